@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/genc-murat/groq-client/pkg/groq"
+	"github.com/genc-murat/groq-client/pkg/groq/bench"
+)
+
+// runBench implements "groq bench".
+func runBench(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	model := fs.String("model", string(groq.ModelLlama31_8bInstant), "model to benchmark")
+	concurrency := fs.Int("concurrency", 4, "number of requests in flight")
+	duration := fs.Duration("duration", 60*time.Second, "how long new requests are started")
+	requests := fs.Int("requests", 0, "stop after this many requests (0 for no limit)")
+	prompt := fs.String("prompt", "", "prompt to send")
+	promptFile := fs.String("prompt-file", "", "file with one prompt per line, sent in rotation")
+	maxTokens := fs.Int("max-tokens", 0, "max_tokens of every request")
+	rateLimit := fs.Int("rate-limit", 0, "client rate limit in requests per minute (0 for the default)")
+	baseURL := fs.String("base-url", groq.DefaultBaseURL, "API base URL")
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	apiKey := os.Getenv("GROQ_API_KEY")
+	if apiKey == "" {
+		return errors.New("GROQ_API_KEY environment variable is required")
+	}
+
+	prompts, err := loadPrompts(*prompt, *promptFile)
+	if err != nil {
+		return err
+	}
+
+	opts := []groq.Option{groq.WithBaseURL(*baseURL)}
+	if *rateLimit > 0 {
+		opts = append(opts, groq.WithRateLimit(*rateLimit))
+	}
+	client := groq.NewClient(apiKey, opts...)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	report, err := bench.Run(ctx, client, bench.Config{
+		Model:       groq.ModelType(*model),
+		Prompts:     prompts,
+		Concurrency: *concurrency,
+		Duration:    *duration,
+		Requests:    *requests,
+		MaxTokens:   *maxTokens,
+	})
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			*bench.Report
+			ErrorRate         float64
+			RequestsPerSecond float64
+			TokensPerSecond   float64
+		}{report, report.ErrorRate(), report.RequestsPerSecond(), report.TokensPerSecond()})
+	}
+	return report.WriteText(out)
+}
+
+// loadPrompts returns the prompt given on the command line or the non-empty lines of the
+// prompt file.
+func loadPrompts(prompt, path string) ([]string, error) {
+	if path == "" {
+		if prompt == "" {
+			return nil, errors.New("set --prompt or --prompt-file")
+		}
+		return []string{prompt}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prompt file: %w", err)
+	}
+
+	var prompts []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			prompts = append(prompts, line)
+		}
+	}
+	if prompt != "" {
+		prompts = append(prompts, prompt)
+	}
+	if len(prompts) == 0 {
+		return nil, fmt.Errorf("prompt file %s is empty", path)
+	}
+	return prompts, nil
+}
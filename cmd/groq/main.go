@@ -0,0 +1,49 @@
+// Command groq is a command line tool for the Groq API.
+//
+// Usage:
+//
+//	groq bench --model llama-3.1-8b-instant --concurrency 8 --prompt-file prompts.txt --duration 60s
+//	groq tools validate tools.yaml
+//
+// The API key is read from the GROQ_API_KEY environment variable.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = `Usage: groq <command> [flags]
+
+Commands:
+  bench    Benchmark chat completions: latency percentiles, tokens/s, errors and cost
+  tools    Validate or list a shared tool catalog (JSON or YAML)
+
+Run "groq <command> -h" for the flags of a command.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "bench":
+		err = runBench(os.Args[2:], os.Stdout)
+	case "tools":
+		err = runTools(os.Args[2:], os.Stdout)
+	case "-h", "--help", "help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "groq:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/genc-murat/groq-client/pkg/groq"
+)
+
+// runTools implements "groq tools validate|list <catalog>".
+func runTools(args []string, out io.Writer) error {
+	if len(args) != 2 || (args[0] != "validate" && args[0] != "list") {
+		return errors.New("usage: groq tools validate|list <catalog.json|catalog.yaml>")
+	}
+
+	registry, err := groq.LoadToolRegistry(args[1])
+	if err != nil {
+		return err
+	}
+
+	names := registry.Names()
+	if args[0] == "validate" {
+		_, err := fmt.Fprintf(out, "%s: %d valid tools\n", args[1], len(names))
+		return err
+	}
+
+	for _, name := range names {
+		f, _ := registry.Get(name)
+		if _, err := fmt.Fprintf(out, "%-24s %s\n", name, f.Description); err != nil {
+			return err
+		}
+	}
+	return nil
+}
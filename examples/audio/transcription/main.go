@@ -36,7 +36,7 @@ func main() {
 		Model:          groq.ModelWhisperLargeV3,
 		Language:       "tr",
 		ResponseFormat: "json",
-		Temperature:    0.3,
+		Temperature:    groq.Float64(0.3),
 	}
 
 	// İsteği gönder
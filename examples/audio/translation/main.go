@@ -36,7 +36,7 @@ func main() {
 		FileName:       "Recording.m4a",
 		Model:          groq.ModelWhisperLargeV3,
 		ResponseFormat: "verbose_json",                               // Detaylı çıktı için
-		Temperature:    0.3,                                          // Daha tutarlı sonuçlar için
+		Temperature:    groq.Float64(0.3),                            // Daha tutarlı sonuçlar için
 		Prompt:         "This is a Turkish speech about technology.", // İsteğe bağlı prompt
 	}
 
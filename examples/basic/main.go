@@ -40,7 +40,7 @@ func main() {
 	req := &groq.ChatCompletionRequest{
 		Model:       model,
 		Messages:    buildMessages("Adana hangi bölgede?"),
-		Temperature: 0.7,
+		Temperature: groq.Float64(0.7),
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -46,7 +46,7 @@ func main() {
 				Content: "Türkiye'nin başkenti neresidir? Detaylı anlat.",
 			},
 		},
-		Temperature: 0.7,
+		Temperature: groq.Float64(0.7),
 	}
 
 	var (
@@ -82,7 +82,9 @@ func printModelInfo(model groq.ModelType) {
 	if info.IsPreview {
 		fmt.Println("⚠️ This is a preview model")
 	}
-	fmt.Printf("- File Size Limit: %s\n", info.MaxFileSize)
+	if info.MaxFileSize > 0 {
+		fmt.Printf("- File Size Limit: %d MB\n", info.MaxFileSize>>20)
+	}
 }
 
 func createStreamHandler(totalTokens *uint64, totalChars *uint64, words map[string]int) groq.StreamHandler {
@@ -14,7 +14,7 @@ func main() {
 
 	// Example 1: Using image URL
 	urlRequest := groq.CreateVisionRequest(
-		groq.ModelLlama32_90bVision,
+		groq.ModelLlama4Scout,
 		"https://i0.wp.com/picjumbo.com/wp-content/uploads/san-francisco-bay-area-beautiful-sunset-evening-cityscape-free-photo.jpg?w=2210&quality=70",
 		"What's in this image?",
 	)
@@ -40,7 +40,7 @@ func main() {
 
 	// Create request with local image
 	localRequest := &groq.ChatCompletionRequest{
-		Model: groq.ModelLlama32_90bVision,
+		Model: groq.ModelLlama4Scout,
 		Messages: []groq.ChatMessage{
 			{
 				Role: "user",
@@ -61,7 +61,7 @@ func main() {
 
 	// Example 3: Multi-turn conversation with image
 	multiTurnRequest := &groq.ChatCompletionRequest{
-		Model: groq.ModelLlama32_90bVision,
+		Model: groq.ModelLlama4Scout,
 		Messages: []groq.ChatMessage{
 			{
 				Role: "user",
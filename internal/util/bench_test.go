@@ -0,0 +1,67 @@
+package util
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// unlimited is a RateLimiter that never waits.
+type unlimited struct{}
+
+func (unlimited) Wait(context.Context) error { return nil }
+
+func newBenchServer(b *testing.B, reply string) *httptest.Server {
+	b.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(reply))
+	}))
+	b.Cleanup(srv.Close)
+	return srv
+}
+
+func BenchmarkDoJSON(b *testing.B) {
+	srv := newBenchServer(b, `{"id":"1","text":"`+strings.Repeat("answer ", 200)+`"}`)
+	client := NewHTTPClient(HTTPClientConfig{RateLimiter: unlimited{}})
+	defer client.Close()
+
+	reqBody := map[string]string{"prompt": strings.Repeat("context ", 500)}
+	var respBody struct {
+		ID   string `json:"id"`
+		Text string `json:"text"`
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := client.DoJSON(context.Background(), "POST", srv.URL, reqBody, &respBody, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDoMultipartForm(b *testing.B) {
+	srv := newBenchServer(b, `{"text":"hello"}`)
+	client := NewHTTPClient(HTTPClientConfig{RateLimiter: unlimited{}})
+	defer client.Close()
+
+	audio := strings.Repeat("a", 32<<10)
+	var respBody struct {
+		Text string `json:"text"`
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		form := map[string]interface{}{
+			"file":     strings.NewReader(audio),
+			"filename": "a.mp3",
+			"model":    "whisper-large-v3",
+			"language": "en",
+		}
+		if err := client.DoMultipartForm(context.Background(), "POST", srv.URL, form, &respBody); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
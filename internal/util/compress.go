@@ -0,0 +1,77 @@
+package util
+
+import (
+	"fmt"
+
+	"github.com/valyala/fasthttp"
+)
+
+// acceptEncoding is sent with every request; responseBody decodes all of its encodings.
+// Requests that set Accept-Encoding themselves, such as "identity", keep their value.
+const acceptEncoding = "gzip, deflate"
+
+// SetRequestCompression gzips the bodies of DoRequest and DoJSON requests of at least
+// minSize bytes and sends them with Content-Encoding: gzip. 0 disables compression, the
+// default. The method is safe for concurrent use.
+func (c *HTTPClient) SetRequestCompression(minSize int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.compressMinSize = minSize
+}
+
+// compressRequest gzips the body of req if it is large enough and not encoded yet.
+func (c *HTTPClient) compressRequest(req *fasthttp.Request) {
+	c.mu.RLock()
+	minSize := c.compressMinSize
+	c.mu.RUnlock()
+
+	body := req.Body()
+	if minSize <= 0 || len(body) < minSize || len(req.Header.ContentEncoding()) > 0 {
+		return
+	}
+	buf := AcquireBuffer()
+	defer ReleaseBuffer(buf)
+	if _, err := fasthttp.WriteGzip(buf, body); err != nil {
+		return
+	}
+	req.SetBody(buf.Bytes())
+	req.Header.SetContentEncoding("gzip")
+}
+
+// responseBody returns a copy of the body of resp, decoded according to its
+// Content-Encoding.
+func responseBody(resp *fasthttp.Response) ([]byte, error) {
+	body, err := uncompressedBody(resp)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Header.ContentEncoding()) == 0 {
+		body = append([]byte(nil), body...)
+	}
+	return body, nil
+}
+
+// uncompressedBody returns the body of resp decoded according to its Content-Encoding. The
+// result shares memory with resp if the body is not encoded.
+func uncompressedBody(resp *fasthttp.Response) ([]byte, error) {
+	if len(resp.Header.ContentEncoding()) == 0 {
+		return resp.Body(), nil
+	}
+	body, err := resp.BodyUncompressed()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s response body: %w", resp.Header.ContentEncoding(), err)
+	}
+	return body, nil
+}
+
+// decodedBody returns the body of resp decoded if possible, or else as received. The
+// result may share memory with resp.
+func decodedBody(resp *fasthttp.Response) []byte {
+	if len(resp.Header.ContentEncoding()) > 0 {
+		if body, err := resp.BodyUncompressed(); err == nil {
+			return body
+		}
+	}
+	return resp.Body()
+}
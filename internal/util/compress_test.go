@@ -0,0 +1,108 @@
+package util
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newGzipServer answers with reply, gzipped if the client accepts it, and records the
+// decoded request body and its Content-Encoding.
+func newGzipServer(t *testing.T, status int, reply string, gotBody, gotEncoding *string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			zr, err := gzip.NewReader(r.Body)
+			require.NoError(t, err)
+			body = zr
+		}
+		data, err := io.ReadAll(body)
+		require.NoError(t, err)
+		*gotBody, *gotEncoding = string(data), r.Header.Get("Content-Encoding")
+
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(reply))
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(status)
+		zw := gzip.NewWriter(w)
+		_, _ = zw.Write([]byte(reply))
+		_ = zw.Close()
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestDoRequest_DecompressesResponses(t *testing.T) {
+	var body, encoding string
+	srv := newGzipServer(t, http.StatusOK, `{"ok":true}`, &body, &encoding)
+
+	for name, transport := range map[string]Transport{"fasthttp": nil, "net/http": NewNetHTTPTransport(nil, time.Minute)} {
+		t.Run(name, func(t *testing.T) {
+			client := NewHTTPClient(HTTPClientConfig{})
+			defer client.Close()
+			client.SetTransport(transport)
+
+			resp, err := client.DoRequest(context.Background(), "POST", srv.URL, []byte("{}"), nil)
+			require.NoError(t, err)
+			assert.Equal(t, `{"ok":true}`, string(resp))
+		})
+	}
+}
+
+func TestDoRequest_DecompressesErrorBodies(t *testing.T) {
+	var body, encoding string
+	srv := newGzipServer(t, http.StatusBadRequest, `{"error":{"message":"bad"}}`, &body, &encoding)
+
+	client := NewHTTPClient(HTTPClientConfig{})
+	defer client.Close()
+
+	_, err := client.DoRequest(context.Background(), "POST", srv.URL, []byte("{}"), nil)
+	var statusErr *StatusError
+	require.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, `{"error":{"message":"bad"}}`, string(statusErr.Body))
+}
+
+func TestDoRequest_IdentityEncoding(t *testing.T) {
+	var body, encoding string
+	srv := newGzipServer(t, http.StatusOK, "plain", &body, &encoding)
+
+	client := NewHTTPClient(HTTPClientConfig{})
+	defer client.Close()
+
+	resp, err := client.DoRequest(context.Background(), "GET", srv.URL, nil, map[string]string{"Accept-Encoding": "identity"})
+	require.NoError(t, err)
+	assert.Equal(t, "plain", string(resp))
+}
+
+func TestSetRequestCompression(t *testing.T) {
+	var body, encoding string
+	srv := newGzipServer(t, http.StatusOK, "{}", &body, &encoding)
+
+	client := NewHTTPClient(HTTPClientConfig{})
+	defer client.Close()
+	client.SetRequestCompression(100)
+
+	large := `{"prompt":"` + strings.Repeat("long context ", 20) + `"}`
+	_, err := client.DoRequest(context.Background(), "POST", srv.URL, []byte(large), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "gzip", encoding)
+	assert.Equal(t, large, body)
+
+	_, err = client.DoRequest(context.Background(), "POST", srv.URL, []byte(`{"prompt":"short"}`), nil)
+	require.NoError(t, err)
+	assert.Empty(t, encoding, "small bodies are sent as they are")
+	assert.Equal(t, `{"prompt":"short"}`, body)
+}
@@ -0,0 +1,182 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// maxDumpBody is the longest body written to a dump; longer bodies are truncated.
+const maxDumpBody = 64 << 10
+
+// redactedHeaders are the headers whose values never appear in a dump.
+var redactedHeaders = map[string]bool{
+	"authorization":       true,
+	"proxy-authorization": true,
+	"x-api-key":           true,
+	"api-key":             true,
+	"cookie":              true,
+	"set-cookie":          true,
+}
+
+// secretPatterns match credentials inside bodies and URLs: Groq API keys and JSON or
+// query parameters named like keys, tokens and secrets.
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`gsk_[A-Za-z0-9]+`),
+	regexp.MustCompile(`(?i)("(?:api_?key|token|secret|password)"\s*:\s*")[^"]*(")`),
+	regexp.MustCompile(`(?i)([?&](?:api_?key|token|key)=)[^&\s]*()`),
+}
+
+// Dumper writes sanitized dumps of HTTP exchanges for debugging: the request and response
+// lines, headers and bodies of every attempt with its timing. Credentials are masked,
+// compressed bodies are shown decoded, and binary or streamed bodies are summarised by
+// size. A Dumper is safe for concurrent use; write errors are ignored so that debugging
+// never fails a request.
+type Dumper struct {
+	w   io.Writer // Destination of all dumps, or nil to write files into dir
+	dir string
+	seq int
+	mu  sync.Mutex
+}
+
+// NewDumper creates a Dumper writing every exchange to w, separated by a header line.
+func NewDumper(w io.Writer) *Dumper {
+	return &Dumper{w: w}
+}
+
+// NewDirDumper creates a Dumper writing every exchange to its own file in dir, which is
+// created if needed. Files are readable by the owner only.
+func NewDirDumper(dir string) *Dumper {
+	return &Dumper{dir: dir}
+}
+
+// SetDumper sets the Dumper receiving every attempt; nil stops dumping.
+// The method is safe for concurrent use.
+func (c *HTTPClient) SetDumper(d *Dumper) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.dumper = d
+}
+
+// getDumper returns the current Dumper under the read lock.
+func (c *HTTPClient) getDumper() *Dumper {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.dumper
+}
+
+// dump writes one attempt. resp is ignored if err is not nil.
+func (d *Dumper) dump(req *fasthttp.Request, resp *fasthttp.Response, err error, attempt int, started time.Time, latency time.Duration) {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "=== %s attempt %d (%v)\n", started.UTC().Format(time.RFC3339Nano), attempt+1, latency.Round(time.Microsecond))
+	fmt.Fprintf(&buf, "%s %s\n", req.Header.Method(), redact(string(req.URI().FullURI())))
+	req.Header.VisitAll(func(key, value []byte) {
+		writeDumpHeader(&buf, key, value)
+	})
+	buf.WriteByte('\n')
+	if req.IsBodyStream() {
+		fmt.Fprintf(&buf, "[streamed body, %d bytes]\n", max(req.Header.ContentLength(), 0))
+	} else {
+		body := req.Body()
+		if decoded, err := req.BodyUncompressed(); err == nil {
+			body = decoded
+		}
+		writeDumpBody(&buf, req.Header.ContentType(), body)
+	}
+
+	if err != nil {
+		fmt.Fprintf(&buf, "\n--- error: %s\n\n", redact(err.Error()))
+	} else {
+		fmt.Fprintf(&buf, "\n--- %d %s\n", resp.StatusCode(), fasthttp.StatusMessage(resp.StatusCode()))
+		resp.Header.VisitAll(func(key, value []byte) {
+			writeDumpHeader(&buf, key, value)
+		})
+		buf.WriteByte('\n')
+		if streamingBody(resp) {
+			buf.WriteString("[streamed body]\n")
+		} else {
+			writeDumpBody(&buf, resp.Header.ContentType(), decodedBody(resp))
+			buf.WriteByte('\n')
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.seq++
+	if d.w != nil {
+		_, _ = d.w.Write(buf.Bytes())
+		return
+	}
+	if err := os.MkdirAll(d.dir, 0o700); err != nil {
+		return
+	}
+	name := fmt.Sprintf("%s-%04d.http", started.UTC().Format("20060102T150405.000"), d.seq)
+	_ = os.WriteFile(filepath.Join(d.dir, name), buf.Bytes(), 0o600)
+}
+
+func writeDumpHeader(buf *bytes.Buffer, key, value []byte) {
+	v := string(value)
+	if redactedHeaders[strings.ToLower(string(key))] {
+		if scheme, _, ok := strings.Cut(v, " "); ok {
+			v = scheme + " [REDACTED]"
+		} else {
+			v = "[REDACTED]"
+		}
+	} else {
+		v = redact(v)
+	}
+	fmt.Fprintf(buf, "%s: %s\n", key, v)
+}
+
+func writeDumpBody(buf *bytes.Buffer, contentType, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+	if !isTextContent(string(contentType), body) {
+		fmt.Fprintf(buf, "[binary body, %d bytes]\n", len(body))
+		return
+	}
+
+	shown := body
+	if len(shown) > maxDumpBody {
+		shown = shown[:maxDumpBody]
+	}
+	buf.WriteString(redact(string(shown)))
+	if len(body) > len(shown) {
+		fmt.Fprintf(buf, "\n[truncated, %d more bytes]", len(body)-len(shown))
+	}
+	buf.WriteByte('\n')
+}
+
+// isTextContent reports whether a body is worth dumping as text.
+func isTextContent(contentType string, body []byte) bool {
+	switch {
+	case strings.HasPrefix(contentType, "text/"), strings.Contains(contentType, "json"),
+		strings.Contains(contentType, "x-www-form-urlencoded"), strings.Contains(contentType, "event-stream"):
+		return true
+	case contentType == "":
+		return !bytes.ContainsRune(body[:min(len(body), 512)], 0)
+	}
+	return false
+}
+
+// redact masks the credentials matched by secretPatterns.
+func redact(s string) string {
+	s = secretPatterns[0].ReplaceAllString(s, "gsk_[REDACTED]")
+	for _, pattern := range secretPatterns[1:] {
+		s = pattern.ReplaceAllString(s, "${1}[REDACTED]${2}")
+	}
+	return s
+}
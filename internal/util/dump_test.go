@@ -0,0 +1,65 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumper(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=abc")
+		_, _ = w.Write([]byte(`{"ok":true,"token":"t-123"}`))
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	client := NewHTTPClient(HTTPClientConfig{BaseHeaders: map[string]string{"Authorization": "Bearer gsk_secret123"}})
+	client.SetDumper(NewDumper(&buf))
+
+	_, err := client.DoRequest(context.Background(), "POST", srv.URL+"/chat?api_key=k-1", []byte(`{"api_key":"k-2","prompt":"hi gsk_other"}`),
+		map[string]string{"Content-Type": "application/json"})
+	require.NoError(t, err)
+
+	dump := buf.String()
+	assert.Contains(t, dump, "attempt 1")
+	assert.Contains(t, dump, "POST "+srv.URL+"/chat?api_key=[REDACTED]")
+	assert.Contains(t, dump, "Authorization: Bearer [REDACTED]")
+	assert.Contains(t, dump, `{"api_key":"[REDACTED]","prompt":"hi gsk_[REDACTED]"}`)
+	assert.Contains(t, dump, "--- 200 OK")
+	assert.Contains(t, dump, "Set-Cookie: [REDACTED]")
+	assert.Contains(t, dump, `{"ok":true,"token":"[REDACTED]"}`)
+	for _, secret := range []string{"gsk_secret123", "gsk_other", "k-1", "k-2", "t-123", "session=abc"} {
+		assert.NotContains(t, dump, secret)
+	}
+}
+
+func TestDirDumper(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "audio/wav")
+		_, _ = w.Write([]byte("RIFF\x00\x00\x00\x00WAVE"))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir() + "/dumps"
+	client := NewHTTPClient(HTTPClientConfig{})
+	client.SetDumper(NewDirDumper(dir))
+
+	_, err := client.DoRequest(context.Background(), "GET", srv.URL, nil, nil)
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	data, err := os.ReadFile(dir + "/" + entries[0].Name())
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "[binary body, 12 bytes]")
+}
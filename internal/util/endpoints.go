@@ -0,0 +1,125 @@
+package util
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultEndpointCooldown is how long an unreachable endpoint is skipped by default.
+const DefaultEndpointCooldown = 30 * time.Second
+
+// EndpointStatus reports the health of one base URL of an EndpointPool.
+type EndpointStatus struct {
+	BaseURL   string
+	Healthy   bool
+	Failures  int       // Consecutive connection failures
+	LastError string    // Error of the last failure, empty if none
+	RetryAt   time.Time // When an unhealthy endpoint is tried again
+}
+
+// EndpointPool holds several base URLs for the same API, in order of preference, and
+// tracks which of them are reachable. Requests go to the first healthy endpoint; an
+// endpoint that fails to connect is skipped for a cooldown and then tried again. An
+// EndpointPool is safe for concurrent use.
+type EndpointPool struct {
+	endpoints []*EndpointStatus
+	cooldown  time.Duration
+	mu        sync.Mutex
+}
+
+// NewEndpointPool creates a pool of base URLs. Trailing slashes are removed.
+//
+// Parameters:
+//   - baseURLs: The base URLs, most preferred first.
+//   - cooldown: How long a failed endpoint is skipped; 0 uses DefaultEndpointCooldown.
+//
+// Returns:
+//   - *EndpointPool: The pool, with every endpoint considered healthy.
+func NewEndpointPool(baseURLs []string, cooldown time.Duration) *EndpointPool {
+	if cooldown <= 0 {
+		cooldown = DefaultEndpointCooldown
+	}
+
+	p := &EndpointPool{cooldown: cooldown}
+	for _, base := range baseURLs {
+		p.endpoints = append(p.endpoints, &EndpointStatus{BaseURL: strings.TrimRight(base, "/"), Healthy: true})
+	}
+	return p
+}
+
+// Status returns a snapshot of the health of every endpoint, in order of preference.
+func (p *EndpointPool) Status() []EndpointStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	status := make([]EndpointStatus, len(p.endpoints))
+	for i, e := range p.endpoints {
+		status[i] = *e
+	}
+	return status
+}
+
+// split returns the path of a URL below one of the pool's base URLs, or false if the
+// URL belongs to none of them.
+func (p *EndpointPool) split(url string) (string, bool) {
+	for _, e := range p.endpoints {
+		if path, ok := strings.CutPrefix(url, e.BaseURL); ok && (path == "" || path[0] == '/' || path[0] == '?') {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// pick returns the base URL for the next attempt: the first healthy endpoint, or the
+// first whose cooldown has passed, or, if all are cooling down, the one that is due first.
+func (p *EndpointPool) pick(now time.Time) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var due *EndpointStatus
+	for _, e := range p.endpoints {
+		if e.Healthy || !now.Before(e.RetryAt) {
+			return e.BaseURL
+		}
+		if due == nil || e.RetryAt.Before(due.RetryAt) {
+			due = e
+		}
+	}
+	if due == nil {
+		return ""
+	}
+	return due.BaseURL
+}
+
+// markFailure records a connection failure of an endpoint and skips it for the cooldown.
+func (p *EndpointPool) markFailure(base string, err error, now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, e := range p.endpoints {
+		if e.BaseURL == base {
+			e.Healthy = false
+			e.Failures++
+			e.LastError = err.Error()
+			e.RetryAt = now.Add(p.cooldown)
+			return
+		}
+	}
+}
+
+// markSuccess records that an endpoint answered, making it healthy again.
+func (p *EndpointPool) markSuccess(base string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, e := range p.endpoints {
+		if e.BaseURL == base {
+			e.Healthy = true
+			e.Failures = 0
+			e.LastError = ""
+			e.RetryAt = time.Time{}
+			return
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEndpointPool_Cooldown(t *testing.T) {
+	pool := NewEndpointPool([]string{"https://proxy.example/v1/", "https://api.example/v1"}, time.Minute)
+	now := time.Now()
+
+	assert.Equal(t, "https://proxy.example/v1", pool.pick(now))
+
+	pool.markFailure("https://proxy.example/v1", errors.New("connection refused"), now)
+	assert.Equal(t, "https://api.example/v1", pool.pick(now))
+	assert.Equal(t, "https://proxy.example/v1", pool.pick(now.Add(time.Minute)))
+
+	status := pool.Status()
+	assert.False(t, status[0].Healthy)
+	assert.Equal(t, 1, status[0].Failures)
+	assert.Equal(t, "connection refused", status[0].LastError)
+	assert.True(t, status[1].Healthy)
+
+	pool.markFailure("https://api.example/v1", errors.New("timeout"), now.Add(time.Second))
+	assert.Equal(t, "https://proxy.example/v1", pool.pick(now.Add(2*time.Second)), "the endpoint due first is tried when all are down")
+
+	pool.markSuccess("https://proxy.example/v1")
+	assert.True(t, pool.Status()[0].Healthy)
+}
+
+func TestHTTPClient_EndpointFailover(t *testing.T) {
+	dead := httptest.NewServer(http.NotFoundHandler())
+	deadURL := dead.URL + "/v1"
+	dead.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	defer srv.Close()
+
+	var delays []time.Duration
+	client := NewHTTPClient(HTTPClientConfig{
+		MaxRetries:    2,
+		RetryWaitTime: time.Minute,
+		OnRetry: func(attempt int, err error, delay time.Duration) error {
+			delays = append(delays, delay)
+			return nil
+		},
+	})
+	pool := NewEndpointPool([]string{deadURL, srv.URL + "/v1"}, time.Minute)
+	client.SetEndpoints(pool)
+
+	body, err := client.DoRequest(context.Background(), "GET", deadURL+"/models", nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "/v1/models", string(body))
+	assert.Equal(t, []time.Duration{0}, delays)
+
+	// The dead endpoint is skipped during its cooldown.
+	_, err = client.DoRequest(context.Background(), "GET", deadURL+"/models", nil, nil)
+	assert.NoError(t, err)
+	assert.Len(t, delays, 1)
+
+	status := pool.Status()
+	assert.False(t, status[0].Healthy)
+	assert.True(t, status[1].Healthy)
+}
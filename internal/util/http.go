@@ -9,6 +9,9 @@ import (
 	"io"
 	"math/rand"
 	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -20,24 +23,163 @@ var (
 	ErrResponseParsing   = errors.New("response parsing failed")
 	ErrRateLimitExceeded = errors.New("rate limit exceeded")
 	ErrTimeout           = errors.New("request timeout")
+	ErrClientClosed      = errors.New("client closed")
 )
 
+// StatusError is returned for responses with a status code of 400 or above. It keeps the
+// response body so callers can decode the API's error details.
+type StatusError struct {
+	StatusCode int
+	Body       []byte
+	RequestID  string        // Value of the x-request-id response header, if any
+	RetryAfter time.Duration // Value of the Retry-After response header, 0 if absent
+
+	ClientRequestID string // Value of the X-Request-ID request header, if any
+}
+
+// Error returns the status code and, if present, the response body.
+func (e *StatusError) Error() string {
+	if len(e.Body) == 0 {
+		return fmt.Sprintf("%s: status code %d", ErrRequestFailed, e.StatusCode)
+	}
+	return fmt.Sprintf("%s: status code %d, body: %s", ErrRequestFailed, e.StatusCode, e.Body)
+}
+
+// Unwrap allows errors.Is(err, ErrRequestFailed) to match any StatusError.
+func (e *StatusError) Unwrap() error {
+	return ErrRequestFailed
+}
+
+// newStatusError copies the status, body and request IDs of a failed response.
+func newStatusError(req *fasthttp.Request, resp *fasthttp.Response) *StatusError {
+	return &StatusError{
+		StatusCode:      resp.StatusCode(),
+		Body:            append([]byte(nil), decodedBody(resp)...),
+		RequestID:       string(resp.Header.Peek("x-request-id")),
+		RetryAfter:      retryDelay(&resp.Header, time.Now()),
+		ClientRequestID: string(req.Header.Peek(HeaderRequestID)),
+	}
+}
+
+// retryDelay returns how long the server asked the client to wait: the Retry-After
+// header, or else the x-ratelimit-reset-requests or x-ratelimit-reset-tokens header of
+// the exhausted limit. It returns 0 if the response gives no guidance.
+func retryDelay(header *fasthttp.ResponseHeader, now time.Time) time.Duration {
+	if wait := parseRetryAfter(string(header.Peek("Retry-After")), now); wait > 0 {
+		return wait
+	}
+
+	var wait time.Duration
+	for _, limit := range []string{"requests", "tokens"} {
+		if string(header.Peek("x-ratelimit-remaining-"+limit)) != "0" {
+			continue
+		}
+		// Reset times are Go-style durations such as "2m59.56s" or "7.66s".
+		if reset, err := time.ParseDuration(string(header.Peek("x-ratelimit-reset-" + limit))); err == nil && reset > wait {
+			wait = reset
+		}
+	}
+	return wait
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date.
+// It returns 0 for missing, malformed or past values.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds * float64(time.Second))
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
+
+// ErrorHandler converts the StatusError of a failed response into the error returned to
+// the caller, e.g. to decode an API-specific error body.
+type ErrorHandler func(*StatusError) error
+
 type HTTPClient struct {
-	client      *fasthttp.Client
-	rateLimit   *RateLimiter
-	retryConfig *RetryConfig
-	baseHeaders map[string]string
+	client       *fasthttp.Client
+	rateLimit    RateLimiter
+	retryConfig  *RetryConfig
+	baseHeaders  map[string]string
+	errorHandler ErrorHandler
+	attemptHook  AttemptHook
+	retryNotify  RetryNotifyHook
+
+	requestIDGenerator func() string // Generates X-Request-ID headers; nil sends none
+	idempotencyKeys    bool          // Generate Idempotency-Key headers for POST requests
+	dumper             *Dumper
+	transport          Transport     // Sends the attempts; nil uses client
+	timeout            time.Duration // Limit of every attempt, 0 for none
+	compressMinSize    int           // Smallest request body to gzip, 0 to never compress
+
+	endpoints   *EndpointPool
+	keys        *KeyPool // API keys the attempts are spread over, nil to use the base headers
+	ownsLimiter bool     // rateLimit was created by NewHTTPClient and is closed with the client
+	inflight    sync.WaitGroup
+	closed      bool
 	mu          sync.RWMutex
 }
 
+// DefaultMaxRetryAfter is the default of HTTPClientConfig.MaxRetryAfter.
+const DefaultMaxRetryAfter = time.Minute
+
 type HTTPClientConfig struct {
 	MaxRequestTimeout time.Duration
 	RequestsPerSecond int
 	MaxRetries        int
 	RetryWaitTime     time.Duration
 	BaseHeaders       map[string]string
+	OnRetry           RetryHook
+	// MaxRetryAfter is the longest wait requested by Retry-After or rate limit reset
+	// headers that is honoured; longer waits end the retries with the error. Default 60s.
+	MaxRetryAfter time.Duration
+	// MaxRetryElapsed caps the time spent on one call including retries: no retry starts
+	// if it would begin later than this after the first attempt. 0 means no limit.
+	MaxRetryElapsed time.Duration
+	// RateLimiter replaces the built-in AdaptiveRateLimiter, e.g. with a RedisRateLimiter
+	// shared by several replicas. RequestsPerSecond is ignored when it is set.
+	RateLimiter RateLimiter
+}
+
+// AttemptInfo describes one attempt of a request.
+type AttemptInfo struct {
+	Method     string
+	URL        string
+	Attempt    int    // 0 for the first attempt, 1 for the first retry, ...
+	StatusCode int    // 0 if no response was received
+	RequestID  string // The server's x-request-id header
+	Latency    time.Duration
+	Queued     time.Duration // Wait for the rate limiter before the first attempt, 0 on retries
+	Err        error         // Connection error, nil if a response was received
+
+	ClientRequestID string // The X-Request-ID header sent
+
+	RequestBytes  int // Size of the request body, 0 if a streamed body has no known length
+	ResponseBytes int // Size of the response body, 0 if no response was received or it is streamed
 }
 
+// AttemptHook is called after every attempt of a request with the context of the request,
+// e.g. to log or measure it. It must not block.
+type AttemptHook func(ctx context.Context, info AttemptInfo)
+
+// RetryNotifyHook is called with the context of the request before each retry the
+// RetryHook allowed, with the same arguments. It must not block.
+type RetryNotifyHook func(ctx context.Context, attempt int, err error, delay time.Duration)
+
+// RetryHook is called before each retry attempt with the attempt number (starting at 1),
+// the error that caused the retry and the delay before the next attempt.
+// Returning a non-nil error aborts the retry loop with that error.
+type RetryHook func(attempt int, err error, delay time.Duration) error
+
 // NewHTTPClient creates a new instance of HTTPClient with the provided configuration.
 // It sets default values for MaxRequestTimeout, RequestsPerSecond, MaxRetries, and RetryWaitTime
 // if they are not provided in the config. It also initializes base headers if provided.
@@ -60,6 +202,13 @@ func NewHTTPClient(config HTTPClientConfig) *HTTPClient {
 	if config.RetryWaitTime == 0 {
 		config.RetryWaitTime = time.Second
 	}
+	if config.MaxRetryAfter == 0 {
+		config.MaxRetryAfter = DefaultMaxRetryAfter
+	}
+	ownsLimiter := config.RateLimiter == nil
+	if ownsLimiter {
+		config.RateLimiter = NewAdaptiveRateLimiter(config.RequestsPerSecond)
+	}
 
 	baseHeaders := make(map[string]string)
 	if config.BaseHeaders != nil {
@@ -70,20 +219,23 @@ func NewHTTPClient(config HTTPClientConfig) *HTTPClient {
 
 	client := &HTTPClient{
 		client: &fasthttp.Client{
-			ReadTimeout:  config.MaxRequestTimeout,
-			WriteTimeout: config.MaxRequestTimeout,
+			Dial: proxyDialer(ProxyFromEnvironment),
 		},
-		rateLimit: NewRateLimiter(config.RequestsPerSecond),
+		timeout:     config.MaxRequestTimeout,
+		rateLimit:   config.RateLimiter,
+		ownsLimiter: ownsLimiter,
 		retryConfig: &RetryConfig{
-			MaxRetries:    config.MaxRetries,
-			RetryWaitTime: config.RetryWaitTime,
+			MaxRetries:      config.MaxRetries,
+			RetryWaitTime:   config.RetryWaitTime,
+			MaxRetryAfter:   config.MaxRetryAfter,
+			MaxRetryElapsed: config.MaxRetryElapsed,
+			OnRetry:         config.OnRetry,
 		},
-		baseHeaders: baseHeaders,
-		mu:          sync.RWMutex{},
+		baseHeaders:        baseHeaders,
+		requestIDGenerator: NewRequestID,
+		mu:                 sync.RWMutex{},
 	}
 
-	fmt.Printf("Base Headers initialized with: %v\n", baseHeaders)
-
 	return client
 }
 
@@ -109,54 +261,75 @@ func (h *HTTPClient) GetClient() *fasthttp.Client {
 //
 // The function respects rate limiting and retries the request if necessary.
 // It also sets base headers defined in the HTTPClient and additional headers provided in the headers parameter.
+// Responses are requested with gzip or deflate compression and returned decoded.
 func (c *HTTPClient) DoRequest(ctx context.Context, method, url string, body []byte, headers map[string]string) ([]byte, error) {
-	if err := c.rateLimit.Wait(ctx); err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrRateLimitExceeded, err)
+	var respBody []byte
+	err := c.doRequest(ctx, method, url, body, headers, false, func(resp *fasthttp.Response) error {
+		var err error
+		respBody, err = responseBody(resp)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return respBody, nil
+}
+
+// doRequest implements DoRequest, passing the successful response to handle before it is
+// released. With stream set, the response body is handed over while it is still arriving
+// if the transport supports it; see DoJSONStream.
+func (c *HTTPClient) doRequest(ctx context.Context, method, url string, body []byte, headers map[string]string, stream bool, handle func(*fasthttp.Response) error) error {
+	if err := c.begin(); err != nil {
+		return err
+	}
+	defer c.inflight.Done()
+
+	queued, err := c.waitRateLimit(ctx)
+	if err != nil {
+		return err
 	}
 
 	req := fasthttp.AcquireRequest()
 	resp := fasthttp.AcquireResponse()
 	defer fasthttp.ReleaseRequest(req)
 	defer fasthttp.ReleaseResponse(resp)
+	resp.StreamBody = stream
 
 	req.SetRequestURI(url)
 	req.Header.SetMethod(method)
+	req.Header.Set("Accept-Encoding", acceptEncoding)
 
 	c.mu.RLock()
-	fmt.Printf("Setting base headers: %v\n", c.baseHeaders)
 	for k, v := range c.baseHeaders {
 		req.Header.Set(k, v)
 	}
 	c.mu.RUnlock()
 
 	if headers != nil {
-		fmt.Printf("Setting request headers: %v\n", headers)
 		for k, v := range headers {
 			req.Header.Set(k, v)
 		}
 	}
+	applyOverrides(ctx, req)
 
 	if len(body) > 0 {
 		req.SetBody(body)
+		c.compressRequest(req)
 	}
 
-	req.Header.VisitAll(func(key, value []byte) {
-		fmt.Printf("Final Header - %s: %s\n", string(key), string(value))
-	})
+	c.identify(ctx, req)
 
-	err := c.doRequestWithRetry(ctx, req, resp)
+	err = c.doRequestWithRetry(ctx, req, resp, queued, nil)
+	recordRequestIDs(ctx, req, resp)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	if resp.StatusCode() >= 400 {
-		return nil, fmt.Errorf("%w: status code %d", ErrRequestFailed, resp.StatusCode())
+		return c.statusError(req, resp)
 	}
 
-	respBody := make([]byte, len(resp.Body()))
-	copy(respBody, resp.Body())
-
-	return respBody, nil
+	return handle(resp)
 }
 
 // DoJSON sends an HTTP request with a JSON body and decodes the JSON response.
@@ -172,34 +345,96 @@ func (c *HTTPClient) DoRequest(ctx context.Context, method, url string, body []b
 // Returns:
 //   - error: An error if the request fails or the response cannot be parsed.
 func (c *HTTPClient) DoJSON(ctx context.Context, method, url string, reqBody interface{}, respBody interface{}, headers map[string]string) error {
-	var bodyBytes []byte
-	var err error
+	return c.DoJSONFunc(ctx, method, url, reqBody, headers, func(body []byte) error {
+		if respBody == nil {
+			return nil
+		}
+		if err := json.Unmarshal(body, respBody); err != nil {
+			return fmt.Errorf("%w: %v", ErrResponseParsing, err)
+		}
+		return nil
+	})
+}
 
-	if reqBody != nil {
-		bodyBytes, err = json.Marshal(reqBody)
+// DoJSONFunc is like DoJSON but passes the decoded response body to handle instead of
+// unmarshaling it. The body is only valid until handle returns, which saves copying it;
+// the request body is encoded into a pooled buffer. Errors of handle are returned as they
+// are.
+//
+// Parameters:
+//   - ctx: The context for the request.
+//   - method: The HTTP method (e.g., "GET", "POST").
+//   - url: The URL to send the request to.
+//   - reqBody: The request body to be marshaled to JSON. Can be nil.
+//   - headers: Additional headers to include in the request. Can be nil.
+//   - handle: The function reading the response body.
+//
+// Returns:
+//   - error: An error if the request fails, or the error of handle.
+func (c *HTTPClient) DoJSONFunc(ctx context.Context, method, url string, reqBody interface{}, headers map[string]string, handle func(body []byte) error) error {
+	return c.doJSON(ctx, method, url, reqBody, headers, false, func(resp *fasthttp.Response) error {
+		body, err := uncompressedBody(resp)
 		if err != nil {
-			return fmt.Errorf("failed to marshal request body: %w", err)
+			return err
 		}
-	}
+		return handle(body)
+	})
+}
 
+// DoJSONStream is like DoJSONFunc but passes the response body to handle as a reader
+// while it is still arriving, e.g. for server-sent events. The response is requested
+// without compression; transports that cannot stream, and responses encoded anyway, are
+// received in full first. Reading the body is bounded by the timeout of the attempt, not
+// by ctx, so handle should check ctx between reads.
+//
+// Parameters:
+//   - ctx: The context for the request.
+//   - method: The HTTP method (e.g., "GET", "POST").
+//   - url: The URL to send the request to.
+//   - reqBody: The request body to be marshaled to JSON. Can be nil.
+//   - headers: Additional headers to include in the request. Can be nil.
+//   - handle: The function reading the response body, valid until it returns.
+//
+// Returns:
+//   - error: An error if the request fails, or the error of handle.
+func (c *HTTPClient) DoJSONStream(ctx context.Context, method, url string, reqBody interface{}, headers map[string]string, handle func(body io.Reader) error) error {
 	if headers == nil {
 		headers = make(map[string]string)
 	}
+	headers["Accept-Encoding"] = "identity"
 
-	headers["Content-Type"] = "application/json"
+	return c.doJSON(ctx, method, url, reqBody, headers, true, func(resp *fasthttp.Response) error {
+		if resp.IsBodyStream() && len(resp.Header.ContentEncoding()) == 0 {
+			return handle(resp.BodyStream())
+		}
+		body, err := uncompressedBody(resp)
+		if err != nil {
+			return err
+		}
+		return handle(bytes.NewReader(body))
+	})
+}
 
-	respBytes, err := c.DoRequest(ctx, method, url, bodyBytes, headers)
-	if err != nil {
-		return err
+// doJSON implements DoJSONFunc and DoJSONStream.
+func (c *HTTPClient) doJSON(ctx context.Context, method, url string, reqBody interface{}, headers map[string]string, stream bool, handle func(*fasthttp.Response) error) error {
+	var bodyBytes []byte
+	if reqBody != nil {
+		data, buf, err := MarshalJSON(reqBody)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		// The request keeps a copy of the body, so the buffer is free once it is sent.
+		defer ReleaseBuffer(buf)
+		bodyBytes = data
 	}
 
-	if respBody != nil {
-		if err := json.Unmarshal(respBytes, respBody); err != nil {
-			return fmt.Errorf("%w: %v", ErrResponseParsing, err)
-		}
+	if headers == nil {
+		headers = make(map[string]string)
 	}
 
-	return nil
+	headers["Content-Type"] = "application/json"
+
+	return c.doRequest(ctx, method, url, bodyBytes, headers, stream, handle)
 }
 
 // SetBaseHeaders sets the base headers for the HTTP client.
@@ -226,8 +461,6 @@ func (c *HTTPClient) SetBaseHeaders(headers map[string]string) {
 	for k, v := range headers {
 		c.baseHeaders[k] = v
 	}
-
-	fmt.Printf("Base headers updated to: %v\n", c.baseHeaders)
 }
 
 // GetBaseHeaders returns a copy of the base headers of the HTTP client.
@@ -244,9 +477,272 @@ func (c *HTTPClient) GetBaseHeaders() map[string]string {
 	return headers
 }
 
+// SetRetryHook replaces the hook called before each retry attempt.
+// Passing nil removes the hook. The method is safe for concurrent use.
+func (c *HTTPClient) SetRetryHook(hook RetryHook) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.retryConfig.OnRetry = hook
+}
+
+// SetMaxRetryAfter sets the longest server-requested wait that is honoured before retrying.
+// Zero restores DefaultMaxRetryAfter. The method is safe for concurrent use.
+func (c *HTTPClient) SetMaxRetryAfter(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if d == 0 {
+		d = DefaultMaxRetryAfter
+	}
+	c.retryConfig.MaxRetryAfter = d
+}
+
+// SetMaxRetryElapsed sets the time budget of one call including its retries; 0 removes
+// the limit. The method is safe for concurrent use.
+func (c *HTTPClient) SetMaxRetryElapsed(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.retryConfig.MaxRetryElapsed = d
+}
+
+// SetRetries sets how often a failed request is retried, 0 for never, and the wait before
+// the first retry, which grows with every attempt; a zero wait is one second. The method
+// is safe for concurrent use.
+func (c *HTTPClient) SetRetries(maxRetries int, wait time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if wait == 0 {
+		wait = time.Second
+	}
+	c.retryConfig.MaxRetries = maxRetries
+	c.retryConfig.RetryWaitTime = wait
+}
+
+// getRetries returns the retry count and wait under the read lock.
+func (c *HTTPClient) getRetries() (int, time.Duration) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.retryConfig.MaxRetries, c.retryConfig.RetryWaitTime
+}
+
+// SetEndpoints makes requests to any base URL of the pool fail over between its endpoints
+// when one cannot be reached. Passing nil sends every request to its own URL. The method
+// is safe for concurrent use.
+func (c *HTTPClient) SetEndpoints(pool *EndpointPool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.endpoints = pool
+}
+
+// Close stops accepting requests, waits for the requests in flight to finish, stops the
+// refill goroutine of the built-in rate limiter and closes idle connections. Rate limiters
+// supplied by the caller are left open, as they may be shared. Requests made after Close
+// fail with ErrClientClosed. Close is idempotent.
+//
+// Returns:
+//   - error: Always nil; the signature matches io.Closer.
+func (c *HTTPClient) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	c.mu.Unlock()
+
+	c.inflight.Wait()
+	c.getTransport().CloseIdleConnections()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closeOwnedLimiter()
+	return nil
+}
+
+// closeOwnedLimiter stops the rate limiter created by NewHTTPClient. The caller must hold c.mu.
+func (c *HTTPClient) closeOwnedLimiter() {
+	if closer, ok := c.rateLimit.(io.Closer); ok && c.ownsLimiter {
+		_ = closer.Close()
+	}
+	c.ownsLimiter = false
+}
+
+// begin registers a request in flight, or returns ErrClientClosed after Close.
+// The caller must call c.inflight.Done when the request is finished.
+func (c *HTTPClient) begin() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return ErrClientClosed
+	}
+	c.inflight.Add(1)
+	return nil
+}
+
+// SetRateLimiter replaces the rate limiter of the client, e.g. with a RedisRateLimiter
+// shared by several replicas. The method is safe for concurrent use.
+func (c *HTTPClient) SetRateLimiter(limiter RateLimiter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closeOwnedLimiter()
+	c.rateLimit = limiter
+}
+
+// SetRequestsPerSecond changes the rate of the limiter created by NewHTTPClient while
+// requests are running. It returns false, changing nothing, if the client uses a rate
+// limiter of its own. The method is safe for concurrent use.
+func (c *HTTPClient) SetRequestsPerSecond(requestsPerSecond int) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	limiter, ok := c.rateLimit.(*AdaptiveRateLimiter)
+	if !ok || !c.ownsLimiter {
+		return false
+	}
+	limiter.SetRate(requestsPerSecond)
+	return true
+}
+
+// getRateLimiter returns the current rate limiter under the read lock.
+func (c *HTTPClient) getRateLimiter() RateLimiter {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.rateLimit
+}
+
+// SetErrorHandler sets the handler that converts failed responses into errors.
+// Passing nil returns *StatusError values. The method is safe for concurrent use.
+func (c *HTTPClient) SetErrorHandler(handler ErrorHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.errorHandler = handler
+}
+
+// statusError returns the error for a failed response, passed through the error handler.
+func (c *HTTPClient) statusError(req *fasthttp.Request, resp *fasthttp.Response) error {
+	c.mu.RLock()
+	handler := c.errorHandler
+	c.mu.RUnlock()
+
+	err := newStatusError(req, resp)
+	if handler == nil {
+		return err
+	}
+	return handler(err)
+}
+
+// SetAttemptHook sets the hook called after every attempt; nil removes it.
+// The method is safe for concurrent use.
+func (c *HTTPClient) SetAttemptHook(hook AttemptHook) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.attemptHook = hook
+}
+
+// getAttemptHook returns the current attempt hook under the read lock.
+func (c *HTTPClient) getAttemptHook() AttemptHook {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.attemptHook
+}
+
+// SetRetryNotifyHook sets the hook notified with the request's context before every retry;
+// nil removes it. The method is safe for concurrent use.
+func (c *HTTPClient) SetRetryNotifyHook(hook RetryNotifyHook) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.retryNotify = hook
+}
+
+// getRetryNotifyHook returns the current retry notification hook under the read lock.
+func (c *HTTPClient) getRetryNotifyHook() RetryNotifyHook {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.retryNotify
+}
+
+// getRetryHook returns the current retry hook under the read lock.
+func (c *HTTPClient) getRetryHook() RetryHook {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.retryConfig.OnRetry
+}
+
+// getMaxRetryAfter returns the longest honoured server wait under the read lock.
+func (c *HTTPClient) getMaxRetryAfter() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.retryConfig.MaxRetryAfter
+}
+
+// waitRateLimit waits for the rate limiter and returns how long that took.
+func (c *HTTPClient) waitRateLimit(ctx context.Context) (time.Duration, error) {
+	started := time.Now()
+	if err := c.getRateLimiter().Wait(ctx); err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrRateLimitExceeded, err)
+	}
+	return time.Since(started), nil
+}
+
+// retryBudget returns the budget shared through ctx, or a budget for this call alone if
+// MaxRetryElapsed is set, or nil if retries are only limited by MaxRetries.
+func (c *HTTPClient) retryBudget(ctx context.Context) *RetryBudget {
+	if budget := retryBudgetFromContext(ctx); budget != nil {
+		return budget
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.retryConfig.MaxRetryElapsed <= 0 {
+		return nil
+	}
+	return &RetryBudget{MaxElapsed: c.retryConfig.MaxRetryElapsed}
+}
+
+// endpointRoute returns the endpoint pool serving a request and the request's path below
+// the pool's base URLs, or nil if the request does not go through a pool.
+func (c *HTTPClient) endpointRoute(req *fasthttp.Request) (*EndpointPool, string) {
+	c.mu.RLock()
+	pool := c.endpoints
+	c.mu.RUnlock()
+
+	if pool == nil {
+		return nil, ""
+	}
+	path, ok := pool.split(string(req.URI().FullURI()))
+	if !ok {
+		return nil, ""
+	}
+	return pool, path
+}
+
 // doRequestWithRetry sends an HTTP request and retries it upon failure based on the retry configuration.
-// It will retry the request up to MaxRetries times, waiting RetryWaitTime * attempt between each retry.
-// If the context is done before the request succeeds, it returns the context's error.
+// It will retry the request up to MaxRetries times, waiting RetryWaitTime * attempt between each retry,
+// or as long as a retryable response asks for with Retry-After or rate limit reset headers.
+// Requested waits longer than MaxRetryAfter end the retries immediately with the response's error,
+// as does a retry the budget from ContextWithRetryBudget or MaxRetryElapsed does not allow.
+// With an endpoint pool, every attempt goes to the preferred reachable endpoint, and after a
+// connection failure the next endpoint is tried without waiting. With a key pool, every
+// attempt is sent with a key of the pool, and after a 429 response another key that is not
+// rate limited is tried without waiting, unless the context overrides the API key.
+// If the context is done before the request succeeds, during an attempt or while waiting
+// between attempts, it returns the context's error at once; its deadline also bounds
+// every attempt.
 // If the response status code is not retryable, it returns nil.
 // If the maximum number of retries is exceeded, it returns an error indicating the last encountered error.
 //
@@ -255,14 +751,32 @@ func (c *HTTPClient) GetBaseHeaders() map[string]string {
 //	ctx - the context to control cancellation and timeout
 //	req - the HTTP request to be sent
 //	resp - the HTTP response to be populated
+//	queued - how long the request waited for the rate limiter, reported to the attempt hook
+//	prepare - optional, called before every attempt, e.g. to attach a fresh body stream
 //
 // Returns:
 //
 //	error - an error if the request fails after the maximum number of retries or if the context is done
-func (c *HTTPClient) doRequestWithRetry(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
-	var lastErr error
+func (c *HTTPClient) doRequestWithRetry(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response, queued time.Duration, prepare func(*fasthttp.Request) error) error {
+	var (
+		lastErr    error
+		serverWait time.Duration // Delay requested by the last response, 0 if none
+		budget     = c.retryBudget(ctx)
+		failedOver bool // The last attempt failed in a way another endpoint or API key avoids
+		base       string
+
+		endpoints, path  = c.endpointRoute(req)
+		keys             = c.getKeyPool()
+		maxRetries, wait = c.getRetries()
+	)
+	if overridesAPIKey(ctx) {
+		keys = nil
+	}
+	if budget != nil {
+		budget.begin(time.Now())
+	}
 
-	for attempt := 0; attempt <= c.retryConfig.MaxRetries; attempt++ {
+	for attempt := 0; attempt <= maxRetries; attempt++ {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
@@ -270,87 +784,140 @@ func (c *HTTPClient) doRequestWithRetry(ctx context.Context, req *fasthttp.Reque
 		}
 
 		if attempt > 0 {
-			time.Sleep(c.retryConfig.RetryWaitTime * time.Duration(attempt))
+			delay := wait * time.Duration(attempt)
+			if serverWait > 0 {
+				if limit := c.getMaxRetryAfter(); serverWait > limit {
+					return fmt.Errorf("retry aborted: server asked to wait %v, longer than %v: %w", serverWait, limit, lastErr)
+				}
+				delay = serverWait
+			}
+			if failedOver {
+				delay = 0
+			}
+			if budget != nil {
+				if err := budget.spend(delay, time.Now()); err != nil {
+					return fmt.Errorf("retry aborted: %w: %w", err, lastErr)
+				}
+			}
+			if hook := c.getRetryHook(); hook != nil {
+				if err := hook(attempt, lastErr, delay); err != nil {
+					return fmt.Errorf("retry aborted: %w", err)
+				}
+			}
+			if notify := c.getRetryNotifyHook(); notify != nil {
+				notify(ctx, attempt, lastErr, delay)
+			}
+
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+
+		if prepare != nil {
+			if err := prepare(req); err != nil {
+				if lastErr != nil {
+					return fmt.Errorf("%w (last error: %v)", err, lastErr)
+				}
+				return err
+			}
 		}
 
-		err := c.client.Do(req, resp)
+		if endpoints != nil {
+			base = endpoints.pick(time.Now())
+			req.SetRequestURI(base + path)
+		}
+
+		var key *keyState
+		if keys != nil {
+			if key = keys.acquire(time.Now()); key != nil {
+				req.Header.Set("Authorization", "Bearer "+key.key)
+			}
+		}
+
+		started := time.Now()
+		err := c.attempt(ctx, req, resp)
+		if key != nil {
+			if err == nil {
+				keys.release(key, resp, time.Now())
+			} else {
+				keys.release(key, nil, time.Now())
+			}
+		}
+		if dumper := c.getDumper(); dumper != nil {
+			dumper.dump(req, resp, err, attempt, started, time.Since(started))
+		}
+		if hook := c.getAttemptHook(); hook != nil {
+			info := AttemptInfo{
+				Method:  string(req.Header.Method()),
+				URL:     string(req.URI().FullURI()),
+				Attempt: attempt,
+				Latency: time.Since(started),
+				Err:     err,
+
+				ClientRequestID: string(req.Header.Peek(HeaderRequestID)),
+			}
+			if attempt == 0 {
+				info.Queued = queued
+			}
+			if req.IsBodyStream() {
+				info.RequestBytes = max(req.Header.ContentLength(), 0)
+			} else {
+				info.RequestBytes = len(req.Body())
+			}
+			if err == nil {
+				info.StatusCode = resp.StatusCode()
+				info.RequestID = string(resp.Header.Peek("x-request-id"))
+				if !streamingBody(resp) {
+					info.ResponseBytes = len(resp.Body())
+				}
+			}
+			hook(ctx, info)
+		}
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return err
+		}
 		if err == nil {
+			if observer, ok := c.getRateLimiter().(RateObserver); ok {
+				observer.Observe(&resp.Header, time.Now())
+			}
+			if endpoints != nil {
+				endpoints.markSuccess(base)
+			}
 			if !isRetryableStatusCode(resp.StatusCode()) {
 				return nil
 			}
-			lastErr = fmt.Errorf("received status code %d", resp.StatusCode())
+			lastErr = c.statusError(req, resp)
+			serverWait = retryDelay(&resp.Header, time.Now())
+			failedOver = false
+			if keys != nil && resp.StatusCode() == fasthttp.StatusTooManyRequests && keys.available(time.Now()) {
+				// Another key is not rate limited, so there is no need to wait.
+				serverWait = 0
+				failedOver = true
+			}
 			continue
 		}
 
 		lastErr = err
+		serverWait = 0
+		if endpoints != nil {
+			endpoints.markFailure(base, err, time.Now())
+			failedOver = endpoints.pick(time.Now()) != base
+		}
 	}
 
 	return fmt.Errorf("max retries exceeded: %w", lastErr)
 }
 
-type RateLimiter struct {
-	ticker *time.Ticker
-	tokens chan struct{}
-}
-
-// NewRateLimiter creates a new RateLimiter that allows a specified number of requests per second.
-// It initializes a ticker that ticks at intervals based on the requestsPerSecond parameter,
-// and a buffered channel to hold the tokens.
-//
-// Parameters:
-//   - requestsPerSecond: The number of requests allowed per second.
-//
-// Returns:
-//   - *RateLimiter: A pointer to the newly created RateLimiter instance.
-func NewRateLimiter(requestsPerSecond int) *RateLimiter {
-	rl := &RateLimiter{
-		ticker: time.NewTicker(time.Second / time.Duration(requestsPerSecond)),
-		tokens: make(chan struct{}, requestsPerSecond),
-	}
-
-	for i := 0; i < requestsPerSecond; i++ {
-		rl.tokens <- struct{}{}
-	}
-
-	go rl.refillTokens()
-
-	return rl
-}
-
-// Wait blocks until a token is available or the context is done.
-// It returns nil if a token is acquired, or an error if the context is done.
-//
-// Parameters:
-//
-//	ctx - The context to use for cancellation.
-//
-// Returns:
-//
-//	error - nil if a token is acquired, or the context's error if it is done.
-func (rl *RateLimiter) Wait(ctx context.Context) error {
-	select {
-	case <-rl.tokens:
-		return nil
-	case <-ctx.Done():
-		return ctx.Err()
-	}
-}
-
-// refillTokens is a method of RateLimiter that continuously refills the token bucket.
-// It listens to a ticker channel and attempts to add a token to the tokens channel
-// whenever the ticker ticks. If the tokens channel is full, it discards the token.
-func (rl *RateLimiter) refillTokens() {
-	for range rl.ticker.C {
-		select {
-		case rl.tokens <- struct{}{}:
-		default:
-		}
-	}
-}
-
 type RetryConfig struct {
-	MaxRetries    int
-	RetryWaitTime time.Duration
+	MaxRetries      int
+	RetryWaitTime   time.Duration
+	MaxRetryAfter   time.Duration // Longest server-requested wait honoured before giving up
+	MaxRetryElapsed time.Duration // Time budget of one call including retries, 0 for no limit
+	OnRetry         RetryHook
 }
 
 // isRetryableStatusCode checks if the given HTTP status code is considered retryable.
@@ -395,44 +962,65 @@ func isRetryableStatusCode(statusCode int) bool {
 //   - ErrResponseParsing for JSON unmarshaling errors
 //   - Other errors for form creation/writing failures
 func (c *HTTPClient) DoMultipartForm(ctx context.Context, method, url string, form map[string]interface{}, respBody interface{}) error {
-	if err := c.rateLimit.Wait(ctx); err != nil {
-		return fmt.Errorf("%w: %v", ErrRateLimitExceeded, err)
+	return c.doMultipartForm(ctx, method, url, form, func(resp *fasthttp.Response) error {
+		body, err := uncompressedBody(resp)
+		if err != nil || respBody == nil {
+			return err
+		}
+		if err := json.Unmarshal(body, respBody); err != nil {
+			return fmt.Errorf("%w: %v", ErrResponseParsing, err)
+		}
+		return nil
+	})
+}
+
+// DoMultipartFormRaw performs an HTTP request with multipart form data and returns
+// the raw response body without attempting to decode it. It is used for endpoints
+// whose response format is selected by the caller (e.g. plain text transcriptions).
+//
+// The file is streamed into the request instead of being buffered, so large uploads
+// do not need to fit in memory. If the file implements io.Seeker, its size is sent as
+// Content-Length and it is rewound for every retry; other readers are sent with chunked
+// encoding and cannot be retried once their data has been consumed.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - method: HTTP method to use (e.g., "POST", "PUT")
+//   - url: Target URL for the request
+//   - form: Map containing form fields and file data, see DoMultipartForm
+//
+// Returns:
+//   - []byte: A copy of the response body
+//   - error: nil if successful, otherwise the same errors as DoMultipartForm
+func (c *HTTPClient) DoMultipartFormRaw(ctx context.Context, method, url string, form map[string]interface{}) ([]byte, error) {
+	var respBody []byte
+	err := c.doMultipartForm(ctx, method, url, form, func(resp *fasthttp.Response) error {
+		var err error
+		respBody, err = responseBody(resp)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
+	return respBody, nil
+}
 
-	var buf bytes.Buffer
-	writer := multipart.NewWriter(&buf)
+// doMultipartForm implements DoMultipartFormRaw, passing the successful response to handle
+// before it is released.
+func (c *HTTPClient) doMultipartForm(ctx context.Context, method, url string, form map[string]interface{}, handle func(*fasthttp.Response) error) error {
+	if err := c.begin(); err != nil {
+		return err
+	}
+	defer c.inflight.Done()
 
-	for key, value := range form {
-		if key == "file" {
-			if reader, ok := value.(io.Reader); ok {
-				if fileName, ok := form["filename"].(string); ok {
-					part, err := writer.CreateFormFile("file", fileName)
-					if err != nil {
-						return fmt.Errorf("error creating form file: %w", err)
-					}
-					if _, err := io.Copy(part, reader); err != nil {
-						return fmt.Errorf("error copying file data: %w", err)
-					}
-				}
-			}
-		} else if key != "filename" {
-			switch v := value.(type) {
-			case []string:
-				for _, item := range v {
-					if err := writer.WriteField(key, item); err != nil {
-						return fmt.Errorf("error writing array field: %w", err)
-					}
-				}
-			default:
-				if err := writer.WriteField(key, fmt.Sprintf("%v", v)); err != nil {
-					return fmt.Errorf("error writing field: %w", err)
-				}
-			}
-		}
+	queued, err := c.waitRateLimit(ctx)
+	if err != nil {
+		return err
 	}
 
-	if err := writer.Close(); err != nil {
-		return fmt.Errorf("error closing multipart writer: %w", err)
+	body, err := newMultipartBody(form)
+	if err != nil {
+		return err
 	}
 
 	req := fasthttp.AcquireRequest()
@@ -442,8 +1030,7 @@ func (c *HTTPClient) DoMultipartForm(ctx context.Context, method, url string, fo
 
 	req.SetRequestURI(url)
 	req.Header.SetMethod(method)
-	req.SetBody(buf.Bytes())
-	req.Header.SetContentType(writer.FormDataContentType())
+	req.Header.Set("Accept-Encoding", acceptEncoding)
 
 	c.mu.RLock()
 	for k, v := range c.baseHeaders {
@@ -453,22 +1040,147 @@ func (c *HTTPClient) DoMultipartForm(ctx context.Context, method, url string, fo
 	}
 	c.mu.RUnlock()
 
-	err := c.doRequestWithRetry(ctx, req, resp)
+	applyOverrides(ctx, req)
+	req.Header.SetContentType(body.contentType)
+
+	c.identify(ctx, req)
+
+	err = c.doRequestWithRetry(ctx, req, resp, queued, body.attach)
+	recordRequestIDs(ctx, req, resp)
 	if err != nil {
+		if !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+			// An abandoned attempt may still be sending the form in the background.
+			body.release()
+		}
 		return err
 	}
+	defer body.release()
 
 	if resp.StatusCode() >= 400 {
-		bodyStr := string(resp.Body())
-		return fmt.Errorf("%w: status code %d, body: %s", ErrRequestFailed, resp.StatusCode(), bodyStr)
+		return c.statusError(req, resp)
 	}
 
-	if respBody != nil {
-		if err := json.Unmarshal(resp.Body(), respBody); err != nil {
-			return fmt.Errorf("%w: %v", ErrResponseParsing, err)
+	return handle(resp)
+}
+
+// multipartBody is a multipart form whose file part is streamed from the caller's reader.
+// The encoded fields and part header form the prefix, the closing boundary the suffix.
+type multipartBody struct {
+	prefix      []byte
+	suffix      []byte
+	file        io.Reader
+	fileStart   int64
+	fileSize    int64 // -1 if the file cannot be measured
+	contentType string
+	attached    bool
+	buf         *bytes.Buffer // Pooled buffer holding prefix and suffix
+}
+
+// release returns the buffer of the form to the pool; the body is unusable afterwards.
+func (b *multipartBody) release() {
+	ReleaseBuffer(b.buf)
+	b.buf, b.prefix, b.suffix = nil, nil, nil
+}
+
+// newMultipartBody encodes the form fields and the file part header of form.
+func newMultipartBody(form map[string]interface{}) (body *multipartBody, err error) {
+	buf := AcquireBuffer()
+	defer func() {
+		if err != nil {
+			ReleaseBuffer(buf)
+		}
+	}()
+	writer := multipart.NewWriter(buf)
+
+	for key, value := range form {
+		if key == "file" || key == "filename" {
+			continue
+		}
+		switch v := value.(type) {
+		case []string:
+			for _, item := range v {
+				if err := writer.WriteField(key, item); err != nil {
+					return nil, fmt.Errorf("error writing array field: %w", err)
+				}
+			}
+		default:
+			if err := writer.WriteField(key, fmt.Sprintf("%v", v)); err != nil {
+				return nil, fmt.Errorf("error writing field: %w", err)
+			}
+		}
+	}
+
+	body = &multipartBody{buf: buf}
+	if reader, ok := form["file"].(io.Reader); ok {
+		if fileName, ok := form["filename"].(string); ok {
+			if _, err := writer.CreateFormFile("file", fileName); err != nil {
+				return nil, fmt.Errorf("error creating form file: %w", err)
+			}
+			body.file = reader
+			body.fileSize = -1
+			if seeker, ok := reader.(io.Seeker); ok {
+				if err := body.measure(seeker); err != nil {
+					return nil, fmt.Errorf("error measuring file data: %w", err)
+				}
+			}
 		}
 	}
 
+	split := buf.Len()
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("error closing multipart writer: %w", err)
+	}
+
+	body.prefix = buf.Bytes()[:split]
+	body.suffix = buf.Bytes()[split:]
+	body.contentType = writer.FormDataContentType()
+	return body, nil
+}
+
+// measure records the current offset and remaining size of a seekable file.
+func (b *multipartBody) measure(seeker io.Seeker) error {
+	start, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	end, err := seeker.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+		return err
+	}
+	b.fileStart = start
+	b.fileSize = end - start
+	return nil
+}
+
+// attach sets the body stream of req for the next attempt, rewinding the file if needed.
+func (b *multipartBody) attach(req *fasthttp.Request) error {
+	if b.attached && b.file != nil {
+		seeker, ok := b.file.(io.Seeker)
+		if !ok || b.fileSize < 0 {
+			return errors.New("multipart body cannot be replayed: file is not seekable")
+		}
+		if _, err := seeker.Seek(b.fileStart, io.SeekStart); err != nil {
+			return fmt.Errorf("error rewinding file data: %w", err)
+		}
+	}
+	b.attached = true
+
+	size := -1
+	if b.fileSize >= 0 {
+		size = len(b.prefix) + int(b.fileSize) + len(b.suffix)
+	}
+
+	readers := []io.Reader{bytes.NewReader(b.prefix)}
+	if b.file != nil {
+		readers = append(readers, b.file)
+	}
+	readers = append(readers, bytes.NewReader(b.suffix))
+
+	// io.MultiReader hides io.Closer, so fasthttp does not close the caller's file.
+	req.SetBodyStream(io.MultiReader(readers...), size)
 	return nil
 }
 
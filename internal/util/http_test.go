@@ -1,6 +1,13 @@
 package util
 
 import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -12,9 +19,8 @@ func TestNewHTTPClient_DefaultConfig(t *testing.T) {
 	client := NewHTTPClient(config)
 
 	assert.NotNil(t, client)
-	assert.Equal(t, 30*time.Second, client.client.ReadTimeout)
-	assert.Equal(t, 30*time.Second, client.client.WriteTimeout)
-	assert.Equal(t, 10, cap(client.rateLimit.tokens))
+	assert.Equal(t, 30*time.Second, client.Timeout())
+	assert.Equal(t, 10, cap(client.rateLimit.(*AdaptiveRateLimiter).tokens))
 	assert.Equal(t, 3, client.retryConfig.MaxRetries)
 	assert.Equal(t, time.Second, client.retryConfig.RetryWaitTime)
 	assert.Empty(t, client.baseHeaders)
@@ -33,9 +39,8 @@ func TestNewHTTPClient_CustomConfig(t *testing.T) {
 	client := NewHTTPClient(config)
 
 	assert.NotNil(t, client)
-	assert.Equal(t, 15*time.Second, client.client.ReadTimeout)
-	assert.Equal(t, 15*time.Second, client.client.WriteTimeout)
-	assert.Equal(t, 20, cap(client.rateLimit.tokens))
+	assert.Equal(t, 15*time.Second, client.Timeout())
+	assert.Equal(t, 20, cap(client.rateLimit.(*AdaptiveRateLimiter).tokens))
 	assert.Equal(t, 5, client.retryConfig.MaxRetries)
 	assert.Equal(t, 2*time.Second, client.retryConfig.RetryWaitTime)
 	assert.Equal(t, map[string]string{"Authorization": "Bearer token"}, client.baseHeaders)
@@ -60,3 +65,360 @@ func TestHTTPClient_GetClient(t *testing.T) {
 	assert.NotNil(t, fastHTTPClient)
 	assert.Equal(t, client.client, fastHTTPClient)
 }
+
+func TestHTTPClient_OnRetryHook(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	var attempts []int
+	abort := errors.New("give up")
+	client := NewHTTPClient(HTTPClientConfig{
+		MaxRetries:    5,
+		RetryWaitTime: time.Millisecond,
+		OnRetry: func(attempt int, err error, delay time.Duration) error {
+			attempts = append(attempts, attempt)
+			assert.Error(t, err)
+			assert.Equal(t, time.Duration(attempt)*time.Millisecond, delay)
+			if attempt == 2 {
+				return abort
+			}
+			return nil
+		},
+	})
+
+	_, err := client.DoRequest(context.Background(), "GET", srv.URL, nil, nil)
+
+	assert.ErrorIs(t, err, abort)
+	assert.Equal(t, []int{1, 2}, attempts)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestHTTPClient_AttemptHook(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-request-id", "req_1")
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	var attempts []AttemptInfo
+	client := NewHTTPClient(HTTPClientConfig{MaxRetries: 2, RetryWaitTime: time.Millisecond})
+	client.SetAttemptHook(func(ctx context.Context, info AttemptInfo) {
+		attempts = append(attempts, info)
+	})
+
+	_, err := client.DoRequest(context.Background(), "GET", srv.URL, nil, nil)
+
+	assert.NoError(t, err)
+	if assert.Len(t, attempts, 2) {
+		assert.Equal(t, 0, attempts[0].Attempt)
+		assert.Equal(t, http.StatusServiceUnavailable, attempts[0].StatusCode)
+		assert.Equal(t, 1, attempts[1].Attempt)
+		assert.Equal(t, http.StatusOK, attempts[1].StatusCode)
+		assert.Equal(t, "req_1", attempts[1].RequestID)
+		assert.Equal(t, "GET", attempts[1].Method)
+	}
+}
+
+func TestHTTPClient_RetryNotifyHook(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	type key struct{}
+	var notified []int
+	client := NewHTTPClient(HTTPClientConfig{MaxRetries: 2, RetryWaitTime: time.Millisecond})
+	client.SetRetryNotifyHook(func(ctx context.Context, attempt int, err error, delay time.Duration) {
+		assert.Equal(t, "call", ctx.Value(key{}))
+		assert.Error(t, err)
+		notified = append(notified, attempt)
+	})
+
+	ctx := context.WithValue(context.Background(), key{}, "call")
+	_, err := client.DoRequest(ctx, "POST", srv.URL, []byte("payload"), nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, []int{1, 2}, notified)
+}
+
+func TestHTTPClient_CancelDuringRetryWait(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	client := NewHTTPClient(HTTPClientConfig{MaxRetries: 3, RetryWaitTime: time.Minute})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.DoRequest(ctx, "GET", srv.URL, nil, nil)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestHTTPClient_CancelledBeforeRequest(t *testing.T) {
+	client := NewHTTPClient(HTTPClientConfig{RequestsPerSecond: 1})
+	_ = client.rateLimit.Wait(context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.DoRequest(ctx, "GET", "http://127.0.0.1:1", nil, nil)
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestHTTPClient_DeadlineInterruptsRequest(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	client := NewHTTPClient(HTTPClientConfig{MaxRetries: 3, RetryWaitTime: time.Millisecond, MaxRequestTimeout: time.Minute})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.DoRequest(ctx, "GET", srv.URL, nil, nil)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
+
+func TestHTTPClient_CancelInterruptsRequest(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	client := NewHTTPClient(HTTPClientConfig{MaxRetries: 3, RetryWaitTime: time.Millisecond, MaxRequestTimeout: time.Minute})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := client.DoMultipartFormRaw(ctx, "POST", srv.URL, map[string]interface{}{"model": "whisper"})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
+
+func TestHTTPClient_MultipartStreamsAndRewinds(t *testing.T) {
+	payload := strings.Repeat("audio-bytes-", 100000)
+
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Positive(t, r.ContentLength)
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		file, _, err := r.FormFile("file")
+		if !assert.NoError(t, err) {
+			return
+		}
+		data, _ := io.ReadAll(file)
+		assert.Equal(t, len(payload), len(data))
+		assert.Equal(t, "whisper-large-v3", r.FormValue("model"))
+
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"text":"ok"}`))
+	}))
+	defer srv.Close()
+
+	client := NewHTTPClient(HTTPClientConfig{MaxRetries: 2, RetryWaitTime: time.Millisecond})
+	body, err := client.DoMultipartFormRaw(context.Background(), "POST", srv.URL, map[string]interface{}{
+		"file":     strings.NewReader(payload),
+		"filename": "a.mp3",
+		"model":    "whisper-large-v3",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"text":"ok"}`, string(body))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestHTTPClient_MultipartNonSeekableNotReplayed(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	client := NewHTTPClient(HTTPClientConfig{MaxRetries: 2, RetryWaitTime: time.Millisecond})
+	_, err := client.DoMultipartFormRaw(context.Background(), "POST", srv.URL, map[string]interface{}{
+		"file":     io.MultiReader(strings.NewReader("data")),
+		"filename": "a.mp3",
+	})
+
+	assert.ErrorContains(t, err, "cannot be replayed")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 7*time.Second, parseRetryAfter("7", now))
+	assert.Equal(t, 1500*time.Millisecond, parseRetryAfter("1.5", now))
+	assert.Equal(t, 30*time.Second, parseRetryAfter("Wed, 01 Jan 2025 12:00:30 GMT", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("Wed, 01 Jan 2025 11:00:00 GMT", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("", now))
+}
+
+func TestHTTPClient_RespectsRetryAfter(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			w.Header().Set("Retry-After", "0.05")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.Header().Set("x-ratelimit-remaining-tokens", "0")
+			w.Header().Set("x-ratelimit-reset-tokens", "30ms")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			_, _ = w.Write([]byte("ok"))
+		}
+	}))
+	defer srv.Close()
+
+	var delays []time.Duration
+	client := NewHTTPClient(HTTPClientConfig{
+		MaxRetries:    3,
+		RetryWaitTime: time.Minute,
+		OnRetry: func(attempt int, err error, delay time.Duration) error {
+			delays = append(delays, delay)
+			return nil
+		},
+	})
+	body, err := client.DoRequest(context.Background(), "GET", srv.URL, nil, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", string(body))
+	assert.Equal(t, []time.Duration{50 * time.Millisecond, 30 * time.Millisecond}, delays)
+}
+
+func TestHTTPClient_RetryAfterTooLong(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	client := NewHTTPClient(HTTPClientConfig{MaxRetries: 3, RetryWaitTime: time.Millisecond})
+	_, err := client.DoRequest(context.Background(), "GET", srv.URL, nil, nil)
+
+	var statusErr *StatusError
+	assert.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, time.Hour, statusErr.RetryAfter)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestHTTPClient_MaxRetryElapsed(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	client := NewHTTPClient(HTTPClientConfig{
+		MaxRetries:      10,
+		RetryWaitTime:   40 * time.Millisecond,
+		MaxRetryElapsed: 100 * time.Millisecond,
+	})
+	_, err := client.DoRequest(context.Background(), "GET", srv.URL, nil, nil)
+
+	assert.ErrorIs(t, err, ErrRetryBudgetExhausted)
+	var statusErr *StatusError
+	assert.ErrorAs(t, err, &statusErr)
+	// Attempts start at 0ms and 40ms; the next would start at 120ms, past the 100ms budget.
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestHTTPClient_RetryBudgetSharedAcrossRequests(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	client := NewHTTPClient(HTTPClientConfig{MaxRetries: 3, RetryWaitTime: time.Millisecond})
+	ctx := ContextWithRetryBudget(context.Background(), &RetryBudget{MaxRetries: 4})
+
+	_, err := client.DoRequest(ctx, "GET", srv.URL, nil, nil)
+	assert.NotErrorIs(t, err, ErrRetryBudgetExhausted)
+	_, err = client.DoRequest(ctx, "GET", srv.URL, nil, nil)
+	assert.ErrorIs(t, err, ErrRetryBudgetExhausted)
+
+	// 1+3 attempts for the first request, then 1 attempt and 1 retry left for the second.
+	assert.Equal(t, int32(6), atomic.LoadInt32(&calls))
+}
+
+func TestHTTPClient_Close(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	client := NewHTTPClient(HTTPClientConfig{})
+	result := make(chan error, 1)
+	go func() {
+		_, err := client.DoRequest(context.Background(), "GET", srv.URL, nil, nil)
+		result <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	closed := make(chan struct{})
+	go func() {
+		_ = client.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+		t.Fatal("Close returned while a request was in flight")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	assert.NoError(t, <-result)
+	<-closed
+
+	_, err := client.DoRequest(context.Background(), "GET", srv.URL, nil, nil)
+	assert.ErrorIs(t, err, ErrClientClosed)
+	assert.NoError(t, client.Close())
+
+	select {
+	case <-client.rateLimit.(*AdaptiveRateLimiter).done:
+	default:
+		t.Error("expected the rate limiter to be stopped")
+	}
+}
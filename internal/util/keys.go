@@ -0,0 +1,206 @@
+package util
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// DefaultKeyCooldown is how long a rate limited API key is skipped if the response does not
+// say when the limit resets.
+const DefaultKeyCooldown = time.Minute
+
+// KeyBalancing selects how a KeyPool spreads requests over its keys.
+type KeyBalancing int
+
+const (
+	// RoundRobin uses the available keys in turn.
+	RoundRobin KeyBalancing = iota
+	// LeastLoaded uses the available key with the fewest requests in flight, and among
+	// those the one with the most tokens left according to the rate limit headers.
+	LeastLoaded
+)
+
+// KeyStatus reports the state of one API key of a KeyPool.
+type KeyStatus struct {
+	Key               string    // The key masked to its last four characters
+	InFlight          int       // Requests currently sent with the key
+	Requests          int64     // Attempts sent with the key
+	RateLimited       int64     // 429 responses received for the key
+	RemainingRequests int       // From the last x-ratelimit-remaining-requests header, -1 if unknown
+	RemainingTokens   int       // From the last x-ratelimit-remaining-tokens header, -1 if unknown
+	CoolingUntil      time.Time // When a rate limited key is used again, zero if it is available
+}
+
+// keyState is the state of one key of a KeyPool.
+type keyState struct {
+	key    string
+	status KeyStatus
+}
+
+// KeyPool holds several API keys of the same account or of several accounts and spreads
+// the attempts of requests over them. Keys answering 429, or whose rate limit headers
+// report an exhausted limit, are skipped until the limit resets; if all keys are cooling
+// down, the one available first is used. A KeyPool is safe
+// for concurrent use.
+type KeyPool struct {
+	keys      []*keyState
+	balancing KeyBalancing
+	next      int // Index where round robin continues
+	mu        sync.Mutex
+}
+
+// NewKeyPool creates a pool of API keys.
+//
+// Parameters:
+//   - keys: The API keys.
+//   - balancing: How requests are spread over the keys.
+//
+// Returns:
+//   - *KeyPool: The pool, with every key available.
+func NewKeyPool(keys []string, balancing KeyBalancing) *KeyPool {
+	p := &KeyPool{balancing: balancing}
+	for _, key := range keys {
+		p.keys = append(p.keys, &keyState{
+			key: key,
+			status: KeyStatus{
+				Key:               maskKey(key),
+				RemainingRequests: -1,
+				RemainingTokens:   -1,
+			},
+		})
+	}
+	return p
+}
+
+// Status returns a snapshot of the state of every key, in the order they were given.
+func (p *KeyPool) Status() []KeyStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	status := make([]KeyStatus, len(p.keys))
+	for i, k := range p.keys {
+		status[i] = k.status
+	}
+	return status
+}
+
+// SetKeyPool sends every attempt with an API key of pool as bearer token, replacing the
+// Authorization base header; nil uses the base headers again. The method is safe for
+// concurrent use.
+func (c *HTTPClient) SetKeyPool(pool *KeyPool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.keys = pool
+}
+
+// getKeyPool returns the current key pool under the read lock.
+func (c *HTTPClient) getKeyPool() *KeyPool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.keys
+}
+
+// acquire picks the key for the next attempt and counts it as in flight.
+func (p *KeyPool) acquire(now time.Time) *keyState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var picked, due *keyState
+	bestTokens := -1
+	for i := range p.keys {
+		k := p.keys[(p.next+i)%len(p.keys)]
+		if now.Before(k.status.CoolingUntil) {
+			if due == nil || k.status.CoolingUntil.Before(due.status.CoolingUntil) {
+				due = k
+			}
+			continue
+		}
+		if p.balancing == RoundRobin {
+			picked = k
+			break
+		}
+		tokens := k.status.RemainingTokens
+		if tokens < 0 {
+			tokens = math.MaxInt
+		}
+		if picked == nil || k.status.InFlight < picked.status.InFlight ||
+			(k.status.InFlight == picked.status.InFlight && tokens > bestTokens) {
+			picked, bestTokens = k, tokens
+		}
+	}
+	if picked == nil {
+		picked = due
+	}
+	if picked == nil {
+		return nil
+	}
+
+	for i, k := range p.keys {
+		if k == picked {
+			p.next = (i + 1) % len(p.keys)
+		}
+	}
+	picked.status.InFlight++
+	picked.status.Requests++
+	return picked
+}
+
+// release records the outcome of an attempt sent with k; resp is nil if the attempt
+// failed without a response.
+func (p *KeyPool) release(k *keyState, resp *fasthttp.Response, now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	k.status.InFlight--
+	if resp == nil {
+		return
+	}
+	if n, err := strconv.Atoi(string(resp.Header.Peek("x-ratelimit-remaining-requests"))); err == nil {
+		k.status.RemainingRequests = n
+	}
+	if n, err := strconv.Atoi(string(resp.Header.Peek("x-ratelimit-remaining-tokens"))); err == nil {
+		k.status.RemainingTokens = n
+	}
+
+	wait := retryDelay(&resp.Header, now)
+	switch {
+	case resp.StatusCode() == fasthttp.StatusTooManyRequests:
+		k.status.RateLimited++
+		if wait <= 0 {
+			wait = DefaultKeyCooldown
+		}
+		k.status.CoolingUntil = now.Add(wait)
+	case wait > 0 && (k.status.RemainingRequests == 0 || k.status.RemainingTokens == 0):
+		// The key has no headroom left; skip it before it gets a 429.
+		k.status.CoolingUntil = now.Add(wait)
+	default:
+		k.status.CoolingUntil = time.Time{}
+	}
+}
+
+// available reports whether a key is not cooling down.
+func (p *KeyPool) available(now time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, k := range p.keys {
+		if !now.Before(k.status.CoolingUntil) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskKey hides all but the last four characters of an API key.
+func maskKey(key string) string {
+	if len(key) <= 8 {
+		return "****"
+	}
+	return "****" + key[len(key)-4:]
+}
@@ -0,0 +1,118 @@
+package util
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestKeyPool_RoundRobin(t *testing.T) {
+	var mu sync.Mutex
+	var used []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		used = append(used, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+		mu.Unlock()
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	client := NewHTTPClient(HTTPClientConfig{BaseHeaders: map[string]string{"Authorization": "Bearer base"}})
+	defer client.Close()
+	client.SetKeyPool(NewKeyPool([]string{"key-a", "key-b", "key-c"}, RoundRobin))
+
+	for i := 0; i < 4; i++ {
+		_, err := client.DoRequest(context.Background(), "GET", srv.URL, nil, nil)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"key-a", "key-b", "key-c", "key-a"}, used)
+}
+
+func TestKeyPool_SwitchesKeyOn429(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer limited-key-1" {
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("x-ratelimit-remaining-requests", "99")
+		w.Header().Set("x-ratelimit-remaining-tokens", "5000")
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	client := NewHTTPClient(HTTPClientConfig{MaxRetries: 1, RetryWaitTime: time.Hour})
+	defer client.Close()
+	pool := NewKeyPool([]string{"limited-key-1", "spare-key-2"}, RoundRobin)
+	client.SetKeyPool(pool)
+
+	start := time.Now()
+	body, err := client.DoRequest(context.Background(), "GET", srv.URL, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", string(body))
+	assert.Less(t, time.Since(start), time.Second, "the retry with the spare key must not wait")
+
+	status := pool.Status()
+	assert.Equal(t, "****ey-1", status[0].Key)
+	assert.Equal(t, int64(1), status[0].RateLimited)
+	assert.WithinDuration(t, start.Add(30*time.Second), status[0].CoolingUntil, 2*time.Second)
+	assert.Equal(t, 99, status[1].RemainingRequests)
+	assert.Equal(t, 5000, status[1].RemainingTokens)
+	assert.Zero(t, status[1].InFlight)
+
+	// The cooling key is skipped by the next request.
+	_, err = client.DoRequest(context.Background(), "GET", srv.URL, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), pool.Status()[0].Requests)
+}
+
+func TestKeyPool_LeastLoaded(t *testing.T) {
+	pool := NewKeyPool([]string{"key-a", "key-b", "key-c"}, LeastLoaded)
+	now := time.Now()
+
+	a := pool.acquire(now)
+	b := pool.acquire(now)
+	assert.NotEqual(t, a.key, b.key, "a busy key is avoided")
+
+	resp := func(remainingTokens string) *fasthttp.Response {
+		return newResponse(http.StatusOK, map[string]string{"x-ratelimit-remaining-tokens": remainingTokens})
+	}
+	pool.release(a, resp("100"), now)
+	pool.release(b, resp("9000"), now)
+	c := pool.acquire(now)
+	pool.release(c, resp("50"), now)
+
+	assert.Equal(t, b.key, pool.acquire(now).key, "the idle key with the most tokens left wins")
+}
+
+func TestKeyPool_ExhaustedKeyCoolsDown(t *testing.T) {
+	pool := NewKeyPool([]string{"key-a", "key-b"}, RoundRobin)
+	now := time.Now()
+
+	a := pool.acquire(now)
+	pool.release(a, newResponse(http.StatusOK, map[string]string{
+		"x-ratelimit-remaining-requests": "0",
+		"x-ratelimit-reset-requests":     "10s",
+	}), now)
+
+	assert.Equal(t, "key-b", pool.acquire(now).key)
+	assert.Equal(t, "key-b", pool.acquire(now).key, "key-a is skipped until its limit resets")
+	assert.Equal(t, "key-a", pool.acquire(now.Add(11*time.Second)).key)
+}
+
+func newResponse(status int, headers map[string]string) *fasthttp.Response {
+	resp := &fasthttp.Response{}
+	resp.SetStatusCode(status)
+	for k, v := range headers {
+		resp.Header.Set(k, v)
+	}
+	return resp
+}
@@ -0,0 +1,59 @@
+package util
+
+import (
+	"context"
+
+	"github.com/valyala/fasthttp"
+)
+
+// RequestOverrides changes the headers of the requests made with a context, e.g. to send
+// one call with another API key without building a second client.
+type RequestOverrides struct {
+	APIKey  string            // Replaces the base Authorization header and the key pool, empty to keep them
+	Headers map[string]string // Set after the base headers and the headers of the request
+}
+
+type requestOverridesKey struct{}
+
+// ContextWithRequestOverrides returns a context whose requests are sent with the given
+// overrides.
+func ContextWithRequestOverrides(ctx context.Context, overrides *RequestOverrides) context.Context {
+	return context.WithValue(ctx, requestOverridesKey{}, overrides)
+}
+
+// requestOverridesFromContext returns the overrides attached by ContextWithRequestOverrides,
+// or nil.
+func requestOverridesFromContext(ctx context.Context) *RequestOverrides {
+	overrides, _ := ctx.Value(requestOverridesKey{}).(*RequestOverrides)
+	return overrides
+}
+
+// RequestAPIKey returns the API key the requests of ctx are sent with instead of the
+// client's, or "" if they use the client's keys.
+func RequestAPIKey(ctx context.Context) string {
+	if overrides := requestOverridesFromContext(ctx); overrides != nil {
+		return overrides.APIKey
+	}
+	return ""
+}
+
+// overridesAPIKey reports whether the requests of ctx are sent with their own API key,
+// in which case the key pool is not used.
+func overridesAPIKey(ctx context.Context) bool {
+	overrides := requestOverridesFromContext(ctx)
+	return overrides != nil && overrides.APIKey != ""
+}
+
+// applyOverrides sets the headers and the API key of the overrides of ctx on req.
+func applyOverrides(ctx context.Context, req *fasthttp.Request) {
+	overrides := requestOverridesFromContext(ctx)
+	if overrides == nil {
+		return
+	}
+	for k, v := range overrides.Headers {
+		req.Header.Set(k, v)
+	}
+	if overrides.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+overrides.APIKey)
+	}
+}
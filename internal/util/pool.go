@@ -0,0 +1,42 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+)
+
+// maxPooledBuffer is the capacity above which buffers are left to the garbage collector
+// instead of being pooled, so that one huge request does not pin its memory.
+const maxPooledBuffer = 1 << 20
+
+// bufferPool holds the buffers of JSON request bodies and multipart forms.
+var bufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// AcquireBuffer returns an empty buffer from the pool. Return it with ReleaseBuffer once
+// nothing refers to its contents anymore.
+func AcquireBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+// ReleaseBuffer returns buf to the pool.
+func ReleaseBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBuffer {
+		return
+	}
+	buf.Reset()
+	bufferPool.Put(buf)
+}
+
+// MarshalJSON encodes v like json.Marshal into a pooled buffer. The returned bytes are
+// valid until buf is released with ReleaseBuffer.
+func MarshalJSON(v any) (data []byte, buf *bytes.Buffer, err error) {
+	buf = AcquireBuffer()
+	if err := json.NewEncoder(buf).Encode(v); err != nil {
+		ReleaseBuffer(buf)
+		return nil, nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), buf, nil
+}
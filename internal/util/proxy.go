@@ -0,0 +1,222 @@
+package util
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// proxyHandshakeTimeout bounds connecting to a proxy and opening the tunnel through it.
+const proxyHandshakeTimeout = 10 * time.Second
+
+// ProxyFunc returns the proxy to reach addr (host:port) through, or nil to connect directly.
+type ProxyFunc func(addr string) (*url.URL, error)
+
+// ProxyURL returns a ProxyFunc that always uses u; nil connects directly.
+func ProxyURL(u *url.URL) ProxyFunc {
+	return func(string) (*url.URL, error) {
+		return u, nil
+	}
+}
+
+// ProxyFromEnvironment picks the proxy for addr like net/http does: from HTTPS_PROXY for
+// port 443 and HTTP_PROXY otherwise, unless NO_PROXY excludes the host. It is the default
+// of every HTTPClient.
+func ProxyFromEnvironment(addr string) (*url.URL, error) {
+	scheme := "http"
+	if _, port, err := net.SplitHostPort(addr); err == nil && port == "443" {
+		scheme = "https"
+	}
+	return http.ProxyFromEnvironment(&http.Request{URL: &url.URL{Scheme: scheme, Host: addr}})
+}
+
+// SetProxy routes the connections of the fasthttp client through the proxies chosen by
+// proxy: http and https proxies are tunnelled through with CONNECT, socks5 proxies with
+// SOCKS5, both with the credentials of the proxy URL's user info. nil connects directly.
+// Set it before sending requests; transports set with SetTransport are not affected.
+func (c *HTTPClient) SetProxy(proxy ProxyFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if proxy == nil {
+		c.client.Dial = nil
+		return
+	}
+	c.client.Dial = proxyDialer(proxy)
+}
+
+// proxyDialer returns a fasthttp dial function connecting through the proxies of proxy.
+func proxyDialer(proxy ProxyFunc) fasthttp.DialFunc {
+	return func(addr string) (net.Conn, error) {
+		u, err := proxy(addr)
+		if err != nil {
+			return nil, fmt.Errorf("proxy for %s: %w", addr, err)
+		}
+		if u == nil {
+			return fasthttp.Dial(addr)
+		}
+		conn, err := dialProxy(u, addr)
+		if err != nil {
+			return nil, fmt.Errorf("proxy %s: %w", u.Redacted(), err)
+		}
+		return conn, nil
+	}
+}
+
+// dialProxy opens a tunnel to addr through the proxy u.
+func dialProxy(u *url.URL, addr string) (net.Conn, error) {
+	var defaultPort string
+	switch u.Scheme {
+	case "http":
+		defaultPort = "80"
+	case "https":
+		defaultPort = "443"
+	case "socks5", "socks5h":
+		defaultPort = "1080"
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q", u.Scheme)
+	}
+	proxyAddr := u.Host
+	if u.Port() == "" {
+		proxyAddr = net.JoinHostPort(u.Hostname(), defaultPort)
+	}
+
+	conn, err := fasthttp.DialTimeout(proxyAddr, proxyHandshakeTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "https" {
+		conn = tls.Client(conn, &tls.Config{ServerName: u.Hostname()})
+	}
+
+	_ = conn.SetDeadline(time.Now().Add(proxyHandshakeTimeout))
+	if u.Scheme == "socks5" || u.Scheme == "socks5h" {
+		err = socks5Connect(conn, u.User, addr)
+	} else {
+		err = httpConnect(conn, u.User, addr)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Time{})
+	return conn, nil
+}
+
+// httpConnect opens a tunnel with an HTTP CONNECT request.
+func httpConnect(conn net.Conn, user *url.Userinfo, addr string) error {
+	req := "CONNECT " + addr + " HTTP/1.1\r\nHost: " + addr + "\r\n"
+	if user != nil {
+		password, _ := user.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(user.Username() + ":" + password))
+		req += "Proxy-Authorization: Basic " + credentials + "\r\n"
+	}
+	if _, err := io.WriteString(conn, req+"\r\n"); err != nil {
+		return err
+	}
+
+	// The proxy sends nothing after its answer until the tunnel is used, so the reader
+	// buffers no tunnelled bytes. The body of a successful answer is the tunnel itself
+	// and is left unread.
+	resp, err := http.ReadResponse(bufio.NewReader(conn), &http.Request{Method: http.MethodConnect})
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return fmt.Errorf("CONNECT %s: %s", addr, resp.Status)
+	}
+	return nil
+}
+
+// socks5Connect opens a tunnel with the SOCKS5 protocol (RFC 1928), authenticating with
+// a username and password (RFC 1929) if user is set. The proxy resolves the host name.
+func socks5Connect(conn net.Conn, user *url.Userinfo, addr string) error {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return fmt.Errorf("invalid port %q", portStr)
+	}
+	if len(host) > 255 {
+		return errors.New("host name too long for SOCKS5")
+	}
+
+	greeting := []byte{5, 1, 0}
+	if user != nil {
+		greeting = []byte{5, 2, 0, 2}
+	}
+	if _, err := conn.Write(greeting); err != nil {
+		return err
+	}
+	reply := make([]byte, 2)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return err
+	}
+	switch {
+	case reply[0] != 5:
+		return fmt.Errorf("not a SOCKS5 proxy (version %d)", reply[0])
+	case reply[1] == 2 && user != nil:
+		password, _ := user.Password()
+		auth := []byte{1, byte(len(user.Username()))}
+		auth = append(auth, user.Username()...)
+		auth = append(auth, byte(len(password)))
+		auth = append(auth, password...)
+		if _, err := conn.Write(auth); err != nil {
+			return err
+		}
+		if _, err := io.ReadFull(conn, reply); err != nil {
+			return err
+		}
+		if reply[1] != 0 {
+			return errors.New("SOCKS5 authentication failed")
+		}
+	case reply[1] != 0:
+		return fmt.Errorf("SOCKS5 proxy requires an unsupported authentication method %d", reply[1])
+	}
+
+	req := []byte{5, 1, 0, 3, byte(len(host))}
+	req = append(req, host...)
+	req = binary.BigEndian.AppendUint16(req, uint16(port))
+	if _, err := conn.Write(req); err != nil {
+		return err
+	}
+
+	head := make([]byte, 4)
+	if _, err := io.ReadFull(conn, head); err != nil {
+		return err
+	}
+	if head[1] != 0 {
+		return fmt.Errorf("SOCKS5 connect to %s failed with code %d", addr, head[1])
+	}
+	var skip int // Bound address and port, which are not needed
+	switch head[3] {
+	case 1:
+		skip = net.IPv4len + 2
+	case 4:
+		skip = net.IPv6len + 2
+	case 3:
+		n := make([]byte, 1)
+		if _, err := io.ReadFull(conn, n); err != nil {
+			return err
+		}
+		skip = int(n[0]) + 2
+	default:
+		return fmt.Errorf("SOCKS5 reply has unknown address type %d", head[3])
+	}
+	_, err = io.ReadFull(conn, make([]byte, skip))
+	return err
+}
@@ -0,0 +1,181 @@
+package util
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newConnectProxy starts an HTTP proxy that tunnels CONNECT requests carrying auth.
+func newConnectProxy(t *testing.T, auth string, tunnels *int32) *httptest.Server {
+	t.Helper()
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			http.Error(w, "CONNECT only", http.StatusMethodNotAllowed)
+			return
+		}
+		if r.Header.Get("Proxy-Authorization") != auth {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		target, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		atomic.AddInt32(tunnels, 1)
+		w.WriteHeader(http.StatusOK)
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			target.Close()
+			return
+		}
+		pipe(conn, target)
+	}))
+	t.Cleanup(proxy.Close)
+	return proxy
+}
+
+func pipe(a, b net.Conn) {
+	go func() {
+		_, _ = io.Copy(a, b)
+		a.Close()
+	}()
+	go func() {
+		_, _ = io.Copy(b, a)
+		b.Close()
+	}()
+}
+
+func newTargetServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("through"))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestSetProxy_HTTPConnect(t *testing.T) {
+	target := newTargetServer(t)
+	var tunnels int32
+	proxy := newConnectProxy(t, "Basic dXNlcjpwYXNz", &tunnels)
+
+	proxyURL, _ := url.Parse(proxy.URL)
+	proxyURL.User = url.UserPassword("user", "pass")
+	client := NewHTTPClient(HTTPClientConfig{MaxRetries: 1, RetryWaitTime: time.Millisecond})
+	defer client.Close()
+	client.SetProxy(ProxyURL(proxyURL))
+
+	body, err := client.DoRequest(context.Background(), "GET", target.URL, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "through", string(body))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&tunnels))
+}
+
+func TestSetProxy_RejectedConnect(t *testing.T) {
+	target := newTargetServer(t)
+	var tunnels int32
+	proxy := newConnectProxy(t, "Basic secret", &tunnels)
+
+	proxyURL, _ := url.Parse(proxy.URL)
+	client := NewHTTPClient(HTTPClientConfig{MaxRetries: 1, RetryWaitTime: time.Millisecond})
+	defer client.Close()
+	client.SetProxy(ProxyURL(proxyURL))
+
+	_, err := client.DoRequest(context.Background(), "GET", target.URL, nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "407")
+	assert.Zero(t, atomic.LoadInt32(&tunnels))
+}
+
+func TestSetProxy_SOCKS5(t *testing.T) {
+	target := newTargetServer(t)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveSOCKS5(conn, "user", "pass")
+		}
+	}()
+
+	client := NewHTTPClient(HTTPClientConfig{MaxRetries: 1, RetryWaitTime: time.Millisecond})
+	defer client.Close()
+	client.SetProxy(ProxyURL(&url.URL{Scheme: "socks5", User: url.UserPassword("user", "pass"), Host: ln.Addr().String()}))
+
+	body, err := client.DoRequest(context.Background(), "GET", target.URL, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "through", string(body))
+}
+
+// serveSOCKS5 handles one SOCKS5 connection requiring username and password auth.
+func serveSOCKS5(conn net.Conn, user, password string) {
+	read := func(n int) []byte {
+		buf := make([]byte, n)
+		if _, err := io.ReadFull(conn, buf); err != nil {
+			return nil
+		}
+		return buf
+	}
+
+	head := read(2)
+	if head == nil || head[0] != 5 {
+		conn.Close()
+		return
+	}
+	read(int(head[1]))
+	_, _ = conn.Write([]byte{5, 2})
+
+	auth := read(2)
+	if auth == nil || auth[0] != 1 {
+		conn.Close()
+		return
+	}
+	gotUser := string(read(int(auth[1])))
+	gotPassword := string(read(int(read(1)[0])))
+	if gotUser != user || gotPassword != password {
+		_, _ = conn.Write([]byte{1, 1})
+		conn.Close()
+		return
+	}
+	_, _ = conn.Write([]byte{1, 0})
+
+	req := read(5)
+	if req == nil || req[1] != 1 || req[3] != 3 {
+		conn.Close()
+		return
+	}
+	host := string(read(int(req[4])))
+	port := binary.BigEndian.Uint16(read(2))
+	target, err := net.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(int(port))))
+	if err != nil {
+		_, _ = conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+		conn.Close()
+		return
+	}
+	_, _ = conn.Write([]byte{5, 0, 0, 1, 127, 0, 0, 1, 0, 0})
+	pipe(conn, target)
+}
+
+func TestProxyFromEnvironment_NoProxyForLoopback(t *testing.T) {
+	u, err := ProxyFromEnvironment("127.0.0.1:8080")
+	require.NoError(t, err)
+	assert.Nil(t, u)
+}
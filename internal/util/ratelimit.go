@@ -0,0 +1,316 @@
+package util
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// adaptiveThreshold is the fraction of a server-side limit below which the limiter
+// starts spacing requests out.
+const adaptiveThreshold = 0.2
+
+// RateLimiter decides when the next request may be sent. Implementations must be safe
+// for concurrent use.
+type RateLimiter interface {
+	// Wait blocks until a request may be sent or the context is done.
+	Wait(ctx context.Context) error
+}
+
+// RateObserver is implemented by rate limiters that adapt to the rate limit headers of
+// the API's responses.
+type RateObserver interface {
+	Observe(header *fasthttp.ResponseHeader, now time.Time)
+}
+
+// AdaptiveRateLimiter paces requests with a token bucket and adapts to the rate limit headers of
+// the responses: as the remaining requests or tokens of the API key fall below 20% of the
+// limit, requests are spread out over the time until the limit resets, and once a limit
+// is exhausted no request starts before it resets. This avoids 429 responses that the
+// fixed bucket alone would run into, e.g. when other clients share the API key.
+type AdaptiveRateLimiter struct {
+	ticker    *time.Ticker
+	tokens    chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+
+	mu       sync.Mutex
+	interval time.Duration // Spacing required by the server's remaining budget, 0 if none
+	next     time.Time     // Earliest start of the next request
+}
+
+// NewAdaptiveRateLimiter creates a new AdaptiveRateLimiter that allows a specified number of requests per second.
+// It initializes a ticker that ticks at intervals based on the requestsPerSecond parameter,
+// and a buffered channel to hold the tokens.
+//
+// Parameters:
+//   - requestsPerSecond: The number of requests allowed per second.
+//
+// Returns:
+//   - *AdaptiveRateLimiter: A pointer to the newly created AdaptiveRateLimiter instance.
+func NewAdaptiveRateLimiter(requestsPerSecond int) *AdaptiveRateLimiter {
+	rl := &AdaptiveRateLimiter{
+		ticker: time.NewTicker(time.Second / time.Duration(requestsPerSecond)),
+		tokens: make(chan struct{}, requestsPerSecond),
+		done:   make(chan struct{}),
+	}
+
+	for i := 0; i < requestsPerSecond; i++ {
+		rl.tokens <- struct{}{}
+	}
+
+	go rl.refillTokens()
+
+	return rl
+}
+
+// Wait blocks until a token is available and the pacing derived from the last observed
+// rate limit headers allows the next request, or the context is done.
+// It returns nil if a token is acquired, or an error if the context is done.
+//
+// Parameters:
+//
+//	ctx - The context to use for cancellation.
+//
+// Returns:
+//
+//	error - nil if a token is acquired, or the context's error if it is done.
+func (rl *AdaptiveRateLimiter) Wait(ctx context.Context) error {
+	select {
+	case <-rl.tokens:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	rl.mu.Lock()
+	now := time.Now()
+	start := now
+	if rl.next.After(start) {
+		start = rl.next
+	}
+	rl.next = start.Add(rl.interval)
+	rl.mu.Unlock()
+
+	wait := start.Sub(now)
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Observe adapts the pacing to the x-ratelimit-* headers of a response. The requests limit
+// is paced exactly, by spreading the remaining requests until the reset; the tokens limit,
+// whose cost per request is unknown, backs off linearly from no delay at 20% remaining to
+// the full reset time at none. Responses without rate limit headers leave the pacing unchanged.
+//
+// Parameters:
+//   - header: The response headers.
+//   - now: The time the response was received.
+func (rl *AdaptiveRateLimiter) Observe(header *fasthttp.ResponseHeader, now time.Time) {
+	var (
+		seen     bool
+		interval time.Duration
+		resume   time.Time
+	)
+	for _, limit := range []string{"requests", "tokens"} {
+		remaining, err := strconv.ParseFloat(string(header.Peek("x-ratelimit-remaining-"+limit)), 64)
+		if err != nil {
+			continue
+		}
+		reset, err := time.ParseDuration(string(header.Peek("x-ratelimit-reset-" + limit)))
+		if err != nil {
+			continue
+		}
+		seen = true
+
+		if remaining <= 0 {
+			if until := now.Add(reset); until.After(resume) {
+				resume = until
+			}
+			continue
+		}
+		total, err := strconv.ParseFloat(string(header.Peek("x-ratelimit-limit-"+limit)), 64)
+		if err != nil || total <= 0 {
+			continue
+		}
+		fraction := remaining / total
+		if fraction >= adaptiveThreshold {
+			continue
+		}
+
+		var spacing time.Duration
+		if limit == "requests" {
+			spacing = time.Duration(float64(reset) / remaining)
+		} else {
+			spacing = time.Duration(float64(reset) * (1 - fraction/adaptiveThreshold))
+		}
+		if spacing > interval {
+			interval = spacing
+		}
+	}
+	if !seen {
+		return
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	rl.interval = interval
+	if resume.After(rl.next) {
+		rl.next = resume
+	}
+}
+
+// SetRate changes how many tokens are added per second, from the next tick on. The
+// bucket keeps the size it was created with, so the burst allowance does not change.
+// It has no effect once the limiter is closed. The method is safe for concurrent use.
+//
+// Parameters:
+//   - requestsPerSecond: The new rate; values below 1 are ignored.
+func (rl *AdaptiveRateLimiter) SetRate(requestsPerSecond int) {
+	if requestsPerSecond < 1 {
+		return
+	}
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	select {
+	case <-rl.done:
+		return
+	default:
+	}
+	rl.ticker.Reset(time.Second / time.Duration(requestsPerSecond))
+}
+
+// Close stops the refill goroutine. Tokens already in the bucket can still be taken, but
+// no new ones are added. Close is idempotent and always returns nil.
+func (rl *AdaptiveRateLimiter) Close() error {
+	rl.closeOnce.Do(func() {
+		rl.mu.Lock()
+		defer rl.mu.Unlock()
+
+		rl.ticker.Stop()
+		close(rl.done)
+	})
+	return nil
+}
+
+// refillTokens is a method of AdaptiveRateLimiter that continuously refills the token bucket.
+// It listens to a ticker channel and attempts to add a token to the tokens channel
+// whenever the ticker ticks. If the tokens channel is full, it discards the token.
+// It returns when the limiter is closed.
+func (rl *AdaptiveRateLimiter) refillTokens() {
+	for {
+		select {
+		case <-rl.ticker.C:
+		case <-rl.done:
+			return
+		}
+		select {
+		case rl.tokens <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// TokenLimiter limits a weighted rate, such as tokens per minute, with a continuously
+// refilled bucket holding one minute of budget. Requests reserve their estimated cost up
+// front and wait until the budget covers it; the estimate can be corrected with Adjust
+// once the actual cost is known. A TokenLimiter is safe for concurrent use.
+type TokenLimiter struct {
+	rate      float64 // Budget refilled per second
+	capacity  float64
+	available float64
+	last      time.Time
+	mu        sync.Mutex
+}
+
+// NewTokenLimiter creates a limiter allowing perMinute units per minute, starting full.
+//
+// Parameters:
+//   - perMinute: The budget per minute; must be positive.
+//
+// Returns:
+//   - *TokenLimiter: The limiter.
+func NewTokenLimiter(perMinute int) *TokenLimiter {
+	return &TokenLimiter{
+		rate:      float64(perMinute) / 60,
+		capacity:  float64(perMinute),
+		available: float64(perMinute),
+		last:      time.Now(),
+	}
+}
+
+// WaitN reserves n units and blocks until the budget covers them or the context is done.
+// Costs larger than the whole budget are capped at it, so they wait for a full bucket
+// instead of forever.
+//
+// Parameters:
+//   - ctx: The context to use for cancellation.
+//   - n: The cost of the request.
+//
+// Returns:
+//   - error: nil once the reservation is covered, or the context's error, in which case
+//     the reservation is released.
+func (l *TokenLimiter) WaitN(ctx context.Context, n int) error {
+	cost := float64(n)
+
+	l.mu.Lock()
+	if cost > l.capacity {
+		cost = l.capacity
+	}
+	l.refill(time.Now())
+	l.available -= cost
+	wait := time.Duration(0)
+	if l.available < 0 {
+		wait = time.Duration(-l.available / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.Adjust(-int(cost))
+		return ctx.Err()
+	}
+}
+
+// Adjust corrects an earlier reservation by delta units: positive values charge more,
+// e.g. when a response used more tokens than estimated, negative values give budget back.
+func (l *TokenLimiter) Adjust(delta int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(time.Now())
+	l.available -= float64(delta)
+	if l.available > l.capacity {
+		l.available = l.capacity
+	}
+}
+
+// refill adds the budget accrued since the last update. The caller must hold l.mu.
+func (l *TokenLimiter) refill(now time.Time) {
+	if elapsed := now.Sub(l.last).Seconds(); elapsed > 0 {
+		l.available += elapsed * l.rate
+		if l.available > l.capacity {
+			l.available = l.capacity
+		}
+		l.last = now
+	}
+}
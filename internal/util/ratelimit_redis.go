@@ -0,0 +1,121 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// RedisEvalFunc runs a Lua script on Redis like the EVAL command and returns its reply.
+// It adapts the Redis client of the caller's choice, which keeps this package free of a
+// Redis dependency. With github.com/redis/go-redis:
+//
+//	eval := func(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+//	    return rdb.Eval(ctx, script, keys, args...).Result()
+//	}
+type RedisEvalFunc func(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error)
+
+// redisTokenBucket takes a token from the bucket stored in KEYS[1], refilled at ARGV[1]
+// tokens per second up to ARGV[2] tokens. It returns 0 if a token was taken, or else the
+// milliseconds until one is available. The Redis clock is used so that replicas with
+// skewed clocks agree.
+const redisTokenBucket = `
+local rate = tonumber(ARGV[1])
+local capacity = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) + tonumber(t[2]) / 1000000
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or capacity
+local ts = tonumber(state[2]) or now
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+local wait = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+else
+  wait = math.ceil((1 - tokens) / rate * 1000)
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.ceil(capacity / rate * 1000) + 1000)
+return wait
+`
+
+// RedisRateLimiter is a RateLimiter whose token bucket lives in Redis, so that several
+// replicas of a service sharing one API key also share one request budget. The bucket
+// holds one minute of requests and is refilled continuously.
+type RedisRateLimiter struct {
+	eval     RedisEvalFunc
+	key      string
+	rate     float64 // Requests per second
+	capacity int
+}
+
+// NewRedisRateLimiter creates a rate limiter that coordinates through Redis.
+//
+// Parameters:
+//   - eval: Runs scripts on the shared Redis server.
+//   - key: The Redis key of the bucket; replicas sharing a budget use the same key.
+//   - requestsPerMinute: The shared budget; must be positive.
+//
+// Returns:
+//   - *RedisRateLimiter: The rate limiter.
+func NewRedisRateLimiter(eval RedisEvalFunc, key string, requestsPerMinute int) *RedisRateLimiter {
+	return &RedisRateLimiter{
+		eval:     eval,
+		key:      key,
+		rate:     float64(requestsPerMinute) / 60,
+		capacity: requestsPerMinute,
+	}
+}
+
+// Wait blocks until the shared bucket grants a request or the context is done.
+//
+// Parameters:
+//
+//	ctx - The context to use for cancellation and for the Redis calls.
+//
+// Returns:
+//
+//	error - nil if a request was granted, the context's error, or an error if Redis fails.
+func (l *RedisRateLimiter) Wait(ctx context.Context) error {
+	for {
+		reply, err := l.eval(ctx, redisTokenBucket, []string{l.key}, strconv.FormatFloat(l.rate, 'f', -1, 64), l.capacity)
+		if err != nil {
+			return fmt.Errorf("redis rate limiter: %w", err)
+		}
+		wait, err := redisInt(reply)
+		if err != nil {
+			return fmt.Errorf("redis rate limiter: %w", err)
+		}
+		if wait <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(time.Duration(wait) * time.Millisecond)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// redisInt converts an integer reply of the Redis clients in common use.
+func redisInt(reply interface{}) (int64, error) {
+	switch v := reply.(type) {
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	case float64:
+		return int64(math.Ceil(v)), nil
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	case []byte:
+		return strconv.ParseInt(string(v), 10, 64)
+	default:
+		return 0, fmt.Errorf("unexpected reply %T", reply)
+	}
+}
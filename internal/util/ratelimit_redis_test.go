@@ -0,0 +1,42 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisRateLimiter(t *testing.T) {
+	replies := []interface{}{int64(30), "0"}
+	var keys []string
+	eval := func(ctx context.Context, script string, k []string, args ...interface{}) (interface{}, error) {
+		keys = append(keys, k...)
+		assert.Equal(t, []interface{}{"1", 60}, args)
+		reply := replies[0]
+		replies = replies[1:]
+		return reply, nil
+	}
+
+	limiter := NewRedisRateLimiter(eval, "groq:ratelimit", 60)
+	start := time.Now()
+	assert.NoError(t, limiter.Wait(context.Background()))
+	assert.GreaterOrEqual(t, time.Since(start), 25*time.Millisecond)
+	assert.Equal(t, []string{"groq:ratelimit", "groq:ratelimit"}, keys)
+}
+
+func TestRedisRateLimiter_Errors(t *testing.T) {
+	failing := NewRedisRateLimiter(func(context.Context, string, []string, ...interface{}) (interface{}, error) {
+		return nil, errors.New("connection refused")
+	}, "k", 60)
+	assert.ErrorContains(t, failing.Wait(context.Background()), "connection refused")
+
+	waiting := NewRedisRateLimiter(func(context.Context, string, []string, ...interface{}) (interface{}, error) {
+		return int64(time.Hour / time.Millisecond), nil
+	}, "k", 60)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, waiting.Wait(ctx), context.DeadlineExceeded)
+}
@@ -0,0 +1,87 @@
+package util
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+)
+
+func rateLimitHeader(values map[string]string) *fasthttp.ResponseHeader {
+	header := &fasthttp.ResponseHeader{}
+	for k, v := range values {
+		header.Set(k, v)
+	}
+	return header
+}
+
+func TestRateLimiter_ObservePacing(t *testing.T) {
+	rl := NewAdaptiveRateLimiter(10)
+	now := time.Now()
+
+	rl.Observe(rateLimitHeader(map[string]string{
+		"x-ratelimit-limit-requests":     "100",
+		"x-ratelimit-remaining-requests": "50",
+		"x-ratelimit-reset-requests":     "1s",
+	}), now)
+	assert.Zero(t, rl.interval, "no pacing well above the threshold")
+
+	rl.Observe(rateLimitHeader(map[string]string{
+		"x-ratelimit-limit-requests":     "100",
+		"x-ratelimit-remaining-requests": "4",
+		"x-ratelimit-reset-requests":     "2s",
+		"x-ratelimit-limit-tokens":       "1000",
+		"x-ratelimit-remaining-tokens":   "100",
+		"x-ratelimit-reset-tokens":       "4s",
+	}), now)
+	// Requests: 2s / 4 remaining; tokens: 10% left is half-way to the full 4s reset.
+	assert.Equal(t, 2*time.Second, rl.interval)
+
+	rl.Observe(&fasthttp.ResponseHeader{}, now)
+	assert.Equal(t, 2*time.Second, rl.interval, "responses without headers keep the pacing")
+}
+
+func TestRateLimiter_WaitsForExhaustedLimit(t *testing.T) {
+	rl := NewAdaptiveRateLimiter(10)
+	rl.Observe(rateLimitHeader(map[string]string{
+		"x-ratelimit-limit-tokens":     "6000",
+		"x-ratelimit-remaining-tokens": "0",
+		"x-ratelimit-reset-tokens":     "80ms",
+	}), time.Now())
+
+	start := time.Now()
+	assert.NoError(t, rl.Wait(context.Background()))
+	assert.GreaterOrEqual(t, time.Since(start), 60*time.Millisecond)
+
+	rl.Observe(rateLimitHeader(map[string]string{
+		"x-ratelimit-remaining-requests": "0",
+		"x-ratelimit-reset-requests":     "1h",
+	}), time.Now())
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, rl.Wait(ctx), context.DeadlineExceeded)
+}
+
+func TestTokenLimiter(t *testing.T) {
+	limiter := NewTokenLimiter(6000) // 100 per second
+
+	start := time.Now()
+	assert.NoError(t, limiter.WaitN(context.Background(), 6000))
+	assert.Less(t, time.Since(start), 50*time.Millisecond, "a full bucket covers its capacity")
+
+	// Refunding part of the estimate makes room for the next request at once.
+	limiter.Adjust(-10)
+	start = time.Now()
+	assert.NoError(t, limiter.WaitN(context.Background(), 10))
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+
+	start = time.Now()
+	assert.NoError(t, limiter.WaitN(context.Background(), 5))
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, limiter.WaitN(ctx, 1000), context.DeadlineExceeded)
+}
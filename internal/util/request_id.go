@@ -0,0 +1,119 @@
+package util
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// Headers identifying a request to the server.
+const (
+	HeaderRequestID      = "X-Request-ID"
+	HeaderIdempotencyKey = "Idempotency-Key"
+)
+
+// RequestIDs identifies one request: the ID the client sent in the X-Request-ID header
+// and the ID the server answered with in its x-request-id header. Support needs the
+// server's ID; the client's ID ties the request to the caller's own logs.
+type RequestIDs struct {
+	Client string
+	Server string
+}
+
+type (
+	requestIDKey      struct{}
+	idempotencyKeyKey struct{}
+	requestIDsKey     struct{}
+)
+
+// NewRequestID returns a random version 4 UUID.
+func NewRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%032x", time.Now().UnixNano())
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	h := hex.EncodeToString(b)
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]
+}
+
+// ContextWithRequestID returns a copy of ctx whose requests are sent with the given
+// X-Request-ID instead of a generated one.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// ContextWithIdempotencyKey returns a copy of ctx whose requests are sent with the given
+// Idempotency-Key header, so the server can recognise a request repeated by the caller.
+func ContextWithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyKey{}, key)
+}
+
+// ContextWithRequestIDs returns a copy of ctx that records the IDs of its requests in ids
+// when they complete. With several requests, ids holds those of the last to complete.
+func ContextWithRequestIDs(ctx context.Context, ids *RequestIDs) context.Context {
+	return context.WithValue(ctx, requestIDsKey{}, ids)
+}
+
+// SetRequestIDGenerator sets the function generating the X-Request-ID of requests that
+// do not carry one; nil stops sending the header. The method is safe for concurrent use.
+func (c *HTTPClient) SetRequestIDGenerator(generate func() string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.requestIDGenerator = generate
+}
+
+// SetIdempotencyKeys sets whether POST requests without an Idempotency-Key from the
+// context get a generated one. The key stays the same across the retries of a request.
+// The method is safe for concurrent use.
+func (c *HTTPClient) SetIdempotencyKeys(enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.idempotencyKeys = enabled
+}
+
+// identify sets the X-Request-ID and Idempotency-Key headers of a request from the
+// context or the client's settings, unless the headers are already set.
+func (c *HTTPClient) identify(ctx context.Context, req *fasthttp.Request) {
+	c.mu.RLock()
+	generate, idempotent := c.requestIDGenerator, c.idempotencyKeys
+	c.mu.RUnlock()
+
+	if len(req.Header.Peek(HeaderRequestID)) == 0 {
+		id, _ := ctx.Value(requestIDKey{}).(string)
+		if id == "" && generate != nil {
+			id = generate()
+		}
+		if id != "" {
+			req.Header.Set(HeaderRequestID, id)
+		}
+	}
+
+	if len(req.Header.Peek(HeaderIdempotencyKey)) == 0 {
+		key, _ := ctx.Value(idempotencyKeyKey{}).(string)
+		if key == "" && idempotent && req.Header.IsPost() {
+			key = NewRequestID()
+		}
+		if key != "" {
+			req.Header.Set(HeaderIdempotencyKey, key)
+		}
+	}
+}
+
+// recordRequestIDs stores the IDs of a completed request in the RequestIDs of the context.
+func recordRequestIDs(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) {
+	ids, _ := ctx.Value(requestIDsKey{}).(*RequestIDs)
+	if ids == nil {
+		return
+	}
+	ids.Client = string(req.Header.Peek(HeaderRequestID))
+	ids.Server = string(resp.Header.Peek("x-request-id"))
+}
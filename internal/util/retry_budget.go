@@ -0,0 +1,66 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var ErrRetryBudgetExhausted = errors.New("retry budget exhausted")
+
+// RetryBudget caps the retries spent on one logical call, which may span several requests,
+// such as a stream and the tool calls that follow it. It bounds the worst-case latency
+// when an endpoint keeps answering with retryable errors. A RetryBudget is safe for
+// concurrent use.
+type RetryBudget struct {
+	MaxRetries int           // Retries allowed in total, 0 for no limit
+	MaxElapsed time.Duration // No retry starts if it would begin later than this after the first attempt, 0 for no limit
+
+	mu      sync.Mutex
+	start   time.Time
+	retries int
+}
+
+type retryBudgetKey struct{}
+
+// ContextWithRetryBudget returns a context whose requests share the given retry budget.
+// It takes precedence over the client's MaxRetryElapsed.
+func ContextWithRetryBudget(ctx context.Context, budget *RetryBudget) context.Context {
+	return context.WithValue(ctx, retryBudgetKey{}, budget)
+}
+
+// retryBudgetFromContext returns the budget attached by ContextWithRetryBudget, or nil.
+func retryBudgetFromContext(ctx context.Context) *RetryBudget {
+	budget, _ := ctx.Value(retryBudgetKey{}).(*RetryBudget)
+	return budget
+}
+
+// begin starts the clock of the budget on the first attempt of the call.
+func (b *RetryBudget) begin(now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.start.IsZero() {
+		b.start = now
+	}
+}
+
+// spend reserves one retry that starts after delay, or returns an error matching
+// ErrRetryBudgetExhausted if the budget does not allow it.
+func (b *RetryBudget) spend(delay time.Duration, now time.Time) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.MaxRetries > 0 && b.retries >= b.MaxRetries {
+		return fmt.Errorf("%w: %d retries used", ErrRetryBudgetExhausted, b.retries)
+	}
+	if b.MaxElapsed > 0 {
+		if elapsed := now.Sub(b.start); elapsed+delay > b.MaxElapsed {
+			return fmt.Errorf("%w: retrying after %v would exceed %v", ErrRetryBudgetExhausted, elapsed.Round(time.Millisecond)+delay, b.MaxElapsed)
+		}
+	}
+	b.retries++
+	return nil
+}
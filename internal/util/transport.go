@@ -0,0 +1,315 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// Transport sends single attempts of the requests of an HTTPClient; retries, rate
+// limiting and failover happen around it. The default uses fasthttp; NetHTTPTransport
+// uses net/http instead.
+type Transport interface {
+	// Do sends req and fills resp, returning the context's error once ctx is done.
+	Do(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error
+	// CloseIdleConnections closes connections that are not in use.
+	CloseIdleConnections()
+}
+
+// SetTransport replaces the transport sending the requests; nil restores the fasthttp
+// client returned by GetClient. The method is safe for concurrent use.
+func (c *HTTPClient) SetTransport(t Transport) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.transport = t
+}
+
+// SetTLSConfig sets the TLS configuration of the fasthttp client's connections; nil uses
+// the defaults. Set it before sending requests; transports set with SetTransport are not
+// affected.
+func (c *HTTPClient) SetTLSConfig(config *tls.Config) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.client.TLSConfig = config
+}
+
+// getTransport returns the current transport under the read lock.
+func (c *HTTPClient) getTransport() Transport {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.transport == nil {
+		return fasthttpTransport{c.client}
+	}
+	return c.transport
+}
+
+// SetTimeout sets the limit of every attempt, which a retry starts afresh; 0 removes it.
+// It takes effect for the next attempt, without rebuilding the client. The method is safe
+// for concurrent use.
+func (c *HTTPClient) SetTimeout(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.timeout = d
+}
+
+// Timeout returns the limit of every attempt, 0 for none.
+func (c *HTTPClient) Timeout() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.timeout
+}
+
+// attempt sends one attempt through the transport within the timeout of the client. An
+// attempt running out of time while ctx is still live fails with ErrTimeout, which is
+// retried, rather than with the context's error, which ends the call.
+func (c *HTTPClient) attempt(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+	transport, timeout := c.getTransport(), c.Timeout()
+	if timeout <= 0 {
+		return transport.Do(ctx, req, resp)
+	}
+	attemptDeadline := time.Now().Add(timeout)
+	if deadline, ok := ctx.Deadline(); ok && !deadline.After(attemptDeadline) {
+		return transport.Do(ctx, req, resp)
+	}
+
+	attemptCtx, cancel := context.WithDeadline(ctx, attemptDeadline)
+	defer cancel()
+	err := transport.Do(attemptCtx, req, resp)
+	if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		return fmt.Errorf("%w after %s", ErrTimeout, timeout)
+	}
+	return err
+}
+
+// fasthttpTransport is the default Transport.
+type fasthttpTransport struct {
+	client *fasthttp.Client
+}
+
+// Do performs one attempt within the limits of ctx: its deadline bounds the attempt, and
+// cancelling it returns the context's error at once. The abandoned attempt then finishes
+// in the background on a copy of req, so req and resp can be released; a streamed body
+// may still be read until the connection gives up.
+func (t fasthttpTransport) Do(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+	deadline, hasDeadline := ctx.Deadline()
+	send := func(req *fasthttp.Request, resp *fasthttp.Response) error {
+		var err error
+		if hasDeadline {
+			err = t.client.DoDeadline(req, resp, deadline)
+		} else {
+			err = t.client.Do(req, resp)
+		}
+		if hasDeadline && errors.Is(err, fasthttp.ErrTimeout) && !time.Now().Before(deadline) {
+			return context.DeadlineExceeded
+		}
+		return err
+	}
+
+	if ctx.Done() == nil {
+		return send(req, resp)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	attemptReq := fasthttp.AcquireRequest()
+	attemptResp := fasthttp.AcquireResponse()
+	req.CopyTo(attemptReq)
+	if req.IsBodyStream() {
+		attemptReq.SetBodyStream(req.BodyStream(), req.Header.ContentLength())
+	}
+	release := func() {
+		fasthttp.ReleaseRequest(attemptReq)
+		fasthttp.ReleaseResponse(attemptResp)
+	}
+
+	attemptResp.StreamBody = resp.StreamBody
+
+	done := make(chan error, 1)
+	go func() {
+		done <- send(attemptReq, attemptResp)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil || !attemptResp.IsBodyStream() {
+			if err == nil {
+				attemptResp.CopyTo(resp)
+			}
+			release()
+			return err
+		}
+		// The body is still arriving: resp reads it from attemptResp, which is released
+		// once resp closes the stream.
+		resp.SetBodyStream(&closingReader{Reader: attemptResp.BodyStream(), close: release}, -1)
+		attemptResp.Header.CopyTo(&resp.Header)
+		return nil
+	case <-ctx.Done():
+		go func() {
+			<-done
+			release()
+		}()
+		return ctx.Err()
+	}
+}
+
+func (t fasthttpTransport) CloseIdleConnections() {
+	t.client.CloseIdleConnections()
+}
+
+// NetHTTPTransport is a Transport built on net/http, for HTTP/2, custom RoundTrippers
+// and proxy setups fasthttp does not support. Cancellation is native to net/http.
+type NetHTTPTransport struct {
+	Client *http.Client
+}
+
+// NewNetHTTPTransport creates a Transport sending requests through rt.
+//
+// Parameters:
+//   - rt: The RoundTripper to use; nil uses http.DefaultTransport.
+//   - timeout: The limit of every attempt, like fasthttp's ReadTimeout; 0 for none.
+//
+// Returns:
+//   - *NetHTTPTransport: The transport.
+func NewNetHTTPTransport(rt http.RoundTripper, timeout time.Duration) *NetHTTPTransport {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	return &NetHTTPTransport{Client: &http.Client{Transport: rt, Timeout: timeout}}
+}
+
+// Do converts req to a net/http request, sends it and copies the answer into resp.
+func (t *NetHTTPTransport) Do(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+	var body io.Reader
+	contentLength := int64(-1)
+	if req.IsBodyStream() {
+		body = req.BodyStream()
+		if n := req.Header.ContentLength(); n >= 0 {
+			contentLength = int64(n)
+		}
+	} else if b := req.Body(); len(b) > 0 {
+		body = bytes.NewReader(b)
+		contentLength = int64(len(b))
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, string(req.Header.Method()), string(req.URI().FullURI()), body)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		httpReq.ContentLength = contentLength
+	}
+	req.Header.VisitAll(func(key, value []byte) {
+		switch k := string(key); http.CanonicalHeaderKey(k) {
+		case "Host", "Content-Length", "Connection":
+		default:
+			httpReq.Header.Add(k, string(value))
+		}
+	})
+
+	stream := resp.StreamBody
+	cancel := func() {}
+	if stream {
+		// The body is read after Do returns, when ctx may already be cancelled, so the
+		// request only follows ctx until the headers arrive and keeps its deadline.
+		var reqCtx context.Context
+		var cancelReq context.CancelFunc
+		if deadline, ok := ctx.Deadline(); ok {
+			reqCtx, cancelReq = context.WithDeadline(context.WithoutCancel(ctx), deadline)
+		} else {
+			reqCtx, cancelReq = context.WithCancel(context.WithoutCancel(ctx))
+		}
+		stop := context.AfterFunc(ctx, cancelReq)
+		defer stop()
+		httpReq = httpReq.WithContext(reqCtx)
+		cancel = cancelReq
+	}
+
+	httpResp, err := t.Client.Do(httpReq)
+	if err != nil {
+		cancel()
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return err
+	}
+
+	if stream && httpResp.StatusCode < 400 {
+		resp.Reset()
+		resp.StreamBody = true
+		resp.SetStatusCode(httpResp.StatusCode)
+		resp.SetBodyStream(&closingReader{Reader: httpResp.Body, close: func() {
+			httpResp.Body.Close()
+			cancel()
+		}}, -1)
+		copyHTTPHeader(resp, httpResp.Header)
+		return nil
+	}
+	defer cancel()
+	defer httpResp.Body.Close()
+
+	data, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return err
+	}
+
+	resp.Reset()
+	resp.StreamBody = stream
+	resp.SetStatusCode(httpResp.StatusCode)
+	copyHTTPHeader(resp, httpResp.Header)
+	resp.SetBody(data)
+	return nil
+}
+
+// copyHTTPHeader adds the net/http header to resp, except for Content-Length, which
+// fasthttp derives from the body.
+func copyHTTPHeader(resp *fasthttp.Response, header http.Header) {
+	for key, values := range header {
+		if key == "Content-Length" {
+			continue
+		}
+		for _, v := range values {
+			resp.Header.Add(key, v)
+		}
+	}
+}
+
+// closingReader is a streamed response body that calls close once when the response
+// closes it, to release what the stream is read from.
+type closingReader struct {
+	io.Reader
+	close func()
+	once  sync.Once
+}
+
+func (r *closingReader) Close() error {
+	r.once.Do(r.close)
+	return nil
+}
+
+// streamingBody reports whether the body of a successful resp is still being streamed,
+// so reading it for logs or dumps would consume it.
+func streamingBody(resp *fasthttp.Response) bool {
+	return resp.IsBodyStream() && resp.StatusCode() < 400
+}
+
+// CloseIdleConnections closes the idle connections of the underlying client.
+func (t *NetHTTPTransport) CloseIdleConnections() {
+	t.Client.CloseIdleConnections()
+}
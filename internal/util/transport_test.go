@@ -0,0 +1,146 @@
+package util
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetHTTPTransport_RetriesAndRewinds(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, _, err := r.FormFile("file")
+		require.NoError(t, err)
+		data, _ := io.ReadAll(file)
+		assert.Equal(t, "audio", string(data))
+
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("x-request-id", "req_1")
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	client := NewHTTPClient(HTTPClientConfig{MaxRetries: 1, RetryWaitTime: time.Millisecond})
+	client.SetTransport(NewNetHTTPTransport(nil, time.Minute))
+	ids := &RequestIDs{}
+
+	body, err := client.DoMultipartFormRaw(ContextWithRequestIDs(context.Background(), ids), "POST", srv.URL, map[string]interface{}{
+		"file":     strings.NewReader("audio"),
+		"filename": "a.mp3",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "ok", string(body))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	assert.Equal(t, "req_1", ids.Server)
+}
+
+func TestNetHTTPTransport_Cancel(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	client := NewHTTPClient(HTTPClientConfig{MaxRetries: 3, RetryWaitTime: time.Millisecond})
+	client.SetTransport(NewNetHTTPTransport(nil, 0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	_, err := client.DoRequest(ctx, "GET", srv.URL, nil, nil)
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestHTTPClient_SetTimeout(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			time.Sleep(200 * time.Millisecond)
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	for name, transport := range map[string]Transport{"fasthttp": nil, "net/http": NewNetHTTPTransport(nil, 0)} {
+		t.Run(name, func(t *testing.T) {
+			atomic.StoreInt32(&calls, 0)
+			client := NewHTTPClient(HTTPClientConfig{MaxRequestTimeout: time.Minute, MaxRetries: 1, RetryWaitTime: time.Millisecond})
+			defer client.Close()
+			client.SetTransport(transport)
+
+			// The first attempt outlives the new timeout and is retried.
+			client.SetTimeout(50 * time.Millisecond)
+			body, err := client.DoRequest(context.Background(), "GET", srv.URL, nil, nil)
+
+			require.NoError(t, err)
+			assert.Equal(t, "ok", string(body))
+			assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+		})
+	}
+}
+
+func TestHTTPClient_DoJSONStream(t *testing.T) {
+	cases := map[string]struct {
+		transport Transport
+		timeout   time.Duration
+	}{
+		"fasthttp":            {nil, time.Minute},
+		"fasthttp no timeout": {nil, 0},
+		"net/http":            {NewNetHTTPTransport(nil, 0), time.Minute},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			next := make(chan struct{})
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "identity", r.Header.Get("Accept-Encoding"))
+				_, _ = w.Write([]byte("first\n"))
+				w.(http.Flusher).Flush()
+				// The second line is only sent once the first was read.
+				select {
+				case <-next:
+				case <-time.After(5 * time.Second):
+				}
+				_, _ = w.Write([]byte("second\n"))
+			}))
+			defer srv.Close()
+
+			client := NewHTTPClient(HTTPClientConfig{MaxRetries: 1, RetryWaitTime: time.Millisecond})
+			defer client.Close()
+			client.SetTransport(tc.transport)
+			client.SetTimeout(tc.timeout)
+
+			started := time.Now()
+			var lines []string
+			err := client.DoJSONStream(context.Background(), "POST", srv.URL, map[string]string{"q": "x"}, nil, func(body io.Reader) error {
+				reader := bufio.NewReader(body)
+				first, err := reader.ReadString('\n')
+				if err != nil {
+					return err
+				}
+				close(next)
+				rest, err := io.ReadAll(reader)
+				lines = append(lines, first, string(rest))
+				return err
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, []string{"first\n", "second\n"}, lines)
+			assert.Less(t, time.Since(started), 2*time.Second, "the body was not streamed")
+		})
+	}
+}
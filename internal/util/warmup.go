@@ -0,0 +1,39 @@
+package util
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/valyala/fasthttp"
+)
+
+// Warmup opens a connection to the host of url and leaves it idle in the pool, so the next
+// request skips the DNS lookup and the TCP and TLS handshakes. It sends a HEAD request
+// without the base headers, retries, rate limiting or hooks; any HTTP response counts as
+// success.
+//
+// Parameters:
+//   - ctx: The context bounding the warmup.
+//   - url: A URL on the host to connect to.
+//
+// Returns:
+//   - error: The error of connecting, or ErrClientClosed.
+func (c *HTTPClient) Warmup(ctx context.Context, url string) error {
+	if err := c.begin(); err != nil {
+		return err
+	}
+	defer c.inflight.Done()
+
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+
+	req.SetRequestURI(url)
+	req.Header.SetMethod(fasthttp.MethodHead)
+
+	if err := c.attempt(ctx, req, resp); err != nil {
+		return fmt.Errorf("warmup of %s: %w", url, err)
+	}
+	return nil
+}
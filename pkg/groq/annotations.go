@@ -0,0 +1,53 @@
+package groq
+
+import (
+	"context"
+)
+
+type annotationsKey struct{}
+
+// ContextWithAnnotations returns a copy of ctx carrying the given key/value annotations,
+// merged over any annotations already in ctx. Annotations describe a request for offline
+// analytics (feature name, experiment ID, tenant) and are never sent to the API.
+//
+// Example usage:
+//
+//	ctx = ContextWithAnnotations(ctx, map[string]string{"feature": "summarize", "experiment": "b"})
+//	resp, err := client.CreateChatCompletion(ctx, req)
+func ContextWithAnnotations(ctx context.Context, annotations map[string]string) context.Context {
+	if len(annotations) == 0 {
+		return ctx
+	}
+
+	existing := AnnotationsFromContext(ctx)
+	merged := make(map[string]string, len(existing)+len(annotations))
+	for k, v := range existing {
+		merged[k] = v
+	}
+	for k, v := range annotations {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, annotationsKey{}, merged)
+}
+
+// AnnotationsFromContext returns the annotations stored in ctx, or nil. The returned
+// map must not be modified.
+func AnnotationsFromContext(ctx context.Context) map[string]string {
+	annotations, _ := ctx.Value(annotationsKey{}).(map[string]string)
+	return annotations
+}
+
+// Annotate sets an annotation on the request. Annotations are not sent to the API; the
+// client adds them to the request's context so they reach cache, guardrails and reports.
+func (r *ChatCompletionRequest) Annotate(key, value string) *ChatCompletionRequest {
+	if r.Annotations == nil {
+		r.Annotations = make(map[string]string)
+	}
+	r.Annotations[key] = value
+	return r
+}
+
+// requestAnnotations returns the annotations of ctx merged with those of req.
+func requestAnnotations(ctx context.Context, req *ChatCompletionRequest) map[string]string {
+	return AnnotationsFromContext(ContextWithAnnotations(ctx, req.Annotations))
+}
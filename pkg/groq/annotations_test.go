@@ -0,0 +1,62 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestAnnotations(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "experiment") {
+			http.Error(w, "annotations leaked", http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"}}]}`))
+	}))
+	defer srv.Close()
+
+	var seen map[string]string
+	client := NewClient("test-key", WithBaseURL(srv.URL), WithGuardrails(&Guardrails{
+		Input: []GuardrailChecker{GuardrailCheckerFunc(func(ctx context.Context, _ GuardrailStage, _ []ChatMessage) error {
+			seen = AnnotationsFromContext(ctx)
+			return nil
+		})},
+	}))
+
+	ctx := ContextWithAnnotations(context.Background(), map[string]string{"feature": "summarize", "experiment": "a"})
+	req := NewRequest(ModelLlama31_8bInstant).User("hi").Annotate("experiment", "b").Build()
+
+	if _, err := client.CreateChatCompletion(ctx, req); err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+	if seen["feature"] != "summarize" || seen["experiment"] != "b" {
+		t.Errorf("unexpected annotations in context: %v", seen)
+	}
+
+	responses := client.CreateParallelCompletions(ctx, []*ChatCompletionRequest{req})
+	if responses[0].Annotations["experiment"] != "b" || responses[0].Annotations["feature"] != "summarize" {
+		t.Errorf("unexpected annotations in parallel response: %v", responses[0].Annotations)
+	}
+}
+
+func TestBatchResultsApplyAnnotations(t *testing.T) {
+	b := NewBatchBuilder().Add("r1", NewRequest(ModelLlama31_8bInstant).User("hi").Annotate("feature", "tagging").Build())
+	results, err := ParseBatchResults(strings.NewReader(`{"custom_id":"r1","response":{"status_code":200,"body":{"choices":[]}}}`))
+	if err != nil {
+		t.Fatalf("ParseBatchResults() error = %v", err)
+	}
+
+	results.ApplyAnnotations(b)
+	if got := results.Results["r1"].Annotations["feature"]; got != "tagging" {
+		t.Errorf("annotation = %q, want tagging", got)
+	}
+	if errors.Is(results.Err(), ErrBatchPartialFailure) {
+		t.Error("unexpected partial failure")
+	}
+}
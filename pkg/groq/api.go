@@ -0,0 +1,35 @@
+package groq
+
+import (
+	"context"
+	"io"
+)
+
+// API is the set of Groq API calls made by *Client. Code that depends on API rather than
+// on *Client can be given a fake in unit tests, which then run without network access.
+//
+// Example usage:
+//
+//	type Summarizer struct {
+//	    Groq groq.API
+//	}
+//
+//	summarizer := &Summarizer{Groq: groq.NewClient(apiKey)}
+type API interface {
+	CreateChatCompletion(ctx context.Context, req *ChatCompletionRequest, opts ...RequestOption) (*ChatCompletionResponse, error)
+	CreateChatCompletionStream(ctx context.Context, req *ChatCompletionRequest, handler StreamHandler, opts ...RequestOption) error
+	CreateFunctionCall(ctx context.Context, req *FunctionCallChatRequest) (*ChatCompletionResponse, error)
+
+	CreateTranscription(ctx context.Context, req *TranscriptionRequest, opts ...RequestOption) (*TranscriptionResponse, error)
+	CreateTranslation(ctx context.Context, req *TranslationRequest, opts ...RequestOption) (*TranslationResponse, error)
+	CreateSpeech(ctx context.Context, req *SpeechRequest, opts ...RequestOption) ([]byte, error)
+	CreateSpeechTo(ctx context.Context, req *SpeechRequest, w io.Writer, opts ...RequestOption) (int64, error)
+
+	ListModels(ctx context.Context) (*ModelList, error)
+	UploadFile(ctx context.Context, req *FileUploadRequest) (*File, error)
+	GetFileContent(ctx context.Context, fileID string) ([]byte, error)
+	CreateBatch(ctx context.Context, req *BatchCreateRequest) (*Batch, error)
+	GetBatch(ctx context.Context, batchID string) (*Batch, error)
+}
+
+var _ API = (*Client)(nil)
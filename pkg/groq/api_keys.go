@@ -0,0 +1,77 @@
+package groq
+
+import (
+	"github.com/genc-murat/groq-client/internal/util"
+)
+
+// APIKeyStatus reports the state of one API key configured with WithAPIKeys.
+type APIKeyStatus = util.KeyStatus
+
+// KeyBalancing selects how requests are spread over the keys of WithAPIKeys.
+type KeyBalancing = util.KeyBalancing
+
+const (
+	// RoundRobin uses the available keys in turn. It is the default.
+	RoundRobin = util.RoundRobin
+	// LeastLoaded uses the available key with the fewest requests in flight, and among
+	// those the one with the most tokens left according to the rate limit headers.
+	LeastLoaded = util.LeastLoaded
+)
+
+// WithAPIKeys spreads requests over several API keys, e.g. of several projects, to raise
+// the throughput beyond the rate limits of one key. Every attempt uses the next available
+// key (see WithKeyBalancing). A key that receives a 429, or whose rate limit headers report
+// an exhausted limit, cools off until its limit resets, and a rate limited request is
+// retried with another key without waiting. The keys replace the key given to NewClient,
+// which may then be empty.
+//
+// Example usage:
+//
+//	client := NewClient("", WithAPIKeys(strings.Split(os.Getenv("GROQ_API_KEYS"), ",")))
+//
+// Parameters:
+//   - keys: The API keys; none uses the key given to NewClient.
+//
+// Returns:
+//   - Option: A function that sets the keys.
+func WithAPIKeys(keys []string) Option {
+	return func(c *Client) {
+		c.apiKeys = keys
+	}
+}
+
+// WithKeyBalancing selects how requests are spread over the keys of WithAPIKeys. The
+// default is RoundRobin.
+//
+// Parameters:
+//   - balancing: RoundRobin or LeastLoaded.
+//
+// Returns:
+//   - Option: A function that sets the balancing.
+func WithKeyBalancing(balancing KeyBalancing) Option {
+	return func(c *Client) {
+		c.keyBalancing = balancing
+	}
+}
+
+// APIKeyStatus returns the state of every key configured with WithAPIKeys, in the order
+// they were given, with the keys masked, e.g. for a status page.
+//
+// Returns:
+//   - []APIKeyStatus: The keys, or nil if the client uses a single key.
+func (c *Client) APIKeyStatus() []APIKeyStatus {
+	if c.keys == nil {
+		return nil
+	}
+	return c.keys.Status()
+}
+
+// setupKeys installs the key pool for WithAPIKeys. It runs after all options, since
+// options may replace the HTTP client.
+func (c *Client) setupKeys() {
+	if len(c.apiKeys) == 0 {
+		return
+	}
+	c.keys = util.NewKeyPool(c.apiKeys, c.keyBalancing)
+	c.httpClient.SetKeyPool(c.keys)
+}
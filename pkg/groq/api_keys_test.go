@@ -0,0 +1,56 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithAPIKeys(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer gsk_limited" {
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":{"message":"rate limited","type":"rate_limit_exceeded"}}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","choices":[{"message":{"role":"assistant","content":"pong"},"finish_reason":"stop"}]}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewClientE("", WithBaseURL(srv.URL), WithAPIKeys([]string{"gsk_limited", "gsk_spare"}), WithRetryConfig(1, 5*time.Second))
+	if err != nil {
+		t.Fatalf("NewClientE: %v", err)
+	}
+	defer client.Close()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := client.CreateChatCompletion(context.Background(), NewRequest(ModelLlama31_8bInstant).User("ping").Build()); err != nil {
+			t.Fatalf("CreateChatCompletion: %v", err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("the retry with the spare key waited, requests took %v", elapsed)
+	}
+
+	status := client.APIKeyStatus()
+	if len(status) != 2 || status[0].RateLimited != 1 || status[0].Requests != 1 || status[1].Requests != 3 {
+		t.Errorf("unexpected key status: %+v", status)
+	}
+	if status[0].CoolingUntil.IsZero() {
+		t.Error("expected the rate limited key to cool off")
+	}
+}
+
+func TestWithAPIKeysInvalid(t *testing.T) {
+	_, err := NewClientE("", WithAPIKeys([]string{"gsk_ok", "gsk_bad\n"}))
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected a config error for the key with a newline, got %v", err)
+	}
+}
@@ -2,37 +2,73 @@ package groq
 
 import (
 	"io"
+
+	"github.com/genc-murat/groq-client/pkg/groq/wire"
+)
+
+const (
+	TimestampGranularityWord    = "word"
+	TimestampGranularitySegment = "segment"
 )
 
 type TranscriptionRequest struct {
 	File           io.Reader
 	FileName       string
+	URL            string // Public audio URL, used instead of File and FileName
 	Language       string
 	Model          ModelType
 	Prompt         string
 	ResponseFormat string
-	Temperature    float64
+	Temperature    *float64 // nil uses the API default; see Float64
+	// TimestampGranularities selects "word" and/or "segment" timestamps. It requires
+	// ResponseFormat "verbose_json"; word timestamps are returned in Words.
+	TimestampGranularities []string
 }
 
 type TranslationRequest struct {
 	File           io.Reader
 	FileName       string
+	URL            string // Public audio URL, used instead of File and FileName
 	Model          ModelType
 	Prompt         string
 	ResponseFormat string
-	Temperature    float64
+	Temperature    *float64 // nil uses the API default; see Float64
 }
 
+// TranscriptionResponse holds the result of a transcription. Only Text is set for the
+// json format; Task, Language, Duration, Segments and Words are populated when
+// ResponseFormat is "verbose_json".
 type TranscriptionResponse struct {
-	Text  string `json:"text"`
-	XGroq struct {
+	Text     string                 `json:"text"`
+	Task     string                 `json:"task,omitempty"`
+	Language string                 `json:"language,omitempty"`
+	Duration float64                `json:"duration,omitempty"` // Seconds
+	Segments []TranscriptionSegment `json:"segments,omitempty"`
+	Words    []TranscriptionWord    `json:"words,omitempty"`
+	XGroq    struct {
 		ID string `json:"id"`
 	} `json:"x_groq"`
 }
 
-type TranslationResponse struct {
-	Text  string `json:"text"`
-	XGroq struct {
-		ID string `json:"id"`
-	} `json:"x_groq"`
+type (
+	TranscriptionSegment = wire.TranscriptionSegment
+	TranscriptionWord    = wire.TranscriptionWord
+	TranslationResponse  = wire.TranslationResponse
+)
+
+// validateTimestampGranularities checks the requested granularities and that the
+// response format can carry them.
+func validateTimestampGranularities(req *TranscriptionRequest) error {
+	if len(req.TimestampGranularities) == 0 {
+		return nil
+	}
+	if req.ResponseFormat != "verbose_json" {
+		return newLocalizedError(nil, MsgGranularityFormat, req.ResponseFormat)
+	}
+	for _, g := range req.TimestampGranularities {
+		if g != TimestampGranularityWord && g != TimestampGranularitySegment {
+			return newLocalizedError(nil, MsgInvalidGranularity, g, TimestampGranularityWord, TimestampGranularitySegment)
+		}
+	}
+	return nil
 }
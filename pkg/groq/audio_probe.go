@@ -0,0 +1,296 @@
+package groq
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// minBilledAudioDuration is the minimum duration Groq bills for a single audio request.
+const minBilledAudioDuration = 10 * time.Second
+
+var ErrUnsupportedAudioProbe = errors.New("audio duration probing not supported for this format")
+
+// ProbeAudioDuration determines the playback duration of an audio file by parsing its
+// container headers, without decoding any audio. WAV, MP3 and MP4/M4A files are supported;
+// other formats return ErrUnsupportedAudioProbe. The reader is left at an unspecified offset.
+//
+// Parameters:
+//   - r: The audio data. It must support seeking so headers can be located.
+//   - fileName: The file name, used to select the parser by extension.
+//
+// Returns:
+//   - time.Duration: The audio duration.
+//   - error: An error if the format is unsupported or the headers are malformed.
+func ProbeAudioDuration(r io.ReadSeeker, fileName string) (time.Duration, error) {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".wav":
+		return probeWAV(r)
+	case ".mp3", ".mpga", ".mpeg":
+		return probeMP3(r)
+	case ".m4a", ".mp4":
+		return probeMP4(r)
+	default:
+		return 0, fmt.Errorf("%w: %s", ErrUnsupportedAudioProbe, fileName)
+	}
+}
+
+// EstimateTranscriptionCost returns the estimated USD cost of transcribing or translating
+// audio of the given duration with model, using the per-hour rate from the model registry.
+// Durations shorter than the 10 second billing minimum are rounded up.
+//
+// Parameters:
+//   - model: The audio model that will process the file.
+//   - duration: The audio duration, e.g. from ProbeAudioDuration.
+//
+// Returns:
+//   - float64: The estimated cost in USD.
+//   - error: An error if the model has no known audio rate.
+func EstimateTranscriptionCost(model ModelType, duration time.Duration) (float64, error) {
+	rate := model.GetInfo().AudioPricePerHour
+	if rate == 0 {
+		return 0, fmt.Errorf("no audio pricing known for model %s", model)
+	}
+
+	if duration < minBilledAudioDuration {
+		duration = minBilledAudioDuration
+	}
+
+	return duration.Hours() * rate, nil
+}
+
+// probeWAV reads the RIFF fmt and data chunks and computes duration as data size / byte rate.
+func probeWAV(r io.ReadSeeker) (time.Duration, error) {
+	var header [12]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, fmt.Errorf("invalid wav header: %w", err)
+	}
+	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		return 0, fmt.Errorf("invalid wav header")
+	}
+
+	var byteRate uint32
+	for {
+		var chunk [8]byte
+		if _, err := io.ReadFull(r, chunk[:]); err != nil {
+			return 0, fmt.Errorf("wav data chunk not found: %w", err)
+		}
+		id := string(chunk[0:4])
+		size := binary.LittleEndian.Uint32(chunk[4:8])
+
+		switch id {
+		case "fmt ":
+			// Only the 16 bytes every format has are read; the size comes from the file,
+			// so extensions are skipped rather than allocated.
+			var fmtChunk [16]byte
+			if size < uint32(len(fmtChunk)) {
+				return 0, fmt.Errorf("invalid wav fmt chunk")
+			}
+			if _, err := io.ReadFull(r, fmtChunk[:]); err != nil {
+				return 0, fmt.Errorf("invalid wav fmt chunk: %w", err)
+			}
+			byteRate = binary.LittleEndian.Uint32(fmtChunk[8:12])
+			if _, err := r.Seek(int64(size)-int64(len(fmtChunk))+int64(size%2), io.SeekCurrent); err != nil {
+				return 0, err
+			}
+		case "data":
+			if byteRate == 0 {
+				return 0, fmt.Errorf("wav data chunk precedes fmt chunk")
+			}
+			return time.Duration(float64(size) / float64(byteRate) * float64(time.Second)), nil
+		default:
+			if _, err := r.Seek(int64(size)+int64(size%2), io.SeekCurrent); err != nil {
+				return 0, err
+			}
+		}
+	}
+}
+
+var (
+	mp3BitratesV1 = [16]int{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 0}
+	mp3BitratesV2 = [16]int{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0}
+	mp3Samplerate = map[int][3]int{
+		3: {44100, 48000, 32000}, // MPEG 1
+		2: {22050, 24000, 16000}, // MPEG 2
+		0: {11025, 12000, 8000},  // MPEG 2.5
+	}
+)
+
+// probeMP3 locates the first MPEG Layer III frame after any ID3v2 tag. If the frame carries
+// a Xing/Info header the exact frame count is used, otherwise the duration is estimated
+// from the constant bitrate and the audio payload size.
+func probeMP3(r io.ReadSeeker) (time.Duration, error) {
+	fileSize, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	var offset int64
+	var id3 [10]byte
+	if _, err := io.ReadFull(r, id3[:]); err != nil {
+		return 0, fmt.Errorf("invalid mp3 file: %w", err)
+	}
+	if string(id3[0:3]) == "ID3" {
+		size := int64(id3[6]&0x7f)<<21 | int64(id3[7]&0x7f)<<14 | int64(id3[8]&0x7f)<<7 | int64(id3[9]&0x7f)
+		offset = 10 + size
+	}
+
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	buf := make([]byte, 64*1024)
+	n, err := io.ReadFull(r, buf)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return 0, fmt.Errorf("invalid mp3 file: %w", err)
+	}
+	buf = buf[:n]
+
+	for i := 0; i+4 <= len(buf); i++ {
+		if buf[i] != 0xff || buf[i+1]&0xe0 != 0xe0 {
+			continue
+		}
+
+		version := int(buf[i+1]>>3) & 0x03
+		layer := int(buf[i+1]>>1) & 0x03
+		bitrateIndex := int(buf[i+2] >> 4)
+		rateIndex := int(buf[i+2]>>2) & 0x03
+		channelMode := int(buf[i+3] >> 6)
+
+		rates, ok := mp3Samplerate[version]
+		if !ok || layer != 1 || rateIndex == 3 || bitrateIndex == 0 || bitrateIndex == 15 {
+			continue
+		}
+		sampleRate := rates[rateIndex]
+
+		bitrate := mp3BitratesV1[bitrateIndex]
+		samplesPerFrame := 1152
+		sideInfo := 32
+		if channelMode == 3 {
+			sideInfo = 17
+		}
+		if version != 3 {
+			bitrate = mp3BitratesV2[bitrateIndex]
+			samplesPerFrame = 576
+			sideInfo = 17
+			if channelMode == 3 {
+				sideInfo = 9
+			}
+		}
+
+		xing := i + 4 + sideInfo
+		if xing+12 <= len(buf) {
+			tag := buf[xing : xing+4]
+			if bytes.Equal(tag, []byte("Xing")) || bytes.Equal(tag, []byte("Info")) {
+				flags := binary.BigEndian.Uint32(buf[xing+4 : xing+8])
+				if flags&0x01 != 0 {
+					frames := binary.BigEndian.Uint32(buf[xing+8 : xing+12])
+					seconds := float64(frames) * float64(samplesPerFrame) / float64(sampleRate)
+					return time.Duration(seconds * float64(time.Second)), nil
+				}
+			}
+		}
+
+		audioBytes := fileSize - offset - int64(i)
+		seconds := float64(audioBytes*8) / float64(bitrate*1000)
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+
+	return 0, fmt.Errorf("no mp3 frame found")
+}
+
+// probeMP4 walks the top-level boxes to moov/mvhd and reads the movie timescale and duration.
+func probeMP4(r io.ReadSeeker) (time.Duration, error) {
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	return findMVHD(r, 0, end)
+}
+
+// findMVHD searches the boxes between start and end for mvhd, descending into moov.
+func findMVHD(r io.ReadSeeker, start, end int64) (time.Duration, error) {
+	pos := start
+	for pos+8 <= end {
+		if _, err := r.Seek(pos, io.SeekStart); err != nil {
+			return 0, err
+		}
+
+		var header [8]byte
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return 0, fmt.Errorf("invalid mp4 box: %w", err)
+		}
+		size := int64(binary.BigEndian.Uint32(header[0:4]))
+		boxType := string(header[4:8])
+		headerSize := int64(8)
+
+		switch size {
+		case 0:
+			size = end - pos
+		case 1:
+			var large [8]byte
+			if _, err := io.ReadFull(r, large[:]); err != nil {
+				return 0, fmt.Errorf("invalid mp4 box: %w", err)
+			}
+			size = int64(binary.BigEndian.Uint64(large[:]))
+			headerSize = 16
+		}
+		if size < headerSize {
+			return 0, fmt.Errorf("invalid mp4 box size")
+		}
+
+		switch boxType {
+		case "moov":
+			return findMVHD(r, pos+headerSize, pos+size)
+		case "mvhd":
+			return readMVHD(r)
+		}
+
+		pos += size
+	}
+
+	return 0, fmt.Errorf("mp4 mvhd box not found")
+}
+
+// readMVHD parses the body of an mvhd box positioned at the version byte.
+func readMVHD(r io.Reader) (time.Duration, error) {
+	var version [4]byte
+	if _, err := io.ReadFull(r, version[:]); err != nil {
+		return 0, fmt.Errorf("invalid mvhd box: %w", err)
+	}
+
+	var timescale uint32
+	var duration uint64
+	if version[0] == 1 {
+		var body [28]byte
+		if _, err := io.ReadFull(r, body[:]); err != nil {
+			return 0, fmt.Errorf("invalid mvhd box: %w", err)
+		}
+		timescale = binary.BigEndian.Uint32(body[16:20])
+		duration = binary.BigEndian.Uint64(body[20:28])
+	} else {
+		var body [16]byte
+		if _, err := io.ReadFull(r, body[:]); err != nil {
+			return 0, fmt.Errorf("invalid mvhd box: %w", err)
+		}
+		timescale = binary.BigEndian.Uint32(body[8:12])
+		duration = uint64(binary.BigEndian.Uint32(body[12:16]))
+	}
+
+	if timescale == 0 {
+		return 0, fmt.Errorf("invalid mvhd timescale")
+	}
+
+	return time.Duration(float64(duration) / float64(timescale) * float64(time.Second)), nil
+}
@@ -0,0 +1,117 @@
+package groq
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// buildWAV returns a PCM WAV file containing the given number of silent samples.
+func buildWAV(sampleRate, samples int) []byte {
+	var buf bytes.Buffer
+	dataSize := uint32(samples * 2)
+
+	buf.WriteString("RIFF")
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(36)+dataSize)
+	buf.WriteString("WAVEfmt ")
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(16))
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(1))            // PCM
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(1))            // mono
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(sampleRate))   // sample rate
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(sampleRate*2)) // byte rate
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(2))            // block align
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(16))           // bits per sample
+	buf.WriteString("data")
+	_ = binary.Write(&buf, binary.LittleEndian, dataSize)
+	buf.Write(make([]byte, dataSize))
+
+	return buf.Bytes()
+}
+
+func TestProbeAudioDurationWAV(t *testing.T) {
+	wav := buildWAV(16000, 16000*3)
+
+	got, err := ProbeAudioDuration(bytes.NewReader(wav), "speech.wav")
+	if err != nil {
+		t.Fatalf("ProbeAudioDuration() error = %v", err)
+	}
+	if got != 3*time.Second {
+		t.Errorf("ProbeAudioDuration() = %v, want 3s", got)
+	}
+}
+
+func TestProbeAudioDurationWAVFmtChunkSize(t *testing.T) {
+	// An extended fmt chunk of 18 bytes, padded to an even size.
+	wav := buildWAV(16000, 16000)
+	extended := append(append([]byte{}, wav[:16]...), 18, 0, 0, 0)
+	extended = append(extended, wav[20:36]...)
+	extended = append(extended, 0, 0)
+	extended = append(extended, wav[36:]...)
+	if got, err := ProbeAudioDuration(bytes.NewReader(extended), "speech.wav"); err != nil || got != time.Second {
+		t.Errorf("ProbeAudioDuration() = %v, %v for an extended fmt chunk", got, err)
+	}
+
+	// A fmt chunk claiming 4 GiB must be skipped, not allocated.
+	huge := append([]byte{}, wav...)
+	binary.LittleEndian.PutUint32(huge[16:20], 0xFFFFFFF0)
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	if _, err := ProbeAudioDuration(bytes.NewReader(huge), "speech.wav"); err == nil {
+		t.Error("expected an error for a fmt chunk larger than the file")
+	}
+	runtime.ReadMemStats(&after)
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1<<20 {
+		t.Errorf("probing allocated %d bytes", allocated)
+	}
+
+	short := append([]byte{}, wav...)
+	binary.LittleEndian.PutUint32(short[16:20], 12)
+	if _, err := ProbeAudioDuration(bytes.NewReader(short), "speech.wav"); err == nil {
+		t.Error("expected an error for a fmt chunk shorter than 16 bytes")
+	}
+}
+
+func TestProbeAudioDurationM4A(t *testing.T) {
+	file, err := os.Open("../../examples/audio/transcription/Recording.m4a")
+	if err != nil {
+		t.Skipf("sample recording not available: %v", err)
+	}
+	defer file.Close()
+
+	got, err := ProbeAudioDuration(file, "Recording.m4a")
+	if err != nil {
+		t.Fatalf("ProbeAudioDuration() error = %v", err)
+	}
+	if got <= 0 || got > time.Hour {
+		t.Errorf("ProbeAudioDuration() = %v, want a plausible duration", got)
+	}
+}
+
+func TestProbeAudioDurationUnsupported(t *testing.T) {
+	if _, err := ProbeAudioDuration(bytes.NewReader(nil), "speech.ogg"); err == nil {
+		t.Error("expected an error for unsupported formats")
+	}
+}
+
+func TestEstimateTranscriptionCost(t *testing.T) {
+	cost, err := EstimateTranscriptionCost(ModelWhisperLargeV3Turbo, time.Hour)
+	if err != nil {
+		t.Fatalf("EstimateTranscriptionCost() error = %v", err)
+	}
+	if cost != 0.04 {
+		t.Errorf("EstimateTranscriptionCost() = %v, want 0.04", cost)
+	}
+
+	short, _ := EstimateTranscriptionCost(ModelWhisperLargeV3Turbo, time.Second)
+	minimum, _ := EstimateTranscriptionCost(ModelWhisperLargeV3Turbo, 10*time.Second)
+	if short != minimum {
+		t.Errorf("expected durations below the billing minimum to be rounded up")
+	}
+
+	if _, err := EstimateTranscriptionCost(ModelLlama31_8bInstant, time.Hour); err == nil {
+		t.Error("expected an error for a model without audio pricing")
+	}
+}
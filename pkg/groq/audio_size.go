@@ -0,0 +1,67 @@
+package groq
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+var ErrFileTooLarge = errors.New("file too large")
+
+type FileTooLargeError struct {
+	Model ModelType
+	Size  int64 // Size of the upload in bytes
+	Limit int64 // ModelInfo.MaxFileSize of the model
+}
+
+// Error returns a message with the size of the file and the limit of the model.
+func (e *FileTooLargeError) Error() string {
+	return fmt.Sprintf("%s: %d bytes exceeds the %d byte limit of %s", ErrFileTooLarge, e.Size, e.Limit, e.Model)
+}
+
+// Unwrap allows errors.Is(err, ErrFileTooLarge) to match any FileTooLargeError.
+func (e *FileTooLargeError) Unwrap() error {
+	return ErrFileTooLarge
+}
+
+// audioSize returns the number of bytes left in r without consuming them. Seekable
+// readers are measured by seeking to the end and back; readers that report their
+// length, like bytes.Buffer, are asked for it. The second result is false when the
+// size cannot be determined up front.
+func audioSize(r io.Reader) (int64, bool) {
+	switch v := r.(type) {
+	case io.Seeker:
+		current, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, false
+		}
+		end, err := v.Seek(0, io.SeekEnd)
+		if err != nil {
+			return 0, false
+		}
+		if _, err := v.Seek(current, io.SeekStart); err != nil {
+			return 0, false
+		}
+		return end - current, true
+	case interface{ Len() int }:
+		return int64(v.Len()), true
+	case interface{ Size() int64 }:
+		return v.Size(), true
+	}
+	return 0, false
+}
+
+// checkAudioSize fails with a *FileTooLargeError if the upload is larger than the
+// model accepts. Files of unknown size and models without a known limit pass.
+func checkAudioSize(model ModelType, file io.Reader) error {
+	limit := model.GetInfo().MaxFileSize
+	if limit <= 0 {
+		return nil
+	}
+
+	size, ok := audioSize(file)
+	if !ok || size <= limit {
+		return nil
+	}
+	return &FileTooLargeError{Model: model, Size: size, Limit: limit}
+}
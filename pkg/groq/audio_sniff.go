@@ -0,0 +1,127 @@
+package groq
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// audioSniffLen is how many leading bytes are inspected to detect the audio format.
+const audioSniffLen = 512
+
+var ErrUnsupportedAudioFormat = errors.New("unsupported audio format")
+
+// audioSignature describes the magic bytes of a container or codec.
+type audioSignature struct {
+	name      string // Human readable format name
+	ext       string // Extension sent to the API, empty for unsupported formats
+	offset    int
+	magic     []byte
+	condition func(head []byte) bool
+}
+
+var audioSignatures = []audioSignature{
+	{name: "WAV", ext: ".wav", magic: []byte("RIFF"), condition: func(h []byte) bool { return len(h) >= 12 && string(h[8:12]) == "WAVE" }},
+	{name: "FLAC", ext: ".flac", magic: []byte("fLaC")},
+	{name: "Ogg", ext: ".ogg", magic: []byte("OggS")},
+	{name: "WebM", ext: ".webm", magic: []byte{0x1A, 0x45, 0xDF, 0xA3}},
+	{name: "M4A", ext: ".m4a", offset: 4, magic: []byte("ftyp"), condition: func(h []byte) bool { return len(h) >= 12 && strings.HasPrefix(string(h[8:12]), "M4") }},
+	{name: "MP4", ext: ".mp4", offset: 4, magic: []byte("ftyp")},
+	{name: "MP3", ext: ".mp3", magic: []byte("ID3")},
+	{name: "MP3", ext: ".mp3", condition: isMPEGFrameSync},
+
+	{name: "AIFF", magic: []byte("FORM"), condition: func(h []byte) bool { return len(h) >= 12 && strings.HasPrefix(string(h[8:12]), "AIF") }},
+	{name: "AMR", magic: []byte("#!AMR")},
+	{name: "CAF", magic: []byte("caff")},
+	{name: "WMA", magic: []byte{0x30, 0x26, 0xB2, 0x75, 0x8E, 0x66, 0xCF, 0x11}},
+	{name: "MIDI", magic: []byte("MThd")},
+	{name: "AAC (ADTS)", condition: func(h []byte) bool { return len(h) >= 2 && h[0] == 0xFF && h[1]&0xF6 == 0xF0 }},
+}
+
+// audioFamilies groups extensions the API treats as the same container.
+var audioFamilies = map[string]string{
+	".mp3":  ".mp3",
+	".mpga": ".mp3",
+	".mpeg": ".mp3",
+	".mp4":  ".mp4",
+	".m4a":  ".mp4",
+}
+
+// SniffAudioFormat detects the audio format from the leading bytes of a file.
+//
+// Parameters:
+//   - head: The first bytes of the file; 512 bytes are enough for every known format.
+//
+// Returns:
+//   - string: The format name, e.g. "MP3" or "AIFF", or "" if the format is unknown.
+//   - string: The file extension for a format the API accepts, e.g. ".mp3", or "" if the
+//     format is unknown or not supported by the API.
+func SniffAudioFormat(head []byte) (name, ext string) {
+	for _, sig := range audioSignatures {
+		if len(sig.magic) > 0 {
+			end := sig.offset + len(sig.magic)
+			if len(head) < end || !bytes.Equal(head[sig.offset:end], sig.magic) {
+				continue
+			}
+		}
+		if sig.condition != nil && !sig.condition(head) {
+			continue
+		}
+		return sig.name, sig.ext
+	}
+	return "", ""
+}
+
+// isMPEGFrameSync reports whether head starts with an MPEG audio layer III frame header.
+func isMPEGFrameSync(head []byte) bool {
+	return len(head) >= 2 && head[0] == 0xFF && head[1]&0xE0 == 0xE0 && (head[1]>>1)&0x03 == 0x01
+}
+
+// sniffAudio inspects the start of file to find its real format and returns a reader that
+// still yields the whole file (the same reader, rewound, if it is seekable), together with the file name to upload it under. Files
+// without an extension or with a wrong one are renamed to match their content, and
+// formats the API does not accept are rejected with ErrUnsupportedAudioFormat.
+func sniffAudio(file io.Reader, fileName string) (io.Reader, string, error) {
+	head := make([]byte, audioSniffLen)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, "", fmt.Errorf("error reading audio: %w", err)
+	}
+	head = head[:n]
+	if seeker, ok := file.(io.Seeker); ok {
+		// Keep the reader seekable so the upload can be sized and retried.
+		if _, err := seeker.Seek(int64(-n), io.SeekCurrent); err != nil {
+			return nil, "", fmt.Errorf("error reading audio: %w", err)
+		}
+	} else {
+		file = io.MultiReader(bytes.NewReader(head), file)
+	}
+
+	ext := strings.ToLower(filepath.Ext(fileName))
+	name, detected := SniffAudioFormat(head)
+
+	switch {
+	case name != "" && detected == "":
+		return nil, "", newLocalizedError(ErrUnsupportedAudioFormat, MsgUnsupportedAudioCodec, fileName, name)
+	case detected == "":
+		if !isValidAudioFormat(ext) {
+			return nil, "", newLocalizedError(nil, MsgInvalidAudioFormat, filepath.Ext(fileName))
+		}
+		return file, fileName, nil
+	case isValidAudioFormat(ext) && audioFamily(ext) == audioFamily(detected):
+		return file, fileName, nil
+	default:
+		return file, strings.TrimSuffix(fileName, filepath.Ext(fileName)) + detected, nil
+	}
+}
+
+// audioFamily returns the container family of an extension.
+func audioFamily(ext string) string {
+	if family, ok := audioFamilies[ext]; ok {
+		return family
+	}
+	return ext
+}
@@ -0,0 +1,74 @@
+package groq
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSniffAudioFormat(t *testing.T) {
+	tests := []struct {
+		head string
+		name string
+		ext  string
+	}{
+		{"RIFF\x24\x00\x00\x00WAVEfmt ", "WAV", ".wav"},
+		{"ID3\x04\x00", "MP3", ".mp3"},
+		{"\xFF\xFB\x90\x00", "MP3", ".mp3"},
+		{"fLaC\x00\x00", "FLAC", ".flac"},
+		{"OggS\x00\x02", "Ogg", ".ogg"},
+		{"\x1A\x45\xDF\xA3\x9F", "WebM", ".webm"},
+		{"\x00\x00\x00\x20ftypM4A \x00", "M4A", ".m4a"},
+		{"\x00\x00\x00\x20ftypisom\x00", "MP4", ".mp4"},
+		{"FORM\x00\x00\x00\x00AIFF", "AIFF", ""},
+		{"#!AMR\n", "AMR", ""},
+		{"\xFF\xF1\x50\x80", "AAC (ADTS)", ""},
+		{"hello world", "", ""},
+	}
+	for _, tt := range tests {
+		name, ext := SniffAudioFormat([]byte(tt.head))
+		if name != tt.name || ext != tt.ext {
+			t.Errorf("SniffAudioFormat(%q) = %q, %q, want %q, %q", tt.head, name, ext, tt.name, tt.ext)
+		}
+	}
+}
+
+func TestSniffAudio(t *testing.T) {
+	wav := "RIFF\x24\x00\x00\x00WAVEfmt rest of the file"
+
+	tests := []struct {
+		content  string
+		fileName string
+		wantName string
+		wantErr  error
+	}{
+		{wav, "voice.wav", "voice.wav", nil},
+		{wav, "voice", "voice.wav", nil},
+		{wav, "voice.mp3", "voice.wav", nil},
+		{"\x00\x00\x00\x20ftypM4A \x00", "memo.mp4", "memo.mp4", nil},
+		{"unknown", "memo.ogg", "memo.ogg", nil},
+		{"FORM\x00\x00\x00\x00AIFF", "song.wav", "", ErrUnsupportedAudioFormat},
+	}
+	for _, tt := range tests {
+		r, name, err := sniffAudio(strings.NewReader(tt.content), tt.fileName)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("sniffAudio(%q) error = %v, want %v", tt.fileName, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if name != tt.wantName {
+			t.Errorf("sniffAudio(%q) name = %q, want %q", tt.fileName, name, tt.wantName)
+		}
+		data, _ := io.ReadAll(r)
+		if string(data) != tt.content {
+			t.Errorf("sniffAudio(%q) lost data: %q", tt.fileName, data)
+		}
+	}
+
+	if _, _, err := sniffAudio(strings.NewReader("unknown"), "notes.txt"); err == nil {
+		t.Error("expected an error for an unknown format with an unsupported extension")
+	}
+}
@@ -0,0 +1,163 @@
+package groq
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCreateTranscriptionVerboseJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if got := r.FormValue("response_format"); got != "verbose_json" {
+			t.Errorf("response_format = %q, want verbose_json", got)
+		}
+		if got := r.MultipartForm.Value["timestamp_granularities[]"]; len(got) != 2 || got[0] == got[1] {
+			t.Errorf("timestamp_granularities[] = %v, want word and segment", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"task": "transcribe",
+			"language": "English",
+			"duration": 2.5,
+			"text": " hello world",
+			"segments": [{"id": 0, "seek": 0, "start": 0, "end": 2.5, "text": " hello world",
+				"tokens": [50364, 2425], "temperature": 0, "avg_logprob": -0.21,
+				"compression_ratio": 0.8, "no_speech_prob": 0.01}],
+			"words": [{"word": "hello", "start": 0, "end": 1.1}, {"word": "world", "start": 1.2, "end": 2.5}],
+			"x_groq": {"id": "req_1"}
+		}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key", WithBaseURL(srv.URL))
+	resp, err := client.CreateTranscription(context.Background(), &TranscriptionRequest{
+		File:           strings.NewReader("data"),
+		FileName:       "a.mp3",
+		ResponseFormat: "verbose_json",
+		TimestampGranularities: []string{
+			TimestampGranularityWord,
+			TimestampGranularitySegment,
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateTranscription() error = %v", err)
+	}
+
+	if resp.Language != "English" || resp.Duration != 2.5 || resp.XGroq.ID != "req_1" {
+		t.Errorf("unexpected metadata: %+v", resp)
+	}
+	if len(resp.Segments) != 1 {
+		t.Fatalf("expected 1 segment, got %d", len(resp.Segments))
+	}
+	seg := resp.Segments[0]
+	if seg.End != 2.5 || seg.AvgLogprob != -0.21 || seg.NoSpeechProb != 0.01 || len(seg.Tokens) != 2 {
+		t.Errorf("unexpected segment: %+v", seg)
+	}
+	if len(resp.Words) != 2 || resp.Words[1].Word != "world" || resp.Words[1].Start != 1.2 {
+		t.Errorf("unexpected words: %+v", resp.Words)
+	}
+}
+
+func TestCreateTranscriptionTimestampGranularitiesValidation(t *testing.T) {
+	client := NewClient("test-key")
+	tests := []*TranscriptionRequest{
+		{FileName: "a.mp3", TimestampGranularities: []string{TimestampGranularityWord}},
+		{FileName: "a.mp3", ResponseFormat: "verbose_json", TimestampGranularities: []string{"sentence"}},
+	}
+	for _, req := range tests {
+		req.File = strings.NewReader("data")
+		if _, err := client.CreateTranscription(context.Background(), req); !errors.Is(err, ErrInvalidRequest) {
+			t.Errorf("expected ErrInvalidRequest for %v, got %v", req.TimestampGranularities, err)
+		}
+	}
+}
+
+func TestCreateTranscriptionFromURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if got := r.FormValue("url"); got != "https://example.com/talk.mp3" {
+			t.Errorf("url = %q", got)
+		}
+		if len(r.MultipartForm.File) != 0 {
+			t.Errorf("expected no file upload, got %v", r.MultipartForm.File)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"text":"hello"}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key", WithBaseURL(srv.URL))
+	resp, err := client.CreateTranscription(context.Background(), &TranscriptionRequest{
+		URL: "https://example.com/talk.mp3",
+	})
+	if err != nil {
+		t.Fatalf("CreateTranscription() error = %v", err)
+	}
+	if resp.Text != "hello" {
+		t.Errorf("unexpected text: %q", resp.Text)
+	}
+
+	_, err = client.CreateTranslation(context.Background(), &TranslationRequest{
+		File:     strings.NewReader("data"),
+		FileName: "a.mp3",
+		URL:      "https://example.com/talk.mp3",
+	})
+	if !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("expected ErrInvalidRequest for File and URL, got %v", err)
+	}
+}
+
+func TestCreateTranscriptionRejectsOversizedFile(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("oversized file was uploaded")
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key", WithBaseURL(srv.URL))
+	limit := ModelWhisperLargeV3.GetInfo().MaxFileSize
+	file := bytes.NewReader(make([]byte, limit+1))
+
+	_, err := client.CreateTranscription(context.Background(), &TranscriptionRequest{
+		File:     file,
+		FileName: "big.wav",
+		Model:    ModelWhisperLargeV3,
+	})
+	var tooLarge *FileTooLargeError
+	if !errors.As(err, &tooLarge) || !errors.Is(err, ErrFileTooLarge) {
+		t.Fatalf("expected FileTooLargeError, got %v", err)
+	}
+	if tooLarge.Size != limit+1 || tooLarge.Limit != limit {
+		t.Errorf("unexpected error fields: %+v", tooLarge)
+	}
+}
+
+func TestAudioSizeKeepsReaderPosition(t *testing.T) {
+	r := strings.NewReader("0123456789")
+	_, _ = r.Seek(4, io.SeekStart)
+
+	if size, ok := audioSize(r); !ok || size != 6 {
+		t.Fatalf("audioSize() = %d, %v; want 6, true", size, ok)
+	}
+	if rest, _ := io.ReadAll(r); string(rest) != "456789" {
+		t.Errorf("reader position changed, remaining %q", rest)
+	}
+
+	if size, ok := audioSize(bytes.NewBufferString("abc")); !ok || size != 3 {
+		t.Errorf("audioSize(buffer) = %d, %v", size, ok)
+	}
+	if _, ok := audioSize(io.LimitReader(r, 1)); ok {
+		t.Error("audioSize() reported a size for an unsized reader")
+	}
+}
@@ -0,0 +1,389 @@
+package groq
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+)
+
+const (
+	// BatchEndpointChatCompletions is the endpoint batch lines are executed against.
+	BatchEndpointChatCompletions = "/v1/chat/completions"
+
+	// MaxBatchLines is the maximum number of requests in a batch input file.
+	MaxBatchLines = 50000
+	// MaxBatchFileSize is the maximum size of a batch input file in bytes.
+	MaxBatchFileSize = 200 << 20
+	// DefaultBatchMaxLineSize is the default limit for a single encoded batch line.
+	DefaultBatchMaxLineSize = 1 << 20
+)
+
+type BatchRequestLine struct {
+	CustomID string                 `json:"custom_id"`
+	Method   string                 `json:"method"`
+	URL      string                 `json:"url"`
+	Body     *ChatCompletionRequest `json:"body"`
+}
+
+type BatchBuilder struct {
+	lines       []BatchRequestLine
+	MaxLineSize int
+}
+
+// NewBatchBuilder creates an empty builder for batch input files. Each added request
+// becomes one JSONL line of the form {"custom_id", "method", "url", "body"}.
+//
+// Returns:
+//   - *BatchBuilder: A pointer to the new builder.
+func NewBatchBuilder() *BatchBuilder {
+	return &BatchBuilder{MaxLineSize: DefaultBatchMaxLineSize}
+}
+
+// Add appends a request with the given custom ID, which is used to match the
+// request to its result once the batch has completed.
+func (b *BatchBuilder) Add(customID string, req *ChatCompletionRequest) *BatchBuilder {
+	b.lines = append(b.lines, BatchRequestLine{
+		CustomID: customID,
+		Method:   "POST",
+		URL:      BatchEndpointChatCompletions,
+		Body:     req,
+	})
+	return b
+}
+
+// AddAll appends requests with the custom IDs "request-0", "request-1", ... based on
+// their position in the batch.
+func (b *BatchBuilder) AddAll(reqs []*ChatCompletionRequest) *BatchBuilder {
+	for _, req := range reqs {
+		b.Add(fmt.Sprintf("request-%d", len(b.lines)), req)
+	}
+	return b
+}
+
+// Len returns the number of requests in the batch.
+func (b *BatchBuilder) Len() int {
+	return len(b.lines)
+}
+
+// Validate checks the batch before it is uploaded. It verifies:
+// - The batch has between 1 and MaxBatchLines requests
+// - Custom IDs are set and unique
+// - Every request is valid and not streaming
+// - All requests use the same model
+// - No encoded line exceeds MaxLineSize and the file does not exceed MaxBatchFileSize
+//
+// Returns an error wrapping ErrInvalidBatch if any check fails, nil otherwise.
+func (b *BatchBuilder) Validate() error {
+	if len(b.lines) == 0 {
+		return fmt.Errorf("%w: batch has no requests", ErrInvalidBatch)
+	}
+	if len(b.lines) > MaxBatchLines {
+		return fmt.Errorf("%w: batch has %d requests, limit is %d", ErrInvalidBatch, len(b.lines), MaxBatchLines)
+	}
+
+	maxLine := b.MaxLineSize
+	if maxLine <= 0 {
+		maxLine = DefaultBatchMaxLineSize
+	}
+
+	seen := make(map[string]bool, len(b.lines))
+	model := b.lines[0].Body.modelOrEmpty()
+	total := 0
+
+	for i, line := range b.lines {
+		if line.CustomID == "" {
+			return fmt.Errorf("%w: line %d has no custom_id", ErrInvalidBatch, i+1)
+		}
+		if seen[line.CustomID] {
+			return fmt.Errorf("%w: duplicate custom_id %q", ErrInvalidBatch, line.CustomID)
+		}
+		seen[line.CustomID] = true
+
+		if line.Body == nil {
+			return fmt.Errorf("%w: %s has no request", ErrInvalidBatch, line.CustomID)
+		}
+		if err := line.Body.Validate(); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidBatch, line.CustomID, err)
+		}
+		if line.Body.Stream {
+			return fmt.Errorf("%w: %s: streaming is not supported in batches", ErrInvalidBatch, line.CustomID)
+		}
+		if line.Body.Model != model {
+			return fmt.Errorf("%w: %s uses model %s, batch uses %s", ErrInvalidBatch, line.CustomID, line.Body.Model, model)
+		}
+
+		encoded, err := json.Marshal(line)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrJSONEncoding, line.CustomID, err)
+		}
+		if len(encoded) > maxLine {
+			return fmt.Errorf("%w: %s is %d bytes, line limit is %d", ErrInvalidBatch, line.CustomID, len(encoded), maxLine)
+		}
+		total += len(encoded) + 1
+	}
+
+	if total > MaxBatchFileSize {
+		return fmt.Errorf("%w: batch file is %d bytes, limit is %d", ErrInvalidBatch, total, MaxBatchFileSize)
+	}
+
+	return nil
+}
+
+// WriteTo validates the batch and writes it to w in JSONL format.
+//
+// Parameters:
+//   - w: The destination, e.g. a file or the body of an upload.
+//
+// Returns:
+//   - int64: The number of bytes written.
+//   - error: A validation error or the first write error.
+func (b *BatchBuilder) WriteTo(w io.Writer) (int64, error) {
+	if err := b.Validate(); err != nil {
+		return 0, err
+	}
+	return b.writeLines(w)
+}
+
+// writeLines encodes the lines to w without validating them.
+func (b *BatchBuilder) writeLines(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	enc := json.NewEncoder(bw)
+
+	for _, line := range b.lines {
+		if err := enc.Encode(line); err != nil {
+			return cw.n, fmt.Errorf("failed to write batch line %s: %w", line.CustomID, err)
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return cw.n, fmt.Errorf("failed to write batch: %w", err)
+	}
+	return cw.n, nil
+}
+
+// UploadBatchInput validates the batch and uploads it to the Files API with purpose
+// "batch". The JSONL lines are encoded straight into the upload body, without an
+// intermediate file.
+//
+// Parameters:
+//   - ctx: Context for the upload.
+//   - b: The batch to upload.
+//   - fileName: The name of the uploaded file; defaults to "batch.jsonl".
+//
+// Returns:
+//   - *File: The uploaded file; its ID is the input_file_id of the batch job.
+//   - error: A validation or upload error.
+func (c *Client) UploadBatchInput(ctx context.Context, b *BatchBuilder, fileName string) (*File, error) {
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+	if fileName == "" {
+		fileName = "batch.jsonl"
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := b.writeLines(pw)
+		pw.CloseWithError(err)
+	}()
+
+	file, err := c.UploadFile(ctx, &FileUploadRequest{
+		File:     pr,
+		FileName: fileName,
+		Purpose:  FilePurposeBatch,
+	})
+	// Unblock the writer if the upload stopped before consuming the whole batch.
+	pr.CloseWithError(io.ErrClosedPipe)
+
+	return file, err
+}
+
+// modelOrEmpty returns the model of r, or "" if r is nil.
+func (r *ChatCompletionRequest) modelOrEmpty() ModelType {
+	if r == nil {
+		return ""
+	}
+	return r.Model
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+type BatchStatus string
+
+const (
+	BatchStatusValidating BatchStatus = "validating"
+	BatchStatusFailed     BatchStatus = "failed"
+	BatchStatusInProgress BatchStatus = "in_progress"
+	BatchStatusFinalizing BatchStatus = "finalizing"
+	BatchStatusCompleted  BatchStatus = "completed"
+	BatchStatusExpired    BatchStatus = "expired"
+	BatchStatusCancelling BatchStatus = "cancelling"
+	BatchStatusCancelled  BatchStatus = "cancelled"
+)
+
+type BatchRequestCounts struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+}
+
+type Batch struct {
+	ID               string             `json:"id"`
+	Object           string             `json:"object"`
+	Endpoint         string             `json:"endpoint"`
+	InputFileID      string             `json:"input_file_id"`
+	CompletionWindow string             `json:"completion_window"`
+	Status           BatchStatus        `json:"status"`
+	OutputFileID     string             `json:"output_file_id,omitempty"`
+	ErrorFileID      string             `json:"error_file_id,omitempty"`
+	CreatedAt        int64              `json:"created_at"`
+	CompletedAt      int64              `json:"completed_at,omitempty"`
+	ExpiresAt        int64              `json:"expires_at,omitempty"`
+	RequestCounts    BatchRequestCounts `json:"request_counts"`
+	Metadata         map[string]string  `json:"metadata,omitempty"`
+}
+
+type BatchCreateRequest struct {
+	InputFileID      string            `json:"input_file_id"`
+	Endpoint         string            `json:"endpoint"`
+	CompletionWindow string            `json:"completion_window"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+}
+
+// CreateBatch starts a batch job for an uploaded input file. The endpoint defaults to
+// BatchEndpointChatCompletions and the completion window to "24h".
+//
+// Parameters:
+//   - ctx: Context for the request
+//   - req: BatchCreateRequest with the input file ID and optional settings
+//
+// Returns:
+//   - *Batch: The created batch job
+//   - error: Any error that occurred during the request
+func (c *Client) CreateBatch(ctx context.Context, req *BatchCreateRequest) (*Batch, error) {
+	if req.InputFileID == "" {
+		return nil, fmt.Errorf("%w: input file id is required", ErrInvalidRequest)
+	}
+	if req.Endpoint == "" {
+		req.Endpoint = BatchEndpointChatCompletions
+	}
+	if req.CompletionWindow == "" {
+		req.CompletionWindow = "24h"
+	}
+
+	var result Batch
+	if err := c.httpClient.DoJSON(ctx, "POST", fmt.Sprintf("%s/batches", c.baseURL), req, &result, nil); err != nil {
+		return nil, fmt.Errorf("batch creation failed: %w", err)
+	}
+
+	return &result, nil
+}
+
+// GetBatch retrieves the current state of a batch job.
+//
+// Parameters:
+//   - ctx: Context for the request
+//   - batchID: The ID of the batch
+//
+// Returns:
+//   - *Batch: The batch job, including its status and result file IDs
+//   - error: Any error that occurred during the request
+func (c *Client) GetBatch(ctx context.Context, batchID string) (*Batch, error) {
+	if batchID == "" {
+		return nil, fmt.Errorf("%w: batch id is required", ErrInvalidRequest)
+	}
+
+	var result Batch
+	if err := c.httpClient.DoJSON(ctx, "GET", fmt.Sprintf("%s/batches/%s", c.baseURL, url.PathEscape(batchID)), nil, &result, nil); err != nil {
+		return nil, fmt.Errorf("batch retrieval failed: %w", err)
+	}
+
+	return &result, nil
+}
+
+// IsTerminal reports whether a batch in this status will not change anymore.
+func (s BatchStatus) IsTerminal() bool {
+	switch s {
+	case BatchStatusCompleted, BatchStatusFailed, BatchStatusExpired, BatchStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+type WaitForBatchOptions struct {
+	InitialInterval time.Duration  // Delay before the second poll; defaults to 5 seconds
+	MaxInterval     time.Duration  // Upper bound for the delay; defaults to 1 minute
+	Multiplier      float64        // Growth factor of the delay; defaults to 2
+	OnProgress      func(b *Batch) // Called after every poll, e.g. to report b.RequestCounts
+}
+
+// WaitForBatch polls a batch with exponential backoff until it reaches a terminal status
+// or ctx is done.
+//
+// Parameters:
+//   - ctx: Context bounding the whole wait.
+//   - batchID: The ID of the batch.
+//   - opts: Polling options; nil uses the defaults.
+//
+// Returns:
+//   - *Batch: The last polled state of the batch.
+//   - error: nil if the batch completed, an error wrapping ErrBatchNotCompleted if it
+//     failed, expired or was cancelled, or a polling error or ctx.Err().
+func (c *Client) WaitForBatch(ctx context.Context, batchID string, opts *WaitForBatchOptions) (*Batch, error) {
+	var o WaitForBatchOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.InitialInterval <= 0 {
+		o.InitialInterval = 5 * time.Second
+	}
+	if o.MaxInterval <= 0 {
+		o.MaxInterval = time.Minute
+	}
+	if o.Multiplier < 1 {
+		o.Multiplier = 2
+	}
+
+	interval := o.InitialInterval
+	for {
+		batch, err := c.GetBatch(ctx, batchID)
+		if err != nil {
+			return nil, err
+		}
+		if o.OnProgress != nil {
+			o.OnProgress(batch)
+		}
+
+		if batch.Status.IsTerminal() {
+			if batch.Status != BatchStatusCompleted {
+				return batch, fmt.Errorf("%w: batch %s is %s", ErrBatchNotCompleted, batch.ID, batch.Status)
+			}
+			return batch, nil
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return batch, ctx.Err()
+		case <-timer.C:
+		}
+
+		interval = min(time.Duration(float64(interval)*o.Multiplier), o.MaxInterval)
+	}
+}
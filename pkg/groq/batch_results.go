@@ -0,0 +1,219 @@
+package groq
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+var ErrBatchPartialFailure = errors.New("batch partially failed")
+
+type BatchError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Type    string `json:"type,omitempty"`
+}
+
+// Error returns the error code and message.
+func (e *BatchError) Error() string {
+	if e.Code == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+type BatchResult struct {
+	CustomID    string
+	StatusCode  int
+	RequestID   string
+	Response    *ChatCompletionResponse // Set for successful requests
+	Error       *BatchError             // Set for failed requests
+	Annotations map[string]string       // Set by ApplyAnnotations
+}
+
+// Failed reports whether the request of this result did not produce a completion.
+func (r *BatchResult) Failed() bool {
+	return r.Error != nil || r.Response == nil
+}
+
+type BatchResults struct {
+	Results   map[string]*BatchResult
+	Succeeded []string // Custom IDs of successful requests, sorted
+	Failed    []string // Custom IDs of failed requests, sorted
+}
+
+// Err returns nil if every request succeeded, or an error wrapping ErrBatchPartialFailure
+// that lists the failed custom IDs.
+func (r *BatchResults) Err() error {
+	if len(r.Failed) == 0 {
+		return nil
+	}
+
+	ids := r.Failed
+	suffix := ""
+	if len(ids) > 10 {
+		ids, suffix = ids[:10], fmt.Sprintf(" and %d more", len(r.Failed)-10)
+	}
+	return fmt.Errorf("%w: %d of %d requests failed: %s%s",
+		ErrBatchPartialFailure, len(r.Failed), len(r.Results), strings.Join(ids, ", "), suffix)
+}
+
+// ApplyAnnotations copies the annotations of the requests in b to the results with the
+// same custom_id, so batch reports can be grouped by feature or experiment. Annotations
+// are not part of the batch file, so the builder used to create the batch is needed.
+func (r *BatchResults) ApplyAnnotations(b *BatchBuilder) {
+	for _, line := range b.lines {
+		if result, ok := r.Results[line.CustomID]; ok && line.Body != nil {
+			result.Annotations = line.Body.Annotations
+		}
+	}
+}
+
+// batchOutputLine is one line of a batch output or error file.
+type batchOutputLine struct {
+	ID       string `json:"id"`
+	CustomID string `json:"custom_id"`
+	Response *struct {
+		StatusCode int             `json:"status_code"`
+		RequestID  string          `json:"request_id"`
+		Body       json.RawMessage `json:"body"`
+	} `json:"response"`
+	Error *BatchError `json:"error"`
+}
+
+// ParseBatchResults parses the JSONL content of batch output and error files into typed
+// results keyed by custom_id. Lines from several files can be parsed into the same
+// results by calling Parse on the returned value.
+//
+// Parameters:
+//   - r: The JSONL content.
+//
+// Returns:
+//   - *BatchResults: The parsed results.
+//   - error: An error if a line is not valid JSON.
+func ParseBatchResults(r io.Reader) (*BatchResults, error) {
+	results := &BatchResults{Results: make(map[string]*BatchResult)}
+	if err := results.Parse(r); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// Parse adds the results of JSONL content to r. A later line for the same custom_id
+// replaces the earlier one.
+func (r *BatchResults) Parse(src io.Reader) error {
+	reader := bufio.NewReader(src)
+	lineNo := 0
+
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return fmt.Errorf("failed to read batch results: %w", readErr)
+		}
+		lineNo++
+
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			result, err := parseBatchLine(line)
+			if err != nil {
+				return fmt.Errorf("%w: batch results line %d: %v", ErrJSONDecoding, lineNo, err)
+			}
+			r.Results[result.CustomID] = result
+		}
+
+		if readErr == io.EOF {
+			break
+		}
+	}
+
+	r.Succeeded, r.Failed = r.Succeeded[:0], r.Failed[:0]
+	for id, result := range r.Results {
+		if result.Failed() {
+			r.Failed = append(r.Failed, id)
+		} else {
+			r.Succeeded = append(r.Succeeded, id)
+		}
+	}
+	sort.Strings(r.Succeeded)
+	sort.Strings(r.Failed)
+
+	return nil
+}
+
+// parseBatchLine decodes a single output or error line.
+func parseBatchLine(line []byte) (*BatchResult, error) {
+	var raw batchOutputLine
+	if err := json.Unmarshal(line, &raw); err != nil {
+		return nil, err
+	}
+
+	result := &BatchResult{CustomID: raw.CustomID, Error: raw.Error}
+	if raw.Response == nil {
+		if result.Error == nil {
+			result.Error = &BatchError{Message: "no response"}
+		}
+		return result, nil
+	}
+
+	result.StatusCode = raw.Response.StatusCode
+	result.RequestID = raw.Response.RequestID
+
+	if result.StatusCode >= 200 && result.StatusCode < 300 {
+		var resp ChatCompletionResponse
+		if err := json.Unmarshal(raw.Response.Body, &resp); err != nil {
+			return nil, err
+		}
+		result.Response = &resp
+		return result, nil
+	}
+
+	if result.Error == nil {
+		var body struct {
+			Error *BatchError `json:"error"`
+		}
+		_ = json.Unmarshal(raw.Response.Body, &body)
+		result.Error = body.Error
+		if result.Error == nil {
+			result.Error = &BatchError{Message: fmt.Sprintf("request failed with status %d", result.StatusCode)}
+		}
+	}
+
+	return result, nil
+}
+
+// DownloadBatchResults downloads the output and error files of a batch and parses them
+// into typed results keyed by custom_id. Use BatchResults.Err to detect partial failures.
+//
+// Parameters:
+//   - ctx: Context for the downloads.
+//   - batch: The batch, typically returned by GetBatch once it has completed.
+//
+// Returns:
+//   - *BatchResults: The parsed results of both files.
+//   - error: An error if the batch has no result files or a download fails.
+func (c *Client) DownloadBatchResults(ctx context.Context, batch *Batch) (*BatchResults, error) {
+	if batch.OutputFileID == "" && batch.ErrorFileID == "" {
+		return nil, fmt.Errorf("%w: batch %s has no result files (status: %s)", ErrInvalidRequest, batch.ID, batch.Status)
+	}
+
+	results := &BatchResults{Results: make(map[string]*BatchResult)}
+	for _, fileID := range []string{batch.OutputFileID, batch.ErrorFileID} {
+		if fileID == "" {
+			continue
+		}
+		content, err := c.GetFileContent(ctx, fileID)
+		if err != nil {
+			return nil, err
+		}
+		if err := results.Parse(bytes.NewReader(content)); err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,166 @@
+package groq
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBatchBuilderValidate(t *testing.T) {
+	req := func(model ModelType) *ChatCompletionRequest {
+		return NewRequest(model).User("hi").Build()
+	}
+
+	tests := []struct {
+		name    string
+		builder *BatchBuilder
+	}{
+		{"empty", NewBatchBuilder()},
+		{"duplicate id", NewBatchBuilder().Add("a", req(ModelLlama31_8bInstant)).Add("a", req(ModelLlama31_8bInstant))},
+		{"mixed models", NewBatchBuilder().Add("a", req(ModelLlama31_8bInstant)).Add("b", req(ModelLlama33_70bVersatile))},
+		{"line too large", func() *BatchBuilder {
+			b := NewBatchBuilder().Add("a", NewRequest(ModelLlama31_8bInstant).User(strings.Repeat("x", 200)).Build())
+			b.MaxLineSize = 100
+			return b
+		}()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.builder.Validate(); !errors.Is(err, ErrInvalidBatch) {
+				t.Errorf("Validate() error = %v, want ErrInvalidBatch", err)
+			}
+		})
+	}
+}
+
+func TestUploadBatchInput(t *testing.T) {
+	var lines []BatchRequestLine
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/files" || r.FormValue("purpose") != "batch" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var line BatchRequestLine
+			_ = json.Unmarshal(scanner.Bytes(), &line)
+			lines = append(lines, line)
+		}
+		_ = json.NewEncoder(w).Encode(File{ID: "file_123", Object: "file", Filename: header.Filename, Purpose: FilePurposeBatch})
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key", WithBaseURL(srv.URL))
+	b := NewBatchBuilder().AddAll([]*ChatCompletionRequest{
+		NewRequest(ModelLlama31_8bInstant).User("one").Build(),
+		NewRequest(ModelLlama31_8bInstant).User("two").Build(),
+	})
+
+	file, err := client.UploadBatchInput(context.Background(), b, "")
+	if err != nil {
+		t.Fatalf("UploadBatchInput() error = %v", err)
+	}
+	if file.ID != "file_123" || file.Filename != "batch.jsonl" {
+		t.Errorf("unexpected file: %+v", file)
+	}
+	if len(lines) != 2 || lines[1].CustomID != "request-1" || lines[0].URL != BatchEndpointChatCompletions {
+		t.Errorf("unexpected uploaded lines: %+v", lines)
+	}
+}
+
+func TestDownloadBatchResults(t *testing.T) {
+	output := `{"id":"r1","custom_id":"request-0","response":{"status_code":200,"request_id":"req_1","body":{"id":"c1","choices":[{"message":{"role":"assistant","content":"hello"}}],"usage":{"total_tokens":7}}},"error":null}
+{"id":"r2","custom_id":"request-1","response":{"status_code":400,"request_id":"req_2","body":{"error":{"message":"bad model","type":"invalid_request_error","code":"model_not_found"}}},"error":null}
+`
+	errorsFile := `{"id":"r3","custom_id":"request-2","response":null,"error":{"code":"batch_expired","message":"This request could not be executed before the completion window expired."}}
+`
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/batches/batch_1":
+			_ = json.NewEncoder(w).Encode(Batch{ID: "batch_1", Status: BatchStatusCompleted, OutputFileID: "file_out", ErrorFileID: "file_err"})
+		case "/files/file_out/content":
+			_, _ = w.Write([]byte(output))
+		case "/files/file_err/content":
+			_, _ = w.Write([]byte(errorsFile))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key", WithBaseURL(srv.URL))
+
+	batch, err := client.GetBatch(context.Background(), "batch_1")
+	if err != nil {
+		t.Fatalf("GetBatch() error = %v", err)
+	}
+
+	results, err := client.DownloadBatchResults(context.Background(), batch)
+	if err != nil {
+		t.Fatalf("DownloadBatchResults() error = %v", err)
+	}
+
+	if got := results.Results["request-0"]; got.Failed() || got.Response.Choices[0].Message.Content != "hello" || got.Response.Usage.TotalTokens != 7 {
+		t.Errorf("unexpected success result: %+v", got)
+	}
+	if got := results.Results["request-1"]; !got.Failed() || got.Error.Code != "model_not_found" || got.StatusCode != 400 {
+		t.Errorf("unexpected failed result: %+v", got)
+	}
+	if got := results.Results["request-2"]; !got.Failed() || got.Error.Code != "batch_expired" {
+		t.Errorf("unexpected expired result: %+v", got)
+	}
+
+	if len(results.Succeeded) != 1 || len(results.Failed) != 2 {
+		t.Errorf("unexpected partition: succeeded %v, failed %v", results.Succeeded, results.Failed)
+	}
+	if err := results.Err(); !errors.Is(err, ErrBatchPartialFailure) {
+		t.Errorf("Err() = %v, want ErrBatchPartialFailure", err)
+	}
+}
+
+func TestWaitForBatch(t *testing.T) {
+	var polls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		batch := Batch{ID: "batch_1", Status: BatchStatusInProgress, RequestCounts: BatchRequestCounts{Total: 3, Completed: polls - 1}}
+		if polls == 3 {
+			batch.Status = BatchStatusCompleted
+		}
+		_ = json.NewEncoder(w).Encode(batch)
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key", WithBaseURL(srv.URL))
+
+	var progress []int
+	batch, err := client.WaitForBatch(context.Background(), "batch_1", &WaitForBatchOptions{
+		InitialInterval: time.Millisecond,
+		OnProgress:      func(b *Batch) { progress = append(progress, b.RequestCounts.Completed) },
+	})
+	if err != nil {
+		t.Fatalf("WaitForBatch() error = %v", err)
+	}
+	if batch.Status != BatchStatusCompleted || len(progress) != 3 || progress[2] != 2 {
+		t.Errorf("unexpected result: %+v, progress %v", batch, progress)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	polls = -100
+	if _, err := client.WaitForBatch(ctx, "batch_1", &WaitForBatchOptions{InitialInterval: time.Hour}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline error, got %v", err)
+	}
+}
@@ -0,0 +1,261 @@
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/genc-murat/groq-client/pkg/groq"
+)
+
+type Config struct {
+	Model       groq.ModelType
+	Prompts     []string      // Prompts sent in rotation, one user message each
+	Concurrency int           // Number of requests in flight, defaults to 1
+	Duration    time.Duration // How long new requests are started
+	Requests    int           // Stop after this many requests, 0 for no limit
+	MaxTokens   int           // max_tokens of every request, 0 for the model default
+}
+
+type Percentiles struct {
+	P50 time.Duration
+	P90 time.Duration
+	P95 time.Duration
+	P99 time.Duration
+	Max time.Duration
+}
+
+type Report struct {
+	Model            groq.ModelType
+	Concurrency      int
+	Elapsed          time.Duration
+	Requests         int
+	Errors           int
+	ErrorKinds       map[string]int // Failed requests by kind, e.g. "timeout"
+	Latency          Percentiles    // Latency of successful requests
+	PromptTokens     int
+	CompletionTokens int
+	Cost             float64       // USD, estimated from the model's published prices
+	Retries          int           // Retried attempts of all requests
+	RateLimitWait    time.Duration // Time requests spent waiting for the client's rate limiters
+}
+
+// ErrorRate returns the fraction of requests that failed.
+func (r *Report) ErrorRate() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+	return float64(r.Errors) / float64(r.Requests)
+}
+
+// RequestsPerSecond returns the throughput of the run.
+func (r *Report) RequestsPerSecond() float64 {
+	return perSecond(float64(r.Requests), r.Elapsed)
+}
+
+// TokensPerSecond returns the completion tokens generated per second across all workers.
+func (r *Report) TokensPerSecond() float64 {
+	return perSecond(float64(r.CompletionTokens), r.Elapsed)
+}
+
+// Run benchmarks chat completions against the API: Concurrency workers take the prompts
+// in rotation from a request channel, so Concurrency requests stay in flight until
+// Duration has passed or Requests were started. Requests still in flight when Duration
+// ends are waited for and included. The latencies, token throughput, retries, rate limit
+// waits and cost of the calls are collected with a MetricsRecorder (see
+// groq.ContextWithMetrics) and summarized. Requests bypass the client cache so every one
+// of them reaches the API.
+//
+// Parameters:
+//   - ctx: Context for the run; canceling it aborts the requests in flight.
+//   - client: The client to benchmark, with the rate limits and retries to measure.
+//   - cfg: The benchmark configuration.
+//
+// Returns:
+//   - *Report: The results.
+//   - error: An error if the configuration is invalid.
+func Run(ctx context.Context, client *groq.Client, cfg Config) (*Report, error) {
+	if len(cfg.Prompts) == 0 {
+		return nil, fmt.Errorf("%w: at least one prompt is required", groq.ErrInvalidConfig)
+	}
+	if cfg.Duration <= 0 && cfg.Requests <= 0 {
+		return nil, fmt.Errorf("%w: set Duration or Requests to bound the run", groq.ErrInvalidConfig)
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+
+	report := &Report{Model: cfg.Model, Concurrency: cfg.Concurrency, ErrorKinds: make(map[string]int)}
+	calls := &recorder{report: report}
+	ctx = groq.ContextWithMetrics(groq.ContextWithCachePolicy(ctx, groq.CacheBypass), calls)
+
+	var deadline <-chan time.Time
+	if cfg.Duration > 0 {
+		timer := time.NewTimer(cfg.Duration)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	// The channel is unbuffered, so a request is only started when a worker is free.
+	requests := make(chan *groq.ChatCompletionRequest)
+	go func() {
+		defer close(requests)
+		for i := 0; cfg.Requests <= 0 || i < cfg.Requests; i++ {
+			req := &groq.ChatCompletionRequest{
+				Model:     cfg.Model,
+				Messages:  []groq.ChatMessage{{Role: "user", Content: cfg.Prompts[i%len(cfg.Prompts)]}},
+				MaxTokens: cfg.MaxTokens,
+			}
+			select {
+			case requests <- req:
+			case <-deadline:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	begin := time.Now()
+	var wg sync.WaitGroup
+	for w := 0; w < cfg.Concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for req := range requests {
+				_, err := client.CreateChatCompletion(ctx, req)
+				calls.finish(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	report.Elapsed = time.Since(begin)
+	report.Latency = percentiles(calls.latencies)
+	return report, nil
+}
+
+// recorder adds the metrics of the calls of a run to its report.
+type recorder struct {
+	mu        sync.Mutex
+	report    *Report
+	latencies []time.Duration // Of successful calls
+}
+
+// finish counts a finished request of the run and the kind of its error.
+func (r *recorder) finish(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.report.Requests++
+	if err != nil {
+		r.report.Errors++
+		r.report.ErrorKinds[errorKind(err)]++
+	}
+}
+
+// RecordCall implements groq.MetricsRecorder.
+func (r *recorder) RecordCall(m groq.CallMetrics) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.report.Retries += m.Retries
+	r.report.RateLimitWait += m.RateLimitWait
+	if m.Failed {
+		return
+	}
+	r.latencies = append(r.latencies, m.Latency)
+	r.report.PromptTokens += m.PromptTokens
+	r.report.CompletionTokens += m.CompletionTokens
+	r.report.Cost += groq.EstimateChatCost(m.Model, groq.Usage{PromptTokens: m.PromptTokens, CompletionTokens: m.CompletionTokens})
+}
+
+// WriteText writes a human-readable summary of the report.
+func (r *Report) WriteText(w io.Writer) error {
+	_, err := fmt.Fprintf(w, `Model:        %s
+Concurrency:  %d
+Elapsed:      %v
+Requests:     %d (%.2f/s)
+Errors:       %d (%.1f%%)
+Latency:      p50 %v  p90 %v  p95 %v  p99 %v  max %v
+Tokens:       %d prompt, %d completion (%.1f completion tokens/s)
+Retries:      %d (rate limit wait %v)
+Cost:         $%.6f
+`,
+		r.Model, r.Concurrency, r.Elapsed.Round(time.Millisecond),
+		r.Requests, r.RequestsPerSecond(),
+		r.Errors, r.ErrorRate()*100,
+		r.Latency.P50, r.Latency.P90, r.Latency.P95, r.Latency.P99, r.Latency.Max,
+		r.PromptTokens, r.CompletionTokens, r.TokensPerSecond(),
+		r.Retries, r.RateLimitWait.Round(time.Millisecond),
+		r.Cost,
+	)
+	if err != nil {
+		return err
+	}
+
+	kinds := make([]string, 0, len(r.ErrorKinds))
+	for kind := range r.ErrorKinds {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		if _, err := fmt.Fprintf(w, "  %-12s %d\n", kind+":", r.ErrorKinds[kind]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// errorKind classifies a failed request for the report.
+func errorKind(err error) string {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, groq.ErrInvalidRequest):
+		return "invalid"
+	case errors.Is(err, groq.ErrPolicyViolation), errors.Is(err, groq.ErrContentPolicy):
+		return "policy"
+	case errors.Is(err, groq.ErrRateLimited), errors.Is(err, groq.ErrQuotaExceeded):
+		return "rate_limited"
+	case errors.Is(err, groq.ErrAuthentication):
+		return "auth"
+	default:
+		return "request"
+	}
+}
+
+// percentiles returns nearest-rank percentiles of the latencies.
+func percentiles(latencies []time.Duration) Percentiles {
+	if len(latencies) == 0 {
+		return Percentiles{}
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	rank := func(p float64) time.Duration {
+		i := int(math.Ceil(p/100*float64(len(latencies)))) - 1
+		return latencies[max(i, 0)]
+	}
+	return Percentiles{
+		P50: rank(50),
+		P90: rank(90),
+		P95: rank(95),
+		P99: rank(99),
+		Max: latencies[len(latencies)-1],
+	}
+}
+
+// perSecond divides n by the elapsed time in seconds.
+func perSecond(n float64, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return n / elapsed.Seconds()
+}
@@ -0,0 +1,135 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/genc-murat/groq-client/pkg/groq"
+)
+
+func TestRun(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req groq.ChatCompletionRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if atomic.AddInt32(&calls, 1)%5 == 0 {
+			http.Error(w, `{"error":{"message":"bad"}}`, http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, `{"choices":[{"message":{"role":"assistant","content":"ok"}}],"usage":{"prompt_tokens":10,"completion_tokens":20,"total_tokens":30}}`)
+	}))
+	defer srv.Close()
+
+	client := groq.NewClient("test-key", groq.WithBaseURL(srv.URL), groq.WithRetryConfig(1, time.Millisecond))
+	report, err := Run(context.Background(), client, Config{
+		Model:       groq.ModelLlama31_8bInstant,
+		Prompts:     []string{"a", "b"},
+		Concurrency: 3,
+		Requests:    20,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Requests != 20 || report.Errors == 0 || report.Errors == 20 {
+		t.Fatalf("unexpected counts: %d requests, %d errors", report.Requests, report.Errors)
+	}
+	ok := report.Requests - report.Errors
+	if report.CompletionTokens != ok*20 || report.PromptTokens != ok*10 {
+		t.Errorf("unexpected tokens: %+v", report)
+	}
+	if want := groq.EstimateChatCost(groq.ModelLlama31_8bInstant, groq.Usage{PromptTokens: ok * 10, CompletionTokens: ok * 20}); report.Cost < want*0.999 || report.Cost > want*1.001 {
+		t.Errorf("Cost = %v, want %v", report.Cost, want)
+	}
+	if report.Latency.P50 <= 0 || report.Latency.P99 > report.Latency.Max {
+		t.Errorf("unexpected latency: %+v", report.Latency)
+	}
+
+	var out bytes.Buffer
+	if err := report.WriteText(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Requests:     20") {
+		t.Errorf("unexpected report:\n%s", out.String())
+	}
+}
+
+func TestRunCountsRetries(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			http.Error(w, `{"error":{"message":"busy"}}`, http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintf(w, `{"choices":[{"message":{"role":"assistant","content":"ok"}}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`)
+	}))
+	defer srv.Close()
+
+	client := groq.NewClient("test-key", groq.WithBaseURL(srv.URL), groq.WithRetryConfig(2, time.Millisecond))
+	report, err := Run(context.Background(), client, Config{
+		Model:    groq.ModelLlama31_8bInstant,
+		Prompts:  []string{"a"},
+		Requests: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Requests != 2 || report.Errors != 0 || report.Retries != 1 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+}
+
+func TestRunKeepsConcurrencyInFlight(t *testing.T) {
+	var calls, doneDuringSlow int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			time.Sleep(300 * time.Millisecond)
+			atomic.StoreInt32(&doneDuringSlow, atomic.LoadInt32(&calls)-1)
+		}
+		fmt.Fprintf(w, `{"choices":[{"message":{"role":"assistant","content":"ok"}}]}`)
+	}))
+	defer srv.Close()
+
+	client := groq.NewClient("test-key", groq.WithBaseURL(srv.URL))
+	report, err := Run(context.Background(), client, Config{
+		Model:       groq.ModelLlama31_8bInstant,
+		Prompts:     []string{"a"},
+		Concurrency: 2,
+		Requests:    6,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The second worker keeps sending while the first waits for the slow reply.
+	if report.Requests != 6 || atomic.LoadInt32(&doneDuringSlow) != 5 {
+		t.Errorf("%d of %d requests were sent during the slow one, want 5", atomic.LoadInt32(&doneDuringSlow), report.Requests)
+	}
+}
+
+func TestRunRequiresBound(t *testing.T) {
+	_, err := Run(context.Background(), groq.NewClient("test-key"), Config{Prompts: []string{"a"}})
+	if !errors.Is(err, groq.ErrInvalidConfig) {
+		t.Fatalf("Run() error = %v", err)
+	}
+}
+
+func TestPercentiles(t *testing.T) {
+	var latencies []time.Duration
+	for i := 100; i >= 1; i-- {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+
+	p := percentiles(latencies)
+	if p.P50 != 50*time.Millisecond || p.P90 != 90*time.Millisecond || p.P99 != 99*time.Millisecond || p.Max != 100*time.Millisecond {
+		t.Errorf("unexpected percentiles: %+v", p)
+	}
+}
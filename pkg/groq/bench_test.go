@@ -0,0 +1,62 @@
+package groq
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// unlimited is a RateLimiter that never waits.
+type unlimited struct{}
+
+func (unlimited) Wait(context.Context) error { return nil }
+
+func BenchmarkCreateChatCompletion(b *testing.B) {
+	reply := `{"id":"1","choices":[{"message":{"role":"assistant","content":"` + strings.Repeat("answer ", 200) + `"},"finish_reason":"stop"}]}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(reply))
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key", WithBaseURL(srv.URL), WithRateLimiter(unlimited{}))
+	defer client.Close()
+	req := NewRequest(ModelLlama31_8bInstant).User(strings.Repeat("context ", 500)).Build()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.CreateChatCompletion(context.Background(), req); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCreateChatCompletionStream(b *testing.B) {
+	var stream strings.Builder
+	for i := 0; i < 200; i++ {
+		fmt.Fprintf(&stream, "data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"token%d \"}}]}\n\n", i)
+	}
+	stream.WriteString("data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"delta\":{},\"finish_reason\":\"stop\"}]}\n\ndata: [DONE]\n\n")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(stream.String()))
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key", WithBaseURL(srv.URL), WithRateLimiter(unlimited{}))
+	defer client.Close()
+	req := NewRequest(ModelLlama31_8bInstant).User("hi").Build()
+	handler := func(*ChatCompletionChunk) error { return nil }
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := client.CreateChatCompletionStream(context.Background(), req, handler); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package groq
 
 import (
 	"context"
+	"time"
 )
 
 type Cache interface {
@@ -12,6 +13,22 @@ type Cache interface {
 	GetStats() CacheStats
 }
 
+// TTLCache is implemented by caches that can store an entry with its own time-to-live
+// instead of their default. CreateChatCompletion uses it for requests whose context
+// carries a TTL, see ContextWithCacheTTL. A ttl of 0 or less uses the default.
+type TTLCache interface {
+	SetWithTTL(ctx context.Context, key string, value *ChatCompletionResponse, ttl time.Duration) error
+}
+
+// TTLReporter is implemented by caches that can tell how much longer the entry a lookup
+// found is kept. Tiered caches use it to copy entries into faster tiers without letting
+// the copies outlive the original.
+type TTLReporter interface {
+	// GetWithTTL is like Get and also returns the remaining TTL of the entry, 0 if it
+	// does not expire.
+	GetWithTTL(ctx context.Context, key string) (*ChatCompletionResponse, time.Duration, bool)
+}
+
 type CacheStats struct {
 	Hits      int64
 	Misses    int64
@@ -31,3 +48,99 @@ func WithCache(cache Cache) Option {
 		c.cache = cache
 	}
 }
+
+// WithSharedCache marks the cache of WithCache as shared with other clients: Close then
+// leaves it open, and its owner closes it once no client uses it anymore.
+func WithSharedCache() Option {
+	return func(c *Client) {
+		c.cacheShared = true
+	}
+}
+
+type CachePolicy int
+
+const (
+	// CacheDefault reads from and writes to the cache.
+	CacheDefault CachePolicy = iota
+	// CacheBypass neither reads from nor writes to the cache.
+	CacheBypass
+	// CacheRefresh skips the cache lookup but stores the fresh response.
+	CacheRefresh
+	// CacheReadOnly serves cached responses but does not store new ones.
+	CacheReadOnly
+)
+
+type cachePolicyKey struct{}
+
+// ContextWithCachePolicy returns a copy of ctx that makes chat completions issued with it
+// follow policy instead of the default read-through caching.
+//
+// Example usage:
+//
+//	ctx := ContextWithCachePolicy(ctx, CacheBypass)
+//	resp, err := client.CreateChatCompletion(ctx, req)
+func ContextWithCachePolicy(ctx context.Context, policy CachePolicy) context.Context {
+	return context.WithValue(ctx, cachePolicyKey{}, policy)
+}
+
+// cachePolicyFrom returns the cache policy stored in ctx, or CacheDefault.
+func cachePolicyFrom(ctx context.Context) CachePolicy {
+	if policy, ok := ctx.Value(cachePolicyKey{}).(CachePolicy); ok {
+		return policy
+	}
+	return CacheDefault
+}
+
+type cacheNamespaceKey struct{}
+
+// ContextWithCacheNamespace returns a copy of ctx whose chat completions are cached under
+// namespace. Entries of different namespaces never collide, and namespaces can have
+// their own stale-while-revalidate policy.
+func ContextWithCacheNamespace(ctx context.Context, namespace string) context.Context {
+	return context.WithValue(ctx, cacheNamespaceKey{}, namespace)
+}
+
+// cacheNamespaceFrom returns the cache namespace stored in ctx, or "".
+func cacheNamespaceFrom(ctx context.Context) string {
+	namespace, _ := ctx.Value(cacheNamespaceKey{}).(string)
+	return namespace
+}
+
+type cacheTTLKey struct{}
+
+// ContextWithCacheTTL returns a copy of ctx whose chat completions are cached for ttl
+// instead of the cache's default TTL, so volatile questions such as "what's the latest
+// news" can expire within minutes while stable ones are kept for days. Caches that do
+// not implement TTLCache store the entry with their default TTL.
+//
+// Example usage:
+//
+//	ctx := ContextWithCacheTTL(ctx, 5*time.Minute)
+//	resp, err := client.CreateChatCompletion(ctx, req)
+func ContextWithCacheTTL(ctx context.Context, ttl time.Duration) context.Context {
+	return context.WithValue(ctx, cacheTTLKey{}, ttl)
+}
+
+// cacheTTLFrom returns the cache TTL stored in ctx, or 0 for the cache's default.
+func cacheTTLFrom(ctx context.Context) time.Duration {
+	ttl, _ := ctx.Value(cacheTTLKey{}).(time.Duration)
+	return ttl
+}
+
+// setCacheEntry stores value in cache, with the TTL of ctx if the cache supports it.
+func setCacheEntry(ctx context.Context, cache Cache, key string, value *ChatCompletionResponse) error {
+	if ttl := cacheTTLFrom(ctx); ttl > 0 {
+		if ttlCache, ok := cache.(TTLCache); ok {
+			return ttlCache.SetWithTTL(ctx, key, value, ttl)
+		}
+	}
+	return cache.Set(ctx, key, value)
+}
+
+// namespacedCacheKey prefixes key with the namespace, if any.
+func namespacedCacheKey(namespace, key string) string {
+	if namespace == "" {
+		return key
+	}
+	return namespace + "\x00" + key
+}
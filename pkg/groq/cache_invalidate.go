@@ -0,0 +1,158 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrPartialInvalidation = errors.New("cache invalidation partially failed")
+	ErrUnsupportedMatcher  = errors.New("cache does not support this matcher")
+)
+
+// CacheKeyLister is implemented by caches that can enumerate their keys. It is required
+// for prefix and namespace invalidation.
+type CacheKeyLister interface {
+	Keys(ctx context.Context) ([]string, error)
+}
+
+// SimilarityInvalidator is implemented by caches that can delete entries semantically
+// similar to a query. It is required for similarity invalidation.
+type SimilarityInvalidator interface {
+	DeleteSimilar(ctx context.Context, query string, threshold float32) ([]string, error)
+}
+
+type matcherKind int
+
+const (
+	matchKey matcherKind = iota
+	matchRequest
+	matchPrefix
+	matchNamespace
+	matchSimilar
+)
+
+type CacheMatcher struct {
+	kind      matcherKind
+	value     string
+	threshold float32
+	request   *ChatCompletionRequest
+}
+
+// MatchKey matches the entries cached for exactly this prompt, whatever the model,
+// conversation and parameters they were asked with.
+func MatchKey(key string) CacheMatcher {
+	return CacheMatcher{kind: matchKey, value: key}
+}
+
+// MatchRequest matches the entry cached for req only, see CacheKey and
+// WithConversationCacheKeys. Unlike the other matchers it works with caches that cannot
+// list their keys.
+func MatchRequest(req *ChatCompletionRequest) CacheMatcher {
+	return CacheMatcher{kind: matchRequest, request: req}
+}
+
+// MatchPrefix matches all entries whose prompt starts with prefix.
+func MatchPrefix(prefix string) CacheMatcher {
+	return CacheMatcher{kind: matchPrefix, value: prefix}
+}
+
+// MatchNamespace matches all entries of a cache namespace (see ContextWithCacheNamespace).
+func MatchNamespace(namespace string) CacheMatcher {
+	return CacheMatcher{kind: matchNamespace, value: namespace}
+}
+
+// MatchSimilar matches entries whose prompt is semantically similar to query, with a
+// cosine similarity of at least threshold.
+func MatchSimilar(query string, threshold float32) CacheMatcher {
+	return CacheMatcher{kind: matchSimilar, value: query, threshold: threshold}
+}
+
+type InvalidationResult struct {
+	Deleted []string         // Keys that were removed
+	Failed  map[string]error // Keys whose deletion failed
+}
+
+// InvalidateCache removes the cache entries selected by matcher, so stale answers can be
+// purged surgically, e.g. after a knowledge update, instead of clearing the whole cache.
+// Key, request, prefix and similarity matchers apply to the cache namespace of ctx.
+//
+// Key, prefix and namespace matchers need a cache implementing CacheKeyLister; similarity
+// matchers need a SimilarityInvalidator. Deletion continues past individual failures.
+//
+// Parameters:
+//   - ctx: Context for the cache operations, optionally with a cache namespace.
+//   - matcher: Selects the entries, see MatchKey, MatchRequest, MatchPrefix, MatchNamespace
+//     and MatchSimilar.
+//
+// Returns:
+//   - *InvalidationResult: The deleted keys and the keys that could not be deleted.
+//   - error: ErrUnsupportedMatcher, a listing error, or an error wrapping
+//     ErrPartialInvalidation if some deletions failed.
+func (c *Client) InvalidateCache(ctx context.Context, matcher CacheMatcher) (*InvalidationResult, error) {
+	result := &InvalidationResult{Failed: make(map[string]error)}
+	if c.cache == nil {
+		return result, nil
+	}
+
+	namespace := cacheNamespaceFrom(ctx)
+
+	var candidates []string
+	switch matcher.kind {
+	case matchRequest:
+		req := c.withDefaultModel(matcher.request)
+		if c.safety != nil {
+			req = c.safety.apply(req)
+		}
+		candidates = []string{c.cacheKey(ctx, req)}
+
+	case matchSimilar:
+		invalidator, ok := c.cache.(SimilarityInvalidator)
+		if !ok {
+			return result, fmt.Errorf("%w: similarity matching", ErrUnsupportedMatcher)
+		}
+		deleted, err := invalidator.DeleteSimilar(ctx, namespacedCacheKey(namespace, matcher.value), matcher.threshold)
+		result.Deleted = deleted
+		if err != nil {
+			return result, fmt.Errorf("%w: %v", ErrPartialInvalidation, err)
+		}
+		return result, nil
+
+	case matchKey, matchPrefix, matchNamespace:
+		lister, ok := c.cache.(CacheKeyLister)
+		if !ok {
+			return result, fmt.Errorf("%w: cache cannot list its keys", ErrUnsupportedMatcher)
+		}
+		keys, err := lister.Keys(ctx)
+		if err != nil {
+			return result, fmt.Errorf("failed to list cache keys: %w", err)
+		}
+
+		for _, key := range keys {
+			parts := ParseCacheKey(key)
+			switch {
+			case matcher.kind == matchNamespace && parts.Namespace == matcher.value:
+				candidates = append(candidates, key)
+			case matcher.kind == matchKey && parts.Namespace == namespace && parts.LastTurn() == matcher.value:
+				candidates = append(candidates, key)
+			case matcher.kind == matchPrefix && parts.Namespace == namespace && strings.HasPrefix(parts.LastTurn(), matcher.value):
+				candidates = append(candidates, key)
+			}
+		}
+	}
+
+	for _, key := range candidates {
+		if err := c.cache.Delete(ctx, key); err != nil {
+			result.Failed[key] = err
+			continue
+		}
+		result.Deleted = append(result.Deleted, key)
+	}
+
+	if len(result.Failed) > 0 {
+		return result, fmt.Errorf("%w: %d of %d deletions failed", ErrPartialInvalidation, len(result.Failed), len(candidates))
+	}
+	return result, nil
+}
@@ -0,0 +1,77 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+)
+
+// flakyCache fails to delete the keys listed in failing.
+type flakyCache struct {
+	*mapCache
+	failing map[string]bool
+}
+
+func (f *flakyCache) Delete(ctx context.Context, key string) error {
+	if f.failing[key] {
+		return errors.New("backend unavailable")
+	}
+	return f.mapCache.Delete(ctx, key)
+}
+
+func TestInvalidateCache(t *testing.T) {
+	ctx := context.Background()
+	docs := ContextWithCacheNamespace(ctx, "docs")
+
+	cache := newMapCache()
+	for _, key := range []string{
+		"pricing for 2024",
+		"pricing for 2025",
+		"weather",
+		namespacedCacheKey("docs", "pricing page"),
+		namespacedCacheKey("docs", "install guide"),
+	} {
+		_ = cache.Set(ctx, key, &ChatCompletionResponse{})
+	}
+	client := NewClient("test-key", WithCache(cache))
+
+	result, err := client.InvalidateCache(ctx, MatchPrefix("pricing"))
+	sort.Strings(result.Deleted)
+	if err != nil || len(result.Deleted) != 2 || result.Deleted[0] != "pricing for 2024" {
+		t.Fatalf("prefix invalidation = %+v, %v", result, err)
+	}
+
+	if result, err := client.InvalidateCache(docs, MatchKey("install guide")); err != nil || len(result.Deleted) != 1 {
+		t.Fatalf("key invalidation = %+v, %v", result, err)
+	}
+
+	if result, err := client.InvalidateCache(ctx, MatchNamespace("docs")); err != nil || len(result.Deleted) != 1 {
+		t.Fatalf("namespace invalidation = %+v, %v", result, err)
+	}
+
+	if _, err := client.InvalidateCache(ctx, MatchSimilar("weather", 0.9)); !errors.Is(err, ErrUnsupportedMatcher) {
+		t.Errorf("expected ErrUnsupportedMatcher, got %v", err)
+	}
+
+	if cache.GetStats().ItemCount != 1 {
+		t.Errorf("expected only the unrelated entry to remain, got %d entries", cache.GetStats().ItemCount)
+	}
+}
+
+func TestInvalidateCachePartialFailure(t *testing.T) {
+	ctx := context.Background()
+	cache := &flakyCache{mapCache: newMapCache(), failing: map[string]bool{"a2": true}}
+	for _, key := range []string{"a1", "a2", "a3"} {
+		_ = cache.Set(ctx, key, &ChatCompletionResponse{})
+	}
+	client := NewClient("test-key", WithCache(cache))
+
+	result, err := client.InvalidateCache(ctx, MatchPrefix("a"))
+	if !errors.Is(err, ErrPartialInvalidation) {
+		t.Fatalf("expected ErrPartialInvalidation, got %v", err)
+	}
+	if len(result.Deleted) != 2 || result.Failed["a2"] == nil {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
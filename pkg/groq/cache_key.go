@@ -0,0 +1,217 @@
+package groq
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/genc-murat/groq-client/internal/util"
+)
+
+// fingerprintLength is the number of hex digits of the request fingerprint in cache keys.
+const fingerprintLength = 16
+
+// TurnSeparator separates the turns in the prompt of a key built by ConversationCacheKey.
+const TurnSeparator = "\x1e"
+
+type CacheKeyParts struct {
+	Namespace   string // Cache namespace, see ContextWithCacheNamespace
+	Fingerprint string // Hash of everything but the prompt that shapes the answer
+	Prompt      string // Text of the last message, or of the last turns separated by TurnSeparator
+}
+
+// Scope returns the part of the key that must match exactly for a cached answer to be
+// reused: the namespace and the fingerprint. Semantic caches compare only the prompts
+// of entries in the same scope.
+func (p CacheKeyParts) Scope() string {
+	return p.Namespace + "\x00" + p.Fingerprint
+}
+
+// Turns returns the texts of the turns in the prompt, oldest first. Keys built by
+// CacheKey have a single turn, the last message.
+func (p CacheKeyParts) Turns() []string {
+	return strings.Split(p.Prompt, TurnSeparator)
+}
+
+// LastTurn returns the text of the last message.
+func (p CacheKeyParts) LastTurn() string {
+	return p.Prompt[strings.LastIndex(p.Prompt, TurnSeparator)+1:]
+}
+
+// CacheKey returns the key CreateChatCompletion caches the response to req under. It is
+// made of the namespace, a fingerprint of the model, the system prompt, the earlier
+// messages, the images and tools of the request and its generation parameters, and the
+// text of the last message. The same question asked to another model, with another
+// temperature or system prompt, or later in another conversation therefore gets its own
+// entry, while semantic caches can still match the question itself, see ParseCacheKey.
+//
+// Parameters:
+//   - namespace: The cache namespace, "" for none.
+//   - req: The request.
+//
+// Returns:
+//   - string: The cache key.
+func CacheKey(namespace string, req *ChatCompletionRequest) string {
+	var prompt string
+	if n := len(req.Messages); n > 0 {
+		prompt = req.Messages[n-1].GetCacheKey()
+	}
+	return namespace + "\x00" + requestFingerprint(req) + "\x00" + prompt
+}
+
+// ConversationCacheKey is like CacheKey but puts the text of the last maxTurns messages
+// other than system messages in the prompt, separated by TurnSeparator, instead of
+// hashing the earlier ones into the fingerprint. Semantic caches then compare whole
+// conversations, weighting recent turns higher, so the same final question asked in two
+// different conversations does not match, while a conversation phrased slightly
+// differently still can. Older turns are left out of the key.
+//
+// Parameters:
+//   - namespace: The cache namespace, "" for none.
+//   - req: The request.
+//   - maxTurns: The number of messages to keep, 0 for all.
+//
+// Returns:
+//   - string: The cache key.
+func ConversationCacheKey(namespace string, req *ChatCompletionRequest, maxTurns int) string {
+	var system, turns []ChatMessage
+	for _, msg := range req.Messages {
+		if msg.Role == "system" {
+			system = append(system, msg)
+		} else {
+			turns = append(turns, msg)
+		}
+	}
+	if maxTurns > 0 && len(turns) > maxTurns {
+		turns = turns[len(turns)-maxTurns:]
+	}
+
+	texts := make([]string, len(turns))
+	shapes := make([]ChatMessage, len(turns))
+	for i, msg := range turns {
+		texts[i] = strings.ReplaceAll(msg.GetCacheKey(), TurnSeparator, " ")
+		shapes[i] = withoutText(msg)
+	}
+	return namespace + "\x00" + fingerprint(req, system, nil, shapes) + "\x00" + strings.Join(texts, TurnSeparator)
+}
+
+// WithConversationCacheKeys caches chat completions under ConversationCacheKey instead
+// of CacheKey, so semantic caches match whole conversations rather than final questions.
+// How much earlier turns count is set on the cache, e.g. semantic_cache.Config.TurnDecay.
+//
+// Example usage:
+//
+//	client := NewClient(apiKey, WithCache(semanticCache), WithConversationCacheKeys(6))
+//
+// Parameters:
+//   - maxTurns: The number of most recent messages in the key, 0 for all.
+//
+// Returns:
+//   - Option: A function that enables conversation cache keys.
+func WithConversationCacheKeys(maxTurns int) Option {
+	return func(c *Client) {
+		c.conversationKeys = true
+		c.conversationTurns = maxTurns
+	}
+}
+
+// cacheKey returns the key the response to req is cached under in the namespace of ctx,
+// see WithConversationCacheKeys. Calls made with WithRequestAPIKey hash the key into the
+// fingerprint, so a tenant never gets an answer cached for another key.
+func (c *Client) cacheKey(ctx context.Context, req *ChatCompletionRequest) string {
+	namespace := cacheNamespaceFrom(ctx)
+	var key string
+	if c.conversationKeys {
+		key = ConversationCacheKey(namespace, req, c.conversationTurns)
+	} else {
+		key = CacheKey(namespace, req)
+	}
+
+	apiKey := util.RequestAPIKey(ctx)
+	if apiKey == "" {
+		return key
+	}
+	parts := ParseCacheKey(key)
+	sum := sha256.Sum256([]byte(parts.Fingerprint + "\x00" + apiKey))
+	return parts.Namespace + "\x00" + hex.EncodeToString(sum[:])[:fingerprintLength] + "\x00" + parts.Prompt
+}
+
+// ParseCacheKey splits a key built by CacheKey into its parts. Keys of the form
+// namespace "\x00" prompt, as used for invalidation, have no fingerprint, and keys
+// without separators are a bare prompt.
+//
+// Parameters:
+//   - key: The cache key.
+//
+// Returns:
+//   - CacheKeyParts: The namespace, fingerprint and prompt of the key.
+func ParseCacheKey(key string) CacheKeyParts {
+	parts := strings.SplitN(key, "\x00", 3)
+	switch len(parts) {
+	case 3:
+		return CacheKeyParts{Namespace: parts[0], Fingerprint: parts[1], Prompt: parts[2]}
+	case 2:
+		return CacheKeyParts{Namespace: parts[0], Prompt: parts[1]}
+	}
+	return CacheKeyParts{Prompt: key}
+}
+
+// requestFingerprint hashes the parts of req other than the text of the last message that
+// change the answer. Streaming and annotations do not.
+func requestFingerprint(req *ChatCompletionRequest) string {
+	n := len(req.Messages)
+	if n == 0 {
+		return fingerprint(req, nil, nil, nil)
+	}
+	last := withoutText(req.Messages[n-1])
+	return fingerprint(req, req.Messages[:n-1], &last, nil)
+}
+
+// fingerprint hashes the model, generation parameters and tools of req together with the
+// messages that are part of the fingerprint: the history and last message of CacheKey,
+// or the system messages and turns without text of ConversationCacheKey.
+func fingerprint(req *ChatCompletionRequest, history []ChatMessage, last *ChatMessage, turns []ChatMessage) string {
+	shape := struct {
+		Model       ModelType     `json:"model"`
+		History     []ChatMessage `json:"history,omitempty"`
+		Last        *ChatMessage  `json:"last,omitempty"`
+		Turns       []ChatMessage `json:"turns,omitempty"`
+		MaxTokens   int           `json:"max_tokens,omitempty"`
+		Temperature *float64      `json:"temperature,omitempty"`
+		TopP        *float64      `json:"top_p,omitempty"`
+		Tools       []Tool        `json:"tools,omitempty"`
+	}{
+		Model:       req.Model,
+		History:     history,
+		Last:        last,
+		Turns:       turns,
+		MaxTokens:   req.MaxTokens,
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+		Tools:       req.Tools,
+	}
+
+	data, _ := json.Marshal(shape)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:fingerprintLength]
+}
+
+// withoutText returns msg without its text, which is the prompt of the cache key, but
+// with its role, tool calls and images, which change the answer.
+func withoutText(msg ChatMessage) ChatMessage {
+	switch content := msg.Content.(type) {
+	case []ContentType:
+		var rest []ContentType
+		for _, part := range content {
+			if part.Type != "text" {
+				rest = append(rest, part)
+			}
+		}
+		msg.Content = rest
+	default:
+		msg.Content = nil
+	}
+	return msg
+}
@@ -0,0 +1,128 @@
+package groq
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCacheKey(t *testing.T) {
+	base := func() *RequestBuilder {
+		return NewRequest(ModelLlama31_8bInstant).System("be brief").User("what is go?")
+	}
+	key := CacheKey("", base().Build())
+
+	for name, req := range map[string]*ChatCompletionRequest{
+		"model":         NewRequest(ModelLlama33_70bVersatile).System("be brief").User("what is go?").Build(),
+		"system prompt": NewRequest(ModelLlama31_8bInstant).System("be verbose").User("what is go?").Build(),
+		"temperature":   base().Temperature(0.2).Build(),
+		"max tokens":    base().MaxTokens(10).Build(),
+		"history":       NewRequest(ModelLlama31_8bInstant).System("be brief").User("hi").Assistant("hello").User("what is go?").Build(),
+		"image":         base().WithImage("https://example.com/go.png").Build(),
+		"prompt":        base().Build(),
+	} {
+		if name == "prompt" {
+			req.Messages[len(req.Messages)-1].Content = "what is rust?"
+		}
+		if CacheKey("", req) == key {
+			t.Errorf("changing the %s did not change the key", name)
+		}
+	}
+
+	same := base().Annotate("team", "search").Build()
+	same.Stream = true
+	if CacheKey("", same) != key {
+		t.Error("streaming and annotations must not change the key")
+	}
+
+	parts := ParseCacheKey(CacheKey("docs", base().Build()))
+	if parts.Namespace != "docs" || parts.Prompt != "what is go?" || len(parts.Fingerprint) != fingerprintLength {
+		t.Errorf("ParseCacheKey() = %+v", parts)
+	}
+	if parts := ParseCacheKey("plain prompt"); parts.Prompt != "plain prompt" || parts.Scope() != "\x00" {
+		t.Errorf("ParseCacheKey() of a bare prompt = %+v", parts)
+	}
+}
+
+func TestCacheKeySeparatesModels(t *testing.T) {
+	var calls atomic.Int32
+	srv := newTestServer(t, func(req *ChatCompletionRequest) string {
+		calls.Add(1)
+		return string(req.Model)
+	})
+	cache := newMapCache()
+	client := NewClient("test-key", WithBaseURL(srv.URL), WithCache(cache))
+	ctx := context.Background()
+
+	ask := func(model ModelType) string {
+		t.Helper()
+		resp, err := client.CreateChatCompletion(ctx, NewRequest(model).User("which model are you?").Build())
+		if err != nil {
+			t.Fatalf("CreateChatCompletion() error = %v", err)
+		}
+		return resp.Choices[0].Message.Content.(string)
+	}
+
+	if got := ask(ModelLlama31_8bInstant); got != string(ModelLlama31_8bInstant) {
+		t.Fatalf("first answer = %q", got)
+	}
+	if got := ask(ModelLlama33_70bVersatile); got != string(ModelLlama33_70bVersatile) {
+		t.Errorf("another model got the cached answer %q", got)
+	}
+	if ask(ModelLlama31_8bInstant); calls.Load() != 2 {
+		t.Errorf("expected the repeated question to hit the cache, got %d calls", calls.Load())
+	}
+
+	result, err := client.InvalidateCache(ctx, MatchRequest(NewRequest(ModelLlama31_8bInstant).User("which model are you?").Build()))
+	if err != nil || len(result.Deleted) != 1 {
+		t.Fatalf("request invalidation = %+v, %v", result, err)
+	}
+	result, err = client.InvalidateCache(ctx, MatchKey("which model are you?"))
+	if err != nil || len(result.Deleted) != 1 || cache.GetStats().ItemCount != 0 {
+		t.Errorf("key invalidation = %+v, %v", result, err)
+	}
+}
+
+func TestConversationCacheKey(t *testing.T) {
+	paris := NewRequest(ModelLlama31_8bInstant).System("be brief").
+		User("Plan a trip to Paris").Assistant("Sure").User("What should I pack?").Build()
+	alps := NewRequest(ModelLlama31_8bInstant).System("be brief").
+		User("Recommend ski resorts in the Alps").Assistant("Sure").User("What should I pack?").Build()
+
+	parts := ParseCacheKey(ConversationCacheKey("", paris, 0))
+	if got := parts.Turns(); len(got) != 3 || got[0] != "Plan a trip to Paris" || got[2] != "What should I pack?" {
+		t.Errorf("Turns() = %q", got)
+	}
+	if parts.LastTurn() != "What should I pack?" {
+		t.Errorf("LastTurn() = %q", parts.LastTurn())
+	}
+	other := ParseCacheKey(ConversationCacheKey("", alps, 0))
+	if other.Scope() != parts.Scope() || other.Prompt == parts.Prompt {
+		t.Errorf("conversations must share the scope and differ in the prompt: %+v, %+v", parts, other)
+	}
+	if ConversationCacheKey("", paris, 1) != ConversationCacheKey("", alps, 1) {
+		t.Error("turns beyond maxTurns must not change the key")
+	}
+	if ParseCacheKey(CacheKey("", paris)).Scope() == parts.Scope() {
+		t.Error("conversation keys must not share the scope of CacheKey")
+	}
+
+	srv := newTestServer(t, func(req *ChatCompletionRequest) string { return "a coat" })
+	cache := newMapCache()
+	client := NewClient("test-key", WithBaseURL(srv.URL), WithCache(cache), WithConversationCacheKeys(4))
+	ctx := context.Background()
+	if _, err := client.CreateChatCompletion(ctx, paris); err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+	if _, ok := cache.Get(ctx, ConversationCacheKey("", paris, 4)); !ok {
+		t.Error("expected the response under the conversation key")
+	}
+	result, err := client.InvalidateCache(ctx, MatchKey("What should I pack?"))
+	if err != nil || len(result.Deleted) != 1 {
+		t.Errorf("key invalidation = %+v, %v; want the entry with that last turn", result, err)
+	}
+
+	if _, err := NewClientE("test-key", WithConversationCacheKeys(-1)); err == nil {
+		t.Error("NewClientE() accepted a negative number of turns")
+	}
+}
@@ -0,0 +1,178 @@
+package groq
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// LRUCache is an exact-match in-process cache: a response is only returned for the very
+// key it was stored under. It evicts the least recently used entry when it is full and
+// needs neither embeddings nor a server, which makes it the predictable default. It is
+// safe for concurrent use.
+type LRUCache struct {
+	entries    map[string]*list.Element
+	recency    *list.List // Front is the most recently used entry
+	maxEntries int
+	ttl        time.Duration
+	size       int
+	hits       int64
+	misses     int64
+	mu         sync.Mutex
+}
+
+type lruEntry struct {
+	key       string
+	response  *ChatCompletionResponse
+	expiresAt time.Time
+	size      int
+}
+
+// NewLRUCache creates an exact-match cache of at most maxEntries entries that keeps
+// entries for ttl. It is also the cache of the Cache section of config files.
+//
+// Example usage:
+//
+//	client := NewClient(apiKey, WithCache(NewLRUCache(1000, time.Hour)))
+//
+// Parameters:
+//   - maxEntries: The maximum number of entries, 0 for no limit.
+//   - ttl: How long entries are kept, 0 for no expiry.
+//
+// Returns:
+//   - *LRUCache: The cache.
+func NewLRUCache(maxEntries int, ttl time.Duration) *LRUCache {
+	return &LRUCache{
+		entries:    make(map[string]*list.Element),
+		recency:    list.New(),
+		maxEntries: maxEntries,
+		ttl:        ttl,
+	}
+}
+
+// Get returns the entry stored under key if it has not expired.
+func (m *LRUCache) Get(ctx context.Context, key string) (*ChatCompletionResponse, bool) {
+	resp, _, ok := m.GetWithTTL(ctx, key)
+	return resp, ok
+}
+
+// GetWithTTL is like Get and also returns how much longer the entry is kept, 0 if it
+// does not expire, implementing TTLReporter.
+func (m *LRUCache) GetWithTTL(ctx context.Context, key string) (*ChatCompletionResponse, time.Duration, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	elem, ok := m.entries[key]
+	if ok {
+		entry := elem.Value.(*lruEntry)
+		if !entry.expiresAt.IsZero() && now.After(entry.expiresAt) {
+			m.remove(elem)
+			ok = false
+		}
+	}
+	if !ok {
+		m.misses++
+		return nil, 0, false
+	}
+	m.hits++
+	m.recency.MoveToFront(elem)
+
+	entry := elem.Value.(*lruEntry)
+	var ttl time.Duration
+	if !entry.expiresAt.IsZero() {
+		ttl = max(entry.expiresAt.Sub(now), time.Nanosecond)
+	}
+	return entry.response, ttl, true
+}
+
+// Set stores value under key, evicting the least recently used entry if the cache is full.
+func (m *LRUCache) Set(ctx context.Context, key string, value *ChatCompletionResponse) error {
+	return m.SetWithTTL(ctx, key, value, 0)
+}
+
+// SetWithTTL is like Set but keeps the entry for ttl instead of the cache's TTL,
+// implementing TTLCache.
+func (m *LRUCache) SetWithTTL(ctx context.Context, key string, value *ChatCompletionResponse, ttl time.Duration) error {
+	if ttl <= 0 {
+		ttl = m.ttl
+	}
+	size := 0
+	if data, err := json.Marshal(value); err == nil {
+		size = len(data)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if elem, ok := m.entries[key]; ok {
+		m.remove(elem)
+	}
+	entry := &lruEntry{key: key, response: value, size: size}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+	m.entries[key] = m.recency.PushFront(entry)
+	m.size += size
+
+	for m.maxEntries > 0 && len(m.entries) > m.maxEntries {
+		m.remove(m.recency.Back())
+	}
+	return nil
+}
+
+// Delete removes the entry stored under key, if any.
+func (m *LRUCache) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if elem, ok := m.entries[key]; ok {
+		m.remove(elem)
+	}
+	return nil
+}
+
+// Clear removes all entries and resets the statistics.
+func (m *LRUCache) Clear(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries = make(map[string]*list.Element)
+	m.recency.Init()
+	m.size, m.hits, m.misses = 0, 0, 0
+	return nil
+}
+
+// Keys returns the keys of all entries, implementing CacheKeyLister.
+func (m *LRUCache) Keys(ctx context.Context) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := make([]string, 0, len(m.entries))
+	for key := range m.entries {
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// GetStats returns the hits, misses and size of the cache.
+func (m *LRUCache) GetStats() CacheStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return CacheStats{
+		Hits:      m.hits,
+		Misses:    m.misses,
+		Size:      m.size,
+		ItemCount: len(m.entries),
+	}
+}
+
+// remove deletes an entry. The caller must hold m.mu.
+func (m *LRUCache) remove(elem *list.Element) {
+	entry := m.recency.Remove(elem).(*lruEntry)
+	delete(m.entries, entry.key)
+	m.size -= entry.size
+}
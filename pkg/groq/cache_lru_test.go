@@ -0,0 +1,79 @@
+package groq
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLRUCache(t *testing.T) {
+	ctx := context.Background()
+	cache := NewLRUCache(2, 0)
+	_ = cache.Set(ctx, "a", &ChatCompletionResponse{ID: "a"})
+	_ = cache.Set(ctx, "b", &ChatCompletionResponse{ID: "b"})
+
+	if resp, ok := cache.Get(ctx, "a"); !ok || resp.ID != "a" {
+		t.Fatalf("Get(a) = %+v, %v", resp, ok)
+	}
+	if _, ok := cache.Get(ctx, "A"); ok {
+		t.Error("the cache must match keys exactly")
+	}
+	_ = cache.Set(ctx, "c", &ChatCompletionResponse{ID: "c"})
+	if _, ok := cache.Get(ctx, "b"); ok {
+		t.Error("expected the least recently used entry to be evicted")
+	}
+	if _, ok := cache.Get(ctx, "a"); !ok {
+		t.Error("the recently used entry was evicted")
+	}
+
+	stats := cache.GetStats()
+	if stats.ItemCount != 2 || stats.Hits != 2 || stats.Misses != 2 || stats.Size == 0 {
+		t.Errorf("GetStats() = %+v", stats)
+	}
+}
+
+func TestLRUCacheTTL(t *testing.T) {
+	ctx := context.Background()
+	cache := NewLRUCache(0, 20*time.Millisecond)
+	_ = cache.Set(ctx, "a", &ChatCompletionResponse{ID: "a"})
+	if _, ttl, ok := cache.GetWithTTL(ctx, "a"); !ok || ttl <= 0 || ttl > 20*time.Millisecond {
+		t.Fatalf("GetWithTTL() = %v, %v before the TTL", ttl, ok)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if _, ok := cache.Get(ctx, "a"); ok {
+		t.Error("expected the entry to expire")
+	}
+	if stats := cache.GetStats(); stats.ItemCount != 0 {
+		t.Errorf("expired entry still counted: %+v", stats)
+	}
+}
+
+func TestContextWithCacheTTL(t *testing.T) {
+	var calls atomic.Int32
+	srv := newTestServer(t, func(req *ChatCompletionRequest) string {
+		calls.Add(1)
+		return "ok"
+	})
+	client := NewClient("test-key", WithBaseURL(srv.URL), WithCache(NewLRUCache(0, time.Hour)))
+	news := NewRequest(ModelLlama31_8bInstant).User("what's the latest news?").Build()
+	facts := NewRequest(ModelLlama31_8bInstant).User("what is the boiling point of water?").Build()
+
+	short := ContextWithCacheTTL(context.Background(), 20*time.Millisecond)
+	for _, req := range []*ChatCompletionRequest{news, facts} {
+		ctx := context.Background()
+		if req == news {
+			ctx = short
+		}
+		if _, err := client.CreateChatCompletion(ctx, req); err != nil {
+			t.Fatalf("CreateChatCompletion() error = %v", err)
+		}
+	}
+	time.Sleep(30 * time.Millisecond)
+
+	_, _ = client.CreateChatCompletion(short, news)
+	_, _ = client.CreateChatCompletion(context.Background(), facts)
+	if calls.Load() != 3 {
+		t.Errorf("got %d calls; want the short-lived entry refetched and the other cached", calls.Load())
+	}
+}
@@ -0,0 +1,89 @@
+package groq
+
+import (
+	"context"
+	"sync"
+)
+
+const defaultCacheSessionEntries = 64
+
+type CacheSession struct {
+	maxEntries int
+	entries    map[string]*ChatCompletionResponse
+	order      []string
+	mu         sync.Mutex
+}
+
+// NewCacheSession creates a read-your-writes overlay for the client cache. Every response
+// a session stores is also kept in the session itself, and lookups made with the session
+// check it before the shared cache. A session therefore always sees its own recent
+// answers, even when the cache persists writes asynchronously, drops them, or fails.
+//
+// Parameters:
+//   - maxEntries: How many recent writes the session remembers; 0 means 64.
+//
+// Returns:
+//   - *CacheSession: The new session.
+func NewCacheSession(maxEntries int) *CacheSession {
+	if maxEntries <= 0 {
+		maxEntries = defaultCacheSessionEntries
+	}
+	return &CacheSession{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*ChatCompletionResponse),
+	}
+}
+
+// Clear forgets all writes remembered by the session.
+func (s *CacheSession) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = make(map[string]*ChatCompletionResponse)
+	s.order = nil
+}
+
+// get returns the response the session stored under key.
+func (s *CacheSession) get(key string) (*ChatCompletionResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resp, ok := s.entries[key]
+	return resp, ok
+}
+
+// put remembers a write, evicting the oldest one when the session is full.
+func (s *CacheSession) put(key string, resp *ChatCompletionResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.entries[key]; !ok {
+		s.order = append(s.order, key)
+		if len(s.order) > s.maxEntries {
+			delete(s.entries, s.order[0])
+			s.order = s.order[1:]
+		}
+	}
+	s.entries[key] = resp
+}
+
+type cacheSessionKey struct{}
+
+// ContextWithCacheSession returns a copy of ctx whose chat completions read their own
+// writes through session. Cache policies still apply: a write is only remembered when
+// the policy stores responses, and only read back when the policy reads them.
+//
+// Example usage:
+//
+//	session := NewCacheSession(0)
+//	ctx = ContextWithCacheSession(ctx, session)
+//	resp, err := client.CreateChatCompletion(ctx, req)
+func ContextWithCacheSession(ctx context.Context, session *CacheSession) context.Context {
+	return context.WithValue(ctx, cacheSessionKey{}, session)
+}
+
+// cacheSessionFrom returns the cache session stored in ctx, or nil.
+func cacheSessionFrom(ctx context.Context) *CacheSession {
+	session, _ := ctx.Value(cacheSessionKey{}).(*CacheSession)
+	return session
+}
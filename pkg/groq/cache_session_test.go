@@ -0,0 +1,76 @@
+package groq
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+)
+
+// laggingCache accepts writes but does not make them visible, like a cache that
+// persists asynchronously.
+type laggingCache struct {
+	*mapCache
+}
+
+func (l *laggingCache) Set(ctx context.Context, key string, value *ChatCompletionResponse) error {
+	return nil
+}
+
+func TestConversationReadYourWrites(t *testing.T) {
+	var calls atomic.Int32
+	srv := newTestServer(t, func(req *ChatCompletionRequest) string {
+		return fmt.Sprintf("answer %d", calls.Add(1))
+	})
+
+	client := NewClient("test-key", WithBaseURL(srv.URL), WithCache(&laggingCache{newMapCache()}))
+	conv := client.NewConversation(ModelLlama31_8bInstant, "")
+	conv.CacheSession = NewCacheSession(0)
+
+	first, err := conv.Send(context.Background(), "what is the capital of France?")
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	// A conversation sharing the session asks the same question with the same history.
+	sibling := client.NewConversation(ModelLlama31_8bInstant, "")
+	sibling.CacheSession = conv.CacheSession
+	second, err := sibling.Send(context.Background(), "what is the capital of France?")
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if calls.Load() != 1 {
+		t.Errorf("expected the second question to be served from the session, got %d calls", calls.Load())
+	}
+	if first.Choices[0].Message.Content != second.Choices[0].Message.Content {
+		t.Errorf("expected consistent answers, got %v and %v", first.Choices[0].Message.Content, second.Choices[0].Message.Content)
+	}
+
+	// Without a session the lagging cache causes a repeat generation.
+	other := client.NewConversation(ModelLlama31_8bInstant, "")
+	if _, err := other.Send(context.Background(), "what is the capital of France?"); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("expected a new generation without a session, got %d calls", calls.Load())
+	}
+}
+
+func TestCacheSessionEviction(t *testing.T) {
+	session := NewCacheSession(2)
+	for _, key := range []string{"a", "b", "c"} {
+		session.put(key, &ChatCompletionResponse{ID: key})
+	}
+
+	if _, ok := session.get("a"); ok {
+		t.Error("expected the oldest write to be evicted")
+	}
+	if resp, ok := session.get("c"); !ok || resp.ID != "c" {
+		t.Errorf("expected the latest write to be kept, got %v", resp)
+	}
+
+	session.Clear()
+	if _, ok := session.get("c"); ok {
+		t.Error("expected Clear to forget all writes")
+	}
+}
@@ -0,0 +1,107 @@
+package groq
+
+import (
+	"context"
+	"time"
+)
+
+type StaleWhileRevalidate struct {
+	SoftTTL        time.Duration // Age after which a hit is served stale and refreshed in the background
+	HardTTL        time.Duration // Age after which a hit is ignored; 0 means no limit
+	RefreshTimeout time.Duration // Timeout of a background refresh; defaults to 30 seconds
+}
+
+// WithStaleWhileRevalidate enables stale-while-revalidate caching for a cache namespace
+// (see ContextWithCacheNamespace); the empty namespace configures requests without one.
+// A cache hit younger than SoftTTL is returned as usual. A hit between SoftTTL and HardTTL
+// is returned immediately while a single background request refreshes the entry. Hits
+// older than HardTTL are treated as misses, which bounds how stale an answer can be.
+//
+// The age of an entry is derived from the response's Created timestamp; responses without
+// one are stamped with the time they are stored. The option can be given once per namespace.
+//
+// Example usage:
+//
+//	client := NewClient(apiKey,
+//	    WithCache(cache),
+//	    WithStaleWhileRevalidate("", StaleWhileRevalidate{SoftTTL: time.Minute, HardTTL: time.Hour}),
+//	    WithStaleWhileRevalidate("news", StaleWhileRevalidate{SoftTTL: 10 * time.Second, HardTTL: time.Minute}),
+//	)
+func WithStaleWhileRevalidate(namespace string, policy StaleWhileRevalidate) Option {
+	return func(c *Client) {
+		if c.swr == nil {
+			c.swr = make(map[string]StaleWhileRevalidate)
+		}
+		c.swr[namespace] = policy
+	}
+}
+
+// cacheFreshness tells how a cache hit may be used.
+type cacheFreshness int
+
+const (
+	cacheFresh cacheFreshness = iota
+	cacheStale
+	cacheExpired
+)
+
+// freshness classifies a cached response according to the namespace's SWR policy.
+func (c *Client) freshness(namespace string, resp *ChatCompletionResponse) cacheFreshness {
+	policy, ok := c.swr[namespace]
+	if !ok || resp.Created == 0 {
+		return cacheFresh
+	}
+
+	age := time.Since(time.Unix(resp.Created, 0))
+	switch {
+	case policy.HardTTL > 0 && age >= policy.HardTTL:
+		return cacheExpired
+	case policy.SoftTTL > 0 && age >= policy.SoftTTL:
+		return cacheStale
+	default:
+		return cacheFresh
+	}
+}
+
+// revalidate refreshes a stale cache entry in the background. Concurrent refreshes of
+// the same key are coalesced, and the refresh outlives the cancellation of ctx.
+func (c *Client) revalidate(ctx context.Context, namespace, key string, req *ChatCompletionRequest) {
+	if _, busy := c.refreshing.LoadOrStore(key, struct{}{}); busy {
+		return
+	}
+
+	timeout := c.swr[namespace].RefreshTimeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+
+	clone := *req
+	clone.Messages = append([]ChatMessage(nil), req.Messages...)
+
+	if !c.startBackground() {
+		c.refreshing.Delete(key)
+		return
+	}
+	go func() {
+		defer c.background.Done()
+		defer c.refreshing.Delete(key)
+
+		refreshCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+		defer cancel()
+
+		_, _ = c.fetchAndStore(refreshCtx, &clone, key, CacheRefresh)
+	}()
+}
+
+// startBackground registers a background task for Close to wait for. It returns false
+// once the client is closing.
+func (c *Client) startBackground() bool {
+	c.closeMu.Lock()
+	defer c.closeMu.Unlock()
+
+	if c.closing {
+		return false
+	}
+	c.background.Add(1)
+	return true
+}
@@ -0,0 +1,132 @@
+package groq
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// mapCache is a minimal in-memory Cache for tests.
+type mapCache struct {
+	mu      sync.Mutex
+	entries map[string]*ChatCompletionResponse
+}
+
+func newMapCache() *mapCache {
+	return &mapCache{entries: make(map[string]*ChatCompletionResponse)}
+}
+
+func (m *mapCache) Get(ctx context.Context, key string) (*ChatCompletionResponse, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	resp, ok := m.entries[key]
+	return resp, ok
+}
+
+func (m *mapCache) Set(ctx context.Context, key string, value *ChatCompletionResponse) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = value
+	return nil
+}
+
+func (m *mapCache) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+	return nil
+}
+
+func (m *mapCache) Clear(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = make(map[string]*ChatCompletionResponse)
+	return nil
+}
+
+func (m *mapCache) GetStats() CacheStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return CacheStats{ItemCount: len(m.entries)}
+}
+
+// age makes every cached entry look older by d.
+func (m *mapCache) age(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, resp := range m.entries {
+		resp.Created -= int64(d / time.Second)
+	}
+}
+
+func TestStaleWhileRevalidate(t *testing.T) {
+	var calls int32
+	srv := newTestServer(t, func(req *ChatCompletionRequest) string {
+		return fmt.Sprintf("answer %d", atomic.AddInt32(&calls, 1))
+	})
+
+	cache := newMapCache()
+	client := NewClient("test-key",
+		WithBaseURL(srv.URL),
+		WithCache(cache),
+		WithStaleWhileRevalidate("news", StaleWhileRevalidate{SoftTTL: time.Minute, HardTTL: time.Hour}),
+	)
+
+	ctx := ContextWithCacheNamespace(context.Background(), "news")
+	ask := func() string {
+		t.Helper()
+		resp, err := client.CreateChatCompletion(ctx, NewRequest(ModelLlama31_8bInstant).User("headlines").Build())
+		if err != nil {
+			t.Fatalf("CreateChatCompletion() error = %v", err)
+		}
+		return resp.Choices[0].Message.Content.(string)
+	}
+
+	if got := ask(); got != "answer 1" {
+		t.Fatalf("first call = %q", got)
+	}
+	if got := ask(); got != "answer 1" || atomic.LoadInt32(&calls) != 1 {
+		t.Fatalf("fresh hit = %q after %d calls", got, calls)
+	}
+
+	cache.age(2 * time.Minute)
+	if got := ask(); got != "answer 1" {
+		t.Fatalf("stale hit = %q, want the cached answer", got)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for atomic.LoadInt32(&calls) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	for {
+		if resp, _ := cache.Get(ctx, CacheKey("news", NewRequest(ModelLlama31_8bInstant).User("headlines").Build())); resp.Choices[0].Message.Content == "answer 2" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("stale entry was not refreshed in the background")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	cache.age(2 * time.Hour)
+	if got := ask(); got != "answer 3" {
+		t.Errorf("expired hit = %q, want a fresh answer", got)
+	}
+
+	if _, found := cache.Get(ctx, "headlines"); found {
+		t.Error("namespaced entries must not be visible without the namespace")
+	}
+}
+
+func (m *mapCache) Keys(ctx context.Context) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keys := make([]string, 0, len(m.entries))
+	for key := range m.entries {
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
@@ -1,6 +1,8 @@
 package groq
 
 import (
+	"context"
+	"errors"
 	"testing"
 )
 
@@ -23,3 +25,31 @@ func TestWithCache(t *testing.T) {
 type mockCache struct {
 	Cache // Embed interface to implement all methods
 }
+
+type closingCache struct {
+	Cache
+	closed int
+}
+
+func (c *closingCache) Close() error {
+	c.closed++
+	return nil
+}
+
+func TestClientClose(t *testing.T) {
+	srv := newTestServer(t, func(req *ChatCompletionRequest) string { return "ok" })
+	cache := &closingCache{Cache: newMapCache()}
+	client := NewClient("test-key", WithBaseURL(srv.URL), WithCache(cache))
+
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := client.Close(); err != nil || cache.closed != 1 {
+		t.Fatalf("expected an idempotent Close that closes the cache once, got %v, %d", err, cache.closed)
+	}
+
+	_, err := client.CreateChatCompletion(context.Background(), NewRequest(ModelLlama31_8bInstant).User("hi").Build())
+	if !errors.Is(err, ErrClientClosed) {
+		t.Errorf("expected ErrClientClosed, got %v", err)
+	}
+}
@@ -0,0 +1,165 @@
+package groq
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/genc-murat/groq-client/internal/util"
+)
+
+// Cache outcomes of a chat call, as recorded in logs and metrics.
+const (
+	cacheOutcomeHit   = "hit"
+	cacheOutcomeStale = "stale"
+	cacheOutcomeMiss  = "miss"
+)
+
+// callTrace collects what happens during one call for its log record, metrics and hooks.
+type callTrace struct {
+	endpoint    Endpoint
+	model       ModelType
+	start       time.Time
+	annotations map[string]string
+
+	mu         sync.Mutex
+	attempts   int
+	status     int
+	requestID  string
+	clientID   string
+	cache      string
+	usage      *Usage
+	queued     time.Duration // Time spent waiting for rate limiters
+	firstToken time.Duration // Time to the first streamed chunk, 0 if none
+
+	requestBytes  int
+	responseBytes int
+}
+
+type callTraceKey struct{}
+
+// traceFrom returns the trace of the call ctx belongs to, or nil.
+func traceFrom(ctx context.Context) *callTrace {
+	trace, _ := ctx.Value(callTraceKey{}).(*callTrace)
+	return trace
+}
+
+// setCache records the cache outcome of the call. It is safe on a nil trace.
+func (t *callTrace) setCache(outcome string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cache = outcome
+}
+
+// setUsage records the token usage of the call. It is safe on a nil trace.
+func (t *callTrace) setUsage(usage *Usage) {
+	if t == nil || usage == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.usage = usage
+}
+
+// addQueued adds time spent waiting for a rate limiter. It is safe on a nil trace.
+func (t *callTrace) addQueued(d time.Duration) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.queued += d
+}
+
+// markFirstToken records the arrival of the first streamed chunk. It is safe on a nil trace.
+func (t *callTrace) markFirstToken() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.firstToken == 0 {
+		t.firstToken = time.Since(t.start)
+	}
+}
+
+// beginCall starts tracing a call if logging, metrics or hooks are enabled, returning the
+// context to make the call with and the trace, or ctx and nil.
+func (c *Client) beginCall(ctx context.Context, endpoint Endpoint, model ModelType) (context.Context, *callTrace) {
+	if c.logger == nil && c.metrics == nil && c.hooks == nil && metricsFrom(ctx) == nil {
+		return ctx, nil
+	}
+	trace := &callTrace{
+		endpoint:    endpoint,
+		model:       model,
+		start:       time.Now(),
+		annotations: AnnotationsFromContext(ctx),
+	}
+	ctx = context.WithValue(ctx, callTraceKey{}, trace)
+
+	if c.hooks != nil && c.hooks.OnRequest != nil {
+		c.hooks.OnRequest(ctx, RequestEvent{
+			Endpoint:    endpoint,
+			Model:       model,
+			Annotations: trace.annotations,
+			Start:       trace.start,
+		})
+	}
+	return ctx, trace
+}
+
+// endCall logs and records a traced call and calls its hooks. It does nothing if trace is nil.
+func (c *Client) endCall(ctx context.Context, trace *callTrace, err error) {
+	if trace == nil {
+		return
+	}
+	latency := time.Since(trace.start)
+	if c.logger != nil {
+		c.logCall(ctx, trace, latency, err)
+	}
+	if recorder := metricsFrom(ctx); c.metrics != nil || recorder != nil {
+		m := trace.metrics(latency, err)
+		if c.metrics != nil {
+			c.metrics.RecordCall(m)
+		}
+		if recorder != nil {
+			recorder.RecordCall(m)
+		}
+	}
+	if c.hooks == nil {
+		return
+	}
+	hook := c.hooks.OnResponse
+	if err != nil {
+		hook = c.hooks.OnError
+	}
+	if hook != nil {
+		hook(ctx, trace.callEvent(latency, err))
+	}
+}
+
+// observeAttempt is the attempt hook of the HTTP client: it updates the trace of the call
+// and logs the attempt.
+func (c *Client) observeAttempt(ctx context.Context, info util.AttemptInfo) {
+	trace := traceFrom(ctx)
+	if trace == nil {
+		return
+	}
+
+	trace.mu.Lock()
+	trace.attempts = info.Attempt + 1
+	trace.status = info.StatusCode
+	trace.requestID = info.RequestID
+	trace.clientID = info.ClientRequestID
+	trace.queued += info.Queued
+	trace.requestBytes = info.RequestBytes
+	trace.responseBytes = info.ResponseBytes
+	trace.mu.Unlock()
+
+	if c.logger != nil {
+		c.logAttempt(ctx, trace, info)
+	}
+}
@@ -0,0 +1,100 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCancelStopsRetryWait(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key", WithBaseURL(srv.URL), WithRetryConfig(3, time.Minute))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.CreateChatCompletion(ctx, NewRequest(ModelLlama31_8bInstant).User("hi").Build())
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("retry wait ignored cancellation for %v", elapsed)
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("expected 1 attempt before cancellation, got %d", n)
+	}
+}
+
+func TestCancelStopsStream(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i := 0; i < 5; i++ {
+			fmt.Fprintf(w, "data: {\"id\":\"c\",\"choices\":[{\"delta\":{\"content\":\"%d\"}}]}\n\n", i)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key", WithBaseURL(srv.URL))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	chunks := 0
+	err := client.CreateChatCompletionStream(ctx, NewRequest(ModelLlama31_8bInstant).User("hi").Build(), func(*ChatCompletionChunk) error {
+		chunks++
+		cancel()
+		return nil
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation error, got %v", err)
+	}
+	if chunks != 1 {
+		t.Errorf("expected no chunks after cancellation, got %d", chunks)
+	}
+}
+
+func TestCancelStopsParallelBatches(t *testing.T) {
+	var calls int32
+	srv := newTestServer(t, func(req *ChatCompletionRequest) string {
+		atomic.AddInt32(&calls, 1)
+		return "ok"
+	})
+	client := NewClient("test-key", WithBaseURL(srv.URL))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	requests := make([]*ChatCompletionRequest, 6)
+	for i := range requests {
+		requests[i] = NewRequest(ModelLlama31_8bInstant).User(fmt.Sprint(i)).Build()
+	}
+
+	responses := client.NewBatchProcessor(2, 2).ProcessBatch(ctx, requests)
+
+	if len(responses) != len(requests) {
+		t.Fatalf("expected %d responses, got %d", len(requests), len(responses))
+	}
+	for i, resp := range responses {
+		if !errors.Is(resp.Error, context.Canceled) {
+			t.Errorf("response %d: expected cancellation error, got %v", i, resp.Error)
+		}
+	}
+	if n := atomic.LoadInt32(&calls); n != 0 {
+		t.Errorf("expected no requests after cancellation, got %d", n)
+	}
+}
@@ -0,0 +1,179 @@
+package groq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/genc-murat/groq-client/internal/util"
+	"github.com/genc-murat/groq-client/pkg/groq/wire"
+)
+
+// capabilityProbeModel is the small model used to probe chat features when the client
+// has no default model.
+const capabilityProbeModel = ModelLlama31_8bInstant
+
+type Capabilities struct {
+	Version       string    // Version of this library
+	BaseURL       string    // The probed endpoint
+	Model         ModelType // The model tools and JSON schema were probed with
+	Models        bool      // GET /models
+	Tools         bool      // Tool calls in chat completions
+	JSONSchema    bool      // response_format with type json_schema
+	Transcription bool      // /audio/transcriptions
+	Speech        bool      // /audio/speech
+	Batch         bool      // /batches
+}
+
+// Capabilities probes which optional features the configured endpoint supports. This
+// matters when WithBaseURL points at an OpenAI-compatible proxy or gateway that covers
+// only part of the API. Endpoints are probed with requests the server rejects without
+// doing work; tools and JSON schema are probed with one-token chat completions on the
+// model set with WithDefaultModel, or a small model without one, so the probe costs a
+// few tokens. Use ModelCapabilities to probe the model you are going to call, since
+// support for tools and JSON schema differs between models. The result does not change
+// while the endpoint stays the same, so callers should keep it rather than probing per
+// request.
+//
+// Example usage:
+//
+//	caps, err := client.Capabilities(ctx)
+//	if err == nil && !caps.Tools {
+//	    log.Printf("%s does not support tool calls; falling back to prompting", caps.BaseURL)
+//	}
+//
+// Parameters:
+//   - ctx: Context for the probe requests.
+//
+// Returns:
+//   - *Capabilities: The supported features.
+//   - error: An error if the endpoint cannot be reached, rejects the API key, or fails
+//     with a rate limit or server error, so that support cannot be determined.
+func (c *Client) Capabilities(ctx context.Context) (*Capabilities, error) {
+	model := c.config.DefaultModel
+	if model == "" {
+		model = capabilityProbeModel
+	}
+	return c.ModelCapabilities(ctx, model)
+}
+
+// ModelCapabilities is like Capabilities but probes tools and JSON schema with the
+// given model.
+//
+// Example usage:
+//
+//	caps, err := client.ModelCapabilities(ctx, groq.ModelLlama4Scout)
+//
+// Parameters:
+//   - ctx: Context for the probe requests.
+//   - model: The model whose chat features are probed.
+//
+// Returns:
+//   - *Capabilities: The supported features.
+//   - error: An error if support cannot be determined, see Capabilities.
+func (c *Client) ModelCapabilities(ctx context.Context, model ModelType) (*Capabilities, error) {
+	caps := &Capabilities{Version: Version, BaseURL: c.baseURL, Model: model}
+
+	probes := []struct {
+		supported *bool
+		probe     func(ctx context.Context) (bool, error)
+	}{
+		{&caps.Models, func(ctx context.Context) (bool, error) {
+			return c.probeEndpoint(ctx, http.MethodGet, "/models", nil)
+		}},
+		{&caps.Transcription, func(ctx context.Context) (bool, error) {
+			return c.probeEndpoint(ctx, http.MethodPost, "/audio/transcriptions", map[string]interface{}{})
+		}},
+		{&caps.Speech, func(ctx context.Context) (bool, error) {
+			return c.probeEndpoint(ctx, http.MethodPost, "/audio/speech", map[string]interface{}{})
+		}},
+		{&caps.Batch, func(ctx context.Context) (bool, error) {
+			return c.probeEndpoint(ctx, http.MethodGet, "/batches", nil)
+		}},
+		{&caps.Tools, func(ctx context.Context) (bool, error) {
+			return c.probeChatFeature(ctx, model, "tools", []Tool{NewFunctionTool(WeatherFunction)})
+		}},
+		{&caps.JSONSchema, func(ctx context.Context) (bool, error) {
+			return c.probeChatFeature(ctx, model, "response_format", map[string]interface{}{
+				"type": "json_schema",
+				"json_schema": map[string]interface{}{
+					"name":   "probe",
+					"schema": map[string]interface{}{"type": "object", "properties": map[string]interface{}{}},
+				},
+			})
+		}},
+	}
+
+	for _, p := range probes {
+		supported, err := p.probe(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("capability probe failed: %w", err)
+		}
+		*p.supported = supported
+	}
+	return caps, nil
+}
+
+// probeEndpoint reports whether an endpoint exists. Any answer other than "not found" or
+// "not implemented" means it does, including a rejection of the empty probe request.
+func (c *Client) probeEndpoint(ctx context.Context, method, path string, body interface{}) (bool, error) {
+	rejected, err := probeResult(c.httpClient.DoJSON(ctx, method, c.baseURL+path, body, nil, nil))
+	if err != nil || rejected == nil {
+		return err == nil, err
+	}
+	switch rejected.StatusCode {
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return false, nil
+	}
+	return true, nil
+}
+
+// probeChatFeature sends a one-token chat completion with an extra request field and
+// reports whether the endpoint accepted it. A rejection counts as unsupported if the
+// error names the field; other rejections, such as JSON output cut short by the token
+// limit, show that the field itself was understood.
+func (c *Client) probeChatFeature(ctx context.Context, model ModelType, field string, value interface{}) (bool, error) {
+	body := map[string]interface{}{
+		"model":      model,
+		"messages":   []ChatMessage{{Role: "user", Content: "Reply with {}"}},
+		"max_tokens": 1,
+		field:        value,
+	}
+	rejected, err := probeResult(c.httpClient.DoJSON(ctx, http.MethodPost, c.baseURL+"/chat/completions", body, nil, nil))
+	if err != nil || rejected == nil {
+		return err == nil, err
+	}
+	if rejected.StatusCode != http.StatusBadRequest && rejected.StatusCode != http.StatusUnprocessableEntity {
+		return false, nil
+	}
+
+	var resp wire.ErrorResponse
+	if json.Unmarshal(rejected.Body, &resp) != nil {
+		return false, nil
+	}
+	mentioned := resp.Error.Param == field || strings.Contains(strings.ToLower(resp.Error.Message), field)
+	return !mentioned, nil
+}
+
+// probeResult classifies the error of a probe request. It returns nil, nil on success and
+// the status error if the server rejected the probe. Errors that leave support undetermined,
+// such as network, authentication, rate limit and server errors, are returned as errors.
+func probeResult(err error) (*util.StatusError, error) {
+	if err == nil {
+		return nil, nil
+	}
+
+	var statusErr *util.StatusError
+	if !errors.As(err, &statusErr) {
+		return nil, err
+	}
+	switch code := statusErr.StatusCode; {
+	case code == http.StatusUnauthorized, code == http.StatusForbidden, code == http.StatusTooManyRequests,
+		code >= http.StatusInternalServerError && code != http.StatusNotImplemented:
+		return nil, err
+	}
+	return statusErr, nil
+}
@@ -0,0 +1,84 @@
+package groq
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCapabilities(t *testing.T) {
+	// An OpenAI-compatible proxy without tools, speech and batches.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/models":
+			_, _ = w.Write([]byte(`{"object":"list","data":[]}`))
+		case "/audio/transcriptions":
+			http.Error(w, `{"error":{"message":"file is required"}}`, http.StatusBadRequest)
+		case "/chat/completions":
+			var body map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if _, ok := body["tools"]; ok {
+				http.Error(w, `{"error":{"message":"Unrecognized request argument supplied: tools"}}`, http.StatusBadRequest)
+				return
+			}
+			http.Error(w, `{"error":{"message":"Failed to generate JSON","code":"json_validate_failed"}}`, http.StatusBadRequest)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	caps, err := NewClient("test-key", WithBaseURL(srv.URL)).Capabilities(context.Background())
+	if err != nil {
+		t.Fatalf("Capabilities() error = %v", err)
+	}
+
+	want := Capabilities{Version: Version, BaseURL: srv.URL, Model: capabilityProbeModel, Models: true, JSONSchema: true, Transcription: true}
+	if *caps != want {
+		t.Errorf("Capabilities() = %+v, want %+v", *caps, want)
+	}
+}
+
+func TestModelCapabilities(t *testing.T) {
+	// Only the vision model supports tools.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if _, ok := body["tools"]; ok && body["model"] != string(ModelLlama4Scout) {
+			http.Error(w, `{"error":{"message":"tools are not supported by this model","param":"tools"}}`, http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"choices":[]}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key", WithBaseURL(srv.URL))
+	caps, err := client.ModelCapabilities(context.Background(), ModelLlama4Scout)
+	if err != nil {
+		t.Fatalf("ModelCapabilities() error = %v", err)
+	}
+	if caps.Model != ModelLlama4Scout || !caps.Tools {
+		t.Errorf("expected tools on %s, got %+v", ModelLlama4Scout, *caps)
+	}
+
+	caps, err = NewClient("test-key", WithBaseURL(srv.URL), WithDefaultModel(ModelLlama33_70bVersatile)).Capabilities(context.Background())
+	if err != nil {
+		t.Fatalf("Capabilities() error = %v", err)
+	}
+	if caps.Model != ModelLlama33_70bVersatile || caps.Tools {
+		t.Errorf("expected no tools on the default model, got %+v", *caps)
+	}
+}
+
+func TestCapabilitiesUnauthorized(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":{"message":"Invalid API Key"}}`, http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	if _, err := NewClient("bad-key", WithBaseURL(srv.URL)).Capabilities(context.Background()); err == nil {
+		t.Error("expected an authentication failure to be reported as an error")
+	}
+}
@@ -0,0 +1,149 @@
+package groq
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ChainResults holds the responses of completed chain steps, keyed by step name.
+type ChainResults map[string]*ChatCompletionResponse
+
+// Text returns the content of the first choice of the named step, or an empty string
+// if the step has not run or returned no choices.
+func (r ChainResults) Text(name string) string {
+	resp, ok := r[name]
+	if !ok || resp == nil || len(resp.Choices) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%v", resp.Choices[0].Message.Content)
+}
+
+type ChainStep struct {
+	Name  string
+	Build func(prev ChainResults) (*ChatCompletionRequest, error)
+}
+
+type ChainResult struct {
+	Responses ChainResults
+	Usage     Usage
+	Duration  time.Duration
+}
+
+type Chain struct {
+	client *Client
+	stages [][]ChainStep
+}
+
+// NewChain creates an empty Chain for orchestrating dependent completion calls.
+// Steps are grouped into stages: stages run one after another, while the steps
+// inside a stage run in parallel and can read the results of all earlier stages.
+//
+// Example:
+//
+//	result, err := client.NewChain().
+//	    Then(groq.ChainStep{Name: "outline", Build: outline}).
+//	    Then(
+//	        groq.ChainStep{Name: "intro", Build: intro},
+//	        groq.ChainStep{Name: "summary", Build: summary},
+//	    ).
+//	    Then(groq.ChainStep{Name: "final", Build: final}).
+//	    Run(ctx)
+func (c *Client) NewChain() *Chain {
+	return &Chain{client: c}
+}
+
+// Then appends a stage whose steps run in parallel once all previous stages completed.
+func (ch *Chain) Then(steps ...ChainStep) *Chain {
+	ch.stages = append(ch.stages, steps)
+	return ch
+}
+
+// Run executes the chain. The first failing step cancels the context of its sibling
+// steps and stops the chain; the returned ChainResult still contains every response
+// and the usage accumulated up to that point.
+//
+// Parameters:
+//   - ctx: Context for the whole chain, propagated to every step.
+//
+// Returns:
+//   - *ChainResult: All step responses and the aggregated token usage.
+//   - error: The first step error, wrapped with the step name, or ctx.Err().
+func (ch *Chain) Run(ctx context.Context) (*ChainResult, error) {
+	start := time.Now()
+	result := &ChainResult{Responses: make(ChainResults)}
+
+	for _, stage := range ch.stages {
+		if err := ctx.Err(); err != nil {
+			result.Duration = time.Since(start)
+			return result, err
+		}
+
+		if err := ch.runStage(ctx, stage, result); err != nil {
+			result.Duration = time.Since(start)
+			return result, err
+		}
+	}
+
+	result.Duration = time.Since(start)
+	return result, nil
+}
+
+// runStage runs the steps of one stage concurrently and merges their responses into result.
+func (ch *Chain) runStage(ctx context.Context, stage []ChainStep, result *ChainResult) error {
+	stageCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Steps only see results of earlier stages, never of their siblings.
+	prev := make(ChainResults, len(result.Responses))
+	for k, v := range result.Responses {
+		prev[k] = v
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+
+	for _, step := range stage {
+		wg.Add(1)
+		go func(step ChainStep) {
+			defer wg.Done()
+
+			resp, err := ch.runStep(stageCtx, step, prev)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				return
+			}
+			result.Responses[step.Name] = resp
+			result.Usage.Add(resp.Usage)
+		}(step)
+	}
+
+	wg.Wait()
+	return firstErr
+}
+
+// runStep builds and sends the request of a single step.
+func (ch *Chain) runStep(ctx context.Context, step ChainStep, prev ChainResults) (*ChatCompletionResponse, error) {
+	req, err := step.Build(prev)
+	if err != nil {
+		return nil, fmt.Errorf("chain step %q: build failed: %w", step.Name, err)
+	}
+
+	resp, err := ch.client.CreateChatCompletion(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("chain step %q: %w", step.Name, err)
+	}
+
+	return resp, nil
+}
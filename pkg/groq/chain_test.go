@@ -0,0 +1,62 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestChainRun(t *testing.T) {
+	srv := newTestServer(t, func(req *ChatCompletionRequest) string {
+		return "echo:" + req.Messages[len(req.Messages)-1].GetCacheKey()
+	})
+	client := NewClient("test-key", WithBaseURL(srv.URL))
+
+	step := func(name string, prompt func(ChainResults) string) ChainStep {
+		return ChainStep{Name: name, Build: func(prev ChainResults) (*ChatCompletionRequest, error) {
+			return NewRequest(ModelLlama31_8bInstant).User(prompt(prev)).Build(), nil
+		}}
+	}
+
+	result, err := client.NewChain().
+		Then(step("a", func(ChainResults) string { return "start" })).
+		Then(
+			step("b", func(prev ChainResults) string { return prev.Text("a") + "+b" }),
+			step("c", func(prev ChainResults) string { return prev.Text("a") + "+c" }),
+		).
+		Then(step("d", func(prev ChainResults) string { return prev.Text("b") + "|" + prev.Text("c") })).
+		Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := "echo:echo:echo:start+b|echo:echo:start+c"
+	if got := result.Responses.Text("d"); got != want {
+		t.Errorf("final step = %q, want %q", got, want)
+	}
+}
+
+func TestChainShortCircuits(t *testing.T) {
+	srv := newTestServer(t, func(req *ChatCompletionRequest) string { return "ok" })
+	client := NewClient("test-key", WithBaseURL(srv.URL))
+
+	boom := errors.New("boom")
+	ran := false
+
+	_, err := client.NewChain().
+		Then(ChainStep{Name: "fail", Build: func(ChainResults) (*ChatCompletionRequest, error) {
+			return nil, boom
+		}}).
+		Then(ChainStep{Name: "never", Build: func(ChainResults) (*ChatCompletionRequest, error) {
+			ran = true
+			return NewRequest(ModelLlama31_8bInstant).User("x").Build(), nil
+		}}).
+		Run(context.Background())
+
+	if !errors.Is(err, boom) {
+		t.Errorf("Run() error = %v, want %v", err, boom)
+	}
+	if ran {
+		t.Error("expected later stages to be skipped after a failure")
+	}
+}
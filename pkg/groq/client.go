@@ -4,11 +4,16 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"path/filepath"
+	"log/slog"
+	"net/http"
+	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/genc-murat/groq-client/internal/util"
@@ -20,15 +25,66 @@ const (
 )
 
 type Client struct {
-	baseURL    string
-	httpClient *util.HTTPClient
-	config     *Config
-	cache      Cache
+	baseURL     string
+	httpClient  *util.HTTPClient
+	config      *Config
+	cache       Cache
+	cacheShared bool // Close leaves the cache open, see WithSharedCache
+	vad         VoiceActivityDetector
+	guardrails  *Guardrails
+	safety      *safetyPolicy
+	locale      Locale
+	images      ImageValidator
+	logger      *slog.Logger
+	metrics     MetricsRecorder
+	hooks       *Hooks
+
+	requestIDGenerator func() string
+	idempotencyKeys    bool
+	dumper             *util.Dumper
+	netHTTP            bool              // Send requests with net/http, see WithHTTPTransport
+	roundTripper       http.RoundTripper // The RoundTripper of the net/http transport
+	proxyURL           string            // Proxy of all requests, see WithProxy; empty uses the environment
+	tlsConfig          *tls.Config       // TLS configuration of the connections, see WithTLSConfig
+	certPins           []string          // Pinned public keys, see WithCertificatePins
+	compressMinSize    int               // Smallest request body to gzip, see WithRequestCompression
+	conversationKeys   bool              // Cache under ConversationCacheKey, see WithConversationCacheKeys
+	conversationTurns  int               // Turns in conversation cache keys, 0 for all
+
+	baseURLs         []string
+	endpointCooldown time.Duration
+	endpoints        *util.EndpointPool
+	apiKeys          []string // Keys of WithAPIKeys
+	keyBalancing     KeyBalancing
+	keys             *util.KeyPool
+	timeout          time.Duration     // Request timeout of WithTimeout, 0 for defaultTimeout
+	headers          map[string]string // Headers of WithBaseHeaders, sent with every request
+	optionSources    map[string]string // The option that made each setting, see claim
+	optionConflicts  []string          // Settings made by several options
+	organization     string            // Sent in the Groq-Organization header, see WithOrganization
+	project          string            // Sent in the Groq-Project header, see WithProject
+	userAgent        string            // User-Agent of WithUserAgent, empty for DefaultUserAgent
+	userAgentTokens  []string          // Tokens of WithUserAgentSuffix appended to the User-Agent
+
+	rateLimiter RateLimiter
+	limiters    map[string]*util.TokenLimiter
+	limiterMu   sync.Mutex
+
+	autoMigrate bool
+	onMigrate   func(from, to ModelType)
+
+	swr        map[string]StaleWhileRevalidate
+	refreshing sync.Map
+	background sync.WaitGroup // Background cache refreshes, drained by Close
+	closing    bool           // Set by Close; no background work starts afterwards
+	closeMu    sync.Mutex
+	closeOnce  sync.Once
 }
 
 // NewClient creates a new instance of Client with the provided API key and optional configurations.
 // It sets up the HTTP client with default settings and base headers including the Authorization header.
-// If the base headers are not set properly, it will panic.
+// If the base headers are not set properly, it will panic. Use NewClientE to validate the
+// key and the options and get an error instead.
 //
 // Parameters:
 //   - apiKey: The API key used for authorization.
@@ -37,37 +93,203 @@ type Client struct {
 // Returns:
 //   - *Client: A pointer to the newly created Client instance.
 func NewClient(apiKey string, opts ...Option) *Client {
-	baseHeaders := map[string]string{
-		"Authorization": fmt.Sprintf("Bearer %s", apiKey),
-		"Content-Type":  "application/json",
+	c, err := newClient(apiKey, opts...)
+	if err != nil {
+		panic(err.Error())
 	}
+	return c
+}
 
-	httpConfig := util.HTTPClientConfig{
-		MaxRequestTimeout: defaultTimeout,
-		RequestsPerSecond: 10,
-		MaxRetries:        3,
-		RetryWaitTime:     time.Second,
-		BaseHeaders:       baseHeaders,
+// NewClientE is like NewClient but checks the API key and the options instead of
+// accepting anything: the key, or every key of WithAPIKeys, must not be empty or contain
+// whitespace, base URLs must be absolute http or https URLs, the timeout must be positive,
+// and the retry and rate limit settings must pass Config.Validate. Options that conflict,
+// such as two options setting the timeout or a proxy for a custom RoundTripper that
+// ignores it, are rejected too, while NewClient lets the later option win. All problems
+// are reported at once.
+//
+// Example usage:
+//
+//	client, err := groq.NewClientE(os.Getenv("GROQ_API_KEY"), groq.WithTimeout(time.Minute))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer client.Close()
+//
+// Parameters:
+//   - apiKey: The API key used for authorization.
+//   - opts: Optional configurations that can be applied to the Client.
+//
+// Returns:
+//   - *Client: The client, or nil if there are problems.
+//   - error: A *ConfigError matching ErrInvalidConfig that lists every problem.
+func NewClientE(apiKey string, opts ...Option) (*Client, error) {
+	c, err := newClient(apiKey, opts...)
+	if err != nil {
+		return nil, err
 	}
 
-	httpClient := util.NewHTTPClient(httpConfig)
+	var problems []string
+	if len(c.apiKeys) == 0 {
+		problems = append(problems, apiKeyProblems(apiKey)...)
+	}
+	for _, key := range c.apiKeys {
+		problems = append(problems, apiKeyProblems(key)...)
+	}
+	problems = append(problems, c.optionProblems()...)
+	if len(problems) > 0 {
+		_ = c.Close()
+		return nil, NewConfigError(problems)
+	}
+	return c, nil
+}
 
-	currentHeaders := httpClient.GetBaseHeaders()
-	if len(currentHeaders) == 0 || currentHeaders["Authorization"] == "" {
-		panic(fmt.Sprintf("Base headers not set properly. Current headers: %v", currentHeaders))
+// apiKeyProblems returns the problems of an API key.
+func apiKeyProblems(apiKey string) []string {
+	switch {
+	case strings.TrimSpace(apiKey) == "":
+		return []string{"API key is empty; set it from the Groq console, e.g. os.Getenv(\"GROQ_API_KEY\")"}
+	case strings.ContainsAny(apiKey, " \t\r\n"):
+		return []string{"API key contains whitespace; check for a trailing newline where it was read from"}
 	}
+	return nil
+}
 
+// newClient builds a client, failing only if the base headers cannot be set. The options
+// only record their settings; the HTTP client is built from all of them afterwards, so
+// the order of the options does not matter.
+func newClient(apiKey string, opts ...Option) (*Client, error) {
 	c := &Client{
-		baseURL:    DefaultBaseURL,
-		httpClient: httpClient,
-		config:     defaultConfig(),
+		baseURL:            DefaultBaseURL,
+		config:             defaultConfig(),
+		requestIDGenerator: util.NewRequestID,
 	}
 
 	for _, opt := range opts {
 		opt(c)
 	}
 
-	return c
+	c.httpClient = util.NewHTTPClient(c.httpClientConfig(apiKey))
+	currentHeaders := c.httpClient.GetBaseHeaders()
+	if len(currentHeaders) == 0 || currentHeaders["Authorization"] == "" {
+		_ = c.httpClient.Close()
+		return nil, fmt.Errorf("base headers not set properly, current headers: %v", currentHeaders)
+	}
+	// NewHTTPClient takes zero retries for the default of three.
+	c.httpClient.SetRetries(c.config.RetryConfig.MaxRetries, c.config.RetryConfig.RetryDelay)
+
+	c.httpClient.SetErrorHandler(newAPIError)
+	c.setupEndpoints()
+	c.setupKeys()
+	c.applyAccountHeaders()
+	// Always installed: a call may be traced for a ContextWithMetrics recorder alone.
+	c.httpClient.SetAttemptHook(c.observeAttempt)
+	if c.hooks != nil {
+		c.httpClient.SetRetryNotifyHook(c.observeRetry)
+	}
+	c.httpClient.SetRequestIDGenerator(c.requestIDGenerator)
+	c.httpClient.SetIdempotencyKeys(c.idempotencyKeys)
+	c.httpClient.SetDumper(c.dumper)
+	c.httpClient.SetRequestCompression(c.compressMinSize)
+	c.applyTransport()
+
+	return c, nil
+}
+
+// optionProblems returns the problems of the configuration the options produced.
+func (c *Client) optionProblems() []string {
+	var problems []string
+
+	var configErr *ConfigError
+	if errors.As(c.config.Validate(), &configErr) {
+		problems = append(problems, configErr.Problems...)
+	}
+
+	baseURLs := c.baseURLs
+	if len(baseURLs) == 0 {
+		baseURLs = []string{c.baseURL}
+	}
+	for _, baseURL := range baseURLs {
+		if u, err := url.Parse(baseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			problems = append(problems, fmt.Sprintf("base URL %q must be an absolute http or https URL", baseURL))
+		}
+	}
+	if n := len(c.baseURLs); n > 1 && c.config.RetryConfig.MaxRetries < n-1 {
+		problems = append(problems, fmt.Sprintf("%d base URLs need at least %d retries to fail over, but MaxRetries is %d", n, n-1, c.config.RetryConfig.MaxRetries))
+	}
+	problems = append(problems, c.accountProblems()...)
+	problems = append(problems, c.userAgentProblems()...)
+	problems = append(problems, c.optionConflicts...)
+	if _, ok := c.optionSources["timeout"]; ok && c.timeout <= 0 {
+		problems = append(problems, fmt.Sprintf("timeout is %v; it must be positive", c.timeout))
+	}
+	if _, ok := c.optionSources["rate limiter"]; ok {
+		if _, ok := c.optionSources["rate limit"]; ok {
+			problems = append(problems, "WithRateLimit has no effect with a custom rate limiter; configure the rate on the limiter given to WithRateLimiter")
+		}
+	}
+	if c.netHTTP && c.roundTripper != nil {
+		if c.proxyURL != "" {
+			problems = append(problems, "WithProxy has no effect with a custom RoundTripper; configure the proxy on the RoundTripper given to WithHTTPTransport")
+		}
+		if c.tlsConfig != nil || len(c.certPins) > 0 {
+			problems = append(problems, "WithTLSConfig and WithCertificatePins have no effect with a custom RoundTripper; configure TLS on the RoundTripper given to WithHTTPTransport")
+		}
+	}
+	if c.compressMinSize < 0 {
+		problems = append(problems, fmt.Sprintf("request compression threshold is %d bytes; it must not be negative", c.compressMinSize))
+	}
+	if c.conversationTurns < 0 {
+		problems = append(problems, fmt.Sprintf("conversation cache keys keep %d turns; it must not be negative, use 0 for all", c.conversationTurns))
+	}
+	if c.endpointCooldown < 0 {
+		problems = append(problems, fmt.Sprintf("endpoint cooldown is %v; it must not be negative", c.endpointCooldown))
+	}
+	if c.proxyURL != "" {
+		if _, err := parseProxyURL(c.proxyURL); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	for _, pin := range c.certPins {
+		if _, err := parseCertificatePin(pin); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	return problems
+}
+
+// ErrClientClosed is returned by requests made after Client.Close.
+var ErrClientClosed = util.ErrClientClosed
+
+// Close releases the resources of the client: it stops accepting requests, waits for the
+// requests in flight and background cache refreshes to finish, stops the rate limiter's
+// goroutine and closes idle connections. If the cache implements io.Closer, such as a
+// semantic cache with persistence, it is closed too, which flushes it to disk, unless it
+// is shared, see WithSharedCache. Requests made
+// after Close fail with ErrClientClosed. Close is idempotent.
+//
+// Example usage:
+//
+//	client := NewClient(apiKey)
+//	defer client.Close()
+//
+// Returns:
+//   - error: The error of closing the cache, if any.
+func (c *Client) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		c.closeMu.Lock()
+		c.closing = true
+		c.closeMu.Unlock()
+
+		_ = c.httpClient.Close()
+		c.background.Wait()
+		if closer, ok := c.cache.(io.Closer); ok && !c.cacheShared {
+			err = closer.Close()
+		}
+	})
+	return err
 }
 
 // GetCacheKey returns a string that can be used as a cache key for the message content.
@@ -94,29 +316,131 @@ func (m ChatMessage) GetCacheKey() string {
 
 // CreateChatCompletion sends a chat completion request to the Groq API.
 // It takes a context and a ChatCompletionRequest as input.
-// The function first validates the request, then checks if a cached response exists.
-// If no cache hit occurs, it makes an HTTP POST request to the chat completions endpoint.
-// The response is cached (if caching is enabled) before being returned.
+// The function first validates the request and runs the input guardrails (if configured),
+// then checks if a cached response exists. If no cache hit occurs, it makes an HTTP POST
+// request to the chat completions endpoint. The response is screened by the output
+// guardrails and cached (if caching is enabled) before being returned. The caching
+// behaviour of a single call can be changed with ContextWithCachePolicy,
+// ContextWithCacheNamespace and ContextWithCacheSession; stale entries may be refreshed in the background when
+// WithStaleWhileRevalidate is configured. With WithSafetyProfile, blocked exchanges may
+// return a refusal answer instead of an error.
 //
 // Parameters:
 //   - ctx: Context for the request, used for timeouts and cancellation
 //   - req: Pointer to ChatCompletionRequest containing the chat messages and parameters
+//   - opts: Optional settings of this call, such as WithRequestTimeout or WithRequestAPIKey
 //
 // Returns:
 //   - *ChatCompletionResponse: Contains the API's response including generated message
 //   - error: Non-nil if request validation fails, API request fails, or other errors occur
-func (c *Client) CreateChatCompletion(ctx context.Context, req *ChatCompletionRequest) (*ChatCompletionResponse, error) {
-	if err := req.Validate(); err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+func (c *Client) CreateChatCompletion(ctx context.Context, req *ChatCompletionRequest, opts ...RequestOption) (*ChatCompletionResponse, error) {
+	ctx, cancel := applyRequestOptions(ctx, opts)
+	defer cancel()
+	req = c.withDefaultModel(req)
+	ctx, trace := c.beginChatCall(ctx, req)
+	resp, err := c.createChatCompletion(ctx, req)
+	if err != nil && c.safety != nil {
+		resp, err = c.safety.refuse(ctx, req, err)
+	}
+	c.endCall(ctx, trace, err)
+	return resp, err
+}
+
+// beginChatCall starts tracing a chat call, see beginCall.
+func (c *Client) beginChatCall(ctx context.Context, req *ChatCompletionRequest) (context.Context, *callTrace) {
+	if req == nil {
+		return c.beginCall(ctx, EndpointChat, "")
 	}
+	return c.beginCall(ContextWithAnnotations(ctx, req.Annotations), EndpointChat, req.Model)
+}
 
-	lastMsg := req.Messages[len(req.Messages)-1]
-	cacheKey := lastMsg.GetCacheKey()
+// createChatCompletion implements CreateChatCompletion without the safety refusal handling.
+func (c *Client) createChatCompletion(ctx context.Context, req *ChatCompletionRequest) (*ChatCompletionResponse, error) {
+	if err := c.validateRequest(ctx, req); err != nil {
+		return nil, c.invalidRequest(err)
+	}
+	ctx = ContextWithAnnotations(ctx, req.Annotations)
+	if c.safety != nil {
+		req = c.safety.apply(req)
+	}
 
-	if c.cache != nil {
+	if err := c.checkGuardrails(ctx, GuardrailInput, req.Messages); err != nil {
+		return nil, err
+	}
+
+	namespace := cacheNamespaceFrom(ctx)
+	cacheKey := c.cacheKey(ctx, req)
+	policy := cachePolicyFrom(ctx)
+
+	if c.cache != nil && (policy == CacheDefault || policy == CacheReadOnly) {
+		if session := cacheSessionFrom(ctx); session != nil {
+			if resp, found := session.get(cacheKey); found {
+				c.recordCacheOutcome(ctx, cacheOutcomeHit, cacheKey)
+				return resp, nil
+			}
+		}
 		if resp, found := c.cache.Get(ctx, cacheKey); found {
-			return resp, nil
+			switch c.freshness(namespace, resp) {
+			case cacheFresh:
+				c.recordCacheOutcome(ctx, cacheOutcomeHit, cacheKey)
+				return resp, nil
+			case cacheStale:
+				c.recordCacheOutcome(ctx, cacheOutcomeStale, cacheKey)
+				if policy == CacheDefault {
+					c.revalidate(ctx, namespace, cacheKey, req)
+				}
+				return resp, nil
+			}
 		}
+		c.recordCacheOutcome(ctx, cacheOutcomeMiss, cacheKey)
+	}
+
+	return c.fetchAndStore(ctx, req, cacheKey, policy)
+}
+
+// fetchAndStore performs the API call, screens the answer with the output guardrails and
+// stores it in the cache if the policy allows it.
+func (c *Client) fetchAndStore(ctx context.Context, req *ChatCompletionRequest, cacheKey string, policy CachePolicy) (*ChatCompletionResponse, error) {
+	result, err := c.doChatCompletion(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if (c.guardrails != nil || c.safety != nil) && len(result.Choices) > 0 {
+		exchange := append(append([]ChatMessage{}, req.Messages...), result.Choices[0].Message)
+		if err := c.checkGuardrails(ctx, GuardrailOutput, exchange); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.cache != nil && (policy == CacheDefault || policy == CacheRefresh) {
+		if c.swr != nil && result.Created == 0 {
+			result.Created = time.Now().Unix()
+		}
+		if session := cacheSessionFrom(ctx); session != nil {
+			session.put(cacheKey, result)
+		}
+		_ = setCacheEntry(ctx, c.cache, cacheKey, result)
+	}
+
+	return result, nil
+}
+
+// doChatCompletion performs the chat completion HTTP call without validation,
+// guardrails or caching.
+func (c *Client) doChatCompletion(ctx context.Context, req *ChatCompletionRequest) (*ChatCompletionResponse, error) {
+	if model := c.migrateModel(req.Model); model != req.Model {
+		migrated := *req
+		migrated.Model = model
+		req = &migrated
+	}
+
+	if err := c.waitRequestLimits(ctx, EndpointChat, req.Model); err != nil {
+		return nil, err
+	}
+	settle, err := c.reserveTokens(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", util.ErrRateLimitExceeded, err)
 	}
 
 	headers := map[string]string{
@@ -124,21 +448,20 @@ func (c *Client) CreateChatCompletion(ctx context.Context, req *ChatCompletionRe
 	}
 
 	var result ChatCompletionResponse
-	err := c.httpClient.DoJSON(
+	err = c.httpClient.DoJSON(
 		ctx,
 		"POST",
-		fmt.Sprintf("%s/chat/completions", c.baseURL),
+		c.endpointURL(ctx, "/chat/completions"),
 		req,
 		&result,
 		headers,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("chat completion request failed: %w", err)
-	}
-
-	if c.cache != nil {
-		_ = c.cache.Set(ctx, cacheKey, &result)
+		settle(nil)
+		return nil, fmt.Errorf("chat completion request failed: %w", decommissionedError(req.Model, err))
 	}
+	settle(&result.Usage)
+	traceFrom(ctx).setUsage(&result.Usage)
 
 	return &result, nil
 }
@@ -173,7 +496,7 @@ func (c *Client) GetCacheStats() *CacheStats {
 
 // CreateChatCompletionStream sends a chat completion request to the server and processes the response stream.
 // It validates the request, marshals it to JSON, and sends it via an HTTP POST request.
-// The response is expected to be a stream of events, which are read and processed line by line.
+// The response is expected to be a stream of events, which are read and processed line by line as they arrive.
 // Each line is expected to be a JSON-encoded ChatCompletionChunk, which is passed to the provided handler function.
 //
 // The function returns an error if the request validation fails, if there is an error during the HTTP request,
@@ -183,19 +506,52 @@ func (c *Client) GetCacheStats() *CacheStats {
 // - ctx: The context for controlling the request lifetime.
 // - req: The chat completion request to be sent.
 // - handler: A function to handle each chunk of the chat completion response.
+// - opts: Optional settings of this call; WithRequestTimeout also bounds reading the stream.
+//
+// The stream ends as soon as every choice seen so far has delivered a terminal finish_reason
+// (see FinishReason); the chunk carrying it is still passed to the handler. The [DONE]
+// sentinel also ends the stream; a body ending before either is reported as truncated.
 //
 // Returns:
-// - An error if any step of the process fails, or if the context is canceled.
-func (c *Client) CreateChatCompletionStream(ctx context.Context, req *ChatCompletionRequest, handler StreamHandler) error {
-	if err := req.Validate(); err != nil {
-		return fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+//   - An error if any step of the process fails, or if the context is canceled. Cancellation
+//     is checked before every chunk, so no chunk is delivered to the handler after ctx is done.
+func (c *Client) CreateChatCompletionStream(ctx context.Context, req *ChatCompletionRequest, handler StreamHandler, opts ...RequestOption) error {
+	ctx, cancel := applyRequestOptions(ctx, opts)
+	defer cancel()
+	req = c.withDefaultModel(req)
+	ctx, trace := c.beginChatCall(ctx, req)
+	err := c.createChatCompletionStream(ctx, req, handler)
+	if err != nil && c.safety != nil {
+		c.safety.report(ctx, err)
+	}
+	c.endCall(ctx, trace, err)
+	return err
+}
+
+// createChatCompletionStream implements CreateChatCompletionStream.
+func (c *Client) createChatCompletionStream(ctx context.Context, req *ChatCompletionRequest, handler StreamHandler) error {
+	if err := c.validateRequest(ctx, req); err != nil {
+		return c.invalidRequest(err)
+	}
+	ctx = ContextWithAnnotations(ctx, req.Annotations)
+	if c.safety != nil {
+		req = c.safety.apply(req)
+	}
+
+	if err := c.checkGuardrails(ctx, GuardrailInput, req.Messages); err != nil {
+		return err
 	}
 
-	req.Stream = true
+	streamReq := *req
+	streamReq.Stream = true
+	streamReq.Model = c.migrateModel(req.Model)
 
-	reqBody, err := json.Marshal(req)
+	if err := c.waitRequestLimits(ctx, EndpointChat, streamReq.Model); err != nil {
+		return err
+	}
+	settle, err := c.reserveTokens(ctx, &streamReq)
 	if err != nil {
-		return fmt.Errorf("failed to marshal request: %w", err)
+		return fmt.Errorf("%w: %w", util.ErrRateLimitExceeded, err)
 	}
 
 	headers := map[string]string{
@@ -203,18 +559,95 @@ func (c *Client) CreateChatCompletionStream(ctx context.Context, req *ChatComple
 		"Content-Type": "application/json",
 	}
 
-	respBody, err := c.httpClient.DoRequest(
+	// Answers are held back until the output checkers have passed them.
+	var held []*ChatCompletionChunk
+	deliver := handler
+	screen := len(c.guardrailCheckers(GuardrailOutput)) > 0
+	if screen {
+		deliver = func(chunk *ChatCompletionChunk) error {
+			held = append(held, chunk)
+			return nil
+		}
+	}
+
+	received := false // Whether the error comes from reading the stream
+	err = c.httpClient.DoJSONStream(
 		ctx,
 		"POST",
-		fmt.Sprintf("%s/chat/completions", c.baseURL),
-		reqBody,
+		c.endpointURL(ctx, "/chat/completions"),
+		&streamReq,
 		headers,
+		func(body io.Reader) error {
+			received = true
+			return c.readChatStream(ctx, body, settle, deliver)
+		},
 	)
-	if err != nil {
+	if err != nil && !received {
+		settle(nil)
+		return decommissionedError(streamReq.Model, err)
+	}
+	if err != nil || !screen {
 		return err
 	}
+	return c.releaseChatStream(ctx, req.Messages, held, handler)
+}
+
+// releaseChatStream runs the output checkers on the answers assembled from held and
+// passes the chunks to handler once every answer has passed.
+func (c *Client) releaseChatStream(ctx context.Context, messages []ChatMessage, held []*ChatCompletionChunk, handler StreamHandler) error {
+	var indexes []int
+	answers := make(map[int]*strings.Builder)
+	for _, chunk := range held {
+		for _, choice := range chunk.Choices {
+			answer, ok := answers[choice.Index]
+			if !ok {
+				answer = &strings.Builder{}
+				answers[choice.Index] = answer
+				indexes = append(indexes, choice.Index)
+			}
+			answer.WriteString(choice.Delta.Content)
+		}
+	}
+	for _, index := range indexes {
+		reply := ChatMessage{Role: "assistant", Content: answers[index].String()}
+		exchange := append(append([]ChatMessage{}, messages...), reply)
+		if err := c.checkGuardrails(ctx, GuardrailOutput, exchange); err != nil {
+			return err
+		}
+	}
 
-	reader := bufio.NewReader(bytes.NewReader(respBody))
+	for _, chunk := range held {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		if err := handler(chunk); err != nil {
+			return fmt.Errorf("stream handler error: %w", err)
+		}
+	}
+	return nil
+}
+
+// streamReaderPool holds the readers splitting chat streams into lines.
+var streamReaderPool = sync.Pool{
+	New: func() any { return bufio.NewReaderSize(nil, 4096) },
+}
+
+// readChatStream passes the chunks of a chat completion stream to handler as they arrive,
+// until the stream ends or every choice has finished. settle is called with the first
+// usage reported.
+func (c *Client) readChatStream(ctx context.Context, body io.Reader, settle func(*Usage), handler StreamHandler) error {
+	reader := streamReaderPool.Get().(*bufio.Reader)
+	reader.Reset(body)
+	defer func() {
+		reader.Reset(nil)
+		streamReaderPool.Put(reader)
+	}()
+
+	settled := false // Streams without reported usage keep the estimate
+	open := make(map[int]bool)
+	var long []byte // Lines longer than the reader's buffer
 
 	for {
 		select {
@@ -223,12 +656,17 @@ func (c *Client) CreateChatCompletionStream(ctx context.Context, req *ChatComple
 		default:
 		}
 
-		line, err := reader.ReadBytes('\n')
+		line, err := readLine(reader, &long)
 		if err != nil {
-			if err == io.EOF {
+			if err == io.EOF && streamFinished(open) {
 				return nil
 			}
-			return fmt.Errorf("error reading stream: %v", err)
+			if err == io.EOF {
+				// fasthttp ends a streamed chunked body that drops between chunks with
+				// io.EOF, so a stream is only complete with [DONE] or finished choices.
+				err = io.ErrUnexpectedEOF
+			}
+			return fmt.Errorf("error reading stream: %w", err)
 		}
 
 		line = bytes.TrimSpace(line)
@@ -250,45 +688,112 @@ func (c *Client) CreateChatCompletionStream(ctx context.Context, req *ChatComple
 			return fmt.Errorf("%w: %v", ErrJSONDecoding, err)
 		}
 
+		if usage := chunk.ReportedUsage(); usage != nil && !settled {
+			settle(usage)
+			traceFrom(ctx).setUsage(usage)
+			settled = true
+		}
+		traceFrom(ctx).markFirstToken()
 		if err := handler(&chunk); err != nil {
-			return fmt.Errorf("stream handler error: %v", err)
+			return fmt.Errorf("stream handler error: %w", err)
+		}
+
+		for _, choice := range chunk.Choices {
+			open[choice.Index] = !choice.FinishReason.IsTerminal()
+		}
+		if streamFinished(open) {
+			return nil
 		}
 	}
 }
 
+// readLine returns the next line of r, valid until the next call. Lines longer than the
+// buffer of r are assembled in *long, which is reused across calls. Like ReadBytes, it
+// returns io.EOF with the rest of the data if the last line has no line break.
+func readLine(r *bufio.Reader, long *[]byte) ([]byte, error) {
+	line, err := r.ReadSlice('\n')
+	if err != bufio.ErrBufferFull {
+		return line, err
+	}
+	*long = append((*long)[:0], line...)
+	for err == bufio.ErrBufferFull {
+		line, err = r.ReadSlice('\n')
+		*long = append(*long, line...)
+	}
+	return *long, err
+}
+
+// streamFinished reports whether every choice seen in a stream has finished.
+func streamFinished(open map[int]bool) bool {
+	if len(open) == 0 {
+		return false
+	}
+	for _, isOpen := range open {
+		if isOpen {
+			return false
+		}
+	}
+	return true
+}
+
 // CreateTranscription sends an audio file to be transcribed into text using the specified model.
 // If no model is specified, it defaults to Whisper Large v3.
 //
 // The audio file must be in one of the supported formats: flac, mp3, mp4, mpeg, mpga, m4a, ogg, wav, or webm.
+// Files larger than the model's MaxFileSize fail with a *FileTooLargeError before anything is uploaded,
+// if their size can be determined (seekable readers or readers reporting their length).
 //
 // Parameters:
 //   - ctx: The context for the request
 //   - req: TranscriptionRequest containing:
 //   - File: The audio file to transcribe
 //   - FileName: Name of the audio file with extension
+//   - URL: Alternatively, a public URL of the audio, downloaded by the API instead of File
 //   - Model: (Optional) The model to use for transcription
 //   - Language: (Optional) The language of the audio
 //   - Prompt: (Optional) Text to guide the model's transcription
-//   - ResponseFormat: (Optional) The format of the transcription response
+//   - ResponseFormat: (Optional) json, verbose_json, or text, srt and vtt, which are returned unparsed in Text
 //   - Temperature: (Optional) Sampling temperature for the model
+//   - opts: Optional settings of this call, such as WithRequestTimeout or WithRequestAPIKey
 //
 // Returns:
 //   - *TranscriptionResponse: Contains the transcribed text and other response data
 //   - error: Any error that occurred during the request
-func (c *Client) CreateTranscription(ctx context.Context, req *TranscriptionRequest) (*TranscriptionResponse, error) {
+func (c *Client) CreateTranscription(ctx context.Context, req *TranscriptionRequest, opts ...RequestOption) (*TranscriptionResponse, error) {
+	ctx, cancel := applyRequestOptions(ctx, opts)
+	defer cancel()
+	body, err := c.createTranscriptionRaw(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var result TranscriptionResponse
+	if isTextResponseFormat(req.ResponseFormat) {
+		result.Text = string(body)
+		return &result, nil
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("transcription request failed: %w: %v", ErrJSONDecoding, err)
+	}
+
+	return &result, nil
+}
+
+// createTranscriptionRaw validates the TranscriptionRequest, uploads the audio file and
+// returns the undecoded response body. The body format depends on req.ResponseFormat.
+func (c *Client) createTranscriptionRaw(ctx context.Context, req *TranscriptionRequest) ([]byte, error) {
 	if req.Model == "" {
 		req.Model = ModelWhisperLargeV3
 	}
 
-	ext := filepath.Ext(req.FileName)
-	if !isValidAudioFormat(ext) {
-		return nil, fmt.Errorf("invalid audio format: %s. Supported formats: flac, mp3, mp4, mpeg, mpga, m4a, ogg, wav, webm", ext)
+	if err := validateTimestampGranularities(req); err != nil {
+		return nil, c.invalidRequest(err)
 	}
 
-	form := map[string]interface{}{
-		"file":     req.File,
-		"filename": req.FileName,
-		"model":    string(req.Model),
+	model := c.migrateModel(req.Model)
+	form, err := c.audioForm(model, req.File, req.FileName, req.URL)
+	if err != nil {
+		return nil, err
 	}
 
 	if req.Language != "" {
@@ -300,23 +805,30 @@ func (c *Client) CreateTranscription(ctx context.Context, req *TranscriptionRequ
 	if req.ResponseFormat != "" {
 		form["response_format"] = req.ResponseFormat
 	}
-	if req.Temperature != 0 {
-		form["temperature"] = fmt.Sprintf("%.2f", req.Temperature)
+	if req.Temperature != nil {
+		form["temperature"] = fmt.Sprintf("%.2f", *req.Temperature)
+	}
+	if len(req.TimestampGranularities) > 0 {
+		form["timestamp_granularities[]"] = req.TimestampGranularities
 	}
 
-	var result TranscriptionResponse
-	err := c.httpClient.DoMultipartForm(
+	ctx, trace := c.beginCall(ctx, EndpointAudio, model)
+	if err := c.waitRequestLimits(ctx, EndpointAudio, model); err != nil {
+		c.endCall(ctx, trace, err)
+		return nil, err
+	}
+	body, err := c.httpClient.DoMultipartFormRaw(
 		ctx,
 		"POST",
-		fmt.Sprintf("%s/audio/transcriptions", c.baseURL),
+		c.endpointURL(ctx, "/audio/transcriptions"),
 		form,
-		&result,
 	)
+	c.endCall(ctx, trace, err)
 	if err != nil {
-		return nil, fmt.Errorf("transcription request failed: %w", err)
+		return nil, fmt.Errorf("transcription request failed: %w", decommissionedError(model, err))
 	}
 
-	return &result, nil
+	return body, nil
 }
 
 // CreateTranslation sends an audio file to be translated into English.
@@ -325,6 +837,7 @@ func (c *Client) CreateTranscription(ctx context.Context, req *TranscriptionRequ
 //
 // The audio file must be in one of the supported formats:
 // flac, mp3, mp4, mpeg, mpga, m4a, ogg, wav, webm
+// Files larger than the model's MaxFileSize fail with a *FileTooLargeError before upload.
 //
 // If no model is specified in the request, it defaults to ModelWhisperLargeV3.
 //
@@ -333,28 +846,27 @@ func (c *Client) CreateTranscription(ctx context.Context, req *TranscriptionRequ
 //   - req: TranslationRequest containing:
 //   - File: The audio file to translate
 //   - FileName: Name of the audio file including extension
+//   - URL: Alternatively, a public URL of the audio, downloaded by the API instead of File
 //   - Model: (Optional) The model to use for translation
 //   - Prompt: (Optional) Text to guide the model's style or continue a previous audio segment
-//   - ResponseFormat: (Optional) The format of the translation response
+//   - ResponseFormat: (Optional) json, verbose_json, or text, srt and vtt, which are returned unparsed in Text
 //   - Temperature: (Optional) Sampling temperature between 0 and 1
+//   - opts: Optional settings of this call, such as WithRequestTimeout or WithRequestAPIKey
 //
 // Returns:
 //   - *TranslationResponse: Contains the translated text and other response data
 //   - error: Any error encountered during the translation request
-func (c *Client) CreateTranslation(ctx context.Context, req *TranslationRequest) (*TranslationResponse, error) {
+func (c *Client) CreateTranslation(ctx context.Context, req *TranslationRequest, opts ...RequestOption) (*TranslationResponse, error) {
+	ctx, cancel := applyRequestOptions(ctx, opts)
+	defer cancel()
 	if req.Model == "" {
 		req.Model = ModelWhisperLargeV3
 	}
 
-	ext := filepath.Ext(req.FileName)
-	if !isValidAudioFormat(ext) {
-		return nil, fmt.Errorf("invalid audio format: %s. Supported formats: flac, mp3, mp4, mpeg, mpga, m4a, ogg, wav, webm", ext)
-	}
-
-	form := map[string]interface{}{
-		"file":     req.File,
-		"filename": req.FileName,
-		"model":    string(req.Model),
+	model := c.migrateModel(req.Model)
+	form, err := c.audioForm(model, req.File, req.FileName, req.URL)
+	if err != nil {
+		return nil, err
 	}
 
 	if req.Prompt != "" {
@@ -363,25 +875,90 @@ func (c *Client) CreateTranslation(ctx context.Context, req *TranslationRequest)
 	if req.ResponseFormat != "" {
 		form["response_format"] = req.ResponseFormat
 	}
-	if req.Temperature != 0 {
-		form["temperature"] = fmt.Sprintf("%.2f", req.Temperature)
+	if req.Temperature != nil {
+		form["temperature"] = fmt.Sprintf("%.2f", *req.Temperature)
 	}
 
-	var result TranslationResponse
-	err := c.httpClient.DoMultipartForm(
+	ctx, trace := c.beginCall(ctx, EndpointAudio, model)
+	if err := c.waitRequestLimits(ctx, EndpointAudio, model); err != nil {
+		c.endCall(ctx, trace, err)
+		return nil, err
+	}
+	body, err := c.httpClient.DoMultipartFormRaw(
 		ctx,
 		"POST",
-		fmt.Sprintf("%s/audio/translations", c.baseURL),
+		c.endpointURL(ctx, "/audio/translations"),
 		form,
-		&result,
 	)
+	c.endCall(ctx, trace, err)
 	if err != nil {
-		return nil, fmt.Errorf("translation request failed: %w", err)
+		return nil, fmt.Errorf("translation request failed: %w", decommissionedError(model, err))
+	}
+
+	var result TranslationResponse
+	if isTextResponseFormat(req.ResponseFormat) {
+		result.Text = string(body)
+		return &result, nil
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("translation request failed: %w: %v", ErrJSONDecoding, err)
 	}
 
 	return &result, nil
 }
 
+// audioForm builds the multipart form fields that select the model and the audio: either
+// the uploaded file, after format sniffing and voice activity detection, or a URL the API
+// downloads itself.
+func (c *Client) audioForm(model ModelType, file io.Reader, fileName, url string) (map[string]interface{}, error) {
+	form := map[string]interface{}{
+		"model": string(model),
+	}
+
+	if url != "" {
+		if file != nil {
+			return nil, c.localize(newLocalizedError(ErrInvalidRequest, MsgAudioSourceConflict))
+		}
+		form["url"] = url
+		return form, nil
+	}
+	if file == nil {
+		return nil, c.localize(newLocalizedError(ErrInvalidRequest, MsgAudioSourceRequired))
+	}
+
+	// Without VAD the upload is the file itself, so its size can be checked before
+	// anything is read; with VAD the trimmed audio is checked instead.
+	if c.vad == nil {
+		if err := checkAudioSize(model, file); err != nil {
+			return nil, err
+		}
+	}
+
+	file, fileName, err := sniffAudio(file, fileName)
+	if err != nil {
+		return nil, c.localize(err)
+	}
+
+	file, err = c.applyVAD(file, fileName)
+	if err != nil {
+		return nil, err
+	}
+	if c.vad != nil {
+		if err := checkAudioSize(model, file); err != nil {
+			return nil, err
+		}
+	}
+	form["file"] = file
+	form["filename"] = fileName
+	return form, nil
+}
+
+// isTextResponseFormat reports whether an audio response format is returned as plain
+// text rather than JSON.
+func isTextResponseFormat(format string) bool {
+	return format == "text" || format == "srt" || format == "vtt"
+}
+
 // isValidAudioFormat checks if the provided file extension is a supported audio format.
 // Returns true if the extension is one of: .flac, .mp3, .mp4, .mpeg, .mpga, .m4a, .ogg, .wav, .webm.
 // The extension should include the dot prefix (e.g. ".mp3").
@@ -1,23 +1,48 @@
 package groq
 
 import (
+	"fmt"
+	"sort"
 	"time"
 )
 
 type Config struct {
 	RetryConfig *RetryConfig
 	RateLimit   *RateLimit
+
+	BaseURL      string        // Empty uses DefaultBaseURL
+	Timeout      time.Duration // Timeout of each request, 0 for the default of 30 seconds
+	DefaultModel ModelType     // Model of chat requests that name none, see WithDefaultModel
+	Cache        *CacheConfig  // Response cache built by NewClientFromConfig, nil for none
+}
+
+// CacheConfig describes the in-process response cache NewClientFromConfig builds. It
+// matches requests exactly and evicts the least recently used entry when it is full.
+type CacheConfig struct {
+	Enabled    bool
+	MaxEntries int           // 0 for no limit
+	TTL        time.Duration // 0 for no expiry
 }
 
 type RetryConfig struct {
 	MaxRetries int
 	RetryDelay time.Duration
 	MaxDelay   time.Duration
+	// MaxRetryAfter is the longest server-requested wait honoured before retrying; zero means one minute.
+	MaxRetryAfter time.Duration
+	// MaxRetryElapsed bounds one call including its retries, streams included; zero means no limit.
+	MaxRetryElapsed time.Duration
+	OnRetry         func(attempt int, err error, delay time.Duration) error
 }
 
 type RateLimit struct {
-	RequestsPerMinute int
-	Enabled           bool
+	RequestsPerMinute    int
+	Enabled              bool
+	TokensPerMinute      int               // Token budget of every model, 0 for no token limit
+	ModelTokensPerMinute map[ModelType]int // Overrides TokensPerMinute for specific models
+
+	EndpointRequestsPerMinute map[Endpoint]int  // Separate request budgets of endpoint families
+	ModelRequestsPerMinute    map[ModelType]int // Separate request budgets of specific models
 }
 
 // defaultConfig returns a pointer to a Config struct with default settings.
@@ -37,3 +62,84 @@ func defaultConfig() *Config {
 		},
 	}
 }
+
+// Validate checks the configuration for values and combinations that cannot work, such as
+// negative retries or rate limiting enabled without a rate. All problems are reported at
+// once in a *ConfigError, each with a hint on how to fix it.
+//
+// Returns:
+//   - error: A *ConfigError matching ErrInvalidConfig, or nil if the configuration is valid.
+func (c *Config) Validate() error {
+	var problems []string
+
+	if r := c.RetryConfig; r != nil {
+		if r.MaxRetries < 0 {
+			problems = append(problems, fmt.Sprintf("RetryConfig.MaxRetries is %d; use 0 to disable retries", r.MaxRetries))
+		}
+		if r.RetryDelay < 0 {
+			problems = append(problems, fmt.Sprintf("RetryConfig.RetryDelay is %v; it must not be negative", r.RetryDelay))
+		}
+		if r.MaxDelay < 0 {
+			problems = append(problems, fmt.Sprintf("RetryConfig.MaxDelay is %v; it must not be negative", r.MaxDelay))
+		}
+		if r.MaxRetryAfter < 0 {
+			problems = append(problems, fmt.Sprintf("RetryConfig.MaxRetryAfter is %v; it must not be negative", r.MaxRetryAfter))
+		}
+		if r.MaxRetryElapsed < 0 {
+			problems = append(problems, fmt.Sprintf("RetryConfig.MaxRetryElapsed is %v; use 0 for no limit", r.MaxRetryElapsed))
+		}
+		if r.MaxDelay > 0 && r.MaxDelay < r.RetryDelay {
+			problems = append(problems, fmt.Sprintf("RetryConfig.MaxDelay (%v) is shorter than RetryDelay (%v); raise MaxDelay or lower RetryDelay", r.MaxDelay, r.RetryDelay))
+		}
+	}
+
+	if rl := c.RateLimit; rl != nil {
+		if rl.Enabled && rl.RequestsPerMinute <= 0 {
+			problems = append(problems, fmt.Sprintf("RateLimit.RequestsPerMinute is %d while rate limiting is enabled; set a positive rate or disable rate limiting", rl.RequestsPerMinute))
+		}
+		if !rl.Enabled && rl.RequestsPerMinute < 0 {
+			problems = append(problems, fmt.Sprintf("RateLimit.RequestsPerMinute is %d; it must not be negative", rl.RequestsPerMinute))
+		}
+		if rl.TokensPerMinute < 0 {
+			problems = append(problems, fmt.Sprintf("RateLimit.TokensPerMinute is %d; use 0 to disable token limiting", rl.TokensPerMinute))
+		}
+		problems = append(problems, negativeLimits("RateLimit.ModelTokensPerMinute", rl.ModelTokensPerMinute)...)
+		problems = append(problems, negativeLimits("RateLimit.ModelRequestsPerMinute", rl.ModelRequestsPerMinute)...)
+		problems = append(problems, negativeLimits("RateLimit.EndpointRequestsPerMinute", rl.EndpointRequestsPerMinute)...)
+	}
+
+	if c.Timeout < 0 {
+		problems = append(problems, fmt.Sprintf("Timeout is %v; use 0 for the default", c.Timeout))
+	}
+	if c.DefaultModel != "" && !c.DefaultModel.IsValid() {
+		problems = append(problems, fmt.Sprintf("DefaultModel %q is not a known model; see AllModels", c.DefaultModel))
+	}
+	if cache := c.Cache; cache != nil {
+		if cache.MaxEntries < 0 {
+			problems = append(problems, fmt.Sprintf("Cache.MaxEntries is %d; use 0 for no limit", cache.MaxEntries))
+		}
+		if cache.TTL < 0 {
+			problems = append(problems, fmt.Sprintf("Cache.TTL is %v; use 0 for no expiry", cache.TTL))
+		}
+	}
+
+	return NewConfigError(problems)
+}
+
+// negativeLimits reports the negative entries of a map of per-model or per-endpoint
+// limits, in key order.
+func negativeLimits[K ~string](field string, limits map[K]int) []string {
+	keys := make([]string, 0, len(limits))
+	for key := range limits {
+		keys = append(keys, string(key))
+	}
+	sort.Strings(keys)
+
+	var problems []string
+	for _, key := range keys {
+		if limit := limits[K(key)]; limit < 0 {
+			problems = append(problems, fmt.Sprintf("%s[%s] is %d; use 0 for no limit", field, key, limit))
+		}
+	}
+	return problems
+}
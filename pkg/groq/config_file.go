@@ -0,0 +1,292 @@
+package groq
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configFile is the layout of the files read by LoadConfig. Durations are strings such
+// as "30s"; settings that are left out keep their defaults.
+type configFile struct {
+	BaseURL      string           `json:"base_url" yaml:"base_url"`
+	Timeout      fileDuration     `json:"timeout" yaml:"timeout"`
+	DefaultModel ModelType        `json:"default_model" yaml:"default_model"`
+	Retry        *retryFile       `json:"retry" yaml:"retry"`
+	RateLimit    *rateLimitFile   `json:"rate_limit" yaml:"rate_limit"`
+	Cache        *cacheConfigFile `json:"cache" yaml:"cache"`
+}
+
+type retryFile struct {
+	MaxRetries      *int         `json:"max_retries" yaml:"max_retries"`
+	RetryDelay      fileDuration `json:"retry_delay" yaml:"retry_delay"`
+	MaxDelay        fileDuration `json:"max_delay" yaml:"max_delay"`
+	MaxRetryAfter   fileDuration `json:"max_retry_after" yaml:"max_retry_after"`
+	MaxRetryElapsed fileDuration `json:"max_retry_elapsed" yaml:"max_retry_elapsed"`
+}
+
+type rateLimitFile struct {
+	Enabled                   *bool             `json:"enabled" yaml:"enabled"`
+	RequestsPerMinute         int               `json:"requests_per_minute" yaml:"requests_per_minute"`
+	TokensPerMinute           int               `json:"tokens_per_minute" yaml:"tokens_per_minute"`
+	ModelTokensPerMinute      map[ModelType]int `json:"model_tokens_per_minute" yaml:"model_tokens_per_minute"`
+	EndpointRequestsPerMinute map[Endpoint]int  `json:"endpoint_requests_per_minute" yaml:"endpoint_requests_per_minute"`
+	ModelRequestsPerMinute    map[ModelType]int `json:"model_requests_per_minute" yaml:"model_requests_per_minute"`
+}
+
+type cacheConfigFile struct {
+	Enabled    *bool        `json:"enabled" yaml:"enabled"`
+	MaxEntries int          `json:"max_entries" yaml:"max_entries"`
+	TTL        fileDuration `json:"ttl" yaml:"ttl"`
+}
+
+// fileDuration is a time.Duration written as a string such as "10m" in config files.
+type fileDuration time.Duration
+
+// UnmarshalJSON accepts a duration string ("90s", "1h") or a number of nanoseconds.
+func (d *fileDuration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		return d.parse(s)
+	}
+
+	var n int64
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("invalid duration %s", data)
+	}
+	*d = fileDuration(n)
+	return nil
+}
+
+// UnmarshalYAML accepts a duration string ("90s", "1h").
+func (d *fileDuration) UnmarshalYAML(node *yaml.Node) error {
+	var s string
+	if err := node.Decode(&s); err != nil {
+		return err
+	}
+	return d.parse(s)
+}
+
+// parse sets d from a duration string.
+func (d *fileDuration) parse(s string) error {
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %w", s, err)
+	}
+	*d = fileDuration(v)
+	return nil
+}
+
+// LoadConfig reads client settings from a JSON or YAML file, chosen by the file extension
+// (.json, .yaml or .yml), so services can manage timeouts, retries, rate limits, the default
+// model and caching declaratively. Settings that are left out keep their defaults; rate
+// limiting and the cache are enabled when their section sets a rate or is present, unless
+// it says enabled: false. Unknown settings are rejected, as they are usually typos.
+//
+// Example config:
+//
+//	base_url: https://api.groq.com/openai/v1
+//	timeout: 45s
+//	default_model: llama-3.1-8b-instant
+//	retry:
+//	  max_retries: 5
+//	  retry_delay: 500ms
+//	  max_retry_elapsed: 1m
+//	rate_limit:
+//	  requests_per_minute: 30
+//	  tokens_per_minute: 6000
+//	cache:
+//	  max_entries: 1000
+//	  ttl: 10m
+//
+// Parameters:
+//   - path: The config file.
+//
+// Returns:
+//   - *Config: The settings, ready for NewClientFromConfig.
+//   - error: An error if the file cannot be read or parsed, or a *ConfigError matching
+//     ErrInvalidConfig listing every invalid setting.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var file configFile
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&file)
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(&file)
+	default:
+		return nil, fmt.Errorf("unsupported config format %q: use .json, .yaml or .yml", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	config := file.config()
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// config returns the default configuration overlaid with the settings of the file.
+func (f *configFile) config() *Config {
+	config := defaultConfig()
+	config.BaseURL = f.BaseURL
+	config.Timeout = time.Duration(f.Timeout)
+	config.DefaultModel = f.DefaultModel
+
+	if r := f.Retry; r != nil {
+		if r.MaxRetries != nil {
+			config.RetryConfig.MaxRetries = *r.MaxRetries
+		}
+		if r.RetryDelay != 0 {
+			config.RetryConfig.RetryDelay = time.Duration(r.RetryDelay)
+		}
+		if r.MaxDelay != 0 {
+			config.RetryConfig.MaxDelay = time.Duration(r.MaxDelay)
+		}
+		config.RetryConfig.MaxRetryAfter = time.Duration(r.MaxRetryAfter)
+		config.RetryConfig.MaxRetryElapsed = time.Duration(r.MaxRetryElapsed)
+	}
+
+	if rl := f.RateLimit; rl != nil {
+		if rl.RequestsPerMinute != 0 {
+			config.RateLimit.RequestsPerMinute = rl.RequestsPerMinute
+		}
+		config.RateLimit.Enabled = rl.Enabled == nil || *rl.Enabled
+		config.RateLimit.TokensPerMinute = rl.TokensPerMinute
+		config.RateLimit.ModelTokensPerMinute = rl.ModelTokensPerMinute
+		config.RateLimit.EndpointRequestsPerMinute = rl.EndpointRequestsPerMinute
+		config.RateLimit.ModelRequestsPerMinute = rl.ModelRequestsPerMinute
+	} else {
+		// Without a rate_limit section the client keeps its own default pacing.
+		config.RateLimit.Enabled = false
+	}
+
+	if cache := f.Cache; cache != nil {
+		config.Cache = &CacheConfig{
+			Enabled:    cache.Enabled == nil || *cache.Enabled,
+			MaxEntries: cache.MaxEntries,
+			TTL:        time.Duration(cache.TTL),
+		}
+	}
+	return config
+}
+
+// NewClientFromConfig creates a client from a Config, typically one read by LoadConfig.
+// The options are applied after the config, so they can add settings a file cannot
+// express, such as hooks or a custom cache, or override it. Like NewClientE, it checks
+// the API key and the settings and reports all problems at once.
+//
+// Example usage:
+//
+//	config, err := groq.LoadConfig("groq.yaml")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	client, err := groq.NewClientFromConfig(os.Getenv("GROQ_API_KEY"), config)
+//
+// Parameters:
+//   - apiKey: The API key used for authorization.
+//   - config: The settings; nil uses the defaults.
+//   - opts: Optional configurations applied after the settings.
+//
+// Returns:
+//   - *Client: The client, or nil if there are problems.
+//   - error: A *ConfigError matching ErrInvalidConfig that lists every problem.
+func NewClientFromConfig(apiKey string, config *Config, opts ...Option) (*Client, error) {
+	if config == nil {
+		config = defaultConfig()
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return NewClientE(apiKey, append(config.options(), opts...)...)
+}
+
+// options returns the client options that apply the settings of the config.
+func (c *Config) options() []Option {
+	var opts []Option
+	if c.BaseURL != "" {
+		opts = append(opts, WithBaseURL(c.BaseURL))
+	}
+	if c.Timeout > 0 {
+		opts = append(opts, WithTimeout(c.Timeout))
+	}
+	if c.DefaultModel != "" {
+		opts = append(opts, WithDefaultModel(c.DefaultModel))
+	}
+
+	if r := c.RetryConfig; r != nil {
+		opts = append(opts,
+			WithRetryConfig(r.MaxRetries, r.RetryDelay),
+			WithMaxRetryAfter(r.MaxRetryAfter),
+			WithMaxRetryElapsed(r.MaxRetryElapsed),
+			func(client *Client) { client.config.RetryConfig.MaxDelay = r.MaxDelay },
+		)
+		if r.OnRetry != nil {
+			opts = append(opts, WithOnRetry(r.OnRetry))
+		}
+	}
+
+	if rl := c.RateLimit; rl != nil {
+		if rl.Enabled {
+			opts = append(opts, WithRateLimit(rl.RequestsPerMinute))
+		}
+		if rl.TokensPerMinute > 0 {
+			opts = append(opts, WithTokenRateLimit(rl.TokensPerMinute))
+		}
+		for model, tpm := range rl.ModelTokensPerMinute {
+			opts = append(opts, WithModelTokenRateLimit(model, tpm))
+		}
+		for endpoint, rpm := range rl.EndpointRequestsPerMinute {
+			opts = append(opts, WithEndpointRateLimit(endpoint, rpm))
+		}
+		for model, rpm := range rl.ModelRequestsPerMinute {
+			opts = append(opts, WithModelRateLimit(model, rpm))
+		}
+	}
+
+	if cache := c.Cache; cache != nil && cache.Enabled {
+		opts = append(opts, WithCache(NewLRUCache(cache.MaxEntries, cache.TTL)))
+	}
+	return opts
+}
+
+// WithDefaultModel sets the model of chat completions and streams whose request names
+// none, so callers and config files can pick the model in one place.
+//
+// Parameters:
+//   - model: The default chat model.
+//
+// Returns:
+//   - Option: A function that sets the default model.
+func WithDefaultModel(model ModelType) Option {
+	return func(c *Client) {
+		c.config.DefaultModel = model
+	}
+}
+
+// withDefaultModel returns req, or a copy of it naming the default model if it names none.
+func (c *Client) withDefaultModel(req *ChatCompletionRequest) *ChatCompletionRequest {
+	if req == nil || req.Model != "" || c.config.DefaultModel == "" {
+		return req
+	}
+	defaulted := *req
+	defaulted.Model = c.config.DefaultModel
+	return &defaulted
+}
@@ -0,0 +1,116 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	yamlPath := writeConfig(t, "groq.yaml", `
+timeout: 45s
+default_model: llama-3.1-8b-instant
+retry:
+  max_retries: 0
+  retry_delay: 500ms
+rate_limit:
+  requests_per_minute: 30
+  model_tokens_per_minute:
+    llama-3.1-8b-instant: 6000
+cache:
+  max_entries: 10
+  ttl: 10m
+`)
+	jsonPath := writeConfig(t, "groq.json", `{
+  "timeout": "45s",
+  "default_model": "llama-3.1-8b-instant",
+  "retry": {"max_retries": 0, "retry_delay": "500ms"},
+  "rate_limit": {"requests_per_minute": 30, "model_tokens_per_minute": {"llama-3.1-8b-instant": 6000}},
+  "cache": {"max_entries": 10, "ttl": "10m"}
+}`)
+
+	for _, path := range []string{yamlPath, jsonPath} {
+		config, err := LoadConfig(path)
+		if err != nil {
+			t.Fatalf("LoadConfig(%s): %v", filepath.Base(path), err)
+		}
+		if config.Timeout != 45*time.Second || config.DefaultModel != ModelLlama31_8bInstant {
+			t.Errorf("%s: unexpected settings %+v", filepath.Base(path), config)
+		}
+		if r := config.RetryConfig; r.MaxRetries != 0 || r.RetryDelay != 500*time.Millisecond || r.MaxDelay != 5*time.Second {
+			t.Errorf("%s: unexpected retry settings %+v", filepath.Base(path), r)
+		}
+		if rl := config.RateLimit; !rl.Enabled || rl.RequestsPerMinute != 30 || rl.ModelTokensPerMinute[ModelLlama31_8bInstant] != 6000 {
+			t.Errorf("%s: unexpected rate limit %+v", filepath.Base(path), rl)
+		}
+		if c := config.Cache; c == nil || !c.Enabled || c.MaxEntries != 10 || c.TTL != 10*time.Minute {
+			t.Errorf("%s: unexpected cache settings %+v", filepath.Base(path), c)
+		}
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	if _, err := LoadConfig(writeConfig(t, "groq.yaml", "timout: 1s\n")); err == nil || !strings.Contains(err.Error(), "timout") {
+		t.Errorf("expected the unknown setting to be reported, got %v", err)
+	}
+	if _, err := LoadConfig(writeConfig(t, "groq.toml", "")); err == nil {
+		t.Error("expected an unsupported format error")
+	}
+
+	_, err := LoadConfig(writeConfig(t, "groq.json", `{"timeout":"-1s","cache":{"max_entries":-1}}`))
+	var configErr *ConfigError
+	if !errors.As(err, &configErr) || len(configErr.Problems) != 2 {
+		t.Errorf("expected two problems, got %v", err)
+	}
+}
+
+func TestNewClientFromConfig(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","choices":[{"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}]}`))
+	}))
+	defer srv.Close()
+
+	config, err := LoadConfig(writeConfig(t, "groq.yaml", "base_url: "+srv.URL+"\ndefault_model: llama-3.1-8b-instant\ncache: {}\n"))
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	client, err := NewClientFromConfig("test-key", config)
+	if err != nil {
+		t.Fatalf("NewClientFromConfig: %v", err)
+	}
+	defer client.Close()
+
+	req := &ChatCompletionRequest{Messages: []ChatMessage{{Role: "user", Content: "hi"}}}
+	for i := 0; i < 2; i++ {
+		if _, err := client.CreateChatCompletion(context.Background(), req); err != nil {
+			t.Fatalf("CreateChatCompletion: %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected the second call to be served from the cache, got %d API calls", calls)
+	}
+	if stats := client.GetCacheStats(); stats == nil || stats.Hits != 1 {
+		t.Errorf("unexpected cache stats %+v", stats)
+	}
+	if req.Model != "" {
+		t.Error("the default model was written into the caller's request")
+	}
+}
@@ -1,8 +1,12 @@
 package groq
 
 import (
+	"errors"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/genc-murat/groq-client/internal/util"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -27,3 +31,112 @@ func TestDefaultConfig(t *testing.T) {
 		t.Error("Expected RateLimit to be enabled")
 	}
 }
+
+func TestConfigValidate(t *testing.T) {
+	if err := defaultConfig().Validate(); err != nil {
+		t.Fatalf("expected the default config to be valid, got %v", err)
+	}
+
+	config := defaultConfig()
+	config.RetryConfig.MaxRetries = -1
+	config.RetryConfig.MaxDelay = time.Millisecond
+	config.RateLimit.RequestsPerMinute = 0
+
+	err := config.Validate()
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("expected ErrInvalidConfig, got %v", err)
+	}
+	var configErr *ConfigError
+	if !errors.As(err, &configErr) || len(configErr.Problems) != 3 {
+		t.Fatalf("expected 3 aggregated problems, got %v", err)
+	}
+	for _, want := range []string{"MaxRetries", "MaxDelay", "RequestsPerMinute"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected the error to mention %s, got %q", want, err)
+		}
+	}
+}
+
+func TestConfigValidateLimitMaps(t *testing.T) {
+	config := defaultConfig()
+	config.RateLimit.ModelRequestsPerMinute = map[ModelType]int{ModelLlama31_8bInstant: 30, ModelLlama33_70bVersatile: -1}
+	config.RateLimit.EndpointRequestsPerMinute = map[Endpoint]int{EndpointAudio: -5}
+
+	var configErr *ConfigError
+	if err := config.Validate(); !errors.As(err, &configErr) || len(configErr.Problems) != 2 {
+		t.Fatalf("expected 2 problems, got %v", err)
+	}
+	if !strings.Contains(configErr.Problems[0], string(ModelLlama33_70bVersatile)) || !strings.Contains(configErr.Problems[1], "audio") {
+		t.Errorf("unexpected problems: %q", configErr.Problems)
+	}
+}
+
+func TestNewClientE(t *testing.T) {
+	client, err := NewClientE("gsk_test")
+	if err != nil {
+		t.Fatalf("NewClientE() error = %v", err)
+	}
+	_ = client.Close()
+
+	_, err = NewClientE(" ",
+		WithBaseURL("api.groq.com/openai/v1"),
+		WithRetryConfig(-1, time.Second),
+	)
+	var configErr *ConfigError
+	if !errors.As(err, &configErr) || len(configErr.Problems) != 3 {
+		t.Fatalf("expected 3 problems, got %v", err)
+	}
+	for _, want := range []string{"API key is empty", "base URL", "MaxRetries"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected the error to mention %q, got %q", want, err)
+		}
+	}
+
+	_, err = NewClientE("gsk_test\n", WithBaseURLs("https://a.example/v1", "https://b.example/v1", "https://c.example/v1"), WithRetryConfig(1, time.Second))
+	if err == nil || !strings.Contains(err.Error(), "whitespace") || !strings.Contains(err.Error(), "at least 2 retries") {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestNewClientEOptionConflicts(t *testing.T) {
+	_, err := NewClientE("gsk_test",
+		WithHTTPConfig(util.HTTPClientConfig{MaxRequestTimeout: time.Minute}),
+		WithTimeout(0),
+		WithBaseURL("https://a.example/v1"),
+		WithBaseURLs("https://b.example/v1", "https://c.example/v1"),
+		WithRateLimit(30),
+		WithRateLimiter(util.NewAdaptiveRateLimiter(1)),
+	)
+	var configErr *ConfigError
+	if !errors.As(err, &configErr) || len(configErr.Problems) != 4 {
+		t.Fatalf("expected 4 problems, got %v", err)
+	}
+	for _, want := range []string{"WithHTTPConfig and WithTimeout both set the timeout", "WithBaseURL and WithBaseURLs", "timeout is 0s", "custom rate limiter"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected the error to mention %q, got %q", want, err)
+		}
+	}
+}
+
+func TestOptionsOrderIndependent(t *testing.T) {
+	orders := [][]Option{
+		{WithBaseHeaders(map[string]string{"X-Tenant": "acme"}), WithRetryConfig(0, time.Millisecond), WithTimeout(time.Minute), WithRateLimit(120)},
+		{WithRateLimit(120), WithTimeout(time.Minute), WithRetryConfig(0, time.Millisecond), WithBaseHeaders(map[string]string{"X-Tenant": "acme"})},
+	}
+	for i, opts := range orders {
+		client, err := NewClientE("gsk_test", opts...)
+		if err != nil {
+			t.Fatalf("order %d: NewClientE() error = %v", i, err)
+		}
+		if got := client.httpClient.Timeout(); got != time.Minute {
+			t.Errorf("order %d: timeout = %v, want 1m", i, got)
+		}
+		if got := client.httpClient.GetBaseHeaders()["X-Tenant"]; got != "acme" {
+			t.Errorf("order %d: X-Tenant = %q, want acme", i, got)
+		}
+		if client.config.RetryConfig.MaxRetries != 0 || client.config.RateLimit.RequestsPerMinute != 120 {
+			t.Errorf("order %d: unexpected config %+v %+v", i, client.config.RetryConfig, client.config.RateLimit)
+		}
+		_ = client.Close()
+	}
+}
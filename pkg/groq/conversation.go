@@ -0,0 +1,336 @@
+package groq
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultSummaryModel       = ModelLlama31_8bInstant
+	defaultSummaryMaxMessages = 20
+	defaultSummaryKeepRecent  = 6
+)
+
+// MemoryStrategy decides which messages of a conversation history are sent to the model.
+// Implementations may drop, rewrite or summarize older turns, but must not modify the
+// slice they are given.
+type MemoryStrategy interface {
+	Apply(ctx context.Context, history []ChatMessage) ([]ChatMessage, error)
+}
+
+type Conversation struct {
+	client      *Client
+	ID          string
+	Model       ModelType
+	System      string
+	Messages    []ChatMessage
+	Memory      MemoryStrategy
+	MaxTokens   int
+	Temperature *float64 // nil uses the API default
+	// CacheSession, if set, makes the conversation read its own cache writes, so a repeated
+	// question is answered consistently even while the cache is still persisting the reply.
+	CacheSession *CacheSession
+	mu           sync.Mutex
+}
+
+// NewConversation creates a new Conversation bound to the client.
+// The conversation keeps the full message history and, on every Send, passes it
+// through the configured MemoryStrategy (if any) before building the request.
+//
+// Parameters:
+//   - model: The model used for every turn of the conversation.
+//   - system: Optional system prompt that is always sent as the first message.
+//
+// Returns:
+//   - *Conversation: A pointer to the newly created Conversation.
+func (c *Client) NewConversation(model ModelType, system string) *Conversation {
+	return &Conversation{
+		client:   c,
+		ID:       newConversationID(),
+		Model:    model,
+		System:   system,
+		Messages: make([]ChatMessage, 0),
+	}
+}
+
+// newConversationID returns a random identifier used to save and resume conversations.
+func newConversationID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("conv_%d", time.Now().UnixNano())
+	}
+	return "conv_" + hex.EncodeToString(b)
+}
+
+// Snapshot returns a serializable copy of the conversation state.
+func (cv *Conversation) Snapshot() *ConversationSnapshot {
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+
+	messages := make([]ChatMessage, len(cv.Messages))
+	copy(messages, cv.Messages)
+
+	return &ConversationSnapshot{
+		ID:          cv.ID,
+		Model:       cv.Model,
+		System:      cv.System,
+		Messages:    messages,
+		MaxTokens:   cv.MaxTokens,
+		Temperature: cv.Temperature,
+		UpdatedAt:   time.Now(),
+	}
+}
+
+// Save stores the current conversation state in the given store under the conversation ID.
+func (cv *Conversation) Save(ctx context.Context, store ConversationStore) error {
+	return store.Save(ctx, cv.Snapshot())
+}
+
+// ResumeConversation loads a previously saved conversation from the store and binds it
+// to the client so it can be continued with Send.
+//
+// Parameters:
+//   - ctx: Context for the store operation.
+//   - store: The ConversationStore the conversation was saved to.
+//   - id: The conversation ID.
+//
+// Returns:
+//   - *Conversation: The restored conversation.
+//   - error: ErrConversationNotFound if no conversation with the ID exists, or a store error.
+func (c *Client) ResumeConversation(ctx context.Context, store ConversationStore, id string) (*Conversation, error) {
+	snapshot, err := store.Load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Conversation{
+		client:      c,
+		ID:          snapshot.ID,
+		Model:       snapshot.Model,
+		System:      snapshot.System,
+		Messages:    snapshot.Messages,
+		MaxTokens:   snapshot.MaxTokens,
+		Temperature: snapshot.Temperature,
+	}, nil
+}
+
+// Add appends a message with the given role and content to the conversation history.
+func (cv *Conversation) Add(role string, content interface{}) {
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+
+	cv.Messages = append(cv.Messages, ChatMessage{Role: role, Content: content})
+}
+
+// History returns a copy of the full, uncompressed message history.
+func (cv *Conversation) History() []ChatMessage {
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+
+	history := make([]ChatMessage, len(cv.Messages))
+	copy(history, cv.Messages)
+	return history
+}
+
+// Send appends the user message to the history, sends the conversation to the model
+// and records the assistant reply. If the request fails the user message is removed
+// again so the conversation can be retried.
+//
+// Parameters:
+//   - ctx: Context for the request, used for timeouts and cancellation.
+//   - content: The user message content (a string or []ContentType).
+//
+// Returns:
+//   - *ChatCompletionResponse: The raw response of the completion request.
+//   - error: Non-nil if the memory strategy or the completion request fails.
+func (cv *Conversation) Send(ctx context.Context, content interface{}) (*ChatCompletionResponse, error) {
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+
+	cv.Messages = append(cv.Messages, ChatMessage{Role: "user", Content: content})
+
+	req, err := cv.buildRequest(ctx)
+	if err != nil {
+		cv.Messages = cv.Messages[:len(cv.Messages)-1]
+		return nil, err
+	}
+
+	if cv.CacheSession != nil {
+		ctx = ContextWithCacheSession(ctx, cv.CacheSession)
+	}
+
+	resp, err := cv.client.CreateChatCompletion(ctx, req)
+	if err != nil {
+		cv.Messages = cv.Messages[:len(cv.Messages)-1]
+		return nil, err
+	}
+
+	if len(resp.Choices) > 0 {
+		cv.Messages = append(cv.Messages, ChatMessage{
+			Role:    "assistant",
+			Content: resp.Choices[0].Message.Content,
+		})
+	}
+
+	return resp, nil
+}
+
+// buildRequest assembles the ChatCompletionRequest for the current history,
+// applying the memory strategy and prepending the system prompt.
+// The caller must hold cv.mu.
+func (cv *Conversation) buildRequest(ctx context.Context) (*ChatCompletionRequest, error) {
+	history := cv.Messages
+	if cv.Memory != nil {
+		compacted, err := cv.Memory.Apply(ctx, history)
+		if err != nil {
+			return nil, fmt.Errorf("memory strategy failed: %w", err)
+		}
+		history = compacted
+	}
+
+	messages := make([]ChatMessage, 0, len(history)+1)
+	if cv.System != "" {
+		messages = append(messages, ChatMessage{Role: "system", Content: cv.System})
+	}
+	messages = append(messages, history...)
+
+	return &ChatCompletionRequest{
+		Model:       cv.Model,
+		Messages:    messages,
+		MaxTokens:   cv.MaxTokens,
+		Temperature: cv.Temperature,
+	}, nil
+}
+
+type SummarizingMemory struct {
+	client      *Client
+	Model       ModelType
+	MaxMessages int
+	KeepRecent  int
+
+	summary    string
+	summarized int
+	mu         sync.Mutex
+}
+
+// NewSummarizingMemory creates a MemoryStrategy that keeps the most recent turns verbatim
+// and replaces everything older with a running summary produced by a cheap model.
+// Summarization only happens once the messages kept verbatim grow beyond MaxMessages,
+// and then folds them down to half of MaxMessages (but never below KeepRecent); the
+// summary is updated incrementally so each message is summarized at most once.
+//
+// Defaults:
+//   - Model: ModelLlama31_8bInstant
+//   - MaxMessages: 20
+//   - KeepRecent: 6
+//
+// Parameters:
+//   - client: The client used to call the summarization model.
+//
+// Returns:
+//   - *SummarizingMemory: A pointer to the newly created SummarizingMemory.
+func NewSummarizingMemory(client *Client) *SummarizingMemory {
+	return &SummarizingMemory{
+		client:      client,
+		Model:       defaultSummaryModel,
+		MaxMessages: defaultSummaryMaxMessages,
+		KeepRecent:  defaultSummaryKeepRecent,
+	}
+}
+
+// Apply implements MemoryStrategy. When the messages after the summary exceed
+// MaxMessages, all but the last max(KeepRecent, MaxMessages/2) messages are folded into
+// the running summary, which is returned as a single system message followed by the
+// recent messages.
+func (m *SummarizingMemory) Apply(ctx context.Context, history []ChatMessage) ([]ChatMessage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(history) < m.summarized {
+		// History was reset or truncated; start over.
+		m.summary = ""
+		m.summarized = 0
+	}
+
+	if len(history) <= m.MaxMessages && m.summarized == 0 {
+		return history, nil
+	}
+
+	// Summarizing down to half of MaxMessages leaves room for new turns, so the summary
+	// is not rewritten on every turn once the threshold is reached.
+	cutoff := m.summarized
+	if len(history)-m.summarized > m.MaxMessages {
+		cutoff = max(len(history)-max(m.KeepRecent, m.MaxMessages/2), 0)
+	}
+
+	if cutoff > m.summarized {
+		summary, err := m.summarize(ctx, history[m.summarized:cutoff])
+		if err != nil {
+			return nil, err
+		}
+		m.summary = summary
+		m.summarized = cutoff
+	}
+
+	result := make([]ChatMessage, 0, len(history)-m.summarized+1)
+	if m.summary != "" {
+		result = append(result, ChatMessage{
+			Role:    "system",
+			Content: "Summary of the earlier conversation: " + m.summary,
+		})
+	}
+	result = append(result, history[m.summarized:]...)
+
+	return result, nil
+}
+
+// Summary returns the current running summary, or an empty string if nothing
+// has been summarized yet.
+func (m *SummarizingMemory) Summary() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.summary
+}
+
+// summarize asks the summary model to fold the given messages into the existing summary.
+func (m *SummarizingMemory) summarize(ctx context.Context, messages []ChatMessage) (string, error) {
+	var transcript strings.Builder
+	if m.summary != "" {
+		transcript.WriteString("Existing summary: ")
+		transcript.WriteString(m.summary)
+		transcript.WriteString("\n\n")
+	}
+	for _, msg := range messages {
+		fmt.Fprintf(&transcript, "%s: %s\n", msg.Role, msg.GetCacheKey())
+	}
+
+	req := &ChatCompletionRequest{
+		Model: m.Model,
+		Messages: []ChatMessage{
+			{
+				Role:    "system",
+				Content: "Summarize the following conversation concisely. Preserve names, facts, decisions and open questions. Reply with the summary only.",
+			},
+			{
+				Role:    "user",
+				Content: transcript.String(),
+			},
+		},
+	}
+
+	resp, err := m.client.CreateChatCompletion(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("summarization failed: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("summarization returned no choices")
+	}
+
+	return strings.TrimSpace(fmt.Sprintf("%v", resp.Choices[0].Message.Content)), nil
+}
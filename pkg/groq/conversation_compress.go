@@ -0,0 +1,134 @@
+package groq
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	defaultCompressMaxMessages = 20
+	defaultCompressKeepRecent  = 6
+	defaultCompressRatio       = 0.3
+)
+
+// sentenceBoundary splits message text into extractive snippets.
+var sentenceBoundary = regexp.MustCompile(`(?:[.!?]+\s+|\n+)`)
+
+type CompressingMemory struct {
+	Embedder    Embedder
+	MaxMessages int     // Compression starts once the history is longer than this
+	KeepRecent  int     // Most recent messages that are always kept verbatim
+	Ratio       float64 // Share of older snippets kept, in (0, 1]; higher keeps more context
+}
+
+// NewCompressingMemory creates a MemoryStrategy that compresses long conversations by
+// extraction instead of summarization: older turns are split into sentences, each is
+// ranked by embedding similarity to the latest user message, and only the best Ratio of
+// them are kept, verbatim and in their original order, as a single system message.
+// Nothing is rewritten by a model, so compression is cheap and never invents facts.
+//
+// Defaults:
+//   - MaxMessages: 20
+//   - KeepRecent: 6
+//   - Ratio: 0.3
+//
+// Parameters:
+//   - embedder: Ranks the snippets; nil uses a local HashingEmbedder.
+//
+// Returns:
+//   - *CompressingMemory: A pointer to the newly created CompressingMemory.
+func NewCompressingMemory(embedder Embedder) *CompressingMemory {
+	if embedder == nil {
+		embedder = NewHashingEmbedder(0)
+	}
+	return &CompressingMemory{
+		Embedder:    embedder,
+		MaxMessages: defaultCompressMaxMessages,
+		KeepRecent:  defaultCompressKeepRecent,
+		Ratio:       defaultCompressRatio,
+	}
+}
+
+// compressSnippet is a sentence of an older message.
+type compressSnippet struct {
+	role  string
+	text  string
+	order int
+	score float64
+}
+
+// Apply implements MemoryStrategy.
+func (m *CompressingMemory) Apply(ctx context.Context, history []ChatMessage) ([]ChatMessage, error) {
+	if len(history) <= m.MaxMessages {
+		return history, nil
+	}
+
+	cutoff := max(len(history)-m.KeepRecent, 0)
+	query := ""
+	if idx := lastUserMessage(history); idx >= 0 {
+		query = history[idx].GetCacheKey()
+	}
+
+	var snippets []compressSnippet
+	for _, msg := range history[:cutoff] {
+		for _, sentence := range sentenceBoundary.Split(msg.GetCacheKey(), -1) {
+			if sentence = strings.TrimSpace(sentence); sentence != "" {
+				snippets = append(snippets, compressSnippet{role: msg.Role, text: sentence, order: len(snippets)})
+			}
+		}
+	}
+
+	result := make([]ChatMessage, 0, len(history)-cutoff+1)
+	if len(snippets) > 0 {
+		selected, err := m.selectSnippets(ctx, query, snippets)
+		if err != nil {
+			return nil, err
+		}
+
+		var excerpt strings.Builder
+		excerpt.WriteString("Relevant excerpts from the earlier conversation:")
+		for _, s := range selected {
+			fmt.Fprintf(&excerpt, "\n- %s: %s", s.role, s.text)
+		}
+		result = append(result, ChatMessage{Role: "system", Content: excerpt.String()})
+	}
+	result = append(result, history[cutoff:]...)
+
+	return result, nil
+}
+
+// selectSnippets keeps the Ratio of snippets most similar to query, in their original order.
+func (m *CompressingMemory) selectSnippets(ctx context.Context, query string, snippets []compressSnippet) ([]compressSnippet, error) {
+	ratio := m.Ratio
+	if ratio <= 0 || ratio > 1 {
+		ratio = defaultCompressRatio
+	}
+	keep := int(math.Ceil(ratio * float64(len(snippets))))
+
+	texts := make([]string, 0, len(snippets)+1)
+	texts = append(texts, query)
+	for _, s := range snippets {
+		texts = append(texts, s.text)
+	}
+
+	vectors, err := m.Embedder.Embed(ctx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("context compression failed: %w", err)
+	}
+	if len(vectors) != len(texts) {
+		return nil, fmt.Errorf("context compression failed: embedder returned %d vectors for %d texts", len(vectors), len(texts))
+	}
+
+	for i := range snippets {
+		snippets[i].score = cosineSimilarity(vectors[0], vectors[i+1])
+	}
+	sort.SliceStable(snippets, func(i, j int) bool { return snippets[i].score > snippets[j].score })
+	selected := snippets[:keep]
+	sort.Slice(selected, func(i, j int) bool { return selected[i].order < selected[j].order })
+
+	return selected, nil
+}
@@ -0,0 +1,51 @@
+package groq
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCompressingMemory(t *testing.T) {
+	history := []ChatMessage{
+		{Role: "user", Content: "My cat is called Pixel. I also like hiking in the Alps."},
+		{Role: "assistant", Content: "Pixel is a great name. The Alps are beautiful in summer."},
+	}
+	for i := 0; i < 10; i++ {
+		history = append(history,
+			ChatMessage{Role: "user", Content: fmt.Sprintf("Tell me about topic %d.", i)},
+			ChatMessage{Role: "assistant", Content: fmt.Sprintf("Topic %d is interesting.", i)},
+		)
+	}
+	history = append(history, ChatMessage{Role: "user", Content: "What is my cat called?"})
+
+	memory := NewCompressingMemory(nil)
+	memory.MaxMessages = 10
+	memory.KeepRecent = 3
+	memory.Ratio = 0.1
+
+	compacted, err := memory.Apply(context.Background(), history)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	if len(compacted) != 4 {
+		t.Fatalf("expected excerpts plus 3 recent messages, got %d", len(compacted))
+	}
+	excerpt := compacted[0].GetCacheKey()
+	if compacted[0].Role != "system" || !strings.Contains(excerpt, "My cat is called Pixel") {
+		t.Errorf("expected the relevant snippet to be kept, got %q", excerpt)
+	}
+	if strings.Contains(excerpt, "Alps") {
+		t.Errorf("expected irrelevant snippets to be dropped, got %q", excerpt)
+	}
+	if compacted[3].GetCacheKey() != "What is my cat called?" {
+		t.Errorf("expected the recent messages to be kept verbatim, got %v", compacted[3].Content)
+	}
+
+	short := history[:4]
+	if got, _ := memory.Apply(context.Background(), short); len(got) != len(short) {
+		t.Errorf("expected short histories to be left alone, got %d messages", len(got))
+	}
+}
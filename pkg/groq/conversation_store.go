@@ -0,0 +1,250 @@
+package groq
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type ConversationSnapshot struct {
+	ID          string        `json:"id"`
+	Model       ModelType     `json:"model"`
+	System      string        `json:"system,omitempty"`
+	Messages    []ChatMessage `json:"messages"`
+	MaxTokens   int           `json:"max_tokens,omitempty"`
+	Temperature *float64      `json:"temperature,omitempty"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+// ConversationStore persists conversation snapshots so chat sessions survive
+// process restarts and can be resumed by ID.
+type ConversationStore interface {
+	Save(ctx context.Context, snapshot *ConversationSnapshot) error
+	Load(ctx context.Context, id string) (*ConversationSnapshot, error)
+	List(ctx context.Context) ([]string, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type FileConversationStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFileConversationStore creates a ConversationStore that keeps one JSON file per
+// conversation in dir. The directory is created if it does not exist.
+//
+// Parameters:
+//   - dir: The directory where conversation files are stored.
+//
+// Returns:
+//   - *FileConversationStore: A pointer to the newly created store.
+//   - error: An error if the directory cannot be created.
+func NewFileConversationStore(dir string) (*FileConversationStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create conversation directory: %w", err)
+	}
+	return &FileConversationStore{dir: dir}, nil
+}
+
+// Save writes the snapshot to <dir>/<id>.json. The file is written to a temporary
+// file first and renamed, so a crash never leaves a truncated conversation behind.
+func (s *FileConversationStore) Save(ctx context.Context, snapshot *ConversationSnapshot) error {
+	path, err := s.path(snapshot.ID)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrJSONEncoding, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write conversation: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// Load reads the snapshot stored under id.
+// It returns ErrConversationNotFound if the conversation does not exist.
+func (s *FileConversationStore) Load(ctx context.Context, id string) (*ConversationSnapshot, error) {
+	path, err := s.path(id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrConversationNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to read conversation: %w", err)
+	}
+
+	var snapshot ConversationSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrJSONDecoding, err)
+	}
+
+	return &snapshot, nil
+}
+
+// List returns the IDs of all stored conversations in lexical order.
+func (s *FileConversationStore) List(ctx context.Context) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conversations: %w", err)
+	}
+
+	ids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		ids = append(ids, strings.TrimSuffix(name, ".json"))
+	}
+	sort.Strings(ids)
+
+	return ids, nil
+}
+
+// Delete removes the conversation stored under id. Deleting a missing conversation is not an error.
+func (s *FileConversationStore) Delete(ctx context.Context, id string) error {
+	path, err := s.path(id)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete conversation: %w", err)
+	}
+	return nil
+}
+
+// path returns the file path for a conversation ID, rejecting IDs that would escape the store directory.
+func (s *FileConversationStore) path(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) || id == "." || id == ".." {
+		return "", fmt.Errorf("%w: invalid conversation id %q", ErrInvalidRequest, id)
+	}
+	return filepath.Join(s.dir, id+".json"), nil
+}
+
+type SQLiteConversationStore struct {
+	db    *sql.DB
+	table string
+}
+
+// NewSQLiteConversationStore creates a ConversationStore backed by a SQLite database.
+// The caller opens the *sql.DB with the SQLite driver of their choice (e.g. mattn/go-sqlite3
+// or modernc.org/sqlite), which keeps this package free of cgo and driver dependencies.
+// The conversations table is created if it does not exist.
+//
+// Parameters:
+//   - ctx: Context for the schema migration.
+//   - db: An open SQLite database handle.
+//
+// Returns:
+//   - *SQLiteConversationStore: A pointer to the newly created store.
+//   - error: An error if the table cannot be created.
+func NewSQLiteConversationStore(ctx context.Context, db *sql.DB) (*SQLiteConversationStore, error) {
+	s := &SQLiteConversationStore{db: db, table: "groq_conversations"}
+
+	_, err := db.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		id         TEXT PRIMARY KEY,
+		data       TEXT NOT NULL,
+		updated_at TIMESTAMP NOT NULL
+	)`, s.table))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create conversations table: %w", err)
+	}
+
+	return s, nil
+}
+
+// Save inserts or replaces the snapshot row for snapshot.ID.
+func (s *SQLiteConversationStore) Save(ctx context.Context, snapshot *ConversationSnapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrJSONEncoding, err)
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		fmt.Sprintf(`INSERT INTO %s (id, data, updated_at) VALUES (?, ?, ?)
+			ON CONFLICT(id) DO UPDATE SET data = excluded.data, updated_at = excluded.updated_at`, s.table),
+		snapshot.ID, string(data), snapshot.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save conversation: %w", err)
+	}
+	return nil
+}
+
+// Load reads the snapshot stored under id.
+// It returns ErrConversationNotFound if the conversation does not exist.
+func (s *SQLiteConversationStore) Load(ctx context.Context, id string) (*ConversationSnapshot, error) {
+	var data string
+	err := s.db.QueryRowContext(ctx, fmt.Sprintf(`SELECT data FROM %s WHERE id = ?`, s.table), id).Scan(&data)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s", ErrConversationNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to load conversation: %w", err)
+	}
+
+	var snapshot ConversationSnapshot
+	if err := json.Unmarshal([]byte(data), &snapshot); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrJSONDecoding, err)
+	}
+
+	return &snapshot, nil
+}
+
+// List returns the IDs of all stored conversations, most recently updated first.
+func (s *SQLiteConversationStore) List(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`SELECT id FROM %s ORDER BY updated_at DESC`, s.table))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conversations: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to list conversations: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// Delete removes the conversation stored under id. Deleting a missing conversation is not an error.
+func (s *SQLiteConversationStore) Delete(ctx context.Context, id string) error {
+	if _, err := s.db.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE id = ?`, s.table), id); err != nil {
+		return fmt.Errorf("failed to delete conversation: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package groq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// newTestServer starts an httptest server that answers every chat completion
+// request with the string returned by reply.
+func newTestServer(t *testing.T, reply func(req *ChatCompletionRequest) string) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ChatCompletionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		resp := map[string]interface{}{
+			"id":    "chatcmpl-test",
+			"model": req.Model,
+			"choices": []map[string]interface{}{
+				{
+					"message":       map[string]string{"role": "assistant", "content": reply(&req)},
+					"finish_reason": "stop",
+				},
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestConversationSend(t *testing.T) {
+	srv := newTestServer(t, func(req *ChatCompletionRequest) string {
+		return fmt.Sprintf("reply to %d messages", len(req.Messages))
+	})
+	client := NewClient("test-key", WithBaseURL(srv.URL))
+
+	conv := client.NewConversation(ModelLlama31_8bInstant, "be brief")
+	if _, err := conv.Send(context.Background(), "hello"); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	history := conv.History()
+	if len(history) != 2 {
+		t.Fatalf("expected 2 messages in history, got %d", len(history))
+	}
+	if history[1].Content != "reply to 2 messages" {
+		t.Errorf("unexpected assistant reply: %v", history[1].Content)
+	}
+}
+
+func TestSummarizingMemory(t *testing.T) {
+	var summaries int32
+	srv := newTestServer(t, func(req *ChatCompletionRequest) string {
+		if req.Model == ModelLlama31_8bInstant {
+			atomic.AddInt32(&summaries, 1)
+			return "summary"
+		}
+		return "ok"
+	})
+	client := NewClient("test-key", WithBaseURL(srv.URL))
+
+	memory := NewSummarizingMemory(client)
+	memory.MaxMessages = 4
+	memory.KeepRecent = 2
+
+	history := []ChatMessage{
+		{Role: "user", Content: "1"},
+		{Role: "assistant", Content: "2"},
+		{Role: "user", Content: "3"},
+	}
+
+	got, err := memory.Apply(context.Background(), history)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if len(got) != 3 || atomic.LoadInt32(&summaries) != 0 {
+		t.Fatalf("expected history below threshold to pass through unchanged")
+	}
+
+	history = append(history,
+		ChatMessage{Role: "assistant", Content: "4"},
+		ChatMessage{Role: "user", Content: "5"},
+	)
+
+	got, err = memory.Apply(context.Background(), history)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected summary + 2 recent messages, got %d", len(got))
+	}
+	if got[0].Role != "system" || got[2].Content != "5" {
+		t.Errorf("unexpected compacted history: %+v", got)
+	}
+	if memory.Summary() != "summary" {
+		t.Errorf("Summary() = %q, want %q", memory.Summary(), "summary")
+	}
+
+	// Applying again without new messages must not summarize again.
+	if _, err := memory.Apply(context.Background(), history); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if n := atomic.LoadInt32(&summaries); n != 1 {
+		t.Errorf("expected 1 summarization call, got %d", n)
+	}
+}
+
+func TestSummarizingMemoryHysteresis(t *testing.T) {
+	var summaries int32
+	srv := newTestServer(t, func(req *ChatCompletionRequest) string {
+		atomic.AddInt32(&summaries, 1)
+		return "summary"
+	})
+	client := NewClient("test-key", WithBaseURL(srv.URL))
+
+	memory := NewSummarizingMemory(client)
+	memory.MaxMessages = 10
+	memory.KeepRecent = 2
+
+	var history []ChatMessage
+	var sizes []int
+	for i := 1; i <= 20; i++ {
+		history = append(history, ChatMessage{Role: "user", Content: fmt.Sprint(i)})
+		got, err := memory.Apply(context.Background(), history)
+		if err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		sizes = append(sizes, len(got))
+	}
+
+	// Crossing 10 messages folds them down to 5, leaving room for 5 more turns before
+	// the next summarization.
+	if n := atomic.LoadInt32(&summaries); n != 2 {
+		t.Errorf("expected 2 summarization calls for 20 turns, got %d", n)
+	}
+	if sizes[10] != 6 || sizes[15] != 11 || sizes[16] != 6 {
+		t.Errorf("unexpected history sizes: %v", sizes)
+	}
+}
+
+func TestFileConversationStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewFileConversationStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileConversationStore() error = %v", err)
+	}
+
+	client := NewClient("test-key")
+	conv := client.NewConversation(ModelLlama31_8bInstant, "be brief")
+	conv.Add("user", []ContentType{NewTextContent("what is this?"), NewImageURLContent("https://example.com/a.png")})
+	conv.Add("assistant", "a cat")
+
+	if err := conv.Save(ctx, store); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	ids, err := store.List(ctx)
+	if err != nil || len(ids) != 1 || ids[0] != conv.ID {
+		t.Fatalf("List() = %v, %v", ids, err)
+	}
+
+	resumed, err := client.ResumeConversation(ctx, store, conv.ID)
+	if err != nil {
+		t.Fatalf("ResumeConversation() error = %v", err)
+	}
+	history := resumed.History()
+	if len(history) != 2 || resumed.System != "be brief" {
+		t.Fatalf("unexpected resumed conversation: %+v", resumed)
+	}
+	if parts, ok := history[0].Content.([]ContentType); !ok || len(parts) != 2 {
+		t.Errorf("expected multimodal content to round-trip, got %T", history[0].Content)
+	}
+
+	if err := store.Delete(ctx, conv.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := store.Load(ctx, conv.ID); !errors.Is(err, ErrConversationNotFound) {
+		t.Errorf("Load() after Delete error = %v, want ErrConversationNotFound", err)
+	}
+}
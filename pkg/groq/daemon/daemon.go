@@ -0,0 +1,289 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/genc-murat/groq-client/pkg/groq"
+)
+
+var (
+	ErrModelNotAllowed = errors.New("model not allowed")
+	ErrClosed          = errors.New("daemon closed")
+)
+
+// defaultRetireDelay is how long a replaced client accepts new requests by default.
+const defaultRetireDelay = 10 * time.Second
+
+type Snapshot struct {
+	Settings *Settings
+	Client   *groq.Client
+	Version  int       // Incremented on every successful reload
+	LoadedAt time.Time // When the settings were applied
+}
+
+type Daemon struct {
+	// OnReload is called after every reload attempt with the new snapshot, or with the
+	// error that kept the previous snapshot in place.
+	OnReload func(snap *Snapshot, err error)
+	// RetireDelay is how long the client of a replaced snapshot still accepts requests
+	// before it is closed, for callers that took the snapshot just before a reload; 0
+	// means 10 seconds. Requests in flight when it is closed finish normally.
+	RetireDelay time.Duration
+
+	path    string
+	apiKey  string
+	opts    []groq.Option
+	current atomic.Pointer[Snapshot]
+	cache   *groq.LRUCache
+	modTime time.Time
+	retired map[*groq.Client]*time.Timer // Replaced clients waiting to be closed
+	closed  bool
+	mu      sync.Mutex
+}
+
+// New creates a long-lived daemon whose client is built from the settings file at path.
+// The settings can be reloaded while the daemon serves requests: each reload builds a new
+// client and swaps it in atomically, so requests already in flight finish on the snapshot
+// they started with, and the replaced client is closed after RetireDelay. The response
+// cache is kept across reloads unless its size or TTL changes. Call Close when done.
+//
+// Example usage:
+//
+//	d, err := daemon.New(apiKey, "groq.json")
+//	defer d.Close()
+//	go d.Watch(ctx, 5*time.Second, syscall.SIGHUP)
+//	snap := d.Snapshot()
+//	resp, err := snap.Client.CreateChatCompletion(ctx, snap.Prepare(req, "support"))
+//
+// Parameters:
+//   - apiKey: The API key for every client the daemon builds.
+//   - path: The JSON settings file.
+//   - opts: Client options applied before the settings.
+//
+// Returns:
+//   - *Daemon: The daemon, serving the initial settings.
+//   - error: An error if the settings cannot be loaded.
+func New(apiKey, path string, opts ...groq.Option) (*Daemon, error) {
+	d := &Daemon{path: path, apiKey: apiKey, opts: opts, retired: make(map[*groq.Client]*time.Timer)}
+	if _, err := d.Reload(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Snapshot returns the settings and client currently in effect. Callers should take one
+// snapshot per request and use it throughout, so a reload cannot change a request midway.
+func (d *Daemon) Snapshot() *Snapshot {
+	return d.current.Load()
+}
+
+// Reload reads the settings file and swaps in a client built from it. Invalid settings
+// are rejected and the previous snapshot stays in effect. The client of the previous
+// snapshot is closed after RetireDelay.
+//
+// Returns:
+//   - *Snapshot: The snapshot in effect after the reload.
+//   - error: An error if the settings could not be loaded, or ErrClosed after Close.
+func (d *Daemon) Reload() (*Snapshot, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return d.current.Load(), ErrClosed
+	}
+
+	var modTime time.Time
+	if info, err := os.Stat(d.path); err == nil {
+		modTime = info.ModTime()
+	}
+
+	settings, err := LoadSettings(d.path)
+	if err != nil {
+		d.notify(d.current.Load(), err)
+		return d.current.Load(), err
+	}
+
+	previous := d.current.Load()
+	snap := &Snapshot{
+		Settings: settings,
+		Client:   d.buildClient(settings, previous),
+		Version:  1,
+		LoadedAt: time.Now(),
+	}
+	if previous != nil {
+		snap.Version = previous.Version + 1
+	}
+
+	d.modTime = modTime
+	d.current.Store(snap)
+	if previous != nil {
+		d.retire(previous.Client)
+	}
+	d.notify(snap, nil)
+	return snap, nil
+}
+
+// Close closes the current client, the replaced clients not closed yet and the response
+// cache, waiting for requests in flight. Reloads fail with ErrClosed afterwards. Close is
+// idempotent.
+//
+// Returns:
+//   - error: The error of closing a cache given in the options, if any.
+func (d *Daemon) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return nil
+	}
+	d.closed = true
+	for client, timer := range d.retired {
+		timer.Stop()
+		_ = client.Close()
+	}
+	d.retired = nil
+
+	snap := d.current.Load()
+	_ = snap.Client.Close()
+	if closer, ok := snap.Client.GetCache().(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// retire closes client after RetireDelay. The caller must hold d.mu.
+func (d *Daemon) retire(client *groq.Client) {
+	delay := d.RetireDelay
+	if delay <= 0 {
+		delay = defaultRetireDelay
+	}
+	d.retired[client] = time.AfterFunc(delay, func() {
+		d.mu.Lock()
+		_, pending := d.retired[client]
+		delete(d.retired, client)
+		d.mu.Unlock()
+		if pending {
+			_ = client.Close()
+		}
+	})
+}
+
+// Watch reloads the settings whenever one of the given signals arrives (typically
+// syscall.SIGHUP) and, if interval is positive, whenever the settings file's modification
+// time changes. It blocks until ctx is canceled.
+//
+// Parameters:
+//   - ctx: Stops watching when canceled.
+//   - interval: How often the file is polled; 0 disables polling.
+//   - signals: Signals that trigger a reload.
+func (d *Daemon) Watch(ctx context.Context, interval time.Duration, signals ...os.Signal) {
+	sigCh := make(chan os.Signal, 1)
+	if len(signals) > 0 {
+		signal.Notify(sigCh, signals...)
+		defer signal.Stop(sigCh)
+	}
+
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sigCh:
+			d.Reload()
+		case <-tick:
+			if d.changed() {
+				d.Reload()
+			}
+		}
+	}
+}
+
+// WatchSIGHUP is shorthand for Watch(ctx, interval, syscall.SIGHUP).
+func (d *Daemon) WatchSIGHUP(ctx context.Context, interval time.Duration) {
+	d.Watch(ctx, interval, syscall.SIGHUP)
+}
+
+// changed reports whether the settings file was modified since the last reload.
+func (d *Daemon) changed() bool {
+	info, err := os.Stat(d.path)
+	if err != nil {
+		return false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return !info.ModTime().Equal(d.modTime)
+}
+
+// buildClient creates a client for settings, reusing the cache of the previous snapshot
+// when the cache settings did not change. The cache is shared by the clients of all
+// snapshots, so closing a replaced client leaves it open.
+func (d *Daemon) buildClient(settings *Settings, previous *Snapshot) *groq.Client {
+	opts := append([]groq.Option{}, d.opts...)
+	opts = append(opts, groq.WithSharedCache())
+	if settings.RateLimit > 0 {
+		opts = append(opts, groq.WithRateLimit(settings.RateLimit))
+	}
+
+	if !settings.Cache.Enabled {
+		d.cache = nil
+	} else {
+		if d.cache == nil || previous == nil || previous.Settings.Cache != settings.Cache {
+			d.cache = groq.NewLRUCache(settings.Cache.MaxEntries, time.Duration(settings.Cache.TTL))
+		}
+		opts = append(opts, groq.WithCache(d.cache))
+	}
+
+	return groq.NewClient(d.apiKey, opts...)
+}
+
+// notify calls OnReload if it is set.
+func (d *Daemon) notify(snap *Snapshot, err error) {
+	if d.OnReload != nil {
+		d.OnReload(snap, err)
+	}
+}
+
+// Prepare returns a copy of req adjusted to the snapshot's settings: the default model is
+// filled in when the request names none, and the named system prompt, if any, is
+// prepended. Use Allowed to reject requests for models the settings do not permit.
+//
+// Parameters:
+//   - req: The incoming request; it is not modified.
+//   - prompt: Name of a prompt from the settings, or "" for none.
+//
+// Returns:
+//   - *groq.ChatCompletionRequest: The adjusted request.
+func (s *Snapshot) Prepare(req *groq.ChatCompletionRequest, prompt string) *groq.ChatCompletionRequest {
+	prepared := *req
+	if prepared.Model == "" {
+		prepared.Model = s.Settings.DefaultModel
+	}
+	if text, ok := s.Settings.Prompts[prompt]; ok && text != "" {
+		prepared.Messages = append([]groq.ChatMessage{{Role: "system", Content: text}}, req.Messages...)
+	}
+	return &prepared
+}
+
+// Allowed returns an error matching ErrModelNotAllowed if the settings do not permit model.
+func (s *Snapshot) Allowed(model groq.ModelType) error {
+	if model == "" || s.Settings.allows(model) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrModelNotAllowed, model)
+}
@@ -0,0 +1,179 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/genc-murat/groq-client/pkg/groq"
+)
+
+func writeSettings(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDaemonReloadKeepsInFlightRequests(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req groq.ChatCompletionRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		<-release
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":    "resp",
+			"model": req.Model,
+			"choices": []map[string]interface{}{
+				{"message": map[string]string{"role": "assistant", "content": req.Messages[0].GetCacheKey()}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "groq.json")
+	writeSettings(t, path, `{"default_model":"llama-3.1-8b-instant","prompts":{"bot":"v1"},"cache":{"enabled":true,"ttl":"1m"}}`)
+
+	d, err := New("key", path, groq.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := d.Snapshot()
+
+	done := make(chan *groq.ChatCompletionResponse)
+	go func() {
+		req := old.Prepare(&groq.ChatCompletionRequest{Messages: []groq.ChatMessage{{Role: "user", Content: "hi"}}}, "bot")
+		resp, err := old.Client.CreateChatCompletion(context.Background(), req)
+		if err != nil {
+			t.Error(err)
+		}
+		done <- resp
+	}()
+
+	writeSettings(t, path, `{"default_model":"llama-3.3-70b-versatile","prompts":{"bot":"v2"},"cache":{"enabled":true,"ttl":"1m"}}`)
+	snap, err := d.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snap.Version != 2 || d.Snapshot() != snap {
+		t.Fatalf("reload did not swap the snapshot: %+v", snap)
+	}
+
+	close(release)
+	resp := <-done
+	if resp.Model != "llama-3.1-8b-instant" || resp.Choices[0].Message.Content != "v1" {
+		t.Errorf("in-flight request used new settings: %+v", resp)
+	}
+
+	if d.cache == nil {
+		t.Fatal("cache not configured")
+	}
+	if d.cache.GetStats().ItemCount != 1 {
+		t.Error("cache was replaced although its settings did not change")
+	}
+}
+
+func TestDaemonRejectsInvalidSettings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "groq.json")
+	writeSettings(t, path, `{"default_model":"llama-3.1-8b-instant","models":["llama-3.1-8b-instant"]}`)
+
+	var reloadErr error
+	d, err := New("key", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.OnReload = func(_ *Snapshot, err error) { reloadErr = err }
+
+	writeSettings(t, path, `{"default_model":"llama-3.3-70b-versatile","models":["llama-3.1-8b-instant"],"rate_limit":-1}`)
+	snap, err := d.Reload()
+	if !errors.Is(err, groq.ErrInvalidConfig) || reloadErr != err {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	var cfgErr *groq.ConfigError
+	if !errors.As(err, &cfgErr) || len(cfgErr.Problems) != 2 {
+		t.Errorf("expected two problems, got %v", err)
+	}
+	if snap.Version != 1 || snap.Settings.DefaultModel != "llama-3.1-8b-instant" {
+		t.Errorf("invalid settings replaced the snapshot: %+v", snap.Settings)
+	}
+
+	if err := snap.Allowed(groq.ModelLlama33_70bVersatile); !errors.Is(err, ErrModelNotAllowed) {
+		t.Errorf("Allowed() = %v", err)
+	}
+}
+
+func TestDaemonWatchPollsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "groq.json")
+	writeSettings(t, path, `{"default_model":"llama-3.1-8b-instant","rate_limit":30}`)
+
+	d, err := New("key", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reloaded := make(chan *Snapshot, 1)
+	d.OnReload = func(snap *Snapshot, err error) {
+		if err == nil {
+			reloaded <- snap
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go d.Watch(ctx, 10*time.Millisecond)
+
+	writeSettings(t, path, `{"default_model":"llama-3.1-8b-instant","rate_limit":120}`)
+	future := time.Now().Add(time.Second)
+	_ = os.Chtimes(path, future, future)
+
+	select {
+	case snap := <-reloaded:
+		if snap.Settings.RateLimit != 120 {
+			t.Errorf("RateLimit = %d", snap.Settings.RateLimit)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("file change was not picked up")
+	}
+}
+
+func TestDaemonReloadClosesReplacedClients(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "groq.json")
+	writeSettings(t, path, `{"default_model":"llama-3.1-8b-instant","rate_limit":60,"cache":{"enabled":true,"ttl":"1m"}}`)
+
+	d, err := New("key", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.RetireDelay = time.Millisecond
+	first := d.Snapshot().Client
+	baseline := runtime.NumGoroutine()
+
+	for i := 0; i < 50; i++ {
+		if _, err := d.Reload(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > baseline+2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > baseline+2 {
+		t.Errorf("%d goroutines after 50 reloads, %d before; replaced clients leak", n, baseline)
+	}
+	if _, err := first.CreateChatCompletion(context.Background(), groq.NewRequest(groq.ModelLlama31_8bInstant).User("hi").Build()); !errors.Is(err, groq.ErrClientClosed) {
+		t.Errorf("replaced client still open: %v", err)
+	}
+
+	if err := d.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, err := d.Reload(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Reload() after Close = %v", err)
+	}
+}
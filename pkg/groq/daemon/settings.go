@@ -0,0 +1,114 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/genc-murat/groq-client/pkg/groq"
+)
+
+type Settings struct {
+	DefaultModel groq.ModelType    `json:"default_model"`
+	Models       []groq.ModelType  `json:"models,omitempty"`  // Models clients may request; empty allows all
+	RateLimit    int               `json:"rate_limit"`        // Requests per minute, 0 keeps the client default
+	Prompts      map[string]string `json:"prompts,omitempty"` // Named system prompts
+	Cache        CacheSettings     `json:"cache"`
+}
+
+type CacheSettings struct {
+	Enabled    bool     `json:"enabled"`
+	MaxEntries int      `json:"max_entries"`
+	TTL        Duration `json:"ttl"`
+}
+
+// Duration is a time.Duration that is written as a string such as "10m" in settings files.
+type Duration time.Duration
+
+// UnmarshalJSON accepts a duration string ("90s", "1h") or a number of nanoseconds.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		v, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q: %w", s, err)
+		}
+		*d = Duration(v)
+		return nil
+	}
+
+	var n int64
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("invalid duration %s", data)
+	}
+	*d = Duration(n)
+	return nil
+}
+
+// MarshalJSON writes the duration as a string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// LoadSettings reads daemon settings from a JSON file and validates them.
+//
+// Parameters:
+//   - path: The settings file.
+//
+// Returns:
+//   - *Settings: The parsed settings.
+//   - error: An error if the file cannot be read or the settings are invalid.
+func LoadSettings(path string) (*Settings, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read settings: %w", err)
+	}
+
+	var s Settings
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse settings %s: %w", path, err)
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Validate reports all problems of the settings at once in a *groq.ConfigError.
+//
+// Returns:
+//   - error: A *groq.ConfigError matching groq.ErrInvalidConfig, or nil if the settings are valid.
+func (s *Settings) Validate() error {
+	var problems []string
+
+	if s.DefaultModel == "" {
+		problems = append(problems, "default_model is empty; set the model used when a request names none")
+	} else if len(s.Models) > 0 && !s.allows(s.DefaultModel) {
+		problems = append(problems, fmt.Sprintf("default_model %q is not in models; add it or pick an allowed model", s.DefaultModel))
+	}
+	if s.RateLimit < 0 {
+		problems = append(problems, fmt.Sprintf("rate_limit is %d; it must not be negative", s.RateLimit))
+	}
+	if s.Cache.MaxEntries < 0 {
+		problems = append(problems, fmt.Sprintf("cache.max_entries is %d; use 0 for no limit", s.Cache.MaxEntries))
+	}
+	if s.Cache.TTL < 0 {
+		problems = append(problems, fmt.Sprintf("cache.ttl is %v; use 0 for no expiry", time.Duration(s.Cache.TTL)))
+	}
+
+	return groq.NewConfigError(problems)
+}
+
+// allows reports whether requests may use model.
+func (s *Settings) allows(model groq.ModelType) bool {
+	if len(s.Models) == 0 {
+		return true
+	}
+	for _, m := range s.Models {
+		if m == model {
+			return true
+		}
+	}
+	return false
+}
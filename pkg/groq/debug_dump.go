@@ -0,0 +1,49 @@
+package groq
+
+import (
+	"io"
+
+	"github.com/genc-murat/groq-client/internal/util"
+)
+
+// WithDebugDump writes a dump of every HTTP attempt to w: method, URL, headers and body
+// of the request and the response, with the attempt's start time and latency. The API key
+// and other credentials in headers, URLs and JSON bodies are masked; audio and other
+// binary bodies are summarised by size and long bodies are truncated. Dumps slow the
+// client down and may still contain prompts and answers, so use them for debugging only.
+//
+// Example usage:
+//
+//	client := NewClient(apiKey, WithDebugDump(os.Stderr))
+//
+// Parameters:
+//   - w: The destination of the dumps; nil disables dumping.
+//
+// Returns:
+//   - Option: A function that enables the dumps.
+func WithDebugDump(w io.Writer) Option {
+	return func(c *Client) {
+		c.dumper = nil
+		if w != nil {
+			c.dumper = util.NewDumper(w)
+		}
+	}
+}
+
+// WithDebugDumpDir is like WithDebugDump but writes every attempt to its own file in dir,
+// named after its start time. The directory is created if needed; files are readable by
+// the owner only.
+//
+// Parameters:
+//   - dir: The directory of the dumps; empty disables dumping.
+//
+// Returns:
+//   - Option: A function that enables the dumps.
+func WithDebugDumpDir(dir string) Option {
+	return func(c *Client) {
+		c.dumper = nil
+		if dir != "" {
+			c.dumper = util.NewDirDumper(dir)
+		}
+	}
+}
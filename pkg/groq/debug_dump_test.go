@@ -0,0 +1,30 @@
+package groq
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestWithDebugDump(t *testing.T) {
+	srv := newTestServer(t, func(req *ChatCompletionRequest) string { return "pong" })
+
+	var buf bytes.Buffer
+	client := NewClient("gsk_supersecret", WithBaseURL(srv.URL), WithDebugDump(&buf))
+	defer client.Close()
+
+	if _, err := client.CreateChatCompletion(context.Background(), NewRequest(ModelLlama31_8bInstant).User("ping").Build()); err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+
+	dump := buf.String()
+	if strings.Contains(dump, "supersecret") {
+		t.Errorf("dump leaks the API key:\n%s", dump)
+	}
+	for _, want := range []string{"POST " + srv.URL + "/chat/completions", "Authorization: Bearer [REDACTED]", `"ping"`, "--- 200", "pong"} {
+		if !strings.Contains(dump, want) {
+			t.Errorf("dump lacks %q:\n%s", want, dump)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package groq
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Embedder turns texts into embedding vectors for relevance ranking. Implementations
+// must return one vector per text, all of the same dimension.
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// EmbedderFunc adapts an ordinary function to the Embedder interface.
+type EmbedderFunc func(ctx context.Context, texts []string) ([][]float32, error)
+
+// Embed calls f(ctx, texts).
+func (f EmbedderFunc) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return f(ctx, texts)
+}
+
+// EmbedConversation embeds the turns of a conversation, oldest first, into one unit
+// vector: the sum of the unit vectors of the turns, the last weighted 1 and every earlier
+// one decay times the turn after it. A decay of 0 embeds the last turn only, 1 weights all
+// turns equally. Semantic caches use it for the turns of a ConversationCacheKey.
+//
+// Parameters:
+//   - ctx: The context of the embedding call.
+//   - embedder: Embeds all turns in one call.
+//   - turns: The texts of the turns, see CacheKeyParts.Turns.
+//   - decay: The weight of a turn relative to the next one, in [0, 1].
+//
+// Returns:
+//   - []float32: The unit vector of the conversation.
+//   - error: An error of the embedder, or if it returns no vector for a turn.
+func EmbedConversation(ctx context.Context, embedder Embedder, turns []string, decay float32) ([]float32, error) {
+	if len(turns) == 0 {
+		turns = []string{""}
+	}
+	if decay == 0 {
+		turns = turns[len(turns)-1:]
+	}
+	vectors, err := embedder.Embed(ctx, turns)
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) != len(turns) {
+		return nil, fmt.Errorf("embedder returned %d vectors for %d texts", len(vectors), len(turns))
+	}
+
+	combined := make([]float32, len(vectors[len(vectors)-1]))
+	weight := 1.0
+	for i := len(vectors) - 1; i >= 0; i-- {
+		if len(vectors[i]) != len(combined) {
+			return nil, fmt.Errorf("embedder returned vectors of %d and %d dimensions", len(vectors[i]), len(combined))
+		}
+		addScaled(combined, vectors[i], weight/vectorNorm(vectors[i]))
+		weight *= float64(decay)
+	}
+	if norm := vectorNorm(combined); norm > 0 {
+		for i := range combined {
+			combined[i] = float32(float64(combined[i]) / norm)
+		}
+	}
+	return combined, nil
+}
+
+// vectorNorm returns the Euclidean length of v, or 1 if it is zero.
+func vectorNorm(v []float32) float64 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	if sum == 0 {
+		return 1
+	}
+	return math.Sqrt(sum)
+}
+
+// addScaled adds scale times v to dst.
+func addScaled(dst, v []float32, scale float64) {
+	for i, x := range v {
+		dst[i] += float32(float64(x) * scale)
+	}
+}
+
+type HashingEmbedder struct {
+	Dimension int
+}
+
+// NewHashingEmbedder creates a local Embedder that hashes the lowercased words of a text
+// into a fixed number of buckets. It needs no model or network access and captures
+// lexical overlap only, which is enough to rank snippets against a question.
+//
+// Parameters:
+//   - dimension: Number of buckets; 0 means 256.
+//
+// Returns:
+//   - *HashingEmbedder: The embedder.
+func NewHashingEmbedder(dimension int) *HashingEmbedder {
+	if dimension <= 0 {
+		dimension = 256
+	}
+	return &HashingEmbedder{Dimension: dimension}
+}
+
+// Embed implements Embedder.
+func (e *HashingEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vector := make([]float32, e.Dimension)
+		words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		for _, word := range words {
+			h := fnv.New32a()
+			h.Write([]byte(word))
+			sum := h.Sum32()
+			if sum&(1<<31) != 0 {
+				vector[sum%uint32(e.Dimension)]--
+			} else {
+				vector[sum%uint32(e.Dimension)]++
+			}
+		}
+		vectors[i] = vector
+	}
+	return vectors, nil
+}
+
+// cosineSimilarity returns the cosine of the angle between a and b, or 0 if either is zero.
+func cosineSimilarity(a, b []float32) float64 {
+	var dot, na, nb float64
+	for i := range a {
+		if i >= len(b) {
+			break
+		}
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
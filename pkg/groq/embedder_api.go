@@ -0,0 +1,136 @@
+package groq
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/genc-murat/groq-client/internal/util"
+	"github.com/genc-murat/groq-client/pkg/groq/wire"
+)
+
+// defaultEmbeddingBatchSize is the number of texts an APIEmbedder sends per request.
+const defaultEmbeddingBatchSize = 96
+
+type APIEmbedder struct {
+	Endpoint   string       // URL of the /embeddings endpoint
+	APIKey     string       // Sent as a bearer token; empty sends none, e.g. for local servers
+	Model      string       // Embedding model, e.g. "text-embedding-3-small" or "nomic-embed-text"
+	Dimensions int          // Requested vector size for models that support it; 0 uses the model's
+	BatchSize  int          // Texts per request; 0 means 96
+	HTTPClient *http.Client // nil uses a client with a 30 second timeout
+}
+
+// NewAPIEmbedder creates an Embedder backed by an OpenAI-compatible embeddings API, such
+// as OpenAI, a hosted gateway or a local Ollama or llama.cpp server.
+//
+// Example usage:
+//
+//	embedder := groq.NewAPIEmbedder("https://api.openai.com/v1/embeddings", os.Getenv("OPENAI_API_KEY"), "text-embedding-3-small")
+//	local := groq.NewAPIEmbedder("http://localhost:11434/v1/embeddings", "", "nomic-embed-text")
+//
+// Parameters:
+//   - endpoint: The URL of the /embeddings endpoint.
+//   - apiKey: The API key; empty for servers that need none.
+//   - model: The embedding model.
+//
+// Returns:
+//   - *APIEmbedder: The embedder.
+func NewAPIEmbedder(endpoint, apiKey, model string) *APIEmbedder {
+	return &APIEmbedder{
+		Endpoint: endpoint,
+		APIKey:   apiKey,
+		Model:    model,
+	}
+}
+
+// Embed implements Embedder. Texts are sent in batches of BatchSize; errors of the API
+// are returned as *APIError.
+func (e *APIEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	batchSize := e.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultEmbeddingBatchSize
+	}
+
+	vectors := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += batchSize {
+		end := min(start+batchSize, len(texts))
+		batch, err := e.embedBatch(ctx, texts[start:end])
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, batch...)
+	}
+	return vectors, nil
+}
+
+// embedBatch embeds texts with a single request.
+func (e *APIEmbedder) embedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	body, err := json.Marshal(wire.EmbeddingRequest{
+		Model:          e.Model,
+		Input:          texts,
+		EncodingFormat: "float",
+		Dimensions:     e.Dimensions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrJSONEncoding, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", DefaultUserAgent)
+	if e.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.APIKey)
+	}
+
+	httpClient := e.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrHTTPRequest, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrHTTPRequest, err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		statusErr := &util.StatusError{
+			StatusCode: resp.StatusCode,
+			Body:       data,
+			RequestID:  resp.Header.Get("x-request-id"),
+		}
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			statusErr.RetryAfter = time.Duration(seconds) * time.Second
+		}
+		return nil, newAPIError(statusErr)
+	}
+
+	var decoded wire.EmbeddingResponse
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrJSONDecoding, err)
+	}
+	if len(decoded.Data) != len(texts) {
+		return nil, fmt.Errorf("%w: got %d embeddings for %d texts", ErrJSONDecoding, len(decoded.Data), len(texts))
+	}
+
+	vectors := make([][]float32, len(texts))
+	for _, item := range decoded.Data {
+		if item.Index < 0 || item.Index >= len(texts) || vectors[item.Index] != nil {
+			return nil, fmt.Errorf("%w: unexpected embedding index %d", ErrJSONDecoding, item.Index)
+		}
+		vectors[item.Index] = item.Embedding
+	}
+	return vectors, nil
+}
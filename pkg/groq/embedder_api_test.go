@@ -0,0 +1,61 @@
+package groq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/genc-murat/groq-client/pkg/groq/wire"
+)
+
+func TestAPIEmbedder(t *testing.T) {
+	var requests []wire.EmbeddingRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q", got)
+		}
+		var req wire.EmbeddingRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		requests = append(requests, req)
+
+		// Answer in reverse order; the embedder must sort by index.
+		var resp wire.EmbeddingResponse
+		for i := len(req.Input) - 1; i >= 0; i-- {
+			resp.Data = append(resp.Data, wire.Embedding{Index: i, Embedding: []float32{float32(len(req.Input[i])), 1}})
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	embedder := NewAPIEmbedder(srv.URL, "secret", "embed-model")
+	embedder.BatchSize = 2
+	vectors, err := embedder.Embed(context.Background(), []string{"a", "bb", "ccc"})
+	if err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	if len(vectors) != 3 || vectors[0][0] != 1 || vectors[1][0] != 2 || vectors[2][0] != 3 {
+		t.Errorf("Embed() = %v, want one vector per text in order", vectors)
+	}
+	if len(requests) != 2 || requests[0].Model != "embed-model" || len(requests[1].Input) != 1 {
+		t.Errorf("requests = %+v, want two batches", requests)
+	}
+}
+
+func TestAPIEmbedderError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"error":{"message":"slow down","type":"requests","code":"rate_limit_exceeded"}}`))
+	}))
+	defer srv.Close()
+
+	_, err := NewAPIEmbedder(srv.URL, "", "embed-model").Embed(context.Background(), []string{"a"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !errors.Is(err, ErrRateLimited) || apiErr.Message != "slow down" {
+		t.Errorf("Embed() error = %v, want a rate limited APIError", err)
+	}
+}
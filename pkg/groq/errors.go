@@ -1,26 +1,184 @@
 package groq
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/genc-murat/groq-client/internal/util"
+	"github.com/genc-murat/groq-client/pkg/groq/wire"
 )
 
+// maxErrorBodyLength limits how much of a non-JSON error body is kept in APIError.Message.
+const maxErrorBodyLength = 500
+
 var (
 	ErrInvalidRequest = errors.New("invalid request")
 	ErrJSONEncoding   = errors.New("json encoding error")
 	ErrJSONDecoding   = errors.New("json decoding error")
 	ErrHTTPRequest    = errors.New("http request failed")
+
+	ErrConversationNotFound = errors.New("conversation not found")
+	ErrInvalidBatch         = errors.New("invalid batch")
+	ErrBatchNotCompleted    = errors.New("batch did not complete")
+	ErrInvalidConfig        = errors.New("invalid config")
+)
+
+// Kinds of API errors. An *APIError matches the kind it was classified as with errors.Is:
+//
+//	if errors.Is(err, groq.ErrRateLimited) {
+//	    wait, _ := groq.RetryAfter(err)
+//	    ...
+//	}
+var (
+	ErrAuthentication        = errors.New("authentication failed")
+	ErrRateLimited           = errors.New("rate limited")
+	ErrQuotaExceeded         = errors.New("quota exceeded")
+	ErrContextLengthExceeded = errors.New("context length exceeded")
+	ErrModelNotFound         = errors.New("model not found")
+	ErrContentPolicy         = errors.New("content policy violation")
 )
 
+type ConfigError struct {
+	Problems []string // One actionable message per invalid setting
+}
+
+// Error returns all problems of the configuration in a single message.
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrInvalidConfig, strings.Join(e.Problems, "; "))
+}
+
+// Unwrap allows errors.Is(err, ErrInvalidConfig) to match any ConfigError.
+func (e *ConfigError) Unwrap() error {
+	return ErrInvalidConfig
+}
+
+// NewConfigError returns a *ConfigError for problems, or nil if there are none.
+// It lets configuration types of subpackages report problems the same way.
+func NewConfigError(problems []string) error {
+	if len(problems) == 0 {
+		return nil
+	}
+	return &ConfigError{Problems: problems}
+}
+
 type APIError struct {
 	StatusCode int    `json:"status_code"`
 	Message    string `json:"message"`
 	Type       string `json:"type"`
+	Code       string `json:"code,omitempty"`       // Machine-readable error code, e.g. "model_not_found"
+	Param      string `json:"param,omitempty"`      // The request field the error refers to, if any
+	RequestID  string `json:"request_id,omitempty"` // ID of the failed request, for support tickets
+
+	ClientRequestID string `json:"client_request_id,omitempty"` // X-Request-ID the client sent, see WithRequestIDGenerator
+
+	RetryAfter time.Duration `json:"retry_after,omitempty"` // Wait requested by the API, 0 if none
+	Err        error         `json:"-"`                     // The underlying HTTP error
 }
 
 // Error returns a formatted string representing the APIError.
-// The string includes the error message, status code, and type of the error.
+// The string includes the error message, status code, and type of the error,
+// followed by the code and the server and client request IDs when known.
 func (e *APIError) Error() string {
-	return fmt.Sprintf("groq api error: %s (status: %d, type: %s)",
-		e.Message, e.StatusCode, e.Type)
+	msg := fmt.Sprintf("groq api error: %s (status: %d, type: %s", e.Message, e.StatusCode, e.Type)
+	if e.Code != "" {
+		msg += ", code: " + e.Code
+	}
+	if e.RequestID != "" {
+		msg += ", request id: " + e.RequestID
+	}
+	if e.ClientRequestID != "" {
+		msg += ", client request id: " + e.ClientRequestID
+	}
+	return msg + ")"
+}
+
+// Unwrap returns the underlying HTTP error, so errors.As can still reach it.
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the kind of the error, e.g. ErrRateLimited.
+func (e *APIError) Is(target error) bool {
+	kind := e.Kind()
+	return kind != nil && target == kind
+}
+
+// Kind classifies the error by its status code, code and message.
+//
+// Returns:
+//   - error: One of ErrAuthentication, ErrRateLimited, ErrQuotaExceeded,
+//     ErrContextLengthExceeded, ErrModelNotFound and ErrContentPolicy, or nil if the
+//     error fits none of them.
+func (e *APIError) Kind() error {
+	message := strings.ToLower(e.Message)
+
+	switch {
+	case e.StatusCode == http.StatusUnauthorized, e.StatusCode == http.StatusForbidden, e.Code == "invalid_api_key":
+		return ErrAuthentication
+	case e.StatusCode == http.StatusPaymentRequired, e.Code == "insufficient_quota",
+		e.StatusCode == http.StatusTooManyRequests && (strings.Contains(message, "quota") || strings.Contains(message, "per day")):
+		return ErrQuotaExceeded
+	case e.StatusCode == http.StatusTooManyRequests, e.Code == "rate_limit_exceeded":
+		return ErrRateLimited
+	case e.Code == "context_length_exceeded", strings.Contains(message, "context length"),
+		strings.Contains(message, "reduce the length"):
+		return ErrContextLengthExceeded
+	case e.Code == "model_not_found", e.StatusCode == http.StatusNotFound && e.Param == "model":
+		return ErrModelNotFound
+	case e.Code == "content_policy_violation", e.Code == "content_filter", strings.Contains(message, "content policy"):
+		return ErrContentPolicy
+	}
+	return nil
+}
+
+// RetryAfter returns how long the API asked the client to wait before retrying, taken
+// from the Retry-After header of a rate limited or overloaded response.
+//
+// Parameters:
+//   - err: An error returned by the client.
+//
+// Returns:
+//   - time.Duration: The requested wait.
+//   - bool: False if err carries no Retry-After value.
+func RetryAfter(err error) (time.Duration, bool) {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.RetryAfter <= 0 {
+		return 0, false
+	}
+	return apiErr.RetryAfter, true
+}
+
+// newAPIError decodes the error body of a failed response into an *APIError. Bodies that
+// are not the API's JSON error format are kept as the message.
+func newAPIError(statusErr *util.StatusError) error {
+	apiErr := &APIError{
+		StatusCode: statusErr.StatusCode,
+		RequestID:  statusErr.RequestID,
+		RetryAfter: statusErr.RetryAfter,
+		Err:        statusErr,
+
+		ClientRequestID: statusErr.ClientRequestID,
+	}
+
+	var body wire.ErrorResponse
+	if err := json.Unmarshal(statusErr.Body, &body); err == nil && body.Error.Message != "" {
+		apiErr.Message = body.Error.Message
+		apiErr.Type = body.Error.Type
+		apiErr.Code = body.Error.Code
+		apiErr.Param = body.Error.Param
+		return apiErr
+	}
+
+	apiErr.Message = strings.TrimSpace(string(statusErr.Body))
+	if runes := []rune(apiErr.Message); len(runes) > maxErrorBodyLength {
+		apiErr.Message = string(runes[:maxErrorBodyLength]) + "…"
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(statusErr.StatusCode)
+	}
+	return apiErr
 }
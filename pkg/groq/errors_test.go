@@ -0,0 +1,111 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/genc-murat/groq-client/internal/util"
+)
+
+func TestAPIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-request-id", "req_123")
+		if r.URL.Path == "/models" {
+			http.Error(w, "upstream gateway exploded", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":{"message":"The model does not exist","type":"invalid_request_error","code":"model_not_found","param":"model"}}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key", WithBaseURL(srv.URL))
+	ctx := ContextWithRequestID(context.Background(), "client_1")
+	_, err := client.CreateChatCompletion(ctx, NewRequest(ModelLlama31_8bInstant).User("hi").Build())
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected an *APIError, got %T: %v", err, err)
+	}
+	want := APIError{StatusCode: 404, Message: "The model does not exist", Type: "invalid_request_error", Code: "model_not_found", Param: "model", RequestID: "req_123", ClientRequestID: "client_1"}
+	apiErr.Err = nil
+	if *apiErr != want {
+		t.Errorf("APIError = %+v, want %+v", *apiErr, want)
+	}
+	if !strings.Contains(err.Error(), "req_123") {
+		t.Errorf("expected the request ID in the message, got %q", err)
+	}
+
+	_, err = client.ListModels(context.Background())
+	if !errors.As(err, &apiErr) || apiErr.Message != "upstream gateway exploded" || apiErr.StatusCode != 400 {
+		t.Errorf("expected a non-JSON body to become the message, got %v", err)
+	}
+}
+
+func TestAPIErrorKinds(t *testing.T) {
+	tests := []struct {
+		status int
+		body   string
+		want   error
+	}{
+		{401, `{"error":{"message":"Invalid API Key","type":"invalid_request_error","code":"invalid_api_key"}}`, ErrAuthentication},
+		{429, `{"error":{"message":"Rate limit reached for model on requests per minute (RPM)","type":"requests","code":"rate_limit_exceeded"}}`, ErrRateLimited},
+		{429, `{"error":{"message":"Rate limit reached for model on tokens per day (TPD)","type":"tokens","code":"rate_limit_exceeded"}}`, ErrQuotaExceeded},
+		{400, `{"error":{"message":"Please reduce the length of the messages or completion.","type":"invalid_request_error","code":"context_length_exceeded"}}`, ErrContextLengthExceeded},
+		{404, `{"error":{"message":"The model does not exist","type":"invalid_request_error","code":"model_not_found"}}`, ErrModelNotFound},
+		{400, `{"error":{"message":"Your request was rejected by our content policy","code":"content_policy_violation"}}`, ErrContentPolicy},
+		{500, `internal error`, nil},
+	}
+
+	kinds := []error{ErrAuthentication, ErrRateLimited, ErrQuotaExceeded, ErrContextLengthExceeded, ErrModelNotFound, ErrContentPolicy}
+	for _, tt := range tests {
+		err := fmt.Errorf("chat completion request failed: %w", newAPIError(&util.StatusError{StatusCode: tt.status, Body: []byte(tt.body)}))
+		for _, kind := range kinds {
+			if got := errors.Is(err, kind); got != (kind == tt.want) {
+				t.Errorf("%d %.40s: errors.Is(%v) = %v", tt.status, tt.body, kind, got)
+			}
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	err := newAPIError(&util.StatusError{StatusCode: 429, RetryAfter: 3 * time.Second})
+	if d, ok := RetryAfter(fmt.Errorf("wrapped: %w", err)); !ok || d != 3*time.Second {
+		t.Errorf("RetryAfter() = %v, %v", d, ok)
+	}
+	if _, ok := RetryAfter(errors.New("other")); ok {
+		t.Error("expected no Retry-After for a plain error")
+	}
+}
+
+func TestWithMaxRetryAfter(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "30")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"error":{"message":"Rate limit reached","type":"requests","code":"rate_limit_exceeded"}}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key", WithBaseURL(srv.URL), WithMaxRetryAfter(time.Second))
+	start := time.Now()
+	_, err := client.ListModels(context.Background())
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited, got %v", err)
+	}
+	if calls != 1 || time.Since(start) > 5*time.Second {
+		t.Errorf("expected no retry, got %d calls in %v", calls, time.Since(start))
+	}
+	if d, ok := RetryAfter(err); !ok || d != 30*time.Second {
+		t.Errorf("RetryAfter() = %v, %v", d, ok)
+	}
+}
@@ -0,0 +1,78 @@
+package groq
+
+import (
+	"strings"
+	"time"
+
+	"github.com/genc-murat/groq-client/internal/util"
+)
+
+// EndpointHealth reports whether one base URL configured with WithBaseURLs is reachable.
+type EndpointHealth = util.EndpointStatus
+
+// WithBaseURLs configures several base URLs for the same API, most preferred first, such as
+// a corporate proxy followed by the public endpoint. Every request goes to the first
+// reachable one. When an endpoint cannot be connected to, the request is retried at once on
+// the next endpoint, and the failed endpoint is skipped for a cooldown before it is tried
+// again (see WithEndpointCooldown). Error responses from a reachable endpoint do not cause
+// a failover; they are retried as usual. Failover uses the retries of WithRetryConfig, so
+// set at least len(baseURLs)-1 retries.
+//
+// Example usage:
+//
+//	client := NewClient(apiKey, WithBaseURLs(
+//	    "https://llm-proxy.internal/openai/v1",
+//	    DefaultBaseURL,
+//	))
+//
+// Parameters:
+//   - baseURLs: The base URLs, most preferred first.
+//
+// Returns:
+//   - Option: A function that sets the base URLs of the client.
+func WithBaseURLs(baseURLs ...string) Option {
+	return func(c *Client) {
+		if len(baseURLs) == 0 {
+			return
+		}
+		c.claim("base URL", "WithBaseURLs")
+		c.baseURL = strings.TrimRight(baseURLs[0], "/")
+		c.baseURLs = baseURLs
+	}
+}
+
+// WithEndpointCooldown sets how long an unreachable endpoint configured with WithBaseURLs
+// is skipped before it is tried again. The default is 30 seconds.
+//
+// Parameters:
+//   - cooldown: The time an endpoint is skipped after a connection failure.
+//
+// Returns:
+//   - Option: A function that sets the cooldown.
+func WithEndpointCooldown(cooldown time.Duration) Option {
+	return func(c *Client) {
+		c.endpointCooldown = cooldown
+	}
+}
+
+// EndpointHealth returns the health of every base URL configured with WithBaseURLs, in
+// order of preference, e.g. for a readiness probe or a status page.
+//
+// Returns:
+//   - []EndpointHealth: The endpoints, or nil if the client has a single base URL.
+func (c *Client) EndpointHealth() []EndpointHealth {
+	if c.endpoints == nil {
+		return nil
+	}
+	return c.endpoints.Status()
+}
+
+// setupEndpoints installs the endpoint pool for WithBaseURLs. It runs after all options,
+// since options may replace the HTTP client.
+func (c *Client) setupEndpoints() {
+	if len(c.baseURLs) < 2 {
+		return
+	}
+	c.endpoints = util.NewEndpointPool(c.baseURLs, c.endpointCooldown)
+	c.httpClient.SetEndpoints(c.endpoints)
+}
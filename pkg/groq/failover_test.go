@@ -0,0 +1,36 @@
+package groq
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithBaseURLs(t *testing.T) {
+	dead := httptest.NewServer(nil)
+	deadURL := dead.URL
+	dead.Close()
+
+	srv := newTestServer(t, func(req *ChatCompletionRequest) string { return "pong" })
+
+	client := NewClient("test-key",
+		WithBaseURLs(deadURL, srv.URL),
+		WithRetryConfig(2, time.Minute),
+	)
+	resp, err := client.CreateChatCompletion(context.Background(), NewRequest(ModelLlama31_8bInstant).User("ping").Build())
+	if err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+	if got := resp.Choices[0].Message.Content; got != "pong" {
+		t.Errorf("unexpected reply %v", got)
+	}
+
+	health := client.EndpointHealth()
+	if len(health) != 2 || health[0].Healthy || !health[1].Healthy {
+		t.Errorf("unexpected endpoint health: %+v", health)
+	}
+	if NewClient("test-key").EndpointHealth() != nil {
+		t.Error("expected no endpoint health for a single base URL")
+	}
+}
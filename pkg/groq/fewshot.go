@@ -0,0 +1,187 @@
+package groq
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+type FewShotExample struct {
+	Input  string
+	Output string
+}
+
+type FewShotBuilder struct {
+	model       ModelType
+	system      string
+	examples    []FewShotExample
+	tokenBudget int
+	maxTokens   int
+	temperature *float64
+}
+
+// NewFewShotBuilder creates a FewShotBuilder that composes a system instruction,
+// example user/assistant pairs and a final query into a ChatCompletionRequest.
+//
+// Parameters:
+//   - model: The model the request is built for. Its context window is used as
+//     the default token budget.
+//
+// Returns:
+//   - *FewShotBuilder: A pointer to the newly created builder.
+func NewFewShotBuilder(model ModelType) *FewShotBuilder {
+	return &FewShotBuilder{model: model}
+}
+
+// System sets the system instruction sent before the examples.
+func (b *FewShotBuilder) System(instruction string) *FewShotBuilder {
+	b.system = instruction
+	return b
+}
+
+// Example adds an example input/output pair to the pool of candidate examples.
+func (b *FewShotBuilder) Example(input, output string) *FewShotBuilder {
+	b.examples = append(b.examples, FewShotExample{Input: input, Output: output})
+	return b
+}
+
+// Examples adds several example pairs to the pool of candidate examples.
+func (b *FewShotBuilder) Examples(examples ...FewShotExample) *FewShotBuilder {
+	b.examples = append(b.examples, examples...)
+	return b
+}
+
+// TokenBudget limits the estimated prompt size. Examples that do not fit are dropped,
+// least relevant first. Zero means the model context window minus MaxTokens.
+func (b *FewShotBuilder) TokenBudget(tokens int) *FewShotBuilder {
+	b.tokenBudget = tokens
+	return b
+}
+
+// MaxTokens sets max_tokens on the built request and reserves it from the default budget.
+func (b *FewShotBuilder) MaxTokens(tokens int) *FewShotBuilder {
+	b.maxTokens = tokens
+	return b
+}
+
+// Temperature sets the sampling temperature of the built request.
+func (b *FewShotBuilder) Temperature(temperature float64) *FewShotBuilder {
+	b.temperature = &temperature
+	return b
+}
+
+// Build composes the final request for query. Examples are ranked by word overlap with
+// the query and added greedily while the estimated prompt stays within the token budget.
+// Selected examples are placed so that the most relevant one is closest to the query.
+//
+// Parameters:
+//   - query: The final user message.
+//
+// Returns:
+//   - *ChatCompletionRequest: The composed request.
+//   - error: An error if the system instruction and query alone exceed the budget.
+func (b *FewShotBuilder) Build(query string) (*ChatCompletionRequest, error) {
+	budget := b.budget()
+
+	var head []ChatMessage
+	if b.system != "" {
+		head = append(head, ChatMessage{Role: "system", Content: b.system})
+	}
+	tail := ChatMessage{Role: "user", Content: query}
+
+	used := EstimateMessageTokens(append(head, tail))
+	if budget > 0 && used > budget {
+		return nil, fmt.Errorf("system instruction and query need ~%d tokens, exceeding the budget of %d", used, budget)
+	}
+
+	selected := make([]FewShotExample, 0, len(b.examples))
+	for _, ex := range b.rankExamples(query) {
+		cost := 2*messageTokenOverhead + EstimateTokens(ex.Input) + EstimateTokens(ex.Output)
+		if budget > 0 && used+cost > budget {
+			continue
+		}
+		used += cost
+		selected = append(selected, ex)
+	}
+
+	messages := make([]ChatMessage, 0, len(head)+2*len(selected)+1)
+	messages = append(messages, head...)
+	for i := len(selected) - 1; i >= 0; i-- {
+		messages = append(messages,
+			ChatMessage{Role: "user", Content: selected[i].Input},
+			ChatMessage{Role: "assistant", Content: selected[i].Output},
+		)
+	}
+	messages = append(messages, tail)
+
+	return &ChatCompletionRequest{
+		Model:       b.model,
+		Messages:    messages,
+		MaxTokens:   b.maxTokens,
+		Temperature: b.temperature,
+	}, nil
+}
+
+// budget returns the effective prompt token budget, or 0 if unlimited.
+func (b *FewShotBuilder) budget() int {
+	if b.tokenBudget > 0 {
+		return b.tokenBudget
+	}
+	if window := b.model.GetInfo().ContextWindow; window > 0 {
+		return window - b.maxTokens
+	}
+	return 0
+}
+
+// rankExamples returns the examples ordered by descending word overlap with query.
+// Ties keep their insertion order.
+func (b *FewShotBuilder) rankExamples(query string) []FewShotExample {
+	queryWords := wordSet(query)
+
+	type scored struct {
+		example FewShotExample
+		score   float64
+	}
+	ranked := make([]scored, len(b.examples))
+	for i, ex := range b.examples {
+		ranked[i] = scored{example: ex, score: jaccard(queryWords, wordSet(ex.Input))}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].score > ranked[j].score
+	})
+
+	examples := make([]FewShotExample, len(ranked))
+	for i, r := range ranked {
+		examples[i] = r.example
+	}
+	return examples
+}
+
+// wordSet returns the set of lower-cased words in text.
+func wordSet(text string) map[string]struct{} {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	set := make(map[string]struct{}, len(words))
+	for _, w := range words {
+		set[w] = struct{}{}
+	}
+	return set
+}
+
+// jaccard returns the Jaccard similarity of two word sets.
+func jaccard(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	intersection := 0
+	for w := range a {
+		if _, ok := b[w]; ok {
+			intersection++
+		}
+	}
+	return float64(intersection) / float64(len(a)+len(b)-intersection)
+}
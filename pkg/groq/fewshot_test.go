@@ -0,0 +1,47 @@
+package groq
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFewShotBuilderOrdersByRelevance(t *testing.T) {
+	req, err := NewFewShotBuilder(ModelLlama31_8bInstant).
+		System("Classify the sentiment.").
+		Example("the pizza was great", "positive").
+		Example("the weather is cold today", "neutral").
+		Build("was the pizza great?")
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	if len(req.Messages) != 6 {
+		t.Fatalf("expected 6 messages, got %d", len(req.Messages))
+	}
+	if req.Messages[3].Content != "the pizza was great" {
+		t.Errorf("expected the most relevant example right before the query, got %v", req.Messages[3].Content)
+	}
+	if req.Messages[5].Content != "was the pizza great?" {
+		t.Errorf("expected the query last, got %v", req.Messages[5].Content)
+	}
+}
+
+func TestFewShotBuilderRespectsBudget(t *testing.T) {
+	long := strings.Repeat("word ", 200)
+
+	req, err := NewFewShotBuilder(ModelLlama31_8bInstant).
+		Example(long, "a").
+		Example("short", "b").
+		TokenBudget(50).
+		Build("short question")
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if len(req.Messages) != 3 {
+		t.Fatalf("expected only the short example to fit, got %d messages", len(req.Messages))
+	}
+
+	if _, err := NewFewShotBuilder(ModelLlama31_8bInstant).TokenBudget(5).Build(long); err == nil {
+		t.Error("expected an error when the query alone exceeds the budget")
+	}
+}
@@ -0,0 +1,107 @@
+package groq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+)
+
+type FilePurpose string
+
+const (
+	FilePurposeBatch FilePurpose = "batch"
+)
+
+type File struct {
+	ID        string      `json:"id"`
+	Object    string      `json:"object"`
+	Bytes     int64       `json:"bytes"`
+	CreatedAt int64       `json:"created_at"`
+	Filename  string      `json:"filename"`
+	Purpose   FilePurpose `json:"purpose"`
+}
+
+type FileUploadRequest struct {
+	File     io.Reader
+	FileName string
+	Purpose  FilePurpose
+}
+
+// UploadFile uploads a file to the Files API, for example the JSONL input of a batch job.
+// If no purpose is set, it defaults to FilePurposeBatch.
+//
+// Parameters:
+//   - ctx: Context for the request
+//   - req: FileUploadRequest containing:
+//   - File: The file content
+//   - FileName: Name of the file, e.g. "batch.jsonl"
+//   - Purpose: (Optional) The intended use of the file
+//
+// Returns:
+//   - *File: The uploaded file object, including its ID
+//   - error: Any error that occurred during the upload
+func (c *Client) UploadFile(ctx context.Context, req *FileUploadRequest) (*File, error) {
+	if req.File == nil {
+		return nil, fmt.Errorf("%w: file is required", ErrInvalidRequest)
+	}
+	if req.FileName == "" {
+		return nil, fmt.Errorf("%w: file name is required", ErrInvalidRequest)
+	}
+	if req.Purpose == "" {
+		req.Purpose = FilePurposeBatch
+	}
+
+	form := map[string]interface{}{
+		"file":     req.File,
+		"filename": req.FileName,
+		"purpose":  string(req.Purpose),
+	}
+
+	body, err := c.httpClient.DoMultipartFormRaw(
+		ctx,
+		"POST",
+		fmt.Sprintf("%s/files", c.baseURL),
+		form,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("file upload failed: %w", err)
+	}
+
+	var result File
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("file upload failed: %w: %v", ErrJSONDecoding, err)
+	}
+
+	return &result, nil
+}
+
+// GetFileContent downloads the content of a file, such as the output or error file
+// of a completed batch.
+//
+// Parameters:
+//   - ctx: Context for the request
+//   - fileID: The ID of the file
+//
+// Returns:
+//   - []byte: The raw file content
+//   - error: Any error that occurred during the download
+func (c *Client) GetFileContent(ctx context.Context, fileID string) ([]byte, error) {
+	if fileID == "" {
+		return nil, fmt.Errorf("%w: file id is required", ErrInvalidRequest)
+	}
+
+	body, err := c.httpClient.DoRequest(
+		ctx,
+		"GET",
+		fmt.Sprintf("%s/files/%s/content", c.baseURL, url.PathEscape(fileID)),
+		nil,
+		nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("file download failed: %w", err)
+	}
+
+	return body, nil
+}
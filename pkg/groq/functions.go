@@ -1,32 +1,33 @@
 package groq
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 )
 
 type Function struct {
-	Name        string     `json:"name"`
-	Description string     `json:"description"`
-	Parameters  Parameters `json:"parameters"`
+	Name        string     `json:"name" yaml:"name"`
+	Description string     `json:"description" yaml:"description"`
+	Parameters  Parameters `json:"parameters" yaml:"parameters"`
 }
 
 type Parameters struct {
-	Type       string              `json:"type"`
-	Properties map[string]Property `json:"properties"`
-	Required   []string            `json:"required,omitempty"`
+	Type       string              `json:"type" yaml:"type"`
+	Properties map[string]Property `json:"properties" yaml:"properties"`
+	Required   []string            `json:"required,omitempty" yaml:"required,omitempty"`
 }
 
 type Property struct {
-	Type        string   `json:"type"`
-	Description string   `json:"description"`
-	Enum        []string `json:"enum,omitempty"`
+	Type        string   `json:"type" yaml:"type"`
+	Description string   `json:"description" yaml:"description"`
+	Enum        []string `json:"enum,omitempty" yaml:"enum,omitempty"`
 }
 
 type FunctionCall struct {
 	Name      string          `json:"name"`
-	Arguments json.RawMessage `json:"arguments"`
+	Arguments json.RawMessage `json:"arguments"` // JSON object, or a string holding one as the API sends it
 }
 
 // WeatherFunction defines a function to get the current weather for a specified location.
@@ -105,7 +106,16 @@ type FunctionCallChatRequest struct {
 //
 //	An error if the unmarshaling fails, otherwise nil.
 func (f *FunctionCall) ParseArguments(v interface{}) error {
-	return json.Unmarshal(f.Arguments, v)
+	args := bytes.TrimSpace(f.Arguments)
+	if len(args) > 0 && args[0] == '"' {
+		// The API encodes the arguments object as a JSON string.
+		var encoded string
+		if err := json.Unmarshal(args, &encoded); err != nil {
+			return err
+		}
+		args = []byte(encoded)
+	}
+	return json.Unmarshal(args, v)
 }
 
 type WeatherArgs struct {
@@ -121,6 +131,8 @@ type CalendarArgs struct {
 
 // CreateFunctionCall creates a chat completion based on the provided FunctionCallChatRequest.
 // It validates the request and ensures that at least one function is provided before proceeding.
+// The functions are sent as tools; calls the model decides to make are returned in the
+// ToolCalls of the response message.
 //
 // Parameters:
 //   - ctx: The context for the request, used for cancellation and timeouts.
@@ -130,13 +142,12 @@ type CalendarArgs struct {
 //   - *ChatCompletionResponse: The response from the chat completion.
 //   - error: An error if the request is invalid or if the chat completion fails.
 func (c *Client) CreateFunctionCall(ctx context.Context, req *FunctionCallChatRequest) (*ChatCompletionResponse, error) {
-	if err := req.Validate(); err != nil {
-		return nil, fmt.Errorf("invalid request: %w", err)
+	if req.ChatCompletionRequest == nil {
+		return nil, fmt.Errorf("invalid request: missing chat completion request")
 	}
-
 	if len(req.Functions) == 0 {
 		return nil, fmt.Errorf("at least one function must be provided")
 	}
 
-	return c.CreateChatCompletion(ctx, req.ChatCompletionRequest)
+	return c.CreateChatCompletion(ctx, withFunctionTools(req.ChatCompletionRequest, req.Functions))
 }
@@ -0,0 +1,105 @@
+// Package groqprom registers the metrics of Groq clients on a prometheus.Registerer, for
+// applications that already expose metrics with github.com/prometheus/client_golang. It
+// reports the same metrics as groq.Metrics:
+//
+//	recorder, err := groqprom.New(prometheus.DefaultRegisterer)
+//	if err != nil {
+//	    return err
+//	}
+//	client := groq.NewClient(apiKey, groq.WithMetrics(recorder))
+package groqprom
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/genc-murat/groq-client/pkg/groq"
+)
+
+// Recorder is a groq.MetricsRecorder updating client_golang collectors. It is safe for
+// concurrent use.
+type Recorder struct {
+	requests   *prometheus.CounterVec
+	latency    *prometheus.HistogramVec
+	firstToken *prometheus.HistogramVec
+	tokens     *prometheus.CounterVec
+	retries    *prometheus.CounterVec
+	waits      *prometheus.CounterVec
+	cache      *prometheus.CounterVec
+}
+
+// New creates a Recorder and registers its collectors on reg; pass the Recorder to
+// groq.WithMetrics. Histograms use groq.DefaultLatencyBuckets.
+//
+// Parameters:
+//   - reg: The registerer, e.g. prometheus.DefaultRegisterer or a prometheus.NewRegistry.
+//
+// Returns:
+//   - *Recorder: The recorder.
+//   - error: The registration error, e.g. a prometheus.AlreadyRegisteredError when reg
+//     already holds the groq metrics.
+func New(reg prometheus.Registerer) (*Recorder, error) {
+	r := &Recorder{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "groq_requests_total",
+			Help: "Calls to the Groq API by endpoint, model and status.",
+		}, []string{"endpoint", "model", "status"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "groq_request_duration_seconds",
+			Help:    "Latency of calls to the Groq API.",
+			Buckets: groq.DefaultLatencyBuckets,
+		}, []string{"endpoint", "model"}),
+		firstToken: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "groq_time_to_first_token_seconds",
+			Help:    "Time to the first chunk of streamed chat completions.",
+			Buckets: groq.DefaultLatencyBuckets,
+		}, []string{"model"}),
+		tokens: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "groq_tokens_total",
+			Help: "Prompt and completion tokens used.",
+		}, []string{"model", "type"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "groq_retries_total",
+			Help: "Retried attempts of calls to the Groq API.",
+		}, []string{"endpoint", "model"}),
+		waits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "groq_rate_limit_wait_seconds_total",
+			Help: "Time calls waited for rate limits.",
+		}, []string{"endpoint"}),
+		cache: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "groq_cache_requests_total",
+			Help: "Chat cache lookups by result.",
+		}, []string{"result"}),
+	}
+	for _, c := range []prometheus.Collector{r.requests, r.latency, r.firstToken, r.tokens, r.retries, r.waits, r.cache} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// RecordCall implements groq.MetricsRecorder.
+func (r *Recorder) RecordCall(call groq.CallMetrics) {
+	endpoint, model := string(call.Endpoint), string(call.Model)
+
+	r.requests.WithLabelValues(endpoint, model, call.StatusLabel()).Inc()
+	r.latency.WithLabelValues(endpoint, model).Observe(call.Latency.Seconds())
+	if call.TimeToFirstToken > 0 {
+		r.firstToken.WithLabelValues(model).Observe(call.TimeToFirstToken.Seconds())
+	}
+	if call.PromptTokens > 0 {
+		r.tokens.WithLabelValues(model, "prompt").Add(float64(call.PromptTokens))
+	}
+	if call.CompletionTokens > 0 {
+		r.tokens.WithLabelValues(model, "completion").Add(float64(call.CompletionTokens))
+	}
+	if call.Retries > 0 {
+		r.retries.WithLabelValues(endpoint, model).Add(float64(call.Retries))
+	}
+	if call.RateLimitWait > 0 {
+		r.waits.WithLabelValues(endpoint).Add(call.RateLimitWait.Seconds())
+	}
+	if call.Cache != "" {
+		r.cache.WithLabelValues(call.Cache).Inc()
+	}
+}
@@ -0,0 +1,76 @@
+package groqprom
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/genc-murat/groq-client/pkg/groq"
+)
+
+func TestRecorder(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	recorder, err := New(reg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var _ groq.MetricsRecorder = recorder
+
+	recorder.RecordCall(groq.CallMetrics{
+		Endpoint:         groq.EndpointChat,
+		Model:            groq.ModelLlama31_8bInstant,
+		Status:           200,
+		Latency:          300 * time.Millisecond,
+		TimeToFirstToken: 100 * time.Millisecond,
+		PromptTokens:     4,
+		CompletionTokens: 2,
+		Retries:          1,
+		RateLimitWait:    time.Second,
+		Cache:            "miss",
+	})
+	recorder.RecordCall(groq.CallMetrics{Endpoint: groq.EndpointChat, Model: groq.ModelLlama31_8bInstant, Failed: true})
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := make(map[string]float64)
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			key := family.GetName()
+			for _, label := range m.GetLabel() {
+				if label.GetName() == "status" || label.GetName() == "type" {
+					key += "/" + label.GetValue()
+				}
+			}
+			switch {
+			case m.GetCounter() != nil:
+				values[key] = m.GetCounter().GetValue()
+			case m.GetHistogram() != nil:
+				values[key] = float64(m.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+	for key, want := range map[string]float64{
+		"groq_requests_total/200":            1,
+		"groq_requests_total/error":          1,
+		"groq_request_duration_seconds":      2,
+		"groq_time_to_first_token_seconds":   1,
+		"groq_tokens_total/prompt":           4,
+		"groq_tokens_total/completion":       2,
+		"groq_retries_total":                 1,
+		"groq_rate_limit_wait_seconds_total": 1,
+		"groq_cache_requests_total":          1,
+	} {
+		if got := values[key]; got != want {
+			t.Errorf("%s = %v, want %v", key, got, want)
+		}
+	}
+
+	var already prometheus.AlreadyRegisteredError
+	if _, err := New(reg); !errors.As(err, &already) {
+		t.Errorf("registering twice: got %v, want AlreadyRegisteredError", err)
+	}
+}
@@ -0,0 +1,365 @@
+package groqtest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/genc-murat/groq-client/pkg/groq"
+	"github.com/genc-murat/groq-client/pkg/groq/wire"
+)
+
+// ErrNotScripted is returned by FakeClient for calls that have no queued outcome and no
+// sensible default, such as file uploads and batches.
+var ErrNotScripted = errors.New("groqtest: no outcome queued for the call")
+
+// Call is a call received by FakeClient.
+type Call struct {
+	Method  string      // The method called, e.g. "CreateChatCompletion"
+	Request interface{} // The request argument, e.g. *groq.ChatCompletionRequest; the ID for GetBatch and GetFileContent
+}
+
+// outcome is a queued result of a FakeClient call.
+type outcome struct {
+	value interface{}
+	err   error
+}
+
+// FakeClient is a groq.API that answers from queued outcomes instead of the network, and
+// records every call for assertions. Outcomes are served in order per kind of call; once
+// they run out, chat calls repeat the last user message, streams send it word by word,
+// audio calls return DefaultTranscript or SilentWAV and ListModels the known models,
+// while the other calls fail with ErrNotScripted.
+//
+// Example usage:
+//
+//	fake := groqtest.NewFakeClient()
+//	fake.QueueChatCompletion(nil, groq.ErrRateLimited)
+//	fake.QueueChatCompletion(groqtest.ChatCompletion("Paris"), nil)
+//
+//	answer, err := myapp.Ask(ctx, fake, "Capital of France?") // myapp.Ask takes a groq.API
+//	req := fake.ChatRequests()[1]
+type FakeClient struct {
+	outcomes map[string][]outcome
+	calls    []Call
+	mu       sync.Mutex
+}
+
+var _ groq.API = (*FakeClient)(nil)
+
+// NewFakeClient creates a fake without queued outcomes.
+func NewFakeClient() *FakeClient {
+	return &FakeClient{outcomes: make(map[string][]outcome)}
+}
+
+// QueueChatCompletion queues the outcome of a CreateChatCompletion or CreateFunctionCall call.
+func (f *FakeClient) QueueChatCompletion(resp *groq.ChatCompletionResponse, err error) {
+	f.queue("chat", resp, err)
+}
+
+// QueueStream queues the chunks a CreateChatCompletionStream call passes to its handler,
+// followed by err, which simulates a stream failing after the chunks.
+func (f *FakeClient) QueueStream(chunks []*groq.ChatCompletionChunk, err error) {
+	f.queue("stream", chunks, err)
+}
+
+// QueueTranscription queues the outcome of a CreateTranscription call.
+func (f *FakeClient) QueueTranscription(resp *groq.TranscriptionResponse, err error) {
+	f.queue("transcription", resp, err)
+}
+
+// QueueTranslation queues the outcome of a CreateTranslation call.
+func (f *FakeClient) QueueTranslation(resp *groq.TranslationResponse, err error) {
+	f.queue("translation", resp, err)
+}
+
+// QueueSpeech queues the outcome of a CreateSpeech or CreateSpeechTo call.
+func (f *FakeClient) QueueSpeech(audio []byte, err error) {
+	f.queue("speech", audio, err)
+}
+
+// QueueModels queues the outcome of a ListModels call.
+func (f *FakeClient) QueueModels(models *groq.ModelList, err error) {
+	f.queue("models", models, err)
+}
+
+// QueueFile queues the outcome of an UploadFile call.
+func (f *FakeClient) QueueFile(file *groq.File, err error) {
+	f.queue("file", file, err)
+}
+
+// QueueFileContent queues the outcome of a GetFileContent call.
+func (f *FakeClient) QueueFileContent(content []byte, err error) {
+	f.queue("file content", content, err)
+}
+
+// QueueBatch queues the outcome of a CreateBatch or GetBatch call.
+func (f *FakeClient) QueueBatch(batch *groq.Batch, err error) {
+	f.queue("batch", batch, err)
+}
+
+// Calls returns the calls received so far, oldest first.
+func (f *FakeClient) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]Call(nil), f.calls...)
+}
+
+// ChatRequests returns the requests of the chat completion, stream and function calls,
+// oldest first.
+func (f *FakeClient) ChatRequests() []*groq.ChatCompletionRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var requests []*groq.ChatCompletionRequest
+	for _, call := range f.calls {
+		switch req := call.Request.(type) {
+		case *groq.ChatCompletionRequest:
+			requests = append(requests, req)
+		case *groq.FunctionCallChatRequest:
+			requests = append(requests, req.ChatCompletionRequest)
+		}
+	}
+	return requests
+}
+
+// Reset drops the queued outcomes and recorded calls.
+func (f *FakeClient) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.outcomes = make(map[string][]outcome)
+	f.calls = nil
+}
+
+// queue appends an outcome for kind.
+func (f *FakeClient) queue(kind string, value interface{}, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.outcomes[kind] = append(f.outcomes[kind], outcome{value: value, err: err})
+}
+
+// record records a call and takes the next outcome queued for kind, if any.
+func (f *FakeClient) record(method, kind string, req interface{}) (outcome, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, Call{Method: method, Request: req})
+	queued := f.outcomes[kind]
+	if len(queued) == 0 {
+		return outcome{}, false
+	}
+	f.outcomes[kind] = queued[1:]
+	return queued[0], true
+}
+
+// CreateChatCompletion returns the next queued chat outcome, or the last user message.
+func (f *FakeClient) CreateChatCompletion(ctx context.Context, req *groq.ChatCompletionRequest, opts ...groq.RequestOption) (*groq.ChatCompletionResponse, error) {
+	return f.chat(ctx, "CreateChatCompletion", req, req)
+}
+
+// CreateFunctionCall returns the next queued chat outcome, or the last user message.
+func (f *FakeClient) CreateFunctionCall(ctx context.Context, req *groq.FunctionCallChatRequest) (*groq.ChatCompletionResponse, error) {
+	var chat *groq.ChatCompletionRequest
+	if req != nil {
+		chat = req.ChatCompletionRequest
+	}
+	return f.chat(ctx, "CreateFunctionCall", req, chat)
+}
+
+// chat implements the chat calls.
+func (f *FakeClient) chat(ctx context.Context, method string, req interface{}, chat *groq.ChatCompletionRequest) (*groq.ChatCompletionResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if out, ok := f.record(method, "chat", req); ok {
+		resp, _ := out.value.(*groq.ChatCompletionResponse)
+		return resp, out.err
+	}
+	return ChatCompletion(lastUserMessage(chat)), nil
+}
+
+// CreateChatCompletionStream passes the next queued chunks to handler and returns the
+// queued error; without queued chunks it streams the last user message word by word.
+func (f *FakeClient) CreateChatCompletionStream(ctx context.Context, req *groq.ChatCompletionRequest, handler groq.StreamHandler, opts ...groq.RequestOption) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	out, ok := f.record("CreateChatCompletionStream", "stream", req)
+	chunks, _ := out.value.([]*groq.ChatCompletionChunk)
+	if !ok {
+		chunks = Chunks(splitWords(lastUserMessage(req))...)
+	}
+
+	for _, chunk := range chunks {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := handler(chunk); err != nil {
+			return fmt.Errorf("stream handler error: %w", err)
+		}
+	}
+	return out.err
+}
+
+// CreateTranscription returns the next queued transcription, or DefaultTranscript.
+func (f *FakeClient) CreateTranscription(ctx context.Context, req *groq.TranscriptionRequest, opts ...groq.RequestOption) (*groq.TranscriptionResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if out, ok := f.record("CreateTranscription", "transcription", req); ok {
+		resp, _ := out.value.(*groq.TranscriptionResponse)
+		return resp, out.err
+	}
+	return &groq.TranscriptionResponse{Text: DefaultTranscript}, nil
+}
+
+// CreateTranslation returns the next queued translation, or DefaultTranscript.
+func (f *FakeClient) CreateTranslation(ctx context.Context, req *groq.TranslationRequest, opts ...groq.RequestOption) (*groq.TranslationResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if out, ok := f.record("CreateTranslation", "translation", req); ok {
+		resp, _ := out.value.(*groq.TranslationResponse)
+		return resp, out.err
+	}
+	return &groq.TranslationResponse{Text: DefaultTranscript}, nil
+}
+
+// CreateSpeech returns the next queued audio, or SilentWAV.
+func (f *FakeClient) CreateSpeech(ctx context.Context, req *groq.SpeechRequest, opts ...groq.RequestOption) ([]byte, error) {
+	return f.speech(ctx, "CreateSpeech", req)
+}
+
+// CreateSpeechTo writes the next queued audio, or SilentWAV, to w.
+func (f *FakeClient) CreateSpeechTo(ctx context.Context, req *groq.SpeechRequest, w io.Writer, opts ...groq.RequestOption) (int64, error) {
+	audio, err := f.speech(ctx, "CreateSpeechTo", req)
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(audio)
+	return int64(n), err
+}
+
+// speech implements the speech calls.
+func (f *FakeClient) speech(ctx context.Context, method string, req *groq.SpeechRequest) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if out, ok := f.record(method, "speech", req); ok {
+		audio, _ := out.value.([]byte)
+		return audio, out.err
+	}
+	return SilentWAV(), nil
+}
+
+// ListModels returns the next queued model list, or the models known to the client.
+func (f *FakeClient) ListModels(ctx context.Context) (*groq.ModelList, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if out, ok := f.record("ListModels", "models", nil); ok {
+		models, _ := out.value.(*groq.ModelList)
+		return models, out.err
+	}
+	var models groq.ModelList
+	if err := json.Unmarshal(modelsReply().Body, &models); err != nil {
+		return nil, err
+	}
+	return &models, nil
+}
+
+// UploadFile returns the next queued file.
+func (f *FakeClient) UploadFile(ctx context.Context, req *groq.FileUploadRequest) (*groq.File, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	out, ok := f.record("UploadFile", "file", req)
+	if !ok {
+		return nil, fmt.Errorf("%w: UploadFile", ErrNotScripted)
+	}
+	file, _ := out.value.(*groq.File)
+	return file, out.err
+}
+
+// GetFileContent returns the next queued file content.
+func (f *FakeClient) GetFileContent(ctx context.Context, fileID string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	out, ok := f.record("GetFileContent", "file content", fileID)
+	if !ok {
+		return nil, fmt.Errorf("%w: GetFileContent", ErrNotScripted)
+	}
+	content, _ := out.value.([]byte)
+	return content, out.err
+}
+
+// CreateBatch returns the next queued batch.
+func (f *FakeClient) CreateBatch(ctx context.Context, req *groq.BatchCreateRequest) (*groq.Batch, error) {
+	return f.batch(ctx, "CreateBatch", req)
+}
+
+// GetBatch returns the next queued batch.
+func (f *FakeClient) GetBatch(ctx context.Context, batchID string) (*groq.Batch, error) {
+	return f.batch(ctx, "GetBatch", batchID)
+}
+
+// batch implements the batch calls.
+func (f *FakeClient) batch(ctx context.Context, method string, req interface{}) (*groq.Batch, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	out, ok := f.record(method, "batch", req)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotScripted, method)
+	}
+	batch, _ := out.value.(*groq.Batch)
+	return batch, out.err
+}
+
+// ChatCompletion returns a chat completion whose only choice answers with content, as
+// ChatReply sends it, for QueueChatCompletion.
+func ChatCompletion(content string) *groq.ChatCompletionResponse {
+	var resp groq.ChatCompletionResponse
+	if err := json.Unmarshal(ChatReply(content).Body, &resp); err != nil {
+		panic(err)
+	}
+	return &resp
+}
+
+// Chunks returns the chunks of a stream sending one delta each and a final chunk with
+// the finish reason and usage, as StreamReply sends them, for QueueStream.
+func Chunks(deltas ...string) []*groq.ChatCompletionChunk {
+	events := StreamReply(deltas...).Events
+	chunks := make([]*groq.ChatCompletionChunk, len(events))
+	for i, event := range events {
+		chunks[i] = new(groq.ChatCompletionChunk)
+		if err := json.Unmarshal([]byte(event), chunks[i]); err != nil {
+			panic(err)
+		}
+	}
+	return chunks
+}
+
+// lastUserMessage returns the text of the last user message of req, read from its wire
+// encoding like Server does, whatever the form of the message content.
+func lastUserMessage(req *groq.ChatCompletionRequest) string {
+	if req == nil {
+		return ""
+	}
+	data, err := json.Marshal(req)
+	if err != nil {
+		return ""
+	}
+	var chat wire.ChatCompletionRequest
+	if err := json.Unmarshal(data, &chat); err != nil {
+		return ""
+	}
+	return lastUserText(chat.Messages)
+}
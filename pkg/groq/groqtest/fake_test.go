@@ -0,0 +1,61 @@
+package groqtest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/genc-murat/groq-client/pkg/groq"
+)
+
+func TestFakeClient(t *testing.T) {
+	fake := NewFakeClient()
+	var api groq.API = fake
+	ctx := context.Background()
+	req := groq.NewRequest(groq.ModelLlama31_8bInstant).User("what is up").Build()
+
+	fake.QueueChatCompletion(nil, groq.ErrRateLimited)
+	fake.QueueChatCompletion(ChatCompletion("scripted"), nil)
+	if _, err := api.CreateChatCompletion(ctx, req); !errors.Is(err, groq.ErrRateLimited) {
+		t.Errorf("first call: expected the queued error, got %v", err)
+	}
+	resp, err := api.CreateChatCompletion(ctx, req)
+	if err != nil || resp.Choices[0].Message.Content != "scripted" {
+		t.Errorf("second call = %+v, %v; want the queued reply", resp, err)
+	}
+	resp, err = api.CreateChatCompletion(ctx, req)
+	if err != nil || resp.Choices[0].Message.Content != "what is up" {
+		t.Errorf("unscripted call = %+v, %v; want the user message", resp, err)
+	}
+
+	streamFailed := errors.New("connection reset")
+	fake.QueueStream(Chunks("Hel", "lo"), streamFailed)
+	var text strings.Builder
+	err = api.CreateChatCompletionStream(ctx, req, func(chunk *groq.ChatCompletionChunk) error {
+		if len(chunk.Choices) > 0 {
+			text.WriteString(chunk.Choices[0].Delta.Content)
+		}
+		return nil
+	})
+	if text.String() != "Hello" || !errors.Is(err, streamFailed) {
+		t.Errorf("stream = %q, %v; want the queued chunks and error", text.String(), err)
+	}
+
+	var audio bytes.Buffer
+	if _, err := api.CreateSpeechTo(ctx, &groq.SpeechRequest{Input: "hi"}, &audio); err != nil || !bytes.Equal(audio.Bytes(), SilentWAV()) {
+		t.Errorf("CreateSpeechTo = %d bytes, %v", audio.Len(), err)
+	}
+	if _, err := api.GetBatch(ctx, "batch_1"); !errors.Is(err, ErrNotScripted) {
+		t.Errorf("GetBatch: expected ErrNotScripted, got %v", err)
+	}
+
+	if n := len(fake.ChatRequests()); n != 4 {
+		t.Errorf("captured %d chat requests, want 4", n)
+	}
+	calls := fake.Calls()
+	if last := calls[len(calls)-1]; last.Method != "GetBatch" || last.Request != "batch_1" {
+		t.Errorf("last call = %+v", last)
+	}
+}
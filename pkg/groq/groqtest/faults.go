@@ -0,0 +1,102 @@
+package groqtest
+
+import (
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// malformedEvent is the data of an event that is not valid JSON.
+const malformedEvent = `{"id":"chatcmpl-groqtest","choices":[{"delta":{"content":"`
+
+// Faults is the chaos a Server injects into its replies, so applications can test their
+// retry and stream recovery logic. Each rate is the probability, from 0 to 1, that a reply
+// gets the fault; faults replace or alter scripted and default replies alike.
+//
+// Example usage:
+//
+//	srv.SetFaults(groqtest.Faults{ErrorRate: 0.3, TruncateRate: 0.2, Seed: 1})
+type Faults struct {
+	// ErrorRate is the share of requests answered with an error status instead.
+	ErrorRate float64
+	// ErrorStatuses are the statuses of the injected errors, picked at random; empty uses
+	// 429 and 500.
+	ErrorStatuses []int
+
+	// TruncateRate is the share of streams whose connection drops before [DONE].
+	TruncateRate float64
+	// MalformedRate is the share of streams in which one event is not valid JSON.
+	MalformedRate float64
+
+	// SlowRate is the share of replies delayed by SlowFirstByte before the first byte.
+	SlowRate      float64
+	SlowFirstByte time.Duration
+
+	// Paths limits the faults to these endpoints, e.g. PathChatCompletions; empty
+	// affects all of them.
+	Paths []string
+	// Seed makes the random choices reproducible; 0 uses the current time.
+	Seed int64
+}
+
+// SetFaults makes the server inject faults into its replies from now on. The zero Faults
+// turns fault injection off.
+func (s *Server) SetFaults(faults Faults) {
+	seed := faults.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = faults
+	s.rng = rand.New(rand.NewSource(seed))
+}
+
+// injectFaults returns reply altered by the configured faults. The caller must hold s.mu.
+func (s *Server) injectFaults(path string, reply Reply) Reply {
+	f := s.faults
+	if s.rng == nil || !f.applies(path) {
+		return reply
+	}
+
+	if s.rng.Float64() < f.SlowRate {
+		reply.Latency += f.SlowFirstByte
+	}
+	if s.rng.Float64() < f.ErrorRate {
+		statuses := f.ErrorStatuses
+		if len(statuses) == 0 {
+			statuses = []int{http.StatusTooManyRequests, http.StatusInternalServerError}
+		}
+		status := statuses[s.rng.Intn(len(statuses))]
+		injected := ErrorReply(status, "groqtest: injected fault")
+		injected.Latency = reply.Latency
+		return injected
+	}
+	if len(reply.Events) == 0 {
+		return reply
+	}
+	if s.rng.Float64() < f.MalformedRate {
+		events := append([]string(nil), reply.Events...)
+		events[s.rng.Intn(len(events))] = malformedEvent
+		reply.Events = events
+	}
+	if s.rng.Float64() < f.TruncateRate {
+		reply.AbortAfter = 1 + s.rng.Intn(len(reply.Events))
+	}
+	return reply
+}
+
+// applies reports whether the faults affect requests for path.
+func (f Faults) applies(path string) bool {
+	if len(f.Paths) == 0 {
+		return true
+	}
+	for _, p := range f.Paths {
+		if p == path {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package groqtest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/genc-murat/groq-client/pkg/groq"
+)
+
+func TestServerFaults(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	ctx := context.Background()
+	req := groq.NewRequest(groq.ModelLlama31_8bInstant).User("hello there").Build()
+	stream := func(client *groq.Client) error {
+		return client.CreateChatCompletionStream(ctx, req, func(*groq.ChatCompletionChunk) error { return nil })
+	}
+
+	t.Run("errors are retried", func(t *testing.T) {
+		srv.SetFaults(Faults{ErrorRate: 0.5, Seed: 7})
+		client := srv.Client(groq.WithRetryConfig(10, time.Millisecond))
+		defer client.Close()
+		for i := 0; i < 5; i++ {
+			if _, err := client.CreateChatCompletion(ctx, req); err != nil {
+				t.Fatalf("call %d: %v", i, err)
+			}
+		}
+	})
+
+	t.Run("errors without retries", func(t *testing.T) {
+		srv.SetFaults(Faults{ErrorRate: 1, ErrorStatuses: []int{http.StatusServiceUnavailable}, Seed: 1})
+		client := srv.Client(groq.WithRetryConfig(0, time.Millisecond))
+		defer client.Close()
+		var apiErr *groq.APIError
+		if _, err := client.CreateChatCompletion(ctx, req); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("expected an injected 503, got %v", err)
+		}
+	})
+
+	t.Run("truncated streams", func(t *testing.T) {
+		srv.SetFaults(Faults{TruncateRate: 1, Paths: []string{PathChatCompletions}, Seed: 1})
+		client := srv.Client(groq.WithRetryConfig(0, time.Millisecond))
+		defer client.Close()
+		if err := stream(client); err == nil {
+			t.Error("expected a truncated stream to fail")
+		}
+	})
+
+	t.Run("malformed chunks", func(t *testing.T) {
+		srv.SetFaults(Faults{MalformedRate: 1, Seed: 1})
+		client := srv.Client()
+		defer client.Close()
+		if err := stream(client); !errors.Is(err, groq.ErrJSONDecoding) {
+			t.Errorf("expected ErrJSONDecoding, got %v", err)
+		}
+	})
+
+	t.Run("slow first byte", func(t *testing.T) {
+		srv.SetFaults(Faults{SlowRate: 1, SlowFirstByte: 300 * time.Millisecond, Seed: 1})
+		client := srv.Client(groq.WithRetryConfig(0, time.Millisecond))
+		defer client.Close()
+		if _, err := client.CreateChatCompletion(ctx, req, groq.WithRequestTimeout(30*time.Millisecond)); err == nil {
+			t.Error("expected the slow reply to time out")
+		}
+	})
+
+	t.Run("other paths unaffected", func(t *testing.T) {
+		srv.SetFaults(Faults{ErrorRate: 1, Paths: []string{PathSpeech}, Seed: 1})
+		client := srv.Client(groq.WithRetryConfig(0, time.Millisecond))
+		defer client.Close()
+		if _, err := client.CreateChatCompletion(ctx, req); err != nil {
+			t.Errorf("chat failed with faults limited to speech: %v", err)
+		}
+	})
+}
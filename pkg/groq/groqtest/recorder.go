@@ -0,0 +1,309 @@
+package groqtest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/genc-murat/groq-client/pkg/groq"
+)
+
+// Mode selects whether a Recorder talks to the API or replays a cassette.
+type Mode int
+
+const (
+	// ModeReplay answers from the cassette and never touches the network. Requests the
+	// cassette does not contain fail with ErrNoInteraction.
+	ModeReplay Mode = iota
+	// ModeRecord sends the requests to the API and saves the exchanges on Close,
+	// replacing the cassette.
+	ModeRecord
+	// ModeAuto replays the cassette if it exists and records it otherwise.
+	ModeAuto
+)
+
+// ErrNoInteraction is returned in ModeReplay for a request the cassette has no answer for.
+var ErrNoInteraction = errors.New("groqtest: no recorded interaction matches the request")
+
+// redacted replaces the values of SensitiveHeaders in cassettes.
+const redacted = "REDACTED"
+
+// SensitiveHeaders are the headers whose values a Recorder never writes to a cassette.
+var SensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "X-Api-Key", "Cookie", "Set-Cookie"}
+
+// Cassette is the file format of a Recorder: the recorded exchanges in order.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one recorded request and its response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the request of an Interaction. Uploads keep their headers only; their
+// multipart bodies, which carry the audio and a random boundary, are not stored.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is the response of an Interaction. Text bodies, including streamed
+// server-sent events, are stored verbatim; binary bodies such as audio in Base64.
+type RecordedResponse struct {
+	Status     int         `json:"status"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	BodyBase64 string      `json:"body_base64,omitempty"`
+}
+
+// Recorder is an http.RoundTripper that records API exchanges to a cassette file and
+// replays them, so tests run deterministically and offline against real API responses.
+// API keys and other SensitiveHeaders are stripped before anything is written. Replayed
+// requests are matched by method, path and JSON body, in the order they were recorded.
+//
+// Example usage:
+//
+//	rec, err := groqtest.NewRecorder("testdata/chat.json", groqtest.ModeAuto)
+//	if err != nil {
+//	    t.Fatal(err)
+//	}
+//	defer rec.Close()
+//	client := groq.NewClient(os.Getenv("GROQ_API_KEY"), rec.Option())
+type Recorder struct {
+	// Transport sends the requests in ModeRecord; nil uses http.DefaultTransport.
+	Transport http.RoundTripper
+	// Filter, if set, can redact further data of an interaction before it is saved.
+	Filter func(*Interaction)
+
+	path     string
+	mode     Mode
+	cassette Cassette
+	used     []bool // Interactions already replayed
+	mu       sync.Mutex
+}
+
+// NewRecorder creates a recorder for the cassette at path. ModeAuto resolves to
+// ModeReplay if the file exists and to ModeRecord otherwise.
+//
+// Parameters:
+//   - path: The cassette file.
+//   - mode: Whether to record or replay.
+//
+// Returns:
+//   - *Recorder: The recorder.
+//   - error: An error if a cassette to replay cannot be read.
+func NewRecorder(path string, mode Mode) (*Recorder, error) {
+	if mode == ModeAuto {
+		mode = ModeRecord
+		if _, err := os.Stat(path); err == nil {
+			mode = ModeReplay
+		}
+	}
+
+	r := &Recorder{path: path, mode: mode}
+	if mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("groqtest: reading cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, &r.cassette); err != nil {
+			return nil, fmt.Errorf("groqtest: parsing cassette %s: %w", path, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+	return r, nil
+}
+
+// Mode returns whether the recorder records or replays.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Option returns the client option sending requests through the recorder.
+func (r *Recorder) Option() groq.Option {
+	return groq.WithHTTPTransport(r)
+}
+
+// RoundTrip records or replays one exchange.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, err := recordRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	interaction := Interaction{
+		Request: recorded,
+		Response: RecordedResponse{
+			Status: resp.StatusCode,
+			Header: redactHeader(resp.Header),
+		},
+	}
+	if utf8.Valid(body) {
+		interaction.Response.Body = string(body)
+	} else {
+		interaction.Response.BodyBase64 = base64.StdEncoding.EncodeToString(body)
+	}
+	if r.Filter != nil {
+		r.Filter(&interaction)
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// replay answers req with the first unused interaction matching it.
+func (r *Recorder) replay(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || !matches(interaction.Request, recorded) {
+			continue
+		}
+		r.used[i] = true
+
+		body := []byte(interaction.Response.Body)
+		if interaction.Response.BodyBase64 != "" {
+			decoded, err := base64.StdEncoding.DecodeString(interaction.Response.BodyBase64)
+			if err != nil {
+				return nil, fmt.Errorf("groqtest: decoding recorded body: %w", err)
+			}
+			body = decoded
+		}
+		header := interaction.Response.Header.Clone()
+		if header == nil {
+			header = make(http.Header)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.Status, http.StatusText(interaction.Response.Status)),
+			StatusCode:    interaction.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, recorded.Method, recorded.URL)
+}
+
+// Close saves the recorded exchanges in ModeRecord; it does nothing in ModeReplay.
+func (r *Recorder) Close() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("groqtest: encoding cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("groqtest: creating cassette directory: %w", err)
+	}
+	if err := os.WriteFile(r.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("groqtest: writing cassette: %w", err)
+	}
+	return nil
+}
+
+// recordRequest captures req for the cassette, restoring its body for sending.
+func recordRequest(req *http.Request) (RecordedRequest, error) {
+	recorded := RecordedRequest{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Header: redactHeader(req.Header),
+	}
+	if req.Body == nil || req.Body == http.NoBody {
+		return recorded, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return recorded, fmt.Errorf("groqtest: reading request body: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	if !strings.HasPrefix(req.Header.Get("Content-Type"), "multipart/") && utf8.Valid(body) {
+		recorded.Body = string(body)
+	}
+	return recorded, nil
+}
+
+// matches reports whether a recorded request answers req: same method and path, and the
+// same body, compared as JSON when both bodies are JSON.
+func matches(recorded, req RecordedRequest) bool {
+	if recorded.Method != req.Method || requestPath(recorded.URL) != requestPath(req.URL) {
+		return false
+	}
+	if recorded.Body == req.Body {
+		return true
+	}
+	var a, b interface{}
+	if json.Unmarshal([]byte(recorded.Body), &a) != nil || json.Unmarshal([]byte(req.Body), &b) != nil {
+		return false
+	}
+	aJSON, _ := json.Marshal(a)
+	bJSON, _ := json.Marshal(b)
+	return bytes.Equal(aJSON, bJSON)
+}
+
+// requestPath returns the path and query of a URL, ignoring the host so cassettes replay
+// against any server.
+func requestPath(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return u.RequestURI()
+}
+
+// redactHeader returns a copy of h with the values of SensitiveHeaders replaced.
+func redactHeader(h http.Header) http.Header {
+	if len(h) == 0 {
+		return nil
+	}
+	clean := h.Clone()
+	for _, name := range SensitiveHeaders {
+		if _, ok := clean[http.CanonicalHeaderKey(name)]; ok {
+			clean.Set(name, redacted)
+		}
+	}
+	return clean
+}
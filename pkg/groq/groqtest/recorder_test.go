@@ -0,0 +1,89 @@
+package groqtest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/genc-murat/groq-client/pkg/groq"
+)
+
+func TestRecorder(t *testing.T) {
+	srv := NewServer()
+	cassette := filepath.Join(t.TempDir(), "cassettes", "session.json")
+
+	// exercise makes a chat, a streamed chat and a speech call and returns what they answered.
+	exercise := func(client *groq.Client) (string, string, []byte, error) {
+		ctx := context.Background()
+		resp, err := client.CreateChatCompletion(ctx, groq.NewRequest(groq.ModelLlama31_8bInstant).User("recorded answer").Build())
+		if err != nil {
+			return "", "", nil, err
+		}
+		var streamed strings.Builder
+		err = client.CreateChatCompletionStream(ctx, groq.NewRequest(groq.ModelLlama31_8bInstant).User("streamed answer").Build(),
+			func(chunk *groq.ChatCompletionChunk) error {
+				if len(chunk.Choices) > 0 {
+					streamed.WriteString(chunk.Choices[0].Delta.Content)
+				}
+				return nil
+			})
+		if err != nil {
+			return "", "", nil, err
+		}
+		audio, err := client.CreateSpeech(ctx, &groq.SpeechRequest{Model: groq.ModelPlayAITTS, Input: "hi", Voice: groq.VoiceFritz})
+		return resp.Choices[0].Message.Content.(string), streamed.String(), audio, err
+	}
+
+	rec, err := NewRecorder(cassette, ModeAuto)
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+	if rec.Mode() != ModeRecord {
+		t.Fatalf("mode = %v, want ModeRecord without a cassette", rec.Mode())
+	}
+	client := groq.NewClient("gsk_secret", groq.WithBaseURL(srv.URL), rec.Option())
+	chat, streamed, audio, err := exercise(client)
+	if err != nil {
+		t.Fatalf("recording: %v", err)
+	}
+	client.Close()
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	srv.Close()
+
+	data, err := os.ReadFile(cassette)
+	if err != nil {
+		t.Fatalf("reading cassette: %v", err)
+	}
+	if bytes.Contains(data, []byte("gsk_secret")) {
+		t.Error("the cassette contains the API key")
+	}
+
+	rec, err = NewRecorder(cassette, ModeAuto)
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+	if rec.Mode() != ModeReplay {
+		t.Fatalf("mode = %v, want ModeReplay with a cassette", rec.Mode())
+	}
+	client = groq.NewClient("other-key", groq.WithBaseURL(srv.URL), rec.Option(), groq.WithRetryConfig(0, time.Millisecond))
+	defer client.Close()
+	replayedChat, replayedStream, replayedAudio, err := exercise(client)
+	if err != nil {
+		t.Fatalf("replaying with the server closed: %v", err)
+	}
+	if replayedChat != chat || replayedStream != streamed || !bytes.Equal(replayedAudio, audio) {
+		t.Errorf("replay = %q, %q, %d bytes; recorded %q, %q, %d bytes", replayedChat, replayedStream, len(replayedAudio), chat, streamed, len(audio))
+	}
+
+	_, err = client.CreateChatCompletion(context.Background(), groq.NewRequest(groq.ModelLlama31_8bInstant).User("never recorded").Build())
+	if !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("expected ErrNoInteraction, got %v", err)
+	}
+}
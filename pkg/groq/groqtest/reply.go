@@ -0,0 +1,183 @@
+package groqtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/genc-murat/groq-client/pkg/groq/wire"
+)
+
+// Model is the model named in the replies built by ChatReply and StreamReply.
+const Model = "llama-3.1-8b-instant"
+
+// replyIDs numbers the IDs of chat replies.
+var replyIDs atomic.Int64
+
+// Reply is a scripted answer of Server. Replies with Events are sent as a stream of
+// server-sent events ending with "data: [DONE]"; the others send Body.
+type Reply struct {
+	Status int               // Status code; 0 is 200
+	Header map[string]string // Response headers, e.g. Retry-After
+	Body   []byte            // The body of replies without Events
+
+	Events     []string      // Data of the server-sent events, usually JSON chunks
+	EventDelay time.Duration // Pause before every event after the first
+	AbortAfter int           // If positive, the connection drops after this many events
+
+	Latency time.Duration // Delay before the reply is sent
+}
+
+// JSONReply returns a reply with the JSON encoding of v.
+func JSONReply(status int, v interface{}) Reply {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return ErrorReply(http.StatusInternalServerError, fmt.Sprintf("groqtest: encoding reply: %v", err))
+	}
+	return Reply{
+		Status: status,
+		Header: map[string]string{"Content-Type": "application/json"},
+		Body:   body,
+	}
+}
+
+// ChatReply returns a chat completion whose only choice answers with content.
+func ChatReply(content string) Reply {
+	completion := countTokens(content)
+	return JSONReply(http.StatusOK, wire.ChatCompletionResponse{
+		ID:      nextID(),
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Model:   Model,
+		Choices: []wire.Choice{{
+			Message:      wire.ChatMessage{Role: "assistant", Content: content},
+			FinishReason: "stop",
+		}},
+		Usage: wire.Usage{PromptTokens: 10, CompletionTokens: completion, TotalTokens: 10 + completion},
+	})
+}
+
+// StreamReply returns a streamed chat completion sending one chunk per delta. The last
+// chunk finishes the choice and reports the usage in x_groq, like the API.
+func StreamReply(deltas ...string) Reply {
+	id := nextID()
+	created := time.Now().Unix()
+	chunk := func(choice wire.ChunkChoice) wire.ChatCompletionChunk {
+		return wire.ChatCompletionChunk{
+			ID:      id,
+			Object:  "chat.completion.chunk",
+			Created: created,
+			Model:   Model,
+			Choices: []wire.ChunkChoice{choice},
+		}
+	}
+
+	var events []string
+	completion := 0
+	for i, delta := range deltas {
+		c := chunk(wire.ChunkChoice{Delta: wire.Delta{Content: delta}})
+		if i == 0 {
+			c.Choices[0].Delta.Role = "assistant"
+		}
+		events = append(events, mustJSON(c))
+		completion += countTokens(delta)
+	}
+	last := chunk(wire.ChunkChoice{FinishReason: "stop"})
+	last.XGroq = &wire.XGroq{ID: id, Usage: &wire.Usage{PromptTokens: 10, CompletionTokens: completion, TotalTokens: 10 + completion}}
+	events = append(events, mustJSON(last))
+
+	return Reply{Events: events}
+}
+
+// TranscriptionReply returns a transcription or translation of text.
+func TranscriptionReply(text string) Reply {
+	return JSONReply(http.StatusOK, wire.TranscriptionResponse{Text: text})
+}
+
+// SpeechReply returns synthesized audio, such as SilentWAV.
+func SpeechReply(audio []byte) Reply {
+	return Reply{
+		Header: map[string]string{"Content-Type": "audio/wav"},
+		Body:   audio,
+	}
+}
+
+// ErrorReply returns an API error with the status and message. Rate limit errors ask to
+// retry at once, so clients under test do not wait.
+func ErrorReply(status int, message string) Reply {
+	detail := wire.ErrorDetail{Message: message, Type: "invalid_request_error"}
+	switch {
+	case status == http.StatusTooManyRequests:
+		detail.Type, detail.Code = "tokens", "rate_limit_exceeded"
+	case status >= 500:
+		detail.Type = "internal_server_error"
+	}
+	reply := JSONReply(status, wire.ErrorResponse{Error: detail})
+	if status == http.StatusTooManyRequests {
+		reply.Header["Retry-After"] = "0"
+	}
+	return reply
+}
+
+// writeReply sends reply, stopping a stream early if ctx is done.
+func writeReply(ctx context.Context, w http.ResponseWriter, reply Reply) {
+	for k, v := range reply.Header {
+		w.Header().Set(k, v)
+	}
+	status := reply.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	if reply.Events == nil {
+		w.WriteHeader(status)
+		_, _ = w.Write(reply.Body)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(status)
+	flusher, _ := w.(http.Flusher)
+	for i, event := range reply.Events {
+		if reply.AbortAfter > 0 && i == reply.AbortAfter {
+			break
+		}
+		if i > 0 && !sleep(ctx, reply.EventDelay) {
+			return
+		}
+		fmt.Fprintf(w, "data: %s\n\n", event)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	if reply.AbortAfter > 0 {
+		// Drop the connection mid-stream, so the client sees a truncated body rather
+		// than a stream that ended without [DONE].
+		panic(http.ErrAbortHandler)
+	}
+	fmt.Fprint(w, "data: [DONE]\n\n")
+}
+
+// nextID returns a new completion ID.
+func nextID() string {
+	return fmt.Sprintf("chatcmpl-groqtest-%d", replyIDs.Add(1))
+}
+
+// countTokens approximates the tokens of s by its words.
+func countTokens(s string) int {
+	return len(strings.Fields(s))
+}
+
+// mustJSON encodes v, which cannot fail for the wire types.
+func mustJSON(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
@@ -0,0 +1,372 @@
+// Package groqtest provides test doubles of the Groq API, so code using the client can be
+// tested hermetically. Server is an in-process HTTP server speaking the chat completions,
+// streaming and audio protocols, with scripted replies, latency and injected errors.
+package groqtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/genc-murat/groq-client/pkg/groq"
+	"github.com/genc-murat/groq-client/pkg/groq/wire"
+)
+
+// Paths of the endpoints served by Server, relative to its URL.
+const (
+	PathChatCompletions = "/chat/completions"
+	PathTranscriptions  = "/audio/transcriptions"
+	PathTranslations    = "/audio/translations"
+	PathSpeech          = "/audio/speech"
+	PathModels          = "/models"
+)
+
+// DefaultTranscript is the text of transcriptions and translations that have no scripted reply.
+const DefaultTranscript = "Hello from groqtest."
+
+// Server is a fake Groq API on a local httptest server. Replies scripted with Enqueue are
+// served in order for their path; once they run out, HandleFunc handlers answer, and
+// without one the server answers plausibly: chat completions repeat the last user
+// message, streamed as one chunk per word when the request asks for a stream,
+// transcriptions return DefaultTranscript and speech returns a silent WAV file.
+//
+// Example usage:
+//
+//	srv := groqtest.NewServer()
+//	defer srv.Close()
+//	srv.Enqueue(groqtest.PathChatCompletions,
+//	    groqtest.ErrorReply(http.StatusTooManyRequests, "slow down"),
+//	    groqtest.ChatReply("Hi!"),
+//	)
+//	client := srv.Client(groq.WithRetryConfig(1, time.Millisecond))
+type Server struct {
+	URL string // Base URL of the API, for groq.WithBaseURL
+
+	srv      *httptest.Server
+	replies  map[string][]Reply
+	handlers map[string]func(*Request) Reply
+	requests []*Request
+	latency  time.Duration
+	faults   Faults
+	rng      *rand.Rand // Random source of the faults, nil until SetFaults
+	mu       sync.Mutex
+}
+
+// NewServer starts a server. Close it when the test is done.
+func NewServer() *Server {
+	s := &Server{
+		replies:  make(map[string][]Reply),
+		handlers: make(map[string]func(*Request) Reply),
+	}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close shuts the server down, waiting for requests in flight.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Client creates a client sending its requests to the server. The options are applied
+// after the base URL and may add retries, hooks or a cache.
+func (s *Server) Client(opts ...groq.Option) *groq.Client {
+	return groq.NewClient("groqtest-key", append([]groq.Option{groq.WithBaseURL(s.URL)}, opts...)...)
+}
+
+// Enqueue scripts the next replies to requests for path, such as PathChatCompletions.
+// Each reply is served once, in order, after the replies enqueued before.
+func (s *Server) Enqueue(path string, replies ...Reply) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.replies[path] = append(s.replies[path], replies...)
+}
+
+// HandleFunc answers requests for path that find no enqueued reply with handler, e.g. to
+// reply depending on the request. Passing nil restores the default replies.
+func (s *Server) HandleFunc(path string, handler func(*Request) Reply) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if handler == nil {
+		delete(s.handlers, path)
+		return
+	}
+	s.handlers[path] = handler
+}
+
+// SetLatency delays every reply by d, in addition to the latency of the reply itself.
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.latency = d
+}
+
+// Requests returns the requests received so far, oldest first.
+func (s *Server) Requests() []*Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*Request(nil), s.requests...)
+}
+
+// LastRequest returns the most recent request, or nil if there was none.
+func (s *Server) LastRequest() *Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.requests) == 0 {
+		return nil
+	}
+	return s.requests[len(s.requests)-1]
+}
+
+// Reset drops the scripted replies, handlers, faults and recorded requests.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.replies = make(map[string][]Reply)
+	s.handlers = make(map[string]func(*Request) Reply)
+	s.requests = nil
+	s.latency = 0
+	s.faults, s.rng = Faults{}, nil
+}
+
+// serveHTTP records the request and writes the reply chosen for it.
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	req, err := readRequest(r)
+	if err != nil {
+		writeReply(r.Context(), w, ErrorReply(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	latency := s.latency
+	var (
+		reply   Reply
+		handler func(*Request) Reply
+	)
+	scripted := len(s.replies[req.Path]) > 0
+	if scripted {
+		reply = s.replies[req.Path][0]
+		s.replies[req.Path] = s.replies[req.Path][1:]
+	} else {
+		handler = s.handlers[req.Path]
+	}
+	s.mu.Unlock()
+
+	switch {
+	case scripted:
+	case handler != nil:
+		reply = handler(req)
+	default:
+		reply = defaultReply(req)
+	}
+	s.mu.Lock()
+	reply = s.injectFaults(req.Path, reply)
+	s.mu.Unlock()
+
+	if !sleep(r.Context(), latency+reply.Latency) {
+		return
+	}
+	writeReply(r.Context(), w, reply)
+}
+
+// defaultReply returns the unscripted reply to req.
+func defaultReply(req *Request) Reply {
+	switch req.Path {
+	case PathChatCompletions:
+		chat, err := req.Chat()
+		if err != nil {
+			return ErrorReply(http.StatusBadRequest, err.Error())
+		}
+		content := lastUserText(chat.Messages)
+		if chat.Stream {
+			return StreamReply(splitWords(content)...)
+		}
+		return ChatReply(content)
+	case PathTranscriptions, PathTranslations:
+		switch req.Form["response_format"] {
+		case "text", "srt", "vtt":
+			return Reply{Header: map[string]string{"Content-Type": "text/plain"}, Body: []byte(DefaultTranscript)}
+		}
+		return TranscriptionReply(DefaultTranscript)
+	case PathSpeech:
+		return SpeechReply(SilentWAV())
+	case PathModels:
+		return modelsReply()
+	}
+	return ErrorReply(http.StatusNotFound, fmt.Sprintf("unknown path %s", req.Path))
+}
+
+// lastUserText returns the text of the last user message.
+func lastUserText(messages []wire.ChatMessage) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role != "user" {
+			continue
+		}
+		switch content := messages[i].Content.(type) {
+		case string:
+			return content
+		case []wire.ContentPart:
+			var text []string
+			for _, part := range content {
+				if part.Type == "text" {
+					text = append(text, part.Text)
+				}
+			}
+			return strings.Join(text, " ")
+		}
+	}
+	return ""
+}
+
+// splitWords splits s into stream deltas of one word each, keeping the spaces.
+func splitWords(s string) []string {
+	var deltas []string
+	for i, word := range strings.Fields(s) {
+		if i > 0 {
+			word = " " + word
+		}
+		deltas = append(deltas, word)
+	}
+	return deltas
+}
+
+// modelsReply lists the models known to the client.
+func modelsReply() Reply {
+	models := groq.AllModels()
+	sort.Slice(models, func(i, j int) bool { return models[i] < models[j] })
+
+	list := wire.ModelList{Object: "list"}
+	for _, model := range models {
+		list.Data = append(list.Data, wire.Model{
+			ID:            string(model),
+			Object:        "model",
+			OwnedBy:       "groqtest",
+			Active:        true,
+			ContextWindow: model.GetInfo().ContextWindow,
+		})
+	}
+	return JSONReply(http.StatusOK, list)
+}
+
+// SilentWAV returns a valid WAV file without samples, the default reply to speech requests.
+func SilentWAV() []byte {
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(36))
+	buf.WriteString("WAVEfmt ")
+	for _, field := range []any{
+		uint32(16),    // Size of the fmt chunk
+		uint16(1),     // PCM
+		uint16(1),     // Mono
+		uint32(16000), // Sample rate
+		uint32(32000), // Byte rate
+		uint16(2),     // Block align
+		uint16(16),    // Bits per sample
+	} {
+		_ = binary.Write(&buf, binary.LittleEndian, field)
+	}
+	buf.WriteString("data")
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(0))
+	return buf.Bytes()
+}
+
+// sleep waits for d or until ctx is done, reporting whether the wait completed.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// readRequest records r, reading its body and, for uploads, its multipart form.
+func readRequest(r *http.Request) (*Request, error) {
+	req := &Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Header: r.Header.Clone(),
+	}
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return nil, fmt.Errorf("reading body: %w", err)
+		}
+		req.Body = body
+		return req, nil
+	}
+
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		return nil, fmt.Errorf("parsing multipart form: %w", err)
+	}
+	req.Form = make(map[string]string)
+	for name, values := range r.MultipartForm.Value {
+		if len(values) > 0 {
+			req.Form[name] = values[0]
+		}
+	}
+	if files := r.MultipartForm.File["file"]; len(files) > 0 {
+		file, err := files[0].Open()
+		if err != nil {
+			return nil, fmt.Errorf("opening file: %w", err)
+		}
+		defer file.Close()
+		if req.File, err = io.ReadAll(file); err != nil {
+			return nil, fmt.Errorf("reading file: %w", err)
+		}
+		req.FileName = files[0].Filename
+	}
+	return req, nil
+}
+
+// Request is a request received by Server.
+type Request struct {
+	Method string
+	Path   string // Relative to the server URL, e.g. PathChatCompletions
+	Header http.Header
+	Body   []byte // The body of JSON requests; nil for uploads
+
+	Form     map[string]string // The fields of uploads, such as "model" and "language"
+	File     []byte            // The uploaded file
+	FileName string            // The name of the uploaded file
+}
+
+// Chat decodes the body of a chat completion request.
+func (r *Request) Chat() (*wire.ChatCompletionRequest, error) {
+	var chat wire.ChatCompletionRequest
+	if err := json.Unmarshal(r.Body, &chat); err != nil {
+		return nil, fmt.Errorf("decoding chat request: %w", err)
+	}
+	return &chat, nil
+}
+
+// Speech decodes the body of a speech request.
+func (r *Request) Speech() (*wire.SpeechRequest, error) {
+	var speech wire.SpeechRequest
+	if err := json.Unmarshal(r.Body, &speech); err != nil {
+		return nil, fmt.Errorf("decoding speech request: %w", err)
+	}
+	return &speech, nil
+}
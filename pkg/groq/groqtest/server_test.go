@@ -0,0 +1,142 @@
+package groqtest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/genc-murat/groq-client/pkg/groq"
+)
+
+func TestServerChat(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	client := srv.Client(groq.WithRetryConfig(1, time.Millisecond))
+	defer client.Close()
+	ctx := context.Background()
+
+	resp, err := client.CreateChatCompletion(ctx, groq.NewRequest(groq.ModelLlama31_8bInstant).User("echo me").Build())
+	if err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if got := resp.Choices[0].Message.Content; got != "echo me" {
+		t.Errorf("default reply = %v, want the user message", got)
+	}
+
+	srv.Enqueue(PathChatCompletions, ErrorReply(http.StatusTooManyRequests, "slow down"), ChatReply("scripted"))
+	resp, err = client.CreateChatCompletion(ctx, groq.NewRequest(groq.ModelLlama31_8bInstant).User("hi").Build())
+	if err != nil {
+		t.Fatalf("CreateChatCompletion after a 429: %v", err)
+	}
+	if got := resp.Choices[0].Message.Content; got != "scripted" {
+		t.Errorf("reply = %v, want the scripted reply", got)
+	}
+	if n := len(srv.Requests()); n != 3 {
+		t.Errorf("server saw %d requests, want 3", n)
+	}
+
+	srv.Enqueue(PathChatCompletions, ErrorReply(http.StatusUnauthorized, "bad key"))
+	_, err = client.CreateChatCompletion(ctx, groq.NewRequest(groq.ModelLlama31_8bInstant).User("hi").Build())
+	if !errors.Is(err, groq.ErrAuthentication) {
+		t.Errorf("expected ErrAuthentication, got %v", err)
+	}
+
+	chat, err := srv.LastRequest().Chat()
+	if err != nil || chat.Model != string(groq.ModelLlama31_8bInstant) {
+		t.Errorf("recorded request = %+v, %v", chat, err)
+	}
+}
+
+func TestServerStream(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	defer client.Close()
+
+	collect := func() string {
+		var text strings.Builder
+		err := client.CreateChatCompletionStream(context.Background(), groq.NewRequest(groq.ModelLlama31_8bInstant).User("one two three").Build(),
+			func(chunk *groq.ChatCompletionChunk) error {
+				if len(chunk.Choices) > 0 {
+					text.WriteString(chunk.Choices[0].Delta.Content)
+				}
+				return nil
+			})
+		if err != nil {
+			t.Fatalf("CreateChatCompletionStream: %v", err)
+		}
+		return text.String()
+	}
+
+	if got := collect(); got != "one two three" {
+		t.Errorf("default stream = %q", got)
+	}
+	reply := StreamReply("Hel", "lo")
+	reply.EventDelay = 10 * time.Millisecond
+	srv.Enqueue(PathChatCompletions, reply)
+	if got := collect(); got != "Hello" {
+		t.Errorf("scripted stream = %q", got)
+	}
+}
+
+func TestServerAudio(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	defer client.Close()
+	ctx := context.Background()
+
+	resp, err := client.CreateTranscription(ctx, &groq.TranscriptionRequest{
+		File:     bytes.NewReader(SilentWAV()),
+		FileName: "question.wav",
+		Model:    groq.ModelWhisperLargeV3Turbo,
+		Language: "en",
+	})
+	if err != nil {
+		t.Fatalf("CreateTranscription: %v", err)
+	}
+	if resp.Text != DefaultTranscript {
+		t.Errorf("transcript = %q", resp.Text)
+	}
+	req := srv.LastRequest()
+	if req.FileName != "question.wav" || !bytes.Equal(req.File, SilentWAV()) || req.Form["language"] != "en" {
+		t.Errorf("recorded upload = %q, %d bytes, form %v", req.FileName, len(req.File), req.Form)
+	}
+
+	audio, err := client.CreateSpeech(ctx, &groq.SpeechRequest{Model: groq.ModelPlayAITTS, Input: "hi", Voice: groq.VoiceFritz})
+	if err != nil {
+		t.Fatalf("CreateSpeech: %v", err)
+	}
+	if !bytes.Equal(audio, SilentWAV()) {
+		t.Errorf("speech = %d bytes, want the silent WAV", len(audio))
+	}
+}
+
+func TestServerLatencyAndHandler(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	client := srv.Client(groq.WithRetryConfig(0, time.Millisecond))
+	defer client.Close()
+	req := groq.NewRequest(groq.ModelLlama31_8bInstant).User("hi").Build()
+
+	srv.SetLatency(200 * time.Millisecond)
+	if _, err := client.CreateChatCompletion(context.Background(), req, groq.WithRequestTimeout(20*time.Millisecond)); err == nil {
+		t.Error("expected the slow reply to time out")
+	}
+	srv.SetLatency(0)
+
+	srv.HandleFunc(PathChatCompletions, func(r *Request) Reply {
+		return ChatReply("handled " + r.Header.Get("X-Tenant"))
+	})
+	resp, err := client.CreateChatCompletion(context.Background(), req, groq.WithRequestHeader("X-Tenant", "acme"))
+	if err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if got := resp.Choices[0].Message.Content; got != "handled acme" {
+		t.Errorf("reply = %v, want the handler's", got)
+	}
+}
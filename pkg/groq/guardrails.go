@@ -0,0 +1,246 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type GuardrailStage string
+
+const (
+	GuardrailInput  GuardrailStage = "input"
+	GuardrailOutput GuardrailStage = "output"
+)
+
+var ErrPolicyViolation = errors.New("policy violation")
+
+type PolicyViolationError struct {
+	Stage    GuardrailStage
+	Checker  string
+	Category string
+	Reason   string
+}
+
+// Error returns a formatted string describing the violation.
+func (e *PolicyViolationError) Error() string {
+	msg := fmt.Sprintf("%s: %s blocked by %s", ErrPolicyViolation, e.Stage, e.Checker)
+	if e.Category != "" {
+		msg += fmt.Sprintf(" (category: %s)", e.Category)
+	}
+	if e.Reason != "" {
+		msg += ": " + e.Reason
+	}
+	return msg
+}
+
+// Unwrap allows errors.Is(err, ErrPolicyViolation) to match any PolicyViolationError.
+func (e *PolicyViolationError) Unwrap() error {
+	return ErrPolicyViolation
+}
+
+// GuardrailChecker screens a conversation before it is sent (GuardrailInput) or after the
+// model answered (GuardrailOutput, with the reply appended as the last message).
+// A checker returns a *PolicyViolationError to block the exchange; any other error is
+// treated as a checker failure and also aborts the request.
+type GuardrailChecker interface {
+	Check(ctx context.Context, stage GuardrailStage, messages []ChatMessage) error
+}
+
+// GuardrailCheckerFunc adapts an ordinary function to the GuardrailChecker interface.
+type GuardrailCheckerFunc func(ctx context.Context, stage GuardrailStage, messages []ChatMessage) error
+
+// Check calls f(ctx, stage, messages).
+func (f GuardrailCheckerFunc) Check(ctx context.Context, stage GuardrailStage, messages []ChatMessage) error {
+	return f(ctx, stage, messages)
+}
+
+type Guardrails struct {
+	Input  []GuardrailChecker
+	Output []GuardrailChecker
+}
+
+// WithGuardrails installs a safety pipeline on the client. Input checkers run on every
+// chat completion request before the cache and the API are consulted; output checkers
+// run on every completion before it is cached and returned. When output checkers are
+// configured, streamed chunks are held back until the whole answer has passed them.
+//
+// Example usage:
+//
+//	deny, _ := NewRegexDenyList("secrets", `(?i)api[_-]?key`)
+//	client := NewClient(apiKey, WithGuardrails(&Guardrails{
+//	    Input:  []GuardrailChecker{deny, NewLlamaGuard(guardClient)},
+//	    Output: []GuardrailChecker{deny},
+//	}))
+func WithGuardrails(g *Guardrails) Option {
+	return func(c *Client) {
+		c.guardrails = g
+	}
+}
+
+// checkGuardrails runs the checkers of a stage in order and returns the first error.
+// Checkers of a safety profile run after the configured guardrails.
+func (c *Client) checkGuardrails(ctx context.Context, stage GuardrailStage, messages []ChatMessage) error {
+	for _, checker := range c.guardrailCheckers(stage) {
+		if err := checker.Check(ctx, stage, messages); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// guardrailCheckers returns the checkers of a stage, those of a safety profile last.
+func (c *Client) guardrailCheckers(stage GuardrailStage) []GuardrailChecker {
+	var checkers []GuardrailChecker
+	if c.guardrails != nil {
+		checkers = c.guardrails.Input
+		if stage == GuardrailOutput {
+			checkers = c.guardrails.Output
+		}
+	}
+	if c.safety != nil {
+		checkers = append(checkers[:len(checkers):len(checkers)], c.safety.checkers(stage)...)
+	}
+	return checkers
+}
+
+type RegexDenyList struct {
+	name     string
+	patterns []*regexp.Regexp
+}
+
+// NewRegexDenyList creates a GuardrailChecker that blocks the last message of the
+// conversation when it matches any of the given regular expressions.
+//
+// Parameters:
+//   - name: Name reported in PolicyViolationError.Checker.
+//   - patterns: Regular expressions in RE2 syntax.
+//
+// Returns:
+//   - *RegexDenyList: The checker.
+//   - error: An error if a pattern does not compile.
+func NewRegexDenyList(name string, patterns ...string) (*RegexDenyList, error) {
+	d := &RegexDenyList{name: name}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid deny-list pattern %q: %w", p, err)
+		}
+		d.patterns = append(d.patterns, re)
+	}
+	return d, nil
+}
+
+// Check implements GuardrailChecker.
+func (d *RegexDenyList) Check(ctx context.Context, stage GuardrailStage, messages []ChatMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	text := messages[len(messages)-1].GetCacheKey()
+	for _, re := range d.patterns {
+		if re.MatchString(text) {
+			return &PolicyViolationError{
+				Stage:    stage,
+				Checker:  d.name,
+				Category: "deny-list",
+				Reason:   fmt.Sprintf("matched pattern %q", re.String()),
+			}
+		}
+	}
+	return nil
+}
+
+// llamaGuardCategories maps Llama Guard hazard codes to readable names.
+var llamaGuardCategories = map[string]string{
+	"S1":  "violent crimes",
+	"S2":  "non-violent crimes",
+	"S3":  "sex-related crimes",
+	"S4":  "child sexual exploitation",
+	"S5":  "defamation",
+	"S6":  "specialized advice",
+	"S7":  "privacy",
+	"S8":  "intellectual property",
+	"S9":  "indiscriminate weapons",
+	"S10": "hate",
+	"S11": "suicide and self-harm",
+	"S12": "sexual content",
+	"S13": "elections",
+	"S14": "code interpreter abuse",
+}
+
+type LlamaGuard struct {
+	client *Client
+	Model  ModelType
+}
+
+// NewLlamaGuard creates a GuardrailChecker that classifies conversations with Llama Guard.
+// The classification request bypasses the client's own guardrails and cache, so the same
+// client can be used for both guarding and answering.
+//
+// Parameters:
+//   - client: The client used to call the Llama Guard model.
+//
+// Returns:
+//   - *LlamaGuard: The checker, using ModelLlamaGuard4_12b by default.
+func NewLlamaGuard(client *Client) *LlamaGuard {
+	return &LlamaGuard{client: client, Model: ModelLlamaGuard4_12b}
+}
+
+// Check implements GuardrailChecker. Llama Guard answers "safe" or "unsafe" followed by
+// the violated hazard codes; unsafe verdicts become a PolicyViolationError. The check
+// fails closed: an empty answer or any other verdict is returned as an error, which aborts
+// the request like a violation.
+func (g *LlamaGuard) Check(ctx context.Context, stage GuardrailStage, messages []ChatMessage) error {
+	filtered := make([]ChatMessage, 0, len(messages))
+	for _, msg := range messages {
+		if msg.Role == "user" || msg.Role == "assistant" {
+			filtered = append(filtered, ChatMessage{Role: msg.Role, Content: msg.GetCacheKey()})
+		}
+	}
+	if len(filtered) == 0 {
+		return nil
+	}
+
+	resp, err := g.client.doChatCompletion(ctx, &ChatCompletionRequest{
+		Model:    g.Model,
+		Messages: filtered,
+	})
+	if err != nil {
+		return fmt.Errorf("llama guard check failed: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return fmt.Errorf("llama guard check failed: no choices returned")
+	}
+
+	verdict := strings.Fields(resp.Choices[0].Message.GetCacheKey())
+	switch {
+	case len(verdict) == 0:
+		return fmt.Errorf("llama guard check failed: empty verdict")
+	case strings.EqualFold(verdict[0], "safe"):
+		return nil
+	case !strings.EqualFold(verdict[0], "unsafe"):
+		return fmt.Errorf("llama guard check failed: unexpected verdict %q", verdict[0])
+	}
+
+	var categories []string
+	for _, code := range verdict[1:] {
+		for _, c := range strings.Split(code, ",") {
+			c = strings.TrimSpace(c)
+			if name, ok := llamaGuardCategories[c]; ok {
+				categories = append(categories, c+" "+name)
+			} else if c != "" {
+				categories = append(categories, c)
+			}
+		}
+	}
+
+	return &PolicyViolationError{
+		Stage:    stage,
+		Checker:  "llama-guard",
+		Category: strings.Join(categories, ", "),
+		Reason:   "classified as unsafe",
+	}
+}
@@ -0,0 +1,74 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGuardrailsRegexDenyList(t *testing.T) {
+	srv := newTestServer(t, func(req *ChatCompletionRequest) string {
+		return "the password is hunter2"
+	})
+
+	deny, err := NewRegexDenyList("secrets", `(?i)password`)
+	if err != nil {
+		t.Fatalf("NewRegexDenyList() error = %v", err)
+	}
+	client := NewClient("test-key", WithBaseURL(srv.URL), WithGuardrails(&Guardrails{
+		Input:  []GuardrailChecker{deny},
+		Output: []GuardrailChecker{deny},
+	}))
+
+	_, err = client.CreateChatCompletion(context.Background(), NewRequest(ModelLlama31_8bInstant).User("my PASSWORD?").Build())
+	var violation *PolicyViolationError
+	if !errors.As(err, &violation) || violation.Stage != GuardrailInput {
+		t.Fatalf("expected input violation, got %v", err)
+	}
+
+	_, err = client.CreateChatCompletion(context.Background(), NewRequest(ModelLlama31_8bInstant).User("hello").Build())
+	if !errors.As(err, &violation) || violation.Stage != GuardrailOutput {
+		t.Fatalf("expected output violation, got %v", err)
+	}
+	if !errors.Is(err, ErrPolicyViolation) {
+		t.Error("expected errors.Is(err, ErrPolicyViolation)")
+	}
+}
+
+func TestLlamaGuard(t *testing.T) {
+	srv := newTestServer(t, func(req *ChatCompletionRequest) string {
+		if req.Model == ModelLlamaGuard4_12b {
+			switch req.Messages[len(req.Messages)-1].Content {
+			case "bad":
+				return "unsafe\nS1"
+			case "empty":
+				return " "
+			case "garbled":
+				return "I cannot classify this."
+			}
+			return "safe"
+		}
+		return "answer"
+	})
+	client := NewClient("test-key", WithBaseURL(srv.URL))
+	client = NewClient("test-key", WithBaseURL(srv.URL), WithGuardrails(&Guardrails{
+		Input: []GuardrailChecker{NewLlamaGuard(client)},
+	}))
+
+	if _, err := client.CreateChatCompletion(context.Background(), NewRequest(ModelLlama31_8bInstant).User("good").Build()); err != nil {
+		t.Fatalf("unexpected error for safe input: %v", err)
+	}
+
+	_, err := client.CreateChatCompletion(context.Background(), NewRequest(ModelLlama31_8bInstant).User("bad").Build())
+	var violation *PolicyViolationError
+	if !errors.As(err, &violation) || violation.Category != "S1 violent crimes" {
+		t.Fatalf("expected llama guard violation, got %v", err)
+	}
+
+	for _, input := range []string{"empty", "garbled"} {
+		_, err := client.CreateChatCompletion(context.Background(), NewRequest(ModelLlama31_8bInstant).User(input).Build())
+		if err == nil || errors.As(err, &violation) {
+			t.Errorf("expected a checker failure for an %s verdict, got %v", input, err)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package groq
+
+import (
+	"context"
+	"time"
+)
+
+// RequestEvent describes a call that is about to start.
+type RequestEvent struct {
+	Endpoint    Endpoint
+	Model       ModelType
+	Annotations map[string]string // See ContextWithAnnotations
+	Start       time.Time
+}
+
+// CallEvent describes a finished call.
+type CallEvent struct {
+	CallMetrics
+	RequestID       string // Server-side ID of the last response, if any
+	ClientRequestID string // X-Request-ID sent with the call, if any
+	RequestBytes    int    // Size of the last request body sent
+	ResponseBytes   int    // Size of the last response body received
+	Err             error  // The error the call returned; nil for OnResponse
+}
+
+// RetryEvent describes a retry that is about to start.
+type RetryEvent struct {
+	Endpoint Endpoint
+	Model    ModelType
+	Attempt  int           // 1 for the first retry, 2 for the second, ...
+	Reason   error         // The error of the previous attempt
+	Delay    time.Duration // The wait before the retry
+}
+
+// CacheHitEvent describes a chat call answered from the cache.
+type CacheHitEvent struct {
+	Model ModelType
+	Key   string // The cache key, including the namespace
+	Stale bool   // The entry is past its TTL and is being revalidated
+}
+
+// Hooks are called at the stages of every call of the client, for telemetry that the
+// built-in logging (WithSlog) and metrics (WithMetrics) do not cover. Every hook is
+// optional, receives the context of the call and runs synchronously on the calling
+// goroutine, so it must return quickly and must not call the client.
+type Hooks struct {
+	// OnRequest is called when a call starts, before rate limits are waited for.
+	OnRequest func(ctx context.Context, e RequestEvent)
+	// OnResponse is called when a call succeeds, including calls answered from the cache.
+	OnResponse func(ctx context.Context, e CallEvent)
+	// OnError is called when a call fails.
+	OnError func(ctx context.Context, e CallEvent)
+	// OnRetry is called before every retry of a call.
+	OnRetry func(ctx context.Context, e RetryEvent)
+	// OnCacheHit is called when a chat call is answered from the cache.
+	OnCacheHit func(ctx context.Context, e CacheHitEvent)
+}
+
+// WithHooks installs lifecycle hooks on the client, replacing hooks installed before.
+//
+// Example usage:
+//
+//	client := NewClient(apiKey, WithHooks(Hooks{
+//	    OnRetry: func(ctx context.Context, e RetryEvent) {
+//	        log.Printf("retrying %s in %v: %v", e.Endpoint, e.Delay, e.Reason)
+//	    },
+//	}))
+//
+// Parameters:
+//   - hooks: The hooks; nil fields are skipped.
+//
+// Returns:
+//   - Option: A function that installs the hooks.
+func WithHooks(hooks Hooks) Option {
+	return func(c *Client) {
+		c.hooks = &hooks
+	}
+}
+
+// callEvent returns the event of a finished call.
+func (t *callTrace) callEvent(latency time.Duration, err error) CallEvent {
+	e := CallEvent{CallMetrics: t.metrics(latency, err), Err: err}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e.RequestID = t.requestID
+	e.ClientRequestID = t.clientID
+	e.RequestBytes = t.requestBytes
+	e.ResponseBytes = t.responseBytes
+	return e
+}
+
+// observeRetry is the retry notification hook of the HTTP client.
+func (c *Client) observeRetry(ctx context.Context, attempt int, err error, delay time.Duration) {
+	trace := traceFrom(ctx)
+	if trace == nil || c.hooks == nil || c.hooks.OnRetry == nil {
+		return
+	}
+	c.hooks.OnRetry(ctx, RetryEvent{
+		Endpoint: trace.endpoint,
+		Model:    trace.model,
+		Attempt:  attempt,
+		Reason:   err,
+		Delay:    delay,
+	})
+}
+
+// recordCacheOutcome records the cache outcome of a chat call and reports hits to the
+// OnCacheHit hook.
+func (c *Client) recordCacheOutcome(ctx context.Context, outcome, key string) {
+	trace := traceFrom(ctx)
+	trace.setCache(outcome)
+	if trace == nil || outcome == cacheOutcomeMiss || c.hooks == nil || c.hooks.OnCacheHit == nil {
+		return
+	}
+	c.hooks.OnCacheHit(ctx, CacheHitEvent{
+		Model: trace.model,
+		Key:   key,
+		Stale: outcome == cacheOutcomeStale,
+	})
+}
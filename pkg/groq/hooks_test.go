@@ -0,0 +1,104 @@
+package groq
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithHooks(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("x-request-id", "req_7")
+		_, _ = w.Write([]byte(`{"id":"1","choices":[{"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}],` +
+			`"usage":{"prompt_tokens":7,"completion_tokens":3,"total_tokens":10}}`))
+	}))
+	defer srv.Close()
+
+	var (
+		requests  []RequestEvent
+		responses []CallEvent
+		retries   []RetryEvent
+		hits      []CacheHitEvent
+	)
+	client := NewClient("test-key",
+		WithBaseURL(srv.URL),
+		WithRetryConfig(2, time.Millisecond),
+		WithCache(newMapCache()),
+		WithHooks(Hooks{
+			OnRequest:  func(ctx context.Context, e RequestEvent) { requests = append(requests, e) },
+			OnResponse: func(ctx context.Context, e CallEvent) { responses = append(responses, e) },
+			OnError:    func(ctx context.Context, e CallEvent) { t.Errorf("unexpected OnError: %v", e.Err) },
+			OnRetry:    func(ctx context.Context, e RetryEvent) { retries = append(retries, e) },
+			OnCacheHit: func(ctx context.Context, e CacheHitEvent) { hits = append(hits, e) },
+		}),
+	)
+	defer client.Close()
+
+	req := &ChatCompletionRequest{
+		Model:    ModelLlama31_8bInstant,
+		Messages: []ChatMessage{{Role: "user", Content: "hello"}},
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := client.CreateChatCompletion(context.Background(), req); err != nil {
+			t.Fatalf("CreateChatCompletion: %v", err)
+		}
+	}
+
+	if len(requests) != 2 || requests[0].Endpoint != EndpointChat || requests[0].Model != ModelLlama31_8bInstant {
+		t.Errorf("unexpected request events: %+v", requests)
+	}
+	if len(retries) != 1 || retries[0].Attempt != 1 || retries[0].Reason == nil || retries[0].Delay != time.Millisecond {
+		t.Errorf("unexpected retry events: %+v", retries)
+	}
+	if len(hits) != 1 || hits[0].Stale || hits[0].Model != ModelLlama31_8bInstant {
+		t.Errorf("unexpected cache hit events: %+v", hits)
+	}
+
+	if len(responses) != 2 {
+		t.Fatalf("expected two response events, got %d", len(responses))
+	}
+	first := responses[0]
+	if first.Status != http.StatusOK || first.Retries != 1 || first.RequestID != "req_7" || first.Cache != cacheOutcomeMiss {
+		t.Errorf("unexpected first response event: %+v", first)
+	}
+	if first.RequestBytes == 0 || first.ResponseBytes == 0 || first.PromptTokens != 7 {
+		t.Errorf("expected payload sizes and usage: %+v", first)
+	}
+	if responses[1].Cache != cacheOutcomeHit {
+		t.Errorf("expected the second call to hit the cache: %+v", responses[1])
+	}
+}
+
+func TestWithHooksError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":{"message":"bad","type":"invalid_request_error"}}`))
+	}))
+	defer srv.Close()
+
+	var failed []CallEvent
+	client := NewClient("test-key", WithBaseURL(srv.URL), WithHooks(Hooks{
+		OnError: func(ctx context.Context, e CallEvent) { failed = append(failed, e) },
+	}))
+	defer client.Close()
+
+	_, err := client.CreateChatCompletion(context.Background(), &ChatCompletionRequest{
+		Model:    ModelLlama31_8bInstant,
+		Messages: []ChatMessage{{Role: "user", Content: "hello"}},
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(failed) != 1 || failed[0].Err != err || !failed[0].Failed || failed[0].Status != http.StatusBadRequest {
+		t.Errorf("unexpected error events: %+v", failed)
+	}
+}
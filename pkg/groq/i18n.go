@@ -0,0 +1,189 @@
+package groq
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+type Locale string
+
+const (
+	LocaleEnglish Locale = "en"
+	LocaleTurkish Locale = "tr"
+)
+
+// MessageID identifies a localizable message. It is the English fmt format string, so
+// a message without a translation falls back to English.
+type MessageID string
+
+const (
+	MsgInvalidRequest         MessageID = "invalid request"
+	MsgInvalidModel           MessageID = "invalid model: %s"
+	MsgMessagesRequired       MessageID = "at least one message is required"
+	MsgMaxTokensExceeded      MessageID = "max_tokens exceeds model limit of %d"
+	MsgTemperatureRange       MessageID = "temperature must be between 0 and 2"
+	MsgTopPRange              MessageID = "top_p must be between 0 and 1"
+	MsgVisionUnsupported      MessageID = "model %s does not support vision features"
+	MsgImageDetail            MessageID = "invalid image detail %q: must be auto, low or high"
+	MsgAudioSourceRequired    MessageID = "File or URL is required"
+	MsgAudioSourceConflict    MessageID = "set either File or URL, not both"
+	MsgInvalidAudioFormat     MessageID = "invalid audio format: %s. Supported formats: flac, mp3, mp4, mpeg, mpga, m4a, ogg, wav, webm"
+	MsgUnsupportedAudioFormat MessageID = "unsupported audio format"
+	MsgUnsupportedAudioCodec  MessageID = "%s is %s audio. Supported formats: flac, mp3, mp4, mpeg, mpga, m4a, ogg, wav, webm"
+	MsgGranularityFormat      MessageID = "timestamp granularities require response format verbose_json, got %q"
+	MsgInvalidGranularity     MessageID = "invalid timestamp granularity %q: must be %q or %q"
+	MsgSpeechUnsupported      MessageID = "model %s does not support speech synthesis"
+	MsgVoiceUnavailable       MessageID = "voice %q is not available for %s; supported voices: %s"
+	MsgSpeechInputRequired    MessageID = "input is required"
+	MsgSpeechInputTooLong     MessageID = "input is %d characters, limit is %d"
+	MsgSpeechFormat           MessageID = "unsupported response format: %s"
+	MsgSpeechSpeed            MessageID = "speed must be between 0.5 and 5"
+)
+
+var (
+	catalogs = map[Locale]map[MessageID]string{
+		LocaleTurkish: {
+			MsgInvalidRequest:         "geçersiz istek",
+			MsgInvalidModel:           "geçersiz model: %s",
+			MsgMessagesRequired:       "en az bir mesaj gereklidir",
+			MsgMaxTokensExceeded:      "max_tokens, modelin %d olan sınırını aşıyor",
+			MsgTemperatureRange:       "temperature 0 ile 2 arasında olmalıdır",
+			MsgTopPRange:              "top_p 0 ile 1 arasında olmalıdır",
+			MsgVisionUnsupported:      "%s modeli görsel özellikleri desteklemiyor",
+			MsgImageDetail:            "geçersiz görsel ayrıntı düzeyi %q: auto, low veya high olmalıdır",
+			MsgAudioSourceRequired:    "File veya URL gereklidir",
+			MsgAudioSourceConflict:    "File ve URL alanlarından yalnızca birini belirtin",
+			MsgInvalidAudioFormat:     "geçersiz ses biçimi: %s. Desteklenen biçimler: flac, mp3, mp4, mpeg, mpga, m4a, ogg, wav, webm",
+			MsgUnsupportedAudioFormat: "desteklenmeyen ses biçimi",
+			MsgUnsupportedAudioCodec:  "%s bir %s ses dosyası. Desteklenen biçimler: flac, mp3, mp4, mpeg, mpga, m4a, ogg, wav, webm",
+			MsgGranularityFormat:      "zaman damgası ayrıntı düzeyleri verbose_json yanıt biçimini gerektirir, verilen: %q",
+			MsgInvalidGranularity:     "geçersiz zaman damgası ayrıntı düzeyi %q: %q veya %q olmalıdır",
+			MsgSpeechUnsupported:      "%s modeli konuşma sentezini desteklemiyor",
+			MsgVoiceUnavailable:       "%[1]q sesi %[2]s için kullanılamıyor; desteklenen sesler: %[3]s",
+			MsgSpeechInputRequired:    "girdi gereklidir",
+			MsgSpeechInputTooLong:     "girdi %d karakter, sınır %d",
+			MsgSpeechFormat:           "desteklenmeyen yanıt biçimi: %s",
+			MsgSpeechSpeed:            "hız 0.5 ile 5 arasında olmalıdır",
+		},
+	}
+	catalogsMu sync.RWMutex
+)
+
+// RegisterMessages adds or replaces translations for a locale, so applications can
+// support more languages or reword the built-in messages. Messages are fmt format strings
+// taking the same arguments as the English text; use explicit argument indexes such as
+// %[2]s when a language needs a different order.
+//
+// Example usage:
+//
+//	groq.RegisterMessages("de", map[groq.MessageID]string{
+//	    groq.MsgMessagesRequired: "mindestens eine Nachricht ist erforderlich",
+//	})
+func RegisterMessages(locale Locale, messages map[MessageID]string) {
+	catalogsMu.Lock()
+	defer catalogsMu.Unlock()
+
+	catalog := catalogs[locale]
+	if catalog == nil {
+		catalog = make(map[MessageID]string, len(messages))
+		catalogs[locale] = catalog
+	}
+	for id, text := range messages {
+		catalog[id] = text
+	}
+}
+
+// Localize formats the message id in locale. A regional locale such as "tr-TR" falls
+// back to its language ("tr"), and missing translations fall back to English.
+//
+// Parameters:
+//   - locale: The target locale; empty means English.
+//   - id: The message to format.
+//   - args: The format arguments.
+//
+// Returns:
+//   - string: The formatted message.
+func Localize(locale Locale, id MessageID, args ...interface{}) string {
+	format := string(id)
+
+	catalogsMu.RLock()
+	for _, l := range []Locale{locale, baseLocale(locale)} {
+		if text, ok := catalogs[l][id]; ok {
+			format = text
+			break
+		}
+	}
+	catalogsMu.RUnlock()
+
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// baseLocale strips the region from a locale, e.g. "tr-TR" becomes "tr".
+func baseLocale(locale Locale) Locale {
+	if i := strings.IndexAny(string(locale), "-_"); i > 0 {
+		return locale[:i]
+	}
+	return locale
+}
+
+// WithLocale makes the client report validation and helper errors in locale, e.g.
+// LocaleTurkish. Errors still match the same sentinels with errors.Is.
+func WithLocale(locale Locale) Option {
+	return func(c *Client) {
+		c.locale = locale
+	}
+}
+
+type LocalizedError struct {
+	Err    error // Sentinel the error wraps, e.g. ErrInvalidRequest; may be nil
+	ID     MessageID
+	Args   []interface{}
+	Locale Locale // Locale Error renders in; empty means English
+}
+
+// newLocalizedError creates a *LocalizedError rendered in English.
+func newLocalizedError(sentinel error, id MessageID, args ...interface{}) *LocalizedError {
+	return &LocalizedError{Err: sentinel, ID: id, Args: args}
+}
+
+// Error returns the message in the error's locale, prefixed by the wrapped sentinel.
+func (e *LocalizedError) Error() string {
+	return e.Message(e.Locale)
+}
+
+// Message returns the message in locale, prefixed by the wrapped sentinel.
+func (e *LocalizedError) Message(locale Locale) string {
+	msg := Localize(locale, e.ID, e.Args...)
+	if e.Err != nil {
+		return Localize(locale, MessageID(e.Err.Error())) + ": " + msg
+	}
+	return msg
+}
+
+// Unwrap returns the wrapped sentinel.
+func (e *LocalizedError) Unwrap() error {
+	return e.Err
+}
+
+// localize renders err in the client's locale if it is a *LocalizedError.
+func (c *Client) localize(err error) error {
+	le, ok := err.(*LocalizedError)
+	if !ok || c.locale == "" {
+		return err
+	}
+	localized := *le
+	localized.Locale = c.locale
+	return &localized
+}
+
+// invalidRequest wraps a validation error in ErrInvalidRequest, localized if possible.
+func (c *Client) invalidRequest(err error) error {
+	if le, ok := err.(*LocalizedError); ok && le.Err == nil {
+		return &LocalizedError{Err: ErrInvalidRequest, ID: le.ID, Args: le.Args, Locale: c.locale}
+	}
+	return fmt.Errorf("%w: %v", ErrInvalidRequest, c.localize(err))
+}
@@ -0,0 +1,49 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLocalizedValidationErrors(t *testing.T) {
+	req := &ChatCompletionRequest{Model: ModelLlama31_8bInstant}
+
+	_, err := NewClient("test-key").CreateChatCompletion(context.Background(), req)
+	if err == nil || err.Error() != "invalid request: at least one message is required" {
+		t.Errorf("unexpected English error: %v", err)
+	}
+
+	_, err = NewClient("test-key", WithLocale(LocaleTurkish)).CreateChatCompletion(context.Background(), req)
+	if !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest, got %v", err)
+	}
+	if err.Error() != "geçersiz istek: en az bir mesaj gereklidir" {
+		t.Errorf("unexpected Turkish error: %v", err)
+	}
+
+	speech := &SpeechRequest{Model: ModelPlayAITTS, Voice: "Nobody", Input: "hi"}
+	_, err = NewClient("test-key", WithLocale("tr-TR")).CreateSpeech(context.Background(), speech)
+	if err == nil || !strings.HasPrefix(err.Error(), `geçersiz istek: "Nobody" sesi playai-tts için kullanılamıyor`) {
+		t.Errorf("unexpected regional Turkish error: %v", err)
+	}
+}
+
+func TestRegisterMessages(t *testing.T) {
+	RegisterMessages("de", map[MessageID]string{
+		MsgMessagesRequired: "mindestens eine Nachricht ist erforderlich",
+	})
+
+	if got := Localize("de", MsgMessagesRequired); got != "mindestens eine Nachricht ist erforderlich" {
+		t.Errorf("unexpected registered message: %q", got)
+	}
+	if got := Localize("de", MsgInvalidModel, "x"); got != "invalid model: x" {
+		t.Errorf("expected English fallback, got %q", got)
+	}
+
+	err := newLocalizedError(ErrInvalidRequest, MsgMessagesRequired)
+	if got := err.Message("de"); got != "invalid request: mindestens eine Nachricht ist erforderlich" {
+		t.Errorf("unexpected message: %q", got)
+	}
+}
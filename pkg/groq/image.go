@@ -0,0 +1,172 @@
+package groq
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+var (
+	ErrUnsupportedImageFormat = errors.New("unsupported image format")
+	ErrImageTooLarge          = errors.New("image too large")
+)
+
+// DetectImageType returns the MIME type of an image from its leading bytes, or "" if
+// the data is not a JPEG, PNG, GIF or WebP image.
+//
+// Parameters:
+//   - head: The first bytes of the image; 512 bytes are enough.
+//
+// Returns:
+//   - string: The MIME type, e.g. "image/png".
+func DetectImageType(head []byte) string {
+	contentType := http.DetectContentType(head)
+	if isValidImageType(contentType) {
+		return contentType
+	}
+	return ""
+}
+
+// ImageFromReader reads an image and returns it as a base64 data URI for
+// NewImageURLContent, with the MIME type detected from the image bytes rather than
+// assumed.
+//
+// Parameters:
+//   - reader: The image data.
+//
+// Returns:
+//   - string: A data URI such as "data:image/png;base64,...".
+//   - error: An error if reading fails, the image exceeds MaxBase64ImageSize (matching
+//     ErrImageTooLarge), or the format is not supported (matching ErrUnsupportedImageFormat).
+func ImageFromReader(reader io.Reader) (string, error) {
+	data, err := readImage(reader)
+	if err != nil {
+		return "", err
+	}
+
+	contentType := DetectImageType(data)
+	if contentType == "" {
+		return "", fmt.Errorf("%w: detected %s, supported formats are JPEG, PNG, GIF and WebP", ErrUnsupportedImageFormat, http.DetectContentType(data))
+	}
+	return imageDataURI(contentType, data), nil
+}
+
+// ImageFromFile reads an image file and returns it as a base64 data URI like
+// ImageFromReader. The file extension is ignored; the format is detected from the content.
+//
+// Example usage:
+//
+//	uri, err := groq.ImageFromFile("screenshot.png")
+//	req := groq.CreateVisionRequest(groq.ModelLlama4Scout, uri, "What does this show?")
+//
+// Parameters:
+//   - path: The image file.
+//
+// Returns:
+//   - string: The data URI.
+//   - error: An error if the file cannot be read or the image is too large or unsupported.
+func ImageFromFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("error opening image: %w", err)
+	}
+	defer file.Close()
+
+	return ImageFromReader(file)
+}
+
+type ImageFetchOptions struct {
+	Client       *http.Client       // Client used for the download; nil means http.DefaultClient
+	Header       http.Header        // Headers sent with the request, e.g. Authorization or cookies
+	Preprocessor *ImagePreprocessor // Fits larger images into the limits instead of failing; optional
+}
+
+// FetchImage downloads an image and returns it as a base64 data URI, so images behind
+// authentication, such as private buckets or internal services, can be sent to vision
+// models, which only see public URLs. The image type is detected from the content, not
+// from the Content-Type header.
+//
+// Example usage:
+//
+//	uri, err := groq.FetchImage(ctx, "https://files.internal/scan.png", &groq.ImageFetchOptions{
+//	    Header: http.Header{"Authorization": {"Bearer " + token}},
+//	})
+//	req := groq.CreateVisionRequest(groq.ModelLlama4Scout, uri, "Summarize this scan.")
+//
+// Parameters:
+//   - ctx: Context for the download.
+//   - imageURL: The http or https URL of the image.
+//   - opts: Download options; nil uses the defaults.
+//
+// Returns:
+//   - string: The data URI.
+//   - error: An error if the download fails or returns a non-200 status, or the image is
+//     unsupported (ErrUnsupportedImageFormat) or larger than MaxBase64ImageSize without a
+//     Preprocessor (ErrImageTooLarge).
+func FetchImage(ctx context.Context, imageURL string, opts *ImageFetchOptions) (string, error) {
+	if opts == nil {
+		opts = &ImageFetchOptions{}
+	}
+	if strings.HasPrefix(imageURL, "data:") {
+		// Already inline
+		if err := validateImageDataURI(imageURL); err != nil {
+			return "", err
+		}
+		return imageURL, nil
+	}
+	if err := (OfflineImageValidator{}).ValidateImage(ctx, imageURL); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("error fetching image: %w", err)
+	}
+	for key, values := range opts.Header {
+		req.Header[key] = values
+	}
+
+	client := opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error fetching image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error fetching image: status code %d", resp.StatusCode)
+	}
+
+	if opts.Preprocessor != nil {
+		return opts.Preprocessor.FromReader(resp.Body)
+	}
+	if resp.ContentLength > MaxBase64ImageSize {
+		return "", fmt.Errorf("%w: %d bytes exceeds limit of %d bytes; set a Preprocessor to downscale it", ErrImageTooLarge, resp.ContentLength, MaxBase64ImageSize)
+	}
+	return ImageFromReader(resp.Body)
+}
+
+// readImage reads an image, rejecting images larger than MaxBase64ImageSize.
+func readImage(reader io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(reader, MaxBase64ImageSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("error reading image: %w", err)
+	}
+	if len(data) > MaxBase64ImageSize {
+		return nil, fmt.Errorf("%w: size exceeds limit of %d bytes; use an ImagePreprocessor to downscale it", ErrImageTooLarge, MaxBase64ImageSize)
+	}
+	return data, nil
+}
+
+// imageDataURI encodes image data as a base64 data URI.
+func imageDataURI(contentType string, data []byte) string {
+	return fmt.Sprintf("data:%s;base64,%s", contentType, base64.StdEncoding.EncodeToString(data))
+}
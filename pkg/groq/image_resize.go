@@ -0,0 +1,189 @@
+package groq
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"os"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	// DefaultImageQuality is the JPEG quality ImagePreprocessor starts re-encoding with.
+	DefaultImageQuality = 85
+
+	minImageQuality    = 40
+	minImageDimension  = 64
+	maxSourceImageSize = 64 * 1024 * 1024 // 64MB
+)
+
+// ImagePreprocessor shrinks images to fit the request limits instead of rejecting them.
+// Images already within MaxBytes and MaxDimension are passed through unchanged. Larger
+// images are downscaled and re-encoded: PNG and GIF images are kept lossless when that is
+// small enough, otherwise they are converted to JPEG, with transparency flattened onto
+// white. Animated GIFs are reduced to their first frame.
+type ImagePreprocessor struct {
+	MaxBytes     int // Encoded size limit; 0 means MaxBase64ImageSize
+	MaxDimension int // Limit of the longer side in pixels; 0 means no resolution limit
+	Quality      int // Initial JPEG quality from 1 to 100; 0 means DefaultImageQuality
+}
+
+// Process fits an image into the configured limits.
+//
+// Parameters:
+//   - data: The encoded JPEG, PNG, GIF or WebP image.
+//
+// Returns:
+//   - []byte: The original or re-encoded image.
+//   - string: The MIME type of the returned image.
+//   - error: An error if the format is unsupported (matching ErrUnsupportedImageFormat), the
+//     image cannot be decoded, or it does not fit even at the smallest size and quality
+//     (matching ErrImageTooLarge).
+func (p *ImagePreprocessor) Process(data []byte) ([]byte, string, error) {
+	contentType := DetectImageType(data)
+	if contentType == "" {
+		return nil, "", fmt.Errorf("%w: detected %s, supported formats are JPEG, PNG, GIF and WebP", ErrUnsupportedImageFormat, http.DetectContentType(data))
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("error decoding image: %w", err)
+	}
+	maxBytes := p.maxBytes()
+	if len(data) <= maxBytes && p.withinDimension(config.Width, config.Height) {
+		return data, contentType, nil
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("error decoding image: %w", err)
+	}
+
+	width, height := p.targetSize(config.Width, config.Height)
+	lossless := contentType == "image/png" || contentType == "image/gif"
+	for {
+		scaled := scaleImage(src, width, height, !lossless)
+
+		if lossless {
+			var buf bytes.Buffer
+			if err := png.Encode(&buf, scaled); err != nil {
+				return nil, "", fmt.Errorf("error encoding image: %w", err)
+			}
+			if buf.Len() <= maxBytes {
+				return buf.Bytes(), "image/png", nil
+			}
+			scaled = scaleImage(src, width, height, true)
+		}
+
+		for quality := p.quality(); quality >= minImageQuality; quality -= 15 {
+			var buf bytes.Buffer
+			if err := jpeg.Encode(&buf, scaled, &jpeg.Options{Quality: quality}); err != nil {
+				return nil, "", fmt.Errorf("error encoding image: %w", err)
+			}
+			if buf.Len() <= maxBytes {
+				return buf.Bytes(), "image/jpeg", nil
+			}
+		}
+
+		if width <= minImageDimension || height <= minImageDimension {
+			return nil, "", fmt.Errorf("%w: cannot fit %dx%d image into %d bytes", ErrImageTooLarge, config.Width, config.Height, maxBytes)
+		}
+		width, height = width*3/4, height*3/4
+	}
+}
+
+// FromReader reads an image, fits it into the configured limits and returns it as a base64
+// data URI for NewImageURLContent.
+//
+// Example usage:
+//
+//	pre := &groq.ImagePreprocessor{MaxDimension: 1568}
+//	uri, err := pre.FromReader(resp.Body)
+//
+// Parameters:
+//   - reader: The image data, up to 64MB.
+//
+// Returns:
+//   - string: A data URI such as "data:image/jpeg;base64,...".
+//   - error: An error if reading or processing fails.
+func (p *ImagePreprocessor) FromReader(reader io.Reader) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(reader, maxSourceImageSize+1))
+	if err != nil {
+		return "", fmt.Errorf("error reading image: %w", err)
+	}
+	if len(data) > maxSourceImageSize {
+		return "", fmt.Errorf("%w: source image exceeds %d bytes", ErrImageTooLarge, maxSourceImageSize)
+	}
+
+	data, contentType, err := p.Process(data)
+	if err != nil {
+		return "", err
+	}
+	return imageDataURI(contentType, data), nil
+}
+
+// FromFile reads an image file and returns it as a data URI like FromReader.
+//
+// Parameters:
+//   - path: The image file.
+//
+// Returns:
+//   - string: The data URI.
+//   - error: An error if the file cannot be read or processed.
+func (p *ImagePreprocessor) FromFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("error opening image: %w", err)
+	}
+	defer file.Close()
+
+	return p.FromReader(file)
+}
+
+func (p *ImagePreprocessor) maxBytes() int {
+	if p.MaxBytes > 0 {
+		return p.MaxBytes
+	}
+	return MaxBase64ImageSize
+}
+
+func (p *ImagePreprocessor) quality() int {
+	if p.Quality > 0 && p.Quality <= 100 {
+		return p.Quality
+	}
+	return DefaultImageQuality
+}
+
+func (p *ImagePreprocessor) withinDimension(width, height int) bool {
+	return p.MaxDimension <= 0 || max(width, height) <= p.MaxDimension
+}
+
+// targetSize returns the size of an image scaled so its longer side fits MaxDimension,
+// preserving the aspect ratio.
+func (p *ImagePreprocessor) targetSize(width, height int) (int, int) {
+	if p.withinDimension(width, height) {
+		return width, height
+	}
+	longer := max(width, height)
+	return max(1, width*p.MaxDimension/longer), max(1, height*p.MaxDimension/longer)
+}
+
+// scaleImage resamples src to width x height. If flatten is set, transparent areas are
+// composed onto white, as JPEG has no alpha channel.
+func scaleImage(src image.Image, width, height int, flatten bool) image.Image {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	op := draw.Src
+	if flatten {
+		draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+		op = draw.Over
+	}
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), op, nil)
+	return dst
+}
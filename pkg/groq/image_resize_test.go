@@ -0,0 +1,82 @@
+package groq
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func noiseImage(width, height int) *image.RGBA {
+	rng := rand.New(rand.NewSource(1))
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{uint8(rng.Intn(256)), uint8(rng.Intn(256)), uint8(rng.Intn(256)), 255})
+		}
+	}
+	return img
+}
+
+func TestImagePreprocessorPassThrough(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, noiseImage(16, 16)); err != nil {
+		t.Fatal(err)
+	}
+
+	data, contentType, err := (&ImagePreprocessor{}).Process(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if contentType != "image/png" || !bytes.Equal(data, buf.Bytes()) {
+		t.Errorf("expected a small image to be passed through, got %s of %d bytes", contentType, len(data))
+	}
+}
+
+func TestImagePreprocessorDownscales(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, noiseImage(400, 200)); err != nil {
+		t.Fatal(err)
+	}
+
+	pre := &ImagePreprocessor{MaxBytes: 40 * 1024, MaxDimension: 300}
+	data, contentType, err := pre.Process(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if contentType != "image/jpeg" || len(data) > pre.MaxBytes {
+		t.Fatalf("expected a JPEG within %d bytes, got %s of %d bytes", pre.MaxBytes, contentType, len(data))
+	}
+
+	img, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("result is not a valid JPEG: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() > 300 || b.Dx() != 2*b.Dy() {
+		t.Errorf("expected at most 300px wide with the aspect ratio kept, got %dx%d", b.Dx(), b.Dy())
+	}
+
+	uri, err := pre.FromReader(bytes.NewReader(buf.Bytes()))
+	if err != nil || !strings.HasPrefix(uri, "data:image/jpeg;base64,") {
+		t.Errorf("FromReader() = %.40s, %v", uri, err)
+	}
+}
+
+func TestImagePreprocessorErrors(t *testing.T) {
+	if _, _, err := (&ImagePreprocessor{}).Process([]byte("%PDF-1.7")); !errors.Is(err, ErrUnsupportedImageFormat) {
+		t.Errorf("expected ErrUnsupportedImageFormat, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, noiseImage(200, 200)); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := (&ImagePreprocessor{MaxBytes: 100}).Process(buf.Bytes()); !errors.Is(err, ErrImageTooLarge) {
+		t.Errorf("expected ErrImageTooLarge, got %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package groq
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var (
+	testPNG  = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	testWebP = []byte("RIFF\x24\x00\x00\x00WEBPVP8 ")
+	testGIF  = []byte("GIF89a\x01\x00\x01\x00")
+	testJPEG = []byte("\xff\xd8\xff\xe0\x00\x10JFIF")
+)
+
+func TestImageFromReader(t *testing.T) {
+	tests := map[string][]byte{
+		"image/png":  testPNG,
+		"image/webp": testWebP,
+		"image/gif":  testGIF,
+		"image/jpeg": testJPEG,
+	}
+	for want, data := range tests {
+		uri, err := ImageFromReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("ImageFromReader(%s) error = %v", want, err)
+		}
+		if !strings.HasPrefix(uri, "data:"+want+";base64,") {
+			t.Errorf("ImageFromReader(%s) = %.40s", want, uri)
+		}
+	}
+
+	if _, err := ImageFromReader(strings.NewReader("%PDF-1.7")); !errors.Is(err, ErrUnsupportedImageFormat) {
+		t.Errorf("expected ErrUnsupportedImageFormat, got %v", err)
+	}
+	if _, err := ImageFromReader(bytes.NewReader(make([]byte, MaxBase64ImageSize+1))); err == nil {
+		t.Error("oversized image was accepted")
+	}
+}
+
+func TestImageFromFileIgnoresExtension(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "photo.jpg")
+	if err := os.WriteFile(path, testPNG, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	uri, err := ImageFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(uri, "data:image/png;base64,") {
+		t.Errorf("ImageFromFile() = %.40s, want a PNG data URI", uri)
+	}
+
+	legacy, err := ImageToBase64(bytes.NewReader(testWebP))
+	if err != nil || !strings.HasPrefix(legacy, "data:image/webp;") {
+		t.Errorf("ImageToBase64() = %.40s, %v", legacy, err)
+	}
+}
+
+func TestFetchImage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		switch r.URL.Path {
+		case "/scan.png":
+			_, _ = w.Write(testPNG)
+		case "/doc.pdf":
+			_, _ = w.Write([]byte("%PDF-1.7"))
+		}
+	}))
+	defer srv.Close()
+
+	opts := &ImageFetchOptions{Header: http.Header{"Authorization": {"Bearer token"}}}
+	uri, err := FetchImage(context.Background(), srv.URL+"/scan.png", opts)
+	if err != nil || !strings.HasPrefix(uri, "data:image/png;base64,") {
+		t.Errorf("FetchImage() = %.40s, %v", uri, err)
+	}
+
+	if _, err := FetchImage(context.Background(), srv.URL+"/scan.png", nil); err == nil {
+		t.Error("expected an unauthenticated download to fail")
+	}
+	if _, err := FetchImage(context.Background(), srv.URL+"/doc.pdf", opts); !errors.Is(err, ErrUnsupportedImageFormat) {
+		t.Errorf("expected ErrUnsupportedImageFormat, got %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package groq
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ImageValidator checks the image URLs of vision requests before they are sent.
+type ImageValidator interface {
+	ValidateImage(ctx context.Context, imageURL string) error
+}
+
+// ImageValidatorFunc adapts a function to ImageValidator.
+type ImageValidatorFunc func(ctx context.Context, imageURL string) error
+
+// ValidateImage calls f.
+func (f ImageValidatorFunc) ValidateImage(ctx context.Context, imageURL string) error {
+	return f(ctx, imageURL)
+}
+
+// OfflineImageValidator checks image URLs without network access: http and https URLs
+// must be well-formed with a host, and base64 data URIs must hold a supported image
+// type within MaxBase64ImageSize. Use it for private or pre-signed URLs that a HEAD
+// request cannot reach, or to avoid the extra round trip per image.
+type OfflineImageValidator struct{}
+
+// HTTPImageValidator checks image URLs with a HEAD request, verifying the status code,
+// the size and the content type. Data URIs are checked offline like OfflineImageValidator.
+// This is the default validator of the client.
+type HTTPImageValidator struct {
+	Client *http.Client // Client used for the HEAD requests; nil means http.DefaultClient
+	Header http.Header  // Headers sent with every HEAD request, e.g. Authorization
+}
+
+// defaultImageValidator is used by ChatCompletionRequest.Validate and clients without
+// WithImageValidator.
+var defaultImageValidator ImageValidator = &HTTPImageValidator{}
+
+// WithImageValidator sets how the client checks image URLs in vision requests. The
+// default, HTTPImageValidator, sends a HEAD request per image; OfflineImageValidator
+// only checks the URL syntax.
+//
+// Example usage:
+//
+//	// Private bucket behind a token
+//	client := NewClient(apiKey, WithImageValidator(&HTTPImageValidator{
+//	    Header: http.Header{"Authorization": {"Bearer " + storageToken}},
+//	}))
+//
+//	// Pre-signed URLs, no network checks
+//	client := NewClient(apiKey, WithImageValidator(OfflineImageValidator{}))
+func WithImageValidator(validator ImageValidator) Option {
+	return func(c *Client) {
+		c.images = validator
+	}
+}
+
+// ValidateImage implements ImageValidator.
+func (OfflineImageValidator) ValidateImage(ctx context.Context, imageURL string) error {
+	if strings.HasPrefix(imageURL, "data:") {
+		return validateImageDataURI(imageURL)
+	}
+
+	u, err := url.Parse(imageURL)
+	if err != nil {
+		return fmt.Errorf("malformed image URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported image URL scheme %q: use http, https or a base64 data URI", u.Scheme)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("image URL %q has no host", imageURL)
+	}
+	return nil
+}
+
+// ValidateImage implements ImageValidator.
+func (v *HTTPImageValidator) ValidateImage(ctx context.Context, imageURL string) error {
+	if err := (OfflineImageValidator{}).ValidateImage(ctx, imageURL); err != nil || strings.HasPrefix(imageURL, "data:") {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, imageURL, nil)
+	if err != nil {
+		return fmt.Errorf("error checking image URL: %w", err)
+	}
+	for key, values := range v.Header {
+		req.Header[key] = values
+	}
+
+	client := v.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error checking image URL: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("invalid image URL, status code: %d", resp.StatusCode)
+	}
+
+	size := resp.ContentLength
+	if size > MaxURLImageSize {
+		return fmt.Errorf("image size (%d bytes) exceeds limit of %d bytes", size, MaxURLImageSize)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if !isValidImageType(contentType) {
+		return fmt.Errorf("invalid image type: %s", contentType)
+	}
+
+	return nil
+}
+
+// validateImageDataURI checks the media type and decoded size of a base64 data URI.
+func validateImageDataURI(uri string) error {
+	meta, payload, ok := strings.Cut(strings.TrimPrefix(uri, "data:"), ",")
+	if !ok || !strings.HasSuffix(meta, ";base64") {
+		return fmt.Errorf("image data URI must be base64 encoded")
+	}
+	if contentType := strings.TrimSuffix(meta, ";base64"); !isValidImageType(contentType) {
+		return fmt.Errorf("invalid image type: %s", contentType)
+	}
+	if size := base64.StdEncoding.DecodedLen(len(payload)); size > MaxBase64ImageSize {
+		return fmt.Errorf("%w: size exceeds limit of %d bytes; use an ImagePreprocessor to downscale it", ErrImageTooLarge, MaxBase64ImageSize)
+	}
+	return nil
+}
+
+// imageValidator returns the validator configured with WithImageValidator or the default.
+func (c *Client) imageValidator() ImageValidator {
+	if c.images != nil {
+		return c.images
+	}
+	return defaultImageValidator
+}
+
+// validateRequest validates a chat request, checking image URLs with the client's validator.
+func (c *Client) validateRequest(ctx context.Context, req *ChatCompletionRequest) error {
+	return req.validate(ctx, c.imageValidator())
+}
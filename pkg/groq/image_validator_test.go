@@ -0,0 +1,68 @@
+package groq
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOfflineImageValidator(t *testing.T) {
+	tests := map[string]bool{
+		"https://bucket.example.com/a.png?X-Amz-Signature=abc":               true,
+		"data:image/png;base64,iVBORw0KGgo=":                                 true,
+		"ftp://example.com/a.png":                                            false,
+		"https:///a.png":                                                     false,
+		"data:application/pdf;base64,JVBERi0=":                               false,
+		"data:image/png,raw":                                                 false,
+		"data:image/png;base64," + strings.Repeat("A", MaxBase64ImageSize*2): false,
+	}
+	for url, valid := range tests {
+		err := OfflineImageValidator{}.ValidateImage(context.Background(), url)
+		if (err == nil) != valid {
+			t.Errorf("ValidateImage(%.50s) = %v, want valid %v", url, err, valid)
+		}
+	}
+}
+
+func TestHTTPImageValidatorHeaders(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead || r.Header.Get("Authorization") != "Bearer storage-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+	}))
+	defer srv.Close()
+
+	if err := (&HTTPImageValidator{}).ValidateImage(context.Background(), srv.URL+"/a.png"); err == nil {
+		t.Error("expected the unauthenticated HEAD check to fail")
+	}
+	v := &HTTPImageValidator{Header: http.Header{"Authorization": {"Bearer storage-token"}}}
+	if err := v.ValidateImage(context.Background(), srv.URL+"/a.png"); err != nil {
+		t.Errorf("authenticated HEAD check failed: %v", err)
+	}
+}
+
+func TestWithImageValidator(t *testing.T) {
+	api := newTestServer(t, func(req *ChatCompletionRequest) string { return "a cat" })
+	req := CreateVisionRequest(ModelLlama4Scout, "https://private.invalid/cat.png", "what is this?")
+
+	client := NewClient("test-key", WithBaseURL(api.URL))
+	if _, err := client.CreateChatCompletion(context.Background(), req); err == nil {
+		t.Fatal("expected the default validator to reject an unreachable image")
+	}
+
+	var checked []string
+	client = NewClient("test-key", WithBaseURL(api.URL), WithImageValidator(ImageValidatorFunc(func(ctx context.Context, url string) error {
+		checked = append(checked, url)
+		return OfflineImageValidator{}.ValidateImage(ctx, url)
+	})))
+	if _, err := client.CreateChatCompletion(context.Background(), req); err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+	if len(checked) != 1 || checked[0] != "https://private.invalid/cat.png" {
+		t.Errorf("expected the custom validator to check the image, got %v", checked)
+	}
+}
@@ -0,0 +1,13 @@
+// Package integration holds an opt-in test suite that runs the client against the live
+// Groq API. It exercises chat completions, streaming, vision, audio transcription and
+// tool calls with small, inexpensive models, and checks that responses decode from the
+// real wire format rather than from hand-written fixtures.
+//
+// The tests are skipped unless GROQ_API_KEY is set, so "go test ./..." stays offline.
+// To validate an environment or a change against the API:
+//
+//	GROQ_API_KEY=gsk_... go test ./pkg/groq/integration -v
+//
+// GROQ_BASE_URL points the suite at a proxy or gateway instead of the public endpoint,
+// and GROQ_TEST_IMAGE_URL replaces the public image used by the vision test.
+package integration
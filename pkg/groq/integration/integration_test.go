@@ -0,0 +1,218 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"math"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/genc-murat/groq-client/pkg/groq"
+)
+
+const (
+	chatModel  = groq.ModelLlama31_8bInstant
+	audioModel = groq.ModelWhisperLargeV3Turbo
+
+	defaultImageURL = "https://upload.wikimedia.org/wikipedia/commons/4/47/PNG_transparency_demonstration_1.png"
+)
+
+// newClient returns a client for the live API, or skips the test without GROQ_API_KEY.
+func newClient(t *testing.T) *groq.Client {
+	t.Helper()
+
+	apiKey := os.Getenv("GROQ_API_KEY")
+	if apiKey == "" {
+		t.Skip("GROQ_API_KEY is not set; skipping live API test")
+	}
+	if testing.Short() {
+		t.Skip("skipping live API test in short mode")
+	}
+
+	opts := []groq.Option{groq.WithAutoMigrateModels(func(from, to groq.ModelType) {
+		t.Logf("model %s is decommissioned, using %s", from, to)
+	})}
+	if baseURL := os.Getenv("GROQ_BASE_URL"); baseURL != "" {
+		opts = append(opts, groq.WithBaseURL(baseURL))
+	}
+	return groq.NewClient(apiKey, opts...)
+}
+
+func testContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func TestChatCompletion(t *testing.T) {
+	client := newClient(t)
+
+	req := groq.NewRequest(chatModel).
+		System("Answer with a single word.").
+		User("What is the capital of France?").
+		Temperature(0).
+		MaxTokens(10).
+		Build()
+	resp, err := client.CreateChatCompletion(testContext(t), req)
+	if err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+
+	if resp.ID == "" || resp.Model == "" || len(resp.Choices) == 0 {
+		t.Fatalf("incomplete response: %+v", resp)
+	}
+	content, ok := resp.Choices[0].Message.Content.(string)
+	if !ok || !strings.Contains(strings.ToLower(content), "paris") {
+		t.Errorf("unexpected answer %#v", resp.Choices[0].Message.Content)
+	}
+	if !resp.Choices[0].FinishReason.IsTerminal() {
+		t.Errorf("unexpected finish reason %q", resp.Choices[0].FinishReason)
+	}
+	if resp.Usage.PromptTokens == 0 || resp.Usage.CompletionTokens == 0 {
+		t.Errorf("usage not decoded: %+v", resp.Usage)
+	}
+}
+
+func TestChatCompletionStream(t *testing.T) {
+	client := newClient(t)
+
+	req := groq.NewRequest(chatModel).User("Count from 1 to 5, separated by spaces.").Temperature(0).MaxTokens(30).Build()
+
+	var (
+		text     strings.Builder
+		chunks   int
+		finished bool
+		usage    *groq.Usage
+	)
+	err := client.CreateChatCompletionStream(testContext(t), req, func(chunk *groq.ChatCompletionChunk) error {
+		chunks++
+		if len(chunk.Choices) > 0 {
+			text.WriteString(chunk.Choices[0].Delta.Content)
+		}
+		if chunk.Finished() {
+			finished = true
+		}
+		if u := chunk.ReportedUsage(); u != nil {
+			usage = u
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("CreateChatCompletionStream() error = %v", err)
+	}
+
+	if chunks < 2 || !finished {
+		t.Errorf("expected several chunks ending with a finish reason, got %d chunks, finished %v", chunks, finished)
+	}
+	if !strings.Contains(text.String(), "3") {
+		t.Errorf("unexpected streamed text %q", text.String())
+	}
+	if usage == nil || usage.TotalTokens == 0 {
+		t.Errorf("expected usage on the last chunk, got %+v", usage)
+	}
+}
+
+func TestVision(t *testing.T) {
+	client := newClient(t)
+
+	imageURL := os.Getenv("GROQ_TEST_IMAGE_URL")
+	if imageURL == "" {
+		imageURL = defaultImageURL
+	}
+	req := groq.CreateVisionRequest(groq.ModelLlama4Scout, imageURL, "Describe this image in one short sentence.")
+	req.MaxTokens = 60
+
+	resp, err := client.CreateChatCompletion(testContext(t), req)
+	if err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+	if len(resp.Choices) == 0 {
+		t.Fatal("no choices in response")
+	}
+	if content, ok := resp.Choices[0].Message.Content.(string); !ok || strings.TrimSpace(content) == "" {
+		t.Errorf("expected a description, got %#v", resp.Choices[0].Message.Content)
+	}
+}
+
+func TestTranscription(t *testing.T) {
+	client := newClient(t)
+
+	resp, err := client.CreateTranscription(testContext(t), &groq.TranscriptionRequest{
+		File:                   bytes.NewReader(toneWAV(2*time.Second, 16000)),
+		FileName:               "tone.wav",
+		Model:                  audioModel,
+		ResponseFormat:         "verbose_json",
+		Temperature:            groq.Float64(0),
+		TimestampGranularities: []string{"segment"},
+	})
+	if err != nil {
+		t.Fatalf("CreateTranscription() error = %v", err)
+	}
+
+	// A tone has no speech, so only the verbose_json metadata is checked.
+	if resp.Task != "transcribe" || resp.Duration < 1.5 {
+		t.Errorf("verbose_json metadata not decoded: task %q, duration %v", resp.Task, resp.Duration)
+	}
+}
+
+func TestToolCall(t *testing.T) {
+	client := newClient(t)
+
+	req := &groq.FunctionCallChatRequest{
+		ChatCompletionRequest: groq.NewRequest(chatModel).
+			User("What is the weather in Istanbul right now? Use the tool.").
+			Temperature(0).
+			Build(),
+		Functions: []groq.Function{groq.WeatherFunction},
+	}
+	resp, err := client.CreateFunctionCall(testContext(t), req)
+	if err != nil {
+		t.Fatalf("CreateFunctionCall() error = %v", err)
+	}
+	if len(resp.Choices) == 0 {
+		t.Fatal("no choices in response")
+	}
+
+	calls := resp.Choices[0].Message.ToolCalls
+	if len(calls) == 0 {
+		t.Fatalf("expected a tool call, got message %+v", resp.Choices[0].Message)
+	}
+	if calls[0].ID == "" || calls[0].Function.Name != groq.WeatherFunction.Name {
+		t.Errorf("unexpected tool call %+v", calls[0])
+	}
+
+	var args groq.WeatherArgs
+	if err := calls[0].Function.ParseArguments(&args); err != nil {
+		t.Fatalf("ParseArguments() error = %v", err)
+	}
+	if !strings.Contains(strings.ToLower(args.Location), "istanbul") {
+		t.Errorf("unexpected arguments %+v", args)
+	}
+}
+
+// toneWAV returns a 16-bit mono WAV file with a 440 Hz tone.
+func toneWAV(duration time.Duration, sampleRate int) []byte {
+	samples := int(duration.Seconds() * float64(sampleRate))
+
+	var buf bytes.Buffer
+	write := func(v interface{}) { _ = binary.Write(&buf, binary.LittleEndian, v) }
+	buf.WriteString("RIFF")
+	write(uint32(36 + 2*samples))
+	buf.WriteString("WAVEfmt ")
+	write(uint32(16))
+	write(uint16(1)) // PCM
+	write(uint16(1)) // mono
+	write(uint32(sampleRate))
+	write(uint32(2 * sampleRate))
+	write(uint16(2))
+	write(uint16(16))
+	buf.WriteString("data")
+	write(uint32(2 * samples))
+	for i := 0; i < samples; i++ {
+		write(int16(8000 * math.Sin(2*math.Pi*440*float64(i)/float64(sampleRate))))
+	}
+	return buf.Bytes()
+}
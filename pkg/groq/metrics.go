@@ -0,0 +1,297 @@
+package groq
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CallMetrics describes one finished call of the client for a MetricsRecorder.
+type CallMetrics struct {
+	Endpoint         Endpoint
+	Model            ModelType
+	Status           int  // HTTP status of the last attempt, 0 if no response was received
+	Failed           bool // The call returned an error
+	Latency          time.Duration
+	TimeToFirstToken time.Duration // Streams only; 0 for other calls or streams without chunks
+	PromptTokens     int
+	CompletionTokens int
+	Retries          int
+	RateLimitWait    time.Duration // Time spent waiting for the client's rate limiters
+	Cache            string        // "hit", "stale" or "miss" for cached chat calls, else empty
+}
+
+// MetricsRecorder receives the metrics of every call of a client configured with
+// WithMetrics. Metrics is the built-in implementation; groqprom.New registers the same
+// metrics on a prometheus.Registerer instead. RecordCall must be safe for concurrent use
+// and must not block.
+type MetricsRecorder interface {
+	RecordCall(m CallMetrics)
+}
+
+// WithMetrics makes the client report every call to recorder: its endpoint, model and
+// status, latency, time to first token of streams, token usage, retries, time spent
+// waiting for rate limits and the cache outcome of chat calls.
+//
+// Example usage:
+//
+//	metrics := NewMetrics()
+//	client := NewClient(apiKey, WithMetrics(metrics))
+//	http.Handle("/metrics", metrics)
+//
+// Parameters:
+//   - recorder: The recorder; nil disables metrics.
+//
+// Returns:
+//   - Option: A function that sets the recorder.
+func WithMetrics(recorder MetricsRecorder) Option {
+	return func(c *Client) {
+		c.metrics = recorder
+	}
+}
+
+type metricsKey struct{}
+
+// ContextWithMetrics returns a copy of ctx whose calls are also reported to recorder, in
+// addition to the recorder of WithMetrics, e.g. to measure one batch of calls on a shared
+// client.
+//
+// Example usage:
+//
+//	batch := NewMetrics()
+//	responses := client.CreateParallelCompletions(ContextWithMetrics(ctx, batch), requests)
+func ContextWithMetrics(ctx context.Context, recorder MetricsRecorder) context.Context {
+	return context.WithValue(ctx, metricsKey{}, recorder)
+}
+
+// metricsFrom returns the recorder stored in ctx, or nil.
+func metricsFrom(ctx context.Context) MetricsRecorder {
+	recorder, _ := ctx.Value(metricsKey{}).(MetricsRecorder)
+	return recorder
+}
+
+// metrics returns the metrics of a finished call.
+func (t *callTrace) metrics(latency time.Duration, err error) CallMetrics {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	m := CallMetrics{
+		Endpoint:         t.endpoint,
+		Model:            t.model,
+		Status:           t.status,
+		Failed:           err != nil,
+		Latency:          latency,
+		TimeToFirstToken: t.firstToken,
+		RateLimitWait:    t.queued,
+		Cache:            t.cache,
+	}
+	if t.attempts > 1 {
+		m.Retries = t.attempts - 1
+	}
+	if t.usage != nil {
+		m.PromptTokens = t.usage.PromptTokens
+		m.CompletionTokens = t.usage.CompletionTokens
+	}
+	return m
+}
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of the latency histograms of
+// Metrics. They reach further than Prometheus' defaults because completions of large
+// models can take a minute.
+var DefaultLatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// Metrics is a MetricsRecorder that aggregates calls into Prometheus metrics and serves
+// them in the Prometheus text format, without depending on the Prometheus client library:
+//
+//   - groq_requests_total{endpoint,model,status}: calls by HTTP status of the last attempt,
+//     "error" for failed calls without a response and "none" for answers from the cache
+//   - groq_request_duration_seconds{endpoint,model}: histogram of call latency
+//   - groq_time_to_first_token_seconds{model}: histogram of the time to the first chunk of streams
+//   - groq_tokens_total{model,type}: prompt and completion tokens
+//   - groq_retries_total{endpoint,model}: retried attempts
+//   - groq_rate_limit_wait_seconds_total{endpoint}: time spent waiting for rate limits
+//   - groq_cache_requests_total{result}: chat cache lookups by hit, stale or miss
+//
+// A Metrics is an http.Handler for the scrape endpoint and is safe for concurrent use.
+type Metrics struct {
+	buckets []float64
+
+	mu         sync.Mutex
+	requests   map[string]float64 // Keyed by rendered label set
+	latency    map[string]*histogram
+	firstToken map[string]*histogram
+	tokens     map[string]float64
+	retries    map[string]float64
+	waits      map[string]float64
+	cache      map[string]float64
+}
+
+// histogram counts observations per bucket; counts are not cumulative.
+type histogram struct {
+	counts []uint64 // One per bucket, plus one for +Inf
+	sum    float64
+	count  uint64
+}
+
+// NewMetrics creates an empty Metrics using DefaultLatencyBuckets.
+//
+// Returns:
+//   - *Metrics: The metrics, ready to pass to WithMetrics and to serve.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		buckets:    DefaultLatencyBuckets,
+		requests:   make(map[string]float64),
+		latency:    make(map[string]*histogram),
+		firstToken: make(map[string]*histogram),
+		tokens:     make(map[string]float64),
+		retries:    make(map[string]float64),
+		waits:      make(map[string]float64),
+		cache:      make(map[string]float64),
+	}
+}
+
+// StatusLabel returns the status label of the call in metrics: the HTTP status of the
+// last attempt, "error" for failed calls without a response or "none" for answers from
+// the cache.
+func (m CallMetrics) StatusLabel() string {
+	switch {
+	case m.Status != 0:
+		return strconv.Itoa(m.Status)
+	case m.Failed:
+		return "error"
+	}
+	return "none"
+}
+
+// RecordCall adds a finished call to the metrics.
+func (m *Metrics) RecordCall(call CallMetrics) {
+	status := call.StatusLabel()
+	endpoint, model := string(call.Endpoint), string(call.Model)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests[labels("endpoint", endpoint, "model", model, "status", status)]++
+	m.observe(m.latency, labels("endpoint", endpoint, "model", model), call.Latency)
+	if call.TimeToFirstToken > 0 {
+		m.observe(m.firstToken, labels("model", model), call.TimeToFirstToken)
+	}
+	if call.PromptTokens > 0 {
+		m.tokens[labels("model", model, "type", "prompt")] += float64(call.PromptTokens)
+	}
+	if call.CompletionTokens > 0 {
+		m.tokens[labels("model", model, "type", "completion")] += float64(call.CompletionTokens)
+	}
+	if call.Retries > 0 {
+		m.retries[labels("endpoint", endpoint, "model", model)] += float64(call.Retries)
+	}
+	if call.RateLimitWait > 0 {
+		m.waits[labels("endpoint", endpoint)] += call.RateLimitWait.Seconds()
+	}
+	if call.Cache != "" {
+		m.cache[labels("result", call.Cache)]++
+	}
+}
+
+// observe adds d to the histogram of a label set; the caller holds m.mu.
+func (m *Metrics) observe(histograms map[string]*histogram, key string, d time.Duration) {
+	h := histograms[key]
+	if h == nil {
+		h = &histogram{counts: make([]uint64, len(m.buckets)+1)}
+		histograms[key] = h
+	}
+
+	seconds := d.Seconds()
+	i := sort.SearchFloat64s(m.buckets, seconds)
+	h.counts[i]++
+	h.sum += seconds
+	h.count++
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format.
+//
+// Parameters:
+//   - w: The destination, e.g. the body of a scrape response.
+//
+// Returns:
+//   - int64: The number of bytes written.
+//   - error: The error of w, if any.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+
+	m.mu.Lock()
+	writeCounters(&buf, "groq_requests_total", "Calls to the Groq API by endpoint, model and status.", m.requests)
+	m.writeHistograms(&buf, "groq_request_duration_seconds", "Latency of calls to the Groq API.", m.latency)
+	m.writeHistograms(&buf, "groq_time_to_first_token_seconds", "Time to the first chunk of streamed chat completions.", m.firstToken)
+	writeCounters(&buf, "groq_tokens_total", "Prompt and completion tokens used.", m.tokens)
+	writeCounters(&buf, "groq_retries_total", "Retried attempts of calls to the Groq API.", m.retries)
+	writeCounters(&buf, "groq_rate_limit_wait_seconds_total", "Time calls waited for rate limits.", m.waits)
+	writeCounters(&buf, "groq_cache_requests_total", "Chat cache lookups by result.", m.cache)
+	m.mu.Unlock()
+
+	return buf.WriteTo(w)
+}
+
+// ServeHTTP serves the metrics to a Prometheus scrape.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = m.WriteTo(w)
+}
+
+// writeCounters writes a counter family, sorted by label set.
+func writeCounters(buf *bytes.Buffer, name, help string, values map[string]float64) {
+	fmt.Fprintf(buf, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+	for _, key := range sortedKeys(values) {
+		fmt.Fprintf(buf, "%s{%s} %s\n", name, key, formatFloat(values[key]))
+	}
+}
+
+// writeHistograms writes a histogram family, sorted by label set; the caller holds m.mu.
+func (m *Metrics) writeHistograms(buf *bytes.Buffer, name, help string, histograms map[string]*histogram) {
+	fmt.Fprintf(buf, "# HELP %s %s\n# TYPE %s histogram\n", name, help, name)
+	for _, key := range sortedKeys(histograms) {
+		h := histograms[key]
+		var cumulative uint64
+		for i, bound := range m.buckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(buf, "%s_bucket{%s,le=\"%s\"} %d\n", name, key, formatFloat(bound), cumulative)
+		}
+		fmt.Fprintf(buf, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, key, h.count)
+		fmt.Fprintf(buf, "%s_sum{%s} %s\n", name, key, formatFloat(h.sum))
+		fmt.Fprintf(buf, "%s_count{%s} %d\n", name, key, h.count)
+	}
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// labels renders name/value pairs as a Prometheus label set without braces.
+func labels(pairs ...string) string {
+	var b strings.Builder
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%s=\"%s\"", pairs[i], labelEscaper.Replace(pairs[i+1]))
+	}
+	return b.String()
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
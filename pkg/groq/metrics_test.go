@@ -0,0 +1,171 @@
+package groq
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMetrics(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","choices":[{"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}],` +
+			`"usage":{"prompt_tokens":7,"completion_tokens":3,"total_tokens":10}}`))
+	}))
+	defer srv.Close()
+
+	metrics := NewMetrics()
+	client := NewClient("test-key",
+		WithBaseURL(srv.URL),
+		WithRetryConfig(2, time.Millisecond),
+		WithCache(newMapCache()),
+		WithMetrics(metrics),
+	)
+	defer client.Close()
+
+	req := &ChatCompletionRequest{
+		Model:    ModelLlama31_8bInstant,
+		Messages: []ChatMessage{{Role: "user", Content: "hello"}},
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := client.CreateChatCompletion(context.Background(), req); err != nil {
+			t.Fatalf("CreateChatCompletion: %v", err)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+
+	for _, want := range []string{
+		`groq_requests_total{endpoint="chat",model="llama-3.1-8b-instant",status="200"} 1`,
+		`groq_requests_total{endpoint="chat",model="llama-3.1-8b-instant",status="none"} 1`,
+		`groq_request_duration_seconds_count{endpoint="chat",model="llama-3.1-8b-instant"} 2`,
+		`groq_request_duration_seconds_bucket{endpoint="chat",model="llama-3.1-8b-instant",le="+Inf"} 2`,
+		`groq_tokens_total{model="llama-3.1-8b-instant",type="prompt"} 7`,
+		`groq_tokens_total{model="llama-3.1-8b-instant",type="completion"} 3`,
+		`groq_retries_total{endpoint="chat",model="llama-3.1-8b-instant"} 1`,
+		`groq_cache_requests_total{result="hit"} 1`,
+		`groq_cache_requests_total{result="miss"} 1`,
+		"# TYPE groq_request_duration_seconds histogram",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics lack %q:\n%s", want, body)
+		}
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("unexpected content type %q", ct)
+	}
+}
+
+func TestMetricsStream(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"hi\"}}]}\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{},\"finish_reason\":\"stop\"}],\"x_groq\":{\"usage\":{\"prompt_tokens\":4,\"completion_tokens\":1,\"total_tokens\":5}}}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer srv.Close()
+
+	var recorded []CallMetrics
+	client := NewClient("test-key", WithBaseURL(srv.URL), WithMetrics(recorderFunc(func(m CallMetrics) {
+		recorded = append(recorded, m)
+	})))
+	defer client.Close()
+
+	err := client.CreateChatCompletionStream(context.Background(), &ChatCompletionRequest{
+		Model:    ModelLlama31_8bInstant,
+		Messages: []ChatMessage{{Role: "user", Content: "hello"}},
+	}, func(*ChatCompletionChunk) error { return nil })
+	if err != nil {
+		t.Fatalf("CreateChatCompletionStream: %v", err)
+	}
+
+	if len(recorded) != 1 {
+		t.Fatalf("expected one recorded call, got %d", len(recorded))
+	}
+	m := recorded[0]
+	if m.Endpoint != EndpointChat || m.Status != http.StatusOK || m.Failed {
+		t.Errorf("unexpected call metrics: %+v", m)
+	}
+	if m.TimeToFirstToken <= 0 || m.TimeToFirstToken > m.Latency {
+		t.Errorf("time to first token %v not within latency %v", m.TimeToFirstToken, m.Latency)
+	}
+	if m.PromptTokens != 4 || m.CompletionTokens != 1 {
+		t.Errorf("unexpected usage: %+v", m)
+	}
+}
+
+func TestMetricsStreamFirstTokenOnArrival(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"hi\"}}]}\n\n")
+		w.(http.Flusher).Flush()
+		time.Sleep(200 * time.Millisecond)
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{},\"finish_reason\":\"stop\"}]}\n\n")
+	}))
+	defer srv.Close()
+
+	var recorded []CallMetrics
+	client := NewClient("test-key", WithBaseURL(srv.URL), WithMetrics(recorderFunc(func(m CallMetrics) {
+		recorded = append(recorded, m)
+	})))
+	defer client.Close()
+
+	err := client.CreateChatCompletionStream(context.Background(), &ChatCompletionRequest{
+		Model:    ModelLlama31_8bInstant,
+		Messages: []ChatMessage{{Role: "user", Content: "hello"}},
+	}, func(*ChatCompletionChunk) error { return nil })
+	if err != nil {
+		t.Fatalf("CreateChatCompletionStream: %v", err)
+	}
+
+	if len(recorded) != 1 {
+		t.Fatalf("expected one recorded call, got %d", len(recorded))
+	}
+	if m := recorded[0]; m.TimeToFirstToken <= 0 || m.Latency-m.TimeToFirstToken < 150*time.Millisecond {
+		t.Errorf("first chunk not timed on arrival: first token %v, latency %v", m.TimeToFirstToken, m.Latency)
+	}
+}
+
+func TestContextWithMetrics(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"ok"}}],"usage":{"prompt_tokens":3,"completion_tokens":2,"total_tokens":5}}`)
+	}))
+	defer srv.Close()
+
+	var client, scoped []CallMetrics
+	c := NewClient("test-key", WithBaseURL(srv.URL), WithMetrics(recorderFunc(func(m CallMetrics) {
+		client = append(client, m)
+	})))
+	defer c.Close()
+	req := NewRequest(ModelLlama31_8bInstant).User("hi").Build()
+
+	ctx := ContextWithMetrics(context.Background(), recorderFunc(func(m CallMetrics) {
+		scoped = append(scoped, m)
+	}))
+	if _, err := c.CreateChatCompletion(ctx, req); err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if _, err := c.CreateChatCompletion(context.Background(), req); err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+
+	if len(client) != 2 || len(scoped) != 1 || scoped[0].CompletionTokens != 2 {
+		t.Errorf("recorded %d calls on the client and %+v on the context", len(client), scoped)
+	}
+}
+
+type recorderFunc func(CallMetrics)
+
+func (f recorderFunc) RecordCall(m CallMetrics) { f(m) }
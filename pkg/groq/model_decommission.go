@@ -0,0 +1,124 @@
+package groq
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/genc-murat/groq-client/internal/util"
+	"github.com/genc-murat/groq-client/pkg/groq/wire"
+)
+
+// decommissionedCode is the error code the API returns for requests to retired models.
+const decommissionedCode = "model_decommissioned"
+
+var ErrModelDecommissioned = errors.New("model decommissioned")
+
+// modelReplacements maps models Groq has decommissioned to the recommended successor,
+// following https://console.groq.com/docs/deprecations.
+var modelReplacements = map[ModelType]ModelType{
+	ModelMixtral8x7b32768:       ModelLlama33_70bVersatile,
+	ModelLlama3_70b_8192:        ModelLlama33_70bVersatile,
+	ModelLlama3_8b_8192:         ModelLlama31_8bInstant,
+	ModelGemma29bIt:             ModelLlama31_8bInstant,
+	ModelLlama33_70bSpecdec:     ModelLlama33_70bVersatile,
+	ModelLlama32_1bPreview:      ModelLlama31_8bInstant,
+	ModelLlama32_3bPreview:      ModelLlama31_8bInstant,
+	ModelLlama32_11bVision:      ModelLlama4Scout,
+	ModelLlama32_90bVision:      ModelLlama4Scout,
+	ModelLlamaGuard3_8b:         ModelLlamaGuard4_12b,
+	ModelDistilWhisperLargeV3En: ModelWhisperLargeV3Turbo,
+}
+
+type ModelDecommissionedError struct {
+	Model       ModelType
+	Replacement ModelType // Recommended successor, empty if unknown
+	Message     string    // Message returned by the API
+	Err         error     // The underlying request error
+}
+
+// Error returns the API message together with the recommended replacement.
+func (e *ModelDecommissionedError) Error() string {
+	msg := fmt.Sprintf("%s: %s", ErrModelDecommissioned, e.Model)
+	if e.Replacement != "" {
+		msg += fmt.Sprintf("; use %s instead", e.Replacement)
+	}
+	if e.Message != "" {
+		msg += " (" + e.Message + ")"
+	}
+	return msg
+}
+
+// Unwrap allows errors.Is to match both ErrModelDecommissioned and the request error.
+func (e *ModelDecommissionedError) Unwrap() []error {
+	return []error{ErrModelDecommissioned, e.Err}
+}
+
+// ModelReplacement returns the recommended successor of a decommissioned model.
+//
+// Parameters:
+//   - model: The model to look up.
+//
+// Returns:
+//   - ModelType: The replacement.
+//   - bool: False if the model is not known to be decommissioned.
+func ModelReplacement(model ModelType) (ModelType, bool) {
+	replacement, ok := modelReplacements[model]
+	return replacement, ok
+}
+
+// WithAutoMigrateModels makes the client send requests for decommissioned models to their
+// recommended replacement instead of failing with a *ModelDecommissionedError. Requests for
+// models in the maintained mapping are switched before they are sent, so no call is wasted;
+// models the mapping does not know still fail with the typed error. onMigrate, if not nil,
+// is called on every switch so applications can log it and update their configuration.
+//
+// Example usage:
+//
+//	client := NewClient(apiKey, WithAutoMigrateModels(func(from, to ModelType) {
+//	    log.Printf("model %s is decommissioned, using %s", from, to)
+//	}))
+func WithAutoMigrateModels(onMigrate func(from, to ModelType)) Option {
+	return func(c *Client) {
+		c.autoMigrate = true
+		c.onMigrate = onMigrate
+	}
+}
+
+// migrateModel returns the model a request should use, which is the replacement of a
+// decommissioned model when WithAutoMigrateModels is enabled.
+func (c *Client) migrateModel(model ModelType) ModelType {
+	if !c.autoMigrate {
+		return model
+	}
+	replacement, ok := modelReplacements[model]
+	if !ok || replacement == model {
+		return model
+	}
+	if c.onMigrate != nil {
+		c.onMigrate(model, replacement)
+	}
+	return replacement
+}
+
+// decommissionedError converts a request error caused by a decommissioned model into a
+// *ModelDecommissionedError. Other errors are returned unchanged.
+func decommissionedError(model ModelType, err error) error {
+	var statusErr *util.StatusError
+	if !errors.As(err, &statusErr) {
+		return err
+	}
+
+	var body wire.ErrorResponse
+	if json.Unmarshal(statusErr.Body, &body) != nil || body.Error.Code != decommissionedCode {
+		return err
+	}
+
+	replacement, _ := ModelReplacement(model)
+	return &ModelDecommissionedError{
+		Model:       model,
+		Replacement: replacement,
+		Message:     body.Error.Message,
+		Err:         err,
+	}
+}
@@ -0,0 +1,65 @@
+package groq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestModelDecommissionedError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ChatCompletionRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.Model == ModelMixtral8x7b32768 {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":{"message":"The model ` + "`mixtral-8x7b-32768`" + ` has been decommissioned","type":"invalid_request_error","code":"model_decommissioned"}}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"model":   req.Model,
+			"choices": []map[string]interface{}{{"message": map[string]string{"role": "assistant", "content": "ok"}}},
+		})
+	}))
+	defer srv.Close()
+
+	req := NewRequest(ModelMixtral8x7b32768).User("hi").Build()
+
+	client := NewClient("test-key", WithBaseURL(srv.URL))
+	_, err := client.CreateChatCompletion(context.Background(), req)
+
+	var decommissioned *ModelDecommissionedError
+	if !errors.As(err, &decommissioned) || !errors.Is(err, ErrModelDecommissioned) {
+		t.Fatalf("expected ModelDecommissionedError, got %v", err)
+	}
+	if decommissioned.Replacement != ModelLlama33_70bVersatile || !strings.Contains(decommissioned.Message, "decommissioned") {
+		t.Errorf("unexpected error: %+v", decommissioned)
+	}
+
+	var from, to ModelType
+	client = NewClient("test-key", WithBaseURL(srv.URL), WithAutoMigrateModels(func(f, t ModelType) { from, to = f, t }))
+	resp, err := client.CreateChatCompletion(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateChatCompletion() with auto-migration error = %v", err)
+	}
+	if resp.Model != ModelLlama33_70bVersatile || from != ModelMixtral8x7b32768 || to != ModelLlama33_70bVersatile {
+		t.Errorf("request was not migrated: model %s, hook %s -> %s", resp.Model, from, to)
+	}
+	if req.Model != ModelMixtral8x7b32768 {
+		t.Error("auto-migration modified the caller's request")
+	}
+}
+
+func TestModelReplacementsAreValid(t *testing.T) {
+	for model, replacement := range modelReplacements {
+		if !replacement.IsValid() {
+			t.Errorf("replacement %s of %s is not a known model", replacement, model)
+		}
+		if _, ok := modelReplacements[replacement]; ok {
+			t.Errorf("replacement %s of %s is decommissioned itself", replacement, model)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package groq
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/genc-murat/groq-client/pkg/groq/wire"
+)
+
+type (
+	RemoteModel = wire.Model
+	ModelList   = wire.ModelList
+)
+
+// ListModels retrieves the models currently available from the API.
+//
+// Parameters:
+//   - ctx: Context for the request
+//
+// Returns:
+//   - *ModelList: The live model listing
+//   - error: Any error that occurred during the request
+func (c *Client) ListModels(ctx context.Context) (*ModelList, error) {
+	var result ModelList
+	if err := c.httpClient.DoJSON(ctx, "GET", fmt.Sprintf("%s/models", c.baseURL), nil, &result, nil); err != nil {
+		return nil, fmt.Errorf("model listing failed: %w", err)
+	}
+	return &result, nil
+}
+
+type ModelChangeKind string
+
+const (
+	ModelAdded       ModelChangeKind = "added"
+	ModelRemoved     ModelChangeKind = "removed"
+	ModelDeactivated ModelChangeKind = "deactivated"
+	ModelModified    ModelChangeKind = "modified"
+)
+
+type ModelChange struct {
+	Model  ModelType       `json:"model"`
+	Kind   ModelChangeKind `json:"kind"`
+	Field  string          `json:"field,omitempty"`
+	Before interface{}     `json:"before,omitempty"`
+	After  interface{}     `json:"after,omitempty"`
+}
+
+type ModelRegistryDiff struct {
+	Severity string        `json:"severity"` // "major", "minor", "patch" or "none", following semantic versioning
+	Changes  []ModelChange `json:"changes"`
+}
+
+// HasChanges reports whether the live listing differs from the registry.
+func (d *ModelRegistryDiff) HasChanges() bool {
+	return len(d.Changes) > 0
+}
+
+// DiffModels compares a live model listing with the registry embedded in this package.
+// Models that disappeared or became inactive are breaking changes (severity "major"),
+// new models are additions ("minor"), and changed limits are "patch" level changes.
+// Changes are sorted by model and field, so the output is stable for alerting.
+//
+// Parameters:
+//   - live: The models returned by ListModels.
+//
+// Returns:
+//   - *ModelRegistryDiff: The machine-readable differences.
+func DiffModels(live []RemoteModel) *ModelRegistryDiff {
+	diff := &ModelRegistryDiff{Changes: []ModelChange{}}
+	seen := make(map[ModelType]bool, len(live))
+
+	for _, remote := range live {
+		model := ModelType(remote.ID)
+		seen[model] = true
+
+		info, known := modelInfoMap[model]
+		if !known {
+			if remote.Active {
+				diff.Changes = append(diff.Changes, ModelChange{Model: model, Kind: ModelAdded})
+			}
+			continue
+		}
+
+		if !remote.Active {
+			diff.Changes = append(diff.Changes, ModelChange{Model: model, Kind: ModelDeactivated})
+			continue
+		}
+		if info.ContextWindow > 0 && remote.ContextWindow > 0 && info.ContextWindow != remote.ContextWindow {
+			diff.Changes = append(diff.Changes, ModelChange{
+				Model: model, Kind: ModelModified, Field: "context_window",
+				Before: info.ContextWindow, After: remote.ContextWindow,
+			})
+		}
+		if info.MaxOutput > 0 && remote.MaxCompletionTokens > 0 && info.MaxOutput != remote.MaxCompletionTokens {
+			diff.Changes = append(diff.Changes, ModelChange{
+				Model: model, Kind: ModelModified, Field: "max_completion_tokens",
+				Before: info.MaxOutput, After: remote.MaxCompletionTokens,
+			})
+		}
+	}
+
+	for model := range modelInfoMap {
+		if !seen[model] {
+			diff.Changes = append(diff.Changes, ModelChange{Model: model, Kind: ModelRemoved})
+		}
+	}
+
+	sort.Slice(diff.Changes, func(i, j int) bool {
+		a, b := diff.Changes[i], diff.Changes[j]
+		if a.Model != b.Model {
+			return a.Model < b.Model
+		}
+		return a.Field < b.Field
+	})
+
+	diff.Severity = "none"
+	for _, change := range diff.Changes {
+		switch {
+		case change.Kind == ModelRemoved || change.Kind == ModelDeactivated:
+			diff.Severity = "major"
+		case change.Kind == ModelAdded && diff.Severity != "major":
+			diff.Severity = "minor"
+		case diff.Severity == "none":
+			diff.Severity = "patch"
+		}
+	}
+
+	return diff
+}
+
+// DiffModelRegistry fetches the live model listing and diffs it against the embedded
+// registry, so operators can alert before a model change breaks production.
+//
+// Example usage:
+//
+//	diff, err := client.DiffModelRegistry(ctx)
+//	if err == nil && diff.Severity == "major" {
+//	    out, _ := json.Marshal(diff)
+//	    alert(string(out))
+//	}
+func (c *Client) DiffModelRegistry(ctx context.Context) (*ModelRegistryDiff, error) {
+	list, err := c.ListModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return DiffModels(list.Data), nil
+}
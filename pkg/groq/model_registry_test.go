@@ -0,0 +1,54 @@
+package groq
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDiffModelRegistry(t *testing.T) {
+	var live []RemoteModel
+	for _, model := range AllModels() {
+		info := model.GetInfo()
+		live = append(live, RemoteModel{ID: string(model), Active: true, ContextWindow: info.ContextWindow})
+	}
+
+	if diff := DiffModels(live); diff.HasChanges() || diff.Severity != "none" {
+		t.Fatalf("expected no changes, got %+v", diff)
+	}
+
+	var changed []RemoteModel
+	for _, m := range live {
+		switch ModelType(m.ID) {
+		case ModelMixtral8x7b32768:
+			continue
+		case ModelLlama31_8bInstant:
+			m.ContextWindow = 131072
+		}
+		changed = append(changed, m)
+	}
+	changed = append(changed, RemoteModel{ID: "qwen-2.5-32b", Active: true})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(ModelList{Object: "list", Data: changed})
+	}))
+	defer srv.Close()
+
+	diff, err := NewClient("test-key", WithBaseURL(srv.URL)).DiffModelRegistry(context.Background())
+	if err != nil {
+		t.Fatalf("DiffModelRegistry() error = %v", err)
+	}
+
+	kinds := map[ModelType]ModelChangeKind{}
+	for _, c := range diff.Changes {
+		kinds[c.Model] = c.Kind
+	}
+	if kinds[ModelMixtral8x7b32768] != ModelRemoved || kinds["qwen-2.5-32b"] != ModelAdded || kinds[ModelLlama31_8bInstant] != ModelModified {
+		t.Errorf("unexpected changes: %+v", diff.Changes)
+	}
+	if diff.Severity != "major" {
+		t.Errorf("Severity = %s, want major", diff.Severity)
+	}
+}
@@ -1,6 +1,12 @@
 package groq
 
-import "fmt"
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/genc-murat/groq-client/pkg/groq/wire"
+)
 
 type ModelType string
 
@@ -17,6 +23,8 @@ const (
 	ModelMixtral8x7b32768       ModelType = "mixtral-8x7b-32768"
 	ModelWhisperLargeV3         ModelType = "whisper-large-v3"
 	ModelWhisperLargeV3Turbo    ModelType = "whisper-large-v3-turbo"
+	ModelPlayAITTS              ModelType = "playai-tts"
+	ModelPlayAITTSArabic        ModelType = "playai-tts-arabic"
 
 	// Preview Models
 	ModelLlama33_70bSpecdec ModelType = "llama-3.3-70b-specdec"
@@ -24,63 +32,163 @@ const (
 	ModelLlama32_3bPreview  ModelType = "llama-3.2-3b-preview"
 	ModelLlama32_11bVision  ModelType = "llama-3.2-11b-vision-preview"
 	ModelLlama32_90bVision  ModelType = "llama-3.2-90b-vision-preview"
+	ModelLlama4Scout        ModelType = "meta-llama/llama-4-scout-17b-16e-instruct"
+	ModelLlamaGuard4_12b    ModelType = "meta-llama/llama-guard-4-12b"
 )
 
 type ModelInfo struct {
 	ContextWindow int      // Maximum context window in tokens
 	MaxOutput     int      // Maximum output tokens
-	MaxFileSize   string   // Maximum file size
+	MaxFileSize   int64    // Maximum upload size in bytes for audio models
 	MaxImageSize  string   // Maximum image size for vision models
 	IsPreview     bool     // Whether this is a preview model
 	Developer     string   // Model developer/organization
 	Features      []string // Supported features: vision, tool-use, json-mode
+
+	AudioPricePerHour     float64 // USD per hour of audio for speech-to-text models
+	InputPricePerMillion  float64 // USD per million prompt tokens
+	OutputPricePerMillion float64 // USD per million completion tokens
 }
 
 type ChatMessage struct {
-	Role    string      `json:"role"`
-	Content interface{} `json:"content"`
+	Role       string      `json:"role"`
+	Content    interface{} `json:"content"`
+	ToolCalls  []ToolCall  `json:"tool_calls,omitempty"`   // Tools the assistant wants to call
+	ToolCallID string      `json:"tool_call_id,omitempty"` // The call a "tool" message answers
 }
 
 type ChatCompletionRequest struct {
 	Model       ModelType     `json:"model"`
 	Messages    []ChatMessage `json:"messages"`
 	MaxTokens   int           `json:"max_tokens,omitempty"`
-	Temperature float64       `json:"temperature,omitempty"`
+	Temperature *float64      `json:"temperature,omitempty"` // nil uses the API default; see Float64
+	TopP        *float64      `json:"top_p,omitempty"`       // nil uses the API default; see Float64
 	Stream      bool          `json:"stream,omitempty"`
+	Tools       []Tool        `json:"tools,omitempty"`
+
+	Annotations map[string]string `json:"-"` // Analytics labels, never sent to the API
 }
 
+type Usage = wire.Usage
+
 type ChatCompletionResponse struct {
 	ID      string    `json:"id"`
 	Object  string    `json:"object"`
 	Created int64     `json:"created"`
 	Model   ModelType `json:"model"`
-	Usage   struct {
-		PromptTokens     int `json:"prompt_tokens"`
-		CompletionTokens int `json:"completion_tokens"`
-		TotalTokens      int `json:"total_tokens"`
-	} `json:"usage"`
+	Usage   Usage     `json:"usage"`
 	Choices []struct {
-		Message      ChatMessage `json:"message"`
-		FinishReason string      `json:"finish_reason"`
+		Message      ChatMessage  `json:"message"`
+		FinishReason FinishReason `json:"finish_reason"`
 	} `json:"choices"`
 }
 
+type FinishReason string
+
+const (
+	FinishReasonStop          FinishReason = "stop"
+	FinishReasonLength        FinishReason = "length"
+	FinishReasonToolCalls     FinishReason = "tool_calls"
+	FinishReasonFunctionCall  FinishReason = "function_call"
+	FinishReasonContentFilter FinishReason = "content_filter"
+)
+
+// IsTerminal reports whether the choice has finished. Streamed choices carry an empty
+// finish reason until their last chunk.
+func (r FinishReason) IsTerminal() bool {
+	return r != ""
+}
+
 type ChatCompletionChunk struct {
 	ID      string    `json:"id"`
 	Object  string    `json:"object"`
 	Created int64     `json:"created"`
 	Model   ModelType `json:"model"`
 	Choices []struct {
+		Index int `json:"index"`
 		Delta struct {
 			Content string `json:"content"`
 			Role    string `json:"role,omitempty"`
 		} `json:"delta"`
-		FinishReason string `json:"finish_reason"`
+		FinishReason FinishReason `json:"finish_reason"`
 	} `json:"choices"`
+	Usage *Usage `json:"usage,omitempty"` // Set on the last chunk when the API reports usage
+	XGroq struct {
+		ID    string `json:"id,omitempty"`
+		Usage *Usage `json:"usage,omitempty"` // Groq reports stream usage here on the last chunk
+	} `json:"x_groq"`
+}
+
+// ReportedUsage returns the token usage the API attached to the chunk, or nil.
+func (c *ChatCompletionChunk) ReportedUsage() *Usage {
+	if c.Usage != nil {
+		return c.Usage
+	}
+	return c.XGroq.Usage
+}
+
+// Finished reports whether every choice in the chunk carries a terminal finish reason.
+func (c *ChatCompletionChunk) Finished() bool {
+	if len(c.Choices) == 0 {
+		return false
+	}
+	for _, choice := range c.Choices {
+		if !choice.FinishReason.IsTerminal() {
+			return false
+		}
+	}
+	return true
 }
 
 type StreamHandler func(*ChatCompletionChunk) error
 
+// UnmarshalJSON decodes a ChatMessage, restoring multimodal content as []ContentType
+// instead of the generic []interface{} the default decoder would produce.
+func (m *ChatMessage) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Role       string          `json:"role"`
+		Content    json.RawMessage `json:"content"`
+		ToolCalls  []ToolCall      `json:"tool_calls"`
+		ToolCallID string          `json:"tool_call_id"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	m.Role = raw.Role
+	m.Content = nil
+	m.ToolCalls = raw.ToolCalls
+	m.ToolCallID = raw.ToolCallID
+
+	content := bytes.TrimSpace(raw.Content)
+	if len(content) == 0 || string(content) == "null" {
+		return nil
+	}
+
+	switch content[0] {
+	case '"':
+		var text string
+		if err := json.Unmarshal(content, &text); err != nil {
+			return err
+		}
+		m.Content = text
+	case '[':
+		var parts []ContentType
+		if err := json.Unmarshal(content, &parts); err != nil {
+			return err
+		}
+		m.Content = parts
+	default:
+		var v interface{}
+		if err := json.Unmarshal(content, &v); err != nil {
+			return err
+		}
+		m.Content = v
+	}
+
+	return nil
+}
+
 // String returns the string representation of the ModelType.
 func (m ModelType) String() string {
 	return string(m)
@@ -143,105 +251,187 @@ func PreviewModels() []ModelType {
 
 var modelInfoMap = map[ModelType]ModelInfo{
 	ModelDistilWhisperLargeV3En: {
-		MaxFileSize: "25 MB",
-		Developer:   "HuggingFace",
+		MaxFileSize:       25 << 20,
+		AudioPricePerHour: 0.02,
+		Developer:         "HuggingFace",
 	},
 	ModelGemma29bIt: {
-		ContextWindow: 8192,
-		Developer:     "Google",
+		ContextWindow:         8192,
+		Developer:             "Google",
+		InputPricePerMillion:  0.20,
+		OutputPricePerMillion: 0.20,
 	},
 	ModelLlama33_70bVersatile: {
-		ContextWindow: 128000,
-		MaxOutput:     32768,
-		Developer:     "Meta",
+		ContextWindow:         128000,
+		MaxOutput:             32768,
+		Developer:             "Meta",
+		InputPricePerMillion:  0.59,
+		OutputPricePerMillion: 0.79,
 	},
 	ModelLlama31_8bInstant: {
-		ContextWindow: 128000,
-		MaxOutput:     8192,
-		Developer:     "Meta",
+		ContextWindow:         128000,
+		MaxOutput:             8192,
+		Developer:             "Meta",
+		InputPricePerMillion:  0.05,
+		OutputPricePerMillion: 0.08,
 	},
 	ModelLlamaGuard3_8b: {
-		ContextWindow: 8192,
-		Developer:     "Meta",
+		ContextWindow:         8192,
+		Developer:             "Meta",
+		InputPricePerMillion:  0.20,
+		OutputPricePerMillion: 0.20,
 	},
 	ModelLlama3_70b_8192: {
-		ContextWindow: 8192,
-		Developer:     "Meta",
+		ContextWindow:         8192,
+		Developer:             "Meta",
+		InputPricePerMillion:  0.59,
+		OutputPricePerMillion: 0.79,
 	},
 	ModelLlama3_8b_8192: {
-		ContextWindow: 8192,
-		Developer:     "Meta",
+		ContextWindow:         8192,
+		Developer:             "Meta",
+		InputPricePerMillion:  0.05,
+		OutputPricePerMillion: 0.08,
 	},
 	ModelMixtral8x7b32768: {
-		ContextWindow: 32768,
-		Developer:     "Mistral",
+		ContextWindow:         32768,
+		Developer:             "Mistral",
+		InputPricePerMillion:  0.24,
+		OutputPricePerMillion: 0.24,
 	},
 	ModelWhisperLargeV3: {
-		MaxFileSize: "25 MB",
-		Developer:   "OpenAI",
+		MaxFileSize:       25 << 20,
+		AudioPricePerHour: 0.111,
+		Developer:         "OpenAI",
 	},
 	ModelWhisperLargeV3Turbo: {
-		MaxFileSize: "25 MB",
-		Developer:   "OpenAI",
+		MaxFileSize:       25 << 20,
+		AudioPricePerHour: 0.04,
+		Developer:         "OpenAI",
+	},
+	ModelPlayAITTS: {
+		ContextWindow: 8192,
+		Developer:     "PlayAI",
+	},
+	ModelPlayAITTSArabic: {
+		ContextWindow: 8192,
+		Developer:     "PlayAI",
 	},
 
 	// Preview Models
 	ModelLlama33_70bSpecdec: {
-		ContextWindow: 8192,
-		Developer:     "Meta",
-		IsPreview:     true,
+		ContextWindow:         8192,
+		Developer:             "Meta",
+		InputPricePerMillion:  0.59,
+		OutputPricePerMillion: 0.99,
+		IsPreview:             true,
 	},
 	ModelLlama32_1bPreview: {
-		ContextWindow: 128000,
-		MaxOutput:     8192,
-		Developer:     "Meta",
-		IsPreview:     true,
+		ContextWindow:         128000,
+		MaxOutput:             8192,
+		Developer:             "Meta",
+		InputPricePerMillion:  0.04,
+		OutputPricePerMillion: 0.04,
+		IsPreview:             true,
 	},
 	ModelLlama32_3bPreview: {
-		ContextWindow: 128000,
-		MaxOutput:     8192,
-		Developer:     "Meta",
-		IsPreview:     true,
+		ContextWindow:         128000,
+		MaxOutput:             8192,
+		Developer:             "Meta",
+		InputPricePerMillion:  0.06,
+		OutputPricePerMillion: 0.06,
+		IsPreview:             true,
 	},
 	ModelLlama32_11bVision: {
-		ContextWindow: 128000,
-		MaxOutput:     8192,
-		Developer:     "Meta",
-		IsPreview:     true,
+		ContextWindow:         128000,
+		MaxOutput:             8192,
+		Developer:             "Meta",
+		InputPricePerMillion:  0.18,
+		OutputPricePerMillion: 0.18,
+		IsPreview:             true,
 	},
 	ModelLlama32_90bVision: {
-		ContextWindow: 128000,
-		MaxOutput:     8192,
-		Developer:     "Meta",
-		IsPreview:     true,
+		ContextWindow:         128000,
+		MaxOutput:             8192,
+		Developer:             "Meta",
+		InputPricePerMillion:  0.90,
+		OutputPricePerMillion: 0.90,
+		IsPreview:             true,
+	},
+	ModelLlama4Scout: {
+		ContextWindow:         131072,
+		MaxOutput:             8192,
+		MaxImageSize:          "20MB",
+		Developer:             "Meta",
+		Features:              []string{"vision", "tool-use", "json-mode"},
+		InputPricePerMillion:  0.11,
+		OutputPricePerMillion: 0.34,
+		IsPreview:             true,
+	},
+	ModelLlamaGuard4_12b: {
+		ContextWindow:         131072,
+		MaxOutput:             1024,
+		Developer:             "Meta",
+		InputPricePerMillion:  0.20,
+		OutputPricePerMillion: 0.20,
+		IsPreview:             true,
 	},
 }
 
+// Float64 returns a pointer to v, for optional sampling parameters such as Temperature
+// and TopP. Leaving such a field nil uses the API default, while Float64(0) sends an
+// explicit zero.
+//
+// Example usage:
+//
+//	req := &ChatCompletionRequest{
+//	    Model:       ModelLlama31_8bInstant,
+//	    Messages:    messages,
+//	    Temperature: Float64(0), // deterministic output
+//	}
+func Float64(v float64) *float64 {
+	return &v
+}
+
 // Validate checks if the ChatCompletionRequest is well-formed and meets model requirements.
 // It verifies:
-// - The model is valid
-// - At least one message is present
-// - The max_tokens value doesn't exceed the model's maximum output limit
-// - Vision-related content is valid when present
+//   - The model is valid
+//   - At least one message is present
+//   - The max_tokens value doesn't exceed the model's maximum output limit
+//   - The temperature, if set, is between 0 and 2
+//   - The top_p value, if set, is between 0 and 1
+//   - Vision-related content is valid when present; image URLs are checked with
+//     HTTPImageValidator (clients use the validator set by WithImageValidator)
 //
 // Returns an error if any validation check fails, nil otherwise.
 func (r *ChatCompletionRequest) Validate() error {
+	return r.validate(context.Background(), defaultImageValidator)
+}
+
+// validate implements Validate with the given image validator.
+func (r *ChatCompletionRequest) validate(ctx context.Context, images ImageValidator) error {
 	if !r.Model.IsValid() {
-		return fmt.Errorf("invalid model: %s", r.Model)
+		return newLocalizedError(nil, MsgInvalidModel, r.Model)
 	}
 	if len(r.Messages) == 0 {
-		return fmt.Errorf("at least one message is required")
+		return newLocalizedError(nil, MsgMessagesRequired)
 	}
 
 	info := r.Model.GetInfo()
 	if info.MaxOutput > 0 && r.MaxTokens > info.MaxOutput {
-		return fmt.Errorf("max_tokens exceeds model limit of %d", info.MaxOutput)
+		return newLocalizedError(nil, MsgMaxTokensExceeded, info.MaxOutput)
+	}
+	if r.Temperature != nil && (*r.Temperature < 0 || *r.Temperature > 2) {
+		return newLocalizedError(nil, MsgTemperatureRange)
+	}
+	if r.TopP != nil && (*r.TopP < 0 || *r.TopP > 1) {
+		return newLocalizedError(nil, MsgTopPRange)
 	}
 
 	// Check if request contains vision content
 	for _, msg := range r.Messages {
 		if _, ok := msg.Content.([]ContentType); ok {
-			if err := r.validateVision(); err != nil {
+			if err := r.validateVision(ctx, images); err != nil {
 				return err
 			}
 			break
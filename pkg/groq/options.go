@@ -1,6 +1,7 @@
 package groq
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/genc-murat/groq-client/internal/util"
@@ -17,14 +18,17 @@ type Option func(*Client)
 //   - Option: A function that sets the base URL for the client.
 func WithBaseURL(baseURL string) Option {
 	return func(c *Client) {
+		c.claim("base URL", "WithBaseURL")
 		c.baseURL = baseURL
+		c.baseURLs = nil
 	}
 }
 
 // WithHTTPConfig returns an Option that configures the HTTP client of the Client
-// with the provided HTTPClientConfig. It merges any existing base headers from
-// the current HTTP client into the new configuration before creating a new
-// HTTP client.
+// with the provided HTTPClientConfig. Its base headers are merged with the others and its
+// retry hook runs along with those of WithOnRetry; the other fields are used if they are
+// set. A setting that is also made by a dedicated option, such as the timeout by
+// WithTimeout, is a conflict that NewClientE reports.
 //
 // Parameters:
 //   - config: The HTTPClientConfig to use for configuring the HTTP client.
@@ -33,23 +37,43 @@ func WithBaseURL(baseURL string) Option {
 //   - Option: A function that applies the provided HTTPClientConfig to the Client.
 func WithHTTPConfig(config util.HTTPClientConfig) Option {
 	return func(c *Client) {
-		currentHeaders := c.httpClient.GetBaseHeaders()
-		if len(currentHeaders) > 0 {
-			if config.BaseHeaders == nil {
-				config.BaseHeaders = make(map[string]string)
-			}
-			for k, v := range currentHeaders {
-				config.BaseHeaders[k] = v
-			}
+		const option = "WithHTTPConfig"
+		if config.MaxRequestTimeout != 0 {
+			c.claim("timeout", option)
+			c.timeout = config.MaxRequestTimeout
 		}
-
-		c.httpClient = util.NewHTTPClient(config)
+		if config.RequestsPerSecond != 0 {
+			c.claim("rate limit", option)
+			c.config.RateLimit.RequestsPerMinute = config.RequestsPerSecond
+			c.config.RateLimit.Enabled = true
+		}
+		if config.MaxRetries != 0 || config.RetryWaitTime != 0 {
+			c.claim("retries", option)
+			c.config.RetryConfig.MaxRetries = config.MaxRetries
+			c.config.RetryConfig.RetryDelay = config.RetryWaitTime
+		}
+		if config.MaxRetryAfter != 0 {
+			c.claim("longest Retry-After", option)
+			c.config.RetryConfig.MaxRetryAfter = config.MaxRetryAfter
+		}
+		if config.MaxRetryElapsed != 0 {
+			c.claim("retry budget", option)
+			c.config.RetryConfig.MaxRetryElapsed = config.MaxRetryElapsed
+		}
+		if config.RateLimiter != nil {
+			c.claim("rate limiter", option)
+			c.rateLimiter = config.RateLimiter
+		}
+		if config.OnRetry != nil {
+			WithOnRetry(config.OnRetry)(c)
+		}
+		WithBaseHeaders(config.BaseHeaders)(c)
 	}
 }
 
 // WithTimeout returns an Option that sets the maximum request timeout for the HTTP client.
 // The timeout parameter specifies the duration to wait before timing out a request.
-// This function updates the client's HTTP client configuration with the provided timeout value.
+// NewClientE rejects timeouts that are not positive.
 //
 // Parameters:
 //   - timeout: The maximum duration to wait before timing out a request.
@@ -58,22 +82,13 @@ func WithHTTPConfig(config util.HTTPClientConfig) Option {
 //   - Option: A function that modifies the client's HTTP client configuration.
 func WithTimeout(timeout time.Duration) Option {
 	return func(c *Client) {
-		currentHeaders := c.httpClient.GetBaseHeaders()
-
-		config := util.HTTPClientConfig{
-			MaxRequestTimeout: timeout,
-			RequestsPerSecond: c.config.RateLimit.RequestsPerMinute,
-			MaxRetries:        c.config.RetryConfig.MaxRetries,
-			RetryWaitTime:     c.config.RetryConfig.RetryDelay,
-			BaseHeaders:       currentHeaders,
-		}
-
-		c.httpClient = util.NewHTTPClient(config)
+		c.claim("timeout", "WithTimeout")
+		c.timeout = timeout
 	}
 }
 
 // WithRetryConfig sets the retry configuration for the client, including the maximum number of retries
-// and the wait time between retries. It also updates the HTTP client configuration with the new retry settings.
+// and the wait time between retries. Zero retries disables retrying.
 //
 // Parameters:
 //   - maxRetries: The maximum number of retry attempts.
@@ -83,25 +98,16 @@ func WithTimeout(timeout time.Duration) Option {
 //   - Option: A function that applies the retry configuration to the client.
 func WithRetryConfig(maxRetries int, retryWaitTime time.Duration) Option {
 	return func(c *Client) {
+		c.claim("retries", "WithRetryConfig")
 		c.config.RetryConfig.MaxRetries = maxRetries
 		c.config.RetryConfig.RetryDelay = retryWaitTime
-
-		currentHeaders := c.httpClient.GetBaseHeaders()
-		config := util.HTTPClientConfig{
-			MaxRequestTimeout: c.httpClient.GetClient().ReadTimeout,
-			RequestsPerSecond: c.config.RateLimit.RequestsPerMinute,
-			MaxRetries:        maxRetries,
-			RetryWaitTime:     retryWaitTime,
-			BaseHeaders:       currentHeaders,
-		}
-
-		c.httpClient = util.NewHTTPClient(config)
 	}
 }
 
 // WithRateLimit sets the rate limit for the client in requests per minute.
-// It updates the client's configuration to enable rate limiting and adjusts
-// the HTTP client settings accordingly.
+// It updates the client's configuration to enable rate limiting. NewClientE rejects
+// rates that are not positive, and the option together with WithRateLimiter, whose
+// limiter replaces the built-in one.
 //
 // Parameters:
 //   - requestsPerMinute: The number of requests allowed per minute.
@@ -110,39 +116,142 @@ func WithRetryConfig(maxRetries int, retryWaitTime time.Duration) Option {
 //   - Option: A function that modifies the client's configuration to apply the rate limit.
 func WithRateLimit(requestsPerMinute int) Option {
 	return func(c *Client) {
+		c.claim("rate limit", "WithRateLimit")
 		c.config.RateLimit.RequestsPerMinute = requestsPerMinute
 		c.config.RateLimit.Enabled = true
-
-		currentHeaders := c.httpClient.GetBaseHeaders()
-		config := util.HTTPClientConfig{
-			MaxRequestTimeout: c.httpClient.GetClient().ReadTimeout,
-			RequestsPerSecond: requestsPerMinute,
-			MaxRetries:        c.config.RetryConfig.MaxRetries,
-			RetryWaitTime:     c.config.RetryConfig.RetryDelay,
-			BaseHeaders:       currentHeaders,
-		}
-
-		c.httpClient = util.NewHTTPClient(config)
 	}
 }
 
 // WithBaseHeaders returns an Option that sets the base headers for the HTTP client.
-// It takes a map of headers as input and merges them with the existing base headers
-// of the client's HTTP client.
+// It takes a map of headers as input and merges them with the base headers set by
+// other options; the headers of later options win.
 //
 // headers: A map where the key is the header name and the value is the header value.
 //
 // Example usage:
 //
-//	client := NewClient()
-//	client.ApplyOptions(WithBaseHeaders(map[string]string{"Authorization": "Bearer token"}))
+//	client := NewClient(apiKey, WithBaseHeaders(map[string]string{"X-Tenant": "acme"}))
 func WithBaseHeaders(headers map[string]string) Option {
 	return func(c *Client) {
-		currentHeaders := c.httpClient.GetBaseHeaders()
+		if len(headers) == 0 {
+			return
+		}
+		if c.headers == nil {
+			c.headers = make(map[string]string)
+		}
 		for k, v := range headers {
-			currentHeaders[k] = v
+			c.headers[k] = v
 		}
+	}
+}
 
-		c.httpClient.SetBaseHeaders(currentHeaders)
+// WithOnRetry registers a hook that is called before every retry attempt with the
+// attempt number (starting at 1), the error that triggered the retry and the delay
+// before the next attempt. Applications can use it to log or count retries, or to
+// show a "retrying…" state. Returning a non-nil error aborts the retry loop and the
+// request fails with that error. Multiple hooks are called in registration order.
+//
+// Example usage:
+//
+//	client := NewClient(apiKey, WithOnRetry(func(attempt int, err error, delay time.Duration) error {
+//	    log.Printf("retry %d in %v: %v", attempt, delay, err)
+//	    return nil
+//	}))
+func WithOnRetry(hook func(attempt int, err error, delay time.Duration) error) Option {
+	return func(c *Client) {
+		previous := c.config.RetryConfig.OnRetry
+		combined := hook
+		if previous != nil {
+			combined = func(attempt int, err error, delay time.Duration) error {
+				if err := previous(attempt, err, delay); err != nil {
+					return err
+				}
+				return hook(attempt, err, delay)
+			}
+		}
+
+		c.config.RetryConfig.OnRetry = combined
+	}
+}
+
+// WithMaxRetryAfter sets the longest wait requested by a Retry-After or rate limit reset
+// header that the client honours before retrying a 429 or 503 response. When the server
+// asks for a longer wait, the request fails immediately with the response's error instead
+// of blocking, and RetryAfter reports how long the server asked to wait. The default is
+// one minute.
+//
+// Parameters:
+//   - d: The longest wait to honour; zero restores the default.
+//
+// Returns:
+//   - Option: A function that applies the limit to the client.
+func WithMaxRetryAfter(d time.Duration) Option {
+	return func(c *Client) {
+		c.claim("longest Retry-After", "WithMaxRetryAfter")
+		c.config.RetryConfig.MaxRetryAfter = d
+	}
+}
+
+// WithRequestCompression gzips request bodies of at least minSize bytes, which saves
+// bandwidth for long-context prompts on slow links. Responses are always requested
+// compressed and decoded transparently; this option only concerns requests, and only
+// chat and other JSON requests, as audio files are compressed already.
+//
+// Example usage:
+//
+//	client := NewClient(apiKey, WithRequestCompression(64<<10))
+//
+// Parameters:
+//   - minSize: The smallest body in bytes to compress; 0 disables compression.
+//
+// Returns:
+//   - Option: A function that enables request compression.
+func WithRequestCompression(minSize int) Option {
+	return func(c *Client) {
+		c.compressMinSize = minSize
+	}
+}
+
+// claim records that option sets a setting. A setting made by two different options is a
+// conflict, as the result would depend on the order of the options; NewClientE reports it.
+func (c *Client) claim(setting, option string) {
+	if c.optionSources == nil {
+		c.optionSources = make(map[string]string)
+	}
+	if previous, ok := c.optionSources[setting]; ok && previous != option {
+		c.optionConflicts = append(c.optionConflicts, fmt.Sprintf("%s and %s both set the %s; use only one of them", previous, option, setting))
+	}
+	c.optionSources[setting] = option
+}
+
+// httpClientConfig returns the configuration of the HTTP client composed from the settings
+// of all options, whatever their order.
+func (c *Client) httpClientConfig(apiKey string) util.HTTPClientConfig {
+	headers := map[string]string{
+		"Authorization": fmt.Sprintf("Bearer %s", apiKey),
+		"Content-Type":  "application/json",
+		"User-Agent":    c.userAgentHeader(),
+	}
+	for k, v := range c.headers {
+		headers[k] = v
+	}
+
+	config := util.HTTPClientConfig{
+		MaxRequestTimeout: defaultTimeout,
+		RequestsPerSecond: 10,
+		MaxRetries:        c.config.RetryConfig.MaxRetries,
+		RetryWaitTime:     c.config.RetryConfig.RetryDelay,
+		BaseHeaders:       headers,
+		OnRetry:           c.config.RetryConfig.OnRetry,
+		MaxRetryAfter:     c.config.RetryConfig.MaxRetryAfter,
+		MaxRetryElapsed:   c.config.RetryConfig.MaxRetryElapsed,
+		RateLimiter:       c.rateLimiter,
+	}
+	if c.timeout > 0 {
+		config.MaxRequestTimeout = c.timeout
+	}
+	if c.config.RateLimit.Enabled && c.config.RateLimit.RequestsPerMinute > 0 {
+		config.RequestsPerSecond = c.config.RateLimit.RequestsPerMinute
 	}
+	return config
 }
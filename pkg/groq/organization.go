@@ -0,0 +1,80 @@
+package groq
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Headers selecting the organization and project a request is made for and billed to.
+const (
+	HeaderOrganization = "Groq-Organization"
+	HeaderProject      = "Groq-Project"
+)
+
+// WithOrganization sends every request on behalf of an organization, for API keys that
+// belong to several Groq organizations. WithRequestHeader(HeaderOrganization, id) selects
+// another organization for a single call.
+//
+// Example usage:
+//
+//	client := NewClient(apiKey, WithOrganization("org_01hq..."))
+//
+// Parameters:
+//   - id: The organization ID; empty sends no header.
+//
+// Returns:
+//   - Option: A function that sets the organization.
+func WithOrganization(id string) Option {
+	return func(c *Client) {
+		c.organization = id
+	}
+}
+
+// WithProject bills every request to a project of the organization, to track usage and
+// costs per project. WithRequestHeader(HeaderProject, id) selects another project for a
+// single call.
+//
+// Example usage:
+//
+//	client := NewClient(apiKey, WithOrganization("org_01hq..."), WithProject("proj_01hr..."))
+//
+// Parameters:
+//   - id: The project ID; empty sends no header.
+//
+// Returns:
+//   - Option: A function that sets the project.
+func WithProject(id string) Option {
+	return func(c *Client) {
+		c.project = id
+	}
+}
+
+// applyAccountHeaders adds the organization and project headers to the base headers, after
+// all options ran so options replacing the HTTP client do not drop them.
+func (c *Client) applyAccountHeaders() {
+	if c.organization == "" && c.project == "" {
+		return
+	}
+	headers := c.httpClient.GetBaseHeaders()
+	if c.organization != "" {
+		headers[HeaderOrganization] = c.organization
+	}
+	if c.project != "" {
+		headers[HeaderProject] = c.project
+	}
+	c.httpClient.SetBaseHeaders(headers)
+}
+
+// accountProblems returns the problems of the organization and project IDs.
+func (c *Client) accountProblems() []string {
+	var problems []string
+	for _, id := range []struct{ name, value string }{
+		{"organization", c.organization},
+		{"project", c.project},
+	} {
+		if strings.ContainsAny(id.value, " \t\r\n") {
+			problems = append(problems, fmt.Sprintf("%s ID %q contains whitespace", id.name, id.value))
+		}
+	}
+	return problems
+}
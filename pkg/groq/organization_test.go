@@ -0,0 +1,49 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithOrganizationAndProject(t *testing.T) {
+	var header http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","choices":[{"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}]}`))
+	}))
+	defer srv.Close()
+
+	// WithTimeout after the options replaces the HTTP client, which must keep the headers.
+	client := NewClient("test-key", WithBaseURL(srv.URL), WithOrganization("org_1"), WithProject("proj_1"), WithTimeout(time.Second))
+	defer client.Close()
+
+	req := NewRequest(ModelLlama31_8bInstant).User("hi").Build()
+	if _, err := client.CreateChatCompletion(context.Background(), req); err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if got := header.Get(HeaderOrganization); got != "org_1" {
+		t.Errorf("%s = %q, want org_1", HeaderOrganization, got)
+	}
+	if got := header.Get(HeaderProject); got != "proj_1" {
+		t.Errorf("%s = %q, want proj_1", HeaderProject, got)
+	}
+
+	if _, err := client.CreateChatCompletion(context.Background(), req, WithRequestHeader(HeaderProject, "proj_2")); err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if got := header.Get(HeaderProject); got != "proj_2" {
+		t.Errorf("%s = %q, want the project of the call", HeaderProject, got)
+	}
+}
+
+func TestWithOrganizationValidation(t *testing.T) {
+	_, err := NewClientE("test-key", WithOrganization("org_1\n"))
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("expected ErrInvalidConfig, got %v", err)
+	}
+}
@@ -6,13 +6,15 @@ import (
 )
 
 type ParallelResponse struct {
-	Response *ChatCompletionResponse
-	Error    error
-	Index    int
+	Response    *ChatCompletionResponse
+	Error       error
+	Index       int
+	Annotations map[string]string // Annotations of the request, merged over those of ctx
 }
 
 // CreateParallelCompletions sends multiple chat completion requests in parallel and returns their responses.
-// It respects the rate limit configuration of the client.
+// It respects the rate limit configuration of the client. Requests still waiting for a
+// rate limit slot when ctx is done are not sent and report ctx.Err().
 //
 // Parameters:
 //   - ctx: The context to control cancellation and timeout.
@@ -32,15 +34,21 @@ func (c *Client) CreateParallelCompletions(ctx context.Context, requests []*Chat
 			defer wg.Done()
 
 			if c.config.RateLimit.Enabled {
-				rateLimiter <- struct{}{}
-				defer func() { <-rateLimiter }()
+				select {
+				case rateLimiter <- struct{}{}:
+					defer func() { <-rateLimiter }()
+				case <-ctx.Done():
+					responses[index] = ParallelResponse{Error: ctx.Err(), Index: index, Annotations: requestAnnotations(ctx, request)}
+					return
+				}
 			}
 
 			resp, err := c.CreateChatCompletion(ctx, request)
 			responses[index] = ParallelResponse{
-				Response: resp,
-				Error:    err,
-				Index:    index,
+				Response:    resp,
+				Error:       err,
+				Index:       index,
+				Annotations: requestAnnotations(ctx, request),
 			}
 		}(i, req)
 	}
@@ -78,6 +86,7 @@ func (c *Client) NewBatchProcessor(batchSize, maxParallel int) *BatchProcessor {
 // ProcessBatch processes a batch of ChatCompletionRequest objects in parallel.
 // It divides the requests into smaller batches based on the batchSize of the BatchProcessor,
 // sends them to the client for parallel processing, and collects the responses.
+// Once ctx is done, the remaining batches are not started and their responses carry ctx.Err().
 //
 // Parameters:
 //   - ctx: The context for controlling the request lifetime.
@@ -95,6 +104,17 @@ func (bp *BatchProcessor) ProcessBatch(ctx context.Context, requests []*ChatComp
 			end = len(requests)
 		}
 
+		if err := ctx.Err(); err != nil {
+			for j := i; j < len(requests); j++ {
+				totalResponses = append(totalResponses, ParallelResponse{
+					Error:       err,
+					Index:       (j - i) % bp.batchSize,
+					Annotations: requestAnnotations(ctx, requests[j]),
+				})
+			}
+			break
+		}
+
 		batch := requests[i:end]
 		responses := bp.client.CreateParallelCompletions(ctx, batch)
 		totalResponses = append(totalResponses, responses...)
@@ -0,0 +1,261 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/genc-murat/groq-client/pkg/groq"
+)
+
+var (
+	ErrNoTranscriber = errors.New("pipeline: no transcriber configured")
+	ErrNoResponder   = errors.New("pipeline: no responder configured")
+)
+
+type Models struct {
+	Transcription groq.ModelType
+	Chat          groq.ModelType
+	Speech        groq.ModelType
+}
+
+// DefaultModels returns fast defaults for voice assistants: Whisper Large v3 Turbo for
+// speech recognition, Llama 3.3 70B for the answer and PlayAI for speech.
+func DefaultModels() Models {
+	return Models{
+		Transcription: groq.ModelWhisperLargeV3Turbo,
+		Chat:          groq.ModelLlama33_70bVersatile,
+		Speech:        groq.ModelPlayAITTS,
+	}
+}
+
+type Turn struct {
+	Transcript string   // What the user said
+	Reply      string   // The complete answer
+	Audio      [][]byte // The spoken answer, one segment per synthesis request
+}
+
+type Pipeline struct {
+	Transcriber Transcriber
+	Responder   Responder
+	Synthesizer Synthesizer // Optional; without it turns are answered in text only
+
+	System     string // Optional system prompt sent before the history
+	MaxHistory int    // Maximum number of history messages sent, 0 for all
+
+	// OnTranscript is called with the transcript before the answer is generated.
+	OnTranscript func(text string)
+	// OnDelta is called with every piece of the answer as it streams in.
+	OnDelta func(delta string) error
+	// OnAudio, if set, makes the pipeline speak the answer sentence by sentence while it is
+	// still being generated and deliver each segment as soon as it is synthesized, so
+	// playback can start before the model has finished.
+	OnAudio func(segment []byte) error
+
+	history []groq.ChatMessage
+	mu      sync.Mutex
+}
+
+// New creates a speech pipeline that transcribes the user's audio, streams an answer from
+// the chat model and speaks it, using client for all three stages. Each stage can be
+// replaced by a custom implementation, for example a local speech recognizer or a
+// Responder that calls tools.
+//
+// Example usage:
+//
+//	p := pipeline.New(client, pipeline.DefaultModels())
+//	p.System = "You are a concise voice assistant."
+//	p.OnAudio = player.Play
+//	turn, err := p.Turn(ctx, mic, "question.wav")
+//
+// Parameters:
+//   - client: The client used by the default stages.
+//   - models: The model of each stage.
+//
+// Returns:
+//   - *Pipeline: The pipeline with an empty history.
+func New(client groq.API, models Models) *Pipeline {
+	return &Pipeline{
+		Transcriber: &Transcription{Client: client, Model: models.Transcription},
+		Responder:   &Chat{Client: client, Model: models.Chat},
+		Synthesizer: &Speech{Client: client, Model: models.Speech},
+	}
+}
+
+// Turn runs one exchange: the audio is transcribed, answered and the answer spoken.
+// The transcript and the answer are added to the history only if the turn succeeds.
+//
+// Parameters:
+//   - ctx: Context for all stages.
+//   - audio: The user's recorded speech.
+//   - fileName: Name of the recording with an extension identifying its format.
+//
+// Returns:
+//   - *Turn: The transcript, answer and audio.
+//   - error: The error of the first failing stage.
+func (p *Pipeline) Turn(ctx context.Context, audio io.Reader, fileName string) (*Turn, error) {
+	if p.Transcriber == nil {
+		return nil, ErrNoTranscriber
+	}
+	if p.Responder == nil {
+		return nil, ErrNoResponder
+	}
+
+	transcript, err := p.Transcriber.Transcribe(ctx, audio, fileName)
+	if err != nil {
+		return nil, fmt.Errorf("transcription stage failed: %w", err)
+	}
+	return p.Ask(ctx, transcript)
+}
+
+// Ask runs an exchange for text the user typed or that was transcribed elsewhere,
+// skipping the transcription stage. It returns ErrNoResponder if Responder is nil.
+func (p *Pipeline) Ask(ctx context.Context, text string) (*Turn, error) {
+	if p.Responder == nil {
+		return nil, ErrNoResponder
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	turn := &Turn{Transcript: strings.TrimSpace(text)}
+	if p.OnTranscript != nil {
+		p.OnTranscript(turn.Transcript)
+	}
+
+	user := groq.ChatMessage{Role: "user", Content: turn.Transcript}
+	messages := p.messages(user)
+
+	var pending strings.Builder
+	reply, err := p.Responder.Respond(ctx, messages, func(delta string) error {
+		if p.OnDelta != nil {
+			if err := p.OnDelta(delta); err != nil {
+				return err
+			}
+		}
+		if p.OnAudio == nil || p.Synthesizer == nil {
+			return nil
+		}
+
+		pending.WriteString(delta)
+		done, rest := splitSentences(pending.String())
+		if done == "" {
+			return nil
+		}
+		pending.Reset()
+		pending.WriteString(rest)
+		return p.speak(ctx, turn, done)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("chat stage failed: %w", err)
+	}
+	turn.Reply = reply
+
+	if p.Synthesizer != nil {
+		remaining := reply
+		if p.OnAudio != nil {
+			remaining = pending.String()
+		}
+		if err := p.speak(ctx, turn, remaining); err != nil {
+			return nil, err
+		}
+	}
+
+	p.history = append(p.history, user, groq.ChatMessage{Role: "assistant", Content: reply})
+	return turn, nil
+}
+
+// History returns a copy of the conversation so far.
+func (p *Pipeline) History() []groq.ChatMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]groq.ChatMessage(nil), p.history...)
+}
+
+// Reset clears the conversation history.
+func (p *Pipeline) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.history = nil
+}
+
+// messages builds the request messages for a new user message.
+func (p *Pipeline) messages(user groq.ChatMessage) []groq.ChatMessage {
+	history := p.history
+	if p.MaxHistory > 0 && len(history) > p.MaxHistory {
+		history = history[len(history)-p.MaxHistory:]
+	}
+
+	messages := make([]groq.ChatMessage, 0, len(history)+2)
+	if p.System != "" {
+		messages = append(messages, groq.ChatMessage{Role: "system", Content: p.System})
+	}
+	messages = append(messages, history...)
+	return append(messages, user)
+}
+
+// speak synthesizes text in segments that fit a speech request and records them in turn.
+func (p *Pipeline) speak(ctx context.Context, turn *Turn, text string) error {
+	for _, segment := range chunkText(text, groq.MaxSpeechInputLength) {
+		audio, err := p.Synthesizer.Synthesize(ctx, segment)
+		if err != nil {
+			return fmt.Errorf("speech stage failed: %w", err)
+		}
+		turn.Audio = append(turn.Audio, audio)
+		if p.OnAudio != nil {
+			if err := p.OnAudio(audio); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// splitSentences splits text after its last complete sentence. A sentence ends with
+// '.', '!', '?' or a newline followed by whitespace.
+func splitSentences(text string) (done, rest string) {
+	for i := len(text) - 2; i >= 0; i-- {
+		if strings.IndexByte(".!?\n", text[i]) >= 0 && unicode.IsSpace(rune(text[i+1])) {
+			return strings.TrimSpace(text[:i+1]), text[i+1:]
+		}
+	}
+	return "", text
+}
+
+// chunkText splits text into pieces of at most limit characters, preferring sentence
+// and then word boundaries.
+func chunkText(text string, limit int) []string {
+	text = strings.TrimSpace(text)
+	var chunks []string
+	for utf8.RuneCountInString(text) > limit {
+		cut := runeOffset(text, limit)
+		head := text[:cut]
+		if done, _ := splitSentences(head + " "); done != "" {
+			cut = len(done)
+		} else if i := strings.LastIndexFunc(head, unicode.IsSpace); i > 0 {
+			cut = i
+		}
+		chunks = append(chunks, strings.TrimSpace(text[:cut]))
+		text = strings.TrimSpace(text[cut:])
+	}
+	if text != "" {
+		chunks = append(chunks, text)
+	}
+	return chunks
+}
+
+// runeOffset returns the byte offset of the n-th rune of s.
+func runeOffset(s string, n int) int {
+	for i := range s {
+		if n == 0 {
+			return i
+		}
+		n--
+	}
+	return len(s)
+}
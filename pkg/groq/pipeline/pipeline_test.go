@@ -0,0 +1,154 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/genc-murat/groq-client/pkg/groq"
+)
+
+func TestPipelineTurn(t *testing.T) {
+	var spoken []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/audio/transcriptions":
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if got := r.FormValue("model"); got != string(groq.ModelWhisperLargeV3Turbo) {
+				t.Errorf("transcription model = %q", got)
+			}
+			fmt.Fprint(w, `{"text":" What is Groq? "}`)
+		case "/chat/completions":
+			var req groq.ChatCompletionRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			if len(req.Messages) != 2 || req.Messages[0].Role != "system" || req.Messages[1].Content != "What is Groq?" {
+				t.Errorf("unexpected messages: %+v", req.Messages)
+			}
+			w.Header().Set("Content-Type", "text/event-stream")
+			for _, delta := range []string{"Groq is fast", ". It runs", " LPUs!", " Bye"} {
+				fmt.Fprintf(w, "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":%q}}]}\n\n", delta)
+			}
+			fmt.Fprint(w, "data: [DONE]\n\n")
+		case "/audio/speech":
+			var req groq.SpeechRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			spoken = append(spoken, req.Input)
+			fmt.Fprint(w, "audio:"+req.Input)
+		}
+	}))
+	defer srv.Close()
+
+	p := New(groq.NewClient("test-key", groq.WithBaseURL(srv.URL)), DefaultModels())
+	p.System = "Be brief."
+
+	var segments []string
+	p.OnAudio = func(segment []byte) error {
+		segments = append(segments, string(segment))
+		return nil
+	}
+
+	turn, err := p.Turn(context.Background(), strings.NewReader("RIFF....WAVE"), "q.wav")
+	if err != nil {
+		t.Fatalf("Turn() error = %v", err)
+	}
+	if turn.Transcript != "What is Groq?" || turn.Reply != "Groq is fast. It runs LPUs! Bye" {
+		t.Errorf("unexpected turn: %+v", turn)
+	}
+
+	want := []string{"Groq is fast.", "It runs LPUs!", "Bye"}
+	if strings.Join(spoken, "|") != strings.Join(want, "|") {
+		t.Errorf("spoken segments = %q, want %q", spoken, want)
+	}
+	if len(segments) != 3 || len(turn.Audio) != 3 || segments[2] != "audio:Bye" {
+		t.Errorf("unexpected audio: %q", segments)
+	}
+	if h := p.History(); len(h) != 2 || h[1].Content != turn.Reply {
+		t.Errorf("unexpected history: %+v", h)
+	}
+}
+
+func TestPipelineCustomStages(t *testing.T) {
+	p := &Pipeline{
+		Responder: ResponderFunc(func(ctx context.Context, messages []groq.ChatMessage, onDelta func(string) error) (string, error) {
+			return fmt.Sprintf("%d messages", len(messages)), nil
+		}),
+		MaxHistory: 2,
+	}
+
+	if _, err := p.Turn(context.Background(), strings.NewReader(""), "a.wav"); err != ErrNoTranscriber {
+		t.Fatalf("Turn() error = %v, want ErrNoTranscriber", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := p.Ask(context.Background(), "hi"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	turn, err := p.Ask(context.Background(), "hi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if turn.Reply != "3 messages" || turn.Audio != nil {
+		t.Errorf("unexpected turn: %+v", turn)
+	}
+
+	p.Responder = nil
+	if _, err := p.Ask(context.Background(), "hi"); err != ErrNoResponder {
+		t.Errorf("Ask() error = %v, want ErrNoResponder", err)
+	}
+}
+
+// fakeAPI answers the calls of the default stages without a server.
+type fakeAPI struct {
+	groq.API
+}
+
+func (fakeAPI) CreateTranscription(ctx context.Context, req *groq.TranscriptionRequest, opts ...groq.RequestOption) (*groq.TranscriptionResponse, error) {
+	return &groq.TranscriptionResponse{Text: "hello"}, nil
+}
+
+func (fakeAPI) CreateChatCompletionStream(ctx context.Context, req *groq.ChatCompletionRequest, handler groq.StreamHandler, opts ...groq.RequestOption) error {
+	var chunk groq.ChatCompletionChunk
+	if err := json.Unmarshal([]byte(`{"choices":[{"delta":{"content":"hi there"}}]}`), &chunk); err != nil {
+		return err
+	}
+	return handler(&chunk)
+}
+
+func TestPipelineFakeAPI(t *testing.T) {
+	p := New(fakeAPI{}, Models{Transcription: groq.ModelWhisperLargeV3Turbo, Chat: groq.ModelLlama31_8bInstant})
+	p.Synthesizer = nil
+
+	turn, err := p.Turn(context.Background(), strings.NewReader("audio"), "q.wav")
+	if err != nil {
+		t.Fatalf("Turn: %v", err)
+	}
+	if turn.Transcript != "hello" || turn.Reply != "hi there" {
+		t.Errorf("turn = %+v", turn)
+	}
+}
+
+func TestChunkText(t *testing.T) {
+	text := strings.Repeat("One sentence here. ", 10) + strings.Repeat("word ", 20)
+	chunks := chunkText(text, 45)
+
+	for _, c := range chunks {
+		if utf8.RuneCountInString(c) > 45 {
+			t.Errorf("chunk longer than limit: %q", c)
+		}
+	}
+	if chunks[0] != "One sentence here. One sentence here." {
+		t.Errorf("first chunk = %q, want a sentence boundary", chunks[0])
+	}
+	if strings.Join(strings.Fields(strings.Join(chunks, " ")), " ") != strings.Join(strings.Fields(text), " ") {
+		t.Error("chunks lost text")
+	}
+}
@@ -0,0 +1,117 @@
+package pipeline
+
+import (
+	"context"
+	"io"
+
+	"github.com/genc-murat/groq-client/pkg/groq"
+)
+
+// Transcriber turns the user's audio into text.
+type Transcriber interface {
+	Transcribe(ctx context.Context, audio io.Reader, fileName string) (string, error)
+}
+
+// Responder answers the conversation, calling onDelta with each piece of the reply as
+// it is generated, and returns the complete reply.
+type Responder interface {
+	Respond(ctx context.Context, messages []groq.ChatMessage, onDelta func(delta string) error) (string, error)
+}
+
+// Synthesizer speaks a piece of text and returns the encoded audio.
+type Synthesizer interface {
+	Synthesize(ctx context.Context, text string) ([]byte, error)
+}
+
+// TranscriberFunc adapts an ordinary function to the Transcriber interface.
+type TranscriberFunc func(ctx context.Context, audio io.Reader, fileName string) (string, error)
+
+// Transcribe calls f(ctx, audio, fileName).
+func (f TranscriberFunc) Transcribe(ctx context.Context, audio io.Reader, fileName string) (string, error) {
+	return f(ctx, audio, fileName)
+}
+
+// ResponderFunc adapts an ordinary function to the Responder interface.
+type ResponderFunc func(ctx context.Context, messages []groq.ChatMessage, onDelta func(delta string) error) (string, error)
+
+// Respond calls f(ctx, messages, onDelta).
+func (f ResponderFunc) Respond(ctx context.Context, messages []groq.ChatMessage, onDelta func(delta string) error) (string, error) {
+	return f(ctx, messages, onDelta)
+}
+
+// SynthesizerFunc adapts an ordinary function to the Synthesizer interface.
+type SynthesizerFunc func(ctx context.Context, text string) ([]byte, error)
+
+// Synthesize calls f(ctx, text).
+func (f SynthesizerFunc) Synthesize(ctx context.Context, text string) ([]byte, error) {
+	return f(ctx, text)
+}
+
+type Transcription struct {
+	Client   groq.API
+	Model    groq.ModelType
+	Language string // Optional ISO-639-1 language of the audio
+	Prompt   string // Optional text guiding spelling and style
+}
+
+// Transcribe implements Transcriber with CreateTranscription.
+func (t *Transcription) Transcribe(ctx context.Context, audio io.Reader, fileName string) (string, error) {
+	resp, err := t.Client.CreateTranscription(ctx, &groq.TranscriptionRequest{
+		File:     audio,
+		FileName: fileName,
+		Model:    t.Model,
+		Language: t.Language,
+		Prompt:   t.Prompt,
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.Text, nil
+}
+
+type Chat struct {
+	Client      groq.API
+	Model       groq.ModelType
+	MaxTokens   int
+	Temperature *float64 // nil uses the API default
+}
+
+// Respond implements Responder with CreateChatCompletionStream.
+func (c *Chat) Respond(ctx context.Context, messages []groq.ChatMessage, onDelta func(delta string) error) (string, error) {
+	req := &groq.ChatCompletionRequest{
+		Model:       c.Model,
+		Messages:    messages,
+		MaxTokens:   c.MaxTokens,
+		Temperature: c.Temperature,
+	}
+
+	var reply []byte
+	err := c.Client.CreateChatCompletionStream(ctx, req, func(chunk *groq.ChatCompletionChunk) error {
+		if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+			return nil
+		}
+		delta := chunk.Choices[0].Delta.Content
+		reply = append(reply, delta...)
+		return onDelta(delta)
+	})
+	return string(reply), err
+}
+
+type Speech struct {
+	Client groq.API
+	Model  groq.ModelType
+	Voice  groq.Voice        // Defaults to the model's default voice
+	Format groq.SpeechFormat // Defaults to wav
+	Speed  float64
+}
+
+// Synthesize implements Synthesizer with CreateSpeech.
+func (s *Speech) Synthesize(ctx context.Context, text string) ([]byte, error) {
+	return s.Client.CreateSpeech(ctx, &groq.SpeechRequest{
+		Model:          s.Model,
+		Input:          text,
+		Voice:          s.Voice,
+		ResponseFormat: s.Format,
+		Speed:          s.Speed,
+	})
+}
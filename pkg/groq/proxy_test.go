@@ -0,0 +1,102 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithProxy(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","choices":[{"message":{"role":"assistant","content":"pong"},"finish_reason":"stop"}]}`))
+	}))
+	defer srv.Close()
+
+	var proxied int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Proxy-Authorization") == "" {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		atomic.AddInt32(&proxied, 1)
+		if r.Method != http.MethodConnect {
+			// net/http sends plain http requests to the proxy instead of tunnelling them.
+			req, _ := http.NewRequest(r.Method, r.URL.String(), r.Body)
+			req.Header = r.Header.Clone()
+			resp, err := http.DefaultTransport.RoundTrip(req)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadGateway)
+				return
+			}
+			defer resp.Body.Close()
+			w.WriteHeader(resp.StatusCode)
+			_, _ = io.Copy(w, resp.Body)
+			return
+		}
+		target, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		go func() {
+			_, _ = io.Copy(target, conn)
+			target.Close()
+		}()
+		go func() {
+			_, _ = io.Copy(conn, target)
+			conn.Close()
+		}()
+	}))
+	defer proxy.Close()
+	proxyURL := strings.Replace(proxy.URL, "http://", "http://user:pass@", 1)
+
+	for name, opts := range map[string][]Option{
+		"fasthttp": {WithProxy(proxyURL)},
+		"net/http": {WithProxy(proxyURL), WithHTTPTransport(nil)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			before := atomic.LoadInt32(&proxied)
+			client := NewClient("test-key", append(opts, WithBaseURL(srv.URL))...)
+			defer client.Close()
+
+			resp, err := client.CreateChatCompletion(context.Background(), NewRequest(ModelLlama31_8bInstant).User("ping").Build())
+			if err != nil {
+				t.Fatalf("CreateChatCompletion: %v", err)
+			}
+			if resp.Choices[0].Message.Content != "pong" {
+				t.Errorf("unexpected response: %+v", resp)
+			}
+			if atomic.LoadInt32(&proxied) == before {
+				t.Error("expected the request to go through the proxy")
+			}
+		})
+	}
+}
+
+func TestWithProxyInvalid(t *testing.T) {
+	_, err := NewClientE("test-key", WithProxy("ftp://proxy.example"))
+	if !errors.Is(err, ErrInvalidConfig) || !strings.Contains(err.Error(), "scheme") {
+		t.Errorf("expected a config error about the scheme, got %v", err)
+	}
+
+	// A bad proxy must not fall back to direct connections.
+	client := NewClient("test-key", WithBaseURL("http://127.0.0.1:1"), WithProxy("://user:secret@"), WithRetryConfig(1, time.Millisecond))
+	defer client.Close()
+	_, err = client.CreateChatCompletion(context.Background(), NewRequest(ModelLlama31_8bInstant).User("ping").Build())
+	if err == nil || !strings.Contains(err.Error(), "proxy URL is invalid") {
+		t.Errorf("expected the invalid proxy URL to fail the request, got %v", err)
+	}
+	if err != nil && strings.Contains(err.Error(), "secret") {
+		t.Errorf("error leaks the proxy password: %v", err)
+	}
+}
@@ -0,0 +1,157 @@
+package groq
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/genc-murat/groq-client/internal/util"
+)
+
+type (
+	// RateLimiter decides when the next request may be sent; see WithRateLimiter.
+	RateLimiter = util.RateLimiter
+	// RedisEvalFunc runs a Lua script on Redis like EVAL; see NewRedisRateLimiter.
+	RedisEvalFunc = util.RedisEvalFunc
+	// RedisRateLimiter is a RateLimiter shared through Redis.
+	RedisRateLimiter = util.RedisRateLimiter
+)
+
+type Endpoint string
+
+const (
+	EndpointChat   Endpoint = "chat"   // Chat completions, including streams
+	EndpointAudio  Endpoint = "audio"  // Transcriptions and translations
+	EndpointSpeech Endpoint = "speech" // Text to speech
+)
+
+// WithEndpointRateLimit gives a family of endpoints its own requests-per-minute budget, in
+// addition to the client-wide rate limit, since Groq's published limits differ between
+// chat completions and audio models.
+//
+// Example usage:
+//
+//	client := NewClient(apiKey,
+//	    WithEndpointRateLimit(EndpointChat, 30),
+//	    WithEndpointRateLimit(EndpointAudio, 20),
+//	)
+//
+// Parameters:
+//   - endpoint: The endpoint family.
+//   - requestsPerMinute: The budget of the endpoints; 0 removes it.
+//
+// Returns:
+//   - Option: A function that sets the budget.
+func WithEndpointRateLimit(endpoint Endpoint, requestsPerMinute int) Option {
+	return func(c *Client) {
+		if c.config.RateLimit.EndpointRequestsPerMinute == nil {
+			c.config.RateLimit.EndpointRequestsPerMinute = make(map[Endpoint]int)
+		}
+		c.config.RateLimit.EndpointRequestsPerMinute[endpoint] = requestsPerMinute
+	}
+}
+
+// WithModelRateLimit gives a model its own requests-per-minute budget, applied on top of
+// the budget of its endpoint, e.g. for a large model with a lower limit than the rest of
+// its family.
+//
+// Parameters:
+//   - model: The model.
+//   - requestsPerMinute: The budget of the model; 0 removes it.
+//
+// Returns:
+//   - Option: A function that sets the budget.
+func WithModelRateLimit(model ModelType, requestsPerMinute int) Option {
+	return func(c *Client) {
+		if c.config.RateLimit.ModelRequestsPerMinute == nil {
+			c.config.RateLimit.ModelRequestsPerMinute = make(map[ModelType]int)
+		}
+		c.config.RateLimit.ModelRequestsPerMinute[model] = requestsPerMinute
+	}
+}
+
+// WithRateLimiter replaces the client-wide rate limiter, which by default paces requests
+// in this process only. Use a RedisRateLimiter so that several replicas sharing one API
+// key also share its request budget.
+//
+// Example usage:
+//
+//	limiter := NewRedisRateLimiter(func(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+//	    return rdb.Eval(ctx, script, keys, args...).Result()
+//	}, "groq:requests", 30)
+//	client := NewClient(apiKey, WithRateLimiter(limiter))
+//
+// Parameters:
+//   - limiter: The rate limiter; nil keeps the default.
+//
+// Returns:
+//   - Option: A function that sets the rate limiter.
+func WithRateLimiter(limiter RateLimiter) Option {
+	return func(c *Client) {
+		c.claim("rate limiter", "WithRateLimiter")
+		c.rateLimiter = limiter
+	}
+}
+
+// NewRedisRateLimiter creates a rate limiter whose budget lives in Redis under key, shared
+// by every client using the same key. The Redis client is supplied by the caller through
+// eval, so this package does not depend on a Redis driver.
+//
+// Parameters:
+//   - eval: Runs scripts on the shared Redis server.
+//   - key: The Redis key of the shared budget.
+//   - requestsPerMinute: The shared budget; must be positive.
+//
+// Returns:
+//   - *RedisRateLimiter: The rate limiter, for WithRateLimiter.
+func NewRedisRateLimiter(eval RedisEvalFunc, key string, requestsPerMinute int) *RedisRateLimiter {
+	return util.NewRedisRateLimiter(eval, key, requestsPerMinute)
+}
+
+// waitRequestLimits waits until the request budgets of the endpoint and the model allow
+// one more request.
+func (c *Client) waitRequestLimits(ctx context.Context, endpoint Endpoint, model ModelType) error {
+	started := time.Now()
+	defer func() { traceFrom(ctx).addQueued(time.Since(started)) }()
+
+	limiters := []*util.TokenLimiter{
+		c.limiter("requests:endpoint:"+string(endpoint), c.config.RateLimit.EndpointRequestsPerMinute[endpoint]),
+		c.limiter("requests:model:"+string(model), c.config.RateLimit.ModelRequestsPerMinute[model]),
+	}
+	for i, limiter := range limiters {
+		if limiter == nil {
+			continue
+		}
+		if err := limiter.WaitN(ctx, 1); err != nil {
+			// The request is not sent, so the limits already passed get their budget back.
+			for _, passed := range limiters[:i] {
+				if passed != nil {
+					passed.Adjust(-1)
+				}
+			}
+			return fmt.Errorf("%w: %w", util.ErrRateLimitExceeded, err)
+		}
+	}
+	return nil
+}
+
+// limiter returns the limiter registered under key, creating it on first use with the
+// given budget per minute, or nil if the budget is not positive.
+func (c *Client) limiter(key string, perMinute int) *util.TokenLimiter {
+	if perMinute <= 0 {
+		return nil
+	}
+
+	c.limiterMu.Lock()
+	defer c.limiterMu.Unlock()
+
+	if c.limiters == nil {
+		c.limiters = make(map[string]*util.TokenLimiter)
+	}
+	limiter, ok := c.limiters[key]
+	if !ok {
+		limiter = util.NewTokenLimiter(perMinute)
+		c.limiters[key] = limiter
+	}
+	return limiter
+}
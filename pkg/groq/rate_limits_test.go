@@ -0,0 +1,82 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithModelRateLimit(t *testing.T) {
+	srv := newTestServer(t, func(req *ChatCompletionRequest) string { return "ok" })
+	client := NewClient("test-key", WithBaseURL(srv.URL),
+		WithModelRateLimit(ModelLlama33_70bVersatile, 2),
+		WithEndpointRateLimit(EndpointAudio, 1),
+	)
+
+	limited := NewRequest(ModelLlama33_70bVersatile).User("hi").Build()
+	for i := 0; i < 2; i++ {
+		if _, err := client.CreateChatCompletion(context.Background(), limited); err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.CreateChatCompletion(ctx, limited); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the model budget to block, got %v", err)
+	}
+
+	// Other models and the audio budget do not limit chat requests of other models.
+	other := NewRequest(ModelLlama31_8bInstant).User("hi").Build()
+	for i := 0; i < 3; i++ {
+		if _, err := client.CreateChatCompletion(context.Background(), other); err != nil {
+			t.Fatalf("unlimited request %d: %v", i, err)
+		}
+	}
+}
+
+func TestRequestLimitsReleasedOnCancel(t *testing.T) {
+	srv := newTestServer(t, func(req *ChatCompletionRequest) string { return "ok" })
+	client := NewClient("test-key", WithBaseURL(srv.URL),
+		WithEndpointRateLimit(EndpointChat, 2),
+		WithModelRateLimit(ModelLlama33_70bVersatile, 1),
+	)
+
+	limited := NewRequest(ModelLlama33_70bVersatile).User("hi").Build()
+	if _, err := client.CreateChatCompletion(context.Background(), limited); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.CreateChatCompletion(ctx, limited); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the model budget to block, got %v", err)
+	}
+
+	// The cancelled request gave its endpoint budget back, so one more request fits.
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := client.CreateChatCompletion(ctx, NewRequest(ModelLlama31_8bInstant).User("hi").Build()); err != nil {
+		t.Errorf("expected the endpoint budget to be released, got %v", err)
+	}
+}
+
+type countingLimiter struct{ waits int }
+
+func (l *countingLimiter) Wait(ctx context.Context) error {
+	l.waits++
+	return nil
+}
+
+func TestWithRateLimiter(t *testing.T) {
+	srv := newTestServer(t, func(req *ChatCompletionRequest) string { return "ok" })
+	limiter := &countingLimiter{}
+	// Options that rebuild the HTTP client must not drop the limiter.
+	client := NewClient("test-key", WithRateLimiter(limiter), WithBaseURL(srv.URL), WithTimeout(5*time.Second))
+
+	if _, err := client.CreateChatCompletion(context.Background(), NewRequest(ModelLlama31_8bInstant).User("hi").Build()); err != nil {
+		t.Fatal(err)
+	}
+	if limiter.waits != 1 {
+		t.Errorf("expected the custom limiter to be used once, got %d", limiter.waits)
+	}
+}
@@ -0,0 +1,302 @@
+// Package redis_cache stores chat completion responses in Redis or Valkey, so several
+// service instances share one response cache and the server expires entries.
+//
+// Cache matches keys exactly; SemanticCache finds similar queries with the vector search
+// of RediSearch (Redis Stack, Redis 8 or Valkey Search). Both talk to the server through
+// a Doer: the built-in Conn, or an adapter for an existing Redis client.
+package redis_cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/genc-murat/groq-client/pkg/groq"
+)
+
+// scanCount is the number of keys requested per SCAN call.
+const scanCount = 500
+
+type Config struct {
+	Prefix string        // Prepended to every key, e.g. "groq:cache:"; keeps apps sharing a server apart
+	TTL    time.Duration // Expiry of entries, enforced by the server; 0 keeps them until evicted
+}
+
+// DefaultConfig returns a Config with the prefix "groq:cache:" and a TTL of 24 hours.
+func DefaultConfig() *Config {
+	return &Config{
+		Prefix: "groq:cache:",
+		TTL:    24 * time.Hour,
+	}
+}
+
+// Validate checks the configuration and reports all problems in a *groq.ConfigError.
+func (c *Config) Validate() error {
+	return groq.NewConfigError(c.problems())
+}
+
+// problems returns the problems of the configuration.
+func (c *Config) problems() []string {
+	var problems []string
+	if c.Prefix == "" {
+		problems = append(problems, "Prefix is empty; Clear would delete every key of the database, set a prefix such as \"groq:cache:\"")
+	}
+	if c.TTL < 0 {
+		problems = append(problems, fmt.Sprintf("TTL is %v; use 0 to keep entries until Redis evicts them", c.TTL))
+	}
+	if c.TTL > 0 && c.TTL < time.Millisecond {
+		problems = append(problems, fmt.Sprintf("TTL is %v; Redis expires keys in whole milliseconds", c.TTL))
+	}
+	return problems
+}
+
+type Cache struct {
+	doer   Doer
+	config *Config
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// New creates a cache storing each response as JSON under its exact key.
+//
+// Example usage:
+//
+//	conn := redis_cache.NewConn(redis_cache.Options{Addr: "localhost:6379"})
+//	defer conn.Close()
+//	client := groq.NewClient(apiKey, groq.WithCache(redis_cache.New(conn, nil)))
+//
+// Parameters:
+//   - doer: Runs the Redis commands, e.g. a *Conn.
+//   - config: The key prefix and TTL. If nil, DefaultConfig() is used.
+//
+// Returns:
+//   - *Cache: The cache.
+func New(doer Doer, config *Config) *Cache {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	return &Cache{doer: doer, config: config}
+}
+
+// NewE is like New but validates the configuration first.
+//
+// Returns:
+//   - *Cache: The cache, or nil if the configuration is invalid.
+//   - error: A *groq.ConfigError describing every invalid setting.
+func NewE(doer Doer, config *Config) (*Cache, error) {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return New(doer, config), nil
+}
+
+// Get returns the response stored under key. Redis errors count as misses.
+func (c *Cache) Get(ctx context.Context, key string) (*groq.ChatCompletionResponse, bool) {
+	resp, ok := c.get(ctx, key)
+	if !ok {
+		c.misses.Add(1)
+		return nil, false
+	}
+	c.hits.Add(1)
+	return resp, true
+}
+
+// GetWithTTL is like Get and also returns how much longer the entry is kept, 0 if it
+// does not expire, implementing groq.TTLReporter. It costs a second round trip.
+func (c *Cache) GetWithTTL(ctx context.Context, key string) (*groq.ChatCompletionResponse, time.Duration, bool) {
+	resp, ok := c.get(ctx, key)
+	var ttl time.Duration
+	if ok {
+		ttl, ok = remainingTTL(ctx, c.doer, c.config.Prefix+key)
+	}
+	if !ok {
+		c.misses.Add(1)
+		return nil, 0, false
+	}
+	c.hits.Add(1)
+	return resp, ttl, true
+}
+
+// get reads and decodes the entry stored under key.
+func (c *Cache) get(ctx context.Context, key string) (*groq.ChatCompletionResponse, bool) {
+	reply, err := c.doer.Do(ctx, "GET", c.config.Prefix+key)
+	data, ok := bytesReply(reply)
+	if err != nil || !ok {
+		return nil, false
+	}
+
+	var resp groq.ChatCompletionResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, false
+	}
+	return &resp, true
+}
+
+// Set stores value under key, expiring after the configured TTL.
+func (c *Cache) Set(ctx context.Context, key string, value *groq.ChatCompletionResponse) error {
+	return c.SetWithTTL(ctx, key, value, 0)
+}
+
+// SetWithTTL is like Set but expires the entry after ttl instead of the configured TTL,
+// implementing groq.TTLCache. A ttl of 0 or less uses the configured TTL.
+func (c *Cache) SetWithTTL(ctx context.Context, key string, value *groq.ChatCompletionResponse, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("%w: %v", groq.ErrJSONEncoding, err)
+	}
+
+	args := []interface{}{"SET", c.config.Prefix + key, data}
+	if ttl = expiry(ttl, c.config.TTL); ttl > 0 {
+		args = append(args, "PX", ttl.Milliseconds())
+	}
+	if _, err := c.doer.Do(ctx, args...); err != nil {
+		return fmt.Errorf("redis_cache: storing %s: %w", key, err)
+	}
+	return nil
+}
+
+// Delete removes the entry stored under key.
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	if _, err := c.doer.Do(ctx, "DEL", c.config.Prefix+key); err != nil {
+		return fmt.Errorf("redis_cache: deleting %s: %w", key, err)
+	}
+	return nil
+}
+
+// Clear removes every entry under the configured prefix.
+func (c *Cache) Clear(ctx context.Context) error {
+	return deletePrefix(ctx, c.doer, c.config.Prefix)
+}
+
+// Keys returns the keys of all entries, implementing groq.CacheKeyLister.
+func (c *Cache) Keys(ctx context.Context) ([]string, error) {
+	var keys []string
+	err := scanPrefix(ctx, c.doer, c.config.Prefix, func(batch []string) error {
+		for _, key := range batch {
+			keys = append(keys, key[len(c.config.Prefix):])
+		}
+		return nil
+	})
+	return keys, err
+}
+
+// GetStats returns the hits and misses of this instance. Size and ItemCount are not
+// reported, as the entries are shared with other instances; use Keys to count them.
+func (c *Cache) GetStats() groq.CacheStats {
+	return groq.CacheStats{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+	}
+}
+
+// expiry returns ttl, or fallback if ttl is not positive, rounded up to whole
+// milliseconds, the resolution of Redis; 0 means no expiry.
+func expiry(ttl, fallback time.Duration) time.Duration {
+	if ttl <= 0 {
+		ttl = fallback
+	}
+	if ttl <= 0 {
+		return 0
+	}
+	if rest := ttl % time.Millisecond; rest != 0 {
+		ttl += time.Millisecond - rest
+	}
+	return ttl
+}
+
+// remainingTTL returns the remaining TTL of a Redis key, 0 if it does not expire, or
+// false if the key is gone or Redis failed.
+func remainingTTL(ctx context.Context, doer Doer, key string) (time.Duration, bool) {
+	reply, err := doer.Do(ctx, "PTTL", key)
+	ms, ok := reply.(int64)
+	switch {
+	case err != nil || !ok || ms == -2:
+		return 0, false
+	case ms < 0:
+		return 0, true
+	}
+	return max(time.Duration(ms)*time.Millisecond, time.Millisecond), true
+}
+
+// scanPrefix calls fn with batches of the keys starting with prefix.
+func scanPrefix(ctx context.Context, doer Doer, prefix string, fn func(keys []string) error) error {
+	cursor := "0"
+	for {
+		reply, err := doer.Do(ctx, "SCAN", cursor, "MATCH", escapeGlob(prefix)+"*", "COUNT", scanCount)
+		if err != nil {
+			return fmt.Errorf("redis_cache: scanning %s: %w", prefix, err)
+		}
+		parts, ok := reply.([]interface{})
+		if !ok || len(parts) != 2 {
+			return fmt.Errorf("redis_cache: unexpected SCAN reply %v", reply)
+		}
+		next, _ := stringReply(parts[0])
+		items, _ := parts[1].([]interface{})
+
+		keys := make([]string, 0, len(items))
+		for _, item := range items {
+			if key, ok := stringReply(item); ok {
+				keys = append(keys, key)
+			}
+		}
+		if len(keys) > 0 {
+			if err := fn(keys); err != nil {
+				return err
+			}
+		}
+		if next == "0" || next == "" {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+// deletePrefix deletes every key starting with prefix.
+func deletePrefix(ctx context.Context, doer Doer, prefix string) error {
+	return scanPrefix(ctx, doer, prefix, func(keys []string) error {
+		args := make([]interface{}, 0, len(keys)+1)
+		args = append(args, "UNLINK")
+		for _, key := range keys {
+			args = append(args, key)
+		}
+		if _, err := doer.Do(ctx, args...); err != nil {
+			return fmt.Errorf("redis_cache: deleting keys: %w", err)
+		}
+		return nil
+	})
+}
+
+// escapeGlob escapes the characters SCAN MATCH treats as patterns.
+func escapeGlob(s string) string {
+	escaped := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '*', '?', '[', ']', '\\':
+			escaped = append(escaped, '\\')
+		}
+		escaped = append(escaped, s[i])
+	}
+	return string(escaped)
+}
+
+// bytesReply returns the data of a string reply; false for nil and other replies.
+func bytesReply(reply interface{}) ([]byte, bool) {
+	switch v := reply.(type) {
+	case []byte:
+		return v, true
+	case string:
+		return []byte(v), true
+	}
+	return nil, false
+}
+
+// stringReply is like bytesReply but returns a string.
+func stringReply(reply interface{}) (string, bool) {
+	data, ok := bytesReply(reply)
+	return string(data), ok
+}
@@ -0,0 +1,80 @@
+package redis_cache
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/genc-murat/groq-client/pkg/groq"
+)
+
+func TestCache(t *testing.T) {
+	fake := newFakeRedis()
+	conn := NewConn(Options{Addr: serveRESP(t, fake, 0)})
+	defer conn.Close()
+	ctx := context.Background()
+
+	cache := New(conn, &Config{Prefix: "app:", TTL: time.Minute})
+	// Entries of other apps must survive Clear.
+	fake.strings["other:key"] = []byte("x")
+
+	if _, ok := cache.Get(ctx, "q1"); ok {
+		t.Error("expected a miss on an empty cache")
+	}
+	if err := cache.Set(ctx, "q1", &groq.ChatCompletionResponse{ID: "one"}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	_ = cache.Set(ctx, "q*2", &groq.ChatCompletionResponse{ID: "two"})
+
+	if resp, ok := cache.Get(ctx, "q1"); !ok || resp.ID != "one" {
+		t.Errorf("Get() = %+v, %v", resp, ok)
+	}
+	if fake.ttls["app:q1"] != 60000 {
+		t.Errorf("TTL = %dms, want 60000", fake.ttls["app:q1"])
+	}
+	if _, ttl, ok := cache.GetWithTTL(ctx, "q1"); !ok || ttl != time.Minute {
+		t.Errorf("GetWithTTL() = %v, %v", ttl, ok)
+	}
+	_ = cache.SetWithTTL(ctx, "news", &groq.ChatCompletionResponse{ID: "news"}, 1500*time.Microsecond)
+	if fake.ttls["app:news"] != 2 {
+		t.Errorf("SetWithTTL() TTL = %dms, want 2", fake.ttls["app:news"])
+	}
+	_ = cache.Delete(ctx, "news")
+
+	keys, err := cache.Keys(ctx)
+	sort.Strings(keys)
+	if err != nil || len(keys) != 2 || keys[0] != "q*2" || keys[1] != "q1" {
+		t.Errorf("Keys() = %v, %v", keys, err)
+	}
+
+	_ = cache.Delete(ctx, "q1")
+	if _, ok := cache.Get(ctx, "q1"); ok {
+		t.Error("expected a miss after Delete")
+	}
+	if err := cache.Clear(ctx); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if _, ok := cache.Get(ctx, "q*2"); ok {
+		t.Error("expected a miss after Clear")
+	}
+	if _, ok := fake.strings["other:key"]; !ok {
+		t.Error("Clear deleted a key outside the prefix")
+	}
+
+	if stats := cache.GetStats(); stats.Hits != 2 || stats.Misses != 3 {
+		t.Errorf("GetStats() = %+v", stats)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	if _, err := NewE(newFakeRedis(), nil); err != nil {
+		t.Fatalf("expected the default config to be valid, got %v", err)
+	}
+	_, err := NewE(newFakeRedis(), &Config{TTL: -time.Second})
+	var configErr *groq.ConfigError
+	if !errors.As(err, &configErr) || len(configErr.Problems) != 2 {
+		t.Errorf("expected 2 problems, got %v", err)
+	}
+}
@@ -0,0 +1,322 @@
+package redis_cache
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrClosed is returned by a Conn used after Close.
+var ErrClosed = errors.New("redis_cache: connection closed")
+
+// Doer runs one Redis command. Replies are decoded as string for simple strings, []byte
+// for bulk strings, int64 for integers and []interface{} for arrays; nil replies are
+// returned as a nil value and a nil error. Redis error replies are returned as errors.
+//
+// Conn implements Doer. Applications that already use a Redis client can adapt it
+// instead, e.g. for go-redis, whose client must use RESP2 (Options.Protocol = 2):
+//
+//	doer := redis_cache.DoerFunc(func(ctx context.Context, args ...interface{}) (interface{}, error) {
+//	    v, err := rdb.Do(ctx, args...).Result()
+//	    if err == redis.Nil {
+//	        return nil, nil
+//	    }
+//	    return v, err
+//	})
+type Doer interface {
+	Do(ctx context.Context, args ...interface{}) (interface{}, error)
+}
+
+// DoerFunc adapts an ordinary function to the Doer interface.
+type DoerFunc func(ctx context.Context, args ...interface{}) (interface{}, error)
+
+// Do calls f(ctx, args...).
+func (f DoerFunc) Do(ctx context.Context, args ...interface{}) (interface{}, error) {
+	return f(ctx, args...)
+}
+
+// Error is an error reply of the Redis server, such as "ERR unknown command".
+type Error string
+
+func (e Error) Error() string {
+	return "redis: " + string(e)
+}
+
+type Options struct {
+	Addr        string        // host:port of the server; empty is localhost:6379
+	Username    string        // ACL user; empty authenticates with Password only
+	Password    string        // Sent with AUTH if set
+	DB          int           // Database selected after connecting
+	PoolSize    int           // Maximum idle connections kept; 0 means 10
+	DialTimeout time.Duration // 0 means 5 seconds
+	TLSConfig   *tls.Config   // If set, connections use TLS, e.g. for managed Redis or Valkey
+}
+
+// Conn is a minimal Redis client speaking RESP2, so the caches work with Redis, Valkey
+// and compatible servers without an external client library. It keeps a small pool of
+// connections and is safe for concurrent use.
+type Conn struct {
+	opts   Options
+	idle   chan *respConn
+	closed bool
+	mu     sync.Mutex
+}
+
+// respConn is one connection to the server.
+type respConn struct {
+	net.Conn
+	r *bufio.Reader
+	w *bufio.Writer
+}
+
+// NewConn creates a client for the server in opts. Connections are opened on first use.
+//
+// Parameters:
+//   - opts: The server address, credentials and pool settings.
+//
+// Returns:
+//   - *Conn: The client.
+func NewConn(opts Options) *Conn {
+	if opts.Addr == "" {
+		opts.Addr = "localhost:6379"
+	}
+	if opts.PoolSize <= 0 {
+		opts.PoolSize = 10
+	}
+	if opts.DialTimeout <= 0 {
+		opts.DialTimeout = 5 * time.Second
+	}
+	return &Conn{opts: opts, idle: make(chan *respConn, opts.PoolSize)}
+}
+
+// Do implements Doer. Connections that fail on the network are discarded; connections
+// that receive an error reply are reused.
+func (c *Conn) Do(ctx context.Context, args ...interface{}) (interface{}, error) {
+	conn, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := conn.do(ctx, args...)
+	var redisErr Error
+	if err != nil && !errors.As(err, &redisErr) {
+		conn.Close()
+		return nil, err
+	}
+	c.put(conn)
+	return reply, err
+}
+
+// Close closes the idle connections; connections in use are closed when they are returned.
+func (c *Conn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	close(c.idle)
+	for conn := range c.idle {
+		conn.Close()
+	}
+	return nil
+}
+
+// get returns an idle connection or dials a new one.
+func (c *Conn) get(ctx context.Context) (*respConn, error) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil, ErrClosed
+	}
+	select {
+	case conn := <-c.idle:
+		c.mu.Unlock()
+		return conn, nil
+	default:
+	}
+	c.mu.Unlock()
+	return c.dial(ctx)
+}
+
+// put returns conn to the pool, closing it if the pool is full or closed.
+func (c *Conn) put(conn *respConn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		conn.Close()
+		return
+	}
+	select {
+	case c.idle <- conn:
+	default:
+		conn.Close()
+	}
+}
+
+// dial opens a connection, authenticates and selects the database.
+func (c *Conn) dial(ctx context.Context) (*respConn, error) {
+	dialer := &net.Dialer{Timeout: c.opts.DialTimeout}
+	var netConn net.Conn
+	var err error
+	if c.opts.TLSConfig != nil {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: c.opts.TLSConfig}
+		netConn, err = tlsDialer.DialContext(ctx, "tcp", c.opts.Addr)
+	} else {
+		netConn, err = dialer.DialContext(ctx, "tcp", c.opts.Addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("redis_cache: dialing %s: %w", c.opts.Addr, err)
+	}
+
+	conn := &respConn{Conn: netConn, r: bufio.NewReader(netConn), w: bufio.NewWriter(netConn)}
+	if c.opts.Password != "" {
+		args := []interface{}{"AUTH", c.opts.Password}
+		if c.opts.Username != "" {
+			args = []interface{}{"AUTH", c.opts.Username, c.opts.Password}
+		}
+		if _, err := conn.do(ctx, args...); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("redis_cache: authenticating: %w", err)
+		}
+	}
+	if c.opts.DB != 0 {
+		if _, err := conn.do(ctx, "SELECT", c.opts.DB); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("redis_cache: selecting database %d: %w", c.opts.DB, err)
+		}
+	}
+	return conn, nil
+}
+
+// do sends one command and reads its reply, bounded by the deadline of ctx.
+func (conn *respConn) do(ctx context.Context, args ...interface{}) (interface{}, error) {
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+	// Cancelling ctx interrupts blocked reads and writes by moving the deadline.
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.SetDeadline(time.Unix(1, 0))
+	})
+
+	if err := writeCommand(conn.w, args); err != nil {
+		stop()
+		return nil, err
+	}
+	if err := conn.w.Flush(); err != nil {
+		stop()
+		return nil, contextError(ctx, err)
+	}
+	reply, err := readReply(conn.r)
+	if !stop() {
+		// The deadline may have moved; the connection must not be reused.
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		var redisErr Error
+		if !errors.As(err, &redisErr) {
+			return nil, contextError(ctx, err)
+		}
+	}
+	return reply, err
+}
+
+// contextError returns the error of ctx if it caused err. The socket deadline can pass
+// just before ctx reports its own, so timeouts after the deadline count as well.
+func contextError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	var netErr net.Error
+	if deadline, ok := ctx.Deadline(); ok && errors.As(err, &netErr) && netErr.Timeout() && !time.Now().Before(deadline) {
+		return context.DeadlineExceeded
+	}
+	return err
+}
+
+// writeCommand encodes args as a RESP array of bulk strings.
+func writeCommand(w *bufio.Writer, args []interface{}) error {
+	fmt.Fprintf(w, "*%d\r\n", len(args))
+	for _, arg := range args {
+		var data []byte
+		switch v := arg.(type) {
+		case string:
+			data = []byte(v)
+		case []byte:
+			data = v
+		case int:
+			data = strconv.AppendInt(nil, int64(v), 10)
+		case int64:
+			data = strconv.AppendInt(nil, v, 10)
+		case float64:
+			data = strconv.AppendFloat(nil, v, 'f', -1, 64)
+		default:
+			return fmt.Errorf("redis_cache: unsupported argument type %T", arg)
+		}
+		fmt.Fprintf(w, "$%d\r\n", len(data))
+		w.Write(data)
+		w.WriteString("\r\n")
+	}
+	return nil
+}
+
+// readReply decodes one RESP2 reply.
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("redis_cache: malformed reply %q", line)
+	}
+	kind, payload := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return payload, nil
+	case '-':
+		return nil, Error(payload)
+	case ':':
+		return strconv.ParseInt(payload, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(payload)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		return data[:n], nil
+	case '*':
+		n, err := strconv.Atoi(payload)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		items := make([]interface{}, n)
+		var firstErr error
+		for i := range items {
+			item, err := readReply(r)
+			var redisErr Error
+			if err != nil && !errors.As(err, &redisErr) {
+				return nil, err
+			}
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+			items[i] = item
+		}
+		return items, firstErr
+	}
+	return nil, fmt.Errorf("redis_cache: unknown reply type %q", kind)
+}
@@ -0,0 +1,351 @@
+package redis_cache
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis emulates the commands the caches use, with a brute-force FT.SEARCH.
+type fakeRedis struct {
+	strings  map[string][]byte
+	hashes   map[string]map[string][]byte
+	ttls     map[string]int64 // Milliseconds set with PX or PEXPIRE
+	indexes  map[string]string
+	dims     map[string]string // Vector dimension of each index
+	commands []string
+	mu       sync.Mutex
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{
+		strings: make(map[string][]byte),
+		hashes:  make(map[string]map[string][]byte),
+		ttls:    make(map[string]int64),
+		indexes: make(map[string]string),
+		dims:    make(map[string]string),
+	}
+}
+
+func (f *fakeRedis) Do(ctx context.Context, args ...interface{}) (interface{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	s := make([]string, len(args))
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case []byte:
+			s[i] = string(v)
+		default:
+			s[i] = fmt.Sprint(v)
+		}
+	}
+	f.commands = append(f.commands, s[0])
+
+	switch strings.ToUpper(s[0]) {
+	case "PING":
+		return "PONG", nil
+	case "AUTH", "SELECT":
+		return "OK", nil
+	case "GET":
+		if v, ok := f.strings[s[1]]; ok {
+			return v, nil
+		}
+		return nil, nil
+	case "SET":
+		f.strings[s[1]] = []byte(s[2])
+		if len(s) == 5 && s[3] == "PX" {
+			f.ttls[s[1]], _ = strconv.ParseInt(s[4], 10, 64)
+		}
+		return "OK", nil
+	case "HSET":
+		hash := make(map[string][]byte)
+		for i := 2; i+1 < len(s); i += 2 {
+			hash[s[i]] = []byte(s[i+1])
+		}
+		f.hashes[s[1]] = hash
+		return int64(len(hash)), nil
+	case "EVAL":
+		// The store script of SemanticCache: HSET the fields, then set or clear the TTL.
+		key := s[3]
+		hash := make(map[string][]byte)
+		for i := 5; i+1 < len(s); i += 2 {
+			hash[s[i]] = []byte(s[i+1])
+		}
+		f.hashes[key] = hash
+		if ttl, _ := strconv.ParseInt(s[4], 10, 64); ttl > 0 {
+			f.ttls[key] = ttl
+		} else {
+			delete(f.ttls, key)
+		}
+		return int64(1), nil
+	case "PEXPIRE":
+		f.ttls[s[1]], _ = strconv.ParseInt(s[2], 10, 64)
+		return int64(1), nil
+	case "PTTL":
+		_, isString := f.strings[s[1]]
+		_, isHash := f.hashes[s[1]]
+		if !isString && !isHash {
+			return int64(-2), nil
+		}
+		if ttl, ok := f.ttls[s[1]]; ok {
+			return ttl, nil
+		}
+		return int64(-1), nil
+	case "DEL", "UNLINK":
+		n := int64(0)
+		for _, key := range s[1:] {
+			if _, ok := f.strings[key]; ok {
+				n++
+			}
+			if _, ok := f.hashes[key]; ok {
+				n++
+			}
+			delete(f.strings, key)
+			delete(f.hashes, key)
+		}
+		return n, nil
+	case "SCAN":
+		prefix := strings.ReplaceAll(strings.TrimSuffix(s[3], "*"), `\`, "")
+		var keys []interface{}
+		for _, key := range f.keys() {
+			if strings.HasPrefix(key, prefix) {
+				keys = append(keys, []byte(key))
+			}
+		}
+		return []interface{}{[]byte("0"), keys}, nil
+	case "FT.CREATE":
+		if _, ok := f.indexes[s[1]]; ok {
+			return nil, Error("Index already exists")
+		}
+		f.indexes[s[1]] = s[6]
+		f.dims[s[1]] = s[len(s)-3]
+		return "OK", nil
+	case "FT.INFO":
+		if _, ok := f.indexes[s[1]]; !ok {
+			return nil, Error("Unknown Index name")
+		}
+		return []interface{}{[]byte("index_name"), []byte(s[1]), []byte("attributes"), []interface{}{
+			[]interface{}{[]byte("identifier"), []byte("scope"), []byte("type"), []byte("TAG")},
+			[]interface{}{[]byte("identifier"), []byte("embedding"), []byte("type"), []byte("VECTOR"),
+				[]byte("algorithm"), []byte("HNSW"), []byte("dim"), []byte(f.dims[s[1]])},
+		}}, nil
+	case "FT.DROPINDEX":
+		delete(f.indexes, s[1])
+		delete(f.dims, s[1])
+		return "OK", nil
+	case "FT.SEARCH":
+		return f.search(s)
+	}
+	return nil, Error("ERR unknown command '" + s[0] + "'")
+}
+
+func (f *fakeRedis) keys() []string {
+	var keys []string
+	for key := range f.strings {
+		keys = append(keys, key)
+	}
+	for key := range f.hashes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (f *fakeRedis) search(s []string) (interface{}, error) {
+	prefix, ok := f.indexes[s[1]]
+	if !ok {
+		return nil, Error("no such index")
+	}
+	params := map[string]string{}
+	for i, arg := range s {
+		if arg == "PARAMS" {
+			n, _ := strconv.Atoi(s[i+1])
+			for j := i + 2; j < i+2+n; j += 2 {
+				params[s[j]] = s[j+1]
+			}
+		}
+	}
+	query := decodeBlob([]byte(params["vec"]))
+	filter := tagFilter.FindStringSubmatch(s[2])
+
+	type match struct {
+		key      string
+		distance float64
+	}
+	var matches []match
+	for _, key := range f.keys() {
+		hash, ok := f.hashes[key]
+		if !ok || !strings.HasPrefix(key, prefix) || filter != nil && string(hash[filter[1]]) != filter[2] {
+			continue
+		}
+		matches = append(matches, match{key, 1 - cosine(query, decodeBlob(hash["embedding"]))})
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].distance < matches[j].distance })
+
+	if strings.Contains(s[2], "VECTOR_RANGE") {
+		radius, _ := strconv.ParseFloat(params["radius"], 64)
+		reply := []interface{}{int64(0)}
+		for _, m := range matches {
+			if m.distance <= radius {
+				reply = append(reply, []byte(m.key))
+			}
+		}
+		reply[0] = int64(len(reply) - 1)
+		return reply, nil
+	}
+	if len(matches) == 0 {
+		return []interface{}{int64(0)}, nil
+	}
+	best := matches[0]
+	return []interface{}{int64(1), []byte(best.key), []interface{}{
+		[]byte("response"), f.hashes[best.key]["response"],
+		[]byte("score"), []byte(strconv.FormatFloat(best.distance, 'f', -1, 64)),
+	}}, nil
+}
+
+// tagFilter matches the TAG condition of a search query.
+var tagFilter = regexp.MustCompile(`@(\w+):\{(\w+)\}`)
+
+func decodeBlob(blob []byte) []float32 {
+	v := make([]float32, len(blob)/4)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(blob[4*i:]))
+	}
+	return v
+}
+
+func cosine(a, b []float32) float64 {
+	var dot, na, nb float64
+	for i := range a {
+		if i < len(b) {
+			dot += float64(a[i]) * float64(b[i])
+		}
+		na += float64(a[i]) * float64(a[i])
+	}
+	for i := range b {
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+// serveRESP serves fake over the Redis protocol and returns its address.
+func serveRESP(t *testing.T, fake Doer, delay time.Duration) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				r, w := bufio.NewReader(conn), bufio.NewWriter(conn)
+				for {
+					cmd, err := readReply(r)
+					if err != nil {
+						return
+					}
+					time.Sleep(delay)
+					reply, err := fake.Do(context.Background(), cmd.([]interface{})...)
+					if err != nil {
+						fmt.Fprintf(w, "-%s\r\n", strings.TrimPrefix(err.Error(), "redis: "))
+					} else {
+						writeRESP(w, reply)
+					}
+					w.Flush()
+				}
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func writeRESP(w *bufio.Writer, reply interface{}) {
+	switch v := reply.(type) {
+	case nil:
+		w.WriteString("$-1\r\n")
+	case string:
+		fmt.Fprintf(w, "+%s\r\n", v)
+	case int64:
+		fmt.Fprintf(w, ":%d\r\n", v)
+	case []byte:
+		fmt.Fprintf(w, "$%d\r\n%s\r\n", len(v), v)
+	case []interface{}:
+		fmt.Fprintf(w, "*%d\r\n", len(v))
+		for _, item := range v {
+			writeRESP(w, item)
+		}
+	}
+}
+
+func TestConn(t *testing.T) {
+	fake := newFakeRedis()
+	conn := NewConn(Options{Addr: serveRESP(t, fake, 0), Password: "secret", DB: 2})
+	defer conn.Close()
+	ctx := context.Background()
+
+	if reply, err := conn.Do(ctx, "PING"); err != nil || reply != "PONG" {
+		t.Fatalf("PING = %v, %v", reply, err)
+	}
+	if reply, err := conn.Do(ctx, "GET", "missing"); err != nil || reply != nil {
+		t.Errorf("GET of a missing key = %v, %v; want nil", reply, err)
+	}
+	if _, err := conn.Do(ctx, "SET", "k", []byte("v\r\nwith newline"), "PX", int64(1000)); err != nil {
+		t.Fatal(err)
+	}
+	if reply, _ := conn.Do(ctx, "GET", "k"); string(reply.([]byte)) != "v\r\nwith newline" {
+		t.Errorf("GET = %q", reply)
+	}
+
+	var redisErr Error
+	if _, err := conn.Do(ctx, "BOGUS"); !errors.As(err, &redisErr) {
+		t.Errorf("expected a Redis error reply, got %v", err)
+	}
+	if reply, err := conn.Do(ctx, "PING"); err != nil || reply != "PONG" {
+		t.Errorf("connection unusable after an error reply: %v, %v", reply, err)
+	}
+	if got := strings.Join(fake.commands[:2], " "); got != "AUTH SELECT" {
+		t.Errorf("first commands = %q, want AUTH and SELECT", got)
+	}
+
+	conn.Close()
+	if _, err := conn.Do(ctx, "PING"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Do after Close = %v, want ErrClosed", err)
+	}
+}
+
+func TestConnContext(t *testing.T) {
+	conn := NewConn(Options{Addr: serveRESP(t, newFakeRedis(), 200*time.Millisecond)})
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := conn.Do(ctx, "PING"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Do() error = %v, want DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("Do() took %v, want it to stop at the deadline", elapsed)
+	}
+}
@@ -0,0 +1,423 @@
+package redis_cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/genc-murat/groq-client/pkg/groq"
+)
+
+// maxSimilarMatches bounds the entries DeleteSimilar removes in one call.
+const maxSimilarMatches = 10000
+
+// ErrDimensionMismatch is returned by Set when the embedder returns vectors of another
+// dimension than the index was created for, e.g. after switching the embedding model.
+// Drop the index or configure a new one.
+var ErrDimensionMismatch = errors.New("redis_cache: embedding dimension does not match the index")
+
+// storeScript writes the hash of an entry and sets or removes its expiry in one step, so
+// no entry is left without its TTL. KEYS[1] is the hash, ARGV[1] the TTL in milliseconds
+// (0 for none) and the remaining arguments the fields and values.
+const storeScript = `redis.call('HSET', KEYS[1], unpack(ARGV, 2))
+if tonumber(ARGV[1]) > 0 then
+  redis.call('PEXPIRE', KEYS[1], ARGV[1])
+else
+  redis.call('PERSIST', KEYS[1])
+end
+return 1`
+
+type SemanticConfig struct {
+	Prefix              string        // Prepended to every key; the index covers the hashes under it
+	TTL                 time.Duration // Expiry of entries, enforced by the server; 0 keeps them until evicted
+	Index               string        // Name of the RediSearch index; created on first Set if missing
+	Embedder            groq.Embedder // Computes the query vectors; required
+	SimilarityThreshold float32       // Minimum cosine similarity (0.0-1.0) of a hit
+	TurnDecay           float32       // Weight of each earlier turn of conversation keys relative to the next (0.0-1.0)
+}
+
+// DefaultSemanticConfig returns a SemanticConfig using embedder, with the prefix
+// "groq:semantic:", the index "groq-semantic-idx", a TTL of 24 hours, a similarity
+// threshold of 0.85 and a turn decay of 0.8.
+func DefaultSemanticConfig(embedder groq.Embedder) *SemanticConfig {
+	return &SemanticConfig{
+		Prefix:              "groq:semantic:",
+		TTL:                 24 * time.Hour,
+		Index:               "groq-semantic-idx",
+		Embedder:            embedder,
+		SimilarityThreshold: 0.85,
+		TurnDecay:           0.8,
+	}
+}
+
+// Validate checks the configuration and reports all problems in a *groq.ConfigError.
+func (c *SemanticConfig) Validate() error {
+	problems := (&Config{Prefix: c.Prefix, TTL: c.TTL}).problems()
+	if c.Index == "" {
+		problems = append(problems, "Index is empty; set the name of the RediSearch index")
+	}
+	if c.Embedder == nil {
+		problems = append(problems, "Embedder is nil; set one, e.g. groq.NewAPIEmbedder")
+	}
+	if c.SimilarityThreshold <= 0 || c.SimilarityThreshold > 1 {
+		problems = append(problems, fmt.Sprintf("SimilarityThreshold is %v; it must be in (0, 1], e.g. 0.85", c.SimilarityThreshold))
+	}
+	if c.TurnDecay < 0 || c.TurnDecay > 1 {
+		problems = append(problems, fmt.Sprintf("TurnDecay is %v; it must be in [0, 1], e.g. 0.8, or 0 to compare final questions only", c.TurnDecay))
+	}
+	return groq.NewConfigError(problems)
+}
+
+type SemanticCache struct {
+	doer   Doer
+	config *SemanticConfig
+	hits   atomic.Int64
+	misses atomic.Int64
+	dim    int // Dimension of the index, 0 until it is known to exist
+	mu     sync.Mutex
+}
+
+// NewSemantic creates a cache that stores each response in a hash with the embedding of
+// its query and answers lookups with the most similar stored query, using the vector
+// search of RediSearch. The server must provide the FT.* commands and Lua scripting, e.g.
+// Redis Stack, Redis 8 or Valkey with the search module.
+//
+// Example usage:
+//
+//	embedder := groq.NewAPIEmbedder("https://api.openai.com/v1/embeddings", openAIKey, "text-embedding-3-small")
+//	cache, err := redis_cache.NewSemantic(conn, redis_cache.DefaultSemanticConfig(embedder))
+//
+// Parameters:
+//   - doer: Runs the Redis commands, e.g. a *Conn.
+//   - config: The key prefix, index, embedder, TTL and threshold.
+//
+// Returns:
+//   - *SemanticCache: The cache.
+//   - error: A *groq.ConfigError if the configuration is invalid.
+func NewSemantic(doer Doer, config *SemanticConfig) (*SemanticCache, error) {
+	if config == nil {
+		return nil, groq.NewConfigError([]string{"config is nil; use DefaultSemanticConfig with an Embedder"})
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &SemanticCache{doer: doer, config: config}, nil
+}
+
+// Get returns the response of the stored query most similar to key, if its similarity
+// reaches the threshold. Only the prompts of entries in the same scope as key are
+// compared, see groq.ParseCacheKey; the turns of conversation keys are weighted by
+// TurnDecay, see groq.EmbedConversation. Embedding and Redis errors count as misses.
+func (sc *SemanticCache) Get(ctx context.Context, key string) (*groq.ChatCompletionResponse, bool) {
+	resp, _, ok := sc.search(ctx, key)
+	if !ok {
+		sc.misses.Add(1)
+		return nil, false
+	}
+	sc.hits.Add(1)
+	return resp, true
+}
+
+// GetWithTTL is like Get and also returns how much longer the matching entry is kept, 0
+// if it does not expire, implementing groq.TTLReporter. It costs a second round trip.
+func (sc *SemanticCache) GetWithTTL(ctx context.Context, key string) (*groq.ChatCompletionResponse, time.Duration, bool) {
+	resp, docKey, ok := sc.search(ctx, key)
+	var ttl time.Duration
+	if ok {
+		ttl, ok = remainingTTL(ctx, sc.doer, docKey)
+	}
+	if !ok {
+		sc.misses.Add(1)
+		return nil, 0, false
+	}
+	sc.hits.Add(1)
+	return resp, ttl, true
+}
+
+// search returns the response of the stored query most similar to key and the Redis key
+// of its document, or false if none reaches the threshold.
+func (sc *SemanticCache) search(ctx context.Context, key string) (*groq.ChatCompletionResponse, string, bool) {
+	parts := groq.ParseCacheKey(key)
+	vector, err := sc.embed(ctx, parts.Turns())
+	if err != nil {
+		return nil, "", false
+	}
+
+	reply, err := sc.doer.Do(ctx, "FT.SEARCH", sc.config.Index,
+		"(@scope:{"+tagValue(parts.Scope())+"})=>[KNN 1 @embedding $vec AS score]",
+		"PARAMS", 2, "vec", vectorBlob(vector),
+		"SORTBY", "score",
+		"RETURN", 2, "response", "score",
+		"DIALECT", 2)
+	if err != nil {
+		if isUnknownIndex(err) {
+			// The index was dropped; the next Set recreates it.
+			sc.forgetIndex()
+		}
+		return nil, "", false
+	}
+
+	for _, doc := range parseSearch(reply) {
+		// The index measures cosine distance, which is 1 - similarity.
+		distance, err := strconv.ParseFloat(string(doc.fields["score"]), 64)
+		if err != nil || float32(1-distance) < sc.config.SimilarityThreshold {
+			break
+		}
+		var resp groq.ChatCompletionResponse
+		if err := json.Unmarshal(doc.fields["response"], &resp); err != nil {
+			break
+		}
+		return &resp, doc.key, true
+	}
+	return nil, "", false
+}
+
+// Set stores value with the embedding of the prompt of key, creating the index on first use
+// and again if it was dropped. It returns ErrDimensionMismatch if the index was created
+// for vectors of another dimension than the embedder returns.
+func (sc *SemanticCache) Set(ctx context.Context, key string, value *groq.ChatCompletionResponse) error {
+	return sc.SetWithTTL(ctx, key, value, 0)
+}
+
+// SetWithTTL is like Set but expires the entry after ttl instead of the configured TTL,
+// implementing groq.TTLCache. A ttl of 0 or less uses the configured TTL.
+func (sc *SemanticCache) SetWithTTL(ctx context.Context, key string, value *groq.ChatCompletionResponse, ttl time.Duration) error {
+	parts := groq.ParseCacheKey(key)
+	vector, err := sc.embed(ctx, parts.Turns())
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("%w: %v", groq.ErrJSONEncoding, err)
+	}
+	if err := sc.ensureIndex(ctx, len(vector)); err != nil {
+		return err
+	}
+
+	ttl = expiry(ttl, sc.config.TTL)
+	if _, err := sc.doer.Do(ctx, "EVAL", storeScript, 1, sc.config.Prefix+key, ttl.Milliseconds(),
+		"query", key, "response", data, "embedding", vectorBlob(vector),
+		"scope", tagValue(parts.Scope()), "namespace", tagValue(parts.Namespace)); err != nil {
+		return fmt.Errorf("redis_cache: storing %s: %w", key, err)
+	}
+	return nil
+}
+
+// Delete removes the entry stored under key.
+func (sc *SemanticCache) Delete(ctx context.Context, key string) error {
+	if _, err := sc.doer.Do(ctx, "DEL", sc.config.Prefix+key); err != nil {
+		return fmt.Errorf("redis_cache: deleting %s: %w", key, err)
+	}
+	return nil
+}
+
+// Clear removes every entry under the configured prefix; the index is kept.
+func (sc *SemanticCache) Clear(ctx context.Context) error {
+	return deletePrefix(ctx, sc.doer, sc.config.Prefix)
+}
+
+// Keys returns the queries of all entries, implementing groq.CacheKeyLister.
+func (sc *SemanticCache) Keys(ctx context.Context) ([]string, error) {
+	var keys []string
+	err := scanPrefix(ctx, sc.doer, sc.config.Prefix, func(batch []string) error {
+		for _, key := range batch {
+			keys = append(keys, key[len(sc.config.Prefix):])
+		}
+		return nil
+	})
+	return keys, err
+}
+
+// DeleteSimilar removes every entry of the query's namespace whose prompt has a cosine
+// similarity of at least threshold with the query's prompt, implementing
+// groq.SimilarityInvalidator.
+func (sc *SemanticCache) DeleteSimilar(ctx context.Context, query string, threshold float32) ([]string, error) {
+	parts := groq.ParseCacheKey(query)
+	vector, err := sc.embed(ctx, parts.Turns())
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := sc.doer.Do(ctx, "FT.SEARCH", sc.config.Index,
+		"@namespace:{"+tagValue(parts.Namespace)+"} @embedding:[VECTOR_RANGE $radius $vec]",
+		"PARAMS", 4, "radius", float64(1-threshold), "vec", vectorBlob(vector),
+		"NOCONTENT", "LIMIT", 0, maxSimilarMatches,
+		"DIALECT", 2)
+	if err != nil {
+		return nil, fmt.Errorf("redis_cache: searching similar entries: %w", err)
+	}
+
+	var deleted []string
+	for _, doc := range parseSearch(reply) {
+		if _, err := sc.doer.Do(ctx, "DEL", doc.key); err != nil {
+			return deleted, fmt.Errorf("redis_cache: deleting %s: %w", doc.key, err)
+		}
+		deleted = append(deleted, strings.TrimPrefix(doc.key, sc.config.Prefix))
+	}
+	return deleted, nil
+}
+
+// GetStats returns the hits and misses of this instance. Size and ItemCount are not
+// reported, as the entries are shared with other instances.
+func (sc *SemanticCache) GetStats() groq.CacheStats {
+	return groq.CacheStats{
+		Hits:   sc.hits.Load(),
+		Misses: sc.misses.Load(),
+	}
+}
+
+// embed returns the embedding of the turns of a prompt.
+func (sc *SemanticCache) embed(ctx context.Context, turns []string) ([]float32, error) {
+	vector, err := groq.EmbedConversation(ctx, sc.config.Embedder, turns, sc.config.TurnDecay)
+	if err != nil {
+		return nil, fmt.Errorf("redis_cache: embedding query: %w", err)
+	}
+	if len(vector) == 0 {
+		return nil, errors.New("redis_cache: embedder returned no vector")
+	}
+	return vector, nil
+}
+
+// ensureIndex creates the vector index for vectors of dimension dim unless it exists. It
+// returns ErrDimensionMismatch if the index holds vectors of another dimension.
+func (sc *SemanticCache) ensureIndex(ctx context.Context, dim int) error {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	if sc.dim == 0 {
+		_, err := sc.doer.Do(ctx, "FT.CREATE", sc.config.Index,
+			"ON", "HASH", "PREFIX", 1, sc.config.Prefix,
+			"SCHEMA", "scope", "TAG", "namespace", "TAG",
+			"embedding", "VECTOR", "HNSW", 6, "TYPE", "FLOAT32", "DIM", dim, "DISTANCE_METRIC", "COSINE")
+		switch {
+		case err == nil:
+			sc.dim = dim
+		case strings.Contains(strings.ToLower(err.Error()), "index already exists"):
+			if sc.dim, err = sc.indexDim(ctx); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("redis_cache: creating index %s: %w", sc.config.Index, err)
+		}
+	}
+	if sc.dim != dim {
+		return fmt.Errorf("%w: index %s has dimension %d, the embedder returned %d",
+			ErrDimensionMismatch, sc.config.Index, sc.dim, dim)
+	}
+	return nil
+}
+
+// indexDim reads the dimension of the vectors of an existing index with FT.INFO.
+func (sc *SemanticCache) indexDim(ctx context.Context) (int, error) {
+	reply, err := sc.doer.Do(ctx, "FT.INFO", sc.config.Index)
+	if err != nil {
+		return 0, fmt.Errorf("redis_cache: reading index %s: %w", sc.config.Index, err)
+	}
+	dim, ok := findDim(reply)
+	if !ok {
+		return 0, fmt.Errorf("redis_cache: index %s has no vector dimension", sc.config.Index)
+	}
+	return dim, nil
+}
+
+// forgetIndex makes the next Set check the index again.
+func (sc *SemanticCache) forgetIndex() {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.dim = 0
+}
+
+// isUnknownIndex reports whether err says the index does not exist.
+func isUnknownIndex(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "no such index") || strings.Contains(msg, "unknown index")
+}
+
+// findDim returns the value following the first "dim" field in the nested arrays of an
+// FT.INFO reply, the dimension of the vector attribute.
+func findDim(reply interface{}) (int, bool) {
+	items, ok := reply.([]interface{})
+	if !ok {
+		return 0, false
+	}
+	for i, item := range items {
+		if name, ok := stringReply(item); ok && strings.EqualFold(name, "dim") && i+1 < len(items) {
+			switch v := items[i+1].(type) {
+			case int64:
+				return int(v), true
+			default:
+				if s, ok := stringReply(v); ok {
+					if dim, err := strconv.Atoi(s); err == nil {
+						return dim, true
+					}
+				}
+			}
+		}
+		if dim, ok := findDim(item); ok {
+			return dim, true
+		}
+	}
+	return 0, false
+}
+
+// searchDoc is a document of an FT.SEARCH reply.
+type searchDoc struct {
+	key    string
+	fields map[string][]byte
+}
+
+// parseSearch decodes an FT.SEARCH reply: the total, then each key followed by its
+// fields unless NOCONTENT was given.
+func parseSearch(reply interface{}) []searchDoc {
+	items, ok := reply.([]interface{})
+	if !ok || len(items) < 1 {
+		return nil
+	}
+
+	var docs []searchDoc
+	for i := 1; i < len(items); i++ {
+		key, ok := stringReply(items[i])
+		if !ok {
+			continue
+		}
+		doc := searchDoc{key: key, fields: make(map[string][]byte)}
+		if i+1 < len(items) {
+			if fields, ok := items[i+1].([]interface{}); ok {
+				for j := 0; j+1 < len(fields); j += 2 {
+					name, _ := stringReply(fields[j])
+					value, _ := bytesReply(fields[j+1])
+					doc.fields[name] = value
+				}
+				i++
+			}
+		}
+		docs = append(docs, doc)
+	}
+	return docs
+}
+
+// tagValue returns a hash of s that can be used as a TAG value without escaping.
+func tagValue(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:16])
+}
+
+// vectorBlob encodes vector as little-endian float32s, the format of RediSearch vectors.
+func vectorBlob(vector []float32) []byte {
+	blob := make([]byte, 4*len(vector))
+	for i, v := range vector {
+		binary.LittleEndian.PutUint32(blob[4*i:], math.Float32bits(v))
+	}
+	return blob
+}
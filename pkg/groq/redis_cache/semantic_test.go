@@ -0,0 +1,127 @@
+package redis_cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/genc-murat/groq-client/pkg/groq"
+)
+
+func TestSemanticCache(t *testing.T) {
+	fake := newFakeRedis()
+	ctx := context.Background()
+
+	config := DefaultSemanticConfig(groq.NewHashingEmbedder(0))
+	config.SimilarityThreshold = 0.8
+	cache, err := NewSemantic(fake, config)
+	if err != nil {
+		t.Fatalf("NewSemantic() error = %v", err)
+	}
+
+	if _, ok := cache.Get(ctx, "anything"); ok {
+		t.Error("expected a miss before the index exists")
+	}
+	if err := cache.Set(ctx, "What is the capital of France?", &groq.ChatCompletionResponse{ID: "paris"}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	_ = cache.Set(ctx, "How tall is Mount Everest?", &groq.ChatCompletionResponse{ID: "everest"})
+
+	if resp, ok := cache.Get(ctx, "what is the capital of france"); !ok || resp.ID != "paris" {
+		t.Errorf("Get() = %+v, %v; want the similar entry", resp, ok)
+	}
+	if _, ok := cache.Get(ctx, "who wrote hamlet"); ok {
+		t.Error("expected a miss for an unrelated query")
+	}
+	if fake.ttls["groq:semantic:How tall is Mount Everest?"] != config.TTL.Milliseconds() {
+		t.Errorf("expected the entries to expire after the TTL, got %v", fake.ttls)
+	}
+	_ = cache.SetWithTTL(ctx, "What is the latest news?", &groq.ChatCompletionResponse{ID: "news"}, time.Minute)
+	if fake.ttls["groq:semantic:What is the latest news?"] != 60000 {
+		t.Errorf("SetWithTTL() TTL = %dms, want 60000", fake.ttls["groq:semantic:What is the latest news?"])
+	}
+	_ = cache.Delete(ctx, "What is the latest news?")
+
+	// A second cache sharing the index does not fail on FT.CREATE.
+	other, _ := NewSemantic(fake, config)
+	if err := other.Set(ctx, "hello", &groq.ChatCompletionResponse{}); err != nil {
+		t.Errorf("Set() on an existing index error = %v", err)
+	}
+
+	deleted, err := cache.DeleteSimilar(ctx, "capital of France", 0.5)
+	if err != nil || len(deleted) != 1 || deleted[0] != "What is the capital of France?" {
+		t.Errorf("DeleteSimilar() = %v, %v", deleted, err)
+	}
+	keys, _ := cache.Keys(ctx)
+	if len(keys) != 2 {
+		t.Errorf("Keys() = %v, want 2 remaining entries", keys)
+	}
+}
+
+func TestSemanticConfigValidate(t *testing.T) {
+	_, err := NewSemantic(newFakeRedis(), DefaultSemanticConfig(nil))
+	if !errors.Is(err, groq.ErrInvalidConfig) {
+		t.Errorf("expected a missing embedder to be rejected, got %v", err)
+	}
+}
+
+func TestSemanticCacheScopes(t *testing.T) {
+	ctx := context.Background()
+	cache, _ := NewSemantic(newFakeRedis(), DefaultSemanticConfig(groq.NewHashingEmbedder(0)))
+
+	question := groq.NewRequest(groq.ModelLlama31_8bInstant).User("what is the capital of France").Build()
+	_ = cache.Set(ctx, groq.CacheKey("", question), &groq.ChatCompletionResponse{ID: "8b"})
+
+	other := groq.NewRequest(groq.ModelLlama33_70bVersatile).User("what is the capital of France").Build()
+	if _, ok := cache.Get(ctx, groq.CacheKey("", other)); ok {
+		t.Error("expected a miss for the same question to another model")
+	}
+	if resp, ok := cache.Get(ctx, groq.CacheKey("", question)); !ok || resp.ID != "8b" {
+		t.Errorf("Get() = %+v, %v", resp, ok)
+	}
+}
+
+func TestSemanticCacheStoresAtomically(t *testing.T) {
+	fake := newFakeRedis()
+	ctx := context.Background()
+	config := DefaultSemanticConfig(groq.NewHashingEmbedder(0))
+	config.TTL = 0
+	cache, _ := NewSemantic(fake, config)
+
+	_ = cache.SetWithTTL(ctx, "q", &groq.ChatCompletionResponse{ID: "short"}, time.Minute)
+	_ = cache.Set(ctx, "q", &groq.ChatCompletionResponse{ID: "kept"})
+	for _, command := range fake.commands {
+		if command == "HSET" || command == "PEXPIRE" {
+			t.Errorf("entry stored with a separate %s", command)
+		}
+	}
+	if ttl, ok := fake.ttls["groq:semantic:q"]; ok {
+		t.Errorf("replaced entry kept the TTL %dms of the old one", ttl)
+	}
+}
+
+func TestSemanticCacheIndexChanges(t *testing.T) {
+	fake := newFakeRedis()
+	ctx := context.Background()
+	cache, _ := NewSemantic(fake, DefaultSemanticConfig(groq.NewHashingEmbedder(0)))
+	_ = cache.Set(ctx, "What is the capital of France?", &groq.ChatCompletionResponse{ID: "paris"})
+
+	// Another embedder on the same index fails instead of missing silently.
+	resized, _ := NewSemantic(fake, DefaultSemanticConfig(groq.NewHashingEmbedder(64)))
+	if err := resized.Set(ctx, "hello", &groq.ChatCompletionResponse{}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("Set() with another dimension error = %v, want ErrDimensionMismatch", err)
+	}
+
+	// A dropped index is recreated by the next Set.
+	_, _ = fake.Do(ctx, "FT.DROPINDEX", "groq-semantic-idx")
+	if _, ok := cache.Get(ctx, "What is the capital of France?"); ok {
+		t.Fatal("expected a miss without the index")
+	}
+	if err := cache.Set(ctx, "How tall is Mount Everest?", &groq.ChatCompletionResponse{ID: "everest"}); err != nil {
+		t.Fatalf("Set() after the index was dropped error = %v", err)
+	}
+	if resp, ok := cache.Get(ctx, "What is the capital of France?"); !ok || resp.ID != "paris" {
+		t.Errorf("Get() = %+v, %v after the index was recreated", resp, ok)
+	}
+}
@@ -0,0 +1,113 @@
+package groq
+
+type RequestBuilder struct {
+	req *ChatCompletionRequest
+}
+
+// NewRequest starts a fluent RequestBuilder for the given model, reducing the boilerplate
+// of writing ChatCompletionRequest and ChatMessage literals by hand.
+//
+// Example:
+//
+//	req := groq.NewRequest(groq.ModelLlama4Scout).
+//	    System("You are a helpful assistant.").
+//	    User("What's in this image?").
+//	    WithImage("https://example.com/cat.jpg").
+//	    Temperature(0.2).
+//	    Build()
+func NewRequest(model ModelType) *RequestBuilder {
+	return &RequestBuilder{
+		req: &ChatCompletionRequest{
+			Model:    model,
+			Messages: make([]ChatMessage, 0),
+		},
+	}
+}
+
+// System appends a system message.
+func (b *RequestBuilder) System(content string) *RequestBuilder {
+	return b.Message("system", content)
+}
+
+// User appends a user message.
+func (b *RequestBuilder) User(content string) *RequestBuilder {
+	return b.Message("user", content)
+}
+
+// Assistant appends an assistant message, e.g. to replay earlier turns.
+func (b *RequestBuilder) Assistant(content string) *RequestBuilder {
+	return b.Message("assistant", content)
+}
+
+// Message appends a message with an arbitrary role and content.
+func (b *RequestBuilder) Message(role string, content interface{}) *RequestBuilder {
+	b.req.Messages = append(b.req.Messages, ChatMessage{Role: role, Content: content})
+	return b
+}
+
+// WithImage attaches an image (URL or base64 data URI) to the last user message,
+// converting it to multimodal content if needed. If there is no user message yet,
+// a new user message containing only the image is appended.
+func (b *RequestBuilder) WithImage(url string) *RequestBuilder {
+	return b.withImageContent(NewImageURLContent(url))
+}
+
+// WithImageDetail attaches an image like WithImage with an explicit detail level.
+func (b *RequestBuilder) WithImageDetail(url string, detail ImageDetail) *RequestBuilder {
+	return b.withImageContent(NewImageURLContentWithDetail(url, detail))
+}
+
+// withImageContent attaches image content to the last user message.
+func (b *RequestBuilder) withImageContent(image ContentType) *RequestBuilder {
+	for i := len(b.req.Messages) - 1; i >= 0; i-- {
+		msg := &b.req.Messages[i]
+		if msg.Role != "user" {
+			continue
+		}
+
+		switch content := msg.Content.(type) {
+		case []ContentType:
+			msg.Content = append(content, image)
+		case string:
+			parts := []ContentType{}
+			if content != "" {
+				parts = append(parts, NewTextContent(content))
+			}
+			msg.Content = append(parts, image)
+		default:
+			continue
+		}
+		return b
+	}
+
+	return b.Message("user", []ContentType{image})
+}
+
+// Temperature sets the sampling temperature.
+func (b *RequestBuilder) Temperature(temperature float64) *RequestBuilder {
+	b.req.Temperature = &temperature
+	return b
+}
+
+// TopP sets the nucleus sampling probability mass.
+func (b *RequestBuilder) TopP(topP float64) *RequestBuilder {
+	b.req.TopP = &topP
+	return b
+}
+
+// Annotate adds an analytics annotation that is not sent to the API.
+func (b *RequestBuilder) Annotate(key, value string) *RequestBuilder {
+	b.req.Annotate(key, value)
+	return b
+}
+
+// MaxTokens sets the maximum number of tokens to generate.
+func (b *RequestBuilder) MaxTokens(tokens int) *RequestBuilder {
+	b.req.MaxTokens = tokens
+	return b
+}
+
+// Build returns the assembled request. The builder should not be reused afterwards.
+func (b *RequestBuilder) Build() *ChatCompletionRequest {
+	return b.req
+}
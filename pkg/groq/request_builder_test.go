@@ -0,0 +1,79 @@
+package groq
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRequestBuilder(t *testing.T) {
+	req := NewRequest(ModelLlama4Scout).
+		System("be brief").
+		User("what is this?").
+		WithImage("https://example.com/a.png").
+		Temperature(0.2).
+		MaxTokens(100).
+		Build()
+
+	if req.Model != ModelLlama4Scout || *req.Temperature != 0.2 || req.MaxTokens != 100 {
+		t.Fatalf("unexpected request parameters: %+v", req)
+	}
+	if len(req.Messages) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(req.Messages))
+	}
+
+	parts, ok := req.Messages[1].Content.([]ContentType)
+	if !ok || len(parts) != 2 {
+		t.Fatalf("expected user message to become multimodal, got %#v", req.Messages[1].Content)
+	}
+	if parts[0].Text != "what is this?" || parts[1].ImageURL.URL != "https://example.com/a.png" {
+		t.Errorf("unexpected content parts: %+v", parts)
+	}
+}
+
+func TestRequestBuilderImageWithoutUserMessage(t *testing.T) {
+	req := NewRequest(ModelLlama4Scout).WithImage("https://example.com/a.png").Build()
+
+	if len(req.Messages) != 1 || req.Messages[0].Role != "user" {
+		t.Fatalf("expected a new user message, got %+v", req.Messages)
+	}
+}
+
+func TestRequestBuilderImageDetail(t *testing.T) {
+	req := NewRequest(ModelLlama4Scout).User("read the receipt").WithImageDetail("https://example.com/r.png", ImageDetailHigh).Build()
+
+	data, err := json.Marshal(req.Messages[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"image_url":{"url":"https://example.com/r.png","detail":"high"}`) {
+		t.Errorf("detail not sent: %s", data)
+	}
+
+	req.Messages[0].Content.([]ContentType)[1].ImageURL.Detail = "ultra"
+	if err := req.Validate(); err == nil || !strings.Contains(err.Error(), `"ultra"`) {
+		t.Errorf("Validate() error = %v, want invalid detail", err)
+	}
+}
+
+func TestRequestBuilderExplicitZeroSampling(t *testing.T) {
+	req := NewRequest(ModelLlama31_8bInstant).User("hi").Temperature(0).TopP(0).Build()
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"temperature":0`) || !strings.Contains(string(data), `"top_p":0`) {
+		t.Errorf("expected explicit zero temperature and top_p to be sent, got %s", data)
+	}
+
+	data, _ = json.Marshal(NewRequest(ModelLlama31_8bInstant).User("hi").Build())
+	if strings.Contains(string(data), "temperature") || strings.Contains(string(data), "top_p") {
+		t.Errorf("expected unset sampling parameters to be omitted, got %s", data)
+	}
+
+	req.Temperature = Float64(2.5)
+	if err := req.Validate(); err == nil || !strings.Contains(err.Error(), "temperature") {
+		t.Errorf("expected out-of-range temperature to be rejected, got %v", err)
+	}
+}
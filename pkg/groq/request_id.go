@@ -0,0 +1,76 @@
+package groq
+
+import (
+	"context"
+
+	"github.com/genc-murat/groq-client/internal/util"
+)
+
+// RequestIDs identifies a request: Client is the X-Request-ID the client sent, Server
+// the ID the API answered with, which Groq support asks for.
+type RequestIDs = util.RequestIDs
+
+// NewRequestID returns a random UUID, the default X-Request-ID of requests.
+func NewRequestID() string {
+	return util.NewRequestID()
+}
+
+// WithRequestIDGenerator sets how the X-Request-ID header sent with every request is
+// generated. By default each call gets a random UUID, which is kept across its retries
+// and reported in APIError.ClientRequestID, logs and hooks.
+//
+// Parameters:
+//   - generate: Returns a new ID; nil stops sending the header.
+//
+// Returns:
+//   - Option: A function that sets the generator.
+func WithRequestIDGenerator(generate func() string) Option {
+	return func(c *Client) {
+		c.requestIDGenerator = generate
+	}
+}
+
+// WithIdempotencyKeys makes the client send a generated Idempotency-Key header with every
+// POST request, kept across retries, so a retry of a request the server already processed
+// can be recognised. ContextWithIdempotencyKey sets the key of individual calls instead.
+//
+// Returns:
+//   - Option: A function that enables idempotency keys.
+func WithIdempotencyKeys() Option {
+	return func(c *Client) {
+		c.idempotencyKeys = true
+	}
+}
+
+// ContextWithRequestID returns a copy of ctx whose calls send id as their X-Request-ID,
+// e.g. the ID of the incoming request being served, to correlate logs across services.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return util.ContextWithRequestID(ctx, id)
+}
+
+// ContextWithIdempotencyKey returns a copy of ctx whose calls send key as their
+// Idempotency-Key header. Reuse the key when repeating an operation that may have
+// succeeded, such as a batch submission after a timeout.
+func ContextWithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return util.ContextWithIdempotencyKey(ctx, key)
+}
+
+// ContextWithRequestIDs returns a copy of ctx that records the client and server IDs of
+// its call in the returned RequestIDs once the call completes, successful or not.
+//
+// Example usage:
+//
+//	ctx, ids := ContextWithRequestIDs(ctx)
+//	resp, err := client.CreateChatCompletion(ctx, req)
+//	log.Printf("request %s (server %s)", ids.Client, ids.Server)
+//
+// Parameters:
+//   - ctx: The parent context; use it for one call at a time.
+//
+// Returns:
+//   - context.Context: The context to make the call with.
+//   - *RequestIDs: Filled in when the call completes.
+func ContextWithRequestIDs(ctx context.Context) (context.Context, *RequestIDs) {
+	ids := &RequestIDs{}
+	return util.ContextWithRequestIDs(ctx, ids), ids
+}
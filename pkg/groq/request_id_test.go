@@ -0,0 +1,88 @@
+package groq
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRequestIDs(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		ids     []string
+		keys    []string
+		failed  bool
+		replied = `{"id":"1","choices":[{"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}]}`
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		ids = append(ids, r.Header.Get("X-Request-ID"))
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		w.Header().Set("x-request-id", "req_server")
+		if !failed {
+			failed = true
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(replied))
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key", WithBaseURL(srv.URL), WithRetryConfig(1, time.Millisecond), WithIdempotencyKeys())
+	defer client.Close()
+
+	ctx, captured := ContextWithRequestIDs(context.Background())
+	_, err := client.CreateChatCompletion(ctx, NewRequest(ModelLlama31_8bInstant).User("hi").Build())
+	if err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+
+	if len(ids) != 2 || ids[0] == "" || ids[0] != ids[1] {
+		t.Errorf("expected one X-Request-ID kept across the retry, got %q", ids)
+	}
+	if len(ids[0]) != 36 {
+		t.Errorf("expected a UUID, got %q", ids[0])
+	}
+	if keys[0] == "" || keys[0] != keys[1] {
+		t.Errorf("expected one Idempotency-Key kept across the retry, got %q", keys)
+	}
+	if captured.Client != ids[0] || captured.Server != "req_server" {
+		t.Errorf("unexpected captured IDs: %+v", captured)
+	}
+
+	ctx = ContextWithIdempotencyKey(ContextWithRequestID(context.Background(), "mine"), "op-1")
+	if _, err := client.CreateChatCompletion(ctx, NewRequest(ModelLlama31_8bInstant).User("again").Build()); err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if got := ids[len(ids)-1]; got != "mine" {
+		t.Errorf("X-Request-ID = %q, want the one from the context", got)
+	}
+	if got := keys[len(keys)-1]; got != "op-1" {
+		t.Errorf("Idempotency-Key = %q, want the one from the context", got)
+	}
+}
+
+func TestWithRequestIDGeneratorNil(t *testing.T) {
+	var header []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Values("X-Request-ID")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","choices":[]}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key", WithBaseURL(srv.URL), WithRequestIDGenerator(nil))
+	defer client.Close()
+
+	if _, err := client.CreateChatCompletion(context.Background(), NewRequest(ModelLlama31_8bInstant).User("hi").Build()); err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if len(header) != 0 {
+		t.Errorf("expected no X-Request-ID, got %q", header)
+	}
+}
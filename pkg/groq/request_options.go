@@ -0,0 +1,121 @@
+package groq
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/genc-murat/groq-client/internal/util"
+)
+
+// RequestOption customizes a single call of CreateChatCompletion, CreateChatCompletionStream,
+// CreateTranscription, CreateTranslation or CreateSpeech without building a second client.
+type RequestOption func(*requestOptions)
+
+// requestOptions holds the settings of the RequestOptions of one call.
+type requestOptions struct {
+	apiKey  string
+	baseURL string
+	timeout time.Duration
+	headers map[string]string
+}
+
+// WithRequestTimeout bounds the call, including its retries and, for streams, the time
+// spent reading the stream. It applies in addition to the deadline of the context.
+//
+// Parameters:
+//   - timeout: The longest the call may take; zero keeps the client's timeouts only.
+//
+// Returns:
+//   - RequestOption: A function that sets the timeout of the call.
+func WithRequestTimeout(timeout time.Duration) RequestOption {
+	return func(o *requestOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithRequestHeader sends an additional header with the call, or replaces a header the
+// client would send, e.g. to tag the requests of a tenant. Repeat the option to set
+// several headers.
+//
+// Parameters:
+//   - key: The header name.
+//   - value: The header value.
+//
+// Returns:
+//   - RequestOption: A function that adds the header to the call.
+func WithRequestHeader(key, value string) RequestOption {
+	return func(o *requestOptions) {
+		if o.headers == nil {
+			o.headers = make(map[string]string)
+		}
+		o.headers[key] = value
+	}
+}
+
+// WithRequestAPIKey sends the call with another API key, e.g. the key of the tenant the
+// call is made for. The key replaces the client's key and the keys of WithAPIKeys. Its
+// hash is part of the cache key, so cached answers are never shared between keys.
+//
+// Parameters:
+//   - apiKey: The API key of the call.
+//
+// Returns:
+//   - RequestOption: A function that sets the API key of the call.
+func WithRequestAPIKey(apiKey string) RequestOption {
+	return func(o *requestOptions) {
+		o.apiKey = apiKey
+	}
+}
+
+// WithRequestBaseURL sends the call to another base URL, e.g. a regional endpoint. Unless
+// the URL is one of WithBaseURLs, the call does not fail over to other endpoints.
+//
+// Parameters:
+//   - baseURL: The base URL of the call, such as DefaultBaseURL.
+//
+// Returns:
+//   - RequestOption: A function that sets the base URL of the call.
+func WithRequestBaseURL(baseURL string) RequestOption {
+	return func(o *requestOptions) {
+		o.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+type requestBaseURLKey struct{}
+
+// applyRequestOptions returns a copy of ctx carrying the settings of opts. The returned
+// cancel function releases the timeout and must be called when the call is done.
+func applyRequestOptions(ctx context.Context, opts []RequestOption) (context.Context, context.CancelFunc) {
+	if len(opts) == 0 {
+		return ctx, func() {}
+	}
+
+	var o requestOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.apiKey != "" || len(o.headers) > 0 {
+		ctx = util.ContextWithRequestOverrides(ctx, &util.RequestOverrides{
+			APIKey:  o.apiKey,
+			Headers: o.headers,
+		})
+	}
+	if o.baseURL != "" {
+		ctx = context.WithValue(ctx, requestBaseURLKey{}, o.baseURL)
+	}
+	if o.timeout > 0 {
+		return context.WithTimeout(ctx, o.timeout)
+	}
+	return ctx, func() {}
+}
+
+// endpointURL returns the URL of an API path below the base URL of the call, which is the
+// client's unless WithRequestBaseURL changed it.
+func (c *Client) endpointURL(ctx context.Context, path string) string {
+	if baseURL, ok := ctx.Value(requestBaseURLKey{}).(string); ok {
+		return baseURL + path
+	}
+	return c.baseURL + path
+}
@@ -0,0 +1,94 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRequestOptions(t *testing.T) {
+	var seen []*http.Request
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, r)
+		if r.Header.Get("X-Slow") != "" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","choices":[{"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}]}`))
+	}
+	primary := httptest.NewServer(http.HandlerFunc(handler))
+	defer primary.Close()
+	regional := httptest.NewServer(http.HandlerFunc(handler))
+	defer regional.Close()
+
+	client := NewClient("client-key", WithBaseURL(primary.URL), WithAPIKeys([]string{"pool-a", "pool-b"}))
+	defer client.Close()
+
+	_, err := client.CreateChatCompletion(context.Background(), NewRequest(ModelLlama31_8bInstant).User("hi").Build(),
+		WithRequestAPIKey("tenant-key"),
+		WithRequestHeader("X-Tenant", "acme"),
+		WithRequestBaseURL(regional.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	last := seen[len(seen)-1]
+	if got := last.Header.Get("Authorization"); got != "Bearer tenant-key" {
+		t.Errorf("Authorization = %q, want the key of the call", got)
+	}
+	if got := last.Header.Get("X-Tenant"); got != "acme" {
+		t.Errorf("X-Tenant = %q, want acme", got)
+	}
+	if !strings.HasPrefix("http://"+last.Host, regional.URL) || last.URL.Path != "/chat/completions" {
+		t.Errorf("request went to %s%s, want the regional base URL", last.Host, last.URL.Path)
+	}
+
+	if _, err := client.CreateChatCompletion(context.Background(), NewRequest(ModelLlama31_8bInstant).User("again").Build()); err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	last = seen[len(seen)-1]
+	if got := last.Header.Get("Authorization"); got != "Bearer pool-a" || last.Header.Get("X-Tenant") != "" {
+		t.Errorf("options leaked into the next call: Authorization %q, X-Tenant %q", got, last.Header.Get("X-Tenant"))
+	}
+
+	_, err = client.CreateChatCompletion(context.Background(), NewRequest(ModelLlama31_8bInstant).User("slow").Build(),
+		WithRequestHeader("X-Slow", "1"),
+		WithRequestTimeout(50*time.Millisecond),
+	)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the call to time out, got %v", err)
+	}
+}
+
+func TestRequestAPIKeyCache(t *testing.T) {
+	var keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","choices":[{"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}]}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient("client-key", WithBaseURL(srv.URL), WithCache(NewLRUCache(10, time.Minute)))
+	defer client.Close()
+
+	ask := func(opts ...RequestOption) {
+		t.Helper()
+		if _, err := client.CreateChatCompletion(context.Background(), NewRequest(ModelLlama31_8bInstant).User("same prompt").Build(), opts...); err != nil {
+			t.Fatalf("CreateChatCompletion: %v", err)
+		}
+	}
+	ask(WithRequestAPIKey("tenant-a"))
+	ask(WithRequestAPIKey("tenant-b"))
+	ask(WithRequestAPIKey("tenant-a"))
+	ask()
+
+	want := []string{"Bearer tenant-a", "Bearer tenant-b", "Bearer client-key"}
+	if strings.Join(keys, ",") != strings.Join(want, ",") {
+		t.Errorf("requests sent with %v, want %v: each key has its own cache entries", keys, want)
+	}
+}
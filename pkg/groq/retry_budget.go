@@ -0,0 +1,54 @@
+package groq
+
+import (
+	"context"
+	"time"
+
+	"github.com/genc-murat/groq-client/internal/util"
+)
+
+// ErrRetryBudgetExhausted is matched by errors of calls that stopped retrying because
+// their retry budget was used up; the error also wraps the last response's error.
+var ErrRetryBudgetExhausted = util.ErrRetryBudgetExhausted
+
+// WithMaxRetryElapsed bounds the worst-case latency of every call, streams included: no
+// retry starts if it would begin later than d after the call's first attempt, and the call
+// fails with an error matching ErrRetryBudgetExhausted and the last API error instead.
+// Attempts already running are still bounded only by the request timeout.
+//
+// Example usage:
+//
+//	client := NewClient(apiKey, WithMaxRetryElapsed(10*time.Second))
+//
+// Parameters:
+//   - d: The time budget of one call including its retries; zero removes the limit.
+//
+// Returns:
+//   - Option: A function that applies the budget to the client.
+func WithMaxRetryElapsed(d time.Duration) Option {
+	return func(c *Client) {
+		c.claim("retry budget", "WithMaxRetryElapsed")
+		c.config.RetryConfig.MaxRetryElapsed = d
+	}
+}
+
+// ContextWithRetryBudget returns a copy of ctx whose calls share one retry budget, so a
+// logical operation made of several calls, such as a stream followed by tool calls, is
+// bounded as a whole. The budget replaces the client's WithMaxRetryElapsed for those calls;
+// each request is still retried at most as often as the client's retry configuration allows.
+//
+// Example usage:
+//
+//	ctx = ContextWithRetryBudget(ctx, 5, 30*time.Second)
+//	err := client.CreateChatCompletionStream(ctx, req, handler)
+//
+// Parameters:
+//   - ctx: The parent context.
+//   - maxRetries: Retries allowed across all calls; zero for no limit.
+//   - maxElapsed: No retry starts later than this after the first attempt; zero for no limit.
+//
+// Returns:
+//   - context.Context: The context carrying the budget.
+func ContextWithRetryBudget(ctx context.Context, maxRetries int, maxElapsed time.Duration) context.Context {
+	return util.ContextWithRetryBudget(ctx, &util.RetryBudget{MaxRetries: maxRetries, MaxElapsed: maxElapsed})
+}
@@ -0,0 +1,65 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestContextWithRetryBudget(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"error":{"message":"Service unavailable","type":"internal_server_error"}}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key", WithBaseURL(srv.URL), WithRetryConfig(5, time.Millisecond))
+	ctx := ContextWithRetryBudget(context.Background(), 2, 0)
+
+	err := client.CreateChatCompletionStream(ctx, NewRequest(ModelLlama31_8bInstant).User("hi").Build(), func(*ChatCompletionChunk) error {
+		return nil
+	})
+	if !errors.Is(err, ErrRetryBudgetExhausted) {
+		t.Fatalf("expected ErrRetryBudgetExhausted, got %v", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected the last API error to be wrapped, got %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("expected 3 attempts, got %d", got)
+	}
+
+	// The budget is shared, so a second call on the same context is not retried.
+	_, err = client.CreateChatCompletion(ctx, NewRequest(ModelLlama31_8bInstant).User("hi").Build())
+	if !errors.Is(err, ErrRetryBudgetExhausted) {
+		t.Fatalf("expected ErrRetryBudgetExhausted, got %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 4 {
+		t.Errorf("expected 4 attempts, got %d", got)
+	}
+}
+
+func TestWithMaxRetryElapsed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key", WithBaseURL(srv.URL), WithRetryConfig(10, 50*time.Millisecond), WithMaxRetryElapsed(200*time.Millisecond))
+	start := time.Now()
+	_, err := client.ListModels(context.Background())
+	if !errors.Is(err, ErrRetryBudgetExhausted) {
+		t.Fatalf("expected ErrRetryBudgetExhausted, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("call took %v, want it bounded by the retry budget", elapsed)
+	}
+}
@@ -0,0 +1,154 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"time"
+)
+
+const defaultRefusalMessage = "Sorry, I can't help with that. Let's talk about something else."
+
+const consumerSafetySystemPrompt = "You are talking with a general audience that may include children. " +
+	"Keep answers age-appropriate, do not produce sexual, violent, hateful or dangerous content, " +
+	"do not ask for or share personal contact details, and politely decline requests that are not suitable."
+
+// personalDataFilter blocks answers that contain e-mail addresses or phone numbers.
+// Phone numbers need an international prefix, an area code in parentheses or the
+// 3-3-4 grouping, so order numbers, years and dates pass.
+var personalDataFilter = &RegexDenyList{
+	name: "personal-data",
+	patterns: []*regexp.Regexp{
+		regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+		regexp.MustCompile(`\+\d{1,3}[\s.-]?(?:\(\d{1,4}\)[\s.-]?)?\d{2,4}(?:[\s.-]?\d{2,4}){1,4}\b`),
+		regexp.MustCompile(`\(\d{2,4}\)[\s.-]?\d{3,4}[\s.-]?\d{3,4}\b`),
+		regexp.MustCompile(`\b\d{3}[\s.-]\d{3}[\s.-]\d{4}\b`),
+	},
+}
+
+type SafetyProfile struct {
+	GuardModel      ModelType          // Llama Guard model screening input and output; empty disables it
+	DetectInjection bool               // Screen user messages for prompt-injection attempts
+	MaxTemperature  float64            // Higher or unset temperatures are lowered to this; 0 disables the cap
+	SystemPrompt    string             // Safety instructions prepended to every request; empty disables it
+	OutputFilters   []GuardrailChecker // Additional checks of every answer
+	RefusalMessage  string             // Returned as the answer when a check blocks; empty returns the error
+	OnViolation     func(ctx context.Context, violation *PolicyViolationError)
+}
+
+// ConsumerSafetyProfile returns safe defaults for consumer-facing apps that may be used
+// by minors: Llama Guard moderation of input and output, prompt-injection detection,
+// temperature capped at 0.7, an age-appropriate system prompt, an output filter for
+// e-mail addresses and phone numbers, and a polite refusal instead of an error when
+// something is blocked. Every field can be changed before passing it to WithSafetyProfile.
+//
+// Returns:
+//   - SafetyProfile: The profile.
+func ConsumerSafetyProfile() SafetyProfile {
+	return SafetyProfile{
+		GuardModel:      ModelLlamaGuard4_12b,
+		DetectInjection: true,
+		MaxTemperature:  0.7,
+		SystemPrompt:    consumerSafetySystemPrompt,
+		OutputFilters:   []GuardrailChecker{personalDataFilter},
+		RefusalMessage:  defaultRefusalMessage,
+	}
+}
+
+// safetyPolicy is a SafetyProfile bound to a client.
+type safetyPolicy struct {
+	profile SafetyProfile
+	input   []GuardrailChecker
+	output  []GuardrailChecker
+}
+
+// WithSafetyProfile enables a safety profile on the client, typically in one line:
+//
+//	client := NewClient(apiKey, WithSafetyProfile(ConsumerSafetyProfile()))
+//
+// The profile's checks run in addition to any WithGuardrails checkers. Chat completions
+// that are blocked return a response with the refusal message and finish reason
+// content_filter, or a *PolicyViolationError if RefusalMessage is empty. Streaming requests
+// are screened on input and output: chunks are held back until the answer has passed the
+// output checks, and blocked streams always return the error.
+func WithSafetyProfile(profile SafetyProfile) Option {
+	return func(c *Client) {
+		policy := &safetyPolicy{profile: profile}
+
+		if profile.GuardModel != "" {
+			guard := NewLlamaGuard(c)
+			guard.Model = profile.GuardModel
+			policy.input = append(policy.input, guard)
+			policy.output = append(policy.output, guard)
+		}
+		if profile.DetectInjection {
+			policy.input = append(policy.input, NewPromptInjectionDetector())
+		}
+		policy.output = append(policy.output, profile.OutputFilters...)
+
+		c.safety = policy
+	}
+}
+
+// apply returns req with the profile's system prompt and temperature cap applied.
+// The caller's request is not modified.
+func (p *safetyPolicy) apply(req *ChatCompletionRequest) *ChatCompletionRequest {
+	// An unset temperature means the API default of 1, so it is capped as well.
+	capTemperature := p.profile.MaxTemperature > 0 &&
+		(req.Temperature == nil || *req.Temperature > p.profile.MaxTemperature)
+	if !capTemperature && p.profile.SystemPrompt == "" {
+		return req
+	}
+
+	safe := *req
+	if capTemperature {
+		safe.Temperature = Float64(p.profile.MaxTemperature)
+	}
+	if p.profile.SystemPrompt != "" {
+		safe.Messages = make([]ChatMessage, 0, len(req.Messages)+1)
+		safe.Messages = append(safe.Messages, ChatMessage{Role: "system", Content: p.profile.SystemPrompt})
+		safe.Messages = append(safe.Messages, req.Messages...)
+	}
+	return &safe
+}
+
+// checkers returns the profile's checkers of a stage.
+func (p *safetyPolicy) checkers(stage GuardrailStage) []GuardrailChecker {
+	if stage == GuardrailOutput {
+		return p.output
+	}
+	return p.input
+}
+
+// report passes a policy violation to OnViolation and reports whether err is one.
+func (p *safetyPolicy) report(ctx context.Context, err error) bool {
+	var violation *PolicyViolationError
+	if !errors.As(err, &violation) {
+		return false
+	}
+	if p.profile.OnViolation != nil {
+		p.profile.OnViolation(ctx, violation)
+	}
+	return true
+}
+
+// refuse turns a policy violation into a refusal answer if the profile has one.
+func (p *safetyPolicy) refuse(ctx context.Context, req *ChatCompletionRequest, err error) (*ChatCompletionResponse, error) {
+	if !p.report(ctx, err) || p.profile.RefusalMessage == "" {
+		return nil, err
+	}
+
+	resp := &ChatCompletionResponse{
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Model:   req.Model,
+	}
+	resp.Choices = append(resp.Choices, struct {
+		Message      ChatMessage  `json:"message"`
+		FinishReason FinishReason `json:"finish_reason"`
+	}{
+		Message:      ChatMessage{Role: "assistant", Content: p.profile.RefusalMessage},
+		FinishReason: FinishReasonContentFilter,
+	})
+	return resp, nil
+}
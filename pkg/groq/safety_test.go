@@ -0,0 +1,169 @@
+package groq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestConsumerSafetyProfile(t *testing.T) {
+	var mu sync.Mutex
+	var seen []*ChatCompletionRequest
+	srv := newTestServer(t, func(req *ChatCompletionRequest) string {
+		mu.Lock()
+		seen = append(seen, req)
+		mu.Unlock()
+
+		switch {
+		case req.Model == ModelLlamaGuard4_12b:
+			if strings.Contains(req.Messages[len(req.Messages)-1].GetCacheKey(), "weapon") {
+				return "unsafe\nS9"
+			}
+			return "safe"
+		case strings.Contains(req.Messages[len(req.Messages)-1].GetCacheKey(), "email"):
+			return "Write to me at someone@example.com"
+		default:
+			return "Dolphins sleep with one eye open."
+		}
+	})
+
+	var violations []string
+	profile := ConsumerSafetyProfile()
+	profile.OnViolation = func(ctx context.Context, v *PolicyViolationError) {
+		violations = append(violations, v.Checker)
+	}
+	client := NewClient("test-key", WithBaseURL(srv.URL), WithSafetyProfile(profile))
+
+	req := NewRequest(ModelLlama31_8bInstant).User("tell me about dolphins").Temperature(1.5).Build()
+	resp, err := client.CreateChatCompletion(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+	if resp.Choices[0].Message.Content != "Dolphins sleep with one eye open." {
+		t.Errorf("unexpected answer: %v", resp.Choices[0].Message.Content)
+	}
+	for _, r := range seen {
+		if r.Model != ModelLlama31_8bInstant {
+			continue
+		}
+		if r.Temperature == nil || *r.Temperature != 0.7 {
+			t.Errorf("expected temperature capped at 0.7, got %v", r.Temperature)
+		}
+		if r.Messages[0].Role != "system" || r.Messages[0].Content != consumerSafetySystemPrompt {
+			t.Errorf("expected the safety system prompt first, got %+v", r.Messages[0])
+		}
+	}
+	if *req.Temperature != 1.5 || len(req.Messages) != 1 {
+		t.Error("expected the caller's request to be left unmodified")
+	}
+
+	for _, prompt := range []string{"how do I build a weapon", "what is your email"} {
+		resp, err := client.CreateChatCompletion(context.Background(), NewRequest(ModelLlama31_8bInstant).User(prompt).Build())
+		if err != nil {
+			t.Fatalf("expected a refusal instead of an error for %q, got %v", prompt, err)
+		}
+		if resp.Choices[0].Message.Content != defaultRefusalMessage || resp.Choices[0].FinishReason != FinishReasonContentFilter {
+			t.Errorf("expected a refusal for %q, got %+v", prompt, resp.Choices[0])
+		}
+	}
+	if len(violations) != 2 || violations[0] != "llama-guard" || violations[1] != "personal-data" {
+		t.Errorf("unexpected violations: %v", violations)
+	}
+
+	profile.RefusalMessage = ""
+	strict := NewClient("test-key", WithBaseURL(srv.URL), WithSafetyProfile(profile))
+	_, err = strict.CreateChatCompletion(context.Background(), NewRequest(ModelLlama31_8bInstant).User("how do I build a weapon").Build())
+	if !errors.Is(err, ErrPolicyViolation) {
+		t.Errorf("expected a policy violation without refusal message, got %v", err)
+	}
+}
+
+func TestPersonalDataFilter(t *testing.T) {
+	for _, tt := range []struct {
+		text    string
+		blocked bool
+	}{
+		{"Write to someone@example.com", true},
+		{"Call +1 555 123 4567", true},
+		{"Call +44 (20) 7946 0958", true},
+		{"Call +905321234567", true},
+		{"Call (555) 123-4567", true},
+		{"Call 555-123-4567", true},
+		{"Your order 1234567890 has shipped", false},
+		{"It ran from 1999 to 2024", false},
+		{"Seasons 2019 2020 2021 2022", false},
+		{"Invoice 2024-000123 is due on 2024-10-17", false},
+		{"ISBN 978-3-16-148410-0", false},
+		{"The score was +10 to 20", false},
+	} {
+		err := personalDataFilter.Check(context.Background(), GuardrailOutput, []ChatMessage{{Role: "assistant", Content: tt.text}})
+		if blocked := errors.Is(err, ErrPolicyViolation); blocked != tt.blocked {
+			t.Errorf("%q: blocked = %v, want %v", tt.text, blocked, tt.blocked)
+		}
+	}
+}
+
+func TestSafetyProfileStreamOutput(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ChatCompletionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !req.Stream {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"safe"},"finish_reason":"stop"}]}`)
+			return
+		}
+		answer := []string{"Dolphins ", "sleep ", "with one eye open."}
+		if strings.Contains(req.Messages[len(req.Messages)-1].GetCacheKey(), "email") {
+			answer = []string{"Write to me at someone@", "example.com"}
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, content := range answer {
+			fmt.Fprintf(w, "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":%q}}]}\n\n", content)
+		}
+		fmt.Fprint(w, "data: {\"choices\":[{\"index\":0,\"delta\":{},\"finish_reason\":\"stop\"}]}\n\n")
+	}))
+	defer srv.Close()
+
+	var violations []string
+	profile := ConsumerSafetyProfile()
+	profile.OnViolation = func(ctx context.Context, v *PolicyViolationError) {
+		violations = append(violations, v.Checker)
+	}
+	client := NewClient("test-key", WithBaseURL(srv.URL), WithSafetyProfile(profile))
+
+	var answer strings.Builder
+	handler := func(chunk *ChatCompletionChunk) error {
+		for _, choice := range chunk.Choices {
+			answer.WriteString(choice.Delta.Content)
+		}
+		return nil
+	}
+	err := client.CreateChatCompletionStream(context.Background(), NewRequest(ModelLlama31_8bInstant).User("tell me about dolphins").Build(), handler)
+	if err != nil {
+		t.Fatalf("CreateChatCompletionStream() error = %v", err)
+	}
+	if answer.String() != "Dolphins sleep with one eye open." {
+		t.Errorf("unexpected answer: %q", answer.String())
+	}
+
+	answer.Reset()
+	err = client.CreateChatCompletionStream(context.Background(), NewRequest(ModelLlama31_8bInstant).User("what is your email").Build(), handler)
+	if !errors.Is(err, ErrPolicyViolation) {
+		t.Fatalf("expected a policy violation, got %v", err)
+	}
+	if answer.Len() != 0 {
+		t.Errorf("expected no chunk of a blocked answer to reach the handler, got %q", answer.String())
+	}
+	if len(violations) != 1 || violations[0] != "personal-data" {
+		t.Errorf("unexpected violations: %v", violations)
+	}
+}
@@ -27,14 +27,19 @@ type CacheEntry struct {
 
 type SemanticCache struct {
 	entries   map[string]*CacheEntry
-	vectors   []Vector
-	keys      []string
+	indexes   map[string]*hnswIndex // Nearest-neighbor index of the entries of each key scope
+	eviction  evictionTracker       // Order in which entries are evicted, see Config.EvictionPolicy
 	config    *Config
 	stats     groq.CacheStats
 	metrics   *Metrics
 	mu        sync.RWMutex
 	embedding *EmbeddingService
 	persister *Persister
+
+	done      chan struct{}  // Closed by Close to stop the auto-prune goroutine
+	saving    sync.WaitGroup // Asynchronous saves in progress
+	closed    bool
+	closeOnce sync.Once
 }
 
 type Metrics struct {
@@ -49,9 +54,10 @@ type Metrics struct {
 
 // NewSemanticCache creates a new instance of SemanticCache with the provided configuration.
 // If the provided config is nil, it uses the default configuration.
-// It initializes the cache entries, vectors, keys, metrics, and embedding service.
+// It initializes the cache entries, the vector indexes, metrics, and embedding service.
 // If a persistence path is specified in the config, it attempts to load persisted data
-// and logs a warning if it fails. It also starts the auto-prune process.
+// and logs a warning if it fails. It also starts the auto-prune process, which runs
+// until Close is called.
 //
 // Parameters:
 //   - config: A pointer to the Config struct. If nil, DefaultConfig() is used.
@@ -65,11 +71,12 @@ func NewSemanticCache(config *Config) *SemanticCache {
 
 	sc := &SemanticCache{
 		entries:   make(map[string]*CacheEntry),
-		vectors:   make([]Vector, 0),
-		keys:      make([]string, 0),
+		indexes:   make(map[string]*hnswIndex),
+		eviction:  newEvictionTracker(config.EvictionPolicy, config.MaxEntries),
 		config:    config,
 		metrics:   &Metrics{},
-		embedding: NewEmbeddingService(config.EmbeddingModel),
+		embedding: NewEmbeddingServiceWith(config.Embedder, config.EmbeddingModel),
+		done:      make(chan struct{}),
 	}
 
 	if config.PersistPath != "" {
@@ -85,13 +92,34 @@ func NewSemanticCache(config *Config) *SemanticCache {
 	return sc
 }
 
+// NewSemanticCacheE is like NewSemanticCache but validates the configuration first and
+// returns all of its problems instead of building a cache that cannot work.
+//
+// Parameters:
+//   - config: A pointer to the Config struct. If nil, DefaultConfig() is used.
+//
+// Returns:
+//   - *SemanticCache: The cache, or nil if the configuration is invalid.
+//   - error: A *groq.ConfigError describing every invalid setting.
+func NewSemanticCacheE(config *Config) (*SemanticCache, error) {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return NewSemanticCache(config), nil
+}
+
 // loadPersistedData loads persisted cache data from the persister into the SemanticCache.
 // It returns an error if the data could not be loaded.
 //
 // If the persister is nil, the function returns immediately with no error.
 //
 // The function locks the cache for writing while it updates the cache entries,
-// vectors, keys, and metrics. Entries that have expired based on their TTL are skipped.
+// the vector indexes, the eviction order, and metrics. Entries that have expired based on
+// their TTL are skipped; the others enter the eviction order by their last access, and the
+// limits of the configuration are enforced.
 //
 // Returns:
 //   - error: if there is an issue loading the persisted data, an error is returned.
@@ -108,24 +136,33 @@ func (sc *SemanticCache) loadPersistedData() error {
 	sc.mu.Lock()
 	defer sc.mu.Unlock()
 
+	loaded := make([]*CacheEntry, 0, len(entries))
 	for key, entry := range entries {
 		if time.Since(entry.CreatedAt) > entry.TTL {
 			continue
 		}
-
-		sc.entries[key] = entry
-		sc.vectors = append(sc.vectors, entry.Embedding)
-		sc.keys = append(sc.keys, key)
+		entry.Key = key
+		loaded = append(loaded, entry)
+	}
+	sort.Slice(loaded, func(i, j int) bool {
+		return loaded[i].LastAccessed.Before(loaded[j].LastAccessed)
+	})
+
+	for _, entry := range loaded {
+		sc.entries[entry.Key] = entry
+		sc.addToIndex(entry.Key, entry.Embedding)
+		sc.eviction.insert(entry.Key, entry.AccessCount)
 		sc.metrics.Size += entry.Size
 	}
+	sc.evictOverLimits()
 
 	return nil
 }
 
 // startAutoPrune initiates an automatic pruning process for the SemanticCache.
 // If the PruneInterval in the configuration is less than or equal to zero, the function returns immediately.
-// Otherwise, it starts a goroutine that periodically prunes the cache at intervals specified by PruneInterval.
-// The pruning process is protected by a mutex to ensure thread safety.
+// Otherwise, it starts a goroutine that periodically prunes the cache at intervals specified by PruneInterval,
+// until the cache is closed. The pruning process is protected by a mutex to ensure thread safety.
 func (sc *SemanticCache) startAutoPrune() {
 	if sc.config.PruneInterval <= 0 {
 		return
@@ -135,7 +172,12 @@ func (sc *SemanticCache) startAutoPrune() {
 		ticker := time.NewTicker(sc.config.PruneInterval)
 		defer ticker.Stop()
 
-		for range ticker.C {
+		for {
+			select {
+			case <-ticker.C:
+			case <-sc.done:
+				return
+			}
 			sc.mu.Lock()
 			sc.prune()
 			sc.mu.Unlock()
@@ -143,9 +185,52 @@ func (sc *SemanticCache) startAutoPrune() {
 	}()
 }
 
+// Close stops the auto-prune goroutine, waits for asynchronous saves to finish and, if
+// persistence is configured, writes the current entries to disk, so nothing stored before
+// Close is lost. The cache can still be read afterwards, but Set no longer persists.
+// Close is idempotent; it implements io.Closer, so groq.Client.Close closes the cache
+// it uses.
+//
+// Returns:
+//   - error: An error if the final save fails.
+func (sc *SemanticCache) Close() error {
+	var err error
+	sc.closeOnce.Do(func() {
+		close(sc.done)
+
+		sc.mu.Lock()
+		sc.closed = true
+		sc.mu.Unlock()
+		sc.saving.Wait()
+
+		if sc.persister != nil {
+			sc.mu.RLock()
+			snapshot := sc.snapshot()
+			sc.mu.RUnlock()
+			if saveErr := sc.persister.Save(snapshot); saveErr != nil {
+				err = fmt.Errorf("failed to save cache: %w", saveErr)
+			}
+		}
+	})
+	return err
+}
+
+// snapshot copies the entry map for saving outside the lock. The caller must hold sc.mu.
+func (sc *SemanticCache) snapshot() map[string]*CacheEntry {
+	entries := make(map[string]*CacheEntry, len(sc.entries))
+	for key, entry := range sc.entries {
+		entries[key] = entry
+	}
+	return entries
+}
+
 // Get retrieves a cached ChatCompletionResponse based on the provided query.
-// It calculates the query's embedding and searches for the most similar cached entry.
-// If a similar entry is found and is not expired, it returns the cached response and true.
+// It calculates the embedding of the query's prompt, weighting the turns of conversation
+// keys by TurnDecay (see groq.ConversationCacheKey), and looks up the most similar cached
+// entries in the vector index of the query's scope (see groq.ParseCacheKey), so only
+// entries asked with the same model, conversation and parameters can match. The most
+// similar entry that reaches the similarity threshold and is not expired is returned
+// with true.
 // Otherwise, it returns nil and false. It also updates cache metrics such as hits, misses, and latency.
 //
 // Parameters:
@@ -156,34 +241,45 @@ func (sc *SemanticCache) startAutoPrune() {
 //   - *groq.ChatCompletionResponse: The cached response if found, otherwise nil.
 //   - bool: True if a cached response is found and valid, otherwise false.
 func (sc *SemanticCache) Get(ctx context.Context, query string) (*groq.ChatCompletionResponse, bool) {
+	resp, _, found := sc.GetWithTTL(ctx, query)
+	return resp, found
+}
+
+// GetWithTTL is like Get and also returns how much longer the matching entry is kept,
+// implementing groq.TTLReporter.
+func (sc *SemanticCache) GetWithTTL(ctx context.Context, query string) (*groq.ChatCompletionResponse, time.Duration, bool) {
 	start := time.Now()
 	defer func() {
 		sc.metrics.TotalLatency += time.Since(start)
 		sc.metrics.TotalRequests++
 	}()
 
-	queryVector, err := sc.embedding.GetEmbedding(ctx, query)
+	parts := groq.ParseCacheKey(query)
+	queryVector, err := sc.embedding.GetConversationEmbedding(ctx, parts.Turns(), sc.config.TurnDecay)
 	if err != nil {
 		sc.metrics.CacheMisses++
-		return nil, false
+		return nil, 0, false
 	}
 
-	sc.mu.RLock()
-	defer sc.mu.RUnlock()
+	// Hits update the eviction order, so even lookups need the write lock.
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
 
-	maxSim := float32(-1)
 	var bestEntry *CacheEntry
 
 	now := time.Now()
 
-	for _, vec := range sc.vectors {
-		sim := cosineSimilarity(queryVector, vec)
-		if sim > maxSim && sim >= sc.config.SimilarityThreshold {
-			maxSim = sim
-			key := sc.keys[len(sc.vectors)-1]
-			if entry, ok := sc.entries[key]; ok && !isExpired(entry, now) {
-				bestEntry = entry
-			}
+	var matches []neighbor
+	if index, ok := sc.indexes[parts.Scope()]; ok {
+		matches = index.Search(queryVector, index.efSearch)
+	}
+	for _, match := range matches {
+		if match.similarity < sc.config.SimilarityThreshold {
+			break
+		}
+		if entry, ok := sc.entries[match.key]; ok && !isExpired(entry, now) {
+			bestEntry = entry
+			break
 		}
 	}
 
@@ -191,21 +287,25 @@ func (sc *SemanticCache) Get(ctx context.Context, query string) (*groq.ChatCompl
 		sc.metrics.CacheHits++
 		bestEntry.LastAccessed = now
 		bestEntry.AccessCount++
-		return bestEntry.Response, true
+		sc.eviction.touch(bestEntry.Key)
+		remaining := max(bestEntry.TTL-now.Sub(bestEntry.CreatedAt), time.Nanosecond)
+		return bestEntry.Response, remaining, true
 	}
 
 	sc.metrics.CacheMisses++
-	return nil, false
+	return nil, 0, false
 }
 
 // Set stores a new query and its corresponding response in the semantic cache.
-// It first retrieves the embedding vector for the query, then locks the cache
-// to ensure thread safety while updating the cache entries. If the cache size
-// exceeds the maximum allowed size, it prunes old entries. The new cache entry
+// It first retrieves the embedding vector for the query's prompt, then locks the cache
+// to ensure thread safety while updating the cache entries. The new cache entry
 // is created with the query, response, embedding vector, and metadata such as
 // creation time, last accessed time, size, and TTL. The entry is then added to
-// the cache, and the cache size is updated. If a persister is configured, the
-// cache entries are saved asynchronously.
+// the cache, the vector index and the eviction order, replacing any entry for the same
+// query, and the cache size is updated. If the cache then holds more than MaxEntries
+// entries or MaxCacheSize bytes, it prunes expired entries and evicts others following
+// the EvictionPolicy. If a persister is configured, the cache entries are saved
+// asynchronously.
 //
 // Parameters:
 //   - ctx: The context for managing request-scoped values, cancellation, and deadlines.
@@ -215,7 +315,26 @@ func (sc *SemanticCache) Get(ctx context.Context, query string) (*groq.ChatCompl
 // Returns:
 //   - error: An error if the embedding retrieval fails or any other issue occurs during the process.
 func (sc *SemanticCache) Set(ctx context.Context, query string, response *groq.ChatCompletionResponse) error {
-	vector, err := sc.embedding.GetEmbedding(ctx, query)
+	return sc.SetWithTTL(ctx, query, response, 0)
+}
+
+// SetWithTTL is like Set but keeps the entry for ttl instead of the configured TTL, so
+// answers to volatile questions can expire sooner, implementing groq.TTLCache. A ttl of 0
+// or less uses the configured TTL.
+//
+// Parameters:
+//   - ctx: The context for managing request-scoped values, cancellation, and deadlines.
+//   - query: The query string to be cached.
+//   - response: The response to be cached, associated with the query.
+//   - ttl: The time-to-live of the entry.
+//
+// Returns:
+//   - error: An error if the embedding retrieval fails.
+func (sc *SemanticCache) SetWithTTL(ctx context.Context, query string, response *groq.ChatCompletionResponse, ttl time.Duration) error {
+	if ttl <= 0 {
+		ttl = sc.config.TTL
+	}
+	vector, err := sc.embedding.GetConversationEmbedding(ctx, groq.ParseCacheKey(query).Turns(), sc.config.TurnDecay)
 	if err != nil {
 		return fmt.Errorf("failed to get embedding: %w", err)
 	}
@@ -224,10 +343,6 @@ func (sc *SemanticCache) Set(ctx context.Context, query string, response *groq.C
 	defer sc.mu.Unlock()
 
 	entrySize := calculateSize(response)
-	if sc.metrics.Size+entrySize > sc.config.MaxCacheSize {
-		sc.prune()
-	}
-
 	entry := &CacheEntry{
 		Key:          query,
 		Response:     response,
@@ -235,16 +350,30 @@ func (sc *SemanticCache) Set(ctx context.Context, query string, response *groq.C
 		CreatedAt:    time.Now(),
 		LastAccessed: time.Now(),
 		Size:         entrySize,
-		TTL:          sc.config.TTL,
+		TTL:          ttl,
 	}
 
+	if old, exists := sc.entries[query]; exists {
+		sc.metrics.Size -= old.Size
+		sc.eviction.touch(query)
+	} else {
+		sc.eviction.insert(query, 0)
+	}
 	sc.entries[query] = entry
-	sc.vectors = append(sc.vectors, vector)
-	sc.keys = append(sc.keys, query)
+	sc.addToIndex(query, vector)
 	sc.metrics.Size += entrySize
 
-	if sc.persister != nil {
-		go sc.persister.Save(sc.entries)
+	if sc.overLimits() {
+		sc.prune()
+	}
+
+	if sc.persister != nil && !sc.closed {
+		snapshot := sc.snapshot()
+		sc.saving.Add(1)
+		go func() {
+			defer sc.saving.Done()
+			_ = sc.persister.Save(snapshot)
+		}()
 	}
 
 	return nil
@@ -252,7 +381,7 @@ func (sc *SemanticCache) Set(ctx context.Context, query string, response *groq.C
 
 // Delete removes an entry from the SemanticCache based on the provided key.
 // It locks the cache to ensure thread safety, updates the cache metrics, and
-// deletes the entry from both the entries map and the keys and vectors slices.
+// deletes the entry from the entries map, the vector index and the eviction order.
 //
 // Parameters:
 // - ctx: The context for the operation.
@@ -265,20 +394,58 @@ func (sc *SemanticCache) Delete(ctx context.Context, key string) error {
 	defer sc.mu.Unlock()
 
 	if entry, exists := sc.entries[key]; exists {
-		sc.metrics.Size -= entry.Size
-		delete(sc.entries, key)
-
-		for i, k := range sc.keys {
-			if k == key {
-				sc.vectors = append(sc.vectors[:i], sc.vectors[i+1:]...)
-				sc.keys = append(sc.keys[:i], sc.keys[i+1:]...)
-				break
-			}
-		}
+		sc.removeEntry(key, entry)
 	}
 	return nil
 }
 
+// Keys returns the keys of all entries, implementing groq.CacheKeyLister.
+func (sc *SemanticCache) Keys(ctx context.Context) ([]string, error) {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+
+	keys := make([]string, 0, len(sc.entries))
+	for key := range sc.entries {
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// DeleteSimilar removes every entry of the query's namespace whose prompt has a cosine
+// similarity of at least threshold with the query's prompt, whatever the model and
+// parameters it was asked with, implementing groq.SimilarityInvalidator.
+//
+// Parameters:
+//   - ctx: The context for the embedding call.
+//   - query: The query to compare against.
+//   - threshold: The minimum similarity (0.0-1.0) of the entries to delete.
+//
+// Returns:
+//   - []string: The keys of the deleted entries.
+//   - error: An error if the query embedding cannot be computed.
+func (sc *SemanticCache) DeleteSimilar(ctx context.Context, query string, threshold float32) ([]string, error) {
+	parts := groq.ParseCacheKey(query)
+	queryVector, err := sc.embedding.GetConversationEmbedding(ctx, parts.Turns(), sc.config.TurnDecay)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get embedding: %w", err)
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	var deleted []string
+	for key, entry := range sc.entries {
+		if groq.ParseCacheKey(key).Namespace != parts.Namespace {
+			continue
+		}
+		if cosineSimilarity(queryVector, entry.Embedding) >= threshold {
+			sc.removeEntry(key, entry)
+			deleted = append(deleted, key)
+		}
+	}
+	return deleted, nil
+}
+
 // Clear removes all entries from the SemanticCache, resetting its internal state.
 // It acquires a lock to ensure thread safety during the operation.
 // Parameters:
@@ -291,8 +458,8 @@ func (sc *SemanticCache) Clear(ctx context.Context) error {
 	defer sc.mu.Unlock()
 
 	sc.entries = make(map[string]*CacheEntry)
-	sc.vectors = make([]Vector, 0)
-	sc.keys = make([]string, 0)
+	sc.indexes = make(map[string]*hnswIndex)
+	sc.eviction = newEvictionTracker(sc.config.EvictionPolicy, sc.config.MaxEntries)
 	sc.metrics.Size = 0
 	return nil
 }
@@ -313,60 +480,79 @@ func (sc *SemanticCache) GetStats() groq.CacheStats {
 	}
 }
 
-// prune removes expired entries from the cache and ensures the cache size
-// does not exceed the maximum allowed size. It first deletes entries that
-// have expired based on their expiration time. If the cache size still
-// exceeds the maximum allowed size, it removes the least recently accessed
-// entries until the cache size is within the limit. The method updates
-// the eviction count and rebuilds the cache vectors and keys after pruning.
+// prune removes expired entries from the cache and then evicts entries until the cache
+// holds at most MaxEntries entries and MaxCacheSize bytes. The entries to evict are chosen
+// by the EvictionPolicy. The method updates the eviction count and removes the pruned
+// entries from the vector indexes. The caller must hold sc.mu.
 func (sc *SemanticCache) prune() {
 	now := time.Now()
 	prunedCount := 0
 
 	for key, entry := range sc.entries {
 		if isExpired(entry, now) {
-			sc.metrics.Size -= entry.Size
-			delete(sc.entries, key)
+			sc.removeEntry(key, entry)
 			prunedCount++
 		}
 	}
 
-	if sc.metrics.Size > sc.config.MaxCacheSize {
-		entries := make([]*CacheEntry, 0, len(sc.entries))
-		for _, entry := range sc.entries {
-			entries = append(entries, entry)
-		}
+	sc.metrics.EvictionCount += uint64(prunedCount)
+	sc.evictOverLimits()
+}
 
-		sort.Slice(entries, func(i, j int) bool {
-			return entries[i].LastAccessed.Before(entries[j].LastAccessed)
-		})
+// overLimits reports whether the cache holds more entries or bytes than configured. The
+// caller must hold sc.mu.
+func (sc *SemanticCache) overLimits() bool {
+	return (sc.config.MaxEntries > 0 && len(sc.entries) > sc.config.MaxEntries) ||
+		sc.metrics.Size > sc.config.MaxCacheSize
+}
 
-		for _, entry := range entries {
-			if sc.metrics.Size <= sc.config.MaxCacheSize {
-				break
-			}
+// evictOverLimits evicts entries in the order of the EvictionPolicy until the cache is
+// within its limits. The caller must hold sc.mu.
+func (sc *SemanticCache) evictOverLimits() {
+	for sc.overLimits() {
+		key, ok := sc.eviction.evict()
+		if !ok {
+			return
+		}
+		if entry, exists := sc.entries[key]; exists {
 			sc.metrics.Size -= entry.Size
-			delete(sc.entries, entry.Key)
-			prunedCount++
+			delete(sc.entries, key)
+			sc.removeFromIndex(key)
+			sc.metrics.EvictionCount++
 		}
 	}
+}
 
-	sc.metrics.EvictionCount += uint64(prunedCount)
-
-	sc.rebuildVectorsAndKeys()
+// removeEntry deletes the entry stored under key from the entries, the vector index and
+// the eviction order. The caller must hold sc.mu.
+func (sc *SemanticCache) removeEntry(key string, entry *CacheEntry) {
+	sc.metrics.Size -= entry.Size
+	delete(sc.entries, key)
+	sc.removeFromIndex(key)
+	sc.eviction.remove(key)
 }
 
-// rebuildVectorsAndKeys reconstructs the vectors and keys slices from the entries map.
-// It iterates over each entry in the map, appending the entry's embedding to the vectors slice
-// and the entry's key to the keys slice. This ensures that the vectors and keys slices are
-// always in sync with the entries map.
-func (sc *SemanticCache) rebuildVectorsAndKeys() {
-	sc.vectors = make([]Vector, 0, len(sc.entries))
-	sc.keys = make([]string, 0, len(sc.entries))
+// addToIndex indexes the embedding of the entry stored under key in the index of the
+// key's scope. The caller must hold sc.mu.
+func (sc *SemanticCache) addToIndex(key string, vector Vector) {
+	scope := groq.ParseCacheKey(key).Scope()
+	index, ok := sc.indexes[scope]
+	if !ok {
+		index = newHNSWIndex(sc.config.IndexM, sc.config.IndexEfSearch)
+		sc.indexes[scope] = index
+	}
+	index.Add(key, vector)
+}
 
-	for key, entry := range sc.entries {
-		sc.vectors = append(sc.vectors, entry.Embedding)
-		sc.keys = append(sc.keys, key)
+// removeFromIndex removes key from the index of its scope, dropping indexes that become
+// empty. The caller must hold sc.mu.
+func (sc *SemanticCache) removeFromIndex(key string) {
+	scope := groq.ParseCacheKey(key).Scope()
+	if index, ok := sc.indexes[scope]; ok {
+		index.Remove(key)
+		if index.Len() == 0 {
+			delete(sc.indexes, scope)
+		}
 	}
 }
 
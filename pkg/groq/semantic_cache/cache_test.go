@@ -0,0 +1,200 @@
+package semantic_cache
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/genc-murat/groq-client/pkg/groq"
+)
+
+func TestDeleteSimilar(t *testing.T) {
+	ctx := context.Background()
+	config := DefaultConfig()
+	config.PruneInterval = 0
+	sc := NewSemanticCache(config)
+
+	_ = sc.Set(ctx, "what is go", &groq.ChatCompletionResponse{})
+	_ = sc.Set(ctx, "what is rust", &groq.ChatCompletionResponse{})
+
+	deleted, err := sc.DeleteSimilar(ctx, "what is go", 0.999)
+	if err != nil || len(deleted) != 1 || deleted[0] != "what is go" {
+		t.Fatalf("DeleteSimilar() = %v, %v", deleted, err)
+	}
+
+	keys, _ := sc.Keys(ctx)
+	if len(keys) != 1 || keys[0] != "what is rust" {
+		t.Errorf("unexpected remaining keys: %v", keys)
+	}
+}
+
+func TestCloseFlushesPersistence(t *testing.T) {
+	ctx := context.Background()
+	config := DefaultConfig()
+	config.PruneInterval = time.Millisecond
+	config.PersistPath = filepath.Join(t.TempDir(), "cache.json")
+	sc := NewSemanticCache(config)
+
+	_ = sc.Set(ctx, "what is go", &groq.ChatCompletionResponse{ID: "1"})
+	if err := sc.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := sc.Close(); err != nil {
+		t.Fatalf("second Close() error = %v", err)
+	}
+
+	reopened := NewSemanticCache(config)
+	defer reopened.Close()
+	keys, _ := reopened.Keys(ctx)
+	if len(keys) != 1 || keys[0] != "what is go" {
+		t.Errorf("expected the entry to be persisted, got %v", keys)
+	}
+}
+
+func TestGetReturnsMatchingEntry(t *testing.T) {
+	ctx := context.Background()
+	config := DefaultConfig()
+	config.PruneInterval = 0
+	config.SimilarityThreshold = 0.999
+	sc := NewSemanticCache(config)
+
+	queries := []string{"what is go", "what is rust", "what is zig"}
+	for _, q := range queries {
+		_ = sc.Set(ctx, q, &groq.ChatCompletionResponse{ID: q})
+	}
+
+	for _, q := range queries {
+		resp, ok := sc.Get(ctx, q)
+		if !ok || resp.ID != q {
+			t.Errorf("Get(%q) = %+v, %v; want its own entry", q, resp, ok)
+		}
+	}
+	if _, ok := sc.Get(ctx, "what is c"); ok {
+		t.Error("expected a miss for an unrelated query")
+	}
+
+	_ = sc.Delete(ctx, "what is rust")
+	if _, ok := sc.Get(ctx, "what is rust"); ok {
+		t.Error("expected a miss after Delete")
+	}
+	if resp, ok := sc.Get(ctx, "what is zig"); !ok || resp.ID != "what is zig" {
+		t.Errorf("Get after Delete = %+v, %v", resp, ok)
+	}
+}
+
+func TestGetSkipsExpiredEntries(t *testing.T) {
+	ctx := context.Background()
+	config := DefaultConfig()
+	config.PruneInterval = 0
+	config.SimilarityThreshold = 0.5
+	sc := NewSemanticCache(config)
+
+	_ = sc.Set(ctx, "what is go", &groq.ChatCompletionResponse{ID: "go"})
+	_ = sc.Set(ctx, "what is rust", &groq.ChatCompletionResponse{ID: "rust"})
+	sc.entries["what is go"].CreatedAt = time.Now().Add(-2 * config.TTL)
+
+	resp, ok := sc.Get(ctx, "what is go")
+	if !ok || resp.ID != "rust" {
+		t.Errorf("Get() = %+v, %v; want the next most similar live entry", resp, ok)
+	}
+}
+
+func TestConfigEmbedder(t *testing.T) {
+	ctx := context.Background()
+	config := DefaultConfig()
+	config.PruneInterval = 0
+	config.SimilarityThreshold = 0.8
+	config.Embedder = groq.NewHashingEmbedder(0)
+	sc := NewSemanticCache(config)
+
+	_ = sc.Set(ctx, "What is the capital of France?", &groq.ChatCompletionResponse{ID: "paris"})
+	if resp, ok := sc.Get(ctx, "what is the capital of france"); !ok || resp.ID != "paris" {
+		t.Errorf("Get() = %+v, %v; want a hit for the rephrased query", resp, ok)
+	}
+	if _, ok := sc.Get(ctx, "how tall is mount everest"); ok {
+		t.Error("expected a miss for an unrelated query")
+	}
+
+	failing := DefaultConfig()
+	failing.PruneInterval = 0
+	failing.Embedder = groq.EmbedderFunc(func(ctx context.Context, texts []string) ([][]float32, error) {
+		return nil, errors.New("embedding service down")
+	})
+	sc = NewSemanticCache(failing)
+	if err := sc.Set(ctx, "hi", &groq.ChatCompletionResponse{}); err == nil {
+		t.Error("expected Set to report the embedder error")
+	}
+}
+
+func TestGetMatchesWithinScope(t *testing.T) {
+	ctx := context.Background()
+	config := DefaultConfig()
+	config.PruneInterval = 0
+	config.Embedder = groq.NewHashingEmbedder(0)
+	sc := NewSemanticCache(config)
+
+	brief := groq.NewRequest(groq.ModelLlama31_8bInstant).System("be brief").User("What is the capital of France?").Build()
+	_ = sc.Set(ctx, groq.CacheKey("", brief), &groq.ChatCompletionResponse{ID: "brief"})
+
+	rephrased := groq.NewRequest(groq.ModelLlama31_8bInstant).System("be brief").User("what is the capital of france").Build()
+	if resp, ok := sc.Get(ctx, groq.CacheKey("", rephrased)); !ok || resp.ID != "brief" {
+		t.Errorf("Get() = %+v, %v; want the entry of the same scope", resp, ok)
+	}
+	verbose := groq.NewRequest(groq.ModelLlama31_8bInstant).System("be verbose").User("What is the capital of France?").Build()
+	if _, ok := sc.Get(ctx, groq.CacheKey("", verbose)); ok {
+		t.Error("expected a miss for another system prompt")
+	}
+
+	deleted, _ := sc.DeleteSimilar(ctx, "capital of France", 0.5)
+	if len(deleted) != 1 {
+		t.Errorf("DeleteSimilar() = %v, want the entry whatever its scope", deleted)
+	}
+}
+
+func TestGetWeighsConversationTurns(t *testing.T) {
+	ctx := context.Background()
+	config := DefaultConfig()
+	config.PruneInterval = 0
+	config.Embedder = groq.NewHashingEmbedder(0)
+
+	conversation := func(topic string) string {
+		req := groq.NewRequest(groq.ModelLlama31_8bInstant).User(topic).Assistant("Sure").User("What should I pack?").Build()
+		return groq.ConversationCacheKey("", req, 0)
+	}
+
+	sc := NewSemanticCache(config)
+	_ = sc.Set(ctx, conversation("Plan a trip to Paris"), &groq.ChatCompletionResponse{ID: "paris"})
+	if resp, ok := sc.Get(ctx, conversation("plan a trip to paris")); !ok || resp.ID != "paris" {
+		t.Errorf("Get() = %+v, %v; want the same conversation", resp, ok)
+	}
+	if resp, ok := sc.Get(ctx, conversation("Recommend ski resorts in the Alps")); ok {
+		t.Errorf("Get() = %+v; another conversation must not match the same final question", resp)
+	}
+
+	config.TurnDecay = 0
+	lastTurnOnly := NewSemanticCache(config)
+	_ = lastTurnOnly.Set(ctx, conversation("Plan a trip to Paris"), &groq.ChatCompletionResponse{ID: "paris"})
+	if _, ok := lastTurnOnly.Get(ctx, conversation("Recommend ski resorts in the Alps")); !ok {
+		t.Error("with a TurnDecay of 0 only the final question should count")
+	}
+}
+
+func TestSetWithTTL(t *testing.T) {
+	ctx := context.Background()
+	config := DefaultConfig()
+	config.PruneInterval = 0
+	sc := NewSemanticCache(config)
+
+	_ = sc.SetWithTTL(ctx, "what is the latest news", &groq.ChatCompletionResponse{ID: "news"}, 20*time.Millisecond)
+	_ = sc.Set(ctx, "what is go", &groq.ChatCompletionResponse{ID: "go"})
+	time.Sleep(30 * time.Millisecond)
+
+	if _, ok := sc.Get(ctx, "what is the latest news"); ok {
+		t.Error("the entry outlived its own TTL")
+	}
+	if _, ok := sc.Get(ctx, "what is go"); !ok {
+		t.Error("the entry with the configured TTL expired")
+	}
+}
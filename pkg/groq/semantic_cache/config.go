@@ -1,31 +1,40 @@
 package semantic_cache
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/genc-murat/groq-client/pkg/groq"
 )
 
 type Config struct {
-	MaxEntries          int           // Maximum number of entries
-	SimilarityThreshold float32       // Minimum similarity score (0.0-1.0)
-	TTL                 time.Duration // Time-to-live for entries
-	EmbeddingModel      string        // Model for embeddings
-	MaxCacheSize        int64         // Maximum cache size in bytes
-	EnableMetrics       bool          // Enable metric collection
-	PruneInterval       time.Duration // Auto-prune interval
-	PersistPath         string        // Path for persistent storage
+	MaxEntries          int            // Maximum number of entries; 0 for no limit
+	EvictionPolicy      EvictionPolicy // Which entries make room when a limit is reached; EvictLRU by default
+	SimilarityThreshold float32        // Minimum similarity score (0.0-1.0)
+	TTL                 time.Duration  // Time-to-live for entries
+	EmbeddingModel      string         // Model for embeddings
+	Embedder            groq.Embedder  // Embedding provider; nil uses hash vectors that only match identical queries
+	MaxCacheSize        int64          // Maximum cache size in bytes
+	EnableMetrics       bool           // Enable metric collection
+	PruneInterval       time.Duration  // Auto-prune interval
+	PersistPath         string         // Path for persistent storage
+	IndexM              int            // Neighbors per node in the vector index; 0 uses 16
+	IndexEfSearch       int            // Candidates explored per lookup; higher is more accurate, 0 uses 64
+	TurnDecay           float32        // Weight of each earlier turn of conversation keys relative to the next (0.0-1.0), see groq.EmbedConversation
 }
 
 // DefaultConfig returns a pointer to a Config struct with default values set.
 // The default configuration includes:
 // - MaxEntries: 10000 (maximum number of entries in the cache)
+// - EvictionPolicy: EvictLRU (evicts the least recently used entries first)
 // - SimilarityThreshold: 0.85 (threshold for similarity comparisons)
 // - TTL: 24 hours (time-to-live for cache entries)
 // - EmbeddingModel: groq.ModelLlama3_8b_8192 (default embedding model)
 // - MaxCacheSize: 1GB (maximum cache size)
 // - EnableMetrics: true (enables metrics collection)
 // - PruneInterval: 1 hour (interval for pruning expired cache entries)
+// - IndexM: 16 and IndexEfSearch: 64 (vector index tuning)
+// - TurnDecay: 0.8 (each earlier turn of a conversation key counts 0.8 times the next)
 func DefaultConfig() *Config {
 	return &Config{
 		MaxEntries:          10000,
@@ -35,5 +44,54 @@ func DefaultConfig() *Config {
 		MaxCacheSize:        1 << 30, // 1GB
 		EnableMetrics:       true,
 		PruneInterval:       time.Hour,
+		IndexM:              defaultIndexM,
+		IndexEfSearch:       defaultIndexEfSearch,
+		TurnDecay:           0.8,
 	}
 }
+
+// Validate checks the configuration for values and combinations that cannot work, such as
+// a similarity threshold outside (0, 1] or a size limit that is never pruned. All problems
+// are reported at once in a *groq.ConfigError, each with a hint on how to fix it.
+//
+// Returns:
+//   - error: A *groq.ConfigError matching groq.ErrInvalidConfig, or nil if the configuration is valid.
+func (c *Config) Validate() error {
+	var problems []string
+
+	if c.MaxEntries < 0 {
+		problems = append(problems, fmt.Sprintf("MaxEntries is %d; it must not be negative", c.MaxEntries))
+	}
+	if c.EvictionPolicy < EvictLRU || c.EvictionPolicy > EvictARC {
+		problems = append(problems, fmt.Sprintf("EvictionPolicy is %v; use EvictLRU, EvictLFU or EvictARC", c.EvictionPolicy))
+	}
+	if c.SimilarityThreshold <= 0 || c.SimilarityThreshold > 1 {
+		problems = append(problems, fmt.Sprintf("SimilarityThreshold is %v; it must be in (0, 1], e.g. 0.85", c.SimilarityThreshold))
+	}
+	if c.TTL <= 0 {
+		problems = append(problems, fmt.Sprintf("TTL is %v; entries would expire immediately, set a positive TTL such as 24h", c.TTL))
+	}
+	if c.EmbeddingModel == "" && c.Embedder == nil {
+		problems = append(problems, "EmbeddingModel is empty; set the model used for embeddings or an Embedder")
+	}
+	if c.MaxCacheSize <= 0 {
+		problems = append(problems, fmt.Sprintf("MaxCacheSize is %d; every entry would be evicted, set a positive size in bytes", c.MaxCacheSize))
+	}
+	if c.PruneInterval < 0 {
+		problems = append(problems, fmt.Sprintf("PruneInterval is %v; use 0 to disable auto-pruning", c.PruneInterval))
+	}
+	if c.IndexM < 0 || c.IndexM == 1 {
+		problems = append(problems, fmt.Sprintf("IndexM is %d; use at least 2 neighbors, or 0 for the default", c.IndexM))
+	}
+	if c.IndexEfSearch < 0 {
+		problems = append(problems, fmt.Sprintf("IndexEfSearch is %d; it must not be negative, use 0 for the default", c.IndexEfSearch))
+	}
+	if c.TurnDecay < 0 || c.TurnDecay > 1 {
+		problems = append(problems, fmt.Sprintf("TurnDecay is %v; it must be in [0, 1], e.g. 0.8, or 0 to compare final questions only", c.TurnDecay))
+	}
+	if c.PruneInterval == 0 && c.MaxCacheSize > 0 && c.TTL > 0 {
+		problems = append(problems, "PruneInterval is 0 while MaxCacheSize and TTL are set; expired entries would only be removed once the cache is full, set a PruneInterval such as 1h")
+	}
+
+	return groq.NewConfigError(problems)
+}
@@ -0,0 +1,34 @@
+package semantic_cache
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/genc-murat/groq-client/pkg/groq"
+)
+
+func TestConfigValidate(t *testing.T) {
+	if err := DefaultConfig().Validate(); err != nil {
+		t.Fatalf("expected the default config to be valid, got %v", err)
+	}
+
+	config := DefaultConfig()
+	config.SimilarityThreshold = 1.5
+	config.PruneInterval = 0
+
+	err := config.Validate()
+	var configErr *groq.ConfigError
+	if !errors.As(err, &configErr) || len(configErr.Problems) != 2 {
+		t.Fatalf("expected 2 aggregated problems, got %v", err)
+	}
+
+	config = DefaultConfig()
+	config.EvictionPolicy = EvictARC + 1
+	if err := config.Validate(); !errors.Is(err, groq.ErrInvalidConfig) {
+		t.Errorf("expected an unknown eviction policy to be rejected, got %v", err)
+	}
+
+	if _, err := NewSemanticCacheE(&Config{}); !errors.Is(err, groq.ErrInvalidConfig) {
+		t.Errorf("expected NewSemanticCacheE to reject an empty config, got %v", err)
+	}
+}
@@ -4,16 +4,22 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/binary"
+	"fmt"
 	"math"
+
+	"github.com/genc-murat/groq-client/pkg/groq"
 )
 
 type EmbeddingService struct {
 	model     string
 	dimension int
+	embedder  groq.Embedder // Computes the vectors; nil uses hash-based vectors
 }
 
 // NewEmbeddingService creates a new instance of EmbeddingService with the specified model.
-// It initializes the dimension to 128.
+// It initializes the dimension to 128. The service derives its vectors from a hash of the
+// text, so only identical texts are similar; use NewEmbeddingServiceWith for semantic
+// matching.
 //
 // Parameters:
 //   - model: A string representing the model to be used.
@@ -27,7 +33,23 @@ func NewEmbeddingService(model string) *EmbeddingService {
 	}
 }
 
-// GetEmbedding retrieves the embedding vector for the given text.
+// NewEmbeddingServiceWith creates an EmbeddingService that computes its vectors with
+// embedder, such as a groq.APIEmbedder for a real embedding model or a local
+// groq.HashingEmbedder.
+//
+// Parameters:
+//   - embedder: The embedding provider; nil behaves like NewEmbeddingService.
+//   - model: A string naming the model, for reference only.
+//
+// Returns:
+//   - A pointer to an EmbeddingService instance.
+func NewEmbeddingServiceWith(embedder groq.Embedder, model string) *EmbeddingService {
+	es := NewEmbeddingService(model)
+	es.embedder = embedder
+	return es
+}
+
+// GetEmbedding retrieves the normalized embedding vector for the given text.
 // If the context is done before the embedding is retrieved, it returns an error.
 //
 // Parameters:
@@ -36,14 +58,63 @@ func NewEmbeddingService(model string) *EmbeddingService {
 //
 // Returns:
 //   - Vector: The embedding vector for the input text.
-//   - error: An error if the context is done before the embedding is retrieved.
+//   - error: An error if the context is done or the embedder fails.
 func (es *EmbeddingService) GetEmbedding(ctx context.Context, text string) (Vector, error) {
 	select {
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	default:
+	}
+
+	if es.embedder == nil {
 		return mockEmbedding(text, es.dimension), nil
 	}
+
+	vectors, err := es.embedder.Embed(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) != 1 {
+		return nil, fmt.Errorf("embedder returned %d vectors for 1 text", len(vectors))
+	}
+	vector := append(Vector(nil), vectors[0]...)
+	normalize(vector)
+	return vector, nil
+}
+
+// GetConversationEmbedding returns the normalized embedding of the turns of a
+// conversation, weighting each earlier turn decay times the next, see
+// groq.EmbedConversation. A single turn gets the same vector as GetEmbedding.
+//
+// Parameters:
+//   - ctx: The context for controlling cancellation and deadlines.
+//   - turns: The texts of the turns, oldest first.
+//   - decay: The weight of a turn relative to the next one.
+//
+// Returns:
+//   - Vector: The embedding vector of the conversation.
+//   - error: An error if the context is done or the embedder fails.
+func (es *EmbeddingService) GetConversationEmbedding(ctx context.Context, turns []string, decay float32) (Vector, error) {
+	if len(turns) == 1 {
+		return es.GetEmbedding(ctx, turns[0])
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	embedder := es.embedder
+	if embedder == nil {
+		embedder = groq.EmbedderFunc(func(ctx context.Context, texts []string) ([][]float32, error) {
+			vectors := make([][]float32, len(texts))
+			for i, text := range texts {
+				vectors[i] = mockEmbedding(text, es.dimension)
+			}
+			return vectors, nil
+		})
+	}
+	return groq.EmbedConversation(ctx, embedder, turns, decay)
 }
 
 // mockEmbedding generates a mock embedding vector for the given text.
@@ -99,7 +170,8 @@ func normalize(v Vector) {
 }
 
 // SetDimension sets the dimension of the embedding service to the specified value
-// if the provided dimension is greater than 0.
+// if the provided dimension is greater than 0. It only affects the hash-based vectors;
+// an embedder determines the dimension of its own.
 //
 // Parameters:
 //
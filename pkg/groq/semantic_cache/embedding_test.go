@@ -171,3 +171,12 @@ func TestGetDimension(t *testing.T) {
 func almostEqual(a, b float32) bool {
 	return math.Abs(float64(a-b)) < 1e-6
 }
+
+func TestGetEmbeddingCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := NewEmbeddingService("test-model").GetEmbedding(ctx, "hello"); err != context.Canceled {
+		t.Errorf("GetEmbedding() error = %v, want %v", err, context.Canceled)
+	}
+}
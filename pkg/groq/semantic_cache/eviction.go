@@ -0,0 +1,278 @@
+package semantic_cache
+
+import (
+	"container/heap"
+	"container/list"
+	"fmt"
+)
+
+type EvictionPolicy int
+
+const (
+	// EvictLRU evicts the least recently used entry.
+	EvictLRU EvictionPolicy = iota
+	// EvictLFU evicts the least frequently used entry, the least recently used of those
+	// hit equally often.
+	EvictLFU
+	// EvictARC balances recency and frequency with an adaptive replacement cache: entries
+	// hit only once are evicted first, so a burst of one-off queries cannot flush the
+	// popular ones, and the balance adapts to the entries that are asked again after
+	// their eviction.
+	EvictARC
+)
+
+// String returns the name of the policy, e.g. "LRU".
+func (p EvictionPolicy) String() string {
+	switch p {
+	case EvictLRU:
+		return "LRU"
+	case EvictLFU:
+		return "LFU"
+	case EvictARC:
+		return "ARC"
+	}
+	return fmt.Sprintf("EvictionPolicy(%d)", int(p))
+}
+
+// evictionTracker orders the keys of the cache by the chance they are asked again.
+type evictionTracker interface {
+	insert(key string, accesses uint64) // A new entry, hit accesses times before, e.g. when loaded from disk
+	touch(key string)                   // A hit or a replaced entry
+	remove(key string)                  // An entry deleted or expired
+	evict() (string, bool)              // Chooses the next entry to evict and forgets it
+}
+
+// newEvictionTracker returns the tracker of policy for a cache of capacity entries, 0
+// for no limit.
+func newEvictionTracker(policy EvictionPolicy, capacity int) evictionTracker {
+	switch policy {
+	case EvictLFU:
+		return &lfuTracker{items: make(map[string]*lfuItem)}
+	case EvictARC:
+		return newARCTracker(capacity)
+	}
+	return &lruTracker{order: list.New(), items: make(map[string]*list.Element)}
+}
+
+// lruTracker keeps the keys in recency order.
+type lruTracker struct {
+	order *list.List // Front is the most recently used key
+	items map[string]*list.Element
+}
+
+func (t *lruTracker) insert(key string, accesses uint64) {
+	if elem, ok := t.items[key]; ok {
+		t.order.MoveToFront(elem)
+		return
+	}
+	t.items[key] = t.order.PushFront(key)
+}
+
+func (t *lruTracker) touch(key string) {
+	if elem, ok := t.items[key]; ok {
+		t.order.MoveToFront(elem)
+	}
+}
+
+func (t *lruTracker) remove(key string) {
+	if elem, ok := t.items[key]; ok {
+		t.order.Remove(elem)
+		delete(t.items, key)
+	}
+}
+
+func (t *lruTracker) evict() (string, bool) {
+	elem := t.order.Back()
+	if elem == nil {
+		return "", false
+	}
+	key := t.order.Remove(elem).(string)
+	delete(t.items, key)
+	return key, true
+}
+
+// lfuTracker keeps the keys in a min-heap of their hit count and last use.
+type lfuTracker struct {
+	heap  lfuHeap
+	items map[string]*lfuItem
+	clock uint64 // Increases with every insert and touch, ordering equal counts by recency
+}
+
+type lfuItem struct {
+	key      string
+	count    uint64
+	lastUsed uint64
+	index    int
+}
+
+func (t *lfuTracker) insert(key string, accesses uint64) {
+	if _, ok := t.items[key]; ok {
+		t.touch(key)
+		return
+	}
+	t.clock++
+	item := &lfuItem{key: key, count: accesses, lastUsed: t.clock}
+	t.items[key] = item
+	heap.Push(&t.heap, item)
+}
+
+func (t *lfuTracker) touch(key string) {
+	if item, ok := t.items[key]; ok {
+		t.clock++
+		item.count++
+		item.lastUsed = t.clock
+		heap.Fix(&t.heap, item.index)
+	}
+}
+
+func (t *lfuTracker) remove(key string) {
+	if item, ok := t.items[key]; ok {
+		heap.Remove(&t.heap, item.index)
+		delete(t.items, key)
+	}
+}
+
+func (t *lfuTracker) evict() (string, bool) {
+	if len(t.heap) == 0 {
+		return "", false
+	}
+	item := heap.Pop(&t.heap).(*lfuItem)
+	delete(t.items, item.key)
+	return item.key, true
+}
+
+// lfuHeap implements heap.Interface for lfuTracker.
+type lfuHeap []*lfuItem
+
+func (h lfuHeap) Len() int { return len(h) }
+
+func (h lfuHeap) Less(i, j int) bool {
+	if h[i].count != h[j].count {
+		return h[i].count < h[j].count
+	}
+	return h[i].lastUsed < h[j].lastUsed
+}
+
+func (h lfuHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *lfuHeap) Push(x any) {
+	item := x.(*lfuItem)
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *lfuHeap) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return item
+}
+
+// arcTracker implements the adaptive replacement cache of Megiddo and Modha. t1 holds
+// the keys hit once and t2 the keys hit again; b1 and b2 remember the keys recently
+// evicted from each. A key inserted again after its eviction from t1 makes t1 grow,
+// one evicted from t2 makes t2 grow.
+type arcTracker struct {
+	capacity       int // 0 adapts to the number of entries
+	target         int // Target length of t1
+	t1, t2, b1, b2 *list.List
+	items          map[string]*list.Element
+	lists          map[string]*list.List // The list holding each key
+}
+
+func newARCTracker(capacity int) *arcTracker {
+	return &arcTracker{
+		capacity: capacity,
+		t1:       list.New(),
+		t2:       list.New(),
+		b1:       list.New(),
+		b2:       list.New(),
+		items:    make(map[string]*list.Element),
+		lists:    make(map[string]*list.List),
+	}
+}
+
+func (t *arcTracker) insert(key string, accesses uint64) {
+	switch t.lists[key] {
+	case t.t1, t.t2:
+		t.touch(key)
+		return
+	case t.b1:
+		t.target = min(t.target+max(t.b2.Len()/t.b1.Len(), 1), t.limit())
+		t.move(key, t.t2)
+	case t.b2:
+		t.target = max(t.target-max(t.b1.Len()/t.b2.Len(), 1), 0)
+		t.move(key, t.t2)
+	default:
+		if accesses > 0 {
+			t.move(key, t.t2)
+		} else {
+			t.move(key, t.t1)
+		}
+	}
+	t.trimGhosts()
+}
+
+func (t *arcTracker) touch(key string) {
+	if current := t.lists[key]; current == t.t1 || current == t.t2 {
+		t.move(key, t.t2)
+	}
+}
+
+func (t *arcTracker) remove(key string) {
+	if current, ok := t.lists[key]; ok {
+		current.Remove(t.items[key])
+		delete(t.items, key)
+		delete(t.lists, key)
+	}
+}
+
+func (t *arcTracker) evict() (string, bool) {
+	var from, ghosts *list.List
+	switch {
+	case t.t1.Len() > 0 && (t.t1.Len() > t.target || t.t2.Len() == 0):
+		from, ghosts = t.t1, t.b1
+	case t.t2.Len() > 0:
+		from, ghosts = t.t2, t.b2
+	default:
+		return "", false
+	}
+	key := from.Back().Value.(string)
+	t.move(key, ghosts)
+	t.trimGhosts()
+	return key, true
+}
+
+// limit returns the capacity the lists are sized for.
+func (t *arcTracker) limit() int {
+	if t.capacity > 0 {
+		return t.capacity
+	}
+	return max(t.t1.Len()+t.t2.Len(), 1)
+}
+
+// move puts key at the front of to, taking it from its current list.
+func (t *arcTracker) move(key string, to *list.List) {
+	if current, ok := t.lists[key]; ok {
+		current.Remove(t.items[key])
+	}
+	t.items[key] = to.PushFront(key)
+	t.lists[key] = to
+}
+
+// trimGhosts bounds the ghost lists: t1 and b1 together hold at most the capacity, and
+// all four lists at most twice the capacity.
+func (t *arcTracker) trimGhosts() {
+	limit := t.limit()
+	for t.b1.Len() > 0 && t.t1.Len()+t.b1.Len() > limit {
+		t.remove(t.b1.Back().Value.(string))
+	}
+	for t.b2.Len() > 0 && t.t1.Len()+t.t2.Len()+t.b1.Len()+t.b2.Len() > 2*limit {
+		t.remove(t.b2.Back().Value.(string))
+	}
+}
@@ -0,0 +1,78 @@
+package semantic_cache
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/genc-murat/groq-client/pkg/groq"
+)
+
+func TestMaxEntriesEvictionPolicies(t *testing.T) {
+	// Two popular queries are asked again, then a burst of one-off queries follows.
+	tests := []struct {
+		policy EvictionPolicy
+		want   []string
+	}{
+		{EvictLRU, []string{"once 1", "once 2", "once 3", "once 4"}},
+		{EvictLFU, []string{"once 4", "popular a", "popular b", "popular c"}},
+		{EvictARC, []string{"once 4", "popular a", "popular b", "popular c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			ctx := context.Background()
+			config := DefaultConfig()
+			config.PruneInterval = 0
+			config.MaxEntries = 4
+			config.EvictionPolicy = tt.policy
+			sc := NewSemanticCache(config)
+
+			for _, key := range []string{"popular a", "popular b", "popular c"} {
+				_ = sc.Set(ctx, key, &groq.ChatCompletionResponse{ID: key})
+			}
+			sc.Get(ctx, "popular a")
+			sc.Get(ctx, "popular b")
+			sc.Get(ctx, "popular c")
+			sc.Get(ctx, "popular b")
+			for i := 1; i <= 4; i++ {
+				_ = sc.Set(ctx, fmt.Sprintf("once %d", i), &groq.ChatCompletionResponse{})
+			}
+
+			keys, _ := sc.Keys(ctx)
+			sort.Strings(keys)
+			if fmt.Sprint(keys) != fmt.Sprint(tt.want) {
+				t.Errorf("kept %v, want %v", keys, tt.want)
+			}
+			if evicted := sc.metrics.EvictionCount; evicted != 3 {
+				t.Errorf("EvictionCount = %d, want 3", evicted)
+			}
+		})
+	}
+}
+
+func TestARCTrackerAdapts(t *testing.T) {
+	arc := newARCTracker(2)
+	arc.insert("a", 0)
+	arc.insert("b", 0)
+	arc.touch("b")
+	arc.insert("c", 0)
+	if key, _ := arc.evict(); key != "a" {
+		t.Fatalf("evict() = %q, want the entry hit once", key)
+	}
+
+	// a comes back after its eviction: entries hit once deserve more room, so c stays.
+	arc.insert("a", 0)
+	if arc.target != 1 || arc.lists["a"] != arc.t2 {
+		t.Errorf("target = %d and a in t2 = %v after a ghost hit", arc.target, arc.lists["a"] == arc.t2)
+	}
+	if key, _ := arc.evict(); key != "b" {
+		t.Errorf("evict() = %q, want b", key)
+	}
+
+	arc.remove("a")
+	arc.remove("c")
+	if key, ok := arc.evict(); ok {
+		t.Errorf("evict() = %q from an empty tracker", key)
+	}
+}
@@ -0,0 +1,317 @@
+package semantic_cache
+
+import (
+	"container/heap"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+const (
+	// defaultIndexM is the number of neighbors an index node keeps per layer.
+	defaultIndexM = 16
+	// defaultIndexEfSearch is the number of candidates a search explores.
+	defaultIndexEfSearch = 64
+	// exactSearchLimit is the index size up to which searches compare every vector, which
+	// is exact and, for caches this small, as fast as walking the graph.
+	exactSearchLimit = 256
+)
+
+// neighbor is a search result: an indexed key and its similarity to the query.
+type neighbor struct {
+	key        string
+	similarity float32
+}
+
+// hnswNode is an indexed vector with its neighbor lists, one per layer it is in.
+type hnswNode struct {
+	key     string
+	vector  Vector
+	friends [][]int
+	deleted bool
+}
+
+// hnswIndex is a Hierarchical Navigable Small World graph over the cached embeddings,
+// so nearest-neighbor lookups take logarithmic rather than linear time in the number of
+// entries. Removed vectors are marked deleted and skipped; the graph is rebuilt once
+// they make up half of it. The index is not safe for concurrent writes; SemanticCache
+// guards it with its own lock.
+type hnswIndex struct {
+	m              int // Neighbors per node on the upper layers; layer 0 keeps 2*m
+	efConstruction int
+	efSearch       int
+	levelMult      float64
+	exactLimit     int
+
+	nodes    []*hnswNode
+	ids      map[string]int
+	entry    int // Entry point of searches, -1 if the index is empty
+	maxLevel int
+	deleted  int
+	rng      *rand.Rand
+}
+
+// newHNSWIndex creates an empty index. Non-positive m and efSearch use the defaults.
+//
+// Parameters:
+//   - m: The number of neighbors kept per node and layer.
+//   - efSearch: The number of candidates a search explores; higher is more accurate.
+//
+// Returns:
+//   - *hnswIndex: The empty index.
+func newHNSWIndex(m, efSearch int) *hnswIndex {
+	if m <= 1 {
+		m = defaultIndexM
+	}
+	if efSearch <= 0 {
+		efSearch = defaultIndexEfSearch
+	}
+	return &hnswIndex{
+		m:              m,
+		efConstruction: max(efSearch, 2*m),
+		efSearch:       efSearch,
+		levelMult:      1 / math.Log(float64(m)),
+		exactLimit:     exactSearchLimit,
+		ids:            make(map[string]int),
+		entry:          -1,
+		rng:            rand.New(rand.NewSource(1)),
+	}
+}
+
+// Len returns the number of vectors in the index, not counting deleted ones.
+func (idx *hnswIndex) Len() int {
+	return len(idx.ids)
+}
+
+// Add indexes vector under key, replacing the vector the key had before.
+func (idx *hnswIndex) Add(key string, vector Vector) {
+	idx.Remove(key)
+
+	level := int(-math.Log(1-idx.rng.Float64()) * idx.levelMult)
+	id := len(idx.nodes)
+	node := &hnswNode{key: key, vector: vector, friends: make([][]int, level+1)}
+	idx.nodes = append(idx.nodes, node)
+	idx.ids[key] = id
+
+	if idx.entry < 0 {
+		idx.entry, idx.maxLevel = id, level
+		return
+	}
+
+	ep := idx.entry
+	for l := idx.maxLevel; l > level; l-- {
+		ep = idx.greedy(vector, ep, l)
+	}
+	for l := min(level, idx.maxLevel); l >= 0; l-- {
+		candidates := idx.searchLayer(vector, ep, idx.efConstruction, l)
+		node.friends[l] = idx.selectNeighbors(candidates, idx.maxFriends(l))
+		for _, friend := range node.friends[l] {
+			idx.link(friend, id, l)
+		}
+		ep = candidates[0].id
+	}
+	if level > idx.maxLevel {
+		idx.entry, idx.maxLevel = id, level
+	}
+}
+
+// Remove deletes key from the index; it does nothing if the key is not indexed.
+func (idx *hnswIndex) Remove(key string) {
+	id, ok := idx.ids[key]
+	if !ok {
+		return
+	}
+	delete(idx.ids, key)
+	idx.nodes[id].deleted = true
+	idx.deleted++
+
+	if len(idx.ids) == 0 {
+		idx.reset()
+	} else if idx.deleted > len(idx.nodes)/2 {
+		idx.rebuild()
+	}
+}
+
+// Search returns up to k indexed keys most similar to query, most similar first.
+func (idx *hnswIndex) Search(query Vector, k int) []neighbor {
+	if k <= 0 || len(idx.ids) == 0 {
+		return nil
+	}
+	if len(idx.ids) <= idx.exactLimit {
+		return idx.exactSearch(query, k)
+	}
+
+	ep := idx.entry
+	for l := idx.maxLevel; l > 0; l-- {
+		ep = idx.greedy(query, ep, l)
+	}
+	found := idx.searchLayer(query, ep, max(idx.efSearch, k), 0)
+
+	results := make([]neighbor, 0, k)
+	for _, c := range found {
+		if node := idx.nodes[c.id]; !node.deleted {
+			results = append(results, neighbor{key: node.key, similarity: c.similarity})
+			if len(results) == k {
+				break
+			}
+		}
+	}
+	return results
+}
+
+// exactSearch compares query with every indexed vector.
+func (idx *hnswIndex) exactSearch(query Vector, k int) []neighbor {
+	results := make([]neighbor, 0, len(idx.ids))
+	for key, id := range idx.ids {
+		results = append(results, neighbor{key: key, similarity: cosineSimilarity(query, idx.nodes[id].vector)})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].similarity > results[j].similarity
+	})
+	if len(results) > k {
+		results = results[:k]
+	}
+	return results
+}
+
+// reset empties the index.
+func (idx *hnswIndex) reset() {
+	idx.nodes = nil
+	idx.ids = make(map[string]int)
+	idx.entry, idx.maxLevel, idx.deleted = -1, 0, 0
+}
+
+// rebuild re-creates the graph from the vectors that are not deleted.
+func (idx *hnswIndex) rebuild() {
+	live := make([]*hnswNode, 0, len(idx.ids))
+	for _, node := range idx.nodes {
+		if !node.deleted {
+			live = append(live, node)
+		}
+	}
+	idx.reset()
+	for _, node := range live {
+		idx.Add(node.key, node.vector)
+	}
+}
+
+// maxFriends returns how many neighbors a node keeps on layer l.
+func (idx *hnswIndex) maxFriends(l int) int {
+	if l == 0 {
+		return 2 * idx.m
+	}
+	return idx.m
+}
+
+// greedy walks layer l from ep towards the node most similar to query.
+func (idx *hnswIndex) greedy(query Vector, ep, l int) int {
+	best := cosineSimilarity(query, idx.nodes[ep].vector)
+	for changed := true; changed; {
+		changed = false
+		for _, friend := range idx.nodes[ep].friends[l] {
+			if sim := cosineSimilarity(query, idx.nodes[friend].vector); sim > best {
+				best, ep, changed = sim, friend, true
+			}
+		}
+	}
+	return ep
+}
+
+// searchLayer returns the ef nodes of layer l most similar to query found from ep, most
+// similar first. Deleted nodes are included, as they still connect the graph.
+func (idx *hnswIndex) searchLayer(query Vector, ep, ef, l int) []candidate {
+	start := candidate{id: ep, similarity: cosineSimilarity(query, idx.nodes[ep].vector)}
+	visited := make([]bool, len(idx.nodes))
+	visited[ep] = true
+	frontier := &candidateHeap{items: []candidate{start}, closest: true}
+	results := &candidateHeap{items: []candidate{start}}
+
+	for frontier.Len() > 0 {
+		current := heap.Pop(frontier).(candidate)
+		if results.Len() >= ef && current.similarity < results.items[0].similarity {
+			break
+		}
+		for _, friend := range idx.nodes[current.id].friends[l] {
+			if visited[friend] {
+				continue
+			}
+			visited[friend] = true
+			c := candidate{id: friend, similarity: cosineSimilarity(query, idx.nodes[friend].vector)}
+			if results.Len() < ef || c.similarity > results.items[0].similarity {
+				heap.Push(frontier, c)
+				heap.Push(results, c)
+				if results.Len() > ef {
+					heap.Pop(results)
+				}
+			}
+		}
+	}
+
+	found := results.items
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].similarity > found[j].similarity
+	})
+	return found
+}
+
+// selectNeighbors returns the ids of the n most similar candidates, which are sorted.
+func (idx *hnswIndex) selectNeighbors(candidates []candidate, n int) []int {
+	if len(candidates) > n {
+		candidates = candidates[:n]
+	}
+	ids := make([]int, len(candidates))
+	for i, c := range candidates {
+		ids[i] = c.id
+	}
+	return ids
+}
+
+// link adds to the neighbors of node on layer l, dropping the least similar neighbor if
+// the node then has too many.
+func (idx *hnswIndex) link(node, to, l int) {
+	n := idx.nodes[node]
+	n.friends[l] = append(n.friends[l], to)
+	if len(n.friends[l]) <= idx.maxFriends(l) {
+		return
+	}
+
+	candidates := make([]candidate, len(n.friends[l]))
+	for i, friend := range n.friends[l] {
+		candidates[i] = candidate{id: friend, similarity: cosineSimilarity(n.vector, idx.nodes[friend].vector)}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].similarity > candidates[j].similarity
+	})
+	n.friends[l] = idx.selectNeighbors(candidates, idx.maxFriends(l))
+}
+
+// candidate is a node visited by a search.
+type candidate struct {
+	id         int
+	similarity float32
+}
+
+// candidateHeap is a heap of candidates; the least similar is on top unless closest is set.
+type candidateHeap struct {
+	items   []candidate
+	closest bool
+}
+
+func (h *candidateHeap) Len() int { return len(h.items) }
+
+func (h *candidateHeap) Less(i, j int) bool {
+	if h.closest {
+		return h.items[i].similarity > h.items[j].similarity
+	}
+	return h.items[i].similarity < h.items[j].similarity
+}
+
+func (h *candidateHeap) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+
+func (h *candidateHeap) Push(x interface{}) { h.items = append(h.items, x.(candidate)) }
+
+func (h *candidateHeap) Pop() interface{} {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return last
+}
@@ -0,0 +1,90 @@
+package semantic_cache
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func randomVectors(rng *rand.Rand, n, dim int) []Vector {
+	vectors := make([]Vector, n)
+	for i := range vectors {
+		v := make(Vector, dim)
+		for j := range v {
+			v[j] = float32(rng.NormFloat64())
+		}
+		normalize(v)
+		vectors[i] = v
+	}
+	return vectors
+}
+
+func TestHNSWIndexRecall(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	vectors := randomVectors(rng, 2000, 32)
+
+	idx := newHNSWIndex(0, 0)
+	exact := newHNSWIndex(0, 0)
+	idx.exactLimit = 0
+	exact.exactLimit = len(vectors)
+	for i, v := range vectors {
+		key := fmt.Sprintf("k%d", i)
+		idx.Add(key, v)
+		exact.Add(key, v)
+	}
+
+	queries := randomVectors(rng, 200, 32)
+	hits := 0
+	for _, q := range queries {
+		got := idx.Search(q, 1)
+		want := exact.Search(q, 1)
+		if len(got) == 1 && got[0].key == want[0].key {
+			hits++
+		}
+	}
+	if recall := float64(hits) / float64(len(queries)); recall < 0.95 {
+		t.Errorf("top-1 recall = %.2f, want at least 0.95", recall)
+	}
+
+	for i, v := range vectors[:50] {
+		if got := idx.Search(v, 1); len(got) != 1 || got[0].key != fmt.Sprintf("k%d", i) {
+			t.Errorf("Search(vector %d) = %v, want the vector itself", i, got)
+		}
+	}
+}
+
+func TestHNSWIndexRemove(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	vectors := randomVectors(rng, 1000, 16)
+
+	idx := newHNSWIndex(8, 32)
+	idx.exactLimit = 0
+	for i, v := range vectors {
+		idx.Add(fmt.Sprintf("k%d", i), v)
+	}
+	for i := 0; i < 800; i++ {
+		idx.Remove(fmt.Sprintf("k%d", i))
+	}
+	if idx.Len() != 200 || len(idx.nodes) >= 1000 {
+		t.Errorf("Len() = %d with %d nodes, want 200 after a rebuild", idx.Len(), len(idx.nodes))
+	}
+
+	for i := 800; i < 1000; i += 10 {
+		got := idx.Search(vectors[i], 3)
+		if len(got) == 0 || got[0].key != fmt.Sprintf("k%d", i) {
+			t.Fatalf("Search(vector %d) = %v", i, got)
+		}
+		for _, n := range got {
+			if _, ok := idx.ids[n.key]; !ok {
+				t.Errorf("Search returned removed key %s", n.key)
+			}
+		}
+	}
+
+	for i := 800; i < 1000; i++ {
+		idx.Remove(fmt.Sprintf("k%d", i))
+	}
+	if got := idx.Search(vectors[0], 1); got != nil || idx.entry != -1 {
+		t.Errorf("Search on an emptied index = %v", got)
+	}
+}
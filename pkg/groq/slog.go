@@ -0,0 +1,120 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/genc-murat/groq-client/internal/util"
+)
+
+// WithSlog makes the client emit a structured log record through handler for every call:
+// endpoint, model, attempts, final status, latency, token usage, cache hit or miss, the
+// server and client request IDs of failed calls and the call's annotations (see
+// ContextWithAnnotations).
+// Successful calls are logged at Info level, failed ones at Error. Every HTTP attempt is
+// additionally logged at Debug level, which shows retries and failovers.
+//
+// Example usage:
+//
+//	client := NewClient(apiKey, WithSlog(slog.NewJSONHandler(os.Stderr, nil)))
+//
+// Parameters:
+//   - handler: The handler that receives the records; nil disables logging.
+//
+// Returns:
+//   - Option: A function that sets the logger.
+func WithSlog(handler slog.Handler) Option {
+	return func(c *Client) {
+		if handler == nil {
+			c.logger = nil
+			return
+		}
+		c.logger = slog.New(handler)
+	}
+}
+
+// logCall logs the record of a finished call.
+func (c *Client) logCall(ctx context.Context, trace *callTrace, latency time.Duration, err error) {
+	trace.mu.Lock()
+	attrs := []slog.Attr{
+		slog.String("endpoint", string(trace.endpoint)),
+		slog.String("model", string(trace.model)),
+		slog.Int("attempts", trace.attempts),
+		slog.Duration("latency", latency),
+	}
+	if trace.status != 0 {
+		attrs = append(attrs, slog.Int("status", trace.status))
+	}
+	if trace.cache != "" {
+		attrs = append(attrs, slog.String("cache", trace.cache))
+	}
+	if u := trace.usage; u != nil && u.TotalTokens > 0 {
+		attrs = append(attrs,
+			slog.Int("prompt_tokens", u.PromptTokens),
+			slog.Int("completion_tokens", u.CompletionTokens),
+			slog.Int("total_tokens", u.TotalTokens),
+		)
+	}
+	requestID, clientID := trace.requestID, trace.clientID
+	trace.mu.Unlock()
+
+	level := slog.LevelInfo
+	if err != nil {
+		level = slog.LevelError
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.RequestID != "" {
+			requestID = apiErr.RequestID
+		}
+		if requestID != "" {
+			attrs = append(attrs, slog.String("request_id", requestID))
+		}
+		if clientID != "" {
+			attrs = append(attrs, slog.String("client_request_id", clientID))
+		}
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	if len(trace.annotations) > 0 {
+		attrs = append(attrs, annotationsAttr(trace.annotations))
+	}
+
+	c.logger.LogAttrs(ctx, level, "groq request", attrs...)
+}
+
+// logAttempt logs one HTTP attempt of a call at Debug level.
+func (c *Client) logAttempt(ctx context.Context, trace *callTrace, info util.AttemptInfo) {
+	if !c.logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("endpoint", string(trace.endpoint)),
+		slog.String("method", info.Method),
+		slog.String("url", info.URL),
+		slog.Int("attempt", info.Attempt+1),
+		slog.Duration("latency", info.Latency),
+	}
+	if info.StatusCode != 0 {
+		attrs = append(attrs, slog.Int("status", info.StatusCode))
+	}
+	if info.Err != nil {
+		attrs = append(attrs, slog.String("error", info.Err.Error()))
+	}
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "groq http attempt", attrs...)
+}
+
+// annotationsAttr groups annotations under "annotations", sorted by key.
+func annotationsAttr(annotations map[string]string) slog.Attr {
+	keys := make([]string, 0, len(annotations))
+	for key := range annotations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]any, 0, len(keys))
+	for _, key := range keys {
+		attrs = append(attrs, slog.String(key, annotations[key]))
+	}
+	return slog.Group("annotations", attrs...)
+}
@@ -0,0 +1,123 @@
+package groq
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// logRecords decodes the JSON log lines written to buf.
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestWithSlog(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","choices":[{"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}],` +
+			`"usage":{"prompt_tokens":7,"completion_tokens":3,"total_tokens":10}}`))
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	client := NewClient("test-key",
+		WithBaseURL(srv.URL),
+		WithCache(newMapCache()),
+		WithSlog(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	)
+	defer client.Close()
+
+	req := &ChatCompletionRequest{
+		Model:       ModelLlama31_8bInstant,
+		Messages:    []ChatMessage{{Role: "user", Content: "hello"}},
+		Annotations: map[string]string{"feature": "greeting"},
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := client.CreateChatCompletion(context.Background(), req); err != nil {
+			t.Fatalf("CreateChatCompletion: %v", err)
+		}
+	}
+
+	records := logRecords(t, &buf)
+	if len(records) != 3 {
+		t.Fatalf("expected an attempt and two request records, got %d: %s", len(records), buf.String())
+	}
+
+	attempt := records[0]
+	if attempt["msg"] != "groq http attempt" || attempt["level"] != "DEBUG" || attempt["status"] != float64(200) {
+		t.Errorf("unexpected attempt record: %v", attempt)
+	}
+
+	miss := records[1]
+	if miss["msg"] != "groq request" || miss["level"] != "INFO" {
+		t.Errorf("unexpected request record: %v", miss)
+	}
+	if miss["endpoint"] != "chat" || miss["model"] != string(ModelLlama31_8bInstant) || miss["cache"] != "miss" {
+		t.Errorf("unexpected request fields: %v", miss)
+	}
+	if miss["attempts"] != float64(1) || miss["total_tokens"] != float64(10) || miss["prompt_tokens"] != float64(7) {
+		t.Errorf("unexpected attempts or usage: %v", miss)
+	}
+	if annotations, _ := miss["annotations"].(map[string]interface{}); annotations["feature"] != "greeting" {
+		t.Errorf("expected annotations in the record: %v", miss)
+	}
+
+	hit := records[2]
+	if hit["cache"] != "hit" || hit["attempts"] != float64(0) {
+		t.Errorf("expected a cache hit without attempts: %v", hit)
+	}
+	if _, ok := hit["total_tokens"]; ok {
+		t.Errorf("cache hits must not report token usage: %v", hit)
+	}
+}
+
+func TestWithSlogError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-request-id", "req_42")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":{"message":"bad","type":"invalid_request_error"}}`))
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	client := NewClient("test-key", WithBaseURL(srv.URL), WithSlog(slog.NewJSONHandler(&buf, nil)))
+	defer client.Close()
+
+	_, err := client.CreateChatCompletion(context.Background(), &ChatCompletionRequest{
+		Model:    ModelLlama31_8bInstant,
+		Messages: []ChatMessage{{Role: "user", Content: "hello"}},
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	records := logRecords(t, &buf)
+	if len(records) != 1 {
+		t.Fatalf("expected one record at the default level, got %d: %s", len(records), buf.String())
+	}
+	record := records[0]
+	if record["level"] != "ERROR" || record["status"] != float64(400) || record["request_id"] != "req_42" {
+		t.Errorf("unexpected error record: %v", record)
+	}
+	if _, ok := record["error"]; !ok {
+		t.Errorf("expected the error in the record: %v", record)
+	}
+}
@@ -0,0 +1,209 @@
+package groq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+type SpeechFormat string
+
+const (
+	SpeechFormatWAV  SpeechFormat = "wav"
+	SpeechFormatMP3  SpeechFormat = "mp3"
+	SpeechFormatFLAC SpeechFormat = "flac"
+)
+
+type Voice string
+
+// English voices of ModelPlayAITTS.
+const (
+	VoiceArista   Voice = "Arista-PlayAI"
+	VoiceAtlas    Voice = "Atlas-PlayAI"
+	VoiceBasil    Voice = "Basil-PlayAI"
+	VoiceBriggs   Voice = "Briggs-PlayAI"
+	VoiceCalum    Voice = "Calum-PlayAI"
+	VoiceCeleste  Voice = "Celeste-PlayAI"
+	VoiceCheyenne Voice = "Cheyenne-PlayAI"
+	VoiceChip     Voice = "Chip-PlayAI"
+	VoiceCillian  Voice = "Cillian-PlayAI"
+	VoiceDeedee   Voice = "Deedee-PlayAI"
+	VoiceFritz    Voice = "Fritz-PlayAI"
+	VoiceGail     Voice = "Gail-PlayAI"
+	VoiceIndigo   Voice = "Indigo-PlayAI"
+	VoiceMamaw    Voice = "Mamaw-PlayAI"
+	VoiceMason    Voice = "Mason-PlayAI"
+	VoiceMikail   Voice = "Mikail-PlayAI"
+	VoiceMitch    Voice = "Mitch-PlayAI"
+	VoiceQuinn    Voice = "Quinn-PlayAI"
+	VoiceThunder  Voice = "Thunder-PlayAI"
+)
+
+// Arabic voices of ModelPlayAITTSArabic.
+const (
+	VoiceAhmad  Voice = "Ahmad-PlayAI"
+	VoiceAmira  Voice = "Amira-PlayAI"
+	VoiceKhalid Voice = "Khalid-PlayAI"
+	VoiceNasser Voice = "Nasser-PlayAI"
+)
+
+const (
+	// DefaultSpeechVoice is used when a SpeechRequest for ModelPlayAITTS does not name a voice.
+	DefaultSpeechVoice = VoiceFritz
+	// DefaultArabicSpeechVoice is used when a SpeechRequest for ModelPlayAITTSArabic does not name a voice.
+	DefaultArabicSpeechVoice = VoiceAhmad
+	// MaxSpeechInputLength is the maximum number of characters per speech request.
+	MaxSpeechInputLength = 10000
+)
+
+var speechVoices = map[ModelType][]Voice{
+	ModelPlayAITTS: {
+		VoiceArista, VoiceAtlas, VoiceBasil, VoiceBriggs, VoiceCalum, VoiceCeleste, VoiceCheyenne,
+		VoiceChip, VoiceCillian, VoiceDeedee, VoiceFritz, VoiceGail, VoiceIndigo, VoiceMamaw,
+		VoiceMason, VoiceMikail, VoiceMitch, VoiceQuinn, VoiceThunder,
+	},
+	ModelPlayAITTSArabic: {VoiceAhmad, VoiceAmira, VoiceKhalid, VoiceNasser},
+}
+
+// SpeechVoices returns the voices supported by a text-to-speech model, or nil if the
+// model does not support speech synthesis.
+func SpeechVoices(model ModelType) []Voice {
+	return append([]Voice(nil), speechVoices[model]...)
+}
+
+// SupportsVoice reports whether the model can speak with voice.
+func (m ModelType) SupportsVoice(voice Voice) bool {
+	for _, v := range speechVoices[m] {
+		if v == voice {
+			return true
+		}
+	}
+	return false
+}
+
+type SpeechRequest struct {
+	Model          ModelType    `json:"model"`
+	Input          string       `json:"input"`
+	Voice          Voice        `json:"voice"`
+	ResponseFormat SpeechFormat `json:"response_format,omitempty"`
+	Speed          float64      `json:"speed,omitempty"`
+}
+
+// Validate checks the SpeechRequest. It verifies:
+// - The model is a text-to-speech model and supports the voice
+// - The input is not empty and at most MaxSpeechInputLength characters
+// - The response format is wav, mp3 or flac, if set
+// - The speed is between 0.5 and 5, if set
+//
+// Returns an error if any validation check fails, nil otherwise.
+func (r *SpeechRequest) Validate() error {
+	voices, ok := speechVoices[r.Model]
+	if !ok {
+		return newLocalizedError(nil, MsgSpeechUnsupported, r.Model)
+	}
+	if !r.Model.SupportsVoice(r.Voice) {
+		names := make([]string, len(voices))
+		for i, v := range voices {
+			names[i] = string(v)
+		}
+		return newLocalizedError(nil, MsgVoiceUnavailable, r.Voice, r.Model, strings.Join(names, ", "))
+	}
+	if r.Input == "" {
+		return newLocalizedError(nil, MsgSpeechInputRequired)
+	}
+	if n := utf8.RuneCountInString(r.Input); n > MaxSpeechInputLength {
+		return newLocalizedError(nil, MsgSpeechInputTooLong, n, MaxSpeechInputLength)
+	}
+	switch r.ResponseFormat {
+	case "", SpeechFormatWAV, SpeechFormatMP3, SpeechFormatFLAC:
+	default:
+		return newLocalizedError(nil, MsgSpeechFormat, r.ResponseFormat)
+	}
+	if r.Speed != 0 && (r.Speed < 0.5 || r.Speed > 5) {
+		return newLocalizedError(nil, MsgSpeechSpeed)
+	}
+	return nil
+}
+
+// CreateSpeech converts text to speech with a PlayAI TTS model and returns the audio.
+// The model defaults to ModelPlayAITTS, the voice to the model's default voice and the
+// format to wav. Voices that the model does not support are rejected before sending.
+//
+// Parameters:
+//   - ctx: Context for the request
+//   - req: SpeechRequest containing:
+//   - Input: The text to speak
+//   - Model: (Optional) The TTS model
+//   - Voice: (Optional) The voice, one of SpeechVoices(Model)
+//   - ResponseFormat: (Optional) wav, mp3 or flac
+//   - Speed: (Optional) Playback speed between 0.5 and 5
+//   - opts: Optional settings of this call, such as WithRequestTimeout or WithRequestAPIKey
+//
+// Returns:
+//   - []byte: The encoded audio
+//   - error: Any error that occurred during the request
+func (c *Client) CreateSpeech(ctx context.Context, req *SpeechRequest, opts ...RequestOption) ([]byte, error) {
+	ctx, cancel := applyRequestOptions(ctx, opts)
+	defer cancel()
+	if req.Model == "" {
+		req.Model = ModelPlayAITTS
+	}
+	if req.Voice == "" {
+		req.Voice = DefaultSpeechVoice
+		if req.Model == ModelPlayAITTSArabic {
+			req.Voice = DefaultArabicSpeechVoice
+		}
+	}
+	if req.ResponseFormat == "" {
+		req.ResponseFormat = SpeechFormatWAV
+	}
+	if err := req.Validate(); err != nil {
+		return nil, c.invalidRequest(err)
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	ctx, trace := c.beginCall(ctx, EndpointSpeech, req.Model)
+	if err := c.waitRequestLimits(ctx, EndpointSpeech, req.Model); err != nil {
+		c.endCall(ctx, trace, err)
+		return nil, err
+	}
+	audio, err := c.httpClient.DoRequest(
+		ctx,
+		"POST",
+		c.endpointURL(ctx, "/audio/speech"),
+		body,
+		map[string]string{"Content-Type": "application/json"},
+	)
+	c.endCall(ctx, trace, err)
+	if err != nil {
+		return nil, fmt.Errorf("speech request failed: %w", err)
+	}
+
+	return audio, nil
+}
+
+// CreateSpeechTo converts text to speech like CreateSpeech and writes the audio to w,
+// for example a file or an HTTP response.
+//
+// Returns:
+//   - int64: The number of bytes written
+//   - error: Any error that occurred during the request or while writing
+func (c *Client) CreateSpeechTo(ctx context.Context, req *SpeechRequest, w io.Writer, opts ...RequestOption) (int64, error) {
+	audio, err := c.CreateSpeech(ctx, req, opts...)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := w.Write(audio)
+	if err != nil {
+		return int64(n), fmt.Errorf("failed to write speech audio: %w", err)
+	}
+	return int64(n), nil
+}
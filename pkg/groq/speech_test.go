@@ -0,0 +1,63 @@
+package groq
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCreateSpeech(t *testing.T) {
+	var got SpeechRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/audio/speech" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.Header().Set("Content-Type", "audio/wav")
+		_, _ = w.Write([]byte("RIFF-audio"))
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key", WithBaseURL(srv.URL))
+
+	var out bytes.Buffer
+	n, err := client.CreateSpeechTo(context.Background(), &SpeechRequest{Input: "Merhaba", Speed: 1.5}, &out)
+	if err != nil {
+		t.Fatalf("CreateSpeechTo() error = %v", err)
+	}
+	if n != int64(out.Len()) || out.String() != "RIFF-audio" {
+		t.Errorf("unexpected audio %q (%d bytes)", out.String(), n)
+	}
+	if got.Model != ModelPlayAITTS || got.Voice != DefaultSpeechVoice || got.ResponseFormat != SpeechFormatWAV || got.Speed != 1.5 {
+		t.Errorf("unexpected request: %+v", got)
+	}
+
+	if _, err := client.CreateSpeech(context.Background(), &SpeechRequest{Input: "x", Speed: 9}); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("expected invalid speed to be rejected, got %v", err)
+	}
+}
+
+func TestSpeechVoiceValidation(t *testing.T) {
+	if err := (&SpeechRequest{Model: ModelPlayAITTSArabic, Voice: VoiceAmira, Input: "مرحبا"}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	err := (&SpeechRequest{Model: ModelPlayAITTSArabic, Voice: VoiceFritz, Input: "hello"}).Validate()
+	if err == nil || !strings.Contains(err.Error(), string(VoiceAhmad)) {
+		t.Errorf("expected a voice error listing the Arabic voices, got %v", err)
+	}
+
+	if err := (&SpeechRequest{Model: ModelLlama31_8bInstant, Voice: VoiceFritz, Input: "hello"}).Validate(); err == nil {
+		t.Error("expected a non-TTS model to be rejected")
+	}
+
+	if len(SpeechVoices(ModelPlayAITTS)) != 19 || SpeechVoices(ModelLlama31_8bInstant) != nil {
+		t.Error("unexpected voice catalog")
+	}
+}
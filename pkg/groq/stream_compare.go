@@ -0,0 +1,142 @@
+package groq
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+type LabeledChunk struct {
+	Model ModelType
+	Seq   int                  // Position of the chunk within its model's stream, starting at 0
+	Chunk *ChatCompletionChunk // nil on the final event of a stream
+	Done  bool                 // Set on the final event of a stream
+	Err   error                // The stream error, only set when Done
+}
+
+type StreamComparisonResult struct {
+	Model            ModelType
+	Text             string
+	Chunks           int
+	TimeToFirstChunk time.Duration
+	Duration         time.Duration
+	Usage            Usage // Reported by the API, or estimated from the stream
+	UsageEstimated   bool  // Usage is an estimate, see StreamUsageEstimator
+	Err              error
+}
+
+// CompareStreams runs the same request on several models at the same time and merges
+// their streamed chunks into a single sequence of labelled events for side-by-side
+// display. The handler is always called from the calling goroutine, one event at a
+// time; chunks of each model arrive in their original order, followed by exactly one
+// Done event per model. Chunks of different models are interleaved as they arrive.
+//
+// Parameters:
+//   - ctx: Context for all streams.
+//   - req: The request to run; it is copied for every model and not modified.
+//   - models: The models to compare, e.g. the current model and its upgrade.
+//   - handler: Receives the merged events. Returning an error cancels all streams.
+//
+// Returns:
+//   - map[ModelType]*StreamComparisonResult: Full text, timing and error per model.
+//   - error: A validation error, the handler error, or ctx.Err().
+func (c *Client) CompareStreams(ctx context.Context, req *ChatCompletionRequest, models []ModelType, handler func(LabeledChunk) error) (map[ModelType]*StreamComparisonResult, error) {
+	if len(models) == 0 {
+		return nil, fmt.Errorf("%w: at least one model is required", ErrInvalidRequest)
+	}
+	seen := make(map[ModelType]bool, len(models))
+	for _, model := range models {
+		if seen[model] {
+			return nil, fmt.Errorf("%w: model %s is listed twice", ErrInvalidRequest, model)
+		}
+		seen[model] = true
+	}
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	events := make(chan LabeledChunk)
+	var wg sync.WaitGroup
+
+	for _, model := range models {
+		clone := *req
+		clone.Messages = append([]ChatMessage(nil), req.Messages...)
+		clone.Model = model
+
+		wg.Add(1)
+		go func(model ModelType, req *ChatCompletionRequest) {
+			defer wg.Done()
+
+			seq := 0
+			err := c.CreateChatCompletionStream(streamCtx, req, func(chunk *ChatCompletionChunk) error {
+				select {
+				case events <- LabeledChunk{Model: model, Seq: seq, Chunk: chunk}:
+					seq++
+					return nil
+				case <-streamCtx.Done():
+					return streamCtx.Err()
+				}
+			})
+
+			select {
+			case events <- LabeledChunk{Model: model, Seq: seq, Done: true, Err: err}:
+			case <-streamCtx.Done():
+			}
+		}(model, &clone)
+	}
+
+	go func() {
+		wg.Wait()
+		close(events)
+	}()
+
+	start := time.Now()
+	results := make(map[ModelType]*StreamComparisonResult, len(models))
+	texts := make(map[ModelType]*strings.Builder, len(models))
+	usages := make(map[ModelType]*StreamUsageEstimator, len(models))
+	for _, model := range models {
+		results[model] = &StreamComparisonResult{Model: model}
+		texts[model] = &strings.Builder{}
+		usages[model] = NewStreamUsageEstimator(req)
+	}
+
+	var handlerErr error
+	for event := range events {
+		if handlerErr != nil {
+			continue
+		}
+
+		result := results[event.Model]
+		if event.Done {
+			result.Duration = time.Since(start)
+			result.Err = event.Err
+		} else {
+			if result.Chunks == 0 {
+				result.TimeToFirstChunk = time.Since(start)
+			}
+			result.Chunks++
+			usages[event.Model].Observe(event.Chunk)
+			for _, choice := range event.Chunk.Choices {
+				texts[event.Model].WriteString(choice.Delta.Content)
+			}
+		}
+
+		if err := handler(event); err != nil {
+			handlerErr = fmt.Errorf("compare handler error: %w", err)
+			cancel()
+		}
+	}
+
+	for model, result := range results {
+		result.Text = texts[model].String()
+		result.Usage = usages[model].Usage()
+		result.UsageEstimated = usages[model].Estimated()
+	}
+
+	if handlerErr != nil {
+		return results, handlerErr
+	}
+	return results, ctx.Err()
+}
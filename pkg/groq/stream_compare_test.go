@@ -0,0 +1,53 @@
+package groq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCompareStreams(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ChatCompletionRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i := 0; i < 3; i++ {
+			fmt.Fprintf(w, "data: {\"model\":%q,\"choices\":[{\"delta\":{\"content\":\"%s-%d \"}}]}\n\n", req.Model, req.Model, i)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key", WithBaseURL(srv.URL))
+	models := []ModelType{ModelLlama31_8bInstant, ModelLlama33_70bVersatile}
+
+	nextSeq := map[ModelType]int{}
+	done := map[ModelType]int{}
+	results, err := client.CompareStreams(context.Background(), NewRequest(ModelLlama31_8bInstant).User("hi").Build(), models, func(ev LabeledChunk) error {
+		if ev.Done {
+			done[ev.Model]++
+			return ev.Err
+		}
+		if ev.Seq != nextSeq[ev.Model] || ev.Chunk.Model != ev.Model {
+			return fmt.Errorf("out of order chunk %+v", ev)
+		}
+		nextSeq[ev.Model]++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("CompareStreams() error = %v", err)
+	}
+
+	for _, model := range models {
+		want := fmt.Sprintf("%s-0 %s-1 %s-2 ", model, model, model)
+		if got := results[model]; got.Text != want || got.Chunks != 3 || got.Err != nil {
+			t.Errorf("%s: unexpected result %+v", model, got)
+		}
+		if done[model] != 1 {
+			t.Errorf("%s: expected one Done event, got %d", model, done[model])
+		}
+	}
+}
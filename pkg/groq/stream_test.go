@@ -0,0 +1,129 @@
+package groq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStreamStopsOnFinishReason(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"a\"}},{\"index\":1,\"delta\":{\"content\":\"b\"}}]}\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"index\":0,\"delta\":{},\"finish_reason\":\"stop\"}]}\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"index\":1,\"delta\":{},\"finish_reason\":\"length\"}]}\n\n")
+		// Anything after the last finish_reason must not reach the handler.
+		fmt.Fprint(w, "data: {not json}\n\n")
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key", WithBaseURL(srv.URL))
+
+	var reasons []FinishReason
+	err := client.CreateChatCompletionStream(context.Background(), NewRequest(ModelLlama31_8bInstant).User("hi").Build(), func(chunk *ChatCompletionChunk) error {
+		for _, choice := range chunk.Choices {
+			if choice.FinishReason.IsTerminal() {
+				reasons = append(reasons, choice.FinishReason)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("CreateChatCompletionStream() error = %v", err)
+	}
+	if len(reasons) != 2 || reasons[0] != FinishReasonStop || reasons[1] != FinishReasonLength {
+		t.Errorf("unexpected finish reasons: %v", reasons)
+	}
+}
+
+func TestStreamLongLines(t *testing.T) {
+	long := strings.Repeat("x", 20000) // Longer than the line reader's buffer
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":%q}}]}\n\n", long)
+		fmt.Fprint(w, "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"!\"}}]}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key", WithBaseURL(srv.URL))
+	defer client.Close()
+
+	var got strings.Builder
+	err := client.CreateChatCompletionStream(context.Background(), NewRequest(ModelLlama31_8bInstant).User("hi").Build(), func(chunk *ChatCompletionChunk) error {
+		got.WriteString(chunk.Choices[0].Delta.Content)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("CreateChatCompletionStream() error = %v", err)
+	}
+	if got.String() != long+"!" {
+		t.Errorf("stream content has %d bytes, want %d", got.Len(), len(long)+1)
+	}
+}
+
+func TestChatCompletionChunkFinished(t *testing.T) {
+	tests := map[string]bool{
+		`{"choices":[]}`: false,
+		`{"choices":[{"delta":{"content":"a"},"finish_reason":null}]}`:                         false,
+		`{"choices":[{"delta":{},"finish_reason":"tool_calls"}]}`:                              true,
+		`{"choices":[{"index":0,"finish_reason":"stop"},{"index":1,"delta":{"content":"b"}}]}`: false,
+	}
+	for data, want := range tests {
+		var chunk ChatCompletionChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			t.Fatal(err)
+		}
+		if got := chunk.Finished(); got != want {
+			t.Errorf("Finished() for %s = %v, want %v", data, got, want)
+		}
+	}
+}
+
+func TestStreamUsageEstimator(t *testing.T) {
+	req := NewRequest(ModelLlama31_8bInstant).User("12345678").Build()
+
+	chunks := []string{
+		`{"choices":[{"index":0,"delta":{"content":"abcd"}}]}`,
+		`{"choices":[{"index":0,"delta":{"content":"efgh i"},"finish_reason":"stop"}]}`,
+	}
+	usage := NewStreamUsageEstimator(req)
+	var seen int
+	handler := usage.Wrap(func(*ChatCompletionChunk) error {
+		seen++
+		return nil
+	})
+	for _, data := range chunks {
+		var chunk ChatCompletionChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			t.Fatal(err)
+		}
+		if err := handler(&chunk); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if seen != 2 {
+		t.Errorf("expected the wrapped handler to see 2 chunks, got %d", seen)
+	}
+	if !usage.Estimated() {
+		t.Error("expected an estimate without reported usage")
+	}
+	want := Usage{PromptTokens: 6, CompletionTokens: 3, TotalTokens: 9}
+	if got := usage.Usage(); got != want {
+		t.Errorf("Usage() = %+v, want %+v", got, want)
+	}
+
+	var last ChatCompletionChunk
+	if err := json.Unmarshal([]byte(`{"choices":[],"x_groq":{"id":"req_1","usage":{"prompt_tokens":10,"completion_tokens":4,"total_tokens":14}}}`), &last); err != nil {
+		t.Fatal(err)
+	}
+	usage.Observe(&last)
+	if usage.Estimated() || usage.Usage().TotalTokens != 14 {
+		t.Errorf("expected the reported usage to win, got %+v", usage.Usage())
+	}
+}
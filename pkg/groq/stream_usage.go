@@ -0,0 +1,92 @@
+package groq
+
+import (
+	"sync"
+	"unicode/utf8"
+)
+
+type StreamUsageEstimator struct {
+	promptTokens int
+	runes        map[int]int // Emitted runes per choice index
+	reported     *Usage
+	mu           sync.Mutex
+}
+
+// NewStreamUsageEstimator creates an estimator that tracks the token usage of a streamed
+// completion. If the API attaches usage to the stream (Groq does so in x_groq on the last
+// chunk), that usage is used as is; otherwise the prompt is estimated from the request
+// messages and the completion from the emitted text, using EstimateTokens. The result
+// can be fed to EstimateChatCost, budgets or usage tracking like any reported Usage.
+//
+// Example usage:
+//
+//	usage := NewStreamUsageEstimator(req)
+//	err := client.CreateChatCompletionStream(ctx, req, usage.Wrap(handler))
+//	cost := EstimateChatCost(req.Model, usage.Usage())
+//
+// Parameters:
+//   - req: The streamed request, used to estimate the prompt tokens.
+//
+// Returns:
+//   - *StreamUsageEstimator: The estimator.
+func NewStreamUsageEstimator(req *ChatCompletionRequest) *StreamUsageEstimator {
+	return &StreamUsageEstimator{
+		promptTokens: EstimateMessageTokens(req.Messages),
+		runes:        make(map[int]int),
+	}
+}
+
+// Observe records a streamed chunk.
+func (e *StreamUsageEstimator) Observe(chunk *ChatCompletionChunk) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, choice := range chunk.Choices {
+		e.runes[choice.Index] += utf8.RuneCountInString(choice.Delta.Content)
+	}
+	if usage := chunk.ReportedUsage(); usage != nil {
+		reported := *usage
+		e.reported = &reported
+	}
+}
+
+// Wrap returns a StreamHandler that observes every chunk before passing it to handler.
+// A nil handler only observes.
+func (e *StreamUsageEstimator) Wrap(handler StreamHandler) StreamHandler {
+	return func(chunk *ChatCompletionChunk) error {
+		e.Observe(chunk)
+		if handler == nil {
+			return nil
+		}
+		return handler(chunk)
+	}
+}
+
+// Usage returns the usage reported by the API if the stream carried it, and the
+// estimate of the chunks observed so far otherwise.
+func (e *StreamUsageEstimator) Usage() Usage {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.reported != nil {
+		return *e.reported
+	}
+
+	completion := 0
+	for _, n := range e.runes {
+		completion += estimateRuneTokens(n)
+	}
+	return Usage{
+		PromptTokens:     e.promptTokens,
+		CompletionTokens: completion,
+		TotalTokens:      e.promptTokens + completion,
+	}
+}
+
+// Estimated reports whether Usage is an estimate rather than usage reported by the API.
+func (e *StreamUsageEstimator) Estimated() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.reported == nil
+}
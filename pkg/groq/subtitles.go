@@ -0,0 +1,67 @@
+package groq
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// WriteSRT renders the transcript as SubRip (.srt) subtitles, one cue per segment.
+// The transcript must come from a verbose_json request; without segments nothing is written.
+//
+// Parameters:
+//   - w: The writer receiving the subtitles.
+//
+// Returns:
+//   - error: Any error returned by w.
+func (r *TranscriptionResponse) WriteSRT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for i, seg := range r.Segments {
+		fmt.Fprintf(bw, "%d\n%s --> %s\n%s\n\n",
+			i+1, formatSRTTime(seg.Start), formatSRTTime(seg.End), strings.TrimSpace(seg.Text))
+	}
+	return bw.Flush()
+}
+
+// WriteVTT renders the transcript as WebVTT (.vtt) subtitles, one cue per segment.
+// The transcript must come from a verbose_json request; without segments only the
+// WEBVTT header is written.
+//
+// Parameters:
+//   - w: The writer receiving the subtitles.
+//
+// Returns:
+//   - error: Any error returned by w.
+func (r *TranscriptionResponse) WriteVTT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("WEBVTT\n\n")
+	for _, seg := range r.Segments {
+		fmt.Fprintf(bw, "%s --> %s\n%s\n\n",
+			formatVTTTime(seg.Start), formatVTTTime(seg.End), strings.TrimSpace(seg.Text))
+	}
+	return bw.Flush()
+}
+
+// formatSRTTime formats seconds as an SRT timestamp (HH:MM:SS,mmm).
+func formatSRTTime(seconds float64) string {
+	h, m, s, ms := splitTimestamp(seconds)
+	return fmt.Sprintf("%02d:%02d:%02d,%03d", h, m, s, ms)
+}
+
+// formatVTTTime formats seconds as a WebVTT timestamp (HH:MM:SS.mmm).
+func formatVTTTime(seconds float64) string {
+	h, m, s, ms := splitTimestamp(seconds)
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", h, m, s, ms)
+}
+
+// splitTimestamp splits seconds into hours, minutes, seconds and milliseconds.
+func splitTimestamp(seconds float64) (h, m, s, ms int64) {
+	d := time.Duration(seconds*1000+0.5) * time.Millisecond
+	h = int64(d / time.Hour)
+	m = int64(d / time.Minute % 60)
+	s = int64(d / time.Second % 60)
+	ms = int64(d / time.Millisecond % 1000)
+	return h, m, s, ms
+}
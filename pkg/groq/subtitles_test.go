@@ -0,0 +1,71 @@
+package groq
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFormatSRTTime(t *testing.T) {
+	tests := map[float64]string{
+		0:       "00:00:00,000",
+		1.5:     "00:00:01,500",
+		3661.25: "01:01:01,250",
+	}
+	for in, want := range tests {
+		if got := formatSRTTime(in); got != want {
+			t.Errorf("formatSRTTime(%v) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestTranscriptionSubtitles(t *testing.T) {
+	transcript := &TranscriptionResponse{
+		Segments: []TranscriptionSegment{
+			{Start: 0, End: 1.2, Text: " Hello there."},
+			{Start: 1.2, End: 62.005, Text: " General Kenobi."},
+		},
+	}
+
+	var srt strings.Builder
+	if err := transcript.WriteSRT(&srt); err != nil {
+		t.Fatal(err)
+	}
+	wantSRT := "1\n00:00:00,000 --> 00:00:01,200\nHello there.\n\n2\n00:00:01,200 --> 00:01:02,005\nGeneral Kenobi.\n\n"
+	if srt.String() != wantSRT {
+		t.Errorf("unexpected SRT:\n%q\nwant\n%q", srt.String(), wantSRT)
+	}
+
+	var vtt strings.Builder
+	if err := transcript.WriteVTT(&vtt); err != nil {
+		t.Fatal(err)
+	}
+	wantVTT := "WEBVTT\n\n00:00:00.000 --> 00:00:01.200\nHello there.\n\n00:00:01.200 --> 00:01:02.005\nGeneral Kenobi.\n\n"
+	if vtt.String() != wantVTT {
+		t.Errorf("unexpected VTT:\n%q\nwant\n%q", vtt.String(), wantVTT)
+	}
+}
+
+func TestCreateTranscriptionSubtitleFormat(t *testing.T) {
+	const body = "WEBVTT\n\n00:00:00.000 --> 00:00:01.000\nhello\n"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/vtt")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key", WithBaseURL(srv.URL))
+	resp, err := client.CreateTranscription(context.Background(), &TranscriptionRequest{
+		File:           strings.NewReader("data"),
+		FileName:       "a.mp3",
+		ResponseFormat: "vtt",
+	})
+	if err != nil {
+		t.Fatalf("CreateTranscription() error = %v", err)
+	}
+	if resp.Text != body {
+		t.Errorf("expected the raw subtitles, got %q", resp.Text)
+	}
+}
@@ -0,0 +1,163 @@
+package groq
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type SweepGrid struct {
+	Models       []ModelType // Defaults to the model of the base request
+	Temperatures []float64   // Defaults to the temperature of the base request
+	TopPs        []float64   // Defaults to the top_p of the base request
+}
+
+type SweepVariant struct {
+	Label       string
+	Model       ModelType
+	Temperature *float64 // nil if the grid leaves it to the API default
+	TopP        *float64 // nil if the grid leaves it to the API default
+}
+
+type SweepResult struct {
+	Variant  SweepVariant
+	Output   string
+	Response *ChatCompletionResponse
+	Err      error
+}
+
+type SweepReport struct {
+	Results  []SweepResult
+	Usage    Usage
+	Duration time.Duration
+}
+
+// Variants expands the grid into every combination of model, temperature and top_p,
+// filling empty dimensions from base.
+func (g SweepGrid) Variants(base *ChatCompletionRequest) []SweepVariant {
+	models := g.Models
+	if len(models) == 0 {
+		models = []ModelType{base.Model}
+	}
+	temperatures := sweepValues(g.Temperatures, base.Temperature)
+	topPs := sweepValues(g.TopPs, base.TopP)
+
+	variants := make([]SweepVariant, 0, len(models)*len(temperatures)*len(topPs))
+	for _, model := range models {
+		for _, temperature := range temperatures {
+			for _, topP := range topPs {
+				variants = append(variants, SweepVariant{
+					Label:       fmt.Sprintf("%s/t=%s/p=%s", model, formatOptional(temperature), formatOptional(topP)),
+					Model:       model,
+					Temperature: temperature,
+					TopP:        topP,
+				})
+			}
+		}
+	}
+	return variants
+}
+
+// Sweep fans one prompt out across a grid of models, temperatures and top_p values and
+// collects the labelled outputs, for comparing settings during prompt engineering.
+// Requests are sent through a BatchProcessor with up to maxParallel requests in flight
+// and bypass the client cache, so every variant gets a fresh answer.
+//
+// Parameters:
+//   - ctx: Context for all requests.
+//   - base: The request to vary; it is not modified.
+//   - grid: The parameter values to try.
+//   - maxParallel: The maximum number of concurrent requests; values below 1 mean 1.
+//
+// Returns:
+//   - *SweepReport: One result per variant, in grid order. Failed variants carry Err.
+//   - error: An error if a variant is not a valid request.
+func (c *Client) Sweep(ctx context.Context, base *ChatCompletionRequest, grid SweepGrid, maxParallel int) (*SweepReport, error) {
+	if maxParallel < 1 {
+		maxParallel = 1
+	}
+
+	variants := grid.Variants(base)
+	requests := make([]*ChatCompletionRequest, len(variants))
+	for i, v := range variants {
+		req := *base
+		req.Messages = append([]ChatMessage(nil), base.Messages...)
+		req.Model = v.Model
+		req.Temperature = v.Temperature
+		req.TopP = v.TopP
+		if err := c.validateRequest(ctx, &req); err != nil {
+			return nil, fmt.Errorf("%w: variant %s: %v", ErrInvalidRequest, v.Label, err)
+		}
+		requests[i] = &req
+	}
+
+	start := time.Now()
+	responses := c.NewBatchProcessor(maxParallel, maxParallel).
+		ProcessBatch(ContextWithCachePolicy(ctx, CacheBypass), requests)
+
+	report := &SweepReport{Results: make([]SweepResult, len(variants))}
+	for i, v := range variants {
+		result := SweepResult{Variant: v, Response: responses[i].Response, Err: responses[i].Error}
+		if result.Response != nil {
+			report.Usage.Add(result.Response.Usage)
+			if len(result.Response.Choices) > 0 {
+				result.Output = fmt.Sprintf("%v", result.Response.Choices[0].Message.Content)
+			}
+		}
+		report.Results[i] = result
+	}
+	report.Duration = time.Since(start)
+
+	return report, nil
+}
+
+// Table renders the results as a Markdown table with one row per variant. Outputs are
+// flattened to a single line and truncated to maxOutput runes (0 means no limit).
+func (r *SweepReport) Table(maxOutput int) string {
+	var b strings.Builder
+	b.WriteString("| Model | Temperature | Top P | Tokens | Output |\n")
+	b.WriteString("|---|---|---|---|---|\n")
+
+	for _, res := range r.Results {
+		output := res.Output
+		if res.Err != nil {
+			output = "error: " + res.Err.Error()
+		}
+		output = strings.Join(strings.Fields(output), " ")
+		if runes := []rune(output); maxOutput > 0 && len(runes) > maxOutput {
+			output = string(runes[:maxOutput]) + "…"
+		}
+		output = strings.ReplaceAll(output, "|", `\|`)
+
+		tokens := 0
+		if res.Response != nil {
+			tokens = res.Response.Usage.TotalTokens
+		}
+
+		fmt.Fprintf(&b, "| %s | %s | %s | %d | %s |\n", res.Variant.Model, formatOptional(res.Variant.Temperature), formatOptional(res.Variant.TopP), tokens, output)
+	}
+
+	return b.String()
+}
+
+// sweepValues returns the grid values of one dimension as pointers, or the base
+// request's value if the grid leaves the dimension empty.
+func sweepValues(values []float64, base *float64) []*float64 {
+	if len(values) == 0 {
+		return []*float64{base}
+	}
+	ptrs := make([]*float64, len(values))
+	for i, v := range values {
+		ptrs[i] = Float64(v)
+	}
+	return ptrs
+}
+
+// formatOptional formats an optional sampling parameter, using "default" for nil.
+func formatOptional(v *float64) string {
+	if v == nil {
+		return "default"
+	}
+	return fmt.Sprintf("%g", *v)
+}
@@ -0,0 +1,44 @@
+package groq
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSweep(t *testing.T) {
+	srv := newTestServer(t, func(req *ChatCompletionRequest) string {
+		return fmt.Sprintf("%s at %s/%s", req.Model, formatOptional(req.Temperature), formatOptional(req.TopP))
+	})
+	client := NewClient("test-key", WithBaseURL(srv.URL), WithCache(newMapCache()))
+
+	base := NewRequest(ModelLlama31_8bInstant).User("write a haiku").Build()
+	report, err := client.Sweep(context.Background(), base, SweepGrid{
+		Models:       []ModelType{ModelLlama31_8bInstant, ModelLlama33_70bVersatile},
+		Temperatures: []float64{0.2, 0.9},
+		TopPs:        []float64{0.5},
+	}, 2)
+	if err != nil {
+		t.Fatalf("Sweep() error = %v", err)
+	}
+
+	if len(report.Results) != 4 {
+		t.Fatalf("expected 4 variants, got %d", len(report.Results))
+	}
+	for _, res := range report.Results {
+		want := fmt.Sprintf("%s at %s/%s", res.Variant.Model, formatOptional(res.Variant.Temperature), formatOptional(res.Variant.TopP))
+		if res.Err != nil || res.Output != want {
+			t.Errorf("%s: output %q, err %v; want %q", res.Variant.Label, res.Output, res.Err, want)
+		}
+	}
+
+	table := report.Table(20)
+	if lines := strings.Count(table, "\n"); lines != 6 {
+		t.Errorf("expected header, separator and 4 rows, got:\n%s", table)
+	}
+
+	if _, err := client.Sweep(context.Background(), base, SweepGrid{TopPs: []float64{2}}, 1); err == nil {
+		t.Error("expected invalid top_p to be rejected")
+	}
+}
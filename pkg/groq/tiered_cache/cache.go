@@ -0,0 +1,304 @@
+package tiered_cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/genc-murat/groq-client/pkg/groq"
+)
+
+type Tier struct {
+	Name  string
+	Cache groq.Cache
+}
+
+type TierStats struct {
+	Name       string
+	Hits       int64
+	Misses     int64
+	Sets       int64
+	Promotions int64 // Entries copied into this tier from a slower one
+	Errors     int64
+}
+
+type TieredCache struct {
+	tiers        []Tier
+	stats        []TierStats
+	hits         int64
+	misses       int64
+	promoteAfter int
+	hitCounts    map[string]int
+	mu           sync.Mutex
+}
+
+// maxTrackedKeys bounds the memory used to count hits for delayed promotion.
+const maxTrackedKeys = 10000
+
+// New composes caches into a single groq.Cache, ordered from fastest to slowest, for
+// example an exact in-memory cache, a semantic cache and a remote cache:
+//
+//	cache := tiered_cache.New(
+//	    tiered_cache.Tier{Name: "memory", Cache: groq.NewLRUCache(1000, time.Hour)},
+//	    tiered_cache.Tier{Name: "semantic", Cache: semantic_cache.NewSemanticCache(nil)},
+//	    tiered_cache.Tier{Name: "redis", Cache: redisCache},
+//	)
+//
+// Lookups try the tiers in order and stop at the first hit. Hits in a slower tier are
+// promoted into all faster tiers, by default on the first hit (see PromoteAfter). A
+// promoted copy never outlives its source: when the slower tier implements
+// groq.TTLReporter, the copy keeps the remaining TTL of the entry, and faster tiers that
+// do not implement groq.TTLCache are skipped. Writes, deletes and clears go to every tier.
+//
+// Parameters:
+//   - tiers: The cache tiers, fastest first.
+//
+// Returns:
+//   - *TieredCache: A pointer to the new cache.
+func New(tiers ...Tier) *TieredCache {
+	tc := &TieredCache{
+		tiers:        tiers,
+		stats:        make([]TierStats, len(tiers)),
+		promoteAfter: 1,
+		hitCounts:    make(map[string]int),
+	}
+	for i, tier := range tiers {
+		if tier.Name == "" {
+			tc.tiers[i].Name = fmt.Sprintf("L%d", i+1)
+		}
+		tc.stats[i].Name = tc.tiers[i].Name
+	}
+	return tc
+}
+
+// PromoteAfter sets how many hits an entry needs in a slower tier before it is copied
+// into the faster tiers, so that only hot entries take up space in small tiers.
+func (tc *TieredCache) PromoteAfter(hits int) *TieredCache {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if hits < 1 {
+		hits = 1
+	}
+	tc.promoteAfter = hits
+	return tc
+}
+
+// Get looks key up in each tier, fastest first, and promotes hot entries.
+func (tc *TieredCache) Get(ctx context.Context, key string) (*groq.ChatCompletionResponse, bool) {
+	for i := range tc.tiers {
+		resp, ttl, found := tc.lookup(ctx, i, key)
+		if !found {
+			tc.record(func() { tc.stats[i].Misses++ })
+			continue
+		}
+
+		tc.record(func() {
+			tc.stats[i].Hits++
+			tc.hits++
+		})
+		if i > 0 && tc.shouldPromote(key) {
+			tc.promote(ctx, key, resp, i, ttl)
+		}
+		return resp, true
+	}
+
+	tc.record(func() { tc.misses++ })
+	return nil, false
+}
+
+// Set writes value to every tier. It returns the errors of the tiers that failed.
+func (tc *TieredCache) Set(ctx context.Context, key string, value *groq.ChatCompletionResponse) error {
+	return tc.SetWithTTL(ctx, key, value, 0)
+}
+
+// SetWithTTL implements groq.TTLCache: it is like Set but keeps the entry for ttl in the
+// tiers implementing groq.TTLCache, while the others use their default TTL.
+func (tc *TieredCache) SetWithTTL(ctx context.Context, key string, value *groq.ChatCompletionResponse, ttl time.Duration) error {
+	var errs []error
+	for i, tier := range tc.tiers {
+		var err error
+		if ttlCache, ok := tier.Cache.(groq.TTLCache); ok && ttl > 0 {
+			err = ttlCache.SetWithTTL(ctx, key, value, ttl)
+		} else {
+			err = tier.Cache.Set(ctx, key, value)
+		}
+		if err != nil {
+			tc.record(func() { tc.stats[i].Errors++ })
+			errs = append(errs, fmt.Errorf("%s: %w", tier.Name, err))
+			continue
+		}
+		tc.record(func() { tc.stats[i].Sets++ })
+	}
+	return errors.Join(errs...)
+}
+
+// Delete removes key from every tier.
+func (tc *TieredCache) Delete(ctx context.Context, key string) error {
+	tc.record(func() { delete(tc.hitCounts, key) })
+
+	var errs []error
+	for _, tier := range tc.tiers {
+		if err := tier.Cache.Delete(ctx, key); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", tier.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Clear removes all entries from every tier.
+func (tc *TieredCache) Clear(ctx context.Context) error {
+	tc.record(func() { tc.hitCounts = make(map[string]int) })
+
+	var errs []error
+	for _, tier := range tc.tiers {
+		if err := tier.Cache.Clear(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", tier.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Keys returns the union of the keys of all tiers that implement groq.CacheKeyLister.
+func (tc *TieredCache) Keys(ctx context.Context) ([]string, error) {
+	seen := make(map[string]bool)
+	var keys []string
+	var errs []error
+
+	for _, tier := range tc.tiers {
+		lister, ok := tier.Cache.(groq.CacheKeyLister)
+		if !ok {
+			continue
+		}
+		tierKeys, err := lister.Keys(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", tier.Name, err))
+			continue
+		}
+		for _, key := range tierKeys {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys, errors.Join(errs...)
+}
+
+// DeleteSimilar deletes similar entries from every tier that implements
+// groq.SimilarityInvalidator, then removes the deleted keys from the other tiers.
+func (tc *TieredCache) DeleteSimilar(ctx context.Context, query string, threshold float32) ([]string, error) {
+	seen := make(map[string]bool)
+	var deleted []string
+	var errs []error
+
+	for _, tier := range tc.tiers {
+		invalidator, ok := tier.Cache.(groq.SimilarityInvalidator)
+		if !ok {
+			continue
+		}
+		keys, err := invalidator.DeleteSimilar(ctx, query, threshold)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", tier.Name, err))
+		}
+		for _, key := range keys {
+			if !seen[key] {
+				seen[key] = true
+				deleted = append(deleted, key)
+			}
+		}
+	}
+
+	for _, key := range deleted {
+		if err := tc.Delete(ctx, key); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return deleted, errors.Join(errs...)
+}
+
+// GetStats returns the combined statistics: a hit is a hit in any tier, a miss a miss in
+// all of them. Size and ItemCount are summed over the tiers.
+func (tc *TieredCache) GetStats() groq.CacheStats {
+	tc.mu.Lock()
+	stats := groq.CacheStats{Hits: tc.hits, Misses: tc.misses}
+	tc.mu.Unlock()
+
+	for _, tier := range tc.tiers {
+		tierStats := tier.Cache.GetStats()
+		stats.Size += tierStats.Size
+		stats.ItemCount += tierStats.ItemCount
+	}
+	return stats
+}
+
+// TierStats returns the statistics of each tier, fastest first.
+func (tc *TieredCache) TierStats() []TierStats {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	return append([]TierStats(nil), tc.stats...)
+}
+
+// record runs fn under the stats lock.
+func (tc *TieredCache) record(fn func()) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	fn()
+}
+
+// shouldPromote counts a lower-tier hit and reports whether key is hot enough to promote.
+func (tc *TieredCache) shouldPromote(key string) bool {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if tc.promoteAfter <= 1 {
+		return true
+	}
+
+	if len(tc.hitCounts) >= maxTrackedKeys {
+		tc.hitCounts = make(map[string]int)
+	}
+	tc.hitCounts[key]++
+	if tc.hitCounts[key] < tc.promoteAfter {
+		return false
+	}
+	delete(tc.hitCounts, key)
+	return true
+}
+
+// lookup gets key from tier i. Slower tiers implementing groq.TTLReporter also report the
+// remaining TTL of the entry for its promotion; ttl is -1 if it is unknown.
+func (tc *TieredCache) lookup(ctx context.Context, i int, key string) (resp *groq.ChatCompletionResponse, ttl time.Duration, found bool) {
+	if reporter, ok := tc.tiers[i].Cache.(groq.TTLReporter); ok && i > 0 {
+		return reporter.GetWithTTL(ctx, key)
+	}
+	resp, found = tc.tiers[i].Cache.Get(ctx, key)
+	return resp, -1, found
+}
+
+// promote copies an entry found in tier `from` into all faster tiers. A positive ttl is
+// the remaining TTL of the entry, which the copies keep; tiers that cannot store an entry
+// with its own TTL are skipped. 0 means the entry does not expire and -1 that its TTL is
+// unknown, in which case the copies get the default TTL of the faster tiers.
+func (tc *TieredCache) promote(ctx context.Context, key string, resp *groq.ChatCompletionResponse, from int, ttl time.Duration) {
+	for i := 0; i < from; i++ {
+		var err error
+		if ttl > 0 {
+			ttlCache, ok := tc.tiers[i].Cache.(groq.TTLCache)
+			if !ok {
+				continue
+			}
+			err = ttlCache.SetWithTTL(ctx, key, resp, ttl)
+		} else {
+			err = tc.tiers[i].Cache.Set(ctx, key, resp)
+		}
+		if err != nil {
+			tc.record(func() { tc.stats[i].Errors++ })
+			continue
+		}
+		tc.record(func() { tc.stats[i].Promotions++ })
+	}
+}
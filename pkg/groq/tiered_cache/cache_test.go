@@ -0,0 +1,101 @@
+package tiered_cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/genc-murat/groq-client/pkg/groq"
+)
+
+func TestTieredCachePromotion(t *testing.T) {
+	ctx := context.Background()
+	l1, l2, l3 := groq.NewLRUCache(10, 0), groq.NewLRUCache(10, 0), groq.NewLRUCache(10, 0)
+	cache := New(Tier{Name: "l1", Cache: l1}, Tier{Name: "l2", Cache: l2}, Tier{Cache: l3}).PromoteAfter(2)
+
+	resp := &groq.ChatCompletionResponse{ID: "cached"}
+	_ = l3.Set(ctx, "q", resp)
+
+	if got, found := cache.Get(ctx, "q"); !found || got.ID != "cached" {
+		t.Fatalf("Get() = %v, %v", got, found)
+	}
+	if _, found := l1.Get(ctx, "q"); found {
+		t.Fatal("entry promoted before reaching the hit threshold")
+	}
+
+	cache.Get(ctx, "q")
+	if _, found := l1.Get(ctx, "q"); !found {
+		t.Fatal("hot entry was not promoted to L1")
+	}
+	if _, found := l2.Get(ctx, "q"); !found {
+		t.Fatal("hot entry was not promoted to L2")
+	}
+
+	cache.Get(ctx, "missing")
+
+	stats := cache.TierStats()
+	if stats[2].Name != "L3" || stats[2].Hits != 2 || stats[0].Promotions != 1 {
+		t.Errorf("unexpected tier stats: %+v", stats)
+	}
+	if total := cache.GetStats(); total.Hits != 2 || total.Misses != 1 {
+		t.Errorf("unexpected combined stats: %+v", total)
+	}
+}
+
+func TestTieredCacheWriteThrough(t *testing.T) {
+	ctx := context.Background()
+	l1, l2 := groq.NewLRUCache(0, 0), groq.NewLRUCache(0, 0)
+	cache := New(Tier{Cache: l1}, Tier{Cache: l2})
+
+	_ = cache.Set(ctx, "q", &groq.ChatCompletionResponse{ID: "a"})
+	if l1.GetStats().ItemCount != 1 || l2.GetStats().ItemCount != 1 {
+		t.Fatal("Set() must write to every tier")
+	}
+
+	_ = cache.Delete(ctx, "q")
+	if _, found := cache.Get(ctx, "q"); found {
+		t.Fatal("Delete() must remove the entry from every tier")
+	}
+}
+
+func TestTieredCacheSetWithTTL(t *testing.T) {
+	ctx := context.Background()
+	l1, l2 := groq.NewLRUCache(0, time.Hour), groq.NewLRUCache(0, time.Hour)
+	cache := New(Tier{Cache: l1}, Tier{Cache: l2})
+
+	_ = cache.SetWithTTL(ctx, "news", &groq.ChatCompletionResponse{}, 20*time.Millisecond)
+	_ = cache.Set(ctx, "facts", &groq.ChatCompletionResponse{})
+	time.Sleep(30 * time.Millisecond)
+
+	if _, found := cache.Get(ctx, "news"); found {
+		t.Error("the entry outlived its own TTL")
+	}
+	if _, found := cache.Get(ctx, "facts"); !found {
+		t.Error("the entry with the default TTL expired")
+	}
+}
+
+// plainCache hides the optional interfaces of the cache it wraps.
+type plainCache struct{ groq.Cache }
+
+func TestTieredCachePromotionKeepsTTL(t *testing.T) {
+	ctx := context.Background()
+	l1, l2, l3 := groq.NewLRUCache(0, time.Hour), groq.NewLRUCache(0, time.Hour), groq.NewLRUCache(0, time.Hour)
+	cache := New(Tier{Cache: l1}, Tier{Cache: plainCache{l2}}, Tier{Cache: l3})
+
+	_ = l3.SetWithTTL(ctx, "news", &groq.ChatCompletionResponse{ID: "news"}, 30*time.Millisecond)
+	if _, found := cache.Get(ctx, "news"); !found {
+		t.Fatal("expected a hit in L3")
+	}
+	if _, ttl, found := l1.GetWithTTL(ctx, "news"); !found || ttl > 30*time.Millisecond {
+		t.Fatalf("promoted entry has TTL %v, %v; want at most the 30ms left in L3", ttl, found)
+	}
+	if _, found := l2.Get(ctx, "news"); found {
+		t.Error("entry promoted into a tier that cannot keep its TTL")
+	}
+
+	time.Sleep(40 * time.Millisecond)
+	if _, found := cache.Get(ctx, "news"); found {
+		t.Error("the promoted entry outlived its source")
+	}
+}
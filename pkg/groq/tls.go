@@ -0,0 +1,129 @@
+package groq
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// WithTLSConfig sets the TLS configuration of the connections to the API, for custom CA
+// bundles, client certificates or a minimum TLS version in locked-down environments. The
+// configuration is cloned, so later changes to cfg have no effect.
+//
+// The option applies to the default client and to WithHTTPTransport with a nil
+// RoundTripper; custom RoundTrippers configure their own TLS.
+//
+// Example usage:
+//
+//	pool := x509.NewCertPool()
+//	pool.AppendCertsFromPEM(corporateCA)
+//	client := NewClient(apiKey, WithTLSConfig(&tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS13}))
+//
+// Parameters:
+//   - cfg: The TLS configuration; nil uses the defaults.
+//
+// Returns:
+//   - Option: A function that sets the TLS configuration.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *Client) {
+		c.tlsConfig = nil
+		if cfg != nil {
+			c.tlsConfig = cfg.Clone()
+		}
+	}
+}
+
+// WithCertificatePins only accepts servers whose verified certificate chain contains one
+// of the given public keys, on top of the usual certificate verification. Pins are the
+// base64 SHA-256 digests of a certificate's SubjectPublicKeyInfo, as computed by
+// CertificatePin or by
+//
+//	openssl x509 -pubkey -noout | openssl pkey -pubin -outform der | openssl dgst -sha256 -binary | base64
+//
+// with an optional "sha256/" prefix. Pin a backup key as well, or a key rotation breaks the
+// client. NewClientE reports malformed pins; they never match.
+//
+// Parameters:
+//   - pins: The accepted public keys; none disables pinning.
+//
+// Returns:
+//   - Option: A function that sets the pins.
+func WithCertificatePins(pins ...string) Option {
+	return func(c *Client) {
+		c.certPins = pins
+	}
+}
+
+// CertificatePin returns the pin of a certificate's public key for WithCertificatePins.
+func CertificatePin(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return "sha256/" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// parseCertificatePin decodes a pin given to WithCertificatePins.
+func parseCertificatePin(pin string) ([]byte, error) {
+	digest, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(pin, "sha256/"))
+	if err != nil || len(digest) != sha256.Size {
+		return nil, fmt.Errorf("certificate pin %q is not a base64 SHA-256 digest", pin)
+	}
+	return digest, nil
+}
+
+// tlsClientConfig returns the TLS configuration of the TLS options, or nil for the defaults.
+func (c *Client) tlsClientConfig() *tls.Config {
+	if c.tlsConfig == nil && len(c.certPins) == 0 {
+		return nil
+	}
+
+	cfg := &tls.Config{}
+	if c.tlsConfig != nil {
+		cfg = c.tlsConfig.Clone()
+	}
+	if len(c.certPins) > 0 {
+		var digests [][]byte
+		for _, pin := range c.certPins {
+			if digest, err := parseCertificatePin(pin); err == nil {
+				digests = append(digests, digest)
+			}
+		}
+		next := cfg.VerifyConnection
+		cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+			if err := verifyPins(cs, digests); err != nil {
+				return err
+			}
+			if next != nil {
+				return next(cs)
+			}
+			return nil
+		}
+	}
+	return cfg
+}
+
+// errCertificatePin is returned when no certificate of a server matches the pins.
+var errCertificatePin = errors.New("server certificate does not match any pinned public key")
+
+// verifyPins checks that a certificate of the verified chains, or of the presented chain
+// if verification is disabled, has one of the pinned public keys.
+func verifyPins(cs tls.ConnectionState, digests [][]byte) error {
+	chains := cs.VerifiedChains
+	if len(chains) == 0 {
+		chains = [][]*x509.Certificate{cs.PeerCertificates}
+	}
+	for _, chain := range chains {
+		for _, cert := range chain {
+			sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+			for _, digest := range digests {
+				if bytes.Equal(sum[:], digest) {
+					return nil
+				}
+			}
+		}
+	}
+	return errCertificatePin
+}
@@ -0,0 +1,66 @@
+package groq
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithTLSConfig(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","choices":[{"message":{"role":"assistant","content":"pong"},"finish_reason":"stop"}]}`))
+	}))
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0) // Rejected handshakes are expected
+	srv.StartTLS()
+	defer srv.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+	trusted := WithTLSConfig(&tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12})
+	pin := CertificatePin(srv.Certificate())
+	otherPin := "sha256/" + strings.Repeat("A", 43) + "="
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr string
+	}{
+		{"untrusted", nil, "certificate"},
+		{"custom CA", []Option{trusted}, ""},
+		{"custom CA over net/http", []Option{trusted, WithHTTPTransport(nil)}, ""},
+		{"matching pin", []Option{trusted, WithCertificatePins(otherPin, pin)}, ""},
+		{"wrong pin", []Option{trusted, WithCertificatePins(otherPin)}, errCertificatePin.Error()},
+		{"wrong pin over net/http", []Option{trusted, WithCertificatePins(otherPin), WithHTTPTransport(nil)}, errCertificatePin.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{WithBaseURL(srv.URL), WithRetryConfig(1, time.Millisecond)}, tt.opts...)
+			client := NewClient("test-key", opts...)
+			defer client.Close()
+
+			_, err := client.CreateChatCompletion(context.Background(), NewRequest(ModelLlama31_8bInstant).User("ping").Build())
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("CreateChatCompletion: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("expected an error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestWithCertificatePinsInvalid(t *testing.T) {
+	_, err := NewClientE("test-key", WithCertificatePins("not-a-pin"))
+	if !errors.Is(err, ErrInvalidConfig) || !strings.Contains(err.Error(), "not-a-pin") {
+		t.Errorf("expected a config error about the pin, got %v", err)
+	}
+}
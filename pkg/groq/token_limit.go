@@ -0,0 +1,100 @@
+package groq
+
+import (
+	"context"
+	"time"
+
+	"github.com/genc-murat/groq-client/internal/util"
+)
+
+// defaultCompletionReserve is the completion size assumed for requests without MaxTokens
+// until the response reports the actual usage.
+const defaultCompletionReserve = 1024
+
+// WithTokenRateLimit limits the tokens per minute sent to every model, in addition to the
+// requests per minute, since Groq enforces both. Each model has its own budget, as Groq's
+// limits are per model. A request reserves its estimated prompt tokens plus MaxTokens (or
+// 1024 if unset) before it is sent and waits while the budget does not cover it; the
+// reservation is corrected with the usage the API reports.
+//
+// Example usage:
+//
+//	client := NewClient(apiKey,
+//	    WithTokenRateLimit(6000),
+//	    WithModelTokenRateLimit(ModelLlama33_70bVersatile, 12000),
+//	)
+//
+// Parameters:
+//   - tokensPerMinute: The budget of each model; 0 disables token limiting.
+//
+// Returns:
+//   - Option: A function that sets the token budget.
+func WithTokenRateLimit(tokensPerMinute int) Option {
+	return func(c *Client) {
+		c.config.RateLimit.TokensPerMinute = tokensPerMinute
+	}
+}
+
+// WithModelTokenRateLimit sets the tokens per minute of one model, overriding
+// WithTokenRateLimit for it.
+//
+// Parameters:
+//   - model: The model.
+//   - tokensPerMinute: The budget of the model; 0 disables token limiting for it.
+//
+// Returns:
+//   - Option: A function that sets the model's token budget.
+func WithModelTokenRateLimit(model ModelType, tokensPerMinute int) Option {
+	return func(c *Client) {
+		if c.config.RateLimit.ModelTokensPerMinute == nil {
+			c.config.RateLimit.ModelTokensPerMinute = make(map[ModelType]int)
+		}
+		c.config.RateLimit.ModelTokensPerMinute[model] = tokensPerMinute
+	}
+}
+
+// tokenLimiter returns the token limiter of a model, or nil if the model's tokens are
+// not limited.
+func (c *Client) tokenLimiter(model ModelType) *util.TokenLimiter {
+	tpm := c.config.RateLimit.TokensPerMinute
+	if override, ok := c.config.RateLimit.ModelTokensPerMinute[model]; ok {
+		tpm = override
+	}
+	return c.limiter("tokens:"+string(model), tpm)
+}
+
+// estimateRequestTokens estimates the tokens a request may use: its prompt plus
+// MaxTokens, or defaultCompletionReserve if MaxTokens is not set.
+func estimateRequestTokens(req *ChatCompletionRequest) int {
+	completion := req.MaxTokens
+	if completion <= 0 {
+		completion = defaultCompletionReserve
+	}
+	return EstimateMessageTokens(req.Messages) + completion
+}
+
+// reserveTokens waits until the token budget of the request's model covers its estimated
+// size. The returned function settles the reservation with the usage the API reported;
+// nil usage, as for failed requests, releases the whole reservation.
+func (c *Client) reserveTokens(ctx context.Context, req *ChatCompletionRequest) (func(*Usage), error) {
+	limiter := c.tokenLimiter(req.Model)
+	if limiter == nil {
+		return func(*Usage) {}, nil
+	}
+
+	estimate := estimateRequestTokens(req)
+	started := time.Now()
+	err := limiter.WaitN(ctx, estimate)
+	traceFrom(ctx).addQueued(time.Since(started))
+	if err != nil {
+		return nil, err
+	}
+	return func(usage *Usage) {
+		switch {
+		case usage == nil:
+			limiter.Adjust(-estimate)
+		case usage.TotalTokens > 0:
+			limiter.Adjust(usage.TotalTokens - estimate)
+		}
+	}, nil
+}
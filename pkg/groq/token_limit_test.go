@@ -0,0 +1,53 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithTokenRateLimit(t *testing.T) {
+	var totalTokens string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"}}],"usage":{"total_tokens":` + totalTokens + `}}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key", WithBaseURL(srv.URL),
+		WithTokenRateLimit(600),
+		WithModelTokenRateLimit(ModelLlama33_70bVersatile, 0),
+	)
+	req := NewRequest(ModelLlama31_8bInstant).User("hi").MaxTokens(290).Build()
+
+	// The reported usage replaces the estimate, so three requests fit in the budget of two.
+	totalTokens = "20"
+	for i := 0; i < 3; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		_, err := client.CreateChatCompletion(ctx, req)
+		cancel()
+		if err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+	}
+
+	// Using the whole budget makes the next request wait for it to refill.
+	totalTokens = "600"
+	if _, err := client.CreateChatCompletion(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.CreateChatCompletion(ctx, req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the token budget to block, got %v", err)
+	}
+
+	// Models with a zero override are not limited.
+	other := NewRequest(ModelLlama33_70bVersatile).User("hi").MaxTokens(290).Build()
+	if _, err := client.CreateChatCompletion(context.Background(), other); err != nil {
+		t.Errorf("unlimited model: %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package groq
+
+import (
+	"unicode/utf8"
+)
+
+// messageTokenOverhead approximates the tokens the chat template adds per message
+// (role markers and separators).
+const messageTokenOverhead = 4
+
+// EstimateTokens returns a rough token count for text using the common heuristic of
+// about four characters per token. It is intended for budgeting and throttling,
+// not for exact billing.
+//
+// Parameters:
+//   - text: The text to estimate.
+//
+// Returns:
+//   - int: The estimated number of tokens.
+func EstimateTokens(text string) int {
+	return estimateRuneTokens(utf8.RuneCountInString(text))
+}
+
+// estimateRuneTokens converts a rune count into an estimated token count.
+func estimateRuneTokens(runes int) int {
+	if runes <= 0 {
+		return 0
+	}
+	return (runes + 3) / 4
+}
+
+// EstimateMessageTokens returns the estimated prompt token count for a list of messages,
+// including a small per-message overhead for the chat template.
+//
+// Parameters:
+//   - messages: The chat messages to estimate.
+//
+// Returns:
+//   - int: The estimated number of prompt tokens.
+func EstimateMessageTokens(messages []ChatMessage) int {
+	total := 0
+	for _, msg := range messages {
+		total += messageTokenOverhead + EstimateTokens(msg.GetCacheKey())
+	}
+	return total
+}
+
+// EstimateChatCost returns the cost in USD of a chat completion with the given usage,
+// based on the published per-token prices of the model. Models without pricing
+// information cost 0.
+//
+// Parameters:
+//   - model: The model that served the request.
+//   - usage: The token usage reported by the API.
+//
+// Returns:
+//   - float64: The estimated cost in USD.
+func EstimateChatCost(model ModelType, usage Usage) float64 {
+	info := model.GetInfo()
+	return (float64(usage.PromptTokens)*info.InputPricePerMillion +
+		float64(usage.CompletionTokens)*info.OutputPricePerMillion) / 1e6
+}
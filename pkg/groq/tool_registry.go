@@ -0,0 +1,251 @@
+package groq
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrInvalidTool  = errors.New("invalid tool definition")
+	ErrToolNotFound = errors.New("tool not found")
+)
+
+// toolNamePattern is the set of function names the API accepts.
+var toolNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// toolPropertyTypes are the JSON Schema types a tool parameter may have.
+var toolPropertyTypes = map[string]bool{
+	"string": true, "number": true, "integer": true, "boolean": true, "array": true, "object": true,
+}
+
+type ToolCatalog struct {
+	Version string     `json:"version,omitempty" yaml:"version,omitempty"`
+	Tools   []Function `json:"tools" yaml:"tools"`
+}
+
+type ToolRegistry struct {
+	tools map[string]Function
+	mu    sync.RWMutex
+}
+
+// Validate checks a function definition before it is registered or sent: the name must
+// be accepted by the API, a description must be present, parameters must form a JSON
+// Schema object and every required parameter must be defined. All problems are reported
+// at once.
+//
+// Returns:
+//   - error: An error matching ErrInvalidTool, or nil if the definition is valid.
+func (f Function) Validate() error {
+	var problems []string
+
+	if !toolNamePattern.MatchString(f.Name) {
+		problems = append(problems, fmt.Sprintf("name %q must be 1-64 letters, digits, '_' or '-'", f.Name))
+	}
+	if strings.TrimSpace(f.Description) == "" {
+		problems = append(problems, "description is empty; the model relies on it to choose the tool")
+	}
+	if f.Parameters.Type != "object" {
+		problems = append(problems, fmt.Sprintf("parameters.type is %q, want \"object\"", f.Parameters.Type))
+	}
+
+	names := make([]string, 0, len(f.Parameters.Properties))
+	for name := range f.Parameters.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		prop := f.Parameters.Properties[name]
+		if !toolPropertyTypes[prop.Type] {
+			problems = append(problems, fmt.Sprintf("parameter %q has unsupported type %q", name, prop.Type))
+		}
+		if len(prop.Enum) > 0 && prop.Type != "string" {
+			problems = append(problems, fmt.Sprintf("parameter %q has an enum but type %q; enums must be strings", name, prop.Type))
+		}
+	}
+	for _, name := range f.Parameters.Required {
+		if _, ok := f.Parameters.Properties[name]; !ok {
+			problems = append(problems, fmt.Sprintf("required parameter %q is not defined", name))
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w %q: %s", ErrInvalidTool, f.Name, strings.Join(problems, "; "))
+}
+
+// NewToolRegistry creates a registry of tool definitions, optionally seeded with functions
+// such as WeatherFunction.
+//
+// Parameters:
+//   - functions: Definitions to register.
+//
+// Returns:
+//   - *ToolRegistry: The registry.
+//   - error: An error if a definition is invalid or a name is registered twice.
+func NewToolRegistry(functions ...Function) (*ToolRegistry, error) {
+	r := &ToolRegistry{tools: make(map[string]Function)}
+	for _, f := range functions {
+		if err := r.Register(f); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// LoadToolRegistry reads a tool catalog from a JSON or YAML file, chosen by the file
+// extension (.json, .yaml or .yml), and validates every definition. This lets several
+// services and the CLI share one reviewed catalog of tool schemas.
+//
+// Example catalog:
+//
+//	version: "1"
+//	tools:
+//	  - name: get_weather
+//	    description: Get the current weather for a location
+//	    parameters:
+//	      type: object
+//	      properties:
+//	        location: {type: string, description: City name or coordinates}
+//	      required: [location]
+//
+// Parameters:
+//   - path: The catalog file.
+//
+// Returns:
+//   - *ToolRegistry: The registry with all tools of the catalog.
+//   - error: An error if the file cannot be read or parsed, or a definition is invalid.
+func LoadToolRegistry(path string) (*ToolRegistry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tool catalog: %w", err)
+	}
+
+	var catalog ToolCatalog
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&catalog)
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(&catalog)
+	default:
+		return nil, fmt.Errorf("unsupported tool catalog format %q: use .json, .yaml or .yml", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse tool catalog %s: %w", path, err)
+	}
+
+	return NewToolRegistry(catalog.Tools...)
+}
+
+// Register adds a validated tool definition.
+//
+// Returns:
+//   - error: An error matching ErrInvalidTool if the definition is invalid or the name is taken.
+func (r *ToolRegistry) Register(f Function) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.tools[f.Name]; exists {
+		return fmt.Errorf("%w %q: registered twice", ErrInvalidTool, f.Name)
+	}
+	r.tools[f.Name] = f
+	return nil
+}
+
+// Get returns the definition of a tool.
+func (r *ToolRegistry) Get(name string) (Function, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	f, ok := r.tools[name]
+	return f, ok
+}
+
+// Names returns the names of all registered tools in alphabetical order.
+func (r *ToolRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.tools))
+	for name := range r.tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Functions returns the named definitions, ready for FunctionCallChatRequest.Functions.
+// Without names, all tools are returned in alphabetical order.
+//
+// Returns:
+//   - []Function: The definitions in the order of names.
+//   - error: An error matching ErrToolNotFound if a name is not registered.
+func (r *ToolRegistry) Functions(names ...string) ([]Function, error) {
+	if len(names) == 0 {
+		names = r.Names()
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	functions := make([]Function, 0, len(names))
+	for _, name := range names {
+		f, ok := r.tools[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrToolNotFound, name)
+		}
+		functions = append(functions, f)
+	}
+	return functions, nil
+}
+
+// Save writes all tools to a JSON or YAML catalog file, chosen by the file extension.
+// Tools are written in alphabetical order so the file diffs cleanly in review.
+//
+// Parameters:
+//   - path: The catalog file.
+//
+// Returns:
+//   - error: An error if the format is unsupported or the file cannot be written.
+func (r *ToolRegistry) Save(path string) error {
+	functions, _ := r.Functions()
+	catalog := ToolCatalog{Tools: functions}
+
+	var (
+		data []byte
+		err  error
+	)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		data, err = json.MarshalIndent(catalog, "", "  ")
+		data = append(data, '\n')
+	case ".yaml", ".yml":
+		data, err = yaml.Marshal(catalog)
+	default:
+		return fmt.Errorf("unsupported tool catalog format %q: use .json, .yaml or .yml", filepath.Ext(path))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode tool catalog: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write tool catalog: %w", err)
+	}
+	return nil
+}
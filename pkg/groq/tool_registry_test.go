@@ -0,0 +1,78 @@
+package groq
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestToolRegistryRoundTrip(t *testing.T) {
+	registry, err := NewToolRegistry(WeatherFunction, CalendarFunction)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	for _, name := range []string{"tools.json", "tools.yaml"} {
+		path := filepath.Join(dir, name)
+		if err := registry.Save(path); err != nil {
+			t.Fatalf("Save(%s) error = %v", name, err)
+		}
+
+		loaded, err := LoadToolRegistry(path)
+		if err != nil {
+			t.Fatalf("LoadToolRegistry(%s) error = %v", name, err)
+		}
+		got, err := loaded.Functions("get_weather", "schedule_meeting")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, []Function{WeatherFunction, CalendarFunction}) {
+			t.Errorf("%s round trip changed the definitions: %+v", name, got)
+		}
+	}
+
+	if _, err := registry.Functions("missing"); !errors.Is(err, ErrToolNotFound) {
+		t.Errorf("Functions(missing) error = %v", err)
+	}
+	if err := registry.Register(WeatherFunction); !errors.Is(err, ErrInvalidTool) {
+		t.Errorf("duplicate Register() error = %v", err)
+	}
+}
+
+func TestLoadToolRegistryValidates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tools.yml")
+	catalog := `
+tools:
+  - name: "bad name"
+    description: ""
+    parameters:
+      type: object
+      properties:
+        count: {type: integer, description: How many, enum: ["1"]}
+      required: [limit]
+`
+	if err := os.WriteFile(path, []byte(catalog), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := LoadToolRegistry(path)
+	if !errors.Is(err, ErrInvalidTool) {
+		t.Fatalf("LoadToolRegistry() error = %v", err)
+	}
+	for _, want := range []string{"name", "description is empty", "enum", `"limit" is not defined`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte("tools:\n  - nmae: typo\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadToolRegistry(path); err == nil || !strings.Contains(err.Error(), "nmae") {
+		t.Errorf("unknown field was accepted: %v", err)
+	}
+}
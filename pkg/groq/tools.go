@@ -0,0 +1,33 @@
+package groq
+
+// Tool is a function the model may call, sent in ChatCompletionRequest.Tools. The API
+// only accepts functions as tools: a request carrying bare function definitions, as
+// CreateFunctionCall used to send, reaches the model without them.
+type Tool struct {
+	Type     string   `json:"type"` // Always "function"
+	Function Function `json:"function"`
+}
+
+// ToolCall is a call the model decided to make, returned in ChatMessage.ToolCalls. The
+// result goes back in a message with role "tool" whose ToolCallID is the ID of the call.
+type ToolCall struct {
+	ID       string       `json:"id"`
+	Type     string       `json:"type"`
+	Function FunctionCall `json:"function"`
+}
+
+// NewFunctionTool wraps a function definition as a tool for ChatCompletionRequest.Tools.
+func NewFunctionTool(f Function) Tool {
+	return Tool{Type: "function", Function: f}
+}
+
+// withFunctionTools returns a copy of req that also offers functions as tools, leaving
+// req unmodified.
+func withFunctionTools(req *ChatCompletionRequest, functions []Function) *ChatCompletionRequest {
+	chatReq := *req
+	chatReq.Tools = append([]Tool(nil), req.Tools...)
+	for _, f := range functions {
+		chatReq.Tools = append(chatReq.Tools, NewFunctionTool(f))
+	}
+	return &chatReq
+}
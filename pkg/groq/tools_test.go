@@ -0,0 +1,50 @@
+package groq
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreateFunctionCall(t *testing.T) {
+	var sent ChatCompletionRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"chatcmpl-test","choices":[{"index":0,"finish_reason":"tool_calls",
+			"message":{"role":"assistant","content":null,"tool_calls":[{"id":"call_1","type":"function",
+			"function":{"name":"get_weather","arguments":"{\"location\":\"Istanbul\"}"}}]}}]}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key", WithBaseURL(srv.URL))
+	req := &FunctionCallChatRequest{
+		ChatCompletionRequest: NewRequest(ModelLlama31_8bInstant).User("weather in Istanbul?").Build(),
+		Functions:             []Function{WeatherFunction},
+	}
+	resp, err := client.CreateFunctionCall(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateFunctionCall() error = %v", err)
+	}
+
+	if len(sent.Tools) != 1 || sent.Tools[0].Type != "function" || sent.Tools[0].Function.Name != "get_weather" {
+		t.Errorf("expected the function to be sent as a tool, got %+v", sent.Tools)
+	}
+	if len(req.Tools) != 0 {
+		t.Error("expected the caller's request to be left unmodified")
+	}
+
+	calls := resp.Choices[0].Message.ToolCalls
+	if len(calls) != 1 || calls[0].ID != "call_1" || calls[0].Function.Name != "get_weather" {
+		t.Fatalf("unexpected tool calls: %+v", calls)
+	}
+	var args WeatherArgs
+	if err := calls[0].Function.ParseArguments(&args); err != nil || args.Location != "Istanbul" {
+		t.Errorf("ParseArguments() = %+v, %v", args, err)
+	}
+}
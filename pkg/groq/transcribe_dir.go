@@ -0,0 +1,494 @@
+package groq
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+type TranscriptFormat string
+
+const (
+	TranscriptFormatText TranscriptFormat = "txt"
+	TranscriptFormatSRT  TranscriptFormat = "srt"
+	TranscriptFormatVTT  TranscriptFormat = "vtt"
+	TranscriptFormatJSON TranscriptFormat = "json"
+)
+
+type TranscribeErrorPolicy int
+
+const (
+	// TranscribeContinue transcribes every file and reports failures per file.
+	TranscribeContinue TranscribeErrorPolicy = iota
+	// TranscribeStopOnError stops scheduling new files once MaxFailures files failed;
+	// files already being transcribed are finished.
+	TranscribeStopOnError
+	// TranscribeCancelOnError stops like TranscribeStopOnError and also cancels the files
+	// still being transcribed.
+	TranscribeCancelOnError
+)
+
+var ErrTranscriptionStopped = errors.New("batch transcription stopped")
+
+type TranscribeDirectoryOptions struct {
+	Model       ModelType          // Transcription model, defaults to ModelWhisperLargeV3
+	Language    string             // Optional language hint passed to every request
+	Prompt      string             // Optional prompt passed to every request
+	Formats     []TranscriptFormat // Output files written next to each source, defaults to txt
+	Concurrency int                // Maximum number of files transcribed at once, defaults to 4
+	MaxRetries  int                // Additional attempts per file after a failure
+	RetryDelay  time.Duration      // Base delay between per-file attempts, defaults to 1 second
+	Recursive   bool               // Descend into subdirectories
+	Overwrite   bool               // Re-transcribe files whose outputs already exist
+	DryRun      bool               // Only probe durations and estimate cost, nothing is uploaded
+
+	OnError     TranscribeErrorPolicy // What happens after a file failed, defaults to TranscribeContinue
+	MaxFailures int                   // Failures tolerated by the stopping policies, defaults to 1
+}
+
+type TranscribeFileResult struct {
+	Path          string
+	Outputs       []string
+	Transcript    *TranscriptionResponse // The parsed verbose_json transcript, nil if the file was not transcribed
+	Attempts      int
+	Skipped       bool
+	AudioDuration time.Duration // Probed audio length, zero if the format could not be probed
+	EstimatedCost float64       // Estimated USD cost based on AudioDuration
+	Duration      time.Duration
+	Err           error
+}
+
+type TranscribeDirectoryReport struct {
+	Directory          string
+	Results            []TranscribeFileResult
+	Succeeded          int
+	Failed             int
+	Skipped            int
+	NotRun             int // Files never started because the error policy stopped the run
+	TotalAudioDuration time.Duration
+	EstimatedCost      float64
+	DryRun             bool
+	Duration           time.Duration
+}
+
+// TranscribeDirectory walks a directory, transcribes every file with a supported audio
+// extension and writes the transcripts next to the source files (e.g. talk.mp3 -> talk.mp3.txt).
+// Files are processed with bounded concurrency and every file is retried independently,
+// so a single failing file does not abort the whole run unless opts.OnError says so.
+// All files share the client's rate limiter.
+//
+// Parameters:
+//   - ctx: Context for the run; cancelling it stops scheduling new files.
+//   - dir: The directory to scan.
+//   - opts: Optional settings, nil uses the defaults.
+//
+// Returns:
+//   - *TranscribeDirectoryReport: Per-file results and aggregate counts.
+//   - error: Non-nil if the directory cannot be scanned, ctx is cancelled, or the error
+//     policy stopped the run (matching ErrTranscriptionStopped).
+func (c *Client) TranscribeDirectory(ctx context.Context, dir string, opts *TranscribeDirectoryOptions) (*TranscribeDirectoryReport, error) {
+	opts = normalizeTranscribeOptions(opts)
+
+	files, err := collectAudioFiles(dir, opts.Recursive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan directory: %w", err)
+	}
+
+	return c.transcribeAll(ctx, dir, files, opts, func(ctx context.Context, path string) TranscribeFileResult {
+		return c.transcribeFile(ctx, path, opts, openOSFile, true)
+	})
+}
+
+// TranscribeFS transcribes every file with a supported audio extension in fsys, for
+// example an embed.FS, a zip archive or os.DirFS. Nothing is written: the transcripts are
+// returned in each result's Transcript, and Formats and Overwrite are ignored. Files that
+// can seek, like those of os.DirFS and embed.FS, are streamed; others are read into
+// memory. Otherwise it behaves like TranscribeDirectory.
+//
+// Parameters:
+//   - ctx: Context for the run; cancelling it stops scheduling new files.
+//   - fsys: The file system to read.
+//   - root: The directory within fsys to scan, "." for all of it.
+//   - opts: Optional settings, nil uses the defaults.
+//
+// Returns:
+//   - *TranscribeDirectoryReport: Per-file results with paths relative to fsys.
+//   - error: Non-nil if root cannot be scanned, ctx is cancelled, or the error policy
+//     stopped the run.
+func (c *Client) TranscribeFS(ctx context.Context, fsys fs.FS, root string, opts *TranscribeDirectoryOptions) (*TranscribeDirectoryReport, error) {
+	opts = normalizeTranscribeOptions(opts)
+
+	var files []string
+	err := fs.WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && !opts.Recursive {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if isValidAudioFormat(strings.ToLower(filepath.Ext(path))) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan file system: %w", err)
+	}
+
+	open := func(path string) (io.ReadSeekCloser, error) {
+		return openFSFile(fsys, path)
+	}
+	return c.transcribeAll(ctx, root, files, opts, func(ctx context.Context, path string) TranscribeFileResult {
+		return c.transcribeFile(ctx, path, opts, open, false)
+	})
+}
+
+// transcribeAll runs transcribe for every file with bounded concurrency, applies the
+// error policy and aggregates the results.
+func (c *Client) transcribeAll(ctx context.Context, source string, files []string, opts *TranscribeDirectoryOptions, transcribe func(ctx context.Context, path string) TranscribeFileResult) (*TranscribeDirectoryReport, error) {
+	start := time.Now()
+	report := &TranscribeDirectoryReport{
+		Directory: source,
+		Results:   make([]TranscribeFileResult, len(files)),
+		DryRun:    opts.DryRun,
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		failures int
+		stopped  error
+	)
+	sem := make(chan struct{}, opts.Concurrency)
+
+	// fail records a failed file and reports whether the run must stop.
+	fail := func(path string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		failures++
+		if opts.OnError == TranscribeContinue || failures < opts.MaxFailures || stopped != nil {
+			return
+		}
+		stopped = fmt.Errorf("%w after %d failed files, last %s: %v", ErrTranscriptionStopped, failures, path, err)
+		if opts.OnError == TranscribeCancelOnError {
+			cancel()
+		}
+	}
+	isStopped := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return stopped != nil
+	}
+
+	scheduled := 0
+	for i, path := range files {
+		if isStopped() {
+			break
+		}
+		select {
+		case <-ctx.Done():
+		case sem <- struct{}{}:
+		}
+		if ctx.Err() != nil || isStopped() {
+			break
+		}
+
+		scheduled++
+		wg.Add(1)
+		go func(index int, path string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result := transcribe(runCtx, path)
+			report.Results[index] = result
+			if result.Err != nil {
+				fail(path, result.Err)
+			}
+		}(i, path)
+	}
+
+	wg.Wait()
+
+	notRun := ErrTranscriptionStopped
+	if ctx.Err() != nil {
+		notRun = ctx.Err()
+	}
+	for i := scheduled; i < len(files); i++ {
+		report.Results[i] = TranscribeFileResult{Path: files[i], Err: notRun}
+	}
+
+	for i, result := range report.Results {
+		report.TotalAudioDuration += result.AudioDuration
+		report.EstimatedCost += result.EstimatedCost
+
+		switch {
+		case i >= scheduled:
+			report.NotRun++
+		case result.Skipped:
+			report.Skipped++
+		case result.Err != nil:
+			report.Failed++
+		default:
+			report.Succeeded++
+		}
+	}
+	report.Duration = time.Since(start)
+
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+	return report, stopped
+}
+
+// normalizeTranscribeOptions returns a copy of opts with defaults applied.
+func normalizeTranscribeOptions(opts *TranscribeDirectoryOptions) *TranscribeDirectoryOptions {
+	normalized := TranscribeDirectoryOptions{}
+	if opts != nil {
+		normalized = *opts
+	}
+
+	if normalized.Model == "" {
+		normalized.Model = ModelWhisperLargeV3
+	}
+	if len(normalized.Formats) == 0 {
+		normalized.Formats = []TranscriptFormat{TranscriptFormatText}
+	}
+	if normalized.Concurrency <= 0 {
+		normalized.Concurrency = 4
+	}
+	if normalized.MaxRetries < 0 {
+		normalized.MaxRetries = 0
+	}
+	if normalized.RetryDelay <= 0 {
+		normalized.RetryDelay = time.Second
+	}
+	if normalized.MaxFailures <= 0 {
+		normalized.MaxFailures = 1
+	}
+
+	return &normalized
+}
+
+// collectAudioFiles returns the paths of all files under dir with a supported audio extension.
+func collectAudioFiles(dir string, recursive bool) ([]string, error) {
+	var files []string
+
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if isValidAudioFormat(strings.ToLower(filepath.Ext(path))) {
+			files = append(files, path)
+		}
+		return nil
+	})
+
+	return files, err
+}
+
+// transcribeFile transcribes a single file with per-file retries and, if write is set,
+// writes the requested outputs next to it.
+func (c *Client) transcribeFile(ctx context.Context, path string, opts *TranscribeDirectoryOptions, open func(string) (io.ReadSeekCloser, error), write bool) TranscribeFileResult {
+	start := time.Now()
+	result := TranscribeFileResult{Path: path}
+
+	var outputs []string
+	if write {
+		outputs = make([]string, len(opts.Formats))
+		for i, format := range opts.Formats {
+			outputs[i] = transcriptPath(path, format)
+		}
+
+		if !opts.Overwrite && allExist(outputs) {
+			result.Skipped = true
+			result.Outputs = outputs
+			return result
+		}
+	}
+
+	duration, err := probeFileDuration(path, open)
+	if err == nil {
+		result.AudioDuration = duration
+		result.EstimatedCost, _ = EstimateTranscriptionCost(opts.Model, duration)
+	}
+	if opts.DryRun {
+		result.Err = err
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	var body []byte
+	for attempt := 0; attempt <= opts.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				result.Err = ctx.Err()
+				result.Duration = time.Since(start)
+				return result
+			case <-time.After(opts.RetryDelay * time.Duration(attempt)):
+			}
+		}
+
+		result.Attempts++
+		body, err = c.transcribeFileOnce(ctx, path, opts, open)
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		result.Err = err
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	var transcript TranscriptionResponse
+	if err := json.Unmarshal(body, &transcript); err != nil {
+		result.Err = fmt.Errorf("%w: %v", ErrJSONDecoding, err)
+		result.Duration = time.Since(start)
+		return result
+	}
+	result.Transcript = &transcript
+
+	for i := range outputs {
+		format := opts.Formats[i]
+		if err := writeTranscript(outputs[i], format, body, &transcript); err != nil {
+			result.Err = err
+			break
+		}
+		result.Outputs = append(result.Outputs, outputs[i])
+	}
+	result.Duration = time.Since(start)
+
+	return result
+}
+
+// transcribeFileOnce opens the file and performs a single verbose_json transcription request.
+func (c *Client) transcribeFileOnce(ctx context.Context, path string, opts *TranscribeDirectoryOptions, open func(string) (io.ReadSeekCloser, error)) ([]byte, error) {
+	file, err := open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return c.createTranscriptionRaw(ctx, &TranscriptionRequest{
+		File:           file,
+		FileName:       filepath.Base(path),
+		Model:          opts.Model,
+		Language:       opts.Language,
+		Prompt:         opts.Prompt,
+		ResponseFormat: "verbose_json",
+	})
+}
+
+// probeFileDuration opens path and probes its audio duration.
+func probeFileDuration(path string, open func(string) (io.ReadSeekCloser, error)) (time.Duration, error) {
+	file, err := open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	return ProbeAudioDuration(file, path)
+}
+
+// openOSFile opens a file of the local file system.
+func openOSFile(path string) (io.ReadSeekCloser, error) {
+	return os.Open(path)
+}
+
+// openFSFile opens a file of fsys. Files that can seek, like those of os.DirFS and
+// embed.FS, are streamed; others, e.g. compressed zip entries, are read into memory.
+func openFSFile(fsys fs.FS, path string) (io.ReadSeekCloser, error) {
+	file, err := fsys.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if seeker, ok := file.(io.ReadSeeker); ok {
+		return readSeekCloser{seeker, file}, nil
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	return nopSeekCloser{bytes.NewReader(data)}, nil
+}
+
+// readSeekCloser combines a seekable file with the Close of the file it came from.
+type readSeekCloser struct {
+	io.ReadSeeker
+	io.Closer
+}
+
+// nopSeekCloser adds a no-op Close to an in-memory file.
+type nopSeekCloser struct {
+	io.ReadSeeker
+}
+
+// Close does nothing.
+func (nopSeekCloser) Close() error { return nil }
+
+// transcriptPath returns the output path for a source file and format. The source
+// extension is kept, so talk.mp3 and talk.wav in one directory get separate transcripts.
+func transcriptPath(path string, format TranscriptFormat) string {
+	return path + "." + string(format)
+}
+
+// allExist reports whether every path in paths exists.
+func allExist(paths []string) bool {
+	for _, p := range paths {
+		if _, err := os.Stat(p); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// writeTranscript writes a transcript file in the given format.
+func writeTranscript(path string, format TranscriptFormat, raw []byte, transcript *TranscriptionResponse) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer file.Close()
+
+	switch format {
+	case TranscriptFormatText:
+		_, err = io.WriteString(file, strings.TrimSpace(transcript.Text)+"\n")
+	case TranscriptFormatJSON:
+		_, err = file.Write(raw)
+	case TranscriptFormatSRT:
+		err = transcript.WriteSRT(file)
+	case TranscriptFormatVTT:
+		err = transcript.WriteVTT(file)
+	default:
+		err = fmt.Errorf("unsupported transcript format: %s", format)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestTranscribeDirectory(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"text":" hello world ","segments":[{"start":0,"end":1.2,"text":" hello world"}]}`))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	for _, name := range []string{"a.mp3", "b.wav", "notes.md"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("data"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	client := NewClient("test-key", WithBaseURL(srv.URL))
+	report, err := client.TranscribeDirectory(context.Background(), dir, &TranscribeDirectoryOptions{
+		Formats: []TranscriptFormat{TranscriptFormatText, TranscriptFormatSRT},
+	})
+	if err != nil {
+		t.Fatalf("TranscribeDirectory() error = %v", err)
+	}
+	if report.Succeeded != 2 || report.Failed != 0 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	txt, err := os.ReadFile(filepath.Join(dir, "a.mp3.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(txt) != "hello world\n" {
+		t.Errorf("unexpected txt output: %q", txt)
+	}
+
+	srt, err := os.ReadFile(filepath.Join(dir, "b.wav.srt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(srt), "00:00:00,000 --> 00:00:01,200") {
+		t.Errorf("unexpected srt output: %q", srt)
+	}
+
+	report, err = client.TranscribeDirectory(context.Background(), dir, &TranscribeDirectoryOptions{
+		Formats: []TranscriptFormat{TranscriptFormatText},
+	})
+	if err != nil {
+		t.Fatalf("TranscribeDirectory() error = %v", err)
+	}
+	if report.Skipped != 2 {
+		t.Errorf("expected existing outputs to be skipped, got %+v", report)
+	}
+}
+
+func TestTranscribeDirectorySameName(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, header, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"text":"from ` + header.Filename + `"}`))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	for _, name := range []string{"a.mp3", "a.wav"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("data"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	client := NewClient("test-key", WithBaseURL(srv.URL))
+	report, err := client.TranscribeDirectory(context.Background(), dir, nil)
+	if err != nil {
+		t.Fatalf("TranscribeDirectory() error = %v", err)
+	}
+	if report.Succeeded != 2 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	for _, name := range []string{"a.mp3", "a.wav"} {
+		txt, err := os.ReadFile(filepath.Join(dir, name+".txt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(txt) != "from "+name+"\n" {
+			t.Errorf("transcript of %s = %q", name, txt)
+		}
+	}
+}
+
+func TestTranscribeFS(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		_, header, _ := r.FormFile("file")
+		if strings.HasPrefix(header.Filename, "bad") {
+			http.Error(w, `{"error":{"message":"corrupt"}}`, http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"text":"from ` + header.Filename + `"}`))
+	}))
+	defer srv.Close()
+
+	fsys := fstest.MapFS{
+		"audio/a.mp3":     {Data: []byte("data")},
+		"audio/bad.mp3":   {Data: []byte("data")},
+		"audio/sub/c.wav": {Data: []byte("data")},
+		"audio/notes.md":  {Data: []byte("text")},
+	}
+	client := NewClient("test-key", WithBaseURL(srv.URL), WithRetryConfig(1, time.Millisecond))
+
+	report, err := client.TranscribeFS(context.Background(), fsys, "audio", &TranscribeDirectoryOptions{Recursive: true})
+	if err != nil {
+		t.Fatalf("TranscribeFS() error = %v", err)
+	}
+	if report.Succeeded != 2 || report.Failed != 1 || len(report.Results) != 3 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	for _, result := range report.Results {
+		if result.Err == nil && result.Transcript.Text != "from "+path.Base(result.Path) {
+			t.Errorf("unexpected transcript for %s: %+v", result.Path, result.Transcript)
+		}
+		if len(result.Outputs) != 0 {
+			t.Errorf("TranscribeFS wrote outputs: %v", result.Outputs)
+		}
+	}
+
+	report, err = client.TranscribeFS(context.Background(), fsys, "audio", &TranscribeDirectoryOptions{
+		Recursive:   true,
+		Concurrency: 1,
+		OnError:     TranscribeStopOnError,
+	})
+	if !errors.Is(err, ErrTranscriptionStopped) {
+		t.Fatalf("expected ErrTranscriptionStopped, got %v", err)
+	}
+	if report.Succeeded != 1 || report.Failed != 1 || report.NotRun != 1 {
+		t.Errorf("unexpected report after stop: %+v", report)
+	}
+}
+
+// streamFS hides the Seek of the files of a file system, like a compressed archive.
+type streamFS struct{ fstest.MapFS }
+
+func (f streamFS) Open(name string) (fs.File, error) {
+	file, err := f.MapFS.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return struct{ fs.File }{file}, nil
+}
+
+func TestOpenFSFile(t *testing.T) {
+	fsys := fstest.MapFS{"a.wav": {Data: []byte("data")}}
+
+	file, err := openFSFile(fsys, "a.wav")
+	if err != nil {
+		t.Fatalf("openFSFile() error = %v", err)
+	}
+	if _, ok := file.(readSeekCloser); !ok {
+		t.Errorf("seekable file was buffered: %T", file)
+	}
+	_ = file.Close()
+
+	file, err = openFSFile(streamFS{fsys}, "a.wav")
+	if err != nil {
+		t.Fatalf("openFSFile() error = %v", err)
+	}
+	defer file.Close()
+	if _, err := file.Seek(2, io.SeekStart); err != nil {
+		t.Fatalf("Seek() on a buffered file error = %v", err)
+	}
+	if rest, _ := io.ReadAll(file); string(rest) != "ta" {
+		t.Errorf("read %q after seeking, want %q", rest, "ta")
+	}
+}
@@ -0,0 +1,62 @@
+package groq
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/genc-murat/groq-client/internal/util"
+)
+
+// WithHTTPTransport sends requests through net/http instead of the default fasthttp
+// client. Use it for HTTP/2, for instrumented or custom http.RoundTrippers, or for proxy
+// and TLS setups configured on an *http.Transport. Retries, rate limits, failover and the
+// request timeout (WithTimeout) apply as with the default client.
+//
+// Example usage:
+//
+//	client := NewClient(apiKey, WithHTTPTransport(otelhttp.NewTransport(http.DefaultTransport)))
+//
+// Parameters:
+//   - rt: The RoundTripper to use; nil uses http.DefaultTransport.
+//
+// Returns:
+//   - Option: A function that selects the net/http transport.
+func WithHTTPTransport(rt http.RoundTripper) Option {
+	return func(c *Client) {
+		c.netHTTP = true
+		c.roundTripper = rt
+	}
+}
+
+// applyTransport configures the connections of the HTTP client with the proxy and TLS
+// options and selects the transport. The net/http transport gets them on a clone of
+// http.DefaultTransport unless a custom RoundTripper was given.
+func (c *Client) applyTransport() {
+	proxy := c.proxyFunc()
+	tlsConfig := c.tlsClientConfig()
+	if proxy != nil {
+		c.httpClient.SetProxy(proxy)
+	}
+	if tlsConfig != nil {
+		c.httpClient.SetTLSConfig(tlsConfig)
+	}
+	if !c.netHTTP {
+		return
+	}
+
+	rt := c.roundTripper
+	if rt == nil && (proxy != nil || tlsConfig != nil) {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		if proxy != nil {
+			transport.Proxy = func(req *http.Request) (*url.URL, error) {
+				return proxy(req.URL.Host)
+			}
+		}
+		if tlsConfig != nil {
+			transport.TLSClientConfig = tlsConfig
+		}
+		rt = transport
+	}
+	// The HTTP client limits every attempt itself, see SetTimeout.
+	c.httpClient.SetTransport(util.NewNetHTTPTransport(rt, 0))
+}
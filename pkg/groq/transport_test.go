@@ -0,0 +1,69 @@
+package groq
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+type countingTransport struct {
+	calls int32
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&t.calls, 1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestWithHTTPTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("missing Authorization header: %v", r.Header)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/audio/transcriptions":
+			file, _, err := r.FormFile("file")
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			data, _ := io.ReadAll(file)
+			_, _ = w.Write([]byte(`{"text":"` + string(data) + `"}`))
+		default:
+			_, _ = w.Write([]byte(`{"id":"1","choices":[{"message":{"role":"assistant","content":"pong"},"finish_reason":"stop"}]}`))
+		}
+	}))
+	defer srv.Close()
+
+	rt := &countingTransport{}
+	client := NewClient("test-key", WithBaseURL(srv.URL), WithHTTPTransport(rt))
+	defer client.Close()
+
+	resp, err := client.CreateChatCompletion(context.Background(), NewRequest(ModelLlama31_8bInstant).User("ping").Build())
+	if err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if resp.Choices[0].Message.Content != "pong" {
+		t.Errorf("unexpected answer %q", resp.Choices[0].Message.Content)
+	}
+
+	transcription, err := client.CreateTranscription(context.Background(), &TranscriptionRequest{
+		File:     strings.NewReader("spoken words"),
+		FileName: "a.mp3",
+	})
+	if err != nil {
+		t.Fatalf("CreateTranscription: %v", err)
+	}
+	if transcription.Text != "spoken words" {
+		t.Errorf("unexpected transcription %q", transcription.Text)
+	}
+
+	if got := atomic.LoadInt32(&rt.calls); got != 2 {
+		t.Errorf("expected both calls to use the RoundTripper, got %d", got)
+	}
+}
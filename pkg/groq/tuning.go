@@ -0,0 +1,70 @@
+package groq
+
+import (
+	"fmt"
+	"time"
+)
+
+// SetTimeout changes the limit of every request attempt while the client is in use, e.g.
+// to give a slow model more time, without rebuilding the client or dropping its pooled
+// connections. Requests already in flight keep the limit they started with.
+//
+// Parameters:
+//   - timeout: The new limit; it must be positive.
+//
+// Returns:
+//   - error: A *ConfigError matching ErrInvalidConfig if the timeout is not positive.
+func (c *Client) SetTimeout(timeout time.Duration) error {
+	if timeout <= 0 {
+		return NewConfigError([]string{fmt.Sprintf("timeout must be positive, got %s", timeout)})
+	}
+	c.httpClient.SetTimeout(timeout)
+	return nil
+}
+
+// SetRateLimit changes how many requests per minute the client starts while it is in use,
+// e.g. after the plan of the API key changed. Requests waiting for the limiter pick up the
+// new pace with the next token; the burst allowance stays the one of the initial rate.
+//
+// Parameters:
+//   - requestsPerMinute: The new rate; it must be positive.
+//
+// Returns:
+//   - error: A *ConfigError matching ErrInvalidConfig if the rate is not positive or the
+//     client paces requests with a limiter of WithRateLimiter, which it cannot retune.
+func (c *Client) SetRateLimit(requestsPerMinute int) error {
+	if requestsPerMinute <= 0 {
+		return NewConfigError([]string{fmt.Sprintf("rate limit must be positive, got %d requests per minute", requestsPerMinute)})
+	}
+	// The built-in limiter takes the configured requests per minute, see httpClientConfig.
+	if !c.httpClient.SetRequestsPerSecond(requestsPerMinute) {
+		return NewConfigError([]string{"SetRateLimit cannot retune a rate limiter set with WithRateLimiter"})
+	}
+	return nil
+}
+
+// SetRetryConfig changes how often failed requests are retried and the initial wait
+// between attempts while the client is in use. Requests already retrying keep their
+// settings.
+//
+// Parameters:
+//   - maxRetries: The number of retries, 0 for none; it must not be negative.
+//   - retryDelay: The wait before the first retry, which grows with every attempt; it
+//     must not be negative, and 0 waits one second.
+//
+// Returns:
+//   - error: A *ConfigError matching ErrInvalidConfig listing the invalid settings.
+func (c *Client) SetRetryConfig(maxRetries int, retryDelay time.Duration) error {
+	var problems []string
+	if maxRetries < 0 {
+		problems = append(problems, fmt.Sprintf("max retries must not be negative, got %d", maxRetries))
+	}
+	if retryDelay < 0 {
+		problems = append(problems, fmt.Sprintf("retry delay must not be negative, got %s", retryDelay))
+	}
+	if err := NewConfigError(problems); err != nil {
+		return err
+	}
+	c.httpClient.SetRetries(maxRetries, retryDelay)
+	return nil
+}
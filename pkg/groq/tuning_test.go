@@ -0,0 +1,55 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRuntimeSetters(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","choices":[{"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}]}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key", WithBaseURL(srv.URL), WithRetryConfig(0, time.Millisecond))
+	defer client.Close()
+	req := NewRequest(ModelLlama31_8bInstant).User("hi").Build()
+
+	if err := client.SetTimeout(20 * time.Millisecond); err != nil {
+		t.Fatalf("SetTimeout: %v", err)
+	}
+	if _, err := client.CreateChatCompletion(context.Background(), req); err == nil {
+		t.Fatal("expected the request to time out")
+	}
+	if err := client.SetTimeout(time.Second); err != nil {
+		t.Fatalf("SetTimeout: %v", err)
+	}
+	if _, err := client.CreateChatCompletion(context.Background(), req); err != nil {
+		t.Fatalf("CreateChatCompletion after raising the timeout: %v", err)
+	}
+
+	if err := client.SetRateLimit(120); err != nil {
+		t.Errorf("SetRateLimit: %v", err)
+	}
+	for name, err := range map[string]error{
+		"timeout":     client.SetTimeout(0),
+		"rate limit":  client.SetRateLimit(-1),
+		"retry count": client.SetRetryConfig(-1, time.Second),
+	} {
+		if !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("%s: expected ErrInvalidConfig, got %v", name, err)
+		}
+	}
+
+	custom := NewClient("test-key", WithRateLimiter(unlimited{}))
+	defer custom.Close()
+	if err := custom.SetRateLimit(60); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected SetRateLimit to reject a custom limiter, got %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package groq
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultUserAgent identifies this library and its version in the User-Agent header of
+// every request, so server logs and support cases can tell which SDK sent a request.
+const DefaultUserAgent = "groq-client-go/" + Version
+
+// WithUserAgent replaces the User-Agent header sent with every request.
+// WithUserAgentSuffix keeps the library version and only adds to it, which is usually
+// what support needs.
+//
+// Parameters:
+//   - userAgent: The header value; empty keeps DefaultUserAgent.
+//
+// Returns:
+//   - Option: A function that sets the User-Agent.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.claim("user agent", "WithUserAgent")
+		c.userAgent = userAgent
+	}
+}
+
+// WithUserAgentSuffix appends a product token, such as the name and version of the
+// application, to the User-Agent header sent with every request. Several suffixes are
+// appended in order.
+//
+// Example usage:
+//
+//	client := NewClient(apiKey, WithUserAgentSuffix("billing-service/2.3.1"))
+//	// User-Agent: groq-client-go/0.1.0 billing-service/2.3.1
+//
+// Parameters:
+//   - suffix: The token to append; empty appends nothing.
+//
+// Returns:
+//   - Option: A function that adds to the User-Agent.
+func WithUserAgentSuffix(suffix string) Option {
+	return func(c *Client) {
+		if suffix != "" {
+			c.userAgentTokens = append(c.userAgentTokens, suffix)
+		}
+	}
+}
+
+// userAgentHeader returns the User-Agent composed from the options.
+func (c *Client) userAgentHeader() string {
+	userAgent := c.userAgent
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+	return strings.Join(append([]string{userAgent}, c.userAgentTokens...), " ")
+}
+
+// userAgentProblems returns the problems of the User-Agent options.
+func (c *Client) userAgentProblems() []string {
+	if userAgent := c.userAgentHeader(); strings.ContainsAny(userAgent, "\r\n") {
+		return []string{fmt.Sprintf("User-Agent %q contains a line break", userAgent)}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUserAgent(t *testing.T) {
+	var userAgent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.UserAgent()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","choices":[{"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}]}`))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{"default", nil, DefaultUserAgent},
+		{"suffix", []Option{WithUserAgentSuffix("billing/2.3.1")}, DefaultUserAgent + " billing/2.3.1"},
+		{"override", []Option{WithUserAgentSuffix("billing/2.3.1"), WithUserAgent("acme-sdk/1.0")}, "acme-sdk/1.0 billing/2.3.1"},
+		{"net/http", []Option{WithHTTPTransport(nil)}, DefaultUserAgent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient("test-key", append([]Option{WithBaseURL(srv.URL)}, tt.opts...)...)
+			defer client.Close()
+
+			if _, err := client.CreateChatCompletion(context.Background(), NewRequest(ModelLlama31_8bInstant).User("hi").Build()); err != nil {
+				t.Fatalf("CreateChatCompletion: %v", err)
+			}
+			if userAgent != tt.want {
+				t.Errorf("User-Agent = %q, want %q", userAgent, tt.want)
+			}
+		})
+	}
+
+	if _, err := NewClientE("test-key", WithUserAgent("bad\r\nX-Injected: 1")); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected a line break to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,239 @@
+package groq
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var ErrUnsupportedVADFormat = errors.New("voice activity detection requires 16-bit PCM wav audio")
+
+// SpeechSegment is a region of detected speech, expressed in sample frames
+// (one frame holds one sample per channel). End is exclusive.
+type SpeechSegment struct {
+	Start int
+	End   int
+}
+
+// VoiceActivityDetector reports the regions of speech in mono PCM audio.
+// Implementations can wrap external detectors (e.g. WebRTC VAD or Silero) so they
+// can be plugged into the transcription pre-filter with WithVAD.
+type VoiceActivityDetector interface {
+	DetectSpeech(samples []int16, sampleRate int) []SpeechSegment
+}
+
+type EnergyVAD struct {
+	FrameDuration time.Duration // Analysis window length
+	Threshold     float64       // RMS level (0-1) above which a frame counts as speech
+	MinSilence    time.Duration // Silences shorter than this are kept as-is
+	Padding       time.Duration // Audio kept around each speech region
+}
+
+// NewEnergyVAD creates a simple RMS energy based VoiceActivityDetector.
+// It works well for clean recordings; noisy audio may need a higher Threshold
+// or a dedicated detector.
+//
+// Defaults:
+//   - FrameDuration: 30ms
+//   - Threshold: 0.02
+//   - MinSilence: 1s
+//   - Padding: 200ms
+//
+// Returns:
+//   - *EnergyVAD: A pointer to the newly created detector.
+func NewEnergyVAD() *EnergyVAD {
+	return &EnergyVAD{
+		FrameDuration: 30 * time.Millisecond,
+		Threshold:     0.02,
+		MinSilence:    time.Second,
+		Padding:       200 * time.Millisecond,
+	}
+}
+
+// DetectSpeech implements VoiceActivityDetector. Frames whose RMS level exceeds the
+// threshold are marked as speech; gaps shorter than MinSilence are merged and each
+// region is extended by Padding on both sides.
+func (v *EnergyVAD) DetectSpeech(samples []int16, sampleRate int) []SpeechSegment {
+	frameLen := int(v.FrameDuration.Seconds() * float64(sampleRate))
+	if frameLen <= 0 {
+		frameLen = 1
+	}
+	minGap := int(v.MinSilence.Seconds() * float64(sampleRate))
+	padding := int(v.Padding.Seconds() * float64(sampleRate))
+
+	var segments []SpeechSegment
+	for start := 0; start < len(samples); start += frameLen {
+		end := start + frameLen
+		if end > len(samples) {
+			end = len(samples)
+		}
+		if rms(samples[start:end]) < v.Threshold {
+			continue
+		}
+
+		if n := len(segments); n > 0 && start-segments[n-1].End < minGap {
+			segments[n-1].End = end
+			continue
+		}
+		segments = append(segments, SpeechSegment{Start: start, End: end})
+	}
+
+	for i := range segments {
+		segments[i].Start = max(0, segments[i].Start-padding)
+		segments[i].End = min(len(samples), segments[i].End+padding)
+		if i > 0 && segments[i].Start < segments[i-1].End {
+			segments[i].Start = segments[i-1].End
+		}
+	}
+
+	return segments
+}
+
+// rms returns the normalized root-mean-square level of the samples.
+func rms(samples []int16) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+
+	var sum float64
+	for _, s := range samples {
+		f := float64(s) / math.MaxInt16
+		sum += f * f
+	}
+	return math.Sqrt(sum / float64(len(samples)))
+}
+
+// TrimSilence removes the non-speech regions reported by detector from a 16-bit PCM WAV
+// file and returns the shortened WAV. Multi-channel audio is analysed on its mono mixdown
+// and trimmed on all channels. Other formats return ErrUnsupportedVADFormat.
+//
+// Parameters:
+//   - r: The WAV audio data.
+//   - detector: The VoiceActivityDetector used to find speech.
+//
+// Returns:
+//   - []byte: The trimmed WAV file.
+//   - error: ErrUnsupportedVADFormat or a read error.
+func TrimSilence(r io.Reader, detector VoiceActivityDetector) ([]byte, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading audio: %w", err)
+	}
+
+	fmtChunk, pcm, err := parsePCMWAV(data)
+	if err != nil {
+		return nil, err
+	}
+
+	channels := int(binary.LittleEndian.Uint16(fmtChunk[2:4]))
+	sampleRate := int(binary.LittleEndian.Uint32(fmtChunk[4:8]))
+	frameSize := channels * 2
+	frames := len(pcm) / frameSize
+
+	mono := make([]int16, frames)
+	for i := 0; i < frames; i++ {
+		var sum int
+		for ch := 0; ch < channels; ch++ {
+			off := i*frameSize + ch*2
+			sum += int(int16(binary.LittleEndian.Uint16(pcm[off : off+2])))
+		}
+		mono[i] = int16(sum / channels)
+	}
+
+	var trimmed bytes.Buffer
+	for _, seg := range detector.DetectSpeech(mono, sampleRate) {
+		start := max(0, seg.Start) * frameSize
+		end := min(frames, seg.End) * frameSize
+		if start < end {
+			trimmed.Write(pcm[start:end])
+		}
+	}
+
+	var out bytes.Buffer
+	out.WriteString("RIFF")
+	_ = binary.Write(&out, binary.LittleEndian, uint32(4+8+len(fmtChunk)+8+trimmed.Len()))
+	out.WriteString("WAVEfmt ")
+	_ = binary.Write(&out, binary.LittleEndian, uint32(len(fmtChunk)))
+	out.Write(fmtChunk)
+	out.WriteString("data")
+	_ = binary.Write(&out, binary.LittleEndian, uint32(trimmed.Len()))
+	out.Write(trimmed.Bytes())
+
+	return out.Bytes(), nil
+}
+
+// parsePCMWAV returns the fmt chunk body and the data chunk of a 16-bit PCM WAV file.
+func parsePCMWAV(data []byte) ([]byte, []byte, error) {
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return nil, nil, ErrUnsupportedVADFormat
+	}
+
+	var fmtChunk []byte
+	pos := 12
+	for pos+8 <= len(data) {
+		id := string(data[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(data[pos+4 : pos+8]))
+		body := pos + 8
+		if body+size > len(data) {
+			size = len(data) - body
+		}
+
+		switch id {
+		case "fmt ":
+			fmtChunk = data[body : body+size]
+			if len(fmtChunk) < 16 ||
+				binary.LittleEndian.Uint16(fmtChunk[0:2]) != 1 ||
+				binary.LittleEndian.Uint16(fmtChunk[14:16]) != 16 ||
+				binary.LittleEndian.Uint16(fmtChunk[2:4]) == 0 {
+				return nil, nil, ErrUnsupportedVADFormat
+			}
+		case "data":
+			if fmtChunk == nil {
+				return nil, nil, ErrUnsupportedVADFormat
+			}
+			return fmtChunk, data[body : body+size], nil
+		}
+
+		pos = body + size + size%2
+	}
+
+	return nil, nil, ErrUnsupportedVADFormat
+}
+
+// applyVAD runs the client's voice activity detector over WAV uploads. Other formats,
+// and WAV files the detector cannot handle, are uploaded unchanged.
+func (c *Client) applyVAD(file io.Reader, fileName string) (io.Reader, error) {
+	if c.vad == nil || !strings.EqualFold(filepath.Ext(fileName), ".wav") {
+		return file, nil
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading audio: %w", err)
+	}
+
+	trimmed, err := TrimSilence(bytes.NewReader(data), c.vad)
+	if err != nil {
+		return bytes.NewReader(data), nil
+	}
+	return bytes.NewReader(trimmed), nil
+}
+
+// WithVAD enables a voice activity detection pre-filter for transcription and translation
+// uploads. Long silences are trimmed from 16-bit PCM WAV files before upload, which reduces
+// the billed duration and avoids Whisper hallucinating text during silence.
+//
+// Example usage:
+//
+//	client := NewClient(apiKey, WithVAD(NewEnergyVAD()))
+func WithVAD(detector VoiceActivityDetector) Option {
+	return func(c *Client) {
+		c.vad = detector
+	}
+}
@@ -0,0 +1,47 @@
+package groq
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTrimSilence(t *testing.T) {
+	const sampleRate = 8000
+
+	// 1s tone, 3s silence, 1s tone
+	wav := buildWAV(sampleRate, sampleRate*5)
+	pcm := wav[44:]
+	for i := 0; i < sampleRate*5; i++ {
+		if i >= sampleRate && i < sampleRate*4 {
+			continue
+		}
+		sample := int16(8000)
+		if i%2 == 1 {
+			sample = -8000
+		}
+		binary.LittleEndian.PutUint16(pcm[i*2:], uint16(sample))
+	}
+
+	trimmed, err := TrimSilence(bytes.NewReader(wav), NewEnergyVAD())
+	if err != nil {
+		t.Fatalf("TrimSilence() error = %v", err)
+	}
+
+	got, err := ProbeAudioDuration(bytes.NewReader(trimmed), "trimmed.wav")
+	if err != nil {
+		t.Fatalf("ProbeAudioDuration() error = %v", err)
+	}
+	if got < 2*time.Second || got > 3*time.Second {
+		t.Errorf("expected the 3s silence to be trimmed to padding, got duration %v", got)
+	}
+}
+
+func TestTrimSilenceUnsupported(t *testing.T) {
+	_, err := TrimSilence(bytes.NewReader([]byte("ID3 not a wav")), NewEnergyVAD())
+	if !errors.Is(err, ErrUnsupportedVADFormat) {
+		t.Errorf("TrimSilence() error = %v, want ErrUnsupportedVADFormat", err)
+	}
+}
@@ -0,0 +1,4 @@
+package groq
+
+// Version is the version of this client library.
+const Version = "0.1.0"
@@ -1,15 +1,15 @@
 package groq
 
 import (
-	"encoding/base64"
+	"context"
 	"fmt"
 	"io"
-	"net/http"
 )
 
 const (
-	MaxURLImageSize    = 20 * 1024 * 1024 // 20MB
-	MaxBase64ImageSize = 4 * 1024 * 1024  // 4MB
+	MaxURLImageSize     = 20 * 1024 * 1024 // 20MB
+	MaxBase64ImageSize  = 4 * 1024 * 1024  // 4MB
+	MaxImagesPerRequest = 5                // Images the API accepts in one request
 )
 
 // Content types for multimodal messages
@@ -21,9 +21,20 @@ type ContentType struct {
 
 // ImageURL represents an image URL in the request
 type ImageURL struct {
-	URL string `json:"url"`
+	URL    string      `json:"url"`
+	Detail ImageDetail `json:"detail,omitempty"` // Optional fidelity, defaults to auto
 }
 
+// ImageDetail controls how much of an image the model sees: low detail costs fewer
+// tokens, high detail preserves fine print and small objects.
+type ImageDetail string
+
+const (
+	ImageDetailAuto ImageDetail = "auto"
+	ImageDetailLow  ImageDetail = "low"
+	ImageDetailHigh ImageDetail = "high"
+)
+
 // NewTextContent creates a new ContentType with type "text" and the given text value.
 // It is used for creating text content for the vision model.
 //
@@ -54,31 +65,43 @@ func NewImageURLContent(url string) ContentType {
 	}
 }
 
+// NewImageURLContentWithDetail creates image content like NewImageURLContent with an
+// explicit detail level, trading vision token cost against fidelity.
+// Example:
+//
+//	content := NewImageURLContentWithDetail("https://example.com/receipt.jpg", ImageDetailHigh)
+func NewImageURLContentWithDetail(url string, detail ImageDetail) ContentType {
+	content := NewImageURLContent(url)
+	content.ImageURL.Detail = detail
+	return content
+}
+
 // ImageToBase64 converts an image from an io.Reader into a base64 encoded string with data URI prefix.
-// The function reads the entire image data and encodes it to base64, prepending the data URI scheme
-// for JPEG images. It enforces a maximum size limit defined by MaxBase64ImageSize.
+// The MIME type of the prefix is detected from the image bytes; data that is not a supported
+// image keeps the historical "image/jpeg" prefix. Use ImageFromReader to reject such data
+// instead. It enforces a maximum size limit defined by MaxBase64ImageSize.
 //
 // Parameters:
 //   - reader: An io.Reader interface providing the image data to be encoded
 //
 // Returns:
-//   - string: A base64 encoded string with "data:image/jpeg;base64," prefix
+//   - string: A base64 encoded string with a "data:<mime type>;base64," prefix
 //   - error: An error if reading fails or if the image size exceeds MaxBase64ImageSize
 func ImageToBase64(reader io.Reader) (string, error) {
-	data, err := io.ReadAll(reader)
+	data, err := readImage(reader)
 	if err != nil {
-		return "", fmt.Errorf("error reading image: %w", err)
+		return "", err
 	}
 
-	if len(data) > MaxBase64ImageSize {
-		return "", fmt.Errorf("image size exceeds limit of %d bytes", MaxBase64ImageSize)
+	contentType := DetectImageType(data)
+	if contentType == "" {
+		contentType = "image/jpeg"
 	}
-
-	return fmt.Sprintf("data:image/jpeg;base64,%s", base64.StdEncoding.EncodeToString(data)), nil
+	return imageDataURI(contentType, data), nil
 }
 
-// ValidateImageURL performs validation checks on a provided image URL.
-// It verifies that:
+// ValidateImageURL performs validation checks on a provided image URL with a HEAD request
+// (see HTTPImageValidator); base64 data URIs are checked offline. It verifies that:
 // - The URL is accessible and returns a successful status code
 // - The image size doesn't exceed MaxURLImageSize
 // - The content type is a valid image format
@@ -95,27 +118,7 @@ func ImageToBase64(reader io.Reader) (string, error) {
 //   - Image size exceeding MaxURLImageSize
 //   - Invalid image content types
 func ValidateImageURL(url string) error {
-	resp, err := http.Head(url)
-	if err != nil {
-		return fmt.Errorf("error checking image URL: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("invalid image URL, status code: %d", resp.StatusCode)
-	}
-
-	size := resp.ContentLength
-	if size > MaxURLImageSize {
-		return fmt.Errorf("image size (%d bytes) exceeds limit of %d bytes", size, MaxURLImageSize)
-	}
-
-	contentType := resp.Header.Get("Content-Type")
-	if !isValidImageType(contentType) {
-		return fmt.Errorf("invalid image type: %s", contentType)
-	}
-
-	return nil
+	return defaultImageValidator.ValidateImage(context.Background(), url)
 }
 
 // isValidImageType checks if the provided MIME content type represents a supported image format.
@@ -192,22 +195,28 @@ func CreateVisionRequest(model ModelType, imageURL, question string) *ChatComple
 // validateVision checks if the ChatCompletionRequest is valid for vision-based tasks.
 // It verifies that:
 // 1. The selected model supports vision features
-// 2. All image URLs in the message content are valid
+// 2. Every image detail level is auto, low or high
+// 3. All image URLs in the message content are valid
 //
 // Returns an error if:
 // - The model does not support vision features
 // - Any image URL in the messages is invalid
-func (r *ChatCompletionRequest) validateVision() error {
+func (r *ChatCompletionRequest) validateVision(ctx context.Context, images ImageValidator) error {
 	info := r.Model.GetInfo()
 	if !containsString(info.Features, "vision") {
-		return fmt.Errorf("model %s does not support vision features", r.Model)
+		return newLocalizedError(nil, MsgVisionUnsupported, r.Model)
 	}
 
 	for _, msg := range r.Messages {
 		if content, ok := msg.Content.([]ContentType); ok {
 			for _, c := range content {
 				if c.ImageURL != nil {
-					if err := ValidateImageURL(c.ImageURL.URL); err != nil {
+					switch c.ImageURL.Detail {
+					case "", ImageDetailAuto, ImageDetailLow, ImageDetailHigh:
+					default:
+						return newLocalizedError(nil, MsgImageDetail, c.ImageURL.Detail)
+					}
+					if err := images.ValidateImage(ctx, c.ImageURL.URL); err != nil {
 						return fmt.Errorf("invalid image URL: %w", err)
 					}
 				}
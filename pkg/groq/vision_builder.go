@@ -0,0 +1,145 @@
+package groq
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrTooManyImages = errors.New("too many images")
+
+type VisionRequestBuilder struct {
+	model        ModelType
+	prompt       string
+	system       string
+	images       []visionImage
+	detail       ImageDetail
+	maxTokens    int
+	preprocessor *ImagePreprocessor
+}
+
+type visionImage struct {
+	source  string // URL, data URI or file path
+	file    bool
+	caption string
+}
+
+// NewVisionRequest starts a builder for a vision request with several images in a single
+// user message. Unlike CreateVisionRequest, images can mix URLs and local files and each
+// can carry a caption. When more than one image is added, every image is preceded by a
+// label such as "Image 2: <caption>", so the prompt can refer to the images by number.
+//
+// Example usage:
+//
+//	req, err := groq.NewVisionRequest(groq.ModelLlama4Scout, "Which of these receipts is the most expensive?").
+//	    System("You are an expense assistant.").
+//	    AddImageURL("https://example.com/receipt-1.jpg", "March").
+//	    AddImageFile("receipt-2.png", "April").
+//	    Build()
+//
+// Parameters:
+//   - model: A vision-capable model.
+//   - prompt: The question or instruction about the images.
+//
+// Returns:
+//   - *VisionRequestBuilder: The builder.
+func NewVisionRequest(model ModelType, prompt string) *VisionRequestBuilder {
+	return &VisionRequestBuilder{model: model, prompt: prompt}
+}
+
+// System sets a system prompt sent before the images.
+func (b *VisionRequestBuilder) System(content string) *VisionRequestBuilder {
+	b.system = content
+	return b
+}
+
+// AddImageURL adds an image by URL or base64 data URI. caption may be empty.
+func (b *VisionRequestBuilder) AddImageURL(url, caption string) *VisionRequestBuilder {
+	b.images = append(b.images, visionImage{source: url, caption: caption})
+	return b
+}
+
+// AddImageFile adds a local image file, embedded as a data URI when Build is called.
+// caption may be empty.
+func (b *VisionRequestBuilder) AddImageFile(path, caption string) *VisionRequestBuilder {
+	b.images = append(b.images, visionImage{source: path, file: true, caption: caption})
+	return b
+}
+
+// Detail sets the detail level of all images.
+func (b *VisionRequestBuilder) Detail(detail ImageDetail) *VisionRequestBuilder {
+	b.detail = detail
+	return b
+}
+
+// Preprocess fits local image files into the request limits with p instead of failing
+// on images larger than MaxBase64ImageSize.
+func (b *VisionRequestBuilder) Preprocess(p *ImagePreprocessor) *VisionRequestBuilder {
+	b.preprocessor = p
+	return b
+}
+
+// MaxTokens sets the maximum number of tokens to generate.
+func (b *VisionRequestBuilder) MaxTokens(tokens int) *VisionRequestBuilder {
+	b.maxTokens = tokens
+	return b
+}
+
+// Build reads the image files and assembles the request.
+//
+// Returns:
+//   - *ChatCompletionRequest: The request, with the prompt followed by the (labelled) images.
+//   - error: An error if there are no images or more than MaxImagesPerRequest (matching
+//     ErrTooManyImages), or an image file cannot be read.
+func (b *VisionRequestBuilder) Build() (*ChatCompletionRequest, error) {
+	if len(b.images) == 0 {
+		return nil, errors.New("vision request needs at least one image")
+	}
+	if len(b.images) > MaxImagesPerRequest {
+		return nil, fmt.Errorf("%w: %d images, the API accepts at most %d per request", ErrTooManyImages, len(b.images), MaxImagesPerRequest)
+	}
+
+	parts := make([]ContentType, 0, 1+2*len(b.images))
+	if strings.TrimSpace(b.prompt) != "" {
+		parts = append(parts, NewTextContent(b.prompt))
+	}
+	for i, img := range b.images {
+		url := img.source
+		if img.file {
+			var err error
+			if b.preprocessor != nil {
+				url, err = b.preprocessor.FromFile(img.source)
+			} else {
+				url, err = ImageFromFile(img.source)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("image %d (%s): %w", i+1, img.source, err)
+			}
+		}
+
+		if label := imageLabel(i, len(b.images), img.caption); label != "" {
+			parts = append(parts, NewTextContent(label))
+		}
+		parts = append(parts, NewImageURLContentWithDetail(url, b.detail))
+	}
+
+	req := &ChatCompletionRequest{Model: b.model, MaxTokens: b.maxTokens}
+	if b.system != "" {
+		req.Messages = append(req.Messages, ChatMessage{Role: "system", Content: b.system})
+	}
+	req.Messages = append(req.Messages, ChatMessage{Role: "user", Content: parts})
+	return req, nil
+}
+
+// imageLabel returns the text placed before image i of n: a numbered label when there
+// are several images, and the caption if one is set.
+func imageLabel(i, n int, caption string) string {
+	switch {
+	case n > 1 && caption != "":
+		return fmt.Sprintf("Image %d: %s", i+1, caption)
+	case n > 1:
+		return fmt.Sprintf("Image %d:", i+1)
+	default:
+		return caption
+	}
+}
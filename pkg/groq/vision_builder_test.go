@@ -0,0 +1,67 @@
+package groq
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVisionRequestBuilder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chart.png")
+	if err := os.WriteFile(path, testPNG, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := NewVisionRequest(ModelLlama4Scout, "Compare the images.").
+		System("Be concise.").
+		AddImageURL("https://example.com/a.jpg", "before").
+		AddImageFile(path, "").
+		Detail(ImageDetailLow).
+		MaxTokens(50).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	if len(req.Messages) != 2 || req.Messages[0].Role != "system" || req.MaxTokens != 50 {
+		t.Fatalf("unexpected request: %+v", req)
+	}
+	parts := req.Messages[1].Content.([]ContentType)
+	want := []string{"Compare the images.", "Image 1: before", "https://example.com/a.jpg", "Image 2:", "data:image/png;base64,"}
+	if len(parts) != len(want) {
+		t.Fatalf("expected %d content parts, got %+v", len(want), parts)
+	}
+	for i, w := range want {
+		got := parts[i].Text
+		if parts[i].ImageURL != nil {
+			got = parts[i].ImageURL.URL
+			if parts[i].ImageURL.Detail != ImageDetailLow {
+				t.Errorf("part %d: expected low detail, got %q", i, parts[i].ImageURL.Detail)
+			}
+		}
+		if !strings.HasPrefix(got, w) {
+			t.Errorf("part %d = %.40q, want %q", i, got, w)
+		}
+	}
+}
+
+func TestVisionRequestBuilderErrors(t *testing.T) {
+	b := NewVisionRequest(ModelLlama4Scout, "describe")
+	if _, err := b.Build(); err == nil {
+		t.Error("expected a request without images to be rejected")
+	}
+
+	for i := 0; i <= MaxImagesPerRequest; i++ {
+		b.AddImageURL("https://example.com/a.jpg", "")
+	}
+	if _, err := b.Build(); !errors.Is(err, ErrTooManyImages) {
+		t.Errorf("expected ErrTooManyImages, got %v", err)
+	}
+
+	_, err := NewVisionRequest(ModelLlama4Scout, "describe").AddImageFile("missing.png", "").Build()
+	if err == nil || !strings.Contains(err.Error(), "missing.png") {
+		t.Errorf("expected an error naming the missing file, got %v", err)
+	}
+}
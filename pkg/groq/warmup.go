@@ -0,0 +1,49 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Warmup connects to the API ahead of the first request, so that request does not pay for
+// the DNS lookup and the TCP and TLS handshakes, which matters most for the time to the
+// first token of streams. With WithBaseURLs every endpoint is warmed up in parallel. The
+// connections stay in the pool until they have been idle for too long, so call Warmup
+// shortly before traffic is expected, e.g. at startup or after a quiet period.
+//
+// Warmup sends a HEAD request without the API key; it does not count against the rate
+// limits of the client.
+//
+// Example usage:
+//
+//	client := NewClient(apiKey)
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//	if err := client.Warmup(ctx); err != nil {
+//	    log.Printf("warmup failed: %v", err)
+//	}
+//
+// Parameters:
+//   - ctx: The context bounding the warmup.
+//
+// Returns:
+//   - error: The errors of the endpoints that could not be reached.
+func (c *Client) Warmup(ctx context.Context) error {
+	baseURLs := c.baseURLs
+	if len(baseURLs) == 0 {
+		baseURLs = []string{c.baseURL}
+	}
+
+	errs := make([]error, len(baseURLs))
+	var wg sync.WaitGroup
+	for i, baseURL := range baseURLs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = c.httpClient.Warmup(ctx, baseURL)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
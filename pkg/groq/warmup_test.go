@@ -0,0 +1,62 @@
+package groq
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestWarmup(t *testing.T) {
+	var conns, heads int32
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			atomic.AddInt32(&heads, 1)
+			if r.Header.Get("Authorization") != "" {
+				t.Error("warmup must not send the API key")
+			}
+			// Without a length fasthttp cannot tell where the answer ends and closes the connection.
+			w.Header().Set("Content-Length", "0")
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","choices":[{"message":{"role":"assistant","content":"pong"},"finish_reason":"stop"}]}`))
+	}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+
+	client := NewClient("test-key", WithBaseURL(srv.URL))
+	defer client.Close()
+
+	if err := client.Warmup(context.Background()); err != nil {
+		t.Fatalf("Warmup: %v", err)
+	}
+	if _, err := client.CreateChatCompletion(context.Background(), NewRequest(ModelLlama31_8bInstant).User("ping").Build()); err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if heads != 1 || conns != 1 {
+		t.Errorf("expected one HEAD request and the request to reuse its connection, got %d HEAD and %d connections", heads, conns)
+	}
+}
+
+func TestWarmupUnreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	client := NewClient("test-key", WithBaseURLs(srv.URL, "http://127.0.0.1:1"), WithRetryConfig(1, 0))
+	defer client.Close()
+
+	err := client.Warmup(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "127.0.0.1:1") || strings.Contains(err.Error(), srv.URL) {
+		t.Errorf("expected an error for the unreachable endpoint only, got %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+package wire
+
+// TranscriptionResponse is the body of /audio/transcriptions for the json and
+// verbose_json formats. Only Text is set for the json format.
+type TranscriptionResponse struct {
+	Text     string                 `json:"text"`
+	Task     string                 `json:"task,omitempty"`
+	Language string                 `json:"language,omitempty"`
+	Duration float64                `json:"duration,omitempty"` // Seconds
+	Segments []TranscriptionSegment `json:"segments,omitempty"`
+	Words    []TranscriptionWord    `json:"words,omitempty"`
+	XGroq    XGroq                  `json:"x_groq"`
+}
+
+type TranscriptionSegment struct {
+	ID               int     `json:"id"`
+	Seek             int     `json:"seek"`
+	Start            float64 `json:"start"` // Seconds
+	End              float64 `json:"end"`   // Seconds
+	Text             string  `json:"text"`
+	Tokens           []int   `json:"tokens,omitempty"`
+	Temperature      float64 `json:"temperature"`
+	AvgLogprob       float64 `json:"avg_logprob"`
+	CompressionRatio float64 `json:"compression_ratio"`
+	NoSpeechProb     float64 `json:"no_speech_prob"`
+}
+
+type TranscriptionWord struct {
+	Word  string  `json:"word"`
+	Start float64 `json:"start"` // Seconds
+	End   float64 `json:"end"`   // Seconds
+}
+
+type TranslationResponse struct {
+	Text  string `json:"text"`
+	XGroq XGroq  `json:"x_groq"`
+}
+
+// SpeechRequest is the body of /audio/speech. The response is the raw audio.
+type SpeechRequest struct {
+	Model          string  `json:"model"`
+	Input          string  `json:"input"`
+	Voice          string  `json:"voice"`
+	ResponseFormat string  `json:"response_format,omitempty"`
+	Speed          float64 `json:"speed,omitempty"`
+}
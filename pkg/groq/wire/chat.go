@@ -0,0 +1,165 @@
+// Package wire defines the JSON wire format of the Groq API: the raw request, response,
+// stream chunk and error bodies. It depends only on the standard library, so servers
+// such as gateways, mocks and batch result parsers can decode and produce API traffic
+// without importing the client and its HTTP stack.
+//
+// The client package aliases the types that carry no client behaviour, such as Usage,
+// TranslationResponse and ModelList. Chat requests and responses and transcriptions are
+// defined separately there because they add validation, model metadata, annotations or
+// subtitle rendering; both encode to the same JSON.
+package wire
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+type ChatCompletionRequest struct {
+	Model       string        `json:"model"`
+	Messages    []ChatMessage `json:"messages"`
+	MaxTokens   int           `json:"max_tokens,omitempty"`
+	Temperature *float64      `json:"temperature,omitempty"`
+	TopP        *float64      `json:"top_p,omitempty"`
+	Stream      bool          `json:"stream,omitempty"`
+	Tools       []Tool        `json:"tools,omitempty"`
+}
+
+// ChatMessage is a message of a chat request or response. Content is a string, or
+// []ContentPart for multimodal messages, or nil for assistant messages with tool calls.
+type ChatMessage struct {
+	Role       string      `json:"role"`
+	Content    interface{} `json:"content"`
+	ToolCalls  []ToolCall  `json:"tool_calls,omitempty"`
+	ToolCallID string      `json:"tool_call_id,omitempty"`
+}
+
+type ContentPart struct {
+	Type     string    `json:"type"` // "text" or "image_url"
+	Text     string    `json:"text,omitempty"`
+	ImageURL *ImageURL `json:"image_url,omitempty"`
+}
+
+type ImageURL struct {
+	URL    string `json:"url"`
+	Detail string `json:"detail,omitempty"` // "auto", "low" or "high"
+}
+
+type Tool struct {
+	Type     string             `json:"type"` // Always "function"
+	Function FunctionDefinition `json:"function"`
+}
+
+type FunctionDefinition struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"` // JSON Schema object
+}
+
+type ToolCall struct {
+	ID       string       `json:"id"`
+	Type     string       `json:"type"`
+	Function FunctionCall `json:"function"`
+}
+
+type FunctionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"` // JSON object encoded as a string
+}
+
+type ChatCompletionResponse struct {
+	ID                string   `json:"id"`
+	Object            string   `json:"object"` // "chat.completion"
+	Created           int64    `json:"created"`
+	Model             string   `json:"model"`
+	SystemFingerprint string   `json:"system_fingerprint,omitempty"`
+	Choices           []Choice `json:"choices"`
+	Usage             Usage    `json:"usage"`
+	XGroq             *XGroq   `json:"x_groq,omitempty"`
+}
+
+type Choice struct {
+	Index        int         `json:"index"`
+	Message      ChatMessage `json:"message"`
+	FinishReason string      `json:"finish_reason"`
+}
+
+// ChatCompletionChunk is one server-sent event of a streamed chat completion. Each event
+// is sent as "data: <json>\n\n" and the stream ends with "data: [DONE]".
+type ChatCompletionChunk struct {
+	ID                string        `json:"id"`
+	Object            string        `json:"object"` // "chat.completion.chunk"
+	Created           int64         `json:"created"`
+	Model             string        `json:"model"`
+	SystemFingerprint string        `json:"system_fingerprint,omitempty"`
+	Choices           []ChunkChoice `json:"choices"`
+	Usage             *Usage        `json:"usage,omitempty"`
+	XGroq             *XGroq        `json:"x_groq,omitempty"` // Groq reports stream usage here on the last chunk
+}
+
+type ChunkChoice struct {
+	Index        int    `json:"index"`
+	Delta        Delta  `json:"delta"`
+	FinishReason string `json:"finish_reason,omitempty"` // Empty until the last chunk of the choice
+}
+
+type Delta struct {
+	Role      string     `json:"role,omitempty"`
+	Content   string     `json:"content,omitempty"`
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+}
+
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+type XGroq struct {
+	ID    string `json:"id,omitempty"`
+	Usage *Usage `json:"usage,omitempty"`
+}
+
+// Add accumulates the token counts of other into u.
+func (u *Usage) Add(other Usage) {
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+	u.TotalTokens += other.TotalTokens
+}
+
+// UnmarshalJSON decodes a ChatMessage, restoring multimodal content as []ContentPart
+// instead of the generic []interface{} the default decoder would produce.
+func (m *ChatMessage) UnmarshalJSON(data []byte) error {
+	type message ChatMessage
+	var raw struct {
+		message
+		Content json.RawMessage `json:"content"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*m = ChatMessage(raw.message)
+	m.Content = nil
+
+	content := bytes.TrimSpace(raw.Content)
+	if len(content) == 0 || string(content) == "null" {
+		return nil
+	}
+
+	var err error
+	switch content[0] {
+	case '"':
+		var text string
+		err = json.Unmarshal(content, &text)
+		m.Content = text
+	case '[':
+		var parts []ContentPart
+		err = json.Unmarshal(content, &parts)
+		m.Content = parts
+	default:
+		var v interface{}
+		err = json.Unmarshal(content, &v)
+		m.Content = v
+	}
+	return err
+}
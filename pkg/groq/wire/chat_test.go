@@ -0,0 +1,28 @@
+package wire
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestChatMessageUnmarshal(t *testing.T) {
+	var req ChatCompletionRequest
+	err := json.Unmarshal([]byte(`{"model":"llama-3.1-8b-instant","messages":[
+		{"role":"user","content":[{"type":"text","text":"what is this?"},{"type":"image_url","image_url":{"url":"https://example.com/a.png","detail":"low"}}]},
+		{"role":"assistant","content":null,"tool_calls":[{"id":"call_1","type":"function","function":{"name":"lookup","arguments":"{}"}}]},
+		{"role":"tool","content":"done","tool_call_id":"call_1"}]}`), &req)
+	if err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	parts, ok := req.Messages[0].Content.([]ContentPart)
+	if !ok || len(parts) != 2 || parts[1].ImageURL.Detail != "low" {
+		t.Errorf("expected multimodal content parts, got %#v", req.Messages[0].Content)
+	}
+	if msg := req.Messages[1]; msg.Content != nil || len(msg.ToolCalls) != 1 || msg.ToolCalls[0].Function.Name != "lookup" {
+		t.Errorf("unexpected tool call message: %+v", msg)
+	}
+	if msg := req.Messages[2]; msg.Content != "done" || msg.ToolCallID != "call_1" {
+		t.Errorf("unexpected tool message: %+v", msg)
+	}
+}
@@ -0,0 +1,23 @@
+package wire
+
+// EmbeddingRequest is the body of an OpenAI-compatible /embeddings request.
+type EmbeddingRequest struct {
+	Model          string   `json:"model"`
+	Input          []string `json:"input"`
+	EncodingFormat string   `json:"encoding_format,omitempty"`
+	Dimensions     int      `json:"dimensions,omitempty"`
+}
+
+// EmbeddingResponse is the body of an /embeddings response, one Embedding per input.
+type EmbeddingResponse struct {
+	Object string      `json:"object"`
+	Data   []Embedding `json:"data"`
+	Model  string      `json:"model"`
+	Usage  *Usage      `json:"usage,omitempty"`
+}
+
+type Embedding struct {
+	Object    string    `json:"object"`
+	Index     int       `json:"index"` // Position of the input the vector belongs to
+	Embedding []float32 `json:"embedding"`
+}
@@ -0,0 +1,13 @@
+package wire
+
+// ErrorResponse is the body of every non-2xx API response.
+type ErrorResponse struct {
+	Error ErrorDetail `json:"error"`
+}
+
+type ErrorDetail struct {
+	Message string `json:"message"`
+	Type    string `json:"type,omitempty"`  // e.g. "invalid_request_error"
+	Code    string `json:"code,omitempty"`  // e.g. "model_decommissioned"
+	Param   string `json:"param,omitempty"` // The offending request field, if any
+}
@@ -0,0 +1,16 @@
+package wire
+
+type Model struct {
+	ID                  string `json:"id"`
+	Object              string `json:"object"` // "model"
+	Created             int64  `json:"created"`
+	OwnedBy             string `json:"owned_by"`
+	Active              bool   `json:"active"`
+	ContextWindow       int    `json:"context_window"`
+	MaxCompletionTokens int    `json:"max_completion_tokens,omitempty"`
+}
+
+type ModelList struct {
+	Object string  `json:"object"` // "list"
+	Data   []Model `json:"data"`
+}
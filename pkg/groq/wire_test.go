@@ -0,0 +1,73 @@
+package groq
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/genc-murat/groq-client/pkg/groq/wire"
+)
+
+// roundTrip encodes v, decodes it into a value of type W and encodes that again, so the
+// JSON of the client type and its wire counterpart can be compared.
+func roundTrip[W any](t *testing.T, v interface{}) (first, second map[string]interface{}) {
+	t.Helper()
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var w W
+	if err := json.Unmarshal(data, &w); err != nil {
+		t.Fatalf("decoding into %T: %v", w, err)
+	}
+	again, err := json.Marshal(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_ = json.Unmarshal(data, &first)
+	_ = json.Unmarshal(again, &second)
+	return first, second
+}
+
+func TestWireTypesMatchClientTypes(t *testing.T) {
+	req := NewRequest(ModelLlama4Scout).
+		System("be brief").
+		User("what is this?").
+		WithImageDetail("https://example.com/a.png", ImageDetailLow).
+		Temperature(0).
+		TopP(0.9).
+		MaxTokens(20).
+		Build()
+	req.Tools = []Tool{NewFunctionTool(WeatherFunction)}
+	req.Messages = append(req.Messages,
+		ChatMessage{Role: "assistant", ToolCalls: []ToolCall{{ID: "call_1", Type: "function", Function: FunctionCall{Name: "get_weather", Arguments: json.RawMessage(`"{\"location\":\"Paris\"}"`)}}}},
+		ChatMessage{Role: "tool", Content: "18C", ToolCallID: "call_1"},
+	)
+	if a, b := roundTrip[wire.ChatCompletionRequest](t, req); !reflect.DeepEqual(a, b) {
+		t.Errorf("request JSON differs:\nclient %v\nwire   %v", a, b)
+	}
+
+	var resp ChatCompletionResponse
+	if err := json.Unmarshal([]byte(`{"id":"chatcmpl-1","object":"chat.completion","created":1,"model":"llama-3.1-8b-instant",
+		"choices":[{"index":0,"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}],
+		"usage":{"prompt_tokens":3,"completion_tokens":1,"total_tokens":4}}`), &resp); err != nil {
+		t.Fatal(err)
+	}
+	a, b := roundTrip[wire.ChatCompletionResponse](t, &resp)
+	delete(b["choices"].([]interface{})[0].(map[string]interface{}), "index")
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("response JSON differs:\nclient %v\nwire   %v", a, b)
+	}
+
+	var chunk ChatCompletionChunk
+	if err := json.Unmarshal([]byte(`{"id":"chatcmpl-1","object":"chat.completion.chunk","created":1,"model":"llama-3.1-8b-instant",
+		"choices":[{"index":0,"delta":{"content":"hi"},"finish_reason":"stop"}],
+		"x_groq":{"id":"req_1","usage":{"prompt_tokens":3,"completion_tokens":1,"total_tokens":4}}}`), &chunk); err != nil {
+		t.Fatal(err)
+	}
+	if a, b := roundTrip[wire.ChatCompletionChunk](t, &chunk); !reflect.DeepEqual(a, b) {
+		t.Errorf("chunk JSON differs:\nclient %v\nwire   %v", a, b)
+	}
+}
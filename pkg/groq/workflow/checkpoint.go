@@ -0,0 +1,190 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/genc-murat/groq-client/pkg/groq"
+)
+
+type Checkpoint struct {
+	NodeID      string          `json:"node_id"`
+	Output      json.RawMessage `json:"output"`
+	Model       groq.ModelType  `json:"model,omitempty"`
+	Usage       *groq.Usage     `json:"usage,omitempty"`
+	CompletedAt time.Time       `json:"completed_at"`
+}
+
+// CheckpointStore persists the outputs of completed nodes so an interrupted run can be
+// resumed from the last completed node instead of starting over.
+type CheckpointStore interface {
+	Save(ctx context.Context, runID string, cp *Checkpoint) error
+	Load(ctx context.Context, runID string) (map[string]*Checkpoint, error)
+	Delete(ctx context.Context, runID string) error
+}
+
+type MemoryCheckpointStore struct {
+	runs map[string]map[string]*Checkpoint
+	mu   sync.Mutex
+}
+
+// NewMemoryCheckpointStore creates a CheckpointStore that keeps checkpoints in memory.
+// It is useful for tests and for retrying a run within the same process.
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{runs: make(map[string]map[string]*Checkpoint)}
+}
+
+// Save stores the checkpoint of a node.
+func (s *MemoryCheckpointStore) Save(ctx context.Context, runID string, cp *Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.runs[runID] == nil {
+		s.runs[runID] = make(map[string]*Checkpoint)
+	}
+	s.runs[runID][cp.NodeID] = cp
+	return nil
+}
+
+// Load returns all checkpoints of a run, keyed by node ID.
+func (s *MemoryCheckpointStore) Load(ctx context.Context, runID string) (map[string]*Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	checkpoints := make(map[string]*Checkpoint, len(s.runs[runID]))
+	for id, cp := range s.runs[runID] {
+		checkpoints[id] = cp
+	}
+	return checkpoints, nil
+}
+
+// Delete removes all checkpoints of a run.
+func (s *MemoryCheckpointStore) Delete(ctx context.Context, runID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.runs, runID)
+	return nil
+}
+
+type FileCheckpointStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFileCheckpointStore creates a CheckpointStore that writes one JSON file per node
+// into <dir>/<runID>/, so checkpoints survive process restarts.
+//
+// Parameters:
+//   - dir: The base directory for checkpoint files. It is created if needed.
+//
+// Returns:
+//   - *FileCheckpointStore: The store.
+//   - error: An error if the directory cannot be created.
+func NewFileCheckpointStore(dir string) (*FileCheckpointStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+	return &FileCheckpointStore{dir: dir}, nil
+}
+
+// Save writes the checkpoint of a node atomically.
+func (s *FileCheckpointStore) Save(ctx context.Context, runID string, cp *Checkpoint) error {
+	runDir, err := s.runDir(runID)
+	if err != nil {
+		return err
+	}
+	if err := validPathElement(cp.NodeID); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(runDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create run directory: %w", err)
+	}
+	path := filepath.Join(runDir, cp.NodeID+".json")
+	if err := os.WriteFile(path+".tmp", data, 0o600); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// Load reads all checkpoints of a run. A run without checkpoints returns an empty map.
+func (s *FileCheckpointStore) Load(ctx context.Context, runID string) (map[string]*Checkpoint, error) {
+	runDir, err := s.runDir(runID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	checkpoints := make(map[string]*Checkpoint)
+	entries, err := os.ReadDir(runDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return checkpoints, nil
+		}
+		return nil, fmt.Errorf("failed to read checkpoints: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(runDir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+		}
+		var cp Checkpoint
+		if err := json.Unmarshal(data, &cp); err != nil {
+			return nil, fmt.Errorf("failed to decode checkpoint %s: %w", entry.Name(), err)
+		}
+		checkpoints[cp.NodeID] = &cp
+	}
+
+	return checkpoints, nil
+}
+
+// Delete removes all checkpoints of a run.
+func (s *FileCheckpointStore) Delete(ctx context.Context, runID string) error {
+	runDir, err := s.runDir(runID)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return os.RemoveAll(runDir)
+}
+
+// runDir returns the checkpoint directory of a run.
+func (s *FileCheckpointStore) runDir(runID string) (string, error) {
+	if err := validPathElement(runID); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.dir, runID), nil
+}
+
+// validPathElement rejects identifiers that cannot be used as a single file name.
+func validPathElement(id string) error {
+	if id == "" || id == "." || id == ".." || strings.ContainsAny(id, `/\`) {
+		return fmt.Errorf("workflow: invalid checkpoint id %q", id)
+	}
+	return nil
+}
@@ -0,0 +1,274 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/genc-murat/groq-client/pkg/groq"
+)
+
+type NodeKind string
+
+const (
+	KindChat      NodeKind = "chat"
+	KindTool      NodeKind = "tool"
+	KindTransform NodeKind = "transform"
+	KindBranch    NodeKind = "branch"
+)
+
+// Inputs holds the outputs of a node's dependencies, keyed by node ID.
+// Dependencies that were skipped by a branch are absent.
+type Inputs map[string]interface{}
+
+// String returns the output of the named dependency formatted as a string.
+func (in Inputs) String(id string) string {
+	v, ok := in[id]
+	if !ok || v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// ToolFunc executes a tool, typically a Go implementation of a function the model can call.
+type ToolFunc func(ctx context.Context, in Inputs) (interface{}, error)
+
+// TransformFunc converts dependency outputs into a new value without side effects.
+type TransformFunc func(in Inputs) (interface{}, error)
+
+// BranchFunc selects the label of the branch to follow.
+type BranchFunc func(in Inputs) (string, error)
+
+// ErrBudgetExceeded is returned by chat nodes whose request cannot fit in the node budget.
+var ErrBudgetExceeded = errors.New("workflow: node budget exceeded")
+
+// nodeOutput is what a node execution produced.
+type nodeOutput struct {
+	value interface{}
+	resp  *groq.ChatCompletionResponse
+	model groq.ModelType
+}
+
+// Budget limits what a single chat node may spend. Limits are enforced before the
+// request is sent, against the estimated prompt size plus the worst-case completion:
+// max_tokens is lowered to fit, and the node fails with ErrBudgetExceeded if the
+// prompt alone does not fit. Zero values mean unlimited.
+type Budget struct {
+	MaxTokens int     // Prompt plus completion tokens
+	MaxCost   float64 // USD, based on the model's published prices
+}
+
+type Node struct {
+	ID         string
+	Kind       NodeKind
+	DependsOn  []string
+	Retries    int           // Additional attempts after a failure
+	RetryDelay time.Duration // Base delay between attempts, multiplied by the attempt number
+
+	// Overrides for chat nodes, applied to the request returned by the build function.
+	Model       groq.ModelType   // Model to use instead of the request's model
+	Temperature *float64         // Temperature to use instead of the request's temperature
+	MaxTokens   int              // max_tokens to use instead of the request's value
+	CachePolicy groq.CachePolicy // How the client cache is used for this node
+	Budget      Budget           // Spending limits for this node
+
+	conditions map[string]string
+	exec       func(ctx context.Context, client *groq.Client, in Inputs) (nodeOutput, error)
+}
+
+// Chat creates a node that sends the request produced by build to the chat completions
+// endpoint. Its output is the text of the first choice. The model, temperature,
+// max_tokens, cache policy and budget can be overridden per node, so cheap steps can
+// run on small models while the synthesis step uses a large one.
+//
+// Parameters:
+//   - id: Unique node ID.
+//   - build: Builds the request from the dependency outputs.
+//   - deps: IDs of the nodes whose outputs are needed.
+//
+// Returns:
+//   - *Node: The new node.
+func Chat(id string, build func(in Inputs) (*groq.ChatCompletionRequest, error), deps ...string) *Node {
+	n := &Node{
+		ID:        id,
+		Kind:      KindChat,
+		DependsOn: deps,
+	}
+	n.exec = func(ctx context.Context, client *groq.Client, in Inputs) (nodeOutput, error) {
+		req, err := build(in)
+		if err != nil {
+			return nodeOutput{}, fmt.Errorf("build request: %w", err)
+		}
+
+		n.applyOverrides(req)
+		if err := n.applyBudget(req); err != nil {
+			return nodeOutput{}, err
+		}
+		if n.CachePolicy != groq.CacheDefault {
+			ctx = groq.ContextWithCachePolicy(ctx, n.CachePolicy)
+		}
+
+		resp, err := client.CreateChatCompletion(ctx, req)
+		if err != nil {
+			return nodeOutput{}, err
+		}
+
+		var text string
+		if len(resp.Choices) > 0 {
+			text = fmt.Sprintf("%v", resp.Choices[0].Message.Content)
+		}
+		return nodeOutput{value: text, resp: resp, model: req.Model}, nil
+	}
+	return n
+}
+
+// applyOverrides replaces the request parameters the node overrides.
+func (n *Node) applyOverrides(req *groq.ChatCompletionRequest) {
+	if n.Model != "" {
+		req.Model = n.Model
+	}
+	if n.Temperature != nil {
+		req.Temperature = groq.Float64(*n.Temperature)
+	}
+	if n.MaxTokens > 0 {
+		req.MaxTokens = n.MaxTokens
+	}
+}
+
+// applyBudget lowers max_tokens so the worst case of req fits in the node budget.
+func (n *Node) applyBudget(req *groq.ChatCompletionRequest) error {
+	if n.Budget.MaxTokens <= 0 && n.Budget.MaxCost <= 0 {
+		return nil
+	}
+
+	prompt := groq.EstimateMessageTokens(req.Messages)
+	limit := math.MaxInt
+
+	if n.Budget.MaxTokens > 0 {
+		limit = n.Budget.MaxTokens - prompt
+	}
+
+	if n.Budget.MaxCost > 0 {
+		promptCost := groq.EstimateChatCost(req.Model, groq.Usage{PromptTokens: prompt})
+		if promptCost > n.Budget.MaxCost {
+			return fmt.Errorf("%w: estimated prompt cost $%.6f exceeds $%.6f", ErrBudgetExceeded, promptCost, n.Budget.MaxCost)
+		}
+		if price := req.Model.GetInfo().OutputPricePerMillion; price > 0 {
+			limit = min(limit, int((n.Budget.MaxCost-promptCost)*1e6/price))
+		}
+	}
+
+	if limit <= 0 {
+		return fmt.Errorf("%w: estimated prompt of %d tokens leaves no room for a completion", ErrBudgetExceeded, prompt)
+	}
+	if limit == math.MaxInt {
+		return nil
+	}
+	if req.MaxTokens == 0 || req.MaxTokens > limit {
+		req.MaxTokens = limit
+	}
+	return nil
+}
+
+// Tool creates a node that runs fn, e.g. the implementation of a function call.
+// Its output is whatever fn returns.
+func Tool(id string, fn ToolFunc, deps ...string) *Node {
+	return &Node{
+		ID:        id,
+		Kind:      KindTool,
+		DependsOn: deps,
+		exec: func(ctx context.Context, _ *groq.Client, in Inputs) (nodeOutput, error) {
+			v, err := fn(ctx, in)
+			return nodeOutput{value: v}, err
+		},
+	}
+}
+
+// Transform creates a node that derives a value from its dependency outputs,
+// for example to parse JSON or join several answers into one prompt.
+func Transform(id string, fn TransformFunc, deps ...string) *Node {
+	return &Node{
+		ID:        id,
+		Kind:      KindTransform,
+		DependsOn: deps,
+		exec: func(_ context.Context, _ *groq.Client, in Inputs) (nodeOutput, error) {
+			v, err := fn(in)
+			return nodeOutput{value: v}, err
+		},
+	}
+}
+
+// Branch creates a node whose output is a branch label. Nodes attached with
+// When(branchID, label) only run if the branch selected that label.
+func Branch(id string, fn BranchFunc, deps ...string) *Node {
+	return &Node{
+		ID:        id,
+		Kind:      KindBranch,
+		DependsOn: deps,
+		exec: func(_ context.Context, _ *groq.Client, in Inputs) (nodeOutput, error) {
+			label, err := fn(in)
+			return nodeOutput{value: label}, err
+		},
+	}
+}
+
+// When makes the node conditional on a branch node selecting label. The branch node
+// is added to the dependencies automatically. If the branch picks another label, the
+// node is skipped.
+func (n *Node) When(branchID, label string) *Node {
+	if n.conditions == nil {
+		n.conditions = make(map[string]string)
+	}
+	n.conditions[branchID] = label
+
+	for _, dep := range n.DependsOn {
+		if dep == branchID {
+			return n
+		}
+	}
+	n.DependsOn = append(n.DependsOn, branchID)
+	return n
+}
+
+// WithModel makes a chat node use model, e.g. a small model for cheap extraction steps
+// and a large one for the final synthesis.
+func (n *Node) WithModel(model groq.ModelType) *Node {
+	n.Model = model
+	return n
+}
+
+// WithTemperature overrides the temperature of a chat node.
+func (n *Node) WithTemperature(temperature float64) *Node {
+	n.Temperature = &temperature
+	return n
+}
+
+// WithMaxTokens overrides max_tokens of a chat node.
+func (n *Node) WithMaxTokens(maxTokens int) *Node {
+	n.MaxTokens = maxTokens
+	return n
+}
+
+// WithCachePolicy sets how a chat node uses the client cache.
+func (n *Node) WithCachePolicy(policy groq.CachePolicy) *Node {
+	n.CachePolicy = policy
+	return n
+}
+
+// WithBudget limits the tokens and cost a chat node may spend.
+func (n *Node) WithBudget(budget Budget) *Node {
+	n.Budget = budget
+	return n
+}
+
+// WithRetries sets how many times the node is retried after a failure and the base delay.
+func (n *Node) WithRetries(retries int, delay time.Duration) *Node {
+	n.Retries = retries
+	n.RetryDelay = delay
+	return n
+}
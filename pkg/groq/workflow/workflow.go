@@ -0,0 +1,521 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/genc-murat/groq-client/pkg/groq"
+)
+
+type NodeStatus string
+
+const (
+	StatusPending   NodeStatus = "pending"
+	StatusSucceeded NodeStatus = "succeeded"
+	StatusFailed    NodeStatus = "failed"
+	StatusSkipped   NodeStatus = "skipped"
+	StatusCancelled NodeStatus = "cancelled"
+)
+
+type NodeTrace struct {
+	ID         string         `json:"id"`
+	Kind       NodeKind       `json:"kind"`
+	DependsOn  []string       `json:"depends_on,omitempty"`
+	Status     NodeStatus     `json:"status"`
+	Attempts   int            `json:"attempts"`
+	StartedAt  time.Time      `json:"started_at,omitempty"`
+	FinishedAt time.Time      `json:"finished_at,omitempty"`
+	Duration   string         `json:"duration,omitempty"`
+	Error      string         `json:"error,omitempty"`
+	Output     interface{}    `json:"output,omitempty"`
+	Model      groq.ModelType `json:"model,omitempty"`
+	Usage      *groq.Usage    `json:"usage,omitempty"`
+	Cost       float64        `json:"cost,omitempty"`
+	Restored   bool           `json:"restored,omitempty"`
+}
+
+type ModelUsage struct {
+	Calls int        `json:"calls"`
+	Usage groq.Usage `json:"usage"`
+	Cost  float64    `json:"cost"`
+}
+
+type Trace struct {
+	RunID      string                         `json:"run_id,omitempty"`
+	StartedAt  time.Time                      `json:"started_at"`
+	FinishedAt time.Time                      `json:"finished_at"`
+	Duration   string                         `json:"duration"`
+	Status     NodeStatus                     `json:"status"`
+	Error      string                         `json:"error,omitempty"`
+	Usage      groq.Usage                     `json:"usage"` // Including nodes restored from checkpoints
+	Cost       float64                        `json:"cost"`  // Including nodes restored from checkpoints
+	Models     map[groq.ModelType]*ModelUsage `json:"models,omitempty"`
+	Nodes      []*NodeTrace                   `json:"nodes"`
+}
+
+// record adds the usage of a chat node to the run totals.
+func (t *Trace) record(nt *NodeTrace) {
+	if nt.Usage == nil {
+		return
+	}
+	t.Usage.Add(*nt.Usage)
+	t.Cost += nt.Cost
+
+	if t.Models == nil {
+		t.Models = make(map[groq.ModelType]*ModelUsage)
+	}
+	mu, ok := t.Models[nt.Model]
+	if !ok {
+		mu = &ModelUsage{}
+		t.Models[nt.Model] = mu
+	}
+	mu.Calls++
+	mu.Usage.Add(*nt.Usage)
+	mu.Cost += nt.Cost
+}
+
+// JSON returns the indented JSON encoding of the trace, for logging or debugging.
+func (t *Trace) JSON() ([]byte, error) {
+	return json.MarshalIndent(t, "", "  ")
+}
+
+type Result struct {
+	Outputs map[string]interface{}
+	Trace   *Trace
+}
+
+type Workflow struct {
+	client         *groq.Client
+	nodes          map[string]*Node
+	order          []string
+	checkpoints    CheckpointStore
+	MaxConcurrency int
+}
+
+// New creates an empty workflow that runs its chat nodes with client.
+// Nodes form a directed acyclic graph through their dependencies; independent
+// nodes run concurrently, limited by MaxConcurrency (default 4).
+//
+// Parameters:
+//   - client: The client used by chat nodes.
+//
+// Returns:
+//   - *Workflow: A pointer to the new workflow.
+func New(client *groq.Client) *Workflow {
+	return &Workflow{
+		client:         client,
+		nodes:          make(map[string]*Node),
+		MaxConcurrency: 4,
+	}
+}
+
+// Add registers nodes with the workflow. Node IDs must be unique.
+func (w *Workflow) Add(nodes ...*Node) *Workflow {
+	for _, n := range nodes {
+		if _, exists := w.nodes[n.ID]; !exists {
+			w.order = append(w.order, n.ID)
+		}
+		w.nodes[n.ID] = n
+	}
+	return w
+}
+
+// WithCheckpoints enables checkpointing for runs started with RunWithID. The output of
+// every completed node is saved to store; when a run with the same ID is started again,
+// completed nodes are restored from their checkpoints instead of being executed.
+//
+// Restored outputs go through a JSON round trip: strings stay strings, while structured
+// tool outputs come back as the generic values produced by encoding/json. The usage and
+// cost of restored nodes count towards the totals of the trace, so a resumed run reports
+// what the whole run cost.
+func (w *Workflow) WithCheckpoints(store CheckpointStore) *Workflow {
+	w.checkpoints = store
+	return w
+}
+
+// Validate checks that every dependency exists, that branch conditions refer to branch
+// nodes and that the graph has no cycles.
+func (w *Workflow) Validate() error {
+	for _, id := range w.order {
+		n := w.nodes[id]
+		if n.ID == "" {
+			return fmt.Errorf("workflow: node with empty id")
+		}
+		for _, dep := range n.DependsOn {
+			if _, ok := w.nodes[dep]; !ok {
+				return fmt.Errorf("workflow: node %q depends on unknown node %q", id, dep)
+			}
+		}
+		for branchID := range n.conditions {
+			if b, ok := w.nodes[branchID]; !ok || b.Kind != KindBranch {
+				return fmt.Errorf("workflow: node %q is conditional on %q, which is not a branch node", id, branchID)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int, len(w.nodes))
+
+	var visit func(id string) error
+	visit = func(id string) error {
+		switch state[id] {
+		case visiting:
+			return fmt.Errorf("workflow: cycle detected at node %q", id)
+		case done:
+			return nil
+		}
+		state[id] = visiting
+		for _, dep := range w.nodes[id].DependsOn {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		state[id] = done
+		return nil
+	}
+
+	for _, id := range w.order {
+		if err := visit(id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// completion is sent by node goroutines when they finish.
+type completion struct {
+	id     string
+	output nodeOutput
+	err    error
+}
+
+// Run executes the workflow. Ready nodes start as soon as all their dependencies have
+// finished; a node that still fails after its retries cancels the run, and nodes that
+// never started are reported as cancelled in the trace.
+//
+// Parameters:
+//   - ctx: Context for the whole run, propagated to every node.
+//
+// Returns:
+//   - *Result: Node outputs and the run trace, also returned on failure.
+//   - error: A validation error, the first node error, or ctx.Err().
+func (w *Workflow) Run(ctx context.Context) (*Result, error) {
+	return w.RunWithID(ctx, "")
+}
+
+// RunWithID executes the workflow like Run, using runID to save and restore node
+// checkpoints when a CheckpointStore is configured with WithCheckpoints. Re-running a
+// failed or interrupted run with the same ID resumes after the last completed nodes.
+//
+// Parameters:
+//   - ctx: Context for the whole run, propagated to every node.
+//   - runID: Identifier of the run in the checkpoint store; empty disables checkpointing.
+//
+// Returns:
+//   - *Result: Node outputs and the run trace, also returned on failure.
+//   - error: A validation, checkpoint or node error, or ctx.Err().
+func (w *Workflow) RunWithID(ctx context.Context, runID string) (*Result, error) {
+	if err := w.Validate(); err != nil {
+		return nil, err
+	}
+
+	var checkpoints map[string]*Checkpoint
+	if w.checkpoints != nil && runID != "" {
+		loaded, err := w.checkpoints.Load(ctx, runID)
+		if err != nil {
+			return nil, fmt.Errorf("workflow: failed to load checkpoints: %w", err)
+		}
+		checkpoints = loaded
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	r := &runner{
+		workflow:   w,
+		outputs:    make(map[string]interface{}),
+		traces:     make(map[string]*NodeTrace, len(w.order)),
+		remaining:  make(map[string]int, len(w.order)),
+		dependents: make(map[string][]string),
+		done:       make(chan completion),
+	}
+
+	trace := &Trace{RunID: runID, StartedAt: time.Now(), Status: StatusSucceeded}
+	for _, id := range w.order {
+		n := w.nodes[id]
+		nt := &NodeTrace{ID: id, Kind: n.Kind, DependsOn: n.DependsOn, Status: StatusPending}
+		r.traces[id] = nt
+		trace.Nodes = append(trace.Nodes, nt)
+
+		r.remaining[id] = len(n.DependsOn)
+		for _, dep := range n.DependsOn {
+			r.dependents[dep] = append(r.dependents[dep], id)
+		}
+	}
+
+	concurrency := w.MaxConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+
+	var ready []string
+	for _, id := range w.order {
+		if r.remaining[id] == 0 {
+			ready = append(ready, id)
+		}
+	}
+
+	var (
+		running  int
+		firstErr error
+		wg       sync.WaitGroup
+	)
+
+	for {
+		for len(ready) > 0 && firstErr == nil {
+			id := ready[0]
+			ready = ready[1:]
+
+			if r.shouldSkip(id) {
+				r.traces[id].Status = StatusSkipped
+				ready = append(ready, r.finish(id)...)
+				continue
+			}
+
+			if cp, ok := checkpoints[id]; ok {
+				if err := r.restore(id, cp); err != nil {
+					firstErr = err
+					cancel()
+					break
+				}
+				trace.record(r.traces[id])
+				ready = append(ready, r.finish(id)...)
+				continue
+			}
+
+			in := r.inputs(id)
+			running++
+			wg.Add(1)
+			go func(id string, in Inputs) {
+				defer wg.Done()
+
+				select {
+				case sem <- struct{}{}:
+				case <-runCtx.Done():
+					r.done <- completion{id: id, err: runCtx.Err()}
+					return
+				}
+				defer func() { <-sem }()
+
+				output, err := r.execute(runCtx, w.nodes[id], in)
+				r.done <- completion{id: id, output: output, err: err}
+			}(id, in)
+		}
+
+		if running == 0 {
+			break
+		}
+
+		c := <-r.done
+		running--
+		nt := r.traces[c.id]
+
+		if c.err != nil {
+			if firstErr != nil && errors.Is(c.err, context.Canceled) {
+				nt.Status = StatusCancelled
+				continue
+			}
+			nt.Status = StatusFailed
+			nt.Error = c.err.Error()
+			if firstErr == nil {
+				firstErr = fmt.Errorf("workflow: node %q failed: %w", c.id, c.err)
+				cancel()
+			}
+			continue
+		}
+
+		nt.Status = StatusSucceeded
+		nt.Output = c.output.value
+		r.outputs[c.id] = c.output.value
+		if c.output.resp != nil {
+			usage := c.output.resp.Usage
+			nt.Model = c.output.model
+			nt.Usage = &usage
+			nt.Cost = groq.EstimateChatCost(nt.Model, usage)
+			trace.record(nt)
+		}
+
+		if w.checkpoints != nil && runID != "" {
+			if err := r.checkpoint(ctx, runID, nt); err != nil {
+				firstErr = err
+				cancel()
+				continue
+			}
+		}
+		ready = append(ready, r.finish(c.id)...)
+	}
+
+	wg.Wait()
+
+	if firstErr == nil && ctx.Err() != nil {
+		firstErr = ctx.Err()
+	}
+	if firstErr != nil {
+		trace.Status = StatusFailed
+		trace.Error = firstErr.Error()
+		for _, nt := range trace.Nodes {
+			if nt.Status == StatusPending {
+				nt.Status = StatusCancelled
+			}
+		}
+	}
+
+	trace.FinishedAt = time.Now()
+	trace.Duration = trace.FinishedAt.Sub(trace.StartedAt).String()
+
+	return &Result{Outputs: r.outputs, Trace: trace}, firstErr
+}
+
+// runner holds the mutable state of a single workflow run. It is only accessed
+// from the scheduling goroutine, except for execute which records attempts on
+// the node's own trace.
+type runner struct {
+	workflow   *Workflow
+	outputs    map[string]interface{}
+	traces     map[string]*NodeTrace
+	remaining  map[string]int
+	dependents map[string][]string
+	done       chan completion
+}
+
+// restore marks a node as completed using its checkpoint.
+func (r *runner) restore(id string, cp *Checkpoint) error {
+	var output interface{}
+	if len(cp.Output) > 0 {
+		if err := json.Unmarshal(cp.Output, &output); err != nil {
+			return fmt.Errorf("workflow: failed to restore checkpoint of node %q: %w", id, err)
+		}
+	}
+
+	nt := r.traces[id]
+	nt.Status = StatusSucceeded
+	nt.Restored = true
+	nt.Output = output
+	nt.Model = cp.Model
+	nt.Usage = cp.Usage
+	if cp.Usage != nil {
+		nt.Cost = groq.EstimateChatCost(cp.Model, *cp.Usage)
+	}
+	nt.FinishedAt = cp.CompletedAt
+	r.outputs[id] = output
+	return nil
+}
+
+// checkpoint saves the output of a completed node.
+func (r *runner) checkpoint(ctx context.Context, runID string, nt *NodeTrace) error {
+	output, err := json.Marshal(nt.Output)
+	if err != nil {
+		return fmt.Errorf("workflow: output of node %q cannot be checkpointed: %w", nt.ID, err)
+	}
+
+	err = r.workflow.checkpoints.Save(ctx, runID, &Checkpoint{
+		NodeID:      nt.ID,
+		Output:      output,
+		Model:       nt.Model,
+		Usage:       nt.Usage,
+		CompletedAt: nt.FinishedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("workflow: failed to checkpoint node %q: %w", nt.ID, err)
+	}
+	return nil
+}
+
+// finish marks id as settled and returns the dependents that became ready.
+func (r *runner) finish(id string) []string {
+	var ready []string
+	for _, dep := range r.dependents[id] {
+		r.remaining[dep]--
+		if r.remaining[dep] == 0 {
+			ready = append(ready, dep)
+		}
+	}
+	return ready
+}
+
+// shouldSkip reports whether a node must be skipped because a branch condition is
+// not met or because all of its dependencies were skipped.
+func (r *runner) shouldSkip(id string) bool {
+	n := r.workflow.nodes[id]
+
+	for branchID, label := range n.conditions {
+		if r.traces[branchID].Status != StatusSucceeded || r.outputs[branchID] != label {
+			return true
+		}
+	}
+
+	if len(n.DependsOn) == 0 {
+		return false
+	}
+	for _, dep := range n.DependsOn {
+		if r.traces[dep].Status != StatusSkipped {
+			return false
+		}
+	}
+	return true
+}
+
+// inputs collects the outputs of id's settled, non-skipped dependencies.
+func (r *runner) inputs(id string) Inputs {
+	in := make(Inputs)
+	for _, dep := range r.workflow.nodes[id].DependsOn {
+		if v, ok := r.outputs[dep]; ok {
+			in[dep] = v
+		}
+	}
+	return in
+}
+
+// execute runs a node with its retry policy and records timing on its trace.
+func (r *runner) execute(ctx context.Context, n *Node, in Inputs) (nodeOutput, error) {
+	nt := r.traces[n.ID]
+	nt.StartedAt = time.Now()
+	defer func() {
+		nt.FinishedAt = time.Now()
+		nt.Duration = nt.FinishedAt.Sub(nt.StartedAt).String()
+	}()
+
+	var lastErr error
+	for attempt := 0; attempt <= n.Retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nodeOutput{}, ctx.Err()
+			case <-time.After(n.RetryDelay * time.Duration(attempt)):
+			}
+		}
+
+		nt.Attempts++
+		output, err := n.exec(ctx, r.workflow.client, in)
+		if err == nil {
+			return output, nil
+		}
+		lastErr = err
+
+		if ctx.Err() != nil || errors.Is(err, ErrBudgetExceeded) {
+			break
+		}
+	}
+
+	return nodeOutput{}, lastErr
+}
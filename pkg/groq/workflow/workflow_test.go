@@ -0,0 +1,242 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/genc-murat/groq-client/pkg/groq"
+)
+
+func newTestClient(t *testing.T) *groq.Client {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req groq.ChatCompletionRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		prompt := req.Messages[len(req.Messages)-1].GetCacheKey()
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"model":   req.Model,
+			"usage":   map[string]int{"prompt_tokens": 3, "completion_tokens": 2, "total_tokens": 5},
+			"choices": []map[string]interface{}{{"message": map[string]string{"role": "assistant", "content": strings.ToUpper(prompt)}}},
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	return groq.NewClient("test-key", groq.WithBaseURL(srv.URL))
+}
+
+func TestWorkflowRun(t *testing.T) {
+	wf := New(newTestClient(t)).Add(
+		Transform("topic", func(Inputs) (interface{}, error) { return "go", nil }),
+		Chat("shout", func(in Inputs) (*groq.ChatCompletionRequest, error) {
+			return groq.NewRequest(groq.ModelLlama31_8bInstant).User(in.String("topic")).Build(), nil
+		}, "topic"),
+		Branch("route", func(in Inputs) (string, error) {
+			if in.String("shout") == "GO" {
+				return "loud", nil
+			}
+			return "quiet", nil
+		}, "shout"),
+		Transform("loud", func(in Inputs) (interface{}, error) { return in.String("shout") + "!", nil }, "shout").When("route", "loud"),
+		Transform("quiet", func(in Inputs) (interface{}, error) { return "...", nil }).When("route", "quiet"),
+		Transform("join", func(in Inputs) (interface{}, error) {
+			return in.String("loud") + in.String("quiet"), nil
+		}, "loud", "quiet"),
+	)
+
+	result, err := wf.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if got := result.Outputs["join"]; got != "GO!" {
+		t.Errorf("join output = %v, want GO!", got)
+	}
+	if result.Trace.Usage.TotalTokens != 5 {
+		t.Errorf("expected usage to be aggregated, got %+v", result.Trace.Usage)
+	}
+
+	statuses := map[string]NodeStatus{}
+	for _, n := range result.Trace.Nodes {
+		statuses[n.ID] = n.Status
+	}
+	if statuses["quiet"] != StatusSkipped || statuses["loud"] != StatusSucceeded {
+		t.Errorf("unexpected branch statuses: %v", statuses)
+	}
+
+	if _, err := result.Trace.JSON(); err != nil {
+		t.Errorf("trace is not serializable: %v", err)
+	}
+}
+
+func TestWorkflowRetriesAndFailure(t *testing.T) {
+	attempts := 0
+	boom := errors.New("boom")
+
+	wf := New(nil).Add(
+		Tool("flaky", func(context.Context, Inputs) (interface{}, error) {
+			attempts++
+			if attempts < 3 {
+				return nil, errors.New("transient")
+			}
+			return "ok", nil
+		}).WithRetries(2, time.Millisecond),
+		Tool("broken", func(context.Context, Inputs) (interface{}, error) { return nil, boom }, "flaky"),
+		Transform("after", func(Inputs) (interface{}, error) { return "unreachable", nil }, "broken"),
+	)
+
+	result, err := wf.Run(context.Background())
+	if !errors.Is(err, boom) {
+		t.Fatalf("Run() error = %v, want %v", err, boom)
+	}
+	if result.Outputs["flaky"] != "ok" || attempts != 3 {
+		t.Errorf("expected flaky node to succeed on the third attempt, attempts = %d", attempts)
+	}
+	if last := result.Trace.Nodes[2]; last.Status != StatusCancelled {
+		t.Errorf("expected downstream node to be cancelled, got %s", last.Status)
+	}
+}
+
+func TestWorkflowValidateCycle(t *testing.T) {
+	noop := func(Inputs) (interface{}, error) { return nil, nil }
+	wf := New(nil).Add(Transform("a", noop, "b"), Transform("b", noop, "a"))
+
+	if err := wf.Validate(); err == nil {
+		t.Error("expected a cycle to be rejected")
+	}
+}
+
+func TestWorkflowResumeFromCheckpoint(t *testing.T) {
+	store, err := NewFileCheckpointStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileCheckpointStore() error = %v", err)
+	}
+
+	var expensiveRuns int
+	fail := true
+
+	build := func() *Workflow {
+		return New(nil).WithCheckpoints(store).Add(
+			Tool("expensive", func(context.Context, Inputs) (interface{}, error) {
+				expensiveRuns++
+				return "summary", nil
+			}),
+			Tool("synthesis", func(_ context.Context, in Inputs) (interface{}, error) {
+				if fail {
+					return nil, errors.New("interrupted")
+				}
+				return in.String("expensive") + " done", nil
+			}, "expensive"),
+		)
+	}
+
+	if _, err := build().RunWithID(context.Background(), "run-1"); err == nil {
+		t.Fatal("expected the first run to fail")
+	}
+
+	fail = false
+	result, err := build().RunWithID(context.Background(), "run-1")
+	if err != nil {
+		t.Fatalf("RunWithID() error = %v", err)
+	}
+
+	if expensiveRuns != 1 {
+		t.Errorf("expected completed node to be restored, ran %d times", expensiveRuns)
+	}
+	if got := result.Outputs["synthesis"]; got != "summary done" {
+		t.Errorf("synthesis output = %v, want %q", got, "summary done")
+	}
+	if !result.Trace.Nodes[0].Restored {
+		t.Error("expected the restored node to be flagged in the trace")
+	}
+}
+
+func TestWorkflowResumedTraceTotals(t *testing.T) {
+	store, err := NewFileCheckpointStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileCheckpointStore() error = %v", err)
+	}
+	client := newTestClient(t)
+	fail := true
+
+	build := func() *Workflow {
+		return New(client).WithCheckpoints(store).Add(
+			Chat("draft", func(Inputs) (*groq.ChatCompletionRequest, error) {
+				return groq.NewRequest(groq.ModelLlama31_8bInstant).User("draft").Build(), nil
+			}),
+			Tool("publish", func(context.Context, Inputs) (interface{}, error) {
+				if fail {
+					return nil, errors.New("interrupted")
+				}
+				return "published", nil
+			}, "draft"),
+		)
+	}
+
+	if _, err := build().RunWithID(context.Background(), "run-1"); err == nil {
+		t.Fatal("expected the first run to fail")
+	}
+	fail = false
+	result, err := build().RunWithID(context.Background(), "run-1")
+	if err != nil {
+		t.Fatalf("RunWithID() error = %v", err)
+	}
+
+	trace := result.Trace
+	draft := trace.Nodes[0]
+	if !draft.Restored || draft.Usage == nil || draft.Cost <= 0 {
+		t.Fatalf("unexpected restored node: %+v", draft)
+	}
+	if trace.Usage.TotalTokens != draft.Usage.TotalTokens || trace.Cost != draft.Cost {
+		t.Errorf("run totals %+v, %v do not include the restored node %+v, %v", trace.Usage, trace.Cost, *draft.Usage, draft.Cost)
+	}
+	if got := trace.Models[groq.ModelLlama31_8bInstant]; got == nil || got.Calls != 1 {
+		t.Errorf("unexpected model totals: %+v", got)
+	}
+}
+
+func TestWorkflowNodeOverrides(t *testing.T) {
+	build := func(in Inputs) (*groq.ChatCompletionRequest, error) {
+		return groq.NewRequest(groq.ModelLlama31_8bInstant).User("hello").Build(), nil
+	}
+
+	wf := New(newTestClient(t)).Add(
+		Chat("extract", build),
+		Chat("summarize", build).WithModel(groq.ModelLlama31_8bInstant),
+		Chat("synthesize", build, "extract", "summarize").
+			WithModel(groq.ModelLlama33_70bVersatile).
+			WithTemperature(0.2).
+			WithBudget(Budget{MaxTokens: 100}),
+		Chat("starved", build, "synthesize").WithBudget(Budget{MaxTokens: 2}),
+	)
+
+	result, err := wf.Run(context.Background())
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("expected budget error, got %v", err)
+	}
+
+	trace := result.Trace
+	if got := trace.Models[groq.ModelLlama31_8bInstant]; got == nil || got.Calls != 2 || got.Usage.TotalTokens != 10 {
+		t.Errorf("unexpected small model totals: %+v", got)
+	}
+	if got := trace.Models[groq.ModelLlama33_70bVersatile]; got == nil || got.Calls != 1 {
+		t.Errorf("unexpected large model totals: %+v", got)
+	}
+	if trace.Usage.TotalTokens != 15 || trace.Cost <= 0 {
+		t.Errorf("unexpected run totals: usage %+v cost %v", trace.Usage, trace.Cost)
+	}
+
+	for _, nt := range trace.Nodes {
+		if nt.ID == "starved" && nt.Attempts != 1 {
+			t.Errorf("budget errors must not be retried, got %d attempts", nt.Attempts)
+		}
+	}
+}